@@ -0,0 +1,44 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package app
+
+import (
+	"io"
+	"os"
+
+	"decred.org/dcrdex/client/backup"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/dex"
+)
+
+// AddBackupSources adds the market making event log and bot config to the
+// Core's scheduled backups. The client database is always backed up.
+func AddBackupSources(c *core.Core, marketMaker *mm.MarketMaker, cfg *Config) {
+	if marketMaker != nil {
+		c.AddBackupSource(backup.MMEventLogFile, true, marketMaker.BackupEventLog)
+	}
+	botCfgPath := cfg.MMConfig.BotConfigPath
+	c.AddBackupSource(backup.MMConfigFile, false, func(w io.Writer) error {
+		f, err := os.Open(botCfgPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(w, f)
+		return err
+	})
+}
+
+// RestoreBackup verifies the backup and restores the files in it to the
+// locations specified by the Config. The app password is the password that
+// was in use when the backup was made. RestoreBackup must not be used while
+// the client is running.
+func RestoreBackup(cfg *Config, path string, appPW []byte) (*backup.Manifest, error) {
+	return backup.Restore(dex.CleanAndExpandPath(path), appPW, map[string]string{
+		backup.DBFile:         cfg.DBPath,
+		backup.MMEventLogFile: cfg.MMConfig.EventLogDBPath,
+		backup.MMConfigFile:   cfg.MMConfig.BotConfigPath,
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/mm"
@@ -22,17 +23,22 @@ import (
 )
 
 const (
-	defaultRPCCertFile = "rpc.cert"
-	defaultRPCKeyFile  = "rpc.key"
-	defaultMainnetHost = "127.0.0.1"
-	defaultTestnetHost = "127.0.0.2"
-	defaultSimnetHost  = "127.0.0.3"
-	walletPairOneHost  = "127.0.0.6"
-	walletPairTwoHost  = "127.0.0.7"
-	defaultRPCPort     = "5757"
-	defaultWebPort     = "5758"
-	defaultLogLevel    = "debug"
-	configFilename     = "dexc.conf"
+	defaultRPCCertFile   = "rpc.cert"
+	defaultRPCKeyFile    = "rpc.key"
+	defaultRPCTokensFile = "rpctokens.json"
+	defaultMainnetHost   = "127.0.0.1"
+	defaultTestnetHost   = "127.0.0.2"
+	defaultSimnetHost    = "127.0.0.3"
+	walletPairOneHost    = "127.0.0.6"
+	walletPairTwoHost    = "127.0.0.7"
+	defaultRPCPort       = "5757"
+	defaultWebPort       = "5758"
+	defaultLogLevel      = "debug"
+	configFilename       = "dexc.conf"
+
+	defaultBackupDirName  = "encrypted-backups"
+	defaultBackupInterval = 24 * time.Hour
+	defaultBackupKeep     = 7
 )
 
 var (
@@ -42,12 +48,13 @@ var (
 
 // RPCConfig encapsulates the configuration needed for the RPC server.
 type RPCConfig struct {
-	RPCAddr string `long:"rpcaddr" description:"RPC server listen address"`
-	RPCUser string `long:"rpcuser" description:"RPC server user name"`
-	RPCPass string `long:"rpcpass" description:"RPC server password"`
-	RPCCert string `long:"rpccert" description:"RPC server certificate file location"`
-	RPCKey  string `long:"rpckey" description:"RPC server key file location"`
-	Dev     bool   `long:"rpcdev" description:"Enable developer RPC endpoints."`
+	RPCAddr   string `long:"rpcaddr" description:"RPC server listen address"`
+	RPCUser   string `long:"rpcuser" description:"RPC server user name"`
+	RPCPass   string `long:"rpcpass" description:"RPC server password"`
+	RPCCert   string `long:"rpccert" description:"RPC server certificate file location"`
+	RPCKey    string `long:"rpckey" description:"RPC server key file location"`
+	RPCTokens string `long:"rpctokens" description:"RPC server API token file location"`
+	Dev       bool   `long:"rpcdev" description:"Enable developer RPC endpoints."`
 	// CertHosts is a list of hosts given to certgen.NewTLSCertPair for the
 	// "Subject Alternate Name" values of the generated TLS certificate. It is
 	// set automatically, not via the config file or cli args.
@@ -85,6 +92,7 @@ func (cfg *RPCConfig) RPC(c *core.Core, marketMaker *mm.MarketMaker, log dex.Log
 		Pass:        cfg.RPCPass,
 		Cert:        cfg.RPCCert,
 		Key:         cfg.RPCKey,
+		TokensPath:  cfg.RPCTokens,
 		BWVersion:   bwVersion,
 		Dev:         cfg.Dev,
 		CertHosts: []string{
@@ -114,6 +122,15 @@ type CoreConfig struct {
 	Mesh bool `long:"mesh" description:"Enable Tatanka Mesh for peer-to-peer trading. This is experimental and not recommended for production use."`
 
 	MaxActiveMatches int `long:"max-active-matches" description:"Maximum number of active swap matches per DEX connection before deferring new orders. Default 48."`
+
+	EVMChainsFile string `long:"evmchains" description:"Path to a JSON file with definitions of additional EVM-compatible chains."`
+
+	BackupDir      string        `long:"backupdir" description:"Directory for scheduled backups of the database, market making event log and bot config. Backups are encrypted with the app password, and are only written while logged in. Default is the encrypted-backups folder in the network directory."`
+	BackupInterval time.Duration `long:"backupinterval" description:"Time between scheduled backups, e.g. 6h. 0 disables scheduled backups."`
+	BackupKeep     int           `long:"backupkeep" description:"Number of scheduled backups to keep. 0 keeps all."`
+	BackupMaxAge   time.Duration `long:"backupmaxage" description:"Delete scheduled backups older than this, e.g. 720h. The newest backup is always kept. 0 disables deletion by age."`
+
+	RefundKitPath string `long:"refundkit" description:"Path of the emergency refund kit, which lists active swaps with signed refund transactions so funds can be recovered with bwrefund if the database is lost. Keep it on a separate disk. Default is refundkit.bwrk in the network directory."`
 }
 
 // WebConfig encapsulates the configuration needed for the web server.
@@ -164,6 +181,7 @@ type Config struct {
 	CPUProfile string `long:"cpuprofile" description:"File for CPU profiling."`
 	ShowVer    bool   `short:"V" long:"version" description:"Display version information and exit"`
 	Language   string `long:"lang" description:"BCP 47 tag for preferred language, e.g. en-GB, fr, zh-CN"`
+	Restore    string `long:"restore" description:"Verify and restore the database, market making event log and bot config from an encrypted backup file before starting. Replaced files are kept with a .pre-restore-<time> suffix. Prompts for the app password that was in use when the backup was made. Restoring is only done at startup, since the files can't be replaced while the client is running, and is not available from bwctl."`
 }
 
 // Web creates a configuration for the webserver. This is a Config method
@@ -229,6 +247,11 @@ func (cfg *Config) Core(log dex.Logger) *core.Config {
 		TheOneHost:         cfg.TheOneHost,
 		Mesh:               cfg.Mesh,
 		MaxActiveMatches:   cfg.MaxActiveMatches,
+		BackupDir:          cfg.BackupDir,
+		BackupInterval:     cfg.BackupInterval,
+		BackupKeep:         cfg.BackupKeep,
+		BackupMaxAge:       cfg.BackupMaxAge,
+		RefundKitPath:      cfg.RefundKitPath,
 	}
 }
 
@@ -236,6 +259,10 @@ var DefaultConfig = Config{
 	AppData:    defaultApplicationDirectory,
 	ConfigPath: defaultConfigPath,
 	LogConfig:  LogConfig{DebugLevel: defaultLogLevel},
+	CoreConfig: CoreConfig{
+		BackupInterval: defaultBackupInterval,
+		BackupKeep:     defaultBackupKeep,
+	},
 	RPCConfig: RPCConfig{
 		CertHosts: []string{defaultTestnetHost, defaultSimnetHost, defaultMainnetHost},
 	},
@@ -346,6 +373,10 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.RPCKey = filepath.Join(appData, defaultRPCKeyFile)
 	}
 
+	if cfg.RPCTokens == "" {
+		cfg.RPCTokens = filepath.Join(appData, defaultRPCTokensFile)
+	}
+
 	if cfg.DBPath == "" {
 		cfg.DBPath = defaultDBPath
 	}
@@ -354,6 +385,16 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.LogPath = defaultLogPath
 	}
 
+	if cfg.BackupDir == "" {
+		cfg.BackupDir = filepath.Join(filepath.Dir(cfg.DBPath), defaultBackupDirName)
+	} else {
+		cfg.BackupDir = dex.CleanAndExpandPath(cfg.BackupDir)
+	}
+
+	if cfg.RefundKitPath != "" {
+		cfg.RefundKitPath = dex.CleanAndExpandPath(cfg.RefundKitPath)
+	}
+
 	if cfg.MMConfig.BotConfigPath == "" {
 		cfg.MMConfig.BotConfigPath = defaultMMConfigPath
 	}
@@ -362,6 +403,10 @@ func ResolveConfig(appData string, cfg *Config) error {
 		cfg.MMConfig.EventLogDBPath = defaultMMEventLogDBPath
 	}
 
+	if cfg.EVMChainsFile != "" {
+		cfg.EVMChainsFile = dex.CleanAndExpandPath(cfg.EVMChainsFile)
+	}
+
 	return nil
 }
 
@@ -10,7 +10,8 @@
 package app
 
 import (
-	_ "decred.org/dcrdex/client/asset/eth"     // register eth asset
+	_ "decred.org/dcrdex/client/asset/eth" // register eth asset
+	"decred.org/dcrdex/client/asset/evm"
 	_ "decred.org/dcrdex/client/asset/polygon" // register polygon network
 	dexeth "decred.org/dcrdex/dex/networks/eth"
 	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
@@ -21,3 +22,14 @@ func init() {
 	dexpolygon.MaybeReadSimnetAddrs()
 
 }
+
+// RegisterEVMChains registers the EVM-compatible chains that are defined in
+// the file at path. RegisterEVMChains must be called before the network is set
+// with asset.SetNetwork.
+func RegisterEVMChains(path string) error {
+	if path == "" {
+		return nil
+	}
+	_, err := evm.RegisterChainsFile(path)
+	return err
+}
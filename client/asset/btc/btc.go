@@ -942,6 +942,8 @@ var _ asset.LogFiler = (*ExchangeWalletSPV)(nil)
 var _ asset.Recoverer = (*ExchangeWalletSPV)(nil)
 var _ asset.PeerManager = (*ExchangeWalletSPV)(nil)
 var _ asset.TxFeeEstimator = (*intermediaryWallet)(nil)
+var _ asset.BatchSender = (*intermediaryWallet)(nil)
+var _ asset.CoinController = (*baseWallet)(nil)
 var _ asset.Bonder = (*baseWallet)(nil)
 var _ asset.Authenticator = (*ExchangeWalletSPV)(nil)
 var _ asset.Authenticator = (*ExchangeWalletFullNode)(nil)
@@ -5745,18 +5747,9 @@ func (btc *baseWallet) ValidateSecret(secret, secretHash []byte) bool {
 // the fees will be subtracted from the value. If false, the fees are in
 // addition to the value. feeRate is in units of sats/byte.
 func (btc *baseWallet) send(address string, val uint64, feeRate uint64, subtract bool) (*chainhash.Hash, uint32, uint64, error) {
-	addr, err := btc.decodeAddr(address, btc.chainParams)
+	pay2script, err := btc.paymentScript(address)
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("invalid address: %s", address)
-	}
-	var pay2script []byte
-	if scripter, is := addr.(PaymentScripter); is {
-		pay2script, err = scripter.PaymentScript()
-	} else {
-		pay2script, err = txscript.PayToAddrScript(addr)
-	}
-	if err != nil {
-		return nil, 0, 0, fmt.Errorf("PayToAddrScript error: %w", err)
+		return nil, 0, 0, err
 	}
 
 	baseSize := dexbtc.MinimumTxOverhead
@@ -5826,6 +5819,309 @@ func (btc *baseWallet) send(address string, val uint64, feeRate uint64, subtract
 	return txHash, 0, toSend, nil
 }
 
+// paymentScript decodes the address and returns the script that pays it.
+func (btc *baseWallet) paymentScript(address string) ([]byte, error) {
+	addr, err := btc.decodeAddr(address, btc.chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %s", address)
+	}
+	var pay2script []byte
+	if scripter, is := addr.(PaymentScripter); is {
+		pay2script, err = scripter.PaymentScript()
+	} else {
+		pay2script, err = txscript.PayToAddrScript(addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("PayToAddrScript error: %w", err)
+	}
+	return pay2script, nil
+}
+
+// batchOutputs generates the outputs paying the batch recipients.
+func (btc *baseWallet) batchOutputs(recipients []*asset.BatchRecipient, feeRate uint64) ([]*wire.TxOut, uint64, error) {
+	if len(recipients) == 0 {
+		return nil, 0, errors.New("no recipients")
+	}
+	txOuts := make([]*wire.TxOut, 0, len(recipients))
+	var total uint64
+	for i, r := range recipients {
+		if r.Value == 0 {
+			return nil, 0, fmt.Errorf("recipient %d (%s): cannot send zero amount", i, r.Address)
+		}
+		pkScript, err := btc.paymentScript(r.Address)
+		if err != nil {
+			return nil, 0, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		txOut := wire.NewTxOut(int64(r.Value), pkScript)
+		if btc.IsDust(txOut, feeRate) {
+			return nil, 0, fmt.Errorf("recipient %d (%s): output value is dust", i, r.Address)
+		}
+		txOuts = append(txOuts, txOut)
+		total += r.Value
+	}
+	return txOuts, total, nil
+}
+
+// sendBatch pays all recipients in a single transaction with the given fee
+// rate. Fees are in addition to the sent values. feeRate is in units of
+// sats/byte.
+func (btc *baseWallet) sendBatch(recipients []*asset.BatchRecipient, feeRate uint64) (*chainhash.Hash, error) {
+	txOuts, totalSend, err := btc.batchOutputs(recipients, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	baseSize := uint64(dexbtc.MinimumTxOverhead)
+	for _, txOut := range txOuts {
+		baseSize += uint64(txOut.SerializeSize())
+	}
+	if btc.segwit {
+		baseSize += dexbtc.P2WPKHOutputSize // change
+	} else {
+		baseSize += dexbtc.P2PKHOutputSize
+	}
+
+	enough := SendEnough(totalSend, feeRate, false, baseSize, btc.segwit, true)
+	coins, _, _, _, _, _, err := btc.cm.Fund(btc.bondReserves.Load(), 0, false, enough)
+	if err != nil {
+		return nil, fmt.Errorf("error funding transaction: %w", err)
+	}
+
+	fundedTx, totalIn, _, err := btc.fundedTx(coins)
+	if err != nil {
+		return nil, fmt.Errorf("error adding inputs to transaction: %w", err)
+	}
+	for _, txOut := range txOuts {
+		fundedTx.AddTxOut(txOut)
+	}
+
+	changeAddr, err := btc.node.ChangeAddress(btc.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error creating change address: %w", err)
+	}
+
+	msgTx, err := btc.sendWithReturn(fundedTx, changeAddr, totalIn, totalSend, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	txHash := btc.hashTx(msgTx)
+
+	var totalOut uint64
+	for _, txOut := range msgTx.TxOut {
+		totalOut += uint64(txOut.Value)
+	}
+
+	btc.addTxToHistory(&asset.WalletTransaction{
+		Type:            asset.Send,
+		ID:              txHash.String(),
+		Amount:          totalSend,
+		Fees:            totalIn - totalOut,
+		BatchRecipients: recipients,
+		Timestamp:       uint64(time.Now().Unix()),
+		Confirms:        &asset.Confirms{Target: confTxFinality},
+	}, txHash, true)
+
+	return txHash, nil
+}
+
+// SpendableCoins lists the wallet's unspent outputs, including outputs locked
+// by active orders or bonds. SpendableCoins satisfies asset.CoinController.
+func (btc *baseWallet) SpendableCoins() ([]*asset.SpendableCoin, error) {
+	unspents, err := btc.node.ListUnspent()
+	if err != nil {
+		return nil, err
+	}
+	coins := make([]*asset.SpendableCoin, 0, len(unspents))
+	for _, u := range unspents {
+		if !u.Spendable {
+			continue
+		}
+		txHash, err := chainhash.NewHashFromStr(u.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding txid in ListUnspentResult: %w", err)
+		}
+		if btc.cm.LockedOutput(NewOutPoint(txHash, u.Vout)) != nil {
+			continue // added below
+		}
+		coins = append(coins, &asset.SpendableCoin{
+			ID:            ToCoinID(txHash, u.Vout),
+			TxID:          u.TxID,
+			Vout:          u.Vout,
+			Address:       u.Address,
+			Value:         toSatoshi(u.Amount),
+			Confirmations: u.Confirmations,
+			Label:         u.Label,
+		})
+	}
+	for _, utxo := range btc.cm.LockedOutputs() {
+		coins = append(coins, &asset.SpendableCoin{
+			ID:      ToCoinID(utxo.TxHash, utxo.Vout),
+			TxID:    utxo.TxHash.String(),
+			Vout:    utxo.Vout,
+			Address: utxo.Address,
+			Value:   utxo.Amount,
+			Locked:  true,
+		})
+	}
+	return coins, nil
+}
+
+// SendWithCoins sends using exactly the specified coins. feeRate is in units
+// of sats/byte. SendWithCoins satisfies asset.CoinController.
+func (btc *baseWallet) SendWithCoins(send *asset.CoinControlSend, feeRate uint64) (asset.Coin, error) {
+	txHash, sent, err := btc.sendWithCoins(send, btc.feeRateWithFallback(feeRate))
+	if err != nil {
+		return nil, err
+	}
+	return NewOutput(txHash, 0, sent), nil
+}
+
+// sendWithCoins spends the specified coins, paying the recipient at output
+// index 0. If send.Value is zero, the full value of the coins less fees is sent
+// and there is no change. feeRate is in units of sats/byte.
+func (btc *baseWallet) sendWithCoins(send *asset.CoinControlSend, feeRate uint64) (*chainhash.Hash, uint64, error) {
+	if len(send.Coins) == 0 {
+		return nil, 0, errors.New("no coins specified")
+	}
+	if send.Value == 0 && send.ChangeAddress != "" {
+		return nil, 0, errors.New("change address specified for a send without change")
+	}
+	pay2script, err := btc.paymentScript(send.Address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	_, utxoMap, spendable, err := btc.cm.SpendableUTXOs(0)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error listing unspent outputs: %w", err)
+	}
+	coins := make(asset.Coins, 0, len(send.Coins))
+	ops := make([]*Output, 0, len(send.Coins))
+	seen := make(map[OutPoint]bool, len(send.Coins))
+	var inputsSize uint64
+	for _, coinID := range send.Coins {
+		txHash, vout, err := decodeCoinID(coinID)
+		if err != nil {
+			return nil, 0, err
+		}
+		pt := NewOutPoint(txHash, vout)
+		if seen[pt] {
+			return nil, 0, fmt.Errorf("duplicate coin %s", pt)
+		}
+		seen[pt] = true
+		if btc.cm.LockedOutput(pt) != nil {
+			return nil, 0, fmt.Errorf("coin %s is locked by an active order or bond", pt)
+		}
+		utxo := utxoMap[pt]
+		if utxo == nil {
+			return nil, 0, fmt.Errorf("coin %s not found or not spendable", pt)
+		}
+		op := NewOutput(txHash, vout, utxo.Amount)
+		coins = append(coins, op)
+		ops = append(ops, op)
+		inputsSize += uint64(utxo.Input.VBytes())
+	}
+
+	// Lock the coins with the wallet until the transaction is broadcast, so
+	// that they can't be selected to fund an order in the meantime.
+	if err := btc.cm.lockUnspent(false, ops); err != nil {
+		return nil, 0, fmt.Errorf("error locking coins: %w", err)
+	}
+	var success bool
+	defer func() {
+		if !success {
+			if err := btc.node.LockUnspent(true, ops); err != nil {
+				btc.log.Errorf("Error unlocking coins after failed send: %v", err)
+			}
+		}
+	}()
+
+	fundedTx, totalIn, _, err := btc.fundedTx(coins)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error adding inputs to transaction: %w", err)
+	}
+
+	var msgTx *wire.MsgTx
+	var toSend, kept uint64
+	txOut := wire.NewTxOut(int64(send.Value), pay2script)
+	if send.Value == 0 {
+		fees := feeRate * (inputsSize + uint64(dexbtc.MinimumTxOverhead+txOut.SerializeSize()))
+		if fees >= totalIn {
+			return nil, 0, fmt.Errorf("coins worth %.8f cannot cover fees of %.8f", toBTC(totalIn), toBTC(fees))
+		}
+		toSend = totalIn - fees
+		txOut.Value = int64(toSend)
+		if btc.IsDust(txOut, feeRate) {
+			return nil, 0, errors.New("output value is dust")
+		}
+		fundedTx.AddTxOut(txOut)
+		if msgTx, err = btc.node.SignTx(btc.ctx, fundedTx); err != nil {
+			return nil, 0, fmt.Errorf("signing error: %w", err)
+		}
+	} else {
+		toSend = send.Value
+		if btc.IsDust(txOut, feeRate) {
+			return nil, 0, errors.New("output value is dust")
+		}
+		fundedTx.AddTxOut(txOut)
+		var changeAddr btcutil.Address
+		if send.ChangeAddress != "" {
+			if changeAddr, err = btc.decodeAddr(send.ChangeAddress, btc.chainParams); err != nil {
+				return nil, 0, fmt.Errorf("invalid change address: %s", send.ChangeAddress)
+			}
+		} else if changeAddr, err = btc.node.ChangeAddress(btc.ctx); err != nil {
+			return nil, 0, fmt.Errorf("error creating change address: %w", err)
+		}
+		var change *Output
+		if msgTx, change, _, err = btc.signTxAndAddChange(btc.ctx, fundedTx, changeAddr, totalIn, toSend, feeRate); err != nil {
+			return nil, 0, err
+		}
+		// Change to a specified address is not counted toward the bond
+		// reserves, even if it belongs to this wallet.
+		if change != nil && send.ChangeAddress == "" {
+			kept = change.Val
+		}
+	}
+
+	if reserves := btc.bondReserves.Load(); reserves > 0 && spendable-totalIn+kept < reserves {
+		return nil, 0, fmt.Errorf("send would leave %.8f, which is less than the %.8f bond reserves",
+			toBTC(spendable-totalIn+kept), toBTC(reserves))
+	}
+
+	txHash, err := btc.broadcastTx(btc.ctx, msgTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	success = true
+
+	var totalOut uint64
+	for _, txOut := range msgTx.TxOut {
+		totalOut += uint64(txOut.Value)
+	}
+
+	selfSend, err := btc.OwnsDepositAddress(send.Address)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error checking address ownership: %w", err)
+	}
+	txType := asset.Send
+	if selfSend {
+		txType = asset.SelfSend
+	}
+
+	btc.addTxToHistory(&asset.WalletTransaction{
+		Type:      txType,
+		ID:        txHash.String(),
+		Amount:    toSend,
+		Fees:      totalIn - totalOut,
+		Recipient: &send.Address,
+		Timestamp: uint64(time.Now().Unix()),
+		Confirms:  &asset.Confirms{Target: confTxFinality},
+	}, txHash, true)
+
+	return txHash, toSend, nil
+}
+
 // SwapConfirmations gets the number of confirmations for the specified swap
 // by first checking for a unspent output, and if not found, searching indexed
 // wallet transactions.
@@ -6277,6 +6573,32 @@ func (btc *intermediaryWallet) EstimateSendTxFee(address string, sendAmount, fee
 	return fee, isValidAddress, nil
 }
 
+// SendBatch pays all of the recipients in a single transaction. feeRate is in
+// units of sats/byte. SendBatch satisfies asset.BatchSender.
+func (btc *intermediaryWallet) SendBatch(recipients []*asset.BatchRecipient, feeRate uint64) ([]string, error) {
+	txHash, err := btc.sendBatch(recipients, btc.feeRateWithFallback(feeRate))
+	if err != nil {
+		return nil, err
+	}
+	return []string{txHash.String()}, nil
+}
+
+// EstimateBatchSendTxFee returns a tx fee estimate for paying all of the
+// recipients in a single transaction using the provided feeRate.
+// EstimateBatchSendTxFee satisfies asset.BatchSender.
+func (btc *intermediaryWallet) EstimateBatchSendTxFee(recipients []*asset.BatchRecipient, feeRate uint64) (uint64, error) {
+	feeRate = btc.feeRateWithFallback(feeRate)
+	txOuts, _, err := btc.batchOutputs(recipients, feeRate)
+	if err != nil {
+		return 0, err
+	}
+	tx := wire.NewMsgTx(btc.txVersion())
+	for _, txOut := range txOuts {
+		tx.AddTxOut(txOut)
+	}
+	return btc.txFeeEstimator.EstimateSendTxFee(tx, feeRate, false)
+}
+
 // StandardSendFee returns the fees for a simple send tx with one input and two
 // outputs.
 func (btc *baseWallet) StandardSendFee(feeRate uint64) uint64 {
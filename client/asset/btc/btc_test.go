@@ -4243,6 +4243,201 @@ func TestSend(t *testing.T) {
 	})
 }
 
+func TestSendBatch(t *testing.T) {
+	runRubric(t, testSendBatch)
+}
+
+func testSendBatch(t *testing.T, segwit bool, walletType string) {
+	wallet, node, shutdown := tNewWallet(segwit, walletType)
+	defer shutdown()
+
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, wallet.segwit)
+	}
+
+	addr := btcAddr(segwit)
+	node.changeAddr = btcAddr(segwit).String()
+	pkScript, _ := txscript.PayToAddrScript(addr)
+	tx := makeRawTx([]dex.Bytes{randBytes(5), pkScript}, []*wire.TxIn{dummyInput()})
+	txHash := tx.TxHash()
+	node.listUnspent = []*ListUnspentResult{{
+		TxID:          txHash.String(),
+		Address:       addr.String(),
+		Amount:        100,
+		Confirmations: 1,
+		ScriptPubKey:  pkScript,
+		SafePtr:       boolPtr(true),
+		Spendable:     true,
+	}}
+
+	recipients := []*asset.BatchRecipient{
+		{Address: btcAddr(segwit).String(), Value: toSatoshi(1)},
+		{Address: btcAddr(segwit).String(), Value: toSatoshi(2)},
+		{Address: btcAddr(segwit).String(), Value: toSatoshi(3)},
+	}
+
+	txIDs, err := wallet.SendBatch(recipients, defaultFee)
+	if err != nil {
+		t.Fatalf("SendBatch error: %v", err)
+	}
+	if len(txIDs) != 1 {
+		t.Fatalf("expected 1 tx ID, got %d", len(txIDs))
+	}
+
+	sentTx := node.sentRawTx
+	if len(sentTx.TxIn) != 1 {
+		t.Fatalf("expected 1 input, got %d", len(sentTx.TxIn))
+	}
+	if len(sentTx.TxOut) != len(recipients)+1 {
+		t.Fatalf("expected %d outputs, got %d", len(recipients)+1, len(sentTx.TxOut))
+	}
+	var totalOut uint64
+	for i, r := range recipients {
+		if sentTx.TxOut[i].Value != int64(r.Value) {
+			t.Fatalf("wrong value for output %d. wanted %d, got %d", i, r.Value, sentTx.TxOut[i].Value)
+		}
+		totalOut += r.Value
+	}
+	totalOut += uint64(sentTx.TxOut[len(recipients)].Value)
+	if fees := toSatoshi(100) - totalOut; fees == 0 || fees > toSatoshi(0.001) {
+		t.Fatalf("unexpected fees %d", fees)
+	}
+
+	if _, err := wallet.SendBatch(nil, defaultFee); err == nil {
+		t.Fatalf("no error for no recipients")
+	}
+	dust := []*asset.BatchRecipient{{Address: btcAddr(segwit).String(), Value: 1}}
+	if _, err := wallet.SendBatch(dust, defaultFee); err == nil {
+		t.Fatalf("no error for dust output")
+	}
+	badAddr := []*asset.BatchRecipient{{Address: "abc", Value: toSatoshi(1)}}
+	if _, err := wallet.SendBatch(badAddr, defaultFee); err == nil {
+		t.Fatalf("no error for invalid address")
+	}
+	tooMuch := []*asset.BatchRecipient{
+		{Address: btcAddr(segwit).String(), Value: toSatoshi(50)},
+		{Address: btcAddr(segwit).String(), Value: toSatoshi(50)},
+	}
+	if _, err := wallet.SendBatch(tooMuch, defaultFee); err == nil {
+		t.Fatalf("no error for insufficient funds")
+	}
+}
+
+func TestSendWithCoins(t *testing.T) {
+	runRubric(t, testSendWithCoins)
+}
+
+func testSendWithCoins(t *testing.T, segwit bool, walletType string) {
+	wallet, node, shutdown := tNewWallet(segwit, walletType)
+	defer shutdown()
+
+	node.signFunc = func(tx *wire.MsgTx) {
+		signFunc(tx, 0, wallet.segwit)
+	}
+
+	addr := btcAddr(segwit)
+	node.changeAddr = btcAddr(segwit).String()
+	pkScript, _ := txscript.PayToAddrScript(addr)
+	tx := makeRawTx([]dex.Bytes{pkScript, pkScript, pkScript}, []*wire.TxIn{dummyInput()})
+	txHash := tx.TxHash()
+	unspent := func(vout uint32, amt float64) *ListUnspentResult {
+		return &ListUnspentResult{
+			TxID:          txHash.String(),
+			Vout:          vout,
+			Address:       addr.String(),
+			Label:         "savings",
+			Amount:        amt,
+			Confirmations: 1,
+			ScriptPubKey:  pkScript,
+			SafePtr:       boolPtr(true),
+			Spendable:     true,
+		}
+	}
+	node.listUnspent = []*ListUnspentResult{unspent(0, 1), unspent(1, 2)}
+	lockedPt := NewOutPoint(&txHash, 2)
+	wallet.cm.lockedOutputs[lockedPt] = &UTxO{TxHash: &txHash, Vout: 2, Address: addr.String(), Amount: toSatoshi(3)}
+
+	coins, err := wallet.SpendableCoins()
+	if err != nil {
+		t.Fatalf("SpendableCoins error: %v", err)
+	}
+	if len(coins) != 3 {
+		t.Fatalf("expected 3 coins, got %d", len(coins))
+	}
+	for _, c := range coins {
+		if c.Locked != (c.Vout == 2) {
+			t.Fatalf("wrong locked status for coin %d", c.Vout)
+		}
+		if !c.Locked && c.Label != "savings" {
+			t.Fatalf("label not set")
+		}
+	}
+
+	coinIDs := []dex.Bytes{ToCoinID(&txHash, 0), ToCoinID(&txHash, 1)}
+	recipient := btcAddr(segwit).String()
+
+	// Exact value with change. The coins are locked until the send is
+	// broadcast.
+	node.lockedCoins = nil
+	coin, err := wallet.SendWithCoins(&asset.CoinControlSend{
+		Coins:   coinIDs,
+		Address: recipient,
+		Value:   toSatoshi(1.5),
+	}, defaultFee)
+	if err != nil {
+		t.Fatalf("SendWithCoins error: %v", err)
+	}
+	if coin.Value() != toSatoshi(1.5) {
+		t.Fatalf("wrong sent value %d", coin.Value())
+	}
+	sentTx := node.sentRawTx
+	if len(sentTx.TxIn) != 2 || len(sentTx.TxOut) != 2 {
+		t.Fatalf("expected 2 inputs and 2 outputs, got %d and %d", len(sentTx.TxIn), len(sentTx.TxOut))
+	}
+	if len(node.lockedCoins) != 2 {
+		t.Fatalf("expected 2 coins locked for the send, got %d", len(node.lockedCoins))
+	}
+
+	// No change.
+	coin, err = wallet.SendWithCoins(&asset.CoinControlSend{
+		Coins:   coinIDs[:1],
+		Address: recipient,
+	}, defaultFee)
+	if err != nil {
+		t.Fatalf("SendWithCoins (no change) error: %v", err)
+	}
+	sentTx = node.sentRawTx
+	if len(sentTx.TxIn) != 1 || len(sentTx.TxOut) != 1 {
+		t.Fatalf("expected 1 input and 1 output, got %d and %d", len(sentTx.TxIn), len(sentTx.TxOut))
+	}
+	if fees := toSatoshi(1) - coin.Value(); fees == 0 || fees > toSatoshi(0.001) {
+		t.Fatalf("unexpected fees %d", fees)
+	}
+
+	for name, send := range map[string]*asset.CoinControlSend{
+		"no coins":                      {Address: recipient, Value: toSatoshi(1)},
+		"locked coin":                   {Coins: []dex.Bytes{ToCoinID(&txHash, 2)}, Address: recipient},
+		"unknown coin":                  {Coins: []dex.Bytes{ToCoinID(&txHash, 5)}, Address: recipient},
+		"duplicate coin":                {Coins: []dex.Bytes{coinIDs[0], coinIDs[0]}, Address: recipient},
+		"insufficient":                  {Coins: coinIDs[:1], Address: recipient, Value: toSatoshi(2)},
+		"bad address":                   {Coins: coinIDs, Address: "abc"},
+		"bad change":                    {Coins: coinIDs, Address: recipient, Value: toSatoshi(1), ChangeAddress: "abc"},
+		"change address without change": {Coins: coinIDs, Address: recipient, ChangeAddress: recipient},
+	} {
+		if _, err := wallet.SendWithCoins(send, defaultFee); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	// Only the RPC wallet can fail to lock coins.
+	if walletType == walletTypeRPC {
+		node.lockUnspentErr = tErr
+		if _, err := wallet.SendWithCoins(&asset.CoinControlSend{Coins: coinIDs, Address: recipient}, defaultFee); err == nil {
+			t.Fatalf("no error when the coins can't be locked")
+		}
+	}
+}
+
 func TestConfirmations(t *testing.T) {
 	runRubric(t, testConfirmations)
 }
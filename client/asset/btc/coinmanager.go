@@ -553,6 +553,18 @@ func (c *CoinManager) UnlockOutPoints(pts []OutPoint) {
 	c.mtx.Unlock()
 }
 
+// LockedOutputs returns all of the utxos currently locked by the
+// CoinManager.
+func (c *CoinManager) LockedOutputs() []*UTxO {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	utxos := make([]*UTxO, 0, len(c.lockedOutputs))
+	for _, utxo := range c.lockedOutputs {
+		utxos = append(utxos, utxo)
+	}
+	return utxos
+}
+
 // LockedOutput returns the currently locked utxo represented by the provided
 // outpoint, or nil if there is no record of the utxo in the local map.
 func (c *CoinManager) LockedOutput(pt OutPoint) *UTxO {
@@ -737,6 +737,8 @@ var _ asset.FeeRater = (*ExchangeWallet)(nil)
 var _ asset.Withdrawer = (*ExchangeWallet)(nil)
 var _ asset.LiveReconfigurer = (*ExchangeWallet)(nil)
 var _ asset.TxFeeEstimator = (*ExchangeWallet)(nil)
+var _ asset.BatchSender = (*ExchangeWallet)(nil)
+var _ asset.CoinController = (*ExchangeWallet)(nil)
 var _ asset.Bonder = (*ExchangeWallet)(nil)
 var _ asset.Authenticator = (*ExchangeWallet)(nil)
 var _ asset.TicketBuyer = (*ExchangeWallet)(nil)
@@ -2647,21 +2649,10 @@ func (dcr *ExchangeWallet) fund(keep uint64, // leave utxos for this reserve amt
 // spendableUTXOs generates a slice of spendable *compositeUTXO.
 func (dcr *ExchangeWallet) spendableUTXOs() ([]*compositeUTXO, error) {
 	accts := dcr.wallet.Accounts()
-	unspents, err := dcr.wallet.Unspents(dcr.ctx, accts.PrimaryAccount)
+	unspents, err := dcr.unspents()
 	if err != nil {
 		return nil, err
 	}
-	if accts.TradingAccount != "" {
-		// Trading account may contain spendable utxos such as unspent split tx
-		// outputs that are unlocked/returned. TODO: Care should probably be
-		// taken to ensure only unspent split tx outputs are selected and other
-		// unmixed outputs in the trading account are ignored.
-		tradingAcctSpendables, err := dcr.wallet.Unspents(dcr.ctx, accts.TradingAccount)
-		if err != nil {
-			return nil, err
-		}
-		unspents = append(unspents, tradingAcctSpendables...)
-	}
 	if len(unspents) == 0 {
 		return nil, fmt.Errorf("insufficient funds. 0 DCR available to spend in account %q", accts.PrimaryAccount)
 	}
@@ -2678,6 +2669,28 @@ func (dcr *ExchangeWallet) spendableUTXOs() ([]*compositeUTXO, error) {
 	return utxos, nil
 }
 
+// unspents lists the unlocked unspent outputs in the primary and trading
+// accounts.
+func (dcr *ExchangeWallet) unspents() ([]*walletjson.ListUnspentResult, error) {
+	accts := dcr.wallet.Accounts()
+	unspents, err := dcr.wallet.Unspents(dcr.ctx, accts.PrimaryAccount)
+	if err != nil {
+		return nil, err
+	}
+	if accts.TradingAccount != "" {
+		// Trading account may contain spendable utxos such as unspent split tx
+		// outputs that are unlocked/returned. TODO: Care should probably be
+		// taken to ensure only unspent split tx outputs are selected and other
+		// unmixed outputs in the trading account are ignored.
+		tradingAcctSpendables, err := dcr.wallet.Unspents(dcr.ctx, accts.TradingAccount)
+		if err != nil {
+			return nil, err
+		}
+		unspents = append(unspents, tradingAcctSpendables...)
+	}
+	return unspents, nil
+}
+
 // tryFund attempts to use the provided UTXO set to satisfy the enough function
 // with the fewest number of inputs. The selected utxos are not locked. If the
 // requirement can be satisfied without 0-conf utxos, that set will be selected
@@ -6210,23 +6223,34 @@ func (dcr *ExchangeWallet) EstimateSendTxFee(address string, sendAmount, feeRate
 
 	tx.AddTxOut(newTxOut(int64(sendAmount), payScriptVer, pkScript)) // payScriptVer is default zero
 
-	utxos, err := dcr.spendableUTXOs()
+	fee, err = dcr.estimateTxFee(tx, sendAmount, feeRate, subtract)
 	if err != nil {
 		return 0, false, err
 	}
+	return fee, isValidAddress, nil
+}
+
+// estimateTxFee estimates the fees for funding the unfunded tx, whose outputs
+// sum to sendAmount. If subtract is true, the fees are taken from the sent
+// amount.
+func (dcr *ExchangeWallet) estimateTxFee(tx *wire.MsgTx, sendAmount, feeRate uint64, subtract bool) (uint64, error) {
+	utxos, err := dcr.spendableUTXOs()
+	if err != nil {
+		return 0, err
+	}
 
 	minTxSize := uint32(tx.SerializeSize())
 	reportChange := dcr.wallet.Accounts().UnmixedAccount == ""
 	enough := sendEnough(sendAmount, feeRate, subtract, minTxSize, reportChange)
 	sum, extra, inputsSize, _, _, _, err := tryFund(utxos, enough)
 	if err != nil {
-		return 0, false, err
+		return 0, err
 	}
 
 	reserves := dcr.bondReserves.Load()
 	avail := sumUTXOs(utxos)
 	if avail-sum+extra /* avail-sendAmount-fees */ < reserves {
-		return 0, false, errors.New("violates reserves")
+		return 0, errors.New("violates reserves")
 	}
 
 	txSize := uint64(minTxSize + inputsSize)
@@ -6253,7 +6277,293 @@ func (dcr *ExchangeWallet) EstimateSendTxFee(address string, sendAmount, feeRate
 		// additional fee will be paid for non-dust change
 		finalFee = estFeeWithChange
 	}
-	return finalFee, isValidAddress, nil
+	return finalFee, nil
+}
+
+// batchOutputs generates the outputs paying the batch recipients.
+func (dcr *ExchangeWallet) batchOutputs(recipients []*asset.BatchRecipient, feeRate uint64) ([]*wire.TxOut, uint64, error) {
+	if len(recipients) == 0 {
+		return nil, 0, errors.New("no recipients")
+	}
+	txOuts := make([]*wire.TxOut, 0, len(recipients))
+	var total uint64
+	for i, r := range recipients {
+		if r.Value == 0 {
+			return nil, 0, fmt.Errorf("recipient %d (%s): cannot send zero amount", i, r.Address)
+		}
+		addr, err := stdaddr.DecodeAddress(r.Address, dcr.chainParams)
+		if err != nil {
+			return nil, 0, fmt.Errorf("recipient %d: invalid address: %s", i, r.Address)
+		}
+		payScriptVer, payScript := addr.PaymentScript()
+		txOut := newTxOut(int64(r.Value), payScriptVer, payScript)
+		if dexdcr.IsDust(txOut, feeRate) {
+			return nil, 0, fmt.Errorf("recipient %d (%s): output value is dust", i, r.Address)
+		}
+		txOuts = append(txOuts, txOut)
+		total += r.Value
+	}
+	return txOuts, total, nil
+}
+
+// SendBatch pays all of the recipients in a single transaction. feeRate is in
+// units of atoms/byte. SendBatch satisfies asset.BatchSender.
+func (dcr *ExchangeWallet) SendBatch(recipients []*asset.BatchRecipient, feeRate uint64) ([]string, error) {
+	feeRate = dcr.feeRateWithFallback(feeRate)
+	txOuts, totalSend, err := dcr.batchOutputs(recipients, feeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	baseSize := uint32(dexdcr.MsgTxOverhead + dexdcr.P2PKHOutputSize) // change
+	for _, txOut := range txOuts {
+		baseSize += uint32(txOut.SerializeSize())
+	}
+	reportChange := dcr.wallet.Accounts().UnmixedAccount == ""
+	enough := sendEnough(totalSend, feeRate, false, baseSize, reportChange)
+	coins, _, _, _, err := dcr.fund(dcr.bondReserves.Load(), enough)
+	if err != nil {
+		return nil, fmt.Errorf("unable to send %s DCR with fee rate of %d atoms/byte: %w",
+			amount(totalSend), feeRate, err)
+	}
+
+	baseTx := wire.NewMsgTx()
+	totalIn, err := dcr.addInputCoins(baseTx, coins)
+	var msgTx *wire.MsgTx
+	if err == nil {
+		for _, txOut := range txOuts {
+			baseTx.AddTxOut(txOut)
+		}
+		msgTx, err = dcr.sendWithReturn(baseTx, feeRate, -1) // fees from change
+	}
+	if err != nil {
+		if _, retErr := dcr.returnCoins(coins); retErr != nil {
+			dcr.log.Errorf("Failed to unlock coins: %v", retErr)
+		}
+		return nil, err
+	}
+
+	var totalOut uint64
+	for _, txOut := range msgTx.TxOut {
+		totalOut += uint64(txOut.Value)
+	}
+
+	txHash := msgTx.CachedTxHash()
+	dcr.addTxToHistory(&asset.WalletTransaction{
+		Type:            asset.Send,
+		ID:              txHash.String(),
+		Amount:          totalSend,
+		Fees:            totalIn - totalOut,
+		BatchRecipients: recipients,
+		Timestamp:       uint64(time.Now().Unix()),
+		Confirms:        &asset.Confirms{Target: confTxFinality},
+	}, txHash, true)
+
+	return []string{txHash.String()}, nil
+}
+
+// EstimateBatchSendTxFee returns a tx fee estimate for paying all of the
+// recipients in a single transaction using the provided feeRate.
+// EstimateBatchSendTxFee satisfies asset.BatchSender.
+func (dcr *ExchangeWallet) EstimateBatchSendTxFee(recipients []*asset.BatchRecipient, feeRate uint64) (uint64, error) {
+	feeRate = dcr.feeRateWithFallback(feeRate)
+	txOuts, totalSend, err := dcr.batchOutputs(recipients, feeRate)
+	if err != nil {
+		return 0, err
+	}
+	tx := wire.NewMsgTx()
+	for _, txOut := range txOuts {
+		tx.AddTxOut(txOut)
+	}
+	return dcr.estimateTxFee(tx, totalSend, feeRate, false)
+}
+
+// SpendableCoins lists the wallet's unspent outputs, including outputs locked
+// by active orders or bonds. The Label is the name of the account holding the
+// output. SpendableCoins satisfies asset.CoinController.
+func (dcr *ExchangeWallet) SpendableCoins() ([]*asset.SpendableCoin, error) {
+	dcr.fundingMtx.RLock()
+	defer dcr.fundingMtx.RUnlock()
+
+	unspents, err := dcr.unspents()
+	if err != nil {
+		return nil, err
+	}
+	coins := make([]*asset.SpendableCoin, 0, len(unspents)+len(dcr.fundingCoins))
+	for _, u := range unspents {
+		if !u.Spendable {
+			continue
+		}
+		txHash, err := chainhash.NewHashFromStr(u.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding txid: %w", err)
+		}
+		if dcr.fundingCoins[newOutPoint(txHash, u.Vout)] != nil {
+			continue // added below
+		}
+		coins = append(coins, &asset.SpendableCoin{
+			ID:            ToCoinID(txHash, u.Vout),
+			TxID:          u.TxID,
+			Vout:          u.Vout,
+			Address:       u.Address,
+			Value:         toAtoms(u.Amount),
+			Confirmations: uint32(u.Confirmations),
+			Label:         u.Account,
+		})
+	}
+	for _, fc := range dcr.fundingCoins {
+		coins = append(coins, &asset.SpendableCoin{
+			ID:      fc.op.ID(),
+			TxID:    fc.op.TxID(),
+			Vout:    fc.op.vout(),
+			Address: fc.addr,
+			Value:   fc.op.value,
+			Locked:  true,
+		})
+	}
+	return coins, nil
+}
+
+// SendWithCoins sends using exactly the specified coins, paying the recipient
+// at output index 0. If send.Value is zero, the full value of the coins less
+// fees is sent and there is no change. feeRate is in units of atoms/byte.
+// SendWithCoins satisfies asset.CoinController.
+func (dcr *ExchangeWallet) SendWithCoins(send *asset.CoinControlSend, feeRate uint64) (asset.Coin, error) {
+	if len(send.Coins) == 0 {
+		return nil, errors.New("no coins specified")
+	}
+	feeRate = dcr.feeRateWithFallback(feeRate)
+	addr, err := stdaddr.DecodeAddress(send.Address, dcr.chainParams)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address: %s", send.Address)
+	}
+	var changeAddr stdaddr.Address
+	if send.ChangeAddress != "" {
+		if send.Value == 0 {
+			return nil, errors.New("change address specified for a send without change")
+		}
+		if changeAddr, err = stdaddr.DecodeAddress(send.ChangeAddress, dcr.chainParams); err != nil {
+			return nil, fmt.Errorf("invalid change address: %s", send.ChangeAddress)
+		}
+	}
+
+	// Hold the funding lock so that the coins can't be selected for an order
+	// until the send is complete.
+	dcr.fundingMtx.Lock()
+	defer dcr.fundingMtx.Unlock()
+
+	unspents, err := dcr.unspents()
+	if err != nil {
+		return nil, err
+	}
+	utxos, err := dcr.parseUTXOs(unspents)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing unspent outputs: %w", err)
+	}
+	utxoMap := make(map[outPoint]*compositeUTXO, len(utxos))
+	var spendable uint64
+	for _, utxo := range utxos {
+		txHash, err := chainhash.NewHashFromStr(utxo.rpc.TxID)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding txid: %w", err)
+		}
+		utxoMap[newOutPoint(txHash, utxo.rpc.Vout)] = utxo
+		spendable += toAtoms(utxo.rpc.Amount)
+	}
+
+	coins := make(asset.Coins, 0, len(send.Coins))
+	var totalIn uint64
+	size := uint64(dexdcr.MsgTxOverhead + dexdcr.P2PKHOutputSize)
+	for _, coinID := range send.Coins {
+		txHash, vout, err := decodeCoinID(coinID)
+		if err != nil {
+			return nil, err
+		}
+		pt := newOutPoint(txHash, vout)
+		if dcr.fundingCoins[pt] != nil {
+			return nil, fmt.Errorf("coin %s is locked by an active order or bond", pt)
+		}
+		utxo := utxoMap[pt]
+		if utxo == nil {
+			return nil, fmt.Errorf("coin %s not found or not spendable", pt)
+		}
+		delete(utxoMap, pt) // no duplicates
+		v := toAtoms(utxo.rpc.Amount)
+		coins = append(coins, newOutput(txHash, vout, v, utxo.rpc.Tree))
+		totalIn += v
+		size += uint64(utxo.input.Size())
+	}
+
+	// Estimate what remains in the wallet to enforce the bond reserves.
+	kept := spendable - totalIn
+	if send.Value > 0 {
+		fee := feeRate * (size + dexdcr.P2PKHOutputSize)
+		if send.Value+fee > totalIn {
+			return nil, fmt.Errorf("coins worth %s DCR cannot cover %s DCR plus fees of %s DCR",
+				amount(totalIn), amount(send.Value), amount(fee))
+		}
+		if changeAddr != nil && dexdcr.IsDustVal(dexdcr.P2PKHOutputSize, totalIn-send.Value-fee, feeRate) {
+			changeAddr = nil // forego dust change as signTxAndAddChange would
+		}
+		if changeAddr == nil && send.ChangeAddress == "" {
+			kept += totalIn - send.Value - fee
+		}
+	}
+	if reserves := dcr.bondReserves.Load(); reserves > 0 && kept < reserves {
+		return nil, fmt.Errorf("send would leave %s DCR, which is less than the %s DCR bond reserves",
+			amount(kept), amount(reserves))
+	}
+
+	var msgTx *wire.MsgTx
+	var sentVal uint64
+	switch {
+	case send.Value == 0:
+		msgTx, sentVal, err = dcr.sendCoins(coins, addr, nil, totalIn, 0, feeRate, true)
+	case changeAddr != nil:
+		// Pay the change to the specified address, taking fees from it.
+		baseTx := wire.NewMsgTx()
+		if _, err = dcr.addInputCoins(baseTx, coins); err != nil {
+			return nil, err
+		}
+		payScriptVer, payScript := addr.PaymentScript()
+		baseTx.AddTxOut(newTxOut(int64(send.Value), payScriptVer, payScript))
+		changeScriptVer, changeScript := changeAddr.PaymentScript()
+		baseTx.AddTxOut(newTxOut(int64(totalIn-send.Value), changeScriptVer, changeScript))
+		msgTx, err = dcr.sendWithReturn(baseTx, feeRate, 1)
+		sentVal = send.Value
+	default:
+		msgTx, sentVal, err = dcr.sendCoins(coins, addr, nil, send.Value, 0, feeRate, false)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var totalOut uint64
+	for _, txOut := range msgTx.TxOut {
+		totalOut += uint64(txOut.Value)
+	}
+
+	selfSend, err := dcr.OwnsDepositAddress(send.Address)
+	if err != nil {
+		dcr.log.Errorf("error checking if address %q is owned: %v", send.Address, err)
+	}
+	txType := asset.Send
+	if selfSend {
+		txType = asset.SelfSend
+	}
+
+	txHash := msgTx.CachedTxHash()
+	dcr.addTxToHistory(&asset.WalletTransaction{
+		Type:      txType,
+		ID:        txHash.String(),
+		Amount:    sentVal,
+		Fees:      totalIn - totalOut,
+		Recipient: &send.Address,
+		Timestamp: uint64(time.Now().Unix()),
+		Confirms:  &asset.Confirms{Target: confTxFinality},
+	}, txHash, true)
+
+	return newOutput(txHash, 0, sentVal, wire.TxTreeRegular), nil
 }
 
 // StandardSendFee returns the fees for a simple send tx with one input and two
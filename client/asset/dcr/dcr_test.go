@@ -3508,6 +3508,173 @@ func TestSend(t *testing.T) {
 	testSender(t, tSendSender)
 }
 
+func TestSendBatch(t *testing.T) {
+	wallet, node, shutdown := tNewWallet()
+	defer shutdown()
+
+	node.changeAddr = tPKHAddr
+	node.unspent = []walletjson.ListUnspentResult{{
+		TxID:          tTxID,
+		Address:       tPKHAddr.String(),
+		Account:       tAcctName,
+		Amount:        100,
+		Confirmations: 5,
+		ScriptPubKey:  hex.EncodeToString(tP2PKHScript),
+		Spendable:     true,
+	}}
+
+	recipients := []*asset.BatchRecipient{
+		{Address: tPKHAddr.String(), Value: 1e8},
+		{Address: tPKHAddr.String(), Value: 2e8},
+	}
+
+	if _, err := wallet.EstimateBatchSendTxFee(recipients, 10); err != nil {
+		t.Fatalf("EstimateBatchSendTxFee error: %v", err)
+	}
+
+	txIDs, err := wallet.SendBatch(recipients, 10)
+	if err != nil {
+		t.Fatalf("SendBatch error: %v", err)
+	}
+	if len(txIDs) != 1 {
+		t.Fatalf("expected 1 tx ID, got %d", len(txIDs))
+	}
+	sentTx := node.sentRawTx
+	if len(sentTx.TxOut) != len(recipients)+1 {
+		t.Fatalf("expected %d outputs, got %d", len(recipients)+1, len(sentTx.TxOut))
+	}
+	for i, r := range recipients {
+		if sentTx.TxOut[i].Value != int64(r.Value) {
+			t.Fatalf("wrong value for output %d. wanted %d, got %d", i, r.Value, sentTx.TxOut[i].Value)
+		}
+	}
+
+	if _, err := wallet.SendBatch(nil, 10); err == nil {
+		t.Fatalf("no error for no recipients")
+	}
+	badAddr := []*asset.BatchRecipient{{Address: "badaddr", Value: 1e8}}
+	if _, err := wallet.SendBatch(badAddr, 10); err == nil {
+		t.Fatalf("no error for bad address")
+	}
+	tooMuch := []*asset.BatchRecipient{
+		{Address: tPKHAddr.String(), Value: 50e8},
+		{Address: tPKHAddr.String(), Value: 50e8},
+	}
+	if _, err := wallet.SendBatch(tooMuch, 10); err == nil {
+		t.Fatalf("no error for insufficient funds")
+	}
+}
+
+func TestSendWithCoins(t *testing.T) {
+	wallet, node, shutdown := tNewWallet()
+	defer shutdown()
+
+	node.changeAddr = tPKHAddr
+	unspent := func(vout uint32, amt float64) walletjson.ListUnspentResult {
+		return walletjson.ListUnspentResult{
+			TxID:          tTxID,
+			Vout:          vout,
+			Address:       tPKHAddr.String(),
+			Account:       tAcctName,
+			Amount:        amt,
+			Confirmations: 5,
+			ScriptPubKey:  hex.EncodeToString(tP2PKHScript),
+			Spendable:     true,
+		}
+	}
+	node.unspent = []walletjson.ListUnspentResult{unspent(0, 1), unspent(1, 2)}
+	lockedPt := newOutPoint(tTxHash, 2)
+	wallet.fundingCoins[lockedPt] = &fundingCoin{
+		op:   newOutput(tTxHash, 2, 3e8, wire.TxTreeRegular),
+		addr: tPKHAddr.String(),
+	}
+
+	coins, err := wallet.SpendableCoins()
+	if err != nil {
+		t.Fatalf("SpendableCoins error: %v", err)
+	}
+	if len(coins) != 3 {
+		t.Fatalf("expected 3 coins, got %d", len(coins))
+	}
+	for _, c := range coins {
+		if c.Locked != (c.Vout == 2) {
+			t.Fatalf("wrong locked status for coin %d", c.Vout)
+		}
+		if !c.Locked && (c.Label != tAcctName || c.Confirmations != 5) {
+			t.Fatalf("wrong coin info %+v", c)
+		}
+	}
+
+	coinIDs := []dex.Bytes{ToCoinID(tTxHash, 0), ToCoinID(tTxHash, 1)}
+	recipient := tPKHAddr.String()
+
+	// Exact value with change.
+	coin, err := wallet.SendWithCoins(&asset.CoinControlSend{
+		Coins:   coinIDs,
+		Address: recipient,
+		Value:   1.5e8,
+	}, 10)
+	if err != nil {
+		t.Fatalf("SendWithCoins error: %v", err)
+	}
+	if coin.Value() != 1.5e8 {
+		t.Fatalf("wrong sent value %d", coin.Value())
+	}
+	sentTx := node.sentRawTx
+	if len(sentTx.TxIn) != 2 || len(sentTx.TxOut) != 2 {
+		t.Fatalf("expected 2 inputs and 2 outputs, got %d and %d", len(sentTx.TxIn), len(sentTx.TxOut))
+	}
+
+	// Change to a specified address.
+	if _, err = wallet.SendWithCoins(&asset.CoinControlSend{
+		Coins:         coinIDs,
+		Address:       recipient,
+		Value:         1.5e8,
+		ChangeAddress: tPKHAddr.String(),
+	}, 10); err != nil {
+		t.Fatalf("SendWithCoins (change address) error: %v", err)
+	}
+	sentTx = node.sentRawTx
+	if len(sentTx.TxOut) != 2 || sentTx.TxOut[0].Value != 1.5e8 {
+		t.Fatalf("wrong outputs for send with change address")
+	}
+
+	// No change.
+	coin, err = wallet.SendWithCoins(&asset.CoinControlSend{
+		Coins:   coinIDs[:1],
+		Address: recipient,
+	}, 10)
+	if err != nil {
+		t.Fatalf("SendWithCoins (no change) error: %v", err)
+	}
+	sentTx = node.sentRawTx
+	if len(sentTx.TxIn) != 1 || len(sentTx.TxOut) != 1 {
+		t.Fatalf("expected 1 input and 1 output, got %d and %d", len(sentTx.TxIn), len(sentTx.TxOut))
+	}
+	if fees := uint64(1e8) - coin.Value(); fees == 0 || fees > 1e5 {
+		t.Fatalf("unexpected fees %d", fees)
+	}
+
+	for name, send := range map[string]*asset.CoinControlSend{
+		"no coins":       {Address: recipient, Value: 1e8},
+		"locked coin":    {Coins: []dex.Bytes{ToCoinID(tTxHash, 2)}, Address: recipient},
+		"unknown coin":   {Coins: []dex.Bytes{ToCoinID(tTxHash, 5)}, Address: recipient},
+		"duplicate coin": {Coins: []dex.Bytes{coinIDs[0], coinIDs[0]}, Address: recipient},
+		"insufficient":   {Coins: coinIDs[:1], Address: recipient, Value: 2e8},
+		"bad address":    {Coins: coinIDs, Address: "badaddr"},
+		"bad change":     {Coins: coinIDs, Address: recipient, Value: 1e8, ChangeAddress: "badaddr"},
+	} {
+		if _, err := wallet.SendWithCoins(send, 10); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	wallet.bondReserves.Store(2e8)
+	if _, err := wallet.SendWithCoins(&asset.CoinControlSend{Coins: coinIDs, Address: recipient}, 10); err == nil {
+		t.Fatalf("no error for violating bond reserves")
+	}
+}
+
 func Test_withdraw(t *testing.T) {
 	wallet, node, shutdown := tNewWallet()
 	defer shutdown()
@@ -114,6 +114,41 @@ func RegisterToken(
 	}
 }
 
+// RegisterCustomToken registers a user-defined token for the current network.
+// Unlike RegisterToken, RegisterCustomToken can be called at any time, and
+// returns an error rather than panicking. SetNetwork must already have been
+// called. Registering a token that is already registered with the same
+// contract address is not an error.
+func RegisterCustomToken(
+	tokenID uint32,
+	token *dex.Token,
+	walletDef *WalletDefinition,
+	contractAddr string,
+	supportedAssetVersions []uint32,
+) error {
+	driversMtx.Lock()
+	defer driversMtx.Unlock()
+	if nt, exists := tokens[tokenID]; exists {
+		if nt.Custom && nt.ContractAddress == contractAddr {
+			return nil
+		}
+		return fmt.Errorf("token %d already exists", tokenID)
+	}
+	if _, exists := drivers[token.ParentID]; !exists {
+		return fmt.Errorf("token %d's parent asset %d isn't registered", tokenID, token.ParentID)
+	}
+	tokens[tokenID] = &nettedToken{
+		Token: &Token{
+			Token:                  token,
+			Definition:             walletDef,
+			ContractAddress:        contractAddr,
+			SupportedAssetVersions: supportedAssetVersions,
+			Custom:                 true,
+		},
+	}
+	return nil
+}
+
 // WalletExists will be true if the specified wallet exists.
 func WalletExists(assetID uint32, walletType, dataDir string, settings map[string]string, net dex.Network) (exists bool, err error) {
 	return exists, withDriver(assetID, func(drv Driver) error {
@@ -66,6 +66,14 @@ var (
 		},
 	}
 
+	// acrossNativeSymbols are the symbols that Across uses for the native
+	// asset of chains on which the native asset is not the Across asset, i.e.
+	// weth for chains that use ETH as the native asset.
+	acrossNativeSymbols = map[uint32]string{
+		ethID:  "weth",
+		baseID: "weth",
+	}
+
 	// Global cache for across available routes.
 	acrossAvailableRoutesCache = make(map[dex.Network][]acrossAvailableRoute)
 	acrossAvailableRoutesMutex sync.Mutex
@@ -79,6 +87,36 @@ func init() {
 	}
 }
 
+// RegisterAcrossChain adds an EVM chain to the chains that are supported by
+// the Across bridge. nativeSymbol is the symbol that Across uses for the
+// chain's native asset, and nativeTokenAddr is the address of the wrapped
+// native asset. RegisterAcrossChain must be called before any wallets are
+// created, e.g. from an init function.
+func RegisterAcrossChain(net dex.Network, chainAssetID uint32, chainID uint64, spokePoolAddr, nativeTokenAddr common.Address, nativeSymbol string) error {
+	if _, found := chainAssetIDToAcrossChainID[net][chainAssetID]; found {
+		return fmt.Errorf("asset %d is already registered for %s", chainAssetID, net)
+	}
+	if _, found := idToChainAssetID[net][chainID]; found {
+		return fmt.Errorf("chain ID %d is already registered for %s", chainID, net)
+	}
+	if chainAssetIDToAcrossChainID[net] == nil {
+		chainAssetIDToAcrossChainID[net] = make(map[uint32]uint64)
+		idToChainAssetID[net] = make(map[uint64]uint32)
+		acrossSpokePoolAddrs[net] = make(map[uint64]common.Address)
+		chainIDToNativeTokenAddr[net] = make(map[uint64]common.Address)
+	}
+	chainAssetIDToAcrossChainID[net][chainAssetID] = chainID
+	idToChainAssetID[net][chainID] = chainAssetID
+	acrossSpokePoolAddrs[net][chainID] = spokePoolAddr
+	if nativeTokenAddr != (common.Address{}) {
+		chainIDToNativeTokenAddr[net][chainID] = nativeTokenAddr
+	}
+	if nativeSymbol = strings.ToLower(nativeSymbol); nativeSymbol != dex.BipIDSymbol(chainAssetID) {
+		acrossNativeSymbols[chainAssetID] = nativeSymbol
+	}
+	return nil
+}
+
 func AcrossBridgeSupportedAsset(assetID uint32, net dex.Network) (supported bool) {
 	return assetIDToAcrossAsset(net, assetID) != nil
 }
@@ -137,11 +175,10 @@ func assetIDToAcrossAsset(net dex.Network, assetID uint32) *acrossAsset {
 
 	// The weth address should be provided for ETH, but the protocol will unwrap
 	// the weth for EOA accounts.
-	if assetName == "eth" {
-		assetName = "weth"
-	}
-	if assetName == "base" {
-		assetName = "weth"
+	if assetName == chainName {
+		if nativeSymbol, found := acrossNativeSymbols[chainAssetID]; found {
+			assetName = nativeSymbol
+		}
 	}
 
 	return &acrossAsset{chainID: chainID, symbol: strings.ToUpper(assetName), address: address}
@@ -160,11 +197,8 @@ func acrossAssetToAssetID(net dex.Network, symbol string, chainID uint64) (uint3
 		fullSymbol = fmt.Sprintf("%s.%s", assetSymbol, chainSymbol)
 	}
 
-	if fullSymbol == "weth.eth" {
-		fullSymbol = "eth"
-	}
-	if fullSymbol == "weth.base" {
-		fullSymbol = "base"
+	if assetSymbol == acrossNativeSymbols[chainAssetID] {
+		fullSymbol = chainSymbol
 	}
 
 	return dex.BipSymbolID(fullSymbol)
@@ -0,0 +1,423 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/networks/erc20"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"decred.org/dcrdex/dex/networks/eth/contracts/bond"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EVM bonds are held by the bond contract and are owned by the wallet's
+// account, so the bond private key that is provided by Core is not used. The
+// bond coin ID is the hash of the createBond transaction and the bond data is
+// the bond ID.
+
+var (
+	_ asset.Bonder = (*ETHWallet)(nil)
+	_ asset.Bonder = (*TokenWallet)(nil)
+)
+
+// bondContract is the address of the bond contract. It is an error if bonds
+// are not supported on the chain.
+func (w *baseWallet) bondContract() (common.Address, error) {
+	if w.bondContractAddr == (common.Address{}) {
+		return common.Address{}, fmt.Errorf("no %s bond contract on %s", dex.BipIDSymbol(w.baseChainID), w.net)
+	}
+	return w.bondContractAddr, nil
+}
+
+// bondGases are the gas limits for the asset's bond transactions.
+func (w *assetWallet) bondGases() *dexeth.BondGases {
+	if w.assetID == w.baseChainID {
+		return dexeth.ETHBondGases
+	}
+	return dexeth.TokenBondGases
+}
+
+// BondsFeeBuffer suggests how much extra may be required for the transaction
+// fees part of bond reserves when bond rotation is enabled. This is enough for
+// a bond and a refund at twice the fee rate. Part of the asset.Bonder
+// interface.
+func (w *ETHWallet) BondsFeeBuffer(feeRate uint64) uint64 {
+	if feeRate == 0 {
+		feeRate = w.FeeRate()
+	}
+	g := w.bondGases()
+	return 2 * feeRate * (g.Create + g.Refund)
+}
+
+// BondsFeeBuffer is zero for tokens, since the fees are paid by the parent
+// asset. Part of the asset.Bonder interface.
+func (w *TokenWallet) BondsFeeBuffer(uint64) uint64 {
+	return 0
+}
+
+// SetBondReserves sets the bond reserve amount for the wallet. Part of the
+// asset.Bonder interface.
+func (w *assetWallet) SetBondReserves(reserves uint64) {
+	w.bondReserves.Store(reserves)
+}
+
+// MakeBondTx authors a signed createBond transaction for the bond contract.
+// The transaction is not broadcast, but its nonce is reserved until it is sent
+// with SendTransaction or abandoned with the returned function. For tokens,
+// an approval transaction is broadcast first if the bond contract is not
+// approved to transfer the bond amount, and that approval is revoked if the
+// bond is abandoned. Part of the asset.Bonder interface.
+func (w *ETHWallet) MakeBondTx(ver uint16, amt, _ uint64, lockTime time.Time, _ *secp256k1.PrivateKey, acctID []byte) (*asset.Bond, func(), error) {
+	return w.makeBondTx(ver, amt, lockTime, acctID)
+}
+
+// MakeBondTx authors a signed createBond transaction for the bond contract.
+// See (*ETHWallet).MakeBondTx. Part of the asset.Bonder interface.
+func (w *TokenWallet) MakeBondTx(ver uint16, amt, _ uint64, lockTime time.Time, _ *secp256k1.PrivateKey, acctID []byte) (*asset.Bond, func(), error) {
+	return w.makeBondTx(ver, amt, lockTime, acctID)
+}
+
+func (w *assetWallet) makeBondTx(ver uint16, amt uint64, lockTime time.Time, acctIDB []byte) (*asset.Bond, func(), error) {
+	if ver != dexeth.BondVersion {
+		return nil, nil, fmt.Errorf("only version %d bonds supported", dexeth.BondVersion)
+	}
+	if until := time.Until(lockTime); until >= 365*12*time.Hour /* ~6 months */ {
+		return nil, nil, fmt.Errorf("that lock time is nuts: %v", lockTime)
+	} else if until < 0 {
+		return nil, nil, fmt.Errorf("that lock time is already passed: %v", lockTime)
+	}
+	if len(acctIDB) != 32 {
+		return nil, nil, fmt.Errorf("invalid account ID length %d", len(acctIDB))
+	}
+	if amt == 0 {
+		return nil, nil, errors.New("zero bond amount")
+	}
+	bondAddr, err := w.bondContract()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var acctID [32]byte
+	copy(acctID[:], acctIDB)
+	b := &dexeth.Bond{
+		AcctID:   acctID,
+		Owner:    w.addr,
+		Token:    w.tokenAddr,
+		Value:    w.evmify(amt),
+		LockTime: uint64(lockTime.Unix()),
+	}
+	bondID := b.ID()
+
+	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(w.ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting fee rate: %w", err)
+	}
+	feeRateGwei := dexeth.WeiToGweiCeil(maxFeeRate)
+	g := w.bondGases()
+	isToken := w.assetID != w.baseChainID
+
+	// Bonds may use the bond reserves.
+	bal, err := w.balance()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting balance: %w", err)
+	}
+	avail := bal.Available + bal.BondReserves
+	var approvalGas uint64
+	if isToken {
+		if avail < amt {
+			return nil, nil, fmt.Errorf("%w: bond amount %s, available %s", asset.ErrInsufficientBalance,
+				w.amtString(amt), w.amtString(avail))
+		}
+		allowance, err := w.bondAllowance(bondAddr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error checking bond contract allowance: %w", err)
+		}
+		if allowance.Cmp(b.Value) < 0 {
+			tokenGases := w.gases(dexeth.ContractVersionNewest)
+			if tokenGases == nil {
+				return nil, nil, fmt.Errorf("no gas table for %s", w.ui.Conventional.Unit)
+			}
+			approvalGas = tokenGases.Approve
+		}
+		feeWallet, err := w.baseAssetWallet()
+		if err != nil {
+			return nil, nil, err
+		}
+		feeBal, err := feeWallet.balance()
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting fee balance: %w", err)
+		}
+		if fees := (g.Create + approvalGas) * feeRateGwei; feeBal.Available+feeBal.BondReserves < fees {
+			return nil, nil, fmt.Errorf("%w: bond fees %s, available %s", asset.ErrInsufficientBalance,
+				feeWallet.amtString(fees), feeWallet.amtString(feeBal.Available+feeBal.BondReserves))
+		}
+	} else if req := amt + g.Create*feeRateGwei; avail < req {
+		return nil, nil, fmt.Errorf("%w: bond amount plus fees %s, available %s", asset.ErrInsufficientBalance,
+			w.amtString(req), w.amtString(avail))
+	}
+
+	if approvalGas > 0 {
+		if err := w.approveBondContract(bondAddr, unlimitedAllowance, approvalGas, maxFeeRate, tipRate); err != nil {
+			return nil, nil, fmt.Errorf("error approving bond contract: %w", err)
+		}
+	}
+	// revokeApproval undoes an approval made for this bond if the bond
+	// transaction is never sent.
+	revokeApproval := func() {
+		if approvalGas == 0 {
+			return
+		}
+		if err := w.approveBondContract(bondAddr, new(big.Int), approvalGas, maxFeeRate, tipRate); err != nil {
+			w.log.Errorf("Error revoking bond contract approval for %s: %v", w.ui.Conventional.Unit, err)
+		}
+	}
+
+	var txValue uint64
+	if !isToken {
+		txValue = amt
+	}
+	var tx *types.Transaction
+	err = w.withNonce(w.ctx, func(nonce *big.Int) (*genTxResult, error) {
+		txOpts, err := w.node.txOpts(w.ctx, txValue, g.Create, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return nil, err
+		}
+		txOpts.NoSend = true
+		c, err := bond.NewETHBondTransactor(bondAddr, w.node.contractBackend())
+		if err != nil {
+			return nil, err
+		}
+		tx, err = c.CreateBond(txOpts, b.AcctID, b.Token, b.Value, new(big.Int).SetUint64(b.LockTime))
+		if err != nil {
+			return nil, err
+		}
+		return &genTxResult{
+			tx:     tx,
+			txType: asset.CreateBond,
+			amt:    amt,
+			bondInfo: &asset.BondTxInfo{
+				AccountID: acctID[:],
+				LockTime:  b.LockTime,
+				BondID:    bondID[:],
+			},
+		}, nil
+	})
+	if err != nil {
+		revokeApproval()
+		return nil, nil, fmt.Errorf("error creating bond transaction: %w", err)
+	}
+
+	rawTx, err := tx.MarshalBinary()
+	if err != nil {
+		w.abandonBondTx(tx)
+		revokeApproval()
+		return nil, nil, fmt.Errorf("error encoding bond transaction: %w", err)
+	}
+
+	return &asset.Bond{
+		Version: ver,
+		AssetID: w.assetID,
+		Amount:  amt,
+		CoinID:  tx.Hash().Bytes(),
+		Data:    bondID[:],
+		// The server needs the signature to recover the bond owner.
+		SignedTx:   rawTx,
+		UnsignedTx: rawTx,
+	}, func() {
+		w.abandonBondTx(tx)
+		revokeApproval()
+	}, nil
+}
+
+// abandonBondTx releases the nonce of a bond transaction that was not
+// broadcast.
+func (w *assetWallet) abandonBondTx(tx *types.Transaction) {
+	w.nonceMtx.Lock()
+	defer w.nonceMtx.Unlock()
+	txID := tx.Hash().String()
+	idx, wt := pendingTxWithID(txID, w.pendingTxs)
+	if wt == nil {
+		return
+	}
+	w.log.Infof("Abandoning unsent bond transaction %s", txID)
+	wt.AssumedLost = true
+	w.tryStoreDBTx(wt)
+	if new(big.Int).Add(wt.Nonce, big.NewInt(1)).Cmp(w.pendingNonceAt) == 0 {
+		w.pendingNonceAt.Set(wt.Nonce)
+	} else {
+		w.log.Warnf("Abandoned bond transaction %s with nonce %s was not the last pending transaction", txID, wt.Nonce)
+	}
+	copy(w.pendingTxs[idx:], w.pendingTxs[idx+1:])
+	w.pendingTxs = w.pendingTxs[:len(w.pendingTxs)-1]
+	w.emitTransactionNote(wt.WalletTransaction, false)
+}
+
+// bondAllowance is the amount of tokens that the bond contract is approved to
+// transfer.
+func (w *assetWallet) bondAllowance(bondAddr common.Address) (*big.Int, error) {
+	c, err := erc20.NewIERC20(w.tokenAddr, w.node.contractBackend())
+	if err != nil {
+		return nil, err
+	}
+	return c.Allowance(&bind.CallOpts{From: w.addr, Context: w.ctx}, w.addr, bondAddr)
+}
+
+// approveBondContract sets the amount of tokens that the bond contract is
+// approved to transfer. A zero amount revokes the approval.
+func (w *assetWallet) approveBondContract(bondAddr common.Address, amount *big.Int, gasLimit uint64, maxFeeRate, tipRate *big.Int) error {
+	return w.withNonce(w.ctx, func(nonce *big.Int) (*genTxResult, error) {
+		txOpts, err := w.node.txOpts(w.ctx, 0, gasLimit, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return nil, err
+		}
+		c, err := erc20.NewIERC20(w.tokenAddr, w.node.contractBackend())
+		if err != nil {
+			return nil, err
+		}
+		tx, err := c.Approve(txOpts, bondAddr, amount)
+		if err != nil {
+			return nil, err
+		}
+		w.log.Infof("Bond contract approval of %s sent for %s, txID = %s", amount, w.ui.Conventional.Unit, tx.Hash())
+		return &genTxResult{
+			tx:     tx,
+			txType: asset.ApproveToken,
+			amt:    w.atomize(amount),
+		}, nil
+	})
+}
+
+// RefundBond refunds the bond after its lock time has passed. script is the
+// bond ID. Part of the asset.Bonder interface.
+func (w *assetWallet) RefundBond(ctx context.Context, ver uint16, _, script []byte, amt uint64, _ *secp256k1.PrivateKey) (asset.Coin, error) {
+	if ver != dexeth.BondVersion {
+		return nil, fmt.Errorf("only version %d bonds supported", dexeth.BondVersion)
+	}
+	if len(script) != 32 {
+		return nil, fmt.Errorf("invalid bond ID length %d", len(script))
+	}
+	var bondID [32]byte
+	copy(bondID[:], script)
+	bondAddr, err := w.bondContract()
+	if err != nil {
+		return nil, err
+	}
+	rec, err := dexeth.ReadBond(ctx, w.node.contractBackend(), bondAddr, bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bond: %w", err)
+	}
+	if rec == nil {
+		return nil, asset.CoinNotFoundError
+	}
+	if rec.Owner != w.addr {
+		return nil, fmt.Errorf("%w: bond is owned by %s", asset.ErrIncorrectBondKey, rec.Owner)
+	}
+	expired, err := w.LockTimeExpired(ctx, time.Unix(int64(rec.LockTime), 0))
+	if err != nil {
+		return nil, err
+	}
+	if !expired {
+		return nil, fmt.Errorf("bond is locked until %v", time.Unix(int64(rec.LockTime), 0))
+	}
+
+	maxFeeRate, tipRate, err := w.recommendedMaxFeeRate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting fee rate: %w", err)
+	}
+	var tx *types.Transaction
+	err = w.withNonce(ctx, func(nonce *big.Int) (*genTxResult, error) {
+		txOpts, err := w.node.txOpts(ctx, 0, w.bondGases().Refund, maxFeeRate, tipRate, nonce)
+		if err != nil {
+			return nil, err
+		}
+		c, err := bond.NewETHBondTransactor(bondAddr, w.node.contractBackend())
+		if err != nil {
+			return nil, err
+		}
+		tx, err = c.RefundBond(txOpts, bondID)
+		if err != nil {
+			return nil, err
+		}
+		return &genTxResult{
+			tx:     tx,
+			txType: asset.RedeemBond,
+			amt:    w.atomize(rec.Value),
+			bondInfo: &asset.BondTxInfo{
+				AccountID: rec.AcctID[:],
+				LockTime:  rec.LockTime,
+				BondID:    bondID[:],
+			},
+		}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error refunding bond: %w", err)
+	}
+	return &coin{txHash: tx.Hash(), value: w.atomize(rec.Value)}, nil
+}
+
+// FindBond finds the active bond created by the transaction with the coin ID.
+// Part of the asset.Bonder interface.
+func (w *assetWallet) FindBond(ctx context.Context, coinID []byte, _ time.Time) (*asset.BondDetails, error) {
+	cid, err := dexeth.DecodeCoinID(coinID)
+	if err != nil {
+		return nil, err
+	}
+	if cid.IsRelay {
+		return nil, errors.New("relay coin ID is not a bond")
+	}
+	bondAddr, err := w.bondContract()
+	if err != nil {
+		return nil, err
+	}
+	tx, _, err := w.node.getTransaction(ctx, cid.TxHash)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bond transaction: %w", err)
+	}
+	if to := tx.To(); to == nil || *to != bondAddr {
+		return nil, fmt.Errorf("transaction %s is not to the bond contract", cid.TxHash)
+	}
+	b, err := dexeth.ParseCreateBondData(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if b.Token != w.tokenAddr {
+		return nil, fmt.Errorf("bond is for token %s, not %s", b.Token, w.ui.Conventional.Unit)
+	}
+	if b.Owner, err = types.LatestSignerForChainID(tx.ChainId()).Sender(tx); err != nil {
+		return nil, fmt.Errorf("error recovering bond owner: %w", err)
+	}
+	if b.Owner != w.addr {
+		return nil, fmt.Errorf("bond is owned by %s", b.Owner)
+	}
+	bondID := b.ID()
+	rec, err := dexeth.ReadBond(ctx, w.node.contractBackend(), bondAddr, bondID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bond: %w", err)
+	}
+	if rec == nil {
+		return nil, asset.CoinNotFoundError
+	}
+	return &asset.BondDetails{
+		Bond: &asset.Bond{
+			Version: dexeth.BondVersion,
+			AssetID: w.assetID,
+			Amount:  w.atomize(b.Value),
+			CoinID:  coinID,
+			Data:    bondID[:],
+		},
+		LockTime: time.Unix(int64(b.LockTime), 0),
+		// The bond is owned by the wallet's account, not a bond key.
+		CheckPrivKey: func(*secp256k1.PrivateKey) bool { return true },
+	}, nil
+}
@@ -273,7 +273,7 @@ func (c *contractorV0) statusAndVector(ctx context.Context, locator []byte) (*de
 func (c *contractorV0) swap(ctx context.Context, secretHash [32]byte) (*dexeth.SwapState, error) {
 	callOpts := &bind.CallOpts{
 		From:    c.acctAddr,
-		Context: ctx,
+		Context: quorumContext(ctx),
 	}
 	state, err := c.contractV0.Swap(callOpts, secretHash)
 	if err != nil {
@@ -706,7 +706,7 @@ func (c *contractorV1) status(ctx context.Context, locator []byte) (*dexeth.Swap
 	if err != nil {
 		return nil, err
 	}
-	rec, err := c.Status(&bind.CallOpts{From: c.acctAddr}, c.tokenAddr, dexeth.SwapVectorToAbigen(v))
+	rec, err := c.Status(&bind.CallOpts{From: c.acctAddr, Context: quorumContext(ctx)}, c.tokenAddr, dexeth.SwapVectorToAbigen(v))
 	if err != nil {
 		return nil, err
 	}
@@ -727,7 +727,7 @@ func (c *contractorV1) statusAndVector(ctx context.Context, locator []byte) (*de
 		return nil, nil, err
 	}
 
-	rec, err := c.Status(&bind.CallOpts{From: c.acctAddr}, c.tokenAddr, dexeth.SwapVectorToAbigen(v))
+	rec, err := c.Status(&bind.CallOpts{From: c.acctAddr, Context: quorumContext(ctx)}, c.tokenAddr, dexeth.SwapVectorToAbigen(v))
 	if err != nil {
 		return nil, nil, err
 	}
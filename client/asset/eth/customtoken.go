@@ -0,0 +1,202 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/networks/erc20"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// customToken is a user-defined ERC20 token. The metadata is queried from the
+// token contract when the token is added.
+type customToken struct {
+	Address  common.Address `json:"address"`
+	Name     string         `json:"name"`
+	Symbol   string         `json:"symbol"`
+	Decimals uint8          `json:"decimals"`
+}
+
+func (ct *customToken) MarshalBinary() ([]byte, error) {
+	return json.Marshal(ct)
+}
+
+func (ct *customToken) UnmarshalBinary(b []byte) error {
+	return json.Unmarshal(b, ct)
+}
+
+var _ asset.CustomTokenAdder = (*ETHWallet)(nil)
+
+// token gets the built-in or user-defined token, or nil if the token is not
+// known.
+func (w *baseWallet) token(assetID uint32) *dexeth.Token {
+	w.tokensMtx.RLock()
+	defer w.tokensMtx.RUnlock()
+	return w.tokens[assetID]
+}
+
+// tokenMap is a copy of the tokens map.
+func (w *baseWallet) tokenMap() map[uint32]*dexeth.Token {
+	w.tokensMtx.RLock()
+	defer w.tokensMtx.RUnlock()
+	tokens := make(map[uint32]*dexeth.Token, len(w.tokens))
+	for assetID, token := range w.tokens {
+		tokens[assetID] = token
+	}
+	return tokens
+}
+
+func (w *baseWallet) txDBPath() string {
+	return filepath.Join(w.dir, "txhistorydb-lexi")
+}
+
+// loadCustomTokens registers the user-defined tokens that are saved in the
+// transaction database. The database is only opened briefly, since the wallet
+// is not connected yet.
+func (w *assetWallet) loadCustomTokens() error {
+	if _, err := os.Stat(w.txDBPath()); err != nil {
+		// No database yet, so no custom tokens.
+		return nil
+	}
+	db, err := NewTxDB(w.txDBPath(), w.log.SubLogger("TXDB"), w.baseChainID)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	cts, err := db.getCustomTokens()
+	if err != nil {
+		return err
+	}
+	for _, ct := range cts {
+		if _, err := w.registerCustomToken(ct); err != nil {
+			w.log.Errorf("Error registering custom token %s at %s: %v", ct.Symbol, ct.Address, err)
+		}
+	}
+	return nil
+}
+
+// registerCustomToken adds the user-defined token to the wallet's tokens and
+// registers it with the asset package.
+func (w *assetWallet) registerCustomToken(ct *customToken) (uint32, error) {
+	tokenID := dexeth.CustomTokenID(w.baseChainID, ct.Address)
+	token, err := dexeth.NewCustomToken(w.baseChainID, w.net, ct.Address, ct.Name, ct.Symbol, ct.Decimals)
+	if err != nil {
+		return 0, err
+	}
+	symbol := strings.ToLower(ct.Symbol) + "." + dex.BipIDSymbol(w.baseChainID)
+	if err := dex.RegisterCustomSymbol(tokenID, symbol); err != nil {
+		return 0, err
+	}
+	if err := asset.RegisterCustomToken(tokenID, token.Token, &asset.WalletDefinition{
+		Type:        walletTypeToken,
+		Tab:         w.wi.Name + " token",
+		Description: fmt.Sprintf("The user-defined %s ERC20 token.", ct.Name),
+	}, ct.Address.String(), []uint32{1}); err != nil {
+		return 0, err
+	}
+	w.tokensMtx.Lock()
+	w.tokens[tokenID] = token
+	w.tokensMtx.Unlock()
+	return tokenID, nil
+}
+
+// AddCustomToken looks up the ERC20 token at the contract address, saves it,
+// and registers it. The token can then be used like a built-in token, except
+// that it can't be traded. Adding a token that was already added is not an
+// error. AddCustomToken is part of the asset.CustomTokenAdder interface.
+func (w *ETHWallet) AddCustomToken(ctx context.Context, contractAddr string) (uint32, error) {
+	if !w.connected.Load() {
+		return 0, errors.New("wallet not connected")
+	}
+	if !common.IsHexAddress(contractAddr) {
+		return 0, fmt.Errorf("invalid contract address %q", contractAddr)
+	}
+	addr := common.HexToAddress(contractAddr)
+	for tokenID, token := range w.tokenMap() {
+		if nt := token.NetTokens[w.net]; nt != nil && nt.Address == addr {
+			if dexeth.IsCustomTokenID(tokenID) {
+				return tokenID, nil
+			}
+			return 0, fmt.Errorf("token %s is already supported as %s", addr, dex.BipIDSymbol(tokenID))
+		}
+	}
+	ct, err := w.customTokenMetadata(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	tokenID, err := w.registerCustomToken(ct)
+	if err != nil {
+		return 0, err
+	}
+	if err := w.txDB.storeCustomToken(ct); err != nil {
+		return 0, fmt.Errorf("error saving custom token: %w", err)
+	}
+	w.log.Infof("Added custom token %s (%s) at %s with asset ID %d", ct.Name, ct.Symbol, addr, tokenID)
+	return tokenID, nil
+}
+
+// customTokenMetadata queries the token contract for the token's name, symbol,
+// and decimals.
+func (w *assetWallet) customTokenMetadata(ctx context.Context, addr common.Address) (*customToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, onChainDataFetchTimeout)
+	defer cancel()
+	cb := w.node.contractBackend()
+	code, err := cb.CodeAt(ctx, addr, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting contract code: %w", err)
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("no contract at %s", addr)
+	}
+	c := bind.NewBoundContract(addr, *erc20.ERC20MetadataABI, cb, cb, cb)
+	callOpts := &bind.CallOpts{From: w.addr, Context: ctx}
+	call := func(method string) (any, error) {
+		var out []any
+		if err := c.Call(callOpts, &out, method); err != nil {
+			return nil, fmt.Errorf("error calling %s: %w", method, err)
+		}
+		if len(out) != 1 {
+			return nil, fmt.Errorf("%s returned %d values", method, len(out))
+		}
+		return out[0], nil
+	}
+	ct := &customToken{Address: addr}
+	var ok bool
+	v, err := call("name")
+	if err != nil {
+		return nil, err
+	}
+	if ct.Name, ok = v.(string); !ok {
+		return nil, fmt.Errorf("unexpected name type %T", v)
+	}
+	if v, err = call("symbol"); err != nil {
+		return nil, err
+	}
+	if ct.Symbol, ok = v.(string); !ok {
+		return nil, fmt.Errorf("unexpected symbol type %T", v)
+	}
+	if v, err = call("decimals"); err != nil {
+		return nil, err
+	}
+	if ct.Decimals, ok = v.(uint8); !ok {
+		return nil, fmt.Errorf("unexpected decimals type %T", v)
+	}
+	ct.Name = strings.TrimSpace(ct.Name)
+	ct.Symbol = strings.TrimSpace(ct.Symbol)
+	if ct.Symbol == "" || strings.ContainsAny(ct.Symbol, ". ") {
+		return nil, fmt.Errorf("unsupported token symbol %q", ct.Symbol)
+	}
+	return ct, nil
+}
@@ -91,6 +91,7 @@ const (
 
 	providersKey = "providers"
 	relayerKey   = "relayer"
+	quorumKey    = "rpcquorum"
 
 	// onChainDataFetchTimeout is the max amount of time allocated to fetching
 	// on-chain data. Testing on testnet has shown spikes up to 2.5 seconds
@@ -157,6 +158,15 @@ var (
 				"a balance on the chain, because they cost more than regular ones.",
 			DefaultValue: "",
 		},
+		{
+			Key:         quorumKey,
+			DisplayName: "Provider Quorum",
+			Description: "The number of RPC providers that must agree on swap " +
+				"states, transaction receipts, and account nonces before they " +
+				"are accepted. At least this many providers must be configured. " +
+				"Providers that disagree are reported. Use 0 or 1 to disable.",
+			DefaultValue: "0",
+		},
 	}
 	// WalletInfo defines some general information about a Ethereum wallet.
 	WalletInfo = asset.WalletInfo{
@@ -233,6 +243,16 @@ func safeConfsBig(tip uint64, blockNum *big.Int) uint64 {
 // WalletConfig are wallet-level configuration settings.
 type WalletConfig struct {
 	GasFeeLimit uint64 `ini:"gasfeelimit"`
+	Quorum      uint32 `ini:"rpcquorum"`
+}
+
+// checkQuorum checks that there are enough providers for the quorum.
+func checkQuorum(quorum uint32, endpoints []string) error {
+	if quorum > 1 && int(quorum) > len(endpoints) {
+		return fmt.Errorf("provider quorum of %d requires at least %d providers, but only %d are configured",
+			quorum, quorum, len(endpoints))
+	}
+	return nil
 }
 
 // parseWalletConfig parses the settings map into a *WalletConfig.
@@ -414,6 +434,8 @@ var _ asset.LiveReconfigurer = (*ETHWallet)(nil)
 var _ asset.LiveReconfigurer = (*TokenWallet)(nil)
 var _ asset.TxFeeEstimator = (*ETHWallet)(nil)
 var _ asset.TxFeeEstimator = (*TokenWallet)(nil)
+var _ asset.BatchSender = (*ETHWallet)(nil)
+var _ asset.BatchSender = (*TokenWallet)(nil)
 var _ asset.DynamicSwapper = (*ETHWallet)(nil)
 var _ asset.DynamicSwapper = (*TokenWallet)(nil)
 var _ asset.Authenticator = (*ETHWallet)(nil)
@@ -442,11 +464,14 @@ type baseWallet struct {
 	multiBalanceAddress  common.Address
 	multiBalanceContract *multibal.MultiBalanceV0
 
+	// bondContractAddr is the address of the fidelity bond contract, or the
+	// zero address if bonds are not supported on the chain.
+	bondContractAddr common.Address
+
 	baseChainID  uint32
 	chainCfg     *params.ChainConfig
 	chainID      int64
 	compat       *CompatibilityData
-	tokens       map[uint32]*dexeth.Token
 	maxTxFeeGwei uint64
 	isOpStack    bool
 
@@ -460,6 +485,10 @@ type baseWallet struct {
 
 	gasFeeLimitV uint64 // atomic
 
+	// tokens are the built-in tokens and any user-defined tokens.
+	tokensMtx sync.RWMutex
+	tokens    map[uint32]*dexeth.Token
+
 	walletsMtx sync.RWMutex
 	wallets    map[uint32]*assetWallet
 
@@ -519,6 +548,9 @@ type assetWallet struct {
 		refundReserves     uint64
 	}
 
+	// bondReserves is the amount that is reserved for bond maintenance.
+	bondReserves atomic.Uint64
+
 	findRedemptionMtx  sync.RWMutex
 	findRedemptionReqs map[string]*findRedemptionRequest
 
@@ -738,7 +770,7 @@ func (w *ETHWallet) TestContractGas(contractVer uint32, maxSwaps int, tokenAsset
 
 	// Test each token.
 	for _, tokenAssetID := range tokenAssetIDs {
-		token := w.tokens[tokenAssetID]
+		token := w.token(tokenAssetID)
 		if token == nil {
 			results = append(results, &asset.GasTestResult{
 				AssetID: tokenAssetID,
@@ -1145,7 +1177,7 @@ func testContractGasForAsset(
 		}
 		defer os.RemoveAll(walletDir)
 
-		token := w.tokens[assetID]
+		token := w.token(assetID)
 		if token == nil {
 			return nil, fmt.Errorf("token %d not found on wallet", assetID)
 		}
@@ -1775,6 +1807,7 @@ func newWallet(assetCFG *asset.WalletConfig, logger dex.Logger, net dex.Network)
 		Logger:             logger,
 		BaseChainContracts: contracts,
 		MultiBalAddress:    dexeth.MultiBalanceAddresses[net],
+		BondContractAddr:   dexeth.BondContractAddresses[net],
 		WalletInfo:         WalletInfo,
 		Net:                net,
 		DefaultProviders:   defaultProviders,
@@ -1795,6 +1828,7 @@ type EVMWalletConfig struct {
 	BaseChainContracts map[uint32]common.Address
 	DefaultProviders   []string
 	MultiBalAddress    common.Address // If empty, separate calls for N tokens + 1
+	BondContractAddr   common.Address // If empty, bonds are not supported
 	WalletInfo         asset.WalletInfo
 	Net                dex.Network
 	// MaxTxFeeGwei is the absolute maximum fees we will allow for a single tx.
@@ -1836,7 +1870,7 @@ func NewEVMWallet(cfg *EVMWalletConfig) (w *ETHWallet, err error) {
 		chainCfg:            cfg.ChainCfg,
 		chainID:             chainID,
 		compat:              cfg.CompatData,
-		tokens:              cfg.Tokens,
+		tokens:              make(map[uint32]*dexeth.Token, len(cfg.Tokens)),
 		log:                 cfg.Logger,
 		dir:                 cfg.AssetCfg.DataDir,
 		walletType:          cfg.AssetCfg.Type,
@@ -1846,9 +1880,13 @@ func NewEVMWallet(cfg *EVMWalletConfig) (w *ETHWallet, err error) {
 		gasFeeLimitV:        gasFeeLimit,
 		wallets:             make(map[uint32]*assetWallet),
 		multiBalanceAddress: cfg.MultiBalAddress,
+		bondContractAddr:    cfg.BondContractAddr,
 		maxTxFeeGwei:        cfg.MaxTxFeeGwei,
 		isOpStack:           cfg.IsOpStack,
 	}
+	for tokenID, token := range cfg.Tokens {
+		eth.tokens[tokenID] = token
+	}
 
 	var maxSwapGas, maxRedeemGas uint64
 	for _, gases := range cfg.VersionedGases {
@@ -1884,6 +1922,13 @@ func NewEVMWallet(cfg *EVMWalletConfig) (w *ETHWallet, err error) {
 		assetID: aw,
 	}
 
+	// User-defined tokens must be registered before core loads the token
+	// wallets. The database could be locked if the wallet is being reloaded,
+	// in which case the tokens are already registered.
+	if err := aw.loadCustomTokens(); err != nil {
+		aw.log.Warnf("Error loading custom tokens: %v", err)
+	}
+
 	return &ETHWallet{
 		assetWallet:      aw,
 		defaultProviders: cfg.DefaultProviders,
@@ -1944,11 +1989,22 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 		if providerDef, found := w.settings[providersKey]; found && len(providerDef) > 0 {
 			endpoints = strings.Split(providerDef, " ")
 		}
+		walletCfg, err := parseWalletConfig(w.settings)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkQuorum(walletCfg.Quorum, endpoints); err != nil {
+			return nil, err
+		}
 		rpcCl, err := newMultiRPCClient(w.dir, endpoints, w.log.SubLogger("RPC"), w.chainCfg, w.finalizeConfs, w.net, w.torProxy)
 		if err != nil {
 			return nil, err
 		}
 		rpcCl.finalizeConfs = w.finalizeConfs
+		rpcCl.setQuorum(walletCfg.Quorum)
+		if w.emit != nil {
+			rpcCl.warn = w.emit.Warning
+		}
 		cl = rpcCl
 	default:
 		return nil, fmt.Errorf("unknown wallet type %q", w.walletType)
@@ -1988,7 +2044,7 @@ func (w *ETHWallet) Connect(ctx context.Context) (_ *sync.WaitGroup, err error)
 		}
 	}
 
-	w.txDB, err = NewTxDB(filepath.Join(w.dir, "txhistorydb-lexi"), w.log.SubLogger("TXDB"), w.baseChainID)
+	w.txDB, err = NewTxDB(w.txDBPath(), w.log.SubLogger("TXDB"), w.baseChainID)
 	if err != nil {
 		return nil, err
 	}
@@ -2359,9 +2415,14 @@ func (w *ETHWallet) Reconfigure(ctx context.Context, cfg *asset.WalletConfig, cu
 			defaultProviders = true
 		}
 
+		if err := checkQuorum(walletCfg.Quorum, endpoints); err != nil {
+			return false, err
+		}
+
 		if err := rpc.reconfigure(ctx, endpoints, w.compat, walletDir, defaultProviders); err != nil {
 			return false, err
 		}
+		rpc.setQuorum(walletCfg.Quorum)
 	}
 
 	// Only update the relayer if the URL has changed. If the relayer's health
@@ -2434,6 +2495,7 @@ type genTxResult struct {
 	amt       uint64
 	recipient *string
 	bridge    *genBridgeTxResult
+	bondInfo  *asset.BondTxInfo
 }
 
 // transactionGenerator is an action that uses a nonce and returns a tx, it's
@@ -2570,7 +2632,7 @@ func parseTokenWalletConfig(settings map[string]string) (cfg *tokenWalletConfig,
 // to do, except check that the token exists.
 func (w *baseWallet) CreateTokenWallet(tokenID uint32, _ map[string]string) error {
 	// Just check that the token exists for now.
-	if w.tokens[tokenID] == nil {
+	if w.token(tokenID) == nil {
 		return fmt.Errorf("token not found for asset ID %d", tokenID)
 	}
 	return nil
@@ -2792,8 +2854,8 @@ var _ asset.Bridger = (*assetWallet)(nil)
 
 // OpenTokenWallet creates a new TokenWallet.
 func (w *ETHWallet) OpenTokenWallet(tokenCfg *asset.TokenConfig) (asset.Wallet, error) {
-	token, found := w.tokens[tokenCfg.AssetID]
-	if !found {
+	token := w.token(tokenCfg.AssetID)
+	if token == nil {
 		return nil, fmt.Errorf("token %d not found", tokenCfg.AssetID)
 	}
 
@@ -2977,11 +3039,23 @@ func (w *assetWallet) balance() (*asset.Balance, error) {
 		available = w.atomize(bal.Current) - locked - w.atomize(bal.PendingOut)
 	}
 
-	return &asset.Balance{
+	b := &asset.Balance{
 		Available: available,
 		Locked:    locked,
 		Immature:  w.atomize(bal.PendingIn),
-	}, nil
+	}
+
+	if reserves := w.bondReserves.Load(); reserves > 0 {
+		if reserves > b.Available {
+			b.ReservesDeficit = reserves - b.Available
+			reserves = b.Available
+		}
+		b.BondReserves = reserves
+		b.Available -= reserves
+		b.Locked += reserves
+	}
+
+	return b, nil
 }
 
 // MaxOrder generates information about the maximum order size and associated
@@ -6488,6 +6562,152 @@ func (w *TokenWallet) Send(addr string, value, _ uint64) (asset.Coin, error) {
 	return &coin{txHash: tx.Hash(), value: value}, nil
 }
 
+// validateBatch checks the batch recipients and returns the total value.
+func validateBatch(recipients []*asset.BatchRecipient) (total uint64, err error) {
+	if len(recipients) == 0 {
+		return 0, errors.New("no recipients")
+	}
+	for i, r := range recipients {
+		if err := isValidSend(r.Address, r.Value, false); err != nil {
+			return 0, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		total += r.Value
+	}
+	return total, nil
+}
+
+// sendBatch sends a separate transaction to each recipient, in order, so the
+// nonces are sequential. This is not a batched transaction. Each send pays its
+// own fee. If an error is encountered, the IDs of the transactions already
+// broadcast are returned with the error.
+func sendBatch(recipients []*asset.BatchRecipient, send func(addr common.Address, amt uint64) (*types.Transaction, error)) ([]string, error) {
+	txIDs := make([]string, 0, len(recipients))
+	for i, r := range recipients {
+		tx, err := send(common.HexToAddress(r.Address), r.Value)
+		if err != nil {
+			return txIDs, fmt.Errorf("error sending to recipient %d (%s): %w", i, r.Address, err)
+		}
+		txIDs = append(txIDs, tx.Hash().String())
+	}
+	return txIDs, nil
+}
+
+// estimateBatchFees sums the TxFeeEstimator estimates of the sends to each
+// recipient, since a batch is sent as one transaction per recipient.
+func estimateBatchFees(est asset.TxFeeEstimator, recipients []*asset.BatchRecipient) (uint64, error) {
+	var fees uint64
+	for i, r := range recipients {
+		fee, _, err := est.EstimateSendTxFee(r.Address, r.Value, 0, false, false)
+		if err != nil {
+			return 0, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		fees += fee
+	}
+	return fees, nil
+}
+
+// canSendBatch ensures that the wallet has enough to cover the batch, with a
+// max fee for each transaction.
+func (w *ETHWallet) canSendBatch(recipients []*asset.BatchRecipient, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
+	total, err := validateBatch(recipients)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	maxFee, maxFeeRate, tipRate, err = w.canSend(total, false, isPreEstimate)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	maxFee *= uint64(len(recipients))
+	bal, err := w.Balance()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if bal.Available < total+maxFee {
+		return 0, nil, nil, fmt.Errorf("available funds %d gwei cannot cover batch: need %d gwei + %d gwei max fees",
+			bal.Available, total, maxFee)
+	}
+	return maxFee, maxFeeRate, tipRate, nil
+}
+
+// SendBatch sends a separate transaction to each recipient. It is not a single
+// batched transaction, and each transaction pays its own fee and is recorded
+// in the history separately. The provided fee rate is ignored since all sends
+// will use an internally derived fee rate. Part of the asset.BatchSender
+// interface.
+func (w *ETHWallet) SendBatch(recipients []*asset.BatchRecipient, _ uint64) ([]string, error) {
+	_, maxFeeRate, tipRate, err := w.canSendBatch(recipients, false)
+	if err != nil {
+		return nil, err
+	}
+	return sendBatch(recipients, func(addr common.Address, amt uint64) (*types.Transaction, error) {
+		return w.sendToAddr(addr, amt, maxFeeRate, tipRate)
+	})
+}
+
+// EstimateBatchSendTxFee returns the sum of the max fees of the transactions
+// sent to each recipient, as estimated by EstimateSendTxFee. The provided fee
+// rate is ignored. Part of the asset.BatchSender interface.
+func (w *ETHWallet) EstimateBatchSendTxFee(recipients []*asset.BatchRecipient, _ uint64) (uint64, error) {
+	if _, _, _, err := w.canSendBatch(recipients, true); err != nil {
+		return 0, err
+	}
+	return estimateBatchFees(w, recipients)
+}
+
+// canSendBatch ensures that the wallet has enough tokens to cover the batch
+// and that the parent wallet can cover a max fee for each transaction.
+func (w *TokenWallet) canSendBatch(recipients []*asset.BatchRecipient, isPreEstimate bool) (maxFee uint64, maxFeeRate, tipRate *big.Int, err error) {
+	total, err := validateBatch(recipients)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	maxFee, maxFeeRate, tipRate, err = w.canSend(total, false, isPreEstimate)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	maxFee *= uint64(len(recipients))
+	bal, err := w.Balance()
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	if bal.Available < total {
+		return 0, nil, nil, fmt.Errorf("not enough tokens: have %[1]d %[3]s need %[2]d %[3]s", bal.Available, total, w.ui.AtomicUnit)
+	}
+	ethBal, err := w.parent.Balance()
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("error getting base chain balance: %w", err)
+	}
+	if ethBal.Available < maxFee {
+		return 0, nil, nil, fmt.Errorf("insufficient balance to cover token transfer fees. %d < %d",
+			ethBal.Available, maxFee)
+	}
+	return maxFee, maxFeeRate, tipRate, nil
+}
+
+// SendBatch sends a separate token transfer to each recipient. It is not a
+// single batched transaction, and each transfer pays its own fee, taken from
+// the parent wallet. The provided fee rate is ignored since all sends will use
+// an internally derived fee rate. Part of the asset.BatchSender interface.
+func (w *TokenWallet) SendBatch(recipients []*asset.BatchRecipient, _ uint64) ([]string, error) {
+	_, maxFeeRate, tipRate, err := w.canSendBatch(recipients, false)
+	if err != nil {
+		return nil, err
+	}
+	return sendBatch(recipients, func(addr common.Address, amt uint64) (*types.Transaction, error) {
+		return w.sendToAddr(addr, amt, maxFeeRate, tipRate)
+	})
+}
+
+// EstimateBatchSendTxFee returns the sum of the max fees of the token
+// transfers to each recipient, as estimated by EstimateSendTxFee. The
+// provided fee rate is ignored. Part of the asset.BatchSender interface.
+func (w *TokenWallet) EstimateBatchSendTxFee(recipients []*asset.BatchRecipient, _ uint64) (uint64, error) {
+	if _, _, _, err := w.canSendBatch(recipients, true); err != nil {
+		return 0, err
+	}
+	return estimateBatchFees(w, recipients)
+}
+
 // ValidateSecret checks that the secret satisfies the contract.
 func (*baseWallet) ValidateSecret(secret, secretHash []byte) bool {
 	h := sha256.Sum256(secret)
@@ -6908,6 +7128,36 @@ func (eth *baseWallet) baseAssetWallet() (*assetWallet, error) {
 	return wallet, nil
 }
 
+var _ asset.PeerManager = (*ETHWallet)(nil)
+
+// Peers lists the wallet's RPC providers with their health. Part of the
+// asset.PeerManager interface.
+func (w *ETHWallet) Peers() ([]*asset.WalletPeer, error) {
+	rpc, is := w.node.(*multiRPCClient)
+	if !is {
+		return nil, errors.New("wallet is not using RPC providers")
+	}
+	source := asset.UserAdded
+	w.settingsMtx.RLock()
+	if len(w.settings[providersKey]) == 0 {
+		source = asset.WalletDefault
+	}
+	w.settingsMtx.RUnlock()
+	return rpc.peers(source), nil
+}
+
+// AddPeer is not supported. RPC providers are set with the wallet settings.
+// Part of the asset.PeerManager interface.
+func (w *ETHWallet) AddPeer(string) error {
+	return errors.New("RPC providers can only be changed in the wallet settings")
+}
+
+// RemovePeer is not supported. RPC providers are set with the wallet settings.
+// Part of the asset.PeerManager interface.
+func (w *ETHWallet) RemovePeer(string) error {
+	return errors.New("RPC providers can only be changed in the wallet settings")
+}
+
 func (eth *ETHWallet) checkPeers() {
 	numPeers := eth.node.peerCount()
 
@@ -7390,7 +7640,7 @@ func (w *assetWallet) getConfirmedBalance() (*big.Int, error) {
 		0: w.baseChainID,
 	}
 	i := 1
-	for assetID, tkn := range w.tokens {
+	for assetID, tkn := range w.tokenMap() {
 		netToken := tkn.NetTokens[w.net]
 		if netToken == nil || netToken.Address == (common.Address{}) {
 			continue
@@ -7732,8 +7982,8 @@ func (w *assetWallet) estimateRefundGas(ctx context.Context, locator []byte, con
 
 // loadContractors prepares the token contractors and add them to the map.
 func (w *assetWallet) loadContractors(parent *assetWallet) error {
-	token, found := w.tokens[w.assetID]
-	if !found {
+	token := w.token(w.assetID)
+	if token == nil {
 		return fmt.Errorf("token %d not found", w.assetID)
 	}
 	netToken, found := token.NetTokens[w.net]
@@ -8884,6 +9134,7 @@ func (w *baseWallet) extendAndStoreTx(genTxResult *genTxResult, tokenAssetID *ui
 			Fees:      fees, // updated later with actual receipt fees
 			TokenID:   tokenAssetID,
 			Recipient: genTxResult.recipient,
+			BondInfo:  genTxResult.bondInfo,
 			AdditionalData: map[string]string{
 				txHistoryNonceKey: strconv.FormatUint(nonce, 10),
 			},
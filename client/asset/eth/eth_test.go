@@ -671,6 +671,7 @@ type tTxDB struct {
 	txs            map[common.Hash]*extendedWalletTx // for looking up multiple txs by hash
 	getTxErr       error
 	pendingBridges []*extendedWalletTx
+	customTokens   []*customToken
 }
 
 var _ txDB = (*tTxDB)(nil)
@@ -716,6 +717,13 @@ func (db *tTxDB) getPendingBridges(tokenID *uint32) ([]*extendedWalletTx, error)
 func (db *tTxDB) getBridgeCompletions(initiationTxID string) ([]*extendedWalletTx, error) {
 	return []*extendedWalletTx{db.txToGet}, db.getTxErr
 }
+func (db *tTxDB) storeCustomToken(ct *customToken) error {
+	db.customTokens = append(db.customTokens, ct)
+	return nil
+}
+func (db *tTxDB) getCustomTokens() ([]*customToken, error) {
+	return db.customTokens, nil
+}
 
 // func TestCheckUnconfirmedTxs(t *testing.T) {
 // 	const tipHeight = 50
@@ -5784,6 +5792,90 @@ func testSend(t *testing.T, assetID uint32) {
 	}
 }
 
+func TestSendBatch(t *testing.T) {
+	t.Run("eth", func(t *testing.T) { testSendBatch(t, BipID) })
+	t.Run("token", func(t *testing.T) { testSendBatch(t, usdcEthID) })
+}
+
+func testSendBatch(t *testing.T, assetID uint32) {
+	w, eth, node, shutdown := tassetWallet(assetID)
+	defer shutdown()
+
+	tx := tTx(0, 0, 0, &testAddressA, nil, 21000)
+	node.sendTxTx = tx
+	node.tokenContractor.transferTx = tx
+
+	maxFeeRate, _, _ := eth.recommendedMaxFeeRate(eth.ctx)
+	ethFees := dexeth.WeiToGwei(maxFeeRate) * defaultSendGasLimit
+	tokenFees := dexeth.WeiToGwei(maxFeeRate) * tokenGasesV1.Transfer
+
+	const val = 10e9
+	const testAddr = "dd93b447f7eBCA361805eBe056259853F3912E04"
+	recipients := []*asset.BatchRecipient{
+		{Address: testAddr, Value: val},
+		{Address: testAddr, Value: val},
+		{Address: testAddr, Value: val},
+	}
+	n := uint64(len(recipients))
+	setBalance := func(feeAdj uint64) {
+		if assetID == BipID {
+			node.bal = dexeth.GweiToWei(val*n + ethFees*n - feeAdj)
+		} else {
+			node.tokenContractor.bal = dexeth.GweiToWei(val * n)
+			node.bal = dexeth.GweiToWei(tokenFees*n - feeAdj)
+		}
+	}
+
+	sender := w.(asset.BatchSender)
+
+	setBalance(0)
+	txIDs, err := sender.SendBatch(recipients, 0)
+	if err != nil {
+		t.Fatalf("SendBatch error: %v", err)
+	}
+	if len(txIDs) != len(recipients) {
+		t.Fatalf("expected %d tx IDs, got %d", len(recipients), len(txIDs))
+	}
+
+	// Not enough to cover the fees for every tx.
+	setBalance(1)
+	if _, err := sender.SendBatch(recipients, 0); err == nil {
+		t.Fatalf("no error for insufficient fee funds")
+	}
+
+	if _, err := sender.SendBatch([]*asset.BatchRecipient{{Address: "0xbad", Value: val}}, 0); err == nil {
+		t.Fatalf("no error for invalid address")
+	}
+	if _, err := sender.SendBatch(nil, 0); err == nil {
+		t.Fatalf("no error for no recipients")
+	}
+
+	// The estimate is the sum of the estimates of the individual sends.
+	setBalance(0)
+	fee, _, err := w.(asset.TxFeeEstimator).EstimateSendTxFee(testAddr, val, 0, false, false)
+	if err != nil {
+		t.Fatalf("EstimateSendTxFee error: %v", err)
+	}
+	batchFee, err := sender.EstimateBatchSendTxFee(recipients, 0)
+	if err != nil {
+		t.Fatalf("EstimateBatchSendTxFee error: %v", err)
+	}
+	if batchFee != n*fee {
+		t.Fatalf("wrong batch fee estimate %d, wanted %d", batchFee, n*fee)
+	}
+
+	// A failed send reports the txs already sent.
+	node.sendTxErr = errors.New("test error")
+	node.tokenContractor.transferErr = node.sendTxErr
+	txIDs, err = sender.SendBatch(recipients, 0)
+	if err == nil {
+		t.Fatalf("no error for send error")
+	}
+	if len(txIDs) != 0 {
+		t.Fatalf("expected no tx IDs, got %d", len(txIDs))
+	}
+}
+
 func TestConfirmTransaction(t *testing.T) {
 	t.Run("eth", func(t *testing.T) { testConfirmTransaction(t, BipID) })
 	t.Run("token", func(t *testing.T) { testConfirmTransaction(t, usdcEthID) })
@@ -6576,6 +6668,128 @@ func TestFreshProviderList(t *testing.T) {
 	}
 }
 
+func TestScoreSortedProviders(t *testing.T) {
+	fast, slow, flaky, fresh := &provider{host: "fast"}, &provider{host: "slow"}, &provider{host: "flaky"}, &provider{host: "fresh"}
+	for i := 0; i < 10; i++ {
+		fast.recordRequest(time.Millisecond*50, nil)
+		slow.recordRequest(time.Second*3, nil)
+		var err error
+		if i%2 == 0 {
+			err = errors.New("test error")
+		}
+		flaky.recordRequest(time.Millisecond*50, err)
+	}
+	// Not-found errors don't count against the provider.
+	fast.recordRequest(time.Millisecond*50, asset.CoinNotFoundError)
+	node := &multiRPCClient{providers: []*provider{slow, flaky, fast, fresh}}
+	providers := node.scoreSortedProviders()
+	for i, expHost := range []string{"fresh", "fast", "flaky", "slow"} {
+		if providers[i].host != expHost {
+			t.Fatalf("expected %s at position %d, got %s", expHost, i, providers[i].host)
+		}
+	}
+	if h := flaky.health(); h.ErrorRate == 0 || h.LastError != "test error" || h.Requests != 10 {
+		t.Fatalf("wrong flaky provider health %+v", h)
+	}
+	if h := fast.health(); h.ErrorRate != 0 || h.LastError != "" {
+		t.Fatalf("not-found error counted %+v", h)
+	}
+	// A mismatch demotes the provider.
+	fast.recordMismatch()
+	fast.recordMismatch()
+	if providers = node.scoreSortedProviders(); providers[1].host != "flaky" {
+		t.Fatalf("provider with mismatches not demoted")
+	}
+	// The mismatch penalty decays.
+	fast.stats.mismatchStamp = time.Now().Add(-mismatchHalfLife * 10)
+	if providers = node.scoreSortedProviders(); providers[1].host != "fast" {
+		t.Fatalf("mismatch penalty did not decay")
+	}
+	if h := fast.health(); h.Mismatches != 2 {
+		t.Fatalf("wrong mismatch count %d", h.Mismatches)
+	}
+
+	// Freshness sorting uses the score only to break ties.
+	stamp := time.Now()
+	for _, p := range node.providers {
+		p.tip.headerStamp = stamp
+	}
+	slow.tip.headerStamp = stamp.Add(-time.Second)
+	providers = node.freshnessSortedProviders()
+	for i, expHost := range []string{"slow", "fresh", "fast", "flaky"} {
+		if providers[i].host != expHost {
+			t.Fatalf("expected %s at freshness position %d, got %s", expHost, i, providers[i].host)
+		}
+	}
+}
+
+func TestWithQuorum(t *testing.T) {
+	a, b, c := &provider{host: "a"}, &provider{host: "b"}, &provider{host: "c"}
+	var warnings []string
+	node := &multiRPCClient{
+		log:       tLogger,
+		providers: []*provider{a, b, c},
+		warn:      func(msg string) { warnings = append(warnings, msg) },
+	}
+	node.setQuorum(2)
+
+	read := func(results map[*provider]string) (string, error) {
+		return withQuorum(context.Background(), node, "test data", func(ctx context.Context, p *provider) (string, string, error) {
+			r := results[p]
+			switch r {
+			case "not found":
+				return "", "", asset.CoinNotFoundError
+			case "error":
+				return "", "", errors.New("test error")
+			}
+			return r, r, nil
+		})
+	}
+
+	// Agreement.
+	if v, err := read(map[*provider]string{a: "x", b: "x", c: "x"}); err != nil || v != "x" {
+		t.Fatalf("wrong result for agreement %q, %v", v, err)
+	}
+	// An error doesn't count toward the quorum.
+	if v, err := read(map[*provider]string{a: "error", b: "x", c: "x"}); err != nil || v != "x" {
+		t.Fatalf("wrong result with one error %q, %v", v, err)
+	}
+	if _, err := read(map[*provider]string{a: "error", b: "error", c: "x"}); err == nil {
+		t.Fatal("no error without a quorum")
+	}
+	// Not-found is a result, but not a mismatch.
+	if _, err := read(map[*provider]string{a: "not found", b: "x", c: "not found"}); !errors.Is(err, asset.CoinNotFoundError) {
+		t.Fatalf("expected CoinNotFoundError, got %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings %v", warnings)
+	}
+	// A mismatch is reported, and the odd one out is penalized. Make b the
+	// preferred provider, so that it's sure to be asked.
+	node.lastProvider.provider, node.lastProvider.stamp = b, time.Now()
+	if v, err := read(map[*provider]string{a: "x", b: "y", c: "x"}); err != nil || v != "x" {
+		t.Fatalf("wrong result for mismatch %q, %v", v, err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	if a.health().Mismatches != 0 || b.health().Mismatches != 1 || c.health().Mismatches != 0 {
+		t.Fatal("wrong provider penalized")
+	}
+	// No agreement at all is an error. Warnings are rate-limited.
+	if _, err := read(map[*provider]string{a: "x", b: "y", c: "z"}); err == nil {
+		t.Fatal("no error for total disagreement")
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("warnings not rate-limited")
+	}
+	// Quorum disabled.
+	node.setQuorum(0)
+	if v, err := read(map[*provider]string{a: "y", b: "x", c: "z"}); err != nil || v == "" {
+		t.Fatalf("wrong result with quorum disabled %q, %v", v, err)
+	}
+}
+
 type mockBridge struct {
 	getCompletionDataFunc            func(ctx context.Context, txID string) ([]byte, error)
 	getCompletionDataCalled          chan struct{}
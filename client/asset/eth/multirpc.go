@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"math/rand"
 	"net"
@@ -66,6 +67,18 @@ const (
 	// TODO: Keep a file mapping provider URL to retrieved chain IDs, and skip
 	// the eth_chainId request after verified for the first time?
 	defaultRequestTimeout = time.Second * 10
+	// statsSmoothing is the weight given to the newest sample in the moving
+	// averages of a provider's latency and error rate.
+	statsSmoothing = 0.1
+	// mismatchWarningInterval is the minimum time between wallet warnings
+	// about providers returning conflicting data.
+	mismatchWarningInterval = time.Minute * 10
+	// mismatchHalfLife is the time it takes for a provider's mismatch penalty
+	// to halve.
+	mismatchHalfLife = time.Hour
+	// maxMismatchPenalty caps the mismatch penalty. Each point of penalty
+	// halves the provider's score.
+	maxMismatchPenalty = 10
 )
 
 var (
@@ -123,6 +136,21 @@ type provider struct {
 		failCount    int
 		wsHeaderSeen atomic.Bool
 	}
+
+	// stats tracks the provider's performance, which is used to order the
+	// providers.
+	stats struct {
+		sync.Mutex
+		latency    float64 // moving average, seconds
+		errRate    float64 // moving average
+		requests   uint64
+		mismatches uint64
+		// mismatchPenalty decays with mismatchHalfLife from
+		// mismatchStamp, the time of the last mismatch.
+		mismatchPenalty float64
+		mismatchStamp   time.Time
+		lastErr         string
+	}
 }
 
 // String returns the provider host name.
@@ -184,6 +212,82 @@ func (p *provider) failed() bool {
 	return p.tip.failCount > brickedFailCount || time.Since(p.tip.failStamp) < failQuarantine
 }
 
+// recordRequest updates the provider's stats with the outcome of a request.
+// Not-found errors are the expected result of many requests, and are not
+// counted as errors.
+func (p *provider) recordRequest(latency time.Duration, err error) {
+	var failed float64
+	if err != nil && !isNotFoundError(err) && !errors.Is(err, asset.CoinNotFoundError) {
+		failed = 1
+	}
+	p.stats.Lock()
+	defer p.stats.Unlock()
+	if p.stats.requests == 0 {
+		p.stats.latency, p.stats.errRate = latency.Seconds(), failed
+	} else {
+		p.stats.latency += statsSmoothing * (latency.Seconds() - p.stats.latency)
+		p.stats.errRate += statsSmoothing * (failed - p.stats.errRate)
+	}
+	p.stats.requests++
+	if failed > 0 {
+		p.stats.lastErr = err.Error()
+	}
+}
+
+// recordMismatch records that the provider returned data that didn't match
+// the data returned by other providers.
+func (p *provider) recordMismatch() {
+	p.stats.Lock()
+	defer p.stats.Unlock()
+	now := time.Now()
+	p.stats.mismatches++
+	p.stats.mismatchPenalty = p.mismatchPenaltyLocked(now) + 1
+	p.stats.mismatchStamp = now
+}
+
+// mismatchPenaltyLocked is the provider's mismatch penalty, decayed to the
+// specified time. The stats mutex must be held.
+func (p *provider) mismatchPenaltyLocked(now time.Time) float64 {
+	if p.stats.mismatchPenalty == 0 {
+		return 0
+	}
+	halfLives := now.Sub(p.stats.mismatchStamp).Seconds() / mismatchHalfLife.Seconds()
+	return p.stats.mismatchPenalty * math.Pow(0.5, halfLives)
+}
+
+// score rates the provider from 0 to 100 based on its latency, error rate,
+// and any recent mismatches. A provider that hasn't been used yet gets a
+// perfect score, so that it is tried.
+func (p *provider) score() float64 {
+	p.stats.Lock()
+	defer p.stats.Unlock()
+	return p.scoreLocked()
+}
+
+func (p *provider) scoreLocked() float64 {
+	if p.stats.requests == 0 {
+		return 100
+	}
+	score := 100 * (1 - p.stats.errRate) / (1 + p.stats.latency)
+	// Each point of mismatch penalty halves the score, down to a floor.
+	penalty := math.Min(p.mismatchPenaltyLocked(time.Now()), maxMismatchPenalty)
+	return score / math.Pow(2, penalty)
+}
+
+// health generates an *asset.PeerHealth from the provider's stats.
+func (p *provider) health() *asset.PeerHealth {
+	p.stats.Lock()
+	defer p.stats.Unlock()
+	return &asset.PeerHealth{
+		Score:      p.scoreLocked(),
+		LatencyMS:  uint64(p.stats.latency * 1000),
+		ErrorRate:  p.stats.errRate,
+		Requests:   p.stats.requests,
+		Mismatches: p.stats.mismatches,
+		LastError:  p.stats.lastErr,
+	}
+}
+
 // bestHeader get the best known header from the provider, cached if available,
 // otherwise a new RPC call is made.
 func (p *provider) bestHeader(ctx context.Context, log dex.Logger) (*types.Header, error) {
@@ -409,6 +513,14 @@ type multiRPCClient struct {
 		cache     map[common.Hash]*receiptRecord
 		lastClean time.Time
 	}
+
+	// quorum is the number of providers that must agree on the result of a
+	// security-critical read. A quorum of 0 or 1 disables quorum reads.
+	quorum atomic.Uint32
+	// warn is used to alert the user when providers disagree.
+	warn            func(msg string)
+	lastMismatchMtx sync.Mutex
+	lastMismatch    time.Time
 }
 
 var _ ethFetcher = (*multiRPCClient)(nil)
@@ -872,10 +984,17 @@ func (m *multiRPCClient) transactionReceipt(ctx context.Context, txHash common.H
 	if r = m.cachedReceipt(txHash); r != nil {
 		return r, nil
 	}
-	if err := m.withPreferred(ctx, func(ctx context.Context, p *provider) error {
-		r, err = p.ec.TransactionReceipt(ctx, txHash)
-		return err
-	}); err != nil {
+	r, err = withQuorum(ctx, m, "receipt for "+txHash.String(), func(ctx context.Context, p *provider) (*types.Receipt, string, error) {
+		r, err := p.ec.TransactionReceipt(ctx, txHash)
+		if err != nil {
+			if isNotFoundError(err) {
+				return nil, "", asset.CoinNotFoundError
+			}
+			return nil, "", err
+		}
+		return r, fmt.Sprintf("%d:%s:%s:%d", r.Status, r.BlockHash, r.BlockNumber, r.GasUsed), nil
+	})
+	if err != nil {
 		if isNotFoundError(err) {
 			return nil, asset.CoinNotFoundError
 		}
@@ -913,8 +1032,12 @@ func (m *multiRPCClient) transactionAndReceipt(ctx context.Context, txHash commo
 	return r, tx, err
 }
 
-// nonce gets the best next nonce for the account.
+// nonce gets the best next nonce for the account. With quorum reads enabled,
+// the confirmed nonce must be agreed upon by the quorum of providers.
 func (m *multiRPCClient) nonce(ctx context.Context) (confirmed, pending *big.Int, _ error) {
+	if m.quorum.Load() > 1 {
+		return m.quorumNonce(ctx)
+	}
 	confirmed, pending = new(big.Int), new(big.Int)
 	return confirmed, pending, m.withAll(ctx, func(ctx context.Context, p *provider) error {
 		confirmedAt, err := p.ec.NonceAt(ctx, m.creds.addr, nil)
@@ -929,6 +1052,35 @@ func (m *multiRPCClient) nonce(ctx context.Context) (confirmed, pending *big.Int
 	})
 }
 
+// quorumNonce gets the confirmed nonce at the best block from a quorum of
+// providers, so that providers at different heights don't disagree. The
+// pending nonce is the highest reported by any provider, but never less than
+// the confirmed nonce.
+func (m *multiRPCClient) quorumNonce(ctx context.Context) (confirmed, pending *big.Int, _ error) {
+	hdr, err := m.bestHeader(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting best header: %w", err)
+	}
+	n, err := withQuorum(ctx, m, "account nonce", func(ctx context.Context, p *provider) (uint64, string, error) {
+		n, err := p.ec.NonceAt(ctx, m.creds.addr, hdr.Number)
+		return n, strconv.FormatUint(n, 10), err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	confirmed, pending = new(big.Int).SetUint64(n), new(big.Int).SetUint64(n)
+	if err := m.withAll(ctx, func(ctx context.Context, p *provider) error {
+		pendingAt, err := p.ec.PendingNonceAt(ctx, m.creds.addr)
+		if err == nil && pending.Uint64() < pendingAt {
+			pending.SetUint64(pendingAt)
+		}
+		return err
+	}); err != nil {
+		return nil, nil, err
+	}
+	return confirmed, pending, nil
+}
+
 type rpcTransaction struct {
 	tx *types.Transaction
 	txExtraDetail
@@ -1061,7 +1213,9 @@ func (m *multiRPCClient) withOne(ctx context.Context, providers []*provider, f f
 	}
 	for _, p := range readyProviders {
 		ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		start := time.Now()
 		err := f(ctx, p)
+		p.recordRequest(time.Since(start), err)
 		cancel()
 		if err == nil {
 			return nil
@@ -1091,7 +1245,7 @@ func (m *multiRPCClient) withOne(ctx context.Context, providers []*provider, f f
 }
 
 // withAll runs the provider function against all known providers in order of
-// freshness, with any non-stale nonce provider first. This is similar to
+// score, with any non-stale nonce provider first. This is similar to
 // withPreferred, except that it does not stop after the first success. However,
 // if an acceptability filter indicates to "propagate" the error (hard stop), it
 // will not try all providers. withAll should only be used for actions that are
@@ -1112,7 +1266,9 @@ func (m *multiRPCClient) withAll(
 		}
 
 		ctx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		start := time.Now()
 		err := f(ctx, p)
+		p.recordRequest(time.Since(start), err)
 		cancel()
 		if err == nil {
 			atLeastOne = true // return nil err unless a later "propagated" error says to
@@ -1158,12 +1314,11 @@ func (m *multiRPCClient) withAll(
 	return nil
 }
 
-// withAny runs the provider function against known providers in random order
-// until one succeeds or all have failed.
+// withAny runs the provider function against known providers in order of
+// score until one succeeds or all have failed. Providers with equal scores are
+// tried in random order.
 func (m *multiRPCClient) withAny(ctx context.Context, f func(context.Context, *provider) error, acceptabilityFilters ...acceptabilityFilter) error {
-	providers := m.providerList()
-	shuffleProviders(providers)
-	return m.withOne(ctx, providers, f, acceptabilityFilters...)
+	return m.withOne(ctx, m.scoreSortedProviders(), f, acceptabilityFilters...)
 }
 
 // withFreshest runs the provider function against known providers in order of
@@ -1179,12 +1334,146 @@ func (m *multiRPCClient) withPreferred(ctx context.Context, f func(context.Conte
 	return m.withOne(ctx, m.nonceProviderList(), f, acceptabilityFilters...)
 }
 
+type quorumCtxKey struct{}
+
+// quorumContext marks the context so that contract calls made with it are
+// quorum reads. Contract calls for security-critical data, such as swap
+// states, should use a quorumContext.
+func quorumContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, quorumCtxKey{}, true)
+}
+
+func isQuorumContext(ctx context.Context) bool {
+	is, _ := ctx.Value(quorumCtxKey{}).(bool)
+	return is
+}
+
+// setQuorum sets the number of providers that must agree on the result of a
+// security-critical read. A quorum of 0 or 1 disables quorum reads.
+func (m *multiRPCClient) setQuorum(quorum uint32) {
+	m.quorum.Store(quorum)
+}
+
+// notFoundQuorumKey is the result key for a not-found error during a quorum
+// read.
+const notFoundQuorumKey = "not found"
+
+// withQuorum runs the provider function against the providers in order of
+// score until the quorum of providers return matching results. The provider
+// function returns the result and a key that identifies the result for
+// comparison. An asset.CoinNotFoundError is considered a result too, but a
+// provider that hasn't seen some data yet doesn't really disagree with one that
+// has, so not-found results are not reported as mismatches. Any other error
+// excludes the provider from the vote. If quorum reads are disabled, withQuorum
+// is equivalent to withPreferred.
+func withQuorum[T any](ctx context.Context, m *multiRPCClient, what string, f func(context.Context, *provider) (T, string, error)) (v T, err error) {
+	quorum := int(m.quorum.Load())
+	if quorum <= 1 {
+		return v, m.withPreferred(ctx, func(ctx context.Context, p *provider) error {
+			v, _, err = f(ctx, p)
+			return err
+		})
+	}
+
+	allProviders := m.nonceProviderList()
+	providers := make([]*provider, 0, len(allProviders))
+	for _, p := range allProviders {
+		if !p.failed() {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) < quorum {
+		// Not enough healthy providers, so try them all.
+		providers = allProviders
+	}
+
+	results := make(map[string]T)
+	votes := make(map[string][]*provider)
+	var errs []string
+	for _, p := range providers {
+		reqCtx, cancel := context.WithTimeout(ctx, defaultRequestTimeout)
+		start := time.Now()
+		pv, key, err := f(reqCtx, p)
+		p.recordRequest(time.Since(start), err)
+		cancel()
+		if err != nil {
+			if !errors.Is(err, asset.CoinNotFoundError) {
+				errs = append(errs, fmt.Sprintf("%s: %v", p, err))
+				if ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+			key = notFoundQuorumKey
+		}
+		if _, found := results[key]; !found {
+			results[key] = pv
+		}
+		votes[key] = append(votes[key], p)
+		if len(votes[key]) >= quorum {
+			m.checkQuorumMismatch(what, key, votes)
+			if key == notFoundQuorumKey {
+				return v, asset.CoinNotFoundError
+			}
+			return results[key], nil
+		}
+	}
+	m.checkQuorumMismatch(what, "", votes)
+	err = fmt.Errorf("fewer than %d of %d providers agreed on %s", quorum, len(providers), what)
+	if len(errs) > 0 {
+		err = fmt.Errorf("%w. errors: %s", err, strings.Join(errs, "; "))
+	}
+	return v, err
+}
+
+// checkQuorumMismatch checks for conflicting results from a quorum read. The
+// providers that disagreed with the agreed upon result are penalized. If no
+// result was agreed upon, agreedKey is empty and all providers with
+// conflicting results are penalized. The user is warned of any mismatch.
+func (m *multiRPCClient) checkQuorumMismatch(what, agreedKey string, votes map[string][]*provider) {
+	groups := make([]string, 0, len(votes))
+	for key, providers := range votes {
+		if key == notFoundQuorumKey {
+			continue
+		}
+		hosts := make([]string, len(providers))
+		for i, p := range providers {
+			hosts[i] = p.host
+		}
+		groups = append(groups, strings.Join(hosts, ", "))
+	}
+	if len(groups) < 2 {
+		return
+	}
+	for key, providers := range votes {
+		if key == agreedKey || key == notFoundQuorumKey {
+			continue
+		}
+		for _, p := range providers {
+			p.recordMismatch()
+		}
+	}
+	sort.Strings(groups)
+	msg := fmt.Sprintf("RPC providers returned conflicting data for %s: [%s]", what, strings.Join(groups, "] vs ["))
+	m.log.Warn(msg)
+
+	m.lastMismatchMtx.Lock()
+	defer m.lastMismatchMtx.Unlock()
+	if m.warn == nil || time.Since(m.lastMismatch) < mismatchWarningInterval {
+		return
+	}
+	m.lastMismatch = time.Now()
+	m.warn(msg)
+}
+
 // freshnessSortedProviders generates a list of providers sorted by their header
-// times, newest first.
+// times, newest first. Providers with the same header time are sorted by
+// score, best first.
 func (m *multiRPCClient) freshnessSortedProviders() []*provider {
 	unsorted := m.providerList()
 	type stampedProvider struct {
 		stamp time.Time
+		score float64
 		p     *provider
 	}
 	sps := make([]*stampedProvider, len(unsorted))
@@ -1194,10 +1483,16 @@ func (m *multiRPCClient) freshnessSortedProviders() []*provider {
 		p.tip.RUnlock()
 		sps[i] = &stampedProvider{
 			stamp: stamp,
+			score: p.score(),
 			p:     p,
 		}
 	}
-	sort.Slice(sps, func(i, j int) bool { return sps[i].stamp.Before(sps[j].stamp) })
+	sort.SliceStable(sps, func(i, j int) bool {
+		if !sps[i].stamp.Equal(sps[j].stamp) {
+			return sps[i].stamp.Before(sps[j].stamp)
+		}
+		return sps[i].score > sps[j].score
+	})
 	providers := make([]*provider, len(sps))
 	for i, sp := range sps {
 		providers[i] = sp.p
@@ -1205,8 +1500,21 @@ func (m *multiRPCClient) freshnessSortedProviders() []*provider {
 	return providers
 }
 
-// nonceProviderList returns the freshness-sorted provider list, but with any recent
-// nonce provider inserted in the first position.
+// scoreSortedProviders generates a list of providers sorted by score, best
+// first. Providers with equal scores are shuffled.
+func (m *multiRPCClient) scoreSortedProviders() []*provider {
+	providers := m.providerList()
+	shuffleProviders(providers)
+	scores := make(map[*provider]float64, len(providers))
+	for _, p := range providers {
+		scores[p] = p.score()
+	}
+	sort.SliceStable(providers, func(i, j int) bool { return scores[providers[i]] > scores[providers[j]] })
+	return providers
+}
+
+// nonceProviderList returns the freshness-sorted provider list, but with any
+// recent nonce provider inserted in the first position.
 func (m *multiRPCClient) nonceProviderList() []*provider {
 	var lastProvider *provider
 	m.lastProvider.Lock()
@@ -1215,10 +1523,10 @@ func (m *multiRPCClient) nonceProviderList() []*provider {
 	}
 	m.lastProvider.Unlock()
 
-	freshProviders := m.freshnessSortedProviders()
+	sortedProviders := m.freshnessSortedProviders()
 
-	providers := make([]*provider, 0, len(m.providers))
-	for _, p := range freshProviders {
+	providers := make([]*provider, 0, len(sortedProviders))
+	for _, p := range sortedProviders {
 		if lastProvider != nil && lastProvider.host == p.host {
 			continue // adding lastProvider below, as preferred provider
 		}
@@ -1295,6 +1603,21 @@ func (m *multiRPCClient) peerCount() (c uint32) {
 	return
 }
 
+// peers lists the providers with their health, best first.
+func (m *multiRPCClient) peers(source asset.PeerSource) []*asset.WalletPeer {
+	providers := m.scoreSortedProviders()
+	peers := make([]*asset.WalletPeer, len(providers))
+	for i, p := range providers {
+		peers[i] = &asset.WalletPeer{
+			Addr:      p.host,
+			Source:    source,
+			Connected: !p.failed(),
+			Health:    p.health(),
+		}
+	}
+	return peers
+}
+
 func (m *multiRPCClient) contractBackend() bind.ContractBackend {
 	return m
 }
@@ -1483,7 +1806,26 @@ func (m *multiRPCClient) CodeAt(ctx context.Context, contract common.Address, bl
 	})
 }
 
+// CallContract executes a contract call. If the context is a quorumContext and
+// quorum reads are enabled, the result must be agreed upon by the quorum of
+// providers. Part of the bind.ContractBackend interface.
 func (m *multiRPCClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) (res []byte, err error) {
+	if isQuorumContext(ctx) && m.quorum.Load() > 1 {
+		if blockNumber == nil {
+			// Use the same block for all providers, so that providers at
+			// different heights don't disagree.
+			hdr, err := m.bestHeader(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("error getting best header: %w", err)
+			}
+			blockNumber = hdr.Number
+		}
+		what := fmt.Sprintf("contract call to %s", call.To)
+		return withQuorum(ctx, m, what, func(ctx context.Context, p *provider) ([]byte, string, error) {
+			res, err := p.ec.CallContract(ctx, call, blockNumber)
+			return res, hexutil.Encode(res), err
+		})
+	}
 	return res, m.withPreferred(ctx, func(ctx context.Context, p *provider) error {
 		res, err = p.ec.CallContract(ctx, call, blockNumber)
 		return err
@@ -75,6 +75,7 @@ const (
 	dbVersion                      = 2
 	txsTable                       = "txs"
 	bridgeCompletionsTable         = "bridgeCompletions"
+	customTokensTable              = "customTokens"
 	allAssetIndexName              = "allAssets"
 	assetIndexName                 = "asset"
 	bridgeInitiationIndexName      = "bridgeinit"
@@ -135,6 +136,8 @@ type txDB interface {
 	getBridges(tokenID *uint32, n int, refID *common.Hash, past bool) ([]*asset.WalletTransaction, error)
 	getPendingBridges(tokenID *uint32) ([]*extendedWalletTx, error)
 	getBridgeCompletions(initiationTxID string) ([]*extendedWalletTx, error)
+	storeCustomToken(ct *customToken) error
+	getCustomTokens() ([]*customToken, error)
 }
 
 type TxDB struct {
@@ -142,6 +145,7 @@ type TxDB struct {
 
 	txs               *lexi.Table
 	bridgeCompletions *lexi.Table
+	customTokens      *lexi.Table
 
 	allAssetIndex              *lexi.Index
 	assetIndex                 *lexi.Index
@@ -248,6 +252,11 @@ func NewTxDB(path string, log dex.Logger, baseChainID uint32) (*TxDB, error) {
 		return nil, err
 	}
 
+	customTokens, err := ldb.Table(customTokensTable)
+	if err != nil {
+		return nil, err
+	}
+
 	allAssetIndex, err := txs.AddUniqueIndex(allAssetIndexName, func(k, v lexi.KV) ([]byte, error) {
 		wt, is := v.(*extendedWalletTx)
 		if !is {
@@ -302,6 +311,7 @@ func NewTxDB(path string, log dex.Logger, baseChainID uint32) (*TxDB, error) {
 		DB:                         ldb,
 		txs:                        txs,
 		bridgeCompletions:          bridgeCompletions,
+		customTokens:               customTokens,
 		allAssetIndex:              allAssetIndex,
 		assetIndex:                 assetIndex,
 		bridgeInitiationIndex:      bridgeInitiationIndex,
@@ -716,3 +726,23 @@ func (db *TxDB) getBridgeCompletions(initiationTxID string) ([]*extendedWalletTx
 
 	return txs, nil
 }
+
+// storeCustomToken stores a user-defined token. An existing entry for the same
+// contract address is replaced.
+func (db *TxDB) storeCustomToken(ct *customToken) error {
+	return db.customTokens.Set(ct.Address[:], ct, lexi.WithReplace())
+}
+
+// getCustomTokens gets all of the user-defined tokens.
+func (db *TxDB) getCustomTokens() (cts []*customToken, err error) {
+	return cts, db.customTokens.Iterate(nil, func(it *lexi.Iter) error {
+		ct := new(customToken)
+		if err := it.V(func(vB []byte) error {
+			return ct.UnmarshalBinary(vB)
+		}); err != nil {
+			return err
+		}
+		cts = append(cts, ct)
+		return nil
+	})
+}
@@ -0,0 +1,167 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package evm provides wallets for EVM-compatible chains that are defined in
+// a chain definition file rather than in their own asset package. The wallets
+// are the shared eth wallet, configured from the chain definition.
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/eth"
+	"decred.org/dcrdex/dex"
+	dexevm "decred.org/dcrdex/dex/networks/evm"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const walletTypeRPC = "rpc"
+
+// Driver implements asset.Driver for a defined chain.
+type Driver struct {
+	def *dexevm.ChainDef
+	wi  asset.WalletInfo
+}
+
+var _ asset.Driver = (*Driver)(nil)
+
+// NewDriver is the constructor for a Driver.
+func NewDriver(def *dexevm.ChainDef) *Driver {
+	return &Driver{
+		def: def,
+		wi: asset.WalletInfo{
+			Name:              def.Name,
+			SupportedVersions: def.ContractVersions(),
+			UnitInfo:          def.Units(),
+			AvailableWallets: []*asset.WalletDefinition{
+				{
+					Type:        walletTypeRPC,
+					Tab:         "External",
+					Description: "Infrastructure providers (e.g. Infura) or local nodes",
+					ConfigOpts: append(append([]*asset.ConfigOption{}, eth.RPCOpts...), &asset.ConfigOption{
+						Key:         "gasfeelimit",
+						DisplayName: "Gas Fee Limit",
+						Description: "This is the highest network fee rate you are willing to " +
+							"pay on swap transactions. If gasfeelimit is lower than a market's " +
+							"maxfeerate, you will not be able to trade on that market with this " +
+							"wallet.  Units: gwei / gas",
+						DefaultValue: strconv.FormatUint(def.GasFeeLimit, 10),
+					}),
+					Seeded: true,
+					NoAuth: true,
+				},
+			},
+			BlockchainClass: asset.BlockchainClassEVM,
+		},
+	}
+}
+
+// network gets the chain's network definition and the compatibility data that
+// is required to check RPC providers.
+func (d *Driver) network(net dex.Network) (*dexevm.NetworkDef, *eth.CompatibilityData, error) {
+	nd := d.def.Network(net)
+	if nd == nil {
+		return nil, nil, fmt.Errorf("%s is not defined for %s", d.def.Name, net)
+	}
+	if nd.Compatibility == nil {
+		return nil, nil, fmt.Errorf("no %s compatibility data for %s", d.def.Name, net)
+	}
+	return nd, &eth.CompatibilityData{
+		Addr:      nd.Compatibility.Addr,
+		TokenAddr: nd.Compatibility.TokenAddr,
+		TxHash:    nd.Compatibility.TxHash,
+		BlockHash: nd.Compatibility.BlockHash,
+	}, nil
+}
+
+// Open opens the exchange wallet. Start the wallet with its Run method.
+func (d *Driver) Open(cfg *asset.WalletConfig, logger dex.Logger, net dex.Network) (asset.Wallet, error) {
+	nd, compat, err := d.network(net)
+	if err != nil {
+		return nil, err
+	}
+	return eth.NewEVMWallet(&eth.EVMWalletConfig{
+		BaseChainID:        d.def.BipID,
+		ChainCfg:           &params.ChainConfig{ChainID: big.NewInt(nd.ChainID)},
+		AssetCfg:           cfg,
+		CompatData:         compat,
+		VersionedGases:     d.def.VersionedGases(),
+		FinalizeConfs:      d.def.FinalizeConfs,
+		Logger:             logger,
+		BaseChainContracts: nd.Contracts,
+		MultiBalAddress:    nd.MultiBalance,
+		WalletInfo:         d.wi,
+		Net:                net,
+		DefaultProviders:   nd.DefaultProviders,
+		MaxTxFeeGwei:       d.def.MaxTxFeeGwei,
+		IsOpStack:          d.def.GasModel == dexevm.GasModelOPStack,
+	})
+}
+
+// DecodeCoinID creates a human-readable representation of a coin ID.
+func (d *Driver) DecodeCoinID(coinID []byte) (string, error) {
+	return (&eth.Driver{}).DecodeCoinID(coinID)
+}
+
+// Info returns basic information about the wallet and asset.
+func (d *Driver) Info() *asset.WalletInfo {
+	wi := d.wi
+	return &wi
+}
+
+// Exists checks the existence of the wallet.
+func (d *Driver) Exists(walletType, dataDir string, settings map[string]string, net dex.Network) (bool, error) {
+	if walletType != walletTypeRPC {
+		return false, fmt.Errorf("unknown wallet type %q", walletType)
+	}
+	return (&eth.Driver{}).Exists(walletType, dataDir, settings, net)
+}
+
+// Create creates a new wallet.
+func (d *Driver) Create(cfg *asset.CreateWalletParams) error {
+	nd, compat, err := d.network(cfg.Net)
+	if err != nil {
+		return err
+	}
+	return eth.CreateEVMWallet(nd.ChainID, cfg, compat, false)
+}
+
+// RegisterChains registers the chain symbols, asset drivers, and bridges for
+// the chain definitions. RegisterChains must be called before the network is
+// set with asset.SetNetwork. Chains that already have a driver can't be
+// registered.
+func RegisterChains(defs []*dexevm.ChainDef) error {
+	for _, def := range defs {
+		if _, err := asset.Info(def.BipID); err == nil {
+			return fmt.Errorf("asset %d is already registered", def.BipID)
+		}
+		if err := def.RegisterSymbol(); err != nil {
+			return fmt.Errorf("error registering %s symbol: %w", def, err)
+		}
+		for _, net := range []dex.Network{dex.Mainnet, dex.Testnet, dex.Simnet} {
+			nd := def.Network(net)
+			if nd == nil || nd.Bridges == nil || nd.Bridges.Across == nil {
+				continue
+			}
+			across := nd.Bridges.Across
+			if err := eth.RegisterAcrossChain(net, def.BipID, uint64(nd.ChainID), across.SpokePool, across.WrappedNative, across.NativeSymbol); err != nil {
+				return fmt.Errorf("error registering %s Across bridge: %w", def, err)
+			}
+		}
+		asset.Register(def.BipID, NewDriver(def))
+	}
+	return nil
+}
+
+// RegisterChainsFile loads the chain definitions from the file and registers
+// them with RegisterChains.
+func RegisterChainsFile(path string) ([]*dexevm.ChainDef, error) {
+	defs, err := dexevm.LoadChainDefs(path)
+	if err != nil {
+		return nil, err
+	}
+	return defs, RegisterChains(defs)
+}
@@ -0,0 +1,86 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/asset/eth"
+	"decred.org/dcrdex/dex"
+)
+
+const tChainDefs = `[{
+  "name": "Test Chain",
+  "symbol": "TEVM",
+  "bipID": 9102,
+  "gasModel": "opstack",
+  "gasFeeLimit": 50,
+  "networks": {
+    "simnet": {
+      "chainID": 1337,
+      "contracts": {"1": "0x1111111111111111111111111111111111111111"},
+      "bridges": {
+        "across": {
+          "spokePool": "0x2222222222222222222222222222222222222222",
+          "wrappedNative": "0x3333333333333333333333333333333333333333",
+          "nativeSymbol": "WETH"
+        }
+      }
+    }
+  }
+}]`
+
+func TestRegisterChainsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evmchains.json")
+	if err := os.WriteFile(path, []byte(tChainDefs), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defs, err := RegisterChainsFile(path)
+	if err != nil {
+		t.Fatalf("RegisterChainsFile error: %v", err)
+	}
+	if sym := dex.BipIDSymbol(9102); sym != "tevm" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	wi, err := asset.Info(9102)
+	if err != nil {
+		t.Fatalf("asset not registered: %v", err)
+	}
+	if wi.Name != "Test Chain" || len(wi.SupportedVersions) != 1 || wi.SupportedVersions[0] != 1 {
+		t.Fatalf("wrong wallet info %+v", wi)
+	}
+	def, err := asset.WalletDef(9102, walletTypeRPC)
+	if err != nil {
+		t.Fatalf("no rpc wallet definition: %v", err)
+	}
+	var gasFeeLimit string
+	for _, opt := range def.ConfigOpts {
+		if opt.Key == "gasfeelimit" {
+			gasFeeLimit = opt.DefaultValue
+		}
+	}
+	if gasFeeLimit != "50" {
+		t.Fatalf("wrong gas fee limit default %q", gasFeeLimit)
+	}
+	if !eth.AcrossBridgeSupportedAsset(9102, dex.Simnet) {
+		t.Fatal("Across bridge not registered")
+	}
+
+	// Already registered.
+	if err := RegisterChains(defs); err == nil {
+		t.Fatal("no error for duplicate registration")
+	}
+
+	// No compatibility data for simnet, and mainnet isn't defined.
+	drv := NewDriver(defs[0])
+	if _, _, err := drv.network(dex.Simnet); err == nil {
+		t.Fatal("no error for missing compatibility data")
+	}
+	if _, _, err := drv.network(dex.Mainnet); err == nil {
+		t.Fatal("no error for undefined network")
+	}
+}
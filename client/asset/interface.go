@@ -43,6 +43,8 @@ const (
 	WalletTraitContractDeployer                          // The wallet can deploy contracts.
 	WalletTraitContractGasTester                         // The wallet can test contract gas usage.
 	WalletTraitPoliteiaVoter                             // The wallet can vote on Politeia proposals.
+	WalletTraitBatchSender                               // The wallet can send to multiple recipients at once.
+	WalletTraitCoinController                            // The wallet supports manual coin selection for sends.
 )
 
 // IsRescanner tests if the WalletTrait has the WalletTraitRescanner bit set.
@@ -170,6 +172,18 @@ func (wt WalletTrait) IsPoliteiaVoter() bool {
 	return wt&WalletTraitPoliteiaVoter != 0
 }
 
+// IsBatchSender tests if the WalletTrait has the WalletTraitBatchSender bit
+// set, which indicates the wallet implements the BatchSender interface.
+func (wt WalletTrait) IsBatchSender() bool {
+	return wt&WalletTraitBatchSender != 0
+}
+
+// IsCoinController tests if the WalletTrait has the WalletTraitCoinController
+// bit set, which indicates the wallet implements the CoinController interface.
+func (wt WalletTrait) IsCoinController() bool {
+	return wt&WalletTraitCoinController != 0
+}
+
 // DetermineWalletTraits returns the WalletTrait bitset for the provided Wallet.
 func DetermineWalletTraits(w Wallet) (t WalletTrait) {
 	if _, is := w.(Rescanner); is {
@@ -232,6 +246,12 @@ func DetermineWalletTraits(w Wallet) (t WalletTrait) {
 	if _, is := w.(PoliteiaVoter); is {
 		t |= WalletTraitPoliteiaVoter
 	}
+	if _, is := w.(BatchSender); is {
+		t |= WalletTraitBatchSender
+	}
+	if _, is := w.(CoinController); is {
+		t |= WalletTraitCoinController
+	}
 	return t
 }
 
@@ -343,6 +363,9 @@ type Token struct {
 	Definition             *WalletDefinition `json:"definition"`
 	ContractAddress        string            `json:"contractAddress"` // Set in SetNetwork
 	SupportedAssetVersions []uint32          `json:"supportedAssetVersions"`
+	// Custom is true for tokens that were added by the user. Custom tokens
+	// can be sent and received, but are not traded on any DEX.
+	Custom bool `json:"custom,omitempty"`
 }
 
 type BlockchainClass string
@@ -1046,6 +1069,73 @@ type ContractGasTester interface {
 	TestContractGas(contractVer uint32, maxSwaps int, tokenAssetIDs []uint32, tokensOnly bool) ([]*GasTestResult, error)
 }
 
+// BatchRecipient is an address and the exact amount to send it in a batch
+// send.
+type BatchRecipient struct {
+	Address string `json:"address"`
+	Value   uint64 `json:"value"`
+}
+
+// BatchSender is a wallet that can send to many recipients at once. UTXO-based
+// wallets pay every recipient in a single transaction and record a single
+// WalletTransaction for the batch. Account-based wallets do not batch. They
+// send N sequential transactions for N recipients, each paying its own fee
+// and recorded as its own WalletTransaction, and their fee estimate is the sum
+// of the TxFeeEstimator estimates of the individual sends.
+type BatchSender interface {
+	// SendBatch sends the exact value to each recipient. Fees are in addition
+	// to the sent values. The returned IDs are the IDs of the broadcast
+	// transactions, one for UTXO-based wallets, or one per recipient for
+	// account-based wallets. If an error is encountered after some
+	// transactions were broadcast, the IDs of those transactions are returned
+	// with the error.
+	SendBatch(recipients []*BatchRecipient, feeRate uint64) ([]string, error)
+	// EstimateBatchSendTxFee returns an estimate of the total fees for sending
+	// to the recipients at the provided fee rate. This is the batch
+	// counterpart to TxFeeEstimator.EstimateSendTxFee.
+	EstimateBatchSendTxFee(recipients []*BatchRecipient, feeRate uint64) (uint64, error)
+}
+
+// SpendableCoin is an unspent output in a UTXO-based wallet.
+type SpendableCoin struct {
+	ID            dex.Bytes `json:"id"`
+	TxID          string    `json:"txID"`
+	Vout          uint32    `json:"vout"`
+	Address       string    `json:"address"`
+	Value         uint64    `json:"value"`
+	Confirmations uint32    `json:"confs"`
+	Label         string    `json:"label,omitempty"`
+	// Locked will be true if the coin is reserved for an active order or
+	// bond. Locked coins cannot be selected for a CoinControlSend.
+	Locked bool `json:"locked"`
+}
+
+// CoinControlSend is a send that spends an explicit set of coins.
+type CoinControlSend struct {
+	// Coins are the IDs of the coins to spend. All of them are spent.
+	Coins []dex.Bytes `json:"coins"`
+	// Address is the recipient address.
+	Address string `json:"address"`
+	// Value is the amount to send. If Value is zero, the combined value of
+	// the coins less fees is sent to Address and there is no change output.
+	Value uint64 `json:"value"`
+	// ChangeAddress is an optional address for the change output. If empty,
+	// a new internal wallet address is used.
+	ChangeAddress string `json:"changeAddress,omitempty"`
+}
+
+// CoinController is a UTXO-based wallet that supports manual coin selection
+// for sends.
+type CoinController interface {
+	// SpendableCoins lists the wallet's unspent outputs, including those
+	// locked by active orders.
+	SpendableCoins() ([]*SpendableCoin, error)
+	// SendWithCoins sends using exactly the specified coins. An error is
+	// returned if any of the coins are locked or not found. The returned Coin
+	// is the output paying the recipient.
+	SendWithCoins(send *CoinControlSend, feeRate uint64) (Coin, error)
+}
+
 // Sweeper is a wallet that can clear the entire balance of the wallet/account
 // to an address. Similar to Withdraw, but no input value is required.
 type Sweeper interface {
@@ -1224,6 +1314,16 @@ type TokenMaster interface {
 	OpenTokenWallet(cfg *TokenConfig) (Wallet, error)
 }
 
+// CustomTokenAdder is a TokenMaster that allows the user to add tokens by
+// contract address.
+type CustomTokenAdder interface {
+	TokenMaster
+	// AddCustomToken looks up the token at the contract address, saves it, and
+	// registers it with RegisterCustomToken. The token's asset ID is returned.
+	// Adding a token that was already added is not an error.
+	AddCustomToken(ctx context.Context, contractAddr string) (uint32, error)
+}
+
 // AccountLocker is a wallet in which redemptions and refunds require a wallet
 // to have available balance to pay fees.
 type AccountLocker interface {
@@ -1324,6 +1424,29 @@ type WalletPeer struct {
 	Addr      string     `json:"addr"`
 	Source    PeerSource `json:"source"`
 	Connected bool       `json:"connected"`
+	// Health is only set by wallets that track the performance of their
+	// peers, e.g. the RPC providers of an EVM wallet.
+	Health *PeerHealth `json:"health,omitempty"`
+}
+
+// PeerHealth describes the recent performance of a wallet's peer.
+type PeerHealth struct {
+	// Score is a rating from 0 to 100 that combines the other metrics. Peers
+	// with higher scores are preferred.
+	Score float64 `json:"score"`
+	// LatencyMS is the moving average of the request latency, in
+	// milliseconds.
+	LatencyMS uint64 `json:"latencyMS"`
+	// ErrorRate is the moving average of the fraction of requests that
+	// failed.
+	ErrorRate float64 `json:"errorRate"`
+	// Requests is the number of requests made to the peer.
+	Requests uint64 `json:"requests"`
+	// Mismatches is the number of times the peer's response disagreed with
+	// the responses of other peers.
+	Mismatches uint64 `json:"mismatches"`
+	// LastError is the most recent error returned by the peer, if any.
+	LastError string `json:"lastError,omitempty"`
 }
 
 // PeerManager is a wallet which provides allows the user to see the peers the
@@ -1576,6 +1699,38 @@ const (
 	DeployContract
 )
 
+var txTypeStrings = map[TransactionType]string{
+	Unknown:             "unknown",
+	Send:                "send",
+	Receive:             "receive",
+	Swap:                "swap",
+	Redeem:              "redeem",
+	Refund:              "refund",
+	Split:               "split",
+	CreateBond:          "createBond",
+	RedeemBond:          "redeemBond",
+	ApproveToken:        "approveToken",
+	Acceleration:        "acceleration",
+	SelfSend:            "selfSend",
+	RevokeTokenApproval: "revokeTokenApproval",
+	TicketPurchase:      "ticketPurchase",
+	TicketVote:          "ticketVote",
+	TicketRevocation:    "ticketRevocation",
+	SwapOrSend:          "swapOrSend",
+	Mix:                 "mix",
+	InitiateBridge:      "initiateBridge",
+	CompleteBridge:      "completeBridge",
+	DeployContract:      "deployContract",
+}
+
+// String returns a string representation of the TransactionType.
+func (tt TransactionType) String() string {
+	if s, found := txTypeStrings[tt]; found {
+		return s
+	}
+	return "unknown"
+}
+
 // IncomingTxType returns true if the wallet's balance increases due to a
 // transaction.
 func IncomingTxType(txType TransactionType) bool {
@@ -1628,6 +1783,9 @@ type WalletTransaction struct {
 	// Recipient will be non-nil for Send/Receive transactions, and specifies the
 	// recipient address of the transaction.
 	Recipient *string `json:"recipient,omitempty"`
+	// BatchRecipients will be non-nil for a Send transaction that paid
+	// multiple recipients. Recipient is nil for these transactions.
+	BatchRecipients []*BatchRecipient `json:"batchRecipients,omitempty"`
 	// BondInfo will be non-nil for CreateBond and RedeemBond transactions.
 	BondInfo *BondTxInfo `json:"bondInfo,omitempty"`
 	// AdditionalData contains asset specific information, i.e. nonce
@@ -1655,6 +1813,12 @@ type WalletTransaction struct {
 	RelayTxID string `json:"relayTxID,omitempty"`
 	// IsRelay will be true if the transaction is a relay redemption.
 	IsRelay bool `json:"isRelay"`
+	// Label is a user-defined label for the transaction. Labels are set by
+	// the consumer, not the wallet.
+	Label string `json:"label,omitempty"`
+	// RecipientLabel is a user-defined label or address book name for the
+	// Recipient address. It is set by the consumer, not the wallet.
+	RecipientLabel string `json:"recipientLabel,omitempty"`
 }
 
 // Bond is the fidelity bond info generated for a certain account ID, amount,
@@ -2136,6 +2300,13 @@ type TransactionNote struct {
 	New         bool               `json:"new"`
 }
 
+// WarningNote is sent when the wallet detects a problem that the user should
+// know about, but that doesn't need any action.
+type WarningNote struct {
+	baseWalletNotification
+	Message string `json:"message"`
+}
+
 // CustomWalletNote is any other information the wallet wishes to convey to
 // the user.
 type CustomWalletNote struct {
@@ -2195,6 +2366,17 @@ func (e *WalletEmitter) Data(route string, payload any) {
 	})
 }
 
+// Warning sends a WarningNote with the specified message.
+func (e *WalletEmitter) Warning(msg string) {
+	e.emit(&WarningNote{
+		baseWalletNotification: baseWalletNotification{
+			AssetID: e.assetID,
+			Route:   "warning",
+		},
+		Message: msg,
+	})
+}
+
 // TipChange sends a TipChangeNote with optional extra data.
 func (e *WalletEmitter) TipChange(tip uint64, datas ...any) {
 	var data any
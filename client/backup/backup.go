@@ -0,0 +1,687 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package backup creates and restores encrypted backups of the client's data
+// files. A backup is a gzipped tar archive of the files and a manifest of
+// their checksums, encrypted in chunks with a key derived from the app
+// password. The key derivation parameters are stored in the backup's header,
+// so a backup can be restored with just the app password.
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+	"go.etcd.io/bbolt"
+)
+
+const (
+	// FileExt is the extension of backup files.
+	FileExt = ".bwbak"
+	// filePrefix is the prefix of backup file names created by Create.
+	filePrefix = "bisonw-backup-"
+	// fileTimeFormat is the format of the time stamp in backup file names.
+	fileTimeFormat = "20060102-150405"
+
+	// Well-known names of the files in a backup.
+	DBFile         = "dexc.db"
+	MMEventLogFile = "eventlog.db"
+	MMConfigFile   = "mm_cfg.json"
+
+	magic          = "BWBK"
+	backupVersion  = 0
+	manifestName   = "manifest.json"
+	chunkSize      = 1 << 20
+	maxChunkSize   = chunkSize + 1024
+	maxHeaderSize  = 1024
+	restoreSuffix  = ".restoring"
+	replacedSuffix = ".pre-restore-"
+)
+
+var intCoder = encode.IntCoder
+
+// rename is os.Rename, replaceable for testing failed restores.
+var rename = os.Rename
+
+// ErrInvalidBackup is returned for a file that is not a backup, or that is
+// corrupted or has been tampered with.
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// File is a file to include in a backup.
+type File struct {
+	// Name is the name of the file in the backup.
+	Name string
+	// Path is the location of the file to back up.
+	Path string
+	// BoltDB indicates that the file is a bbolt database, which will be
+	// integrity checked when the backup is verified or restored.
+	BoltDB bool
+}
+
+// ManifestFile is the manifest entry for a file in a backup.
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	BoltDB bool   `json:"boltDB,omitempty"`
+}
+
+// Manifest describes the contents of a backup.
+type Manifest struct {
+	Version uint8           `json:"version"`
+	Created int64           `json:"created"` // unix ms
+	Files   []*ManifestFile `json:"files"`
+}
+
+// Info is information about a backup file in a backup directory.
+type Info struct {
+	Path string
+	Time time.Time
+}
+
+// Create writes an encrypted backup of the files to a new file in dir, and
+// verifies the backup by decrypting it and checking the contents. The path of
+// the new backup is returned.
+func Create(dir string, crypter encrypt.Crypter, files []*File, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("error creating backup directory: %w", err)
+	}
+	path := filepath.Join(dir, filePrefix+now.UTC().Format(fileTimeFormat)+FileExt)
+	if _, err := os.Stat(path); err == nil {
+		return "", fmt.Errorf("backup %s already exists", path)
+	}
+	tmpPath := path + ".tmp"
+	if err := write(tmpPath, crypter, files, now); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	if _, err := verify(tmpPath, crypter); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("backup verification failed: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", err
+	}
+	return path, nil
+}
+
+// write writes the backup file.
+func write(path string, crypter encrypt.Crypter, files []*File, now time.Time) (err error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	header := encode.BuildyBytes{backupVersion}.AddData(crypter.Serialize())
+	if _, err := bw.WriteString(magic); err != nil {
+		return err
+	}
+	if _, err := bw.Write(append(encode.Uint32Bytes(uint32(len(header))), header...)); err != nil {
+		return err
+	}
+
+	ew := &chunkWriter{w: bw, crypter: crypter}
+	zw := gzip.NewWriter(ew)
+	tw := tar.NewWriter(zw)
+
+	manifest := &Manifest{
+		Version: backupVersion,
+		Created: now.UnixMilli(),
+	}
+	for _, file := range files {
+		mf, err := addFile(tw, file)
+		if err != nil {
+			return fmt.Errorf("error adding %s to backup: %w", file.Name, err)
+		}
+		manifest.Files = append(manifest.Files, mf)
+	}
+	manifestB, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0600,
+		Size:    int64(len(manifestB)),
+		ModTime: now,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifestB); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := ew.Close(); err != nil {
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// addFile adds the file to the archive.
+func addFile(tw *tar.Writer, file *File) (*ManifestFile, error) {
+	if file.Name == manifestName || file.Name != filepath.Base(file.Name) {
+		return nil, fmt.Errorf("invalid file name %q", file.Name)
+	}
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    file.Name,
+		Mode:    0600,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tw, h), f)
+	if err != nil {
+		return nil, err
+	}
+	if n != fi.Size() {
+		return nil, fmt.Errorf("file size changed while reading")
+	}
+	return &ManifestFile{
+		Name:   file.Name,
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+		BoltDB: file.BoltDB,
+	}, nil
+}
+
+// Verify decrypts the backup and checks the contents against the manifest.
+// Any bbolt databases in the backup are integrity checked.
+func Verify(path string, pw []byte) (*Manifest, error) {
+	crypter, err := crypterFromFile(path, pw)
+	if err != nil {
+		return nil, err
+	}
+	defer crypter.Close()
+	return verify(path, crypter)
+}
+
+func verify(path string, crypter encrypt.Crypter) (*Manifest, error) {
+	tmpDir, err := os.MkdirTemp("", "bwbackup")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	extracted := make(map[string]string)
+	manifest, err := extract(path, crypter, func(name string) string {
+		p := filepath.Join(tmpDir, name)
+		extracted[name] = p
+		return p
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkDBs(manifest, extracted); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// Restore verifies the backup and then replaces the files at the destinations
+// with the files in the backup. dsts maps the names of the files in the
+// backup to their destination paths. Files in the backup with no destination
+// are not restored. Any existing file at a destination is kept, renamed with
+// a ".pre-restore-<time>" suffix. Nothing is replaced unless the entire backup
+// is verified, and if any file cannot be replaced, the files that were already
+// replaced are put back.
+func Restore(path string, pw []byte, dsts map[string]string) (*Manifest, error) {
+	crypter, err := crypterFromFile(path, pw)
+	if err != nil {
+		return nil, err
+	}
+	defer crypter.Close()
+
+	staged := make(map[string]string)
+	removeStaged := func() {
+		for _, p := range staged {
+			os.Remove(p)
+		}
+	}
+	manifest, err := extract(path, crypter, func(name string) string {
+		dst, found := dsts[name]
+		if !found {
+			return ""
+		}
+		p := dst + restoreSuffix
+		staged[name] = p
+		return p
+	})
+	if err != nil {
+		removeStaged()
+		return nil, err
+	}
+	if err := checkDBs(manifest, staged); err != nil {
+		removeStaged()
+		return nil, err
+	}
+
+	// swap is a destination that has been replaced, with the path that the
+	// existing file was moved to, if there was one.
+	type swap struct {
+		dst, moved string
+	}
+	var swapped []*swap
+	rollback := func(err error) error {
+		errs := []error{err}
+		for i := len(swapped) - 1; i >= 0; i-- {
+			s := swapped[i]
+			if err := os.Remove(s.dst); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, fmt.Errorf("error removing restored %s: %w", s.dst, err))
+				continue
+			}
+			if s.moved == "" {
+				continue
+			}
+			if err := rename(s.moved, s.dst); err != nil {
+				errs = append(errs, fmt.Errorf("error putting back %s: %w", s.dst, err))
+			}
+		}
+		removeStaged()
+		return errors.Join(errs...)
+	}
+
+	suffix := replacedSuffix + time.Now().UTC().Format(fileTimeFormat)
+	names := make([]string, 0, len(staged))
+	for name := range staged {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		dst := dsts[name]
+		s := &swap{dst: dst}
+		if _, err := os.Stat(dst); err == nil {
+			if err := rename(dst, dst+suffix); err != nil {
+				return nil, rollback(fmt.Errorf("error moving existing %s: %w", dst, err))
+			}
+			s.moved = dst + suffix
+		}
+		if err := rename(staged[name], dst); err != nil {
+			if s.moved != "" {
+				if err := rename(s.moved, dst); err != nil {
+					return nil, rollback(fmt.Errorf("error putting back %s: %w", dst, err))
+				}
+			}
+			return nil, rollback(fmt.Errorf("error restoring %s: %w", dst, err))
+		}
+		swapped = append(swapped, s)
+		delete(staged, name)
+	}
+	return manifest, nil
+}
+
+// crypterFromFile reads the backup's header and derives the key from the
+// password.
+func crypterFromFile(path string, pw []byte) (encrypt.Crypter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	params, err := readHeader(bufio.NewReader(f))
+	if err != nil {
+		return nil, err
+	}
+	crypter, err := encrypt.Deserialize(pw, params)
+	if err != nil {
+		return nil, fmt.Errorf("incorrect password or corrupted backup: %w", err)
+	}
+	return crypter, nil
+}
+
+// readHeader reads the backup's header, returning the serialized crypter.
+func readHeader(r io.Reader) ([]byte, error) {
+	b := make([]byte, len(magic)+4)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("%w: error reading header: %v", ErrInvalidBackup, err)
+	}
+	if string(b[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: not a backup file", ErrInvalidBackup)
+	}
+	headerLen := intCoder.Uint32(b[len(magic):])
+	if headerLen > maxHeaderSize {
+		return nil, fmt.Errorf("%w: header too large", ErrInvalidBackup)
+	}
+	header := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: error reading header: %v", ErrInvalidBackup, err)
+	}
+	ver, pushes, err := encode.DecodeBlob(header, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error decoding header: %v", ErrInvalidBackup, err)
+	}
+	if ver != backupVersion {
+		return nil, fmt.Errorf("%w: unknown backup version %d", ErrInvalidBackup, ver)
+	}
+	if len(pushes) != 1 {
+		return nil, fmt.Errorf("%w: expected 1 header push, got %d", ErrInvalidBackup, len(pushes))
+	}
+	return pushes[0], nil
+}
+
+// extract decrypts the backup and extracts the files. dstPath returns the path
+// to extract each file to, or an empty string to skip the file. Skipped files
+// are still checked against the manifest.
+func extract(path string, crypter encrypt.Crypter, dstPath func(name string) string) (*Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	br := bufio.NewReader(f)
+	if _, err := readHeader(br); err != nil {
+		return nil, err
+	}
+
+	cr := &chunkReader{r: br, crypter: crypter}
+	zr, err := gzip.NewReader(cr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	tr := tar.NewReader(zr)
+
+	var manifest *Manifest
+	sums := make(map[string]*ManifestFile)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		if manifest != nil {
+			return nil, fmt.Errorf("%w: data after manifest", ErrInvalidBackup)
+		}
+		if hdr.Name == manifestName {
+			manifest = new(Manifest)
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("%w: error decoding manifest: %v", ErrInvalidBackup, err)
+			}
+			continue
+		}
+		if hdr.Name != filepath.Base(hdr.Name) || sums[hdr.Name] != nil {
+			return nil, fmt.Errorf("%w: invalid file name %q", ErrInvalidBackup, hdr.Name)
+		}
+		mf, err := extractFile(tr, hdr.Name, dstPath(hdr.Name))
+		if err != nil {
+			return nil, err
+		}
+		sums[hdr.Name] = mf
+	}
+	// Make sure the stream was not truncated.
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if !cr.done {
+		return nil, fmt.Errorf("%w: backup is truncated", ErrInvalidBackup)
+	}
+
+	if manifest == nil {
+		return nil, fmt.Errorf("%w: no manifest", ErrInvalidBackup)
+	}
+	if len(manifest.Files) != len(sums) {
+		return nil, fmt.Errorf("%w: manifest lists %d files, found %d", ErrInvalidBackup, len(manifest.Files), len(sums))
+	}
+	for _, mf := range manifest.Files {
+		sum := sums[mf.Name]
+		if sum == nil {
+			return nil, fmt.Errorf("%w: %s is missing", ErrInvalidBackup, mf.Name)
+		}
+		if sum.Size != mf.Size || sum.SHA256 != mf.SHA256 {
+			return nil, fmt.Errorf("%w: checksum mismatch for %s", ErrInvalidBackup, mf.Name)
+		}
+	}
+	return manifest, nil
+}
+
+// extractFile writes the file to dst, if dst is not empty, and computes its
+// checksum.
+func extractFile(r io.Reader, name, dst string) (*ManifestFile, error) {
+	h := sha256.New()
+	w := io.Writer(h)
+	var f *os.File
+	if dst != "" {
+		var err error
+		f, err = os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		w = io.MultiWriter(f, h)
+	}
+	n, err := io.Copy(w, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error extracting %s: %v", ErrInvalidBackup, name, err)
+	}
+	if f != nil {
+		if err := f.Sync(); err != nil {
+			return nil, err
+		}
+	}
+	return &ManifestFile{
+		Name:   name,
+		Size:   n,
+		SHA256: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// checkDBs runs the bbolt consistency check on the extracted databases.
+func checkDBs(manifest *Manifest, extracted map[string]string) error {
+	for _, mf := range manifest.Files {
+		p, found := extracted[mf.Name]
+		if !mf.BoltDB || !found {
+			continue
+		}
+		if err := checkDB(p); err != nil {
+			return fmt.Errorf("%w: %s failed integrity check: %v", ErrInvalidBackup, mf.Name, err)
+		}
+	}
+	return nil
+}
+
+func checkDB(path string) error {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true, Timeout: time.Second})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.View(func(tx *bbolt.Tx) error {
+		var errs []error
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// List lists the backups in dir created by Create, newest first.
+func List(dir string) ([]*Info, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var infos []*Info
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, FileExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), FileExt)
+		t, err := time.Parse(fileTimeFormat, stamp)
+		if err != nil {
+			continue
+		}
+		infos = append(infos, &Info{
+			Path: filepath.Join(dir, name),
+			Time: t,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Time.After(infos[j].Time)
+	})
+	return infos, nil
+}
+
+// Prune deletes backups in dir beyond the newest keep backups, and backups
+// older than maxAge. A keep or maxAge of zero disables that rule. The newest
+// backup is never deleted. The paths of the deleted backups are returned.
+func Prune(dir string, keep int, maxAge time.Duration, now time.Time) ([]string, error) {
+	infos, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	var deleted []string
+	for i, info := range infos {
+		if i == 0 {
+			continue
+		}
+		tooMany := keep > 0 && i >= keep
+		tooOld := maxAge > 0 && now.Sub(info.Time) > maxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := os.Remove(info.Path); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, info.Path)
+	}
+	return deleted, nil
+}
+
+// chunkWriter encrypts the data written to it in chunks. Each encrypted chunk
+// is prefixed with its length. Close writes an encrypted empty chunk that
+// marks the end of the data, so truncation can be detected.
+type chunkWriter struct {
+	w       io.Writer
+	crypter encrypt.Crypter
+	buf     []byte
+}
+
+func (cw *chunkWriter) Write(b []byte) (int, error) {
+	n := len(b)
+	for len(b) > 0 {
+		take := min(chunkSize-len(cw.buf), len(b))
+		cw.buf = append(cw.buf, b[:take]...)
+		b = b[take:]
+		if len(cw.buf) == chunkSize {
+			if err := cw.flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (cw *chunkWriter) flush() error {
+	enc, err := cw.crypter.Encrypt(cw.buf)
+	if err != nil {
+		return err
+	}
+	cw.buf = cw.buf[:0]
+	if _, err := cw.w.Write(encode.Uint32Bytes(uint32(len(enc)))); err != nil {
+		return err
+	}
+	_, err = cw.w.Write(enc)
+	return err
+}
+
+// Close writes any buffered data and the end marker.
+func (cw *chunkWriter) Close() error {
+	if len(cw.buf) > 0 {
+		if err := cw.flush(); err != nil {
+			return err
+		}
+	}
+	return cw.flush()
+}
+
+// chunkReader decrypts the data written by a chunkWriter.
+type chunkReader struct {
+	r       io.Reader
+	crypter encrypt.Crypter
+	buf     *bytes.Reader
+	done    bool
+}
+
+func (cr *chunkReader) Read(b []byte) (int, error) {
+	for cr.buf == nil || cr.buf.Len() == 0 {
+		if cr.done {
+			return 0, io.EOF
+		}
+		if err := cr.next(); err != nil {
+			return 0, err
+		}
+	}
+	return cr.buf.Read(b)
+}
+
+func (cr *chunkReader) next() error {
+	lenB := make([]byte, 4)
+	if _, err := io.ReadFull(cr.r, lenB); err != nil {
+		return fmt.Errorf("%w: backup is truncated", ErrInvalidBackup)
+	}
+	l := intCoder.Uint32(lenB)
+	if l > maxChunkSize {
+		return fmt.Errorf("%w: chunk too large", ErrInvalidBackup)
+	}
+	enc := make([]byte, l)
+	if _, err := io.ReadFull(cr.r, enc); err != nil {
+		return fmt.Errorf("%w: backup is truncated", ErrInvalidBackup)
+	}
+	b, err := cr.crypter.Decrypt(enc)
+	if err != nil {
+		return fmt.Errorf("%w: decryption error: %v", ErrInvalidBackup, err)
+	}
+	if len(b) == 0 {
+		// End marker. There should be nothing after it.
+		if n, _ := cr.r.Read(make([]byte, 1)); n > 0 {
+			return fmt.Errorf("%w: data after end of backup", ErrInvalidBackup)
+		}
+		cr.done = true
+	}
+	cr.buf = bytes.NewReader(b)
+	return nil
+}
@@ -0,0 +1,263 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package backup
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/encrypt"
+	"go.etcd.io/bbolt"
+)
+
+var tPW = []byte("abc")
+
+// makeFiles writes a bbolt database and a config file to dir.
+func makeFiles(t *testing.T, dir, val string) []*File {
+	t.Helper()
+	dbPath := filepath.Join(dir, "test.db")
+	db, err := bbolt.Open(dbPath, 0600, nil)
+	if err != nil {
+		t.Fatalf("error creating db: %v", err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		bkt, err := tx.CreateBucketIfNotExists([]byte("bucket"))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < 1000; i++ {
+			if err := bkt.Put([]byte{byte(i >> 8), byte(i)}, bytes.Repeat([]byte(val), 1000)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	db.Close()
+	if err != nil {
+		t.Fatalf("error populating db: %v", err)
+	}
+	cfgPath := filepath.Join(dir, "cfg.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"val":"`+val+`"}`), 0600); err != nil {
+		t.Fatalf("error writing config: %v", err)
+	}
+	return []*File{
+		{Name: DBFile, Path: dbPath, BoltDB: true},
+		{Name: MMConfigFile, Path: cfgPath},
+	}
+}
+
+func TestCreateAndRestore(t *testing.T) {
+	srcDir, backupDir, dstDir := t.TempDir(), t.TempDir(), t.TempDir()
+	files := makeFiles(t, srcDir, "a")
+
+	crypter := encrypt.NewCrypter(tPW)
+	defer crypter.Close()
+	now := time.Now()
+	path, err := Create(backupDir, crypter, files, now)
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	manifest, err := Verify(path, tPW)
+	if err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	if len(manifest.Files) != 2 || manifest.Created != now.UnixMilli() {
+		t.Fatalf("wrong manifest: %+v", manifest)
+	}
+
+	if _, err := Verify(path, []byte("wrong")); err == nil {
+		t.Fatalf("no error for wrong password")
+	}
+
+	// Restore over existing files.
+	dbDst, cfgDst := filepath.Join(dstDir, "restored.db"), filepath.Join(dstDir, "restored.json")
+	makeFiles(t, dstDir, "b")
+	if err := os.Rename(filepath.Join(dstDir, "test.db"), dbDst); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Restore(path, tPW, map[string]string{DBFile: dbDst, MMConfigFile: cfgDst}); err != nil {
+		t.Fatalf("Restore error: %v", err)
+	}
+	for _, pair := range [][2]string{{files[0].Path, dbDst}, {files[1].Path, cfgDst}} {
+		orig, _ := os.ReadFile(pair[0])
+		restored, err := os.ReadFile(pair[1])
+		if err != nil {
+			t.Fatalf("error reading restored file: %v", err)
+		}
+		if !bytes.Equal(orig, restored) {
+			t.Fatalf("restored %s does not match original", pair[1])
+		}
+	}
+	// The replaced db is kept.
+	replaced, _ := filepath.Glob(dbDst + replacedSuffix + "*")
+	if len(replaced) != 1 {
+		t.Fatalf("expected 1 replaced db, found %d", len(replaced))
+	}
+	staged, _ := filepath.Glob(filepath.Join(dstDir, "*"+restoreSuffix))
+	if len(staged) != 0 {
+		t.Fatalf("staged files not cleaned up: %v", staged)
+	}
+}
+
+func TestRestoreRollback(t *testing.T) {
+	srcDir, backupDir, dstDir := t.TempDir(), t.TempDir(), t.TempDir()
+	files := makeFiles(t, srcDir, "a")
+	crypter := encrypt.NewCrypter(tPW)
+	defer crypter.Close()
+	path, err := Create(backupDir, crypter, files, time.Now())
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+
+	live := makeFiles(t, dstDir, "b")
+	dbDst, cfgDst := live[0].Path, live[1].Path
+	liveDB, _ := os.ReadFile(dbDst)
+	liveCfg, _ := os.ReadFile(cfgDst)
+
+	// The db is restored first, then restoring the config fails.
+	defer func() { rename = os.Rename }()
+	rename = func(src, dst string) error {
+		if dst == cfgDst && src == cfgDst+restoreSuffix {
+			return errors.New("test error")
+		}
+		return os.Rename(src, dst)
+	}
+	if _, err := Restore(path, tPW, map[string]string{DBFile: dbDst, MMConfigFile: cfgDst}); err == nil {
+		t.Fatal("no Restore error")
+	}
+	for _, pair := range []struct {
+		path string
+		b    []byte
+	}{{dbDst, liveDB}, {cfgDst, liveCfg}} {
+		b, err := os.ReadFile(pair.path)
+		if err != nil {
+			t.Fatalf("error reading %s: %v", pair.path, err)
+		}
+		if !bytes.Equal(b, pair.b) {
+			t.Fatalf("%s not rolled back", pair.path)
+		}
+	}
+	leftover, _ := filepath.Glob(filepath.Join(dstDir, "*"+replacedSuffix+"*"))
+	staged, _ := filepath.Glob(filepath.Join(dstDir, "*"+restoreSuffix))
+	if len(leftover)+len(staged) != 0 {
+		t.Fatalf("files not cleaned up: %v, %v", leftover, staged)
+	}
+}
+
+func TestCorruptBackup(t *testing.T) {
+	srcDir, backupDir, dstDir := t.TempDir(), t.TempDir(), t.TempDir()
+	files := makeFiles(t, srcDir, "a")
+	crypter := encrypt.NewCrypter(tPW)
+	defer crypter.Close()
+	path, err := Create(backupDir, crypter, files, time.Now())
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"flipped byte", func() []byte {
+			c := bytes.Clone(b)
+			c[len(c)/2] ^= 0x01
+			return c
+		}()},
+		{"truncated", b[:len(b)-100]},
+		// The end marker is a 4-byte length and a 43-byte encrypted empty
+		// chunk.
+		{"end marker removed", b[:len(b)-47]},
+		{"appended", append(bytes.Clone(b), 0)},
+		{"not a backup", []byte("not a backup file at all")},
+	}
+	for _, tt := range tests {
+		corruptPath := filepath.Join(backupDir, "corrupt"+FileExt)
+		if err := os.WriteFile(corruptPath, tt.b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(corruptPath, tPW); err == nil {
+			t.Fatalf("%s: no Verify error", tt.name)
+		}
+		dst := filepath.Join(dstDir, "restored.db")
+		if err := os.WriteFile(dst, []byte("live"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Restore(corruptPath, tPW, map[string]string{DBFile: dst}); err == nil {
+			t.Fatalf("%s: no Restore error", tt.name)
+		}
+		if live, _ := os.ReadFile(dst); string(live) != "live" {
+			t.Fatalf("%s: live file replaced by corrupt backup", tt.name)
+		}
+	}
+}
+
+func TestCorruptDB(t *testing.T) {
+	srcDir, backupDir := t.TempDir(), t.TempDir()
+	dbPath := filepath.Join(srcDir, "bad.db")
+	if err := os.WriteFile(dbPath, bytes.Repeat([]byte{1}, 8192), 0600); err != nil {
+		t.Fatal(err)
+	}
+	crypter := encrypt.NewCrypter(tPW)
+	defer crypter.Close()
+	_, err := Create(backupDir, crypter, []*File{{Name: DBFile, Path: dbPath, BoltDB: true}}, time.Now())
+	if !errors.Is(err, ErrInvalidBackup) {
+		t.Fatalf("expected ErrInvalidBackup for corrupt db, got %v", err)
+	}
+	if infos, _ := List(backupDir); len(infos) != 0 {
+		t.Fatalf("unverified backup was kept")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+	for i := 0; i < 6; i++ {
+		stamp := now.Add(-time.Duration(i) * 24 * time.Hour).UTC().Format(fileTimeFormat)
+		if err := os.WriteFile(filepath.Join(dir, filePrefix+stamp+FileExt), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Not a scheduled backup.
+	if err := os.WriteFile(filepath.Join(dir, "other"+FileExt), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := Prune(dir, 5, 0, now)
+	if err != nil {
+		t.Fatalf("Prune error: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Fatalf("expected 1 deleted, got %d", len(deleted))
+	}
+
+	deleted, err = Prune(dir, 0, 50*time.Hour, now)
+	if err != nil {
+		t.Fatalf("Prune error: %v", err)
+	}
+	if len(deleted) != 2 {
+		t.Fatalf("expected 2 deleted by age, got %d", len(deleted))
+	}
+
+	// The newest is kept even if it's too old.
+	deleted, err = Prune(dir, 0, time.Nanosecond, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Prune error: %v", err)
+	}
+	infos, _ := List(dir)
+	if len(deleted) != 2 || len(infos) != 1 || !infos[0].Time.Equal(now.UTC()) {
+		t.Fatalf("wrong backups kept: deleted %d, kept %d", len(deleted), len(infos))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other"+FileExt)); err != nil {
+		t.Fatalf("unrelated file deleted")
+	}
+}
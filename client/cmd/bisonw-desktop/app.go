@@ -83,6 +83,10 @@ func mainCore() error {
 		return fmt.Errorf("configuration error: %w", err)
 	}
 
+	if err := app.RegisterEVMChains(cfg.EVMChainsFile); err != nil {
+		return fmt.Errorf("error registering EVM chains: %w", err)
+	}
+
 	// Filter registered assets.
 	asset.SetNetwork(cfg.Net)
 
@@ -191,6 +195,7 @@ func mainCore() error {
 	if err != nil {
 		return fmt.Errorf("error creating market maker: %w", err)
 	}
+	app.AddBackupSources(clientCore, marketMaker, &cfg.Config)
 	cm := dex.NewConnectionMaster(marketMaker)
 	if err := cm.ConnectOnce(appCtx); err != nil {
 		return fmt.Errorf("error connecting market maker")
@@ -25,6 +25,7 @@ import (
 	"decred.org/dcrdex/client/rpcserver"
 	"decred.org/dcrdex/client/webserver"
 	"decred.org/dcrdex/dex"
+	"golang.org/x/term"
 )
 
 // appName defines the application name.
@@ -39,6 +40,10 @@ var (
 func runCore(cfg *app.Config) error {
 	defer cancel() // for the earliest returns
 
+	if err := app.RegisterEVMChains(cfg.EVMChainsFile); err != nil {
+		return fmt.Errorf("error registering EVM chains: %w", err)
+	}
+
 	asset.SetNetwork(cfg.Net)
 
 	// If explicitly running without web server then you must run the rpc
@@ -81,6 +86,12 @@ func runCore(cfg *app.Config) error {
 		}
 	}()
 
+	if cfg.Restore != "" {
+		if err := restoreBackup(cfg); err != nil {
+			return fmt.Errorf("error restoring backup: %w", err)
+		}
+	}
+
 	// Prepare the Core.
 	clientCore, err := core.New(cfg.Core(logMaker.Logger("CORE")))
 	if err != nil {
@@ -91,6 +102,7 @@ func runCore(cfg *app.Config) error {
 	if err != nil {
 		return fmt.Errorf("error creating market maker: %w", err)
 	}
+	app.AddBackupSources(clientCore, marketMaker, cfg)
 
 	// Catch interrupt signal (e.g. ctrl+c), prompting to shutdown if the user
 	// is logged in, and there are active orders or matches.
@@ -182,6 +194,26 @@ func runCore(cfg *app.Config) error {
 	return nil
 }
 
+// restoreBackup prompts for the app password and restores the backup
+// specified with --restore.
+func restoreBackup(cfg *app.Config) error {
+	fmt.Printf("Restoring backup %s.\n", cfg.Restore)
+	fmt.Print("App password when the backup was made: ")
+	pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return fmt.Errorf("error reading password: %w", err)
+	}
+	manifest, err := app.RestoreBackup(cfg, cfg.Restore, pw)
+	if err != nil {
+		return err
+	}
+	for _, f := range manifest.Files {
+		log.Infof("Restored %s (%d bytes) from backup created %s", f.Name, f.Size, time.UnixMilli(manifest.Created))
+	}
+	return nil
+}
+
 // promptShutdown checks if there are active orders and asks confirmation to
 // shutdown if there are. The return value indicates if it is safe to stop Core
 // or if the user has confirmed they want to shutdown with active orders.
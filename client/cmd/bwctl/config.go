@@ -38,6 +38,7 @@ type config struct {
 	Config       string   `short:"C" long:"config" description:"Path to configuration file"`
 	RPCUser      string   `short:"u" long:"rpcuser" description:"RPC username"`
 	RPCPass      string   `short:"P" long:"rpcpass" default-mask:"-" description:"RPC password"`
+	RPCToken     string   `long:"rpctoken" default-mask:"-" description:"RPC API token, used instead of the RPC username and password"`
 	RPCAddr      string   `short:"a" long:"rpcaddr" description:"RPC server to connect to"`
 	RPCCert      string   `short:"c" long:"rpccert" description:"RPC server certificate chain for validation"`
 	PrintJSON    bool     `short:"j" long:"json" description:"Print json messages sent and received"`
@@ -82,6 +83,7 @@ func configure() (*config, []string, bool, error) {
 	// specified.
 	if cfg.ListCommands {
 		fmt.Println(rpcserver.ListCommands(false, true))
+		fmt.Println(watchUsage)
 		return nil, nil, stop, nil
 	}
 
@@ -89,8 +89,12 @@ func sendPostRequest(marshalledJSON []byte, cfg *config) (*msgjson.Message, erro
 	httpRequest.Close = true
 	httpRequest.Header.Set("Content-Type", "application/json")
 
-	// Configure basic access authorization.
-	httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPass)
+	// Configure bearer token or basic access authorization.
+	if cfg.RPCToken != "" {
+		httpRequest.Header.Set("Authorization", "Bearer "+cfg.RPCToken)
+	} else {
+		httpRequest.SetBasicAuth(cfg.RPCUser, cfg.RPCPass)
+	}
 
 	// Create the new HTTP client that is configured according to the user-
 	// specified options and submit the request.
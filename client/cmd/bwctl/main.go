@@ -203,6 +203,10 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("no command specified\n%s", listCmdMessage)
 	}
 
+	if args[0] == watchCmd {
+		return runWatch(ctx, cfg, args[1:])
+	}
+
 	// Convert remaining command line args to a slice of interface values
 	// to be passed along as parameters to new command creation function.
 	//
@@ -266,6 +266,13 @@ func setFieldFromString(fv reflect.Value, goType reflect.Type, s string) error {
 			return err
 		}
 		fv.SetInt(v)
+	case reflect.Float32, reflect.Float64:
+		bits := int(goType.Size()) * 8
+		v, err := strconv.ParseFloat(s, bits)
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(v)
 	case reflect.Map:
 		mp := reflect.New(goType)
 		if err := json.Unmarshal([]byte(s), mp.Interface()); err != nil {
@@ -278,8 +285,6 @@ func setFieldFromString(fv reflect.Value, goType reflect.Type, s string) error {
 			return err
 		}
 		fv.Set(sp.Elem())
-	// NOTE: float32/float64 are not currently handled. Add a case here if a
-	// future params struct uses floating-point fields.
 	default:
 		return fmt.Errorf("unsupported field type %v", goType)
 	}
@@ -5,6 +5,7 @@ package main
 
 import (
 	"reflect"
+	"slices"
 	"testing"
 
 	"decred.org/dcrdex/client/core"
@@ -612,6 +613,19 @@ func TestBondOpts(t *testing.T) {
 	if bo.TargetTier == nil || *bo.TargetTier != 5 {
 		t.Fatal("bondopts: wrong targetTier")
 	}
+
+	// Bond asset list for the bond renewal planner.
+	p, err = buildPayload("bondopts", nil, []string{"localhost", "", "", "", "", "[42,0]", "true"})
+	if err != nil {
+		t.Fatalf("bondopts: %v", err)
+	}
+	bo = p.(*core.BondOptionsForm)
+	if bo.TargetTier != nil || bo.BondAssetIDs == nil || !slices.Equal(*bo.BondAssetIDs, []uint32{42, 0}) {
+		t.Fatal("bondopts: wrong bondAssetIDs")
+	}
+	if bo.SplitBonds == nil || !*bo.SplitBonds {
+		t.Fatal("bondopts: wrong splitBonds")
+	}
 }
 
 func TestPasswordPrompts(t *testing.T) {
@@ -850,6 +864,8 @@ func TestAllRoutesHaveCorrectType(t *testing.T) {
 				args = append(args, "0")
 			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 				args = append(args, "0")
+			case reflect.Float32, reflect.Float64:
+				args = append(args, "0")
 			case reflect.Ptr:
 				args = append(args, "0")
 			case reflect.Map, reflect.Slice, reflect.Interface:
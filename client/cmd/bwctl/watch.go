@@ -0,0 +1,678 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/client/rpcclient"
+	"decred.org/dcrdex/client/rpcserver"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+)
+
+const (
+	// watchCmd is the bwctl subcommand for the live view. It is handled by
+	// bwctl rather than the RPC server.
+	watchCmd = "watch"
+	// watchEventLines is the number of recent notifications shown.
+	watchEventLines = 8
+	// watchBotsInterval is how often the bot status is refreshed without a
+	// bot notification.
+	watchBotsInterval = 10 * time.Second
+	// clearScreen moves the cursor home and clears the terminal.
+	clearScreen = "\033[H\033[2J"
+)
+
+// watchUsage is listed with the RPC server's commands.
+const watchUsage = "watch [-host] [-market] [-assets] [-types] [-jsonl] [-refresh]"
+
+// watchOpts are the options for the watch subcommand.
+type watchOpts struct {
+	filter  *watchFilter
+	jsonl   bool
+	refresh time.Duration
+}
+
+// watchFilter selects what is shown. Empty fields match everything.
+type watchFilter struct {
+	host string
+	// market is a market name, e.g. dcr_btc. base and quote are its asset
+	// symbols.
+	market, base, quote string
+	assets              map[string]bool
+	types               map[string]bool
+}
+
+func parseWatchArgs(args []string) (*watchOpts, error) {
+	fs := flag.NewFlagSet(watchCmd, flag.ContinueOnError)
+	host := fs.String("host", "", "Only show orders and bots for this DEX host.")
+	market := fs.String("market", "", "Only show orders, bots and wallets for this market, e.g. dcr_btc.")
+	assets := fs.String("assets", "", "Only show wallets, and orders and bots on markets, with these comma-separated asset symbols.")
+	types := fs.String("types", "", "Only show notifications of these comma-separated types, e.g. order,match.")
+	jsonl := fs.Bool("jsonl", false, "Write the notifications as JSON lines instead of showing the view.")
+	refresh := fs.Duration("refresh", time.Second, "Minimum time between view updates.")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [options] %s\n\n", appName, watchUsage)
+		fmt.Fprintln(fs.Output(), "Show a continuously updating view of active orders and their matches, wallet")
+		fmt.Fprintln(fs.Output(), "balances and sync status, and market making bots. With -jsonl, the core")
+		fmt.Fprintln(fs.Output(), "notifications are written as JSON lines instead, e.g. to pipe into other tools.")
+		fmt.Fprintln(fs.Output())
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected arguments for %s: %v", watchCmd, fs.Args())
+	}
+	if *refresh <= 0 {
+		return nil, errors.New("refresh must be positive")
+	}
+	f := &watchFilter{
+		host:   *host,
+		market: strings.ToLower(*market),
+		assets: splitSet(*assets),
+		types:  splitSet(*types),
+	}
+	if f.market != "" {
+		var found bool
+		f.base, f.quote, found = strings.Cut(f.market, "_")
+		if !found || f.base == "" || f.quote == "" {
+			return nil, fmt.Errorf("invalid market %q, expected e.g. dcr_btc", *market)
+		}
+	}
+	return &watchOpts{filter: f, jsonl: *jsonl, refresh: *refresh}, nil
+}
+
+// splitSet parses a comma-separated list into a set of lower case values.
+func splitSet(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(strings.ToLower(v)); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// anyAsset checks whether any of the symbols pass the assets filter.
+func (f *watchFilter) anyAsset(symbols ...string) bool {
+	if len(f.assets) == 0 {
+		return true
+	}
+	for _, s := range symbols {
+		if f.assets[strings.ToLower(s)] {
+			return true
+		}
+	}
+	return false
+}
+
+// passWallet checks whether the asset's wallet should be shown.
+func (f *watchFilter) passWallet(symbol string) bool {
+	symbol = strings.ToLower(symbol)
+	if f.market != "" && symbol != f.base && symbol != f.quote {
+		return false
+	}
+	return f.anyAsset(symbol)
+}
+
+// passMarket checks whether an order or bot on the market should be shown.
+func (f *watchFilter) passMarket(host, baseSymbol, quoteSymbol string) bool {
+	if f.host != "" && host != f.host {
+		return false
+	}
+	if f.market != "" && (strings.ToLower(baseSymbol) != f.base || strings.ToLower(quoteSymbol) != f.quote) {
+		return false
+	}
+	return f.anyAsset(baseSymbol, quoteSymbol)
+}
+
+// passNote checks whether the notification should be shown. Notifications
+// without a host, market or asset are only filtered by type.
+func (f *watchFilter) passNote(n core.Notification) bool {
+	if len(f.types) > 0 && !f.types[n.Type()] {
+		return false
+	}
+	switch n := n.(type) {
+	case *core.OrderNote:
+		if n.Order != nil {
+			return f.passMarket(n.Order.Host, n.Order.BaseSymbol, n.Order.QuoteSymbol)
+		}
+	case *core.MatchNote:
+		base, quote, _ := strings.Cut(n.MarketID, "_")
+		return f.passMarket(n.Host, base, quote)
+	case *core.BalanceNote:
+		return f.passWallet(dex.BipIDSymbol(n.AssetID))
+	case *core.WalletSyncNote:
+		return f.passWallet(dex.BipIDSymbol(n.AssetID))
+	case *core.WalletStateNote:
+		if n.Wallet != nil {
+			return f.passWallet(n.Wallet.Symbol)
+		}
+	case *core.ConnEventNote:
+		return f.host == "" || n.Host == f.host
+	}
+	return true
+}
+
+// watchClient is the part of the RPC client used by the watcher.
+type watchClient interface {
+	Exchanges(ctx context.Context) (map[string]*core.Exchange, error)
+	Wallets(ctx context.Context) ([]*core.WalletState, error)
+	MyOrders(ctx context.Context, params *rpcserver.MyOrdersParams) (rpcserver.MyOrdersResponse, error)
+	MMStatus(ctx context.Context) (*mm.Status, error)
+}
+
+// watcher keeps the state for the live view.
+type watcher struct {
+	cl     watchClient
+	opts   *watchOpts
+	out    io.Writer
+	header string
+
+	units   map[uint32]*dex.UnitInfo
+	hosts   map[string]comms.ConnectionStatus
+	wallets map[uint32]*core.WalletState
+	orders  rpcserver.MyOrdersResponse
+	bots    *mm.Status
+	botsErr error
+	events  []string
+
+	ordersStale bool
+	botsStale   bool
+	changed     bool
+}
+
+func newWatcher(cl watchClient, opts *watchOpts, out io.Writer, header string) *watcher {
+	return &watcher{
+		cl:      cl,
+		opts:    opts,
+		out:     out,
+		header:  header,
+		units:   make(map[uint32]*dex.UnitInfo),
+		hosts:   make(map[string]comms.ConnectionStatus),
+		wallets: make(map[uint32]*core.WalletState),
+	}
+}
+
+// load loads the initial state.
+func (w *watcher) load(ctx context.Context) error {
+	xcs, err := w.cl.Exchanges(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading exchanges: %w", err)
+	}
+	for host, xc := range xcs {
+		w.hosts[host] = xc.ConnectionStatus
+		for assetID, a := range xc.Assets {
+			ui := a.UnitInfo
+			w.units[assetID] = &ui
+		}
+	}
+	wallets, err := w.cl.Wallets(ctx)
+	if err != nil {
+		return fmt.Errorf("error loading wallets: %w", err)
+	}
+	for _, ws := range wallets {
+		w.wallets[ws.AssetID] = ws
+	}
+	if err := w.loadOrders(ctx); err != nil {
+		return err
+	}
+	w.loadBots(ctx)
+	w.changed = true
+	return nil
+}
+
+func (w *watcher) loadOrders(ctx context.Context) error {
+	orders, err := w.cl.MyOrders(ctx, &rpcserver.MyOrdersParams{Host: w.opts.filter.host})
+	if err != nil {
+		return fmt.Errorf("error loading orders: %w", err)
+	}
+	w.orders = orders
+	w.ordersStale = false
+	w.changed = true
+	return nil
+}
+
+// loadBots loads the bot status. Market making may not be enabled, so errors
+// are shown rather than returned.
+func (w *watcher) loadBots(ctx context.Context) {
+	w.bots, w.botsErr = w.cl.MMStatus(ctx)
+	w.botsStale = false
+	w.changed = true
+}
+
+// handleNote updates the state for a notification.
+func (w *watcher) handleNote(n core.Notification) {
+	switch n := n.(type) {
+	case *core.OrderNote, *core.MatchNote:
+		w.ordersStale = true
+	case *core.BalanceNote:
+		if ws := w.wallets[n.AssetID]; ws != nil {
+			ws.Balance = n.Balance
+			w.changed = true
+		}
+	case *core.WalletSyncNote:
+		if ws := w.wallets[n.AssetID]; ws != nil {
+			ws.SyncStatus = n.SyncStatus
+			ws.SyncProgress = n.SyncProgress
+			if n.SyncStatus != nil {
+				ws.Synced = n.SyncStatus.Synced
+			}
+			w.changed = true
+		}
+	case *core.WalletStateNote:
+		if n.Wallet != nil {
+			w.wallets[n.Wallet.AssetID] = n.Wallet
+			w.changed = true
+		}
+	case *core.ConnEventNote:
+		w.hosts[n.Host] = n.ConnectionStatus
+		w.changed = true
+	case *rpcclient.UnknownNote:
+		switch n.Type() {
+		case mm.NoteTypeRunStats, mm.NoteTypeCEXProblems:
+			w.botsStale = true
+		}
+	}
+	// Data notifications are not meant for display.
+	if n.Severity() <= db.Data || n.Subject() == "" || !w.opts.filter.passNote(n) {
+		return
+	}
+	stamp := time.UnixMilli(int64(n.Time())).Format(time.TimeOnly)
+	line := fmt.Sprintf("%s  %-8s %s", stamp, n.Type(), n.Subject())
+	if d := strings.Join(strings.Fields(n.Details()), " "); d != "" {
+		line += ": " + d
+	}
+	w.events = append(w.events, line)
+	if len(w.events) > watchEventLines {
+		w.events = w.events[len(w.events)-watchEventLines:]
+	}
+	w.changed = true
+}
+
+// run updates the view until the stream ends or ctx is canceled.
+func (w *watcher) run(ctx context.Context, stream *rpcclient.NoteStream) error {
+	tick := time.NewTicker(w.opts.refresh)
+	defer tick.Stop()
+	botsTick := time.NewTicker(watchBotsInterval)
+	defer botsTick.Stop()
+	w.render()
+	for {
+		select {
+		case n, ok := <-stream.C:
+			if !ok {
+				if err := stream.Err(); err != nil {
+					return fmt.Errorf("notification stream ended: %w", err)
+				}
+				return nil
+			}
+			w.handleNote(n)
+		case <-botsTick.C:
+			w.botsStale = true
+		case <-tick.C:
+			if w.ordersStale {
+				if err := w.loadOrders(ctx); err != nil {
+					return err
+				}
+			}
+			if w.botsStale {
+				w.loadBots(ctx)
+			}
+			if w.changed {
+				w.render()
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// render writes the view.
+func (w *watcher) render() {
+	w.changed = false
+	var b bytes.Buffer
+	b.WriteString(clearScreen)
+	fmt.Fprintf(&b, "%s  %s\n", w.header, time.Now().Format(time.TimeOnly))
+	w.renderHosts(&b)
+	w.renderWallets(&b)
+	w.renderOrders(&b)
+	w.renderBots(&b)
+	if len(w.events) > 0 {
+		b.WriteString("\nEVENTS\n")
+		for _, e := range w.events {
+			b.WriteString(e + "\n")
+		}
+	}
+	w.out.Write(b.Bytes())
+}
+
+func (w *watcher) renderHosts(b *bytes.Buffer) {
+	hosts := make([]string, 0, len(w.hosts))
+	for host := range w.hosts {
+		if w.opts.filter.host == "" || host == w.opts.filter.host {
+			hosts = append(hosts, host)
+		}
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		fmt.Fprintf(b, "DEX %s %s\n", host, w.hosts[host])
+	}
+}
+
+func (w *watcher) renderWallets(b *bytes.Buffer) {
+	wallets := make([]*core.WalletState, 0, len(w.wallets))
+	for _, ws := range w.wallets {
+		if w.opts.filter.passWallet(ws.Symbol) {
+			wallets = append(wallets, ws)
+		}
+	}
+	if len(wallets) == 0 {
+		return
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].Symbol < wallets[j].Symbol })
+	b.WriteString("\nWALLETS\n")
+	tw := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ASSET\tAVAILABLE\tLOCKED\tIMMATURE\tSYNC\tPEERS\tSTATUS")
+	for _, ws := range wallets {
+		var avail, locked, immature string
+		if ws.Balance != nil && ws.Balance.Balance != nil {
+			avail = w.amount(ws.AssetID, ws.Balance.Available)
+			locked = w.amount(ws.AssetID, ws.Balance.Locked)
+			immature = w.amount(ws.AssetID, ws.Balance.Immature)
+		}
+		sync := "synced"
+		if !ws.Synced {
+			sync = fmt.Sprintf("%.1f%%", ws.SyncProgress*100)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", ws.Symbol, avail, locked, immature, sync, ws.PeerCount, walletStatus(ws))
+	}
+	tw.Flush()
+}
+
+func walletStatus(ws *core.WalletState) string {
+	switch {
+	case ws.Disabled:
+		return "disabled"
+	case !ws.Running:
+		return "off"
+	case !ws.Open:
+		return "locked"
+	}
+	return "ready"
+}
+
+func (w *watcher) renderOrders(b *bytes.Buffer) {
+	var orders []*rpcserver.MyOrder
+	for _, o := range w.orders {
+		base, quote := dex.BipIDSymbol(o.BaseID), dex.BipIDSymbol(o.QuoteID)
+		if orderActive(o) && w.opts.filter.passMarket(o.Host, base, quote) {
+			orders = append(orders, o)
+		}
+	}
+	b.WriteString("\nORDERS\n")
+	if len(orders) == 0 {
+		b.WriteString("no active orders\n")
+		return
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].SubmitTime < orders[j].SubmitTime })
+	tw := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ORDER\tHOST\tMARKET\tSIDE\tTYPE\tRATE\tQTY\tFILLED\tSTATUS")
+	for _, o := range orders {
+		side := "buy"
+		if o.Sell {
+			side = "sell"
+		}
+		var rate string
+		if o.Rate > 0 {
+			rate = w.rate(o.BaseID, o.QuoteID, o.Rate)
+		}
+		status := o.Status
+		if o.Cancelling {
+			status += " (cancelling)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", shortID(o.ID), o.Host, o.MarketName, side, o.Type,
+			rate, w.amount(o.BaseID, o.Quantity), w.amount(o.BaseID, o.Filled), status)
+		for _, m := range o.Matches {
+			if !matchActive(m) {
+				continue
+			}
+			fmt.Fprintf(tw, "  match %s\t\t\t%s\t\t%s\t%s\t\t%s\n", shortID(m.MatchID), strings.ToLower(m.Side),
+				w.rate(o.BaseID, o.QuoteID, m.Rate), w.amount(o.BaseID, m.Qty), matchStep(m))
+		}
+	}
+	tw.Flush()
+}
+
+func (w *watcher) renderBots(b *bytes.Buffer) {
+	if w.botsErr != nil {
+		fmt.Fprintf(b, "\nBOTS\nunavailable: %v\n", w.botsErr)
+		return
+	}
+	if w.bots == nil {
+		return
+	}
+	var bots []*mm.BotStatus
+	for _, bot := range w.bots.Bots {
+		cfg := bot.Config
+		if cfg != nil && w.opts.filter.passMarket(cfg.Host, dex.BipIDSymbol(cfg.BaseID), dex.BipIDSymbol(cfg.QuoteID)) {
+			bots = append(bots, bot)
+		}
+	}
+	if len(bots) == 0 {
+		return
+	}
+	b.WriteString("\nBOTS\n")
+	tw := tabwriter.NewWriter(b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "HOST\tMARKET\tSTATUS\tRUNNING\tMATCHES\tTRADED USD\tPROFIT USD\tPROBLEMS")
+	for _, bot := range bots {
+		cfg := bot.Config
+		mktName, _ := dex.MarketName(cfg.BaseID, cfg.QuoteID)
+		status := "stopped"
+		switch {
+		case bot.Stopping:
+			status = "stopping"
+		case bot.Running:
+			status = "running"
+		}
+		var running, matches, traded, profit string
+		if s := bot.RunStats; s != nil {
+			running = time.Since(time.Unix(s.StartTime, 0)).Round(time.Second).String()
+			matches = fmt.Sprint(s.CompletedMatches)
+			traded = fmt.Sprintf("%.2f", s.TradedUSD)
+			if s.ProfitLoss != nil {
+				profit = fmt.Sprintf("%.2f", s.ProfitLoss.Profit)
+			}
+		}
+		var problems string
+		if bot.CEXProblems != nil {
+			problems = "cex"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", cfg.Host, mktName, status, running, matches, traded, profit, problems)
+	}
+	tw.Flush()
+}
+
+// amount formats the atoms in conventional units, if known.
+func (w *watcher) amount(assetID uint32, v uint64) string {
+	if ui := w.units[assetID]; ui != nil {
+		return ui.FormatConventional(v)
+	}
+	return fmt.Sprintf("%d atoms", v)
+}
+
+// rate formats the message-rate as a conventional rate, if known.
+func (w *watcher) rate(baseID, quoteID uint32, rate uint64) string {
+	baseUI, quoteUI := w.units[baseID], w.units[quoteID]
+	if baseUI == nil || quoteUI == nil {
+		return fmt.Sprintf("%d", rate)
+	}
+	return fmt.Sprintf("%g", calc.ConventionalRate(rate, *baseUI, *quoteUI))
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+// orderActive checks whether the order is booked or has active matches.
+func orderActive(o *rpcserver.MyOrder) bool {
+	if o.Status == order.OrderStatusEpoch.String() || o.Status == order.OrderStatusBooked.String() {
+		return true
+	}
+	for _, m := range o.Matches {
+		if matchActive(m) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchActive checks whether the match is still swapping or may need a
+// refund.
+func matchActive(m *rpcserver.Match) bool {
+	if m.IsCancel || m.Refund != "" || m.Status == order.MatchComplete.String() || m.Status == order.MatchConfirmed.String() {
+		return false
+	}
+	// A revoked match without our swap needs nothing more.
+	return !m.Revoked || m.Swap != ""
+}
+
+// matchStep describes the current step of the swap for our side.
+func matchStep(m *rpcserver.Match) string {
+	maker := m.Side == order.Maker.String()
+	var step string
+	switch m.Status {
+	case order.NewlyMatched.String():
+		step = "waiting for maker swap"
+		if maker {
+			step = "sending our swap"
+		}
+	case order.MakerSwapCast.String():
+		step = "sending our swap"
+		if maker {
+			step = "waiting for taker swap"
+		}
+	case order.TakerSwapCast.String():
+		step = "waiting for maker redeem"
+		if maker {
+			step = "redeeming"
+		}
+	case order.MakerRedeemed.String():
+		step = "redeeming"
+		if maker {
+			step = "redeemed"
+		}
+	default:
+		step = m.Status
+	}
+	if m.Revoked {
+		step += " (revoked, refund pending)"
+	}
+	return step
+}
+
+// writeJSONL writes the notifications that pass the filter as JSON lines
+// until the stream ends or ctx is canceled.
+func writeJSONL(ctx context.Context, out io.Writer, f *watchFilter, notes <-chan core.Notification) error {
+	for {
+		select {
+		case n, ok := <-notes:
+			if !ok {
+				return nil
+			}
+			if !f.passNote(n) {
+				continue
+			}
+			b, err := noteJSON(n)
+			if err != nil {
+				return err
+			}
+			if _, err := out.Write(append(b, '\n')); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// noteJSON encodes the notification on one line. Unknown notifications are
+// written as received.
+func noteJSON(n core.Notification) ([]byte, error) {
+	if un, ok := n.(*rpcclient.UnknownNote); ok && len(un.Raw) > 0 {
+		var b bytes.Buffer
+		if err := json.Compact(&b, un.Raw); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+	return json.Marshal(n)
+}
+
+// runWatch runs the watch subcommand.
+func runWatch(ctx context.Context, cfg *config, args []string) error {
+	opts, err := parseWatchArgs(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if cfg.Proxy != "" {
+		return errors.New("watch does not support a proxy")
+	}
+	cert, err := os.ReadFile(cfg.RPCCert)
+	if err != nil {
+		return fmt.Errorf("error reading RPC certificate: %w", err)
+	}
+	cl, err := rpcclient.New(&rpcclient.Config{
+		Addr:  cfg.RPCAddr,
+		User:  cfg.RPCUser,
+		Pass:  cfg.RPCPass,
+		Token: cfg.RPCToken,
+		Cert:  cert,
+	})
+	if err != nil {
+		return err
+	}
+	// Subscribe before loading the state so that no updates are missed.
+	stream, err := cl.SubscribeNotifications(ctx)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if opts.jsonl {
+		if err := writeJSONL(ctx, os.Stdout, opts.filter, stream.C); err != nil {
+			return err
+		}
+		return stream.Err()
+	}
+	w := newWatcher(cl, opts, os.Stdout, fmt.Sprintf("%s %s %s", appName, watchCmd, cfg.RPCAddr))
+	if err := w.load(ctx); err != nil {
+		return err
+	}
+	return w.run(ctx, stream)
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/client/rpcclient"
+	"decred.org/dcrdex/client/rpcserver"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/order"
+)
+
+const tWatchHost = "dex.example.com:7232"
+
+type tWatchClient struct {
+	orders  rpcserver.MyOrdersResponse
+	bots    *mm.Status
+	nOrders int
+}
+
+func (c *tWatchClient) Exchanges(context.Context) (map[string]*core.Exchange, error) {
+	return map[string]*core.Exchange{
+		tWatchHost: {
+			Host:             tWatchHost,
+			ConnectionStatus: comms.Connected,
+			Assets: map[uint32]*dex.Asset{
+				42: {UnitInfo: dex.UnitInfo{Conventional: dex.Denomination{Unit: "DCR", ConversionFactor: 1e8}}},
+				0:  {UnitInfo: dex.UnitInfo{Conventional: dex.Denomination{Unit: "BTC", ConversionFactor: 1e8}}},
+			},
+		},
+	}, nil
+}
+
+func (c *tWatchClient) Wallets(context.Context) ([]*core.WalletState, error) {
+	return []*core.WalletState{
+		{Symbol: "dcr", AssetID: 42, Running: true, Open: true, Synced: true},
+		{Symbol: "btc", AssetID: 0, Running: true, Open: true, SyncProgress: 0.5},
+		{Symbol: "ltc", AssetID: 2, Running: true, Open: true, Synced: true},
+	}, nil
+}
+
+func (c *tWatchClient) MyOrders(context.Context, *rpcserver.MyOrdersParams) (rpcserver.MyOrdersResponse, error) {
+	c.nOrders++
+	return c.orders, nil
+}
+
+func (c *tWatchClient) MMStatus(context.Context) (*mm.Status, error) {
+	return c.bots, nil
+}
+
+func tWatchOpts(t *testing.T, args ...string) *watchOpts {
+	t.Helper()
+	opts, err := parseWatchArgs(args)
+	if err != nil {
+		t.Fatalf("parseWatchArgs error: %v", err)
+	}
+	return opts
+}
+
+func TestParseWatchArgs(t *testing.T) {
+	opts := tWatchOpts(t, "-market", "DCR_BTC", "-assets", "dcr, btc", "-types", "order", "-jsonl")
+	f := opts.filter
+	if f.base != "dcr" || f.quote != "btc" || !f.assets["btc"] || !f.types["order"] || !opts.jsonl {
+		t.Fatalf("wrong options %+v, %+v", opts, f)
+	}
+	for _, args := range [][]string{
+		{"-market", "dcrbtc"},
+		{"-refresh", "0s"},
+		{"extra"},
+	} {
+		if _, err := parseWatchArgs(args); err == nil {
+			t.Fatalf("no error for %v", args)
+		}
+	}
+}
+
+func TestWatchFilter(t *testing.T) {
+	orderNote := &core.OrderNote{
+		Notification: db.NewNotification(core.NoteTypeOrder, core.TopicBuyOrderPlaced, "Order placed", "", db.Poke),
+		Order:        &core.Order{Host: tWatchHost, BaseSymbol: "dcr", QuoteSymbol: "btc"},
+	}
+	balanceNote := &core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+		AssetID:      2,
+	}
+	tests := []struct {
+		args      []string
+		note      core.Notification
+		wantPass  bool
+		passLabel string
+	}{
+		{nil, orderNote, true, "no filter"},
+		{[]string{"-market", "dcr_btc"}, orderNote, true, "market"},
+		{[]string{"-market", "btc_usdt.eth"}, orderNote, false, "other market"},
+		{[]string{"-host", "other.tld"}, orderNote, false, "other host"},
+		{[]string{"-assets", "btc"}, orderNote, true, "quote asset"},
+		{[]string{"-types", "match"}, orderNote, false, "other type"},
+		{[]string{"-market", "dcr_btc"}, balanceNote, false, "asset not in market"},
+		{[]string{"-assets", "ltc"}, balanceNote, true, "asset"},
+		{[]string{"-host", "other.tld"}, balanceNote, true, "no host"},
+	}
+	for _, test := range tests {
+		f := tWatchOpts(t, test.args...).filter
+		if pass := f.passNote(test.note); pass != test.wantPass {
+			t.Fatalf("%s: expected pass = %t, got %t", test.passLabel, test.wantPass, pass)
+		}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	cl := &tWatchClient{
+		orders: rpcserver.MyOrdersResponse{{
+			Host:       tWatchHost,
+			MarketName: "dcr_btc",
+			BaseID:     42,
+			QuoteID:    0,
+			ID:         "0123456789abcdef",
+			Type:       order.LimitOrderType.String(),
+			Sell:       true,
+			Rate:       1e6,
+			Quantity:   2e8,
+			Filled:     1e8,
+			Status:     order.OrderStatusBooked.String(),
+			Matches: []*rpcserver.Match{{
+				MatchID: "fedcba9876543210",
+				Status:  order.MakerSwapCast.String(),
+				Side:    order.Maker.String(),
+				Rate:    1e6,
+				Qty:     1e8,
+				Swap:    "swap:0",
+			}},
+		}, {
+			Host:       tWatchHost,
+			MarketName: "dcr_btc",
+			BaseID:     42,
+			QuoteID:    0,
+			ID:         "aaaaaaaaaaaaaaaa",
+			Status:     order.OrderStatusExecuted.String(),
+		}},
+		bots: &mm.Status{Bots: []*mm.BotStatus{{
+			Config:  &mm.BotConfig{Host: tWatchHost, BaseID: 42, QuoteID: 0},
+			Running: true,
+		}}},
+	}
+	var out bytes.Buffer
+	w := newWatcher(cl, tWatchOpts(t, "-market", "dcr_btc"), &out, "test")
+	if err := w.load(context.Background()); err != nil {
+		t.Fatalf("load error: %v", err)
+	}
+	w.render()
+	view := out.String()
+	for _, s := range []string{"DEX " + tWatchHost + " connected", "dcr", "50.0%", "01234567",
+		"1 DCR", "0.01", "match fedcba98", "waiting for taker swap", "running"} {
+		if !strings.Contains(view, s) {
+			t.Fatalf("%q not in view:\n%s", s, view)
+		}
+	}
+	for _, s := range []string{"ltc", "aaaaaaaa"} {
+		if strings.Contains(view, s) {
+			t.Fatalf("%q should not be in view:\n%s", s, view)
+		}
+	}
+
+	// Balance updates are applied directly. Order updates reload the orders.
+	w.handleNote(&core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+		AssetID:      42,
+		Balance:      &core.WalletBalance{Balance: &db.Balance{Balance: asset.Balance{Available: 5e8}}},
+	})
+	w.handleNote(&core.OrderNote{
+		Notification: db.NewNotification(core.NoteTypeOrder, core.TopicBuyOrderPlaced, "Order placed", "Buying 1 DCR", db.Poke),
+		Order:        &core.Order{Host: tWatchHost, BaseSymbol: "dcr", QuoteSymbol: "btc"},
+	})
+	if !w.ordersStale || !w.changed || len(w.events) != 1 {
+		t.Fatalf("notes not handled")
+	}
+	if err := w.loadOrders(context.Background()); err != nil || cl.nOrders != 2 {
+		t.Fatalf("orders not reloaded: %v", err)
+	}
+	out.Reset()
+	w.render()
+	view = out.String()
+	if !strings.Contains(view, "5 DCR") || !strings.Contains(view, "Order placed: Buying 1 DCR") {
+		t.Fatalf("updates not in view:\n%s", view)
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	notes := make(chan core.Notification, 3)
+	notes <- &core.OrderNote{
+		Notification: db.NewNotification(core.NoteTypeOrder, core.TopicBuyOrderPlaced, "Order placed", "", db.Poke),
+		Order:        &core.Order{Host: tWatchHost, BaseSymbol: "dcr", QuoteSymbol: "btc"},
+	}
+	notes <- &core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+		AssetID:      42,
+	}
+	notes <- &rpcclient.UnknownNote{
+		Notification: db.NewNotification(mm.NoteTypeRunStats, "", "", "", db.Data),
+		Raw:          json.RawMessage(`{"type": "runstats",` + "\n" + `"host": "dex.example.com:7232"}`),
+	}
+	close(notes)
+	var out bytes.Buffer
+	f := tWatchOpts(t, "-types", "order,runstats").filter
+	if err := writeJSONL(context.Background(), &out, f, notes); err != nil {
+		t.Fatalf("writeJSONL error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %s", len(lines), out.String())
+	}
+	var hdr db.Notification
+	for i, wantType := range []string{core.NoteTypeOrder, mm.NoteTypeRunStats} {
+		if err := json.Unmarshal([]byte(lines[i]), &hdr); err != nil || hdr.NoteType != wantType {
+			t.Fatalf("line %d: wrong note %s (err = %v)", i, lines[i], err)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// bwrefund reads a Bison Wallet emergency refund kit and broadcasts the signed
+// refund transactions for swaps that are past their lock time. The refunds can
+// be broadcast through a node's JSON-RPC sendrawtransaction, or through an
+// Electrum server. Swaps without a signed refund are listed with the details
+// needed to refund them manually.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset/btc/electrum"
+	"decred.org/dcrdex/client/refundkit"
+	"golang.org/x/term"
+)
+
+const requestTimeout = 30 * time.Second
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] <refund kit file>\n\n", os.Args[0])
+		fmt.Fprintln(os.Stderr, "Lists the swaps in a refund kit. With -broadcast, the signed refunds for")
+		fmt.Fprintln(os.Stderr, "the -asset that are past their lock time are broadcast through the node")
+		fmt.Fprintln(os.Stderr, "at -rpcaddr or the Electrum server at -electrum.")
+		fmt.Fprintln(os.Stderr)
+		flag.PrintDefaults()
+	}
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func run() error {
+	var symbol, rpcAddr, rpcUser, rpcPass, rpcCert, electrumAddr string
+	var broadcast, electrumTLS, showHex bool
+	flag.StringVar(&symbol, "asset", "", "Only use swaps for the asset with this symbol, e.g. btc. Required with -broadcast.")
+	flag.BoolVar(&broadcast, "broadcast", false, "Broadcast the refunds that are past their lock time.")
+	flag.BoolVar(&showHex, "hex", false, "Show the signed refund transactions, e.g. to broadcast them with a block explorer.")
+	flag.StringVar(&rpcAddr, "rpcaddr", "", "Node JSON-RPC URL, e.g. http://127.0.0.1:8332.")
+	flag.StringVar(&rpcUser, "rpcuser", "", "Node JSON-RPC username.")
+	flag.StringVar(&rpcPass, "rpcpass", "", "Node JSON-RPC password.")
+	flag.StringVar(&rpcCert, "rpccert", "", "Node TLS certificate file, for a self-signed certificate.")
+	flag.StringVar(&electrumAddr, "electrum", "", "Electrum server host:port.")
+	flag.BoolVar(&electrumTLS, "electrumtls", false, "Connect to the Electrum server with TLS. The server certificate is not verified.")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		return errors.New("no refund kit file")
+	}
+	kitPath := flag.Arg(0)
+
+	var broadcaster func(ctx context.Context, tx []byte) (string, error)
+	if broadcast {
+		if symbol == "" {
+			return errors.New("-asset is required with -broadcast")
+		}
+		switch {
+		case rpcAddr != "" && electrumAddr != "":
+			return errors.New("set only one of -rpcaddr and -electrum")
+		case rpcAddr != "":
+			client, err := newRPCClient(rpcAddr, rpcUser, rpcPass, rpcCert)
+			if err != nil {
+				return err
+			}
+			broadcaster = client.sendRawTransaction
+		case electrumAddr != "":
+			broadcaster = func(ctx context.Context, tx []byte) (string, error) {
+				return electrumBroadcast(ctx, electrumAddr, electrumTLS, tx)
+			}
+		default:
+			return errors.New("-broadcast requires -rpcaddr or -electrum")
+		}
+	}
+
+	fmt.Print("App password: ")
+	pw, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Println()
+	if err != nil {
+		return fmt.Errorf("error reading password: %w", err)
+	}
+	kit, err := refundkit.Read(kitPath, pw)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Refund kit updated %s\n", time.UnixMilli(kit.Updated).Format(time.RFC1123))
+	now := time.Now()
+	var n, refunded, failed int
+	for _, e := range kit.Entries {
+		if symbol != "" && !strings.EqualFold(e.Symbol, symbol) {
+			continue
+		}
+		n++
+		printEntry(e, now, showHex)
+		if broadcaster == nil || !e.Refundable(now) {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		txID, err := broadcaster(ctx, e.SignedRefund)
+		cancel()
+		if err != nil {
+			// The swap may have been redeemed or refunded already.
+			fmt.Printf("  Error broadcasting refund: %v\n", err)
+			failed++
+			continue
+		}
+		fmt.Printf("  Refund broadcast: %s\n", txID)
+		refunded++
+	}
+	fmt.Printf("\n%d swaps", n)
+	if broadcaster != nil {
+		fmt.Printf(", %d refunds broadcast, %d failed", refunded, failed)
+	}
+	fmt.Println()
+	return nil
+}
+
+func printEntry(e *refundkit.Entry, now time.Time, showHex bool) {
+	lockTime := time.Unix(e.LockTime, 0)
+	fmt.Println()
+	fmt.Printf("%s swap %s\n", strings.ToUpper(e.Symbol), e.SwapCoin)
+	fmt.Printf("  Host: %s, order %s, match %s\n", e.Host, e.OrderID, e.MatchID)
+	fmt.Printf("  Value: %d atoms\n", e.Value)
+	fmt.Printf("  Lock time: %s\n", lockTime.Format(time.RFC1123))
+	fmt.Printf("  Contract: %s\n", e.Contract)
+	fmt.Printf("  Secret hash: %s\n", e.SecretHash)
+	if e.Details != "" {
+		fmt.Printf("  Details: %s\n", e.Details)
+	}
+	switch {
+	case len(e.SignedRefund) == 0:
+		fmt.Println("  No signed refund. Refund manually with the contract details after the lock time.")
+	case now.Before(lockTime):
+		fmt.Printf("  Refundable in %s\n", lockTime.Sub(now).Round(time.Minute))
+	default:
+		fmt.Println("  Refundable now")
+	}
+	if showHex && len(e.SignedRefund) > 0 {
+		fmt.Printf("  Signed refund: %s\n", e.SignedRefund)
+	}
+}
+
+// rpcClient is a minimal JSON-RPC client for a node's sendrawtransaction.
+type rpcClient struct {
+	url, user, pass string
+	http            *http.Client
+}
+
+func newRPCClient(url, user, pass, certPath string) (*rpcClient, error) {
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if certPath != "" {
+		pem, err := os.ReadFile(certPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid certificate file %s", certPath)
+		}
+		httpClient.Transport = &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12},
+		}
+	}
+	return &rpcClient{url: url, user: user, pass: pass, http: httpClient}, nil
+}
+
+func (c *rpcClient) sendRawTransaction(ctx context.Context, tx []byte) (string, error) {
+	reqBody, err := json.Marshal(map[string]any{
+		"jsonrpc": "1.0",
+		"id":      1,
+		"method":  "sendrawtransaction",
+		"params":  []string{hex.EncodeToString(tx)},
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(reqBody))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.user != "" || c.pass != "" {
+		req.SetBasicAuth(c.user, c.pass)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	var res struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return "", fmt.Errorf("unexpected response (%s): %s", resp.Status, strings.TrimSpace(string(b)))
+	}
+	if res.Error != nil {
+		return "", fmt.Errorf("node error %d: %s", res.Error.Code, res.Error.Message)
+	}
+	return res.Result, nil
+}
+
+func electrumBroadcast(ctx context.Context, addr string, useTLS bool, tx []byte) (string, error) {
+	opts := new(electrum.ConnectOpts)
+	if useTLS {
+		host, _, _ := strings.Cut(addr, ":")
+		// Electrum servers commonly use self-signed certificates. A signed
+		// transaction can't be altered, so the worst a rogue server can do is
+		// not broadcast it.
+		opts.TLSConfig = &tls.Config{ServerName: host, InsecureSkipVerify: true}
+	}
+	sc, err := electrum.ConnectServer(ctx, addr, opts)
+	if err != nil {
+		return "", fmt.Errorf("error connecting to %s: %w", addr, err)
+	}
+	defer func() {
+		sc.Shutdown()
+		<-sc.Done()
+	}()
+	var txID string
+	err = sc.Request(ctx, "blockchain.transaction.broadcast", []string{hex.EncodeToString(tx)}, &txID)
+	return txID, err
+}
@@ -0,0 +1,189 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"decred.org/dcrdex/client/backup"
+)
+
+const (
+	// backupCheckInterval is how often the scheduled backup loop checks
+	// whether a backup is due.
+	backupCheckInterval = time.Minute
+	// backupRetryDelay is the delay before a failed scheduled backup is
+	// retried.
+	backupRetryDelay = 15 * time.Minute
+)
+
+// errNoBackupKey is returned by writeBackup when the user is not logged in, so
+// there is no key to encrypt the backup with.
+var errNoBackupKey = errors.New("not logged in")
+
+// BackupSource writes the current contents of a file to include in the
+// scheduled backups. If the file does not exist, the BackupSource should
+// return an error wrapping fs.ErrNotExist, and the file is left out of the
+// backup.
+type BackupSource func(w io.Writer) error
+
+type backupSource struct {
+	write  BackupSource
+	boltDB bool
+}
+
+// AddBackupSource adds a file to the scheduled backups. The file is named name
+// in the backup. If boltDB is true, the file is integrity checked as a bbolt
+// database when the backup is verified or restored. The client database is
+// always included, as backup.DBFile. AddBackupSource should be called before
+// Run.
+func (c *Core) AddBackupSource(name string, boltDB bool, src BackupSource) {
+	c.backupMtx.Lock()
+	defer c.backupMtx.Unlock()
+	c.backupSources[name] = &backupSource{write: src, boltDB: boltDB}
+}
+
+// scheduledBackups checks whether scheduled backups are configured.
+func (c *Core) scheduledBackups() bool {
+	return c.cfg.BackupDir != "" && c.cfg.BackupInterval > 0
+}
+
+// setBackupKey derives the backup encryption key from the app password. The
+// key is kept in memory while the user is logged in.
+func (c *Core) setBackupKey(pw []byte) {
+	if !c.scheduledBackups() {
+		return
+	}
+	crypter := c.newCrypter(pw)
+	c.backupMtx.Lock()
+	defer c.backupMtx.Unlock()
+	if c.backupCrypter != nil {
+		c.backupCrypter.Close()
+	}
+	c.backupCrypter = crypter
+}
+
+// replaceBackupKey replaces the backup encryption key after a password change,
+// if the user is logged in.
+func (c *Core) replaceBackupKey(pw []byte) {
+	c.backupMtx.Lock()
+	loggedIn := c.backupCrypter != nil
+	c.backupMtx.Unlock()
+	if loggedIn {
+		c.setBackupKey(pw)
+	}
+}
+
+// clearBackupKey zeros and discards the backup encryption key.
+func (c *Core) clearBackupKey() {
+	c.backupMtx.Lock()
+	defer c.backupMtx.Unlock()
+	if c.backupCrypter != nil {
+		c.backupCrypter.Close()
+		c.backupCrypter = nil
+	}
+}
+
+// writeBackup creates an encrypted backup of the database and the other
+// backup sources in the backup directory, and prunes old backups.
+func (c *Core) writeBackup() (string, error) {
+	// Hold the lock for the duration so that the key is not closed while in
+	// use.
+	c.backupMtx.Lock()
+	defer c.backupMtx.Unlock()
+	if c.backupCrypter == nil {
+		return "", errNoBackupKey
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(c.cfg.DBPath), "backup-staging")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	dbPath := filepath.Join(tmpDir, backup.DBFile)
+	if err := c.db.BackupTo(dbPath, true, false); err != nil {
+		return "", fmt.Errorf("error copying database: %w", err)
+	}
+	files := []*backup.File{{Name: backup.DBFile, Path: dbPath, BoltDB: true}}
+	for name, src := range c.backupSources {
+		p := filepath.Join(tmpDir, name)
+		if err := writeBackupSource(p, src.write); err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				c.log.Debugf("Skipping backup of %s. File does not exist.", name)
+				continue
+			}
+			return "", fmt.Errorf("error writing %s: %w", name, err)
+		}
+		files = append(files, &backup.File{Name: name, Path: p, BoltDB: src.boltDB})
+	}
+
+	now := time.Now()
+	path, err := backup.Create(c.cfg.BackupDir, c.backupCrypter, files, now)
+	if err != nil {
+		return "", err
+	}
+	deleted, err := backup.Prune(c.cfg.BackupDir, c.cfg.BackupKeep, c.cfg.BackupMaxAge, now)
+	if err != nil {
+		c.log.Errorf("Error pruning old backups: %v", err)
+	}
+	for _, p := range deleted {
+		c.log.Debugf("Deleted old backup %s", p)
+	}
+	return path, nil
+}
+
+func writeBackupSource(path string, src BackupSource) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := src(f); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// runBackups writes scheduled backups while the user is logged in.
+func (c *Core) runBackups(ctx context.Context) {
+	var next time.Time
+	infos, err := backup.List(c.cfg.BackupDir)
+	if err != nil {
+		c.log.Errorf("Error listing backups in %s: %v", c.cfg.BackupDir, err)
+	} else if len(infos) > 0 {
+		next = infos[0].Time.Add(c.cfg.BackupInterval)
+	}
+
+	tick := time.NewTicker(backupCheckInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return
+		}
+		if time.Now().Before(next) {
+			continue
+		}
+		path, err := c.writeBackup()
+		switch {
+		case errors.Is(err, errNoBackupKey):
+			continue
+		case err != nil:
+			c.log.Errorf("Scheduled backup failed: %v", err)
+			next = time.Now().Add(backupRetryDelay)
+		default:
+			c.log.Infof("Wrote encrypted backup %s", path)
+			next = time.Now().Add(c.cfg.BackupInterval)
+		}
+	}
+}
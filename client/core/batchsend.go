@@ -0,0 +1,187 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+// maxBatchRecipients is the maximum number of recipients in a batch send.
+const maxBatchRecipients = 1000
+
+// conventionalToAtoms parses a decimal amount in conventional units, e.g.
+// "1.25", to atomic units without floating point rounding.
+func conventionalToAtoms(s string, convFactor uint64) (uint64, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if whole == "" && frac == "" {
+		return 0, errors.New("empty amount")
+	}
+	decimals := int(math.Round(math.Log10(float64(convFactor))))
+	if len(frac) > decimals {
+		return 0, fmt.Errorf("amount %q has more than %d decimal places", s, decimals)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	var f uint64
+	if frac != "" {
+		if f, err = strconv.ParseUint(frac+strings.Repeat("0", decimals-len(frac)), 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid amount %q", s)
+		}
+	}
+	if w > (math.MaxUint64-f)/convFactor {
+		return 0, fmt.Errorf("amount %q is too large", s)
+	}
+	return w*convFactor + f, nil
+}
+
+// ParseBatchSendCSV parses batch send recipients from CSV data. Each record is
+// an address and an amount in conventional units, e.g. "Dsa...,1.5". An
+// optional header row and lines beginning with # are ignored.
+func (c *Core) ParseBatchSendCSV(assetID uint32, csvData string) ([]*asset.BatchRecipient, error) {
+	ui, err := asset.UnitInfo(assetID)
+	if err != nil {
+		return nil, fmt.Errorf("unknown asset %d", assetID)
+	}
+	r := csv.NewReader(strings.NewReader(csvData))
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	var recipients []*asset.BatchRecipient
+	for i := 0; ; i++ {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+		addr := strings.TrimSpace(rec[0])
+		v, err := conventionalToAtoms(rec[1], ui.Conventional.ConversionFactor)
+		if err != nil {
+			if i == 0 { // header
+				continue
+			}
+			line, _ := r.FieldPos(0)
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		recipients = append(recipients, &asset.BatchRecipient{Address: addr, Value: v})
+	}
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	return recipients, nil
+}
+
+// batchSender returns the asset.BatchSender for the asset's wallet.
+func (c *Core) batchSender(assetID uint32) (*xcWallet, asset.BatchSender, error) {
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return nil, nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	sender, is := wallet.Wallet.(asset.BatchSender)
+	if !is {
+		return nil, nil, fmt.Errorf("%s wallet does not support batch sends", unbip(assetID))
+	}
+	return wallet, sender, nil
+}
+
+func validateBatchRecipients(recipients []*asset.BatchRecipient) (total uint64, err error) {
+	if len(recipients) == 0 {
+		return 0, errors.New("no recipients")
+	}
+	if len(recipients) > maxBatchRecipients {
+		return 0, fmt.Errorf("too many recipients. %d > %d", len(recipients), maxBatchRecipients)
+	}
+	for i, r := range recipients {
+		if r.Address == "" {
+			return 0, fmt.Errorf("recipient %d has no address", i)
+		}
+		if r.Value == 0 {
+			return 0, fmt.Errorf("cannot send zero to recipient %d (%s)", i, r.Address)
+		}
+		total += r.Value
+	}
+	return total, nil
+}
+
+// SendBatch sends the exact values to multiple recipients. Fees are taken from
+// the wallet. UTXO-based wallets pay every recipient in a single transaction,
+// while account-based wallets send N sequential transactions for N
+// recipients, each paying its own fee. The IDs of the broadcast transactions
+// are returned, even if an error is encountered part way through the batch.
+func (c *Core) SendBatch(pw []byte, assetID uint32, recipients []*asset.BatchRecipient) ([]string, error) {
+	var crypter encrypt.Crypter
+	// Empty password can be provided if wallet is already unlocked. Webserver
+	// and RPCServer should not allow empty password.
+	if len(pw) > 0 {
+		var err error
+		crypter, err = c.encryptionKey(pw)
+		if err != nil {
+			return nil, fmt.Errorf("Trade password error: %w", err)
+		}
+		defer crypter.Close()
+	}
+
+	total, err := validateBatchRecipients(recipients)
+	if err != nil {
+		return nil, err
+	}
+	wallet, sender, err := c.batchSender(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.connectAndUnlock(crypter, wallet); err != nil {
+		return nil, err
+	}
+	if err = wallet.checkPeersAndSyncStatus(); err != nil {
+		return nil, err
+	}
+
+	txIDs, err := sender.SendBatch(recipients, c.feeSuggestionAny(assetID))
+	if err != nil {
+		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
+		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
+		if len(txIDs) > 0 {
+			c.updateAssetBalance(assetID)
+		}
+		return txIDs, err
+	}
+
+	sentValue := wallet.Info().UnitInfo.ConventionalString(total)
+	subject, details := c.formatDetails(TopicBatchSendSuccess, sentValue, unbip(assetID), len(recipients), strings.Join(txIDs, ", "))
+	c.notify(newSendNote(TopicBatchSendSuccess, subject, details, db.Success))
+
+	c.updateAssetBalance(assetID)
+
+	return txIDs, nil
+}
+
+// EstimateBatchSendTxFee returns an estimate of the total fees for sending to
+// the recipients. For account-based wallets, this is the sum of the fees of
+// the transactions to each recipient.
+func (c *Core) EstimateBatchSendTxFee(assetID uint32, recipients []*asset.BatchRecipient) (uint64, error) {
+	if _, err := validateBatchRecipients(recipients); err != nil {
+		return 0, err
+	}
+	_, sender, err := c.batchSender(assetID)
+	if err != nil {
+		return 0, err
+	}
+	return sender.EstimateBatchSendTxFee(recipients, c.feeSuggestionAny(assetID))
+}
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex/encode"
+)
+
+func TestConventionalToAtoms(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    uint64
+		wantErr bool
+	}{
+		{s: "1", want: 1e8},
+		{s: "1.5", want: 1.5e8},
+		{s: " 0.00000001 ", want: 1},
+		{s: ".25", want: 0.25e8},
+		{s: "21000000.12345678", want: 2_100_000_012_345_678},
+		{s: "0.000000001", wantErr: true},
+		{s: "-1", wantErr: true},
+		{s: "1e8", wantErr: true},
+		{s: "", wantErr: true},
+		{s: "184467440737.09551616", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := conventionalToAtoms(tt.s, 1e8)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%q: no error", tt.s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tt.s, err)
+		}
+		if v != tt.want {
+			t.Fatalf("%q: wanted %d, got %d", tt.s, tt.want, v)
+		}
+	}
+}
+
+func TestParseBatchSendCSV(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const csvData = "address,amount\n" +
+		"# payroll\n" +
+		"addr1, 1.5\n" +
+		"addr2,0.00000001\n"
+
+	recipients, err := tCore.ParseBatchSendCSV(tUTXOAssetA.ID, csvData)
+	if err != nil {
+		t.Fatalf("ParseBatchSendCSV error: %v", err)
+	}
+	if len(recipients) != 2 {
+		t.Fatalf("expected 2 recipients, got %d", len(recipients))
+	}
+	if r := recipients[0]; r.Address != "addr1" || r.Value != 1.5e8 {
+		t.Fatalf("wrong first recipient %+v", r)
+	}
+	if r := recipients[1]; r.Address != "addr2" || r.Value != 1 {
+		t.Fatalf("wrong second recipient %+v", r)
+	}
+
+	for name, data := range map[string]string{
+		"bad amount":    "addr1,1\naddr2,abc\n",
+		"extra field":   "addr1,1,x\n",
+		"header only":   "address,amount\n",
+		"unknown asset": "addr1,1\n",
+	} {
+		assetID := tUTXOAssetA.ID
+		if name == "unknown asset" {
+			assetID = 12345
+		}
+		if _, err := tCore.ParseBatchSendCSV(assetID, data); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+}
+
+func TestSendBatch(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.sendCoin = &tCoin{id: encode.RandomBytes(36)}
+
+	recipients := []*asset.BatchRecipient{
+		{Address: "addr1", Value: 1e8},
+		{Address: "addr2", Value: 2e8},
+	}
+
+	txIDs, err := tCore.SendBatch(tPW, tUTXOAssetA.ID, recipients)
+	if err != nil {
+		t.Fatalf("SendBatch error: %v", err)
+	}
+	if len(txIDs) != 1 || len(tWallet.batchRecipients) != 2 {
+		t.Fatalf("wrong batch send result")
+	}
+
+	if _, err := tCore.SendBatch(tPW, tUTXOAssetA.ID, nil); err == nil {
+		t.Fatalf("no error for no recipients")
+	}
+	zero := []*asset.BatchRecipient{{Address: "addr1", Value: 0}}
+	if _, err := tCore.SendBatch(tPW, tUTXOAssetA.ID, zero); err == nil {
+		t.Fatalf("no error for zero value")
+	}
+	if _, err := tCore.SendBatch(tPW, 12345, recipients); err == nil {
+		t.Fatalf("no error for unknown wallet")
+	}
+
+	tWallet.batchSendErr = tErr
+	if _, err := tCore.SendBatch(tPW, tUTXOAssetA.ID, recipients); err == nil {
+		t.Fatalf("no error for wallet error")
+	}
+	tWallet.batchSendErr = nil
+
+	tWallet.estFee = 1000
+	fee, err := tCore.EstimateBatchSendTxFee(tUTXOAssetA.ID, recipients)
+	if err != nil {
+		t.Fatalf("EstimateBatchSendTxFee error: %v", err)
+	}
+	if fee != 1000 {
+		t.Fatalf("wrong fee %d", fee)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
 	"sort"
 	"time"
 
@@ -204,13 +205,34 @@ func (c *Core) updateBondReserves(balanceCheckID ...uint32) {
 			return
 		}
 
-		bondAsset := bondAssets[dc.acct.bondAsset]
-		if bondAsset == nil {
-			// Logged at login auth.
+		// With a list of bond assets, reserve funds in the assets that the
+		// latest bond plan will post with, splitting the reserves as the plan
+		// splits the tiers. Without a plan, reserve in the primary bond asset.
+		plan := dc.acct.bondPlan
+		if len(dc.acct.bondAssets) == 0 || plan == nil || len(plan.Allocations) == 0 {
+			bondAsset := bondAssets[dc.acct.bondAsset]
+			if bondAsset == nil {
+				// Logged at login auth.
+				return
+			}
+			future := c.minBondReserves(dc, bondAsset)
+			reserves[bondAsset.ID] = append(reserves[bondAsset.ID], future)
 			return
 		}
-		future := c.minBondReserves(dc, bondAsset)
-		reserves[bondAsset.ID] = append(reserves[bondAsset.ID], future)
+		var allocatedTiers uint64
+		for _, a := range plan.Allocations {
+			allocatedTiers += a.Tiers
+		}
+		for _, a := range plan.Allocations {
+			bondAsset := bondAssets[a.AssetID]
+			if bondAsset == nil || bondAsset.Amt == 0 || allocatedTiers == 0 {
+				continue
+			}
+			tiers := c.minBondReserves(dc, bondAsset) / bondAsset.Amt
+			// Round up, so that the split reserves cover all of the tiers.
+			share := (tiers*a.Tiers + allocatedTiers - 1) / allocatedTiers
+			reserves[bondAsset.ID] = append(reserves[bondAsset.ID], share*bondAsset.Amt)
+		}
 	}
 
 	for _, dc := range c.dexConnections() {
@@ -226,7 +248,7 @@ func (c *Core) updateBondReserves(balanceCheckID ...uint32) {
 		if !found {
 			// Not selected as a bond asset for any exchanges.
 			bonder.SetBondReserves(0)
-			return
+			continue
 		}
 		var nominalReserves uint64
 		for _, v := range bondValues {
@@ -405,6 +427,7 @@ func (c *Core) bondStateOfDEX(dc *dexConnection, bondCfg *dexBondCfg) *dexAcctBo
 
 	state.Rep, state.TargetTier, state.EffectiveTier = dc.acct.rep, dc.acct.targetTier, dc.acct.rep.EffectiveTier()
 	state.BondAssetID, state.MaxBondedAmt, state.PenaltyComps = dc.acct.bondAsset, dc.acct.maxBondedAmt, dc.acct.penaltyComps
+	state.BondAssetIDs, state.SplitBonds, state.BondPlan = slices.Clone(dc.acct.bondAssets), dc.acct.splitBonds, dc.acct.bondPlan
 	state.inBonds, _ = dc.bondTotalInternal(state.BondAssetID)
 	// Screen the unexpired bonds slices.
 	dc.acct.bonds = filterExpiredBonds(dc.acct.bonds)
@@ -739,6 +762,11 @@ func (c *Core) rotateBonds(ctx context.Context) {
 
 		c.repostPendingBonds(dc, bondCfg, acctBondState, unlocked)
 
+		if len(acctBondState.BondAssetIDs) > 0 {
+			c.planAndPostBonds(dc, bondCfg, acctBondState, expiredStrength, unlocked)
+			continue
+		}
+
 		bondAsset := bondCfg.bondAssets[acctBondState.BondAssetID]
 		if bondAsset == nil {
 			if acctBondState.TargetTier > 0 {
@@ -758,6 +786,8 @@ func (c *Core) rotateBonds(ctx context.Context) {
 		c.postRequiredBonds(dc, bondCfg, acctBondState, bondAsset, wallet, expiredStrength, unlocked)
 	}
 
+	c.refundMeshBonds(ctx, now)
+
 	c.updateBondReserves()
 }
 
@@ -1127,7 +1157,9 @@ func (c *Core) nextBondKey(assetID uint32) (*secp256k1.PrivateKey, uint32, error
 
 // UpdateBondOptions sets the bond rotation options for a DEX host, including
 // the target trading tier, the preferred asset to use for bonds, and the
-// maximum amount allowable to be locked in bonds.
+// maximum amount allowable to be locked in bonds. With a list of bond assets,
+// the bond renewal planner chooses the assets for each renewal, and the first
+// listed asset is the primary bond asset.
 func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 	dc, _, err := c.dex(form.Host)
 	if err != nil {
@@ -1151,6 +1183,8 @@ func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 	var bondAssetID0 uint32 // old wallet's asset ID
 	var targetTier0, maxBondedAmt0 uint64
 	var penaltyComps0 uint16
+	var bondAssetIDs0 []uint32
+	var splitBonds0 bool
 	defer func() {
 		if (tierChanged || assetChanged) && (wallet != nil) {
 			if _, err := c.updateWalletBalance(wallet); err != nil {
@@ -1178,11 +1212,13 @@ func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 	// Revert to initial values if we encounter any error below.
 	bondAssetID0 = dc.acct.bondAsset
 	targetTier0, maxBondedAmt0, penaltyComps0 = dc.acct.targetTier, dc.acct.maxBondedAmt, dc.acct.penaltyComps
+	bondAssetIDs0, splitBonds0 = dc.acct.bondAssets, dc.acct.splitBonds
 	defer func() { // still under authMtx lock on defer stack
 		if !success {
 			dc.acct.bondAsset = bondAssetID0
 			dc.acct.maxBondedAmt = maxBondedAmt0
 			dc.acct.penaltyComps = penaltyComps0
+			dc.acct.bondAssets, dc.acct.splitBonds = bondAssetIDs0, splitBonds0
 			if dc.acct.targetTier > 0 || assetChanged {
 				dc.acct.targetTier = targetTier0
 			} // else the user was trying to clear target tier and the wallet was gone too
@@ -1194,7 +1230,33 @@ func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 	if form.BondAssetID != nil {
 		bondAssetID = *form.BondAssetID
 	}
+	bondAssetIDs := bondAssetIDs0
+	if form.BondAssetIDs != nil {
+		bondAssetIDs = slices.Clone(*form.BondAssetIDs)
+		if len(bondAssetIDs) > 0 && form.BondAssetID != nil && bondAssetIDs[0] != bondAssetID {
+			return fmt.Errorf("bond asset %v is not first in the list of bond assets", unbip(bondAssetID))
+		}
+		for i, assetID := range bondAssetIDs {
+			if slices.Contains(bondAssetIDs[:i], assetID) {
+				return fmt.Errorf("duplicate bond asset %v", unbip(assetID))
+			}
+			if bondAssets != nil && bondAssets[assetID] == nil {
+				return fmt.Errorf("dex %v does not support %v as a bond asset", dbAcct.Host, unbip(assetID))
+			}
+		}
+	} else if len(bondAssetIDs) > 0 && form.BondAssetID != nil {
+		// Move the new primary bond asset to the front of the list.
+		bondAssetIDs = append([]uint32{bondAssetID}, slices.DeleteFunc(slices.Clone(bondAssetIDs),
+			func(assetID uint32) bool { return assetID == bondAssetID })...)
+	}
+	if len(bondAssetIDs) > 0 {
+		bondAssetID = bondAssetIDs[0]
+	}
 	assetChanged = bondAssetID != bondAssetID0
+	dc.acct.bondAssets, dbAcct.BondAssets = bondAssetIDs, bondAssetIDs
+	if form.SplitBonds != nil {
+		dc.acct.splitBonds, dbAcct.SplitBonds = *form.SplitBonds, *form.SplitBonds
+	}
 
 	targetTier := targetTier0
 	if form.TargetTier != nil {
@@ -1298,9 +1360,14 @@ func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 			req := nominalReserves + feeReserves
 			c.log.Infof("%d DEX server(s) using %s for bonding a total of %d tiers. %d required includes %d in fee reserves. Current balance = %d",
 				n, unbip(bondAssetID), tiers, req, feeReserves, avail)
-			// If raising the tier or changing asset, enforce available funds.
+			// If raising the tier or changing asset, enforce available funds,
+			// unless the planner can use other assets.
 			if (assetChanged || targetTier > targetTier0) && req > avail {
-				return fmt.Errorf("insufficient funds. need %d, have %d", req, avail)
+				if len(bondAssetIDs) < 2 {
+					return fmt.Errorf("insufficient funds. need %d, have %d", req, avail)
+				}
+				c.log.Warnf("Insufficient %s funds for bonds. need %d, have %d. The bond planner may use other assets.",
+					unbip(bondAssetID), req, avail)
 			}
 		}
 
@@ -1315,10 +1382,12 @@ func (c *Core) UpdateBondOptions(form *BondOptionsForm) error {
 		dbAcct.MaxBondedAmt = maxBonded
 	}
 
+	dc.acct.bondPlan = nil // replaced on the next rotation
+
 	c.triggerBondRotation()
 
-	c.log.Debugf("Bond options for %v: target tier %d, bond asset %d, maxBonded %v",
-		dbAcct.Host, dc.acct.targetTier, dc.acct.bondAsset, dbAcct.MaxBondedAmt)
+	c.log.Debugf("Bond options for %v: target tier %d, bond asset %d, bond assets %v, split %t, maxBonded %v",
+		dbAcct.Host, dc.acct.targetTier, dc.acct.bondAsset, dc.acct.bondAssets, dc.acct.splitBonds, dbAcct.MaxBondedAmt)
 
 	if err = c.db.UpdateAccountInfo(dbAcct); err == nil {
 		success = true
@@ -1366,6 +1435,10 @@ func (c *Core) PostBond(form *PostBondForm) (*PostBondResult, error) {
 		return nil, fmt.Errorf("app not initialized")
 	}
 
+	if form.Addr == MeshHost {
+		return c.postMeshBond(form)
+	}
+
 	// Check that the bond amount is non-zero before we touch wallets and make
 	// connections to the DEX host.
 	if form.Bond == 0 {
@@ -0,0 +1,411 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/db"
+)
+
+const (
+	// bondPlanFeeTolerance is the fiat value below which differences in bond
+	// fees are ignored in favor of the user's order of bond assets.
+	bondPlanFeeTolerance = 0.05
+	// bondFeeRateExpiry is how long the planner reuses an asset's fee rate.
+	bondFeeRateExpiry = 5 * time.Minute
+)
+
+// bondFeeRate is a cached fee rate for the bond renewal planner.
+type bondFeeRate struct {
+	rate  uint64
+	stamp time.Time
+}
+
+// BondPlanCandidate is a bond asset considered by the bond renewal planner.
+type BondPlanCandidate struct {
+	AssetID uint32 `json:"assetID"`
+	Symbol  string `json:"symbol"`
+	// BondAmt is the server's bond amount for one tier.
+	BondAmt uint64 `json:"bondAmt"`
+	FeeRate uint64 `json:"feeRate"`
+	// Fees is the wallet's estimate of the fees for a bond.
+	Fees uint64 `json:"fees"`
+	// Available is the wallet balance available for bonds.
+	Available uint64 `json:"available"`
+	// MaxTiers is the number of tiers that Available can fund.
+	MaxTiers uint64 `json:"maxTiers"`
+	// FiatRate is the asset's fiat exchange rate, zero if unknown.
+	FiatRate float64 `json:"fiatRate"`
+	FeesFiat float64 `json:"feesFiat"`
+	TierFiat float64 `json:"tierFiat"`
+	// Reason explains how the asset is used in the plan, or why it is not.
+	Reason string `json:"reason"`
+}
+
+// BondPlanAllocation is the part of a bond renewal funded with one asset.
+type BondPlanAllocation struct {
+	AssetID    uint32  `json:"assetID"`
+	Symbol     string  `json:"symbol"`
+	Tiers      uint64  `json:"tiers"`
+	Amount     uint64  `json:"amount"`
+	FeeRate    uint64  `json:"feeRate"`
+	Fees       uint64  `json:"fees"`
+	AmountFiat float64 `json:"amountFiat"`
+	FeesFiat   float64 `json:"feesFiat"`
+}
+
+// BondPlan is a decision of the bond renewal planner, which chooses the
+// assets used to post bonds from the account's ordered list of bond assets.
+type BondPlan struct {
+	// Stamp is when the plan was made, in milliseconds.
+	Stamp int64 `json:"stamp"`
+	// Tiers is the number of tiers to post.
+	Tiers uint64 `json:"tiers"`
+	// Projected is true if no bonds are needed yet. The plan is then for the
+	// renewal of the target tier at NextRenewal, at current fees and prices.
+	Projected   bool  `json:"projected"`
+	NextRenewal int64 `json:"nextRenewal,omitempty"` // unix seconds
+	// RefundTime is the lock time of the new bonds, after which they can be
+	// refunded, in unix seconds.
+	RefundTime  int64                 `json:"refundTime,omitempty"`
+	Split       bool                  `json:"split"`
+	Allocations []*BondPlanAllocation `json:"allocations"`
+	// Candidates are the account's bond assets, in the user's order.
+	Candidates []*BondPlanCandidate `json:"candidates"`
+	// Shortfall is the number of tiers that could not be funded.
+	Shortfall uint64  `json:"shortfall"`
+	FeesFiat  float64 `json:"feesFiat"`
+	Decision  string  `json:"decision"`
+}
+
+// allocate adds an allocation of tiers funded with the candidate asset.
+func (p *BondPlan) allocate(bc *BondPlanCandidate, tiers uint64) {
+	p.Allocations = append(p.Allocations, &BondPlanAllocation{
+		AssetID:    bc.AssetID,
+		Symbol:     bc.Symbol,
+		Tiers:      tiers,
+		Amount:     tiers * bc.BondAmt,
+		FeeRate:    bc.FeeRate,
+		Fees:       bc.Fees,
+		AmountFiat: float64(tiers) * bc.TierFiat,
+		FeesFiat:   bc.FeesFiat,
+	})
+	p.FeesFiat += bc.FeesFiat
+	bc.Reason = fmt.Sprintf("funding %d tiers", tiers)
+}
+
+// feeLevel is the candidate's bond fees in units of bondPlanFeeTolerance.
+func (bc *BondPlanCandidate) feeLevel() float64 {
+	return math.Floor(bc.FeesFiat / bondPlanFeeTolerance)
+}
+
+// rankBondCandidates orders the candidates by the fiat value of their bond
+// fees, keeping the user's order for differences within bondPlanFeeTolerance.
+// Fees can't be compared without fiat rates for every candidate, so the user's
+// order is kept if any rate is missing.
+func rankBondCandidates(cands []*BondPlanCandidate) (ranked []*BondPlanCandidate, byFees bool) {
+	ranked = slices.Clone(cands)
+	for _, bc := range ranked {
+		if bc.FiatRate == 0 {
+			return ranked, false
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b *BondPlanCandidate) int {
+		return cmp.Compare(a.feeLevel(), b.feeLevel())
+	})
+	return ranked, true
+}
+
+// planBonds chooses the assets with which to post tiers from the candidates,
+// which are in the user's order of preference. A single bond costs less in
+// fees than several, so one asset is used if any can fund all of the tiers,
+// choosing by fees, then by preference. Otherwise, if split is true, the tiers
+// are spread over as many assets as needed. Candidates with a Reason set are
+// already excluded. Every candidate is given a Reason.
+func planBonds(tiers uint64, cands []*BondPlanCandidate, split bool) *BondPlan {
+	plan := &BondPlan{
+		Stamp:      time.Now().UnixMilli(),
+		Tiers:      tiers,
+		Split:      split,
+		Candidates: cands,
+	}
+	eligible := make([]*BondPlanCandidate, 0, len(cands))
+	for _, bc := range cands {
+		switch {
+		case bc.Reason != "":
+		case bc.MaxTiers == 0:
+			bc.Reason = "insufficient balance for a bond"
+		default:
+			eligible = append(eligible, bc)
+		}
+	}
+	if tiers == 0 {
+		for _, bc := range eligible {
+			bc.Reason = "not needed"
+		}
+		plan.Decision = "no bonds needed"
+		return plan
+	}
+	if len(eligible) == 0 {
+		plan.Shortfall = tiers
+		plan.Decision = "no usable bond asset"
+		return plan
+	}
+
+	ranked, byFees := rankBondCandidates(eligible)
+	explain := func(chosen *BondPlanCandidate) {
+		for _, bc := range ranked {
+			switch {
+			case bc.Reason != "": // allocated
+			case len(plan.Allocations) > 1:
+				bc.Reason = "not needed"
+			case bc.MaxTiers < tiers:
+				bc.Reason = fmt.Sprintf("can only fund %d of %d tiers", bc.MaxTiers, tiers)
+			case byFees && bc.feeLevel() > chosen.feeLevel():
+				bc.Reason = fmt.Sprintf("higher fees than %s", strings.ToUpper(chosen.Symbol))
+			default:
+				bc.Reason = fmt.Sprintf("lower preference than %s", strings.ToUpper(chosen.Symbol))
+			}
+		}
+	}
+	basis := "in preference order, without fiat rates to compare fees"
+	if byFees {
+		basis = "by fees, then preference"
+	}
+
+	for _, bc := range ranked {
+		if bc.MaxTiers >= tiers {
+			plan.allocate(bc, tiers)
+			plan.Decision = fmt.Sprintf("fund %d tiers with %s, chosen %s", tiers, strings.ToUpper(bc.Symbol), basis)
+			explain(bc)
+			return plan
+		}
+	}
+
+	if !split {
+		// Fund as many tiers as possible with one asset.
+		best := ranked[0]
+		for _, bc := range ranked[1:] {
+			if bc.MaxTiers > best.MaxTiers {
+				best = bc
+			}
+		}
+		plan.allocate(best, best.MaxTiers)
+		plan.Shortfall = tiers - best.MaxTiers
+		plan.Decision = fmt.Sprintf("no asset can fund %d tiers and splitting is disabled. Fund %d tiers with %s",
+			tiers, best.MaxTiers, strings.ToUpper(best.Symbol))
+		explain(best)
+		return plan
+	}
+
+	remain := tiers
+	for _, bc := range ranked {
+		if remain == 0 {
+			break
+		}
+		n := min(bc.MaxTiers, remain)
+		plan.allocate(bc, n)
+		remain -= n
+	}
+	plan.Shortfall = remain
+	plan.Decision = fmt.Sprintf("no asset can fund %d tiers. Splitting across %d assets, chosen %s",
+		tiers, len(plan.Allocations), basis)
+	if remain > 0 {
+		plan.Decision += fmt.Sprintf(". %d tiers can't be funded", remain)
+	}
+	explain(ranked[0])
+	return plan
+}
+
+// bondPlanCandidates evaluates the bond assets for the planner, excluding any
+// that can't be used now with a Reason.
+func (c *Core) bondPlanCandidates(cfg *dexBondCfg, assetIDs []uint32) []*BondPlanCandidate {
+	rates := c.fiatConversions()
+	cands := make([]*BondPlanCandidate, 0, len(assetIDs))
+	for _, assetID := range assetIDs {
+		bc := &BondPlanCandidate{
+			AssetID:  assetID,
+			Symbol:   unbip(assetID),
+			FiatRate: rates[assetID],
+		}
+		cands = append(cands, bc)
+		bondAsset := cfg.bondAssets[assetID]
+		if bondAsset == nil || bondAsset.Amt == 0 {
+			bc.Reason = "not a bond asset of the server"
+			continue
+		}
+		bc.BondAmt = bondAsset.Amt
+		wallet, found := c.wallet(assetID)
+		if !found {
+			bc.Reason = "no wallet"
+			continue
+		}
+		if !wallet.connected() {
+			bc.Reason = "wallet not connected"
+			continue
+		}
+		bonder, ok := wallet.Wallet.(asset.Bonder)
+		if !ok {
+			bc.Reason = "wallet does not support bonds"
+			continue
+		}
+		if err := wallet.checkPeersAndSyncStatus(); err != nil {
+			bc.Reason = "wallet not synced or has no peers"
+			continue
+		}
+		if bc.FeeRate = c.bondFeeRate(assetID); bc.FeeRate == 0 {
+			bc.Reason = "no fee rate"
+			continue
+		}
+		bc.Fees = bonder.BondsFeeBuffer(bc.FeeRate)
+		wallet.mtx.RLock()
+		if wallet.balance != nil && wallet.balance.Balance != nil {
+			bc.Available = wallet.balance.Available + wallet.balance.BondReserves
+		}
+		wallet.mtx.RUnlock()
+		if bc.Available > bc.Fees {
+			bc.MaxTiers = (bc.Available - bc.Fees) / bc.BondAmt
+		}
+		if bc.FiatRate > 0 {
+			conv := float64(wallet.unitInfo().Conventional.ConversionFactor)
+			bc.FeesFiat = float64(bc.Fees) / conv * bc.FiatRate
+			bc.TierFiat = float64(bc.BondAmt) / conv * bc.FiatRate
+		}
+	}
+	return cands
+}
+
+// bondFeeRate is the fee rate for the planner's bond asset. The rate is cached
+// for bondFeeRateExpiry, since the planner runs on every bond check for every
+// listed asset.
+func (c *Core) bondFeeRate(assetID uint32) uint64 {
+	c.bondFeeRatesMtx.Lock()
+	defer c.bondFeeRatesMtx.Unlock()
+	if r := c.bondFeeRates[assetID]; r != nil && time.Since(r.stamp) < bondFeeRateExpiry {
+		return r.rate
+	}
+	rate := c.feeSuggestionAny(assetID)
+	if rate == 0 {
+		delete(c.bondFeeRates, assetID)
+		return 0
+	}
+	c.bondFeeRates[assetID] = &bondFeeRate{rate: rate, stamp: time.Now()}
+	return rate
+}
+
+// planAndPostBonds is the counterpart of postRequiredBonds for accounts with
+// a list of bond assets. The plan is stored with the account, and the required
+// bonds are posted according to the plan. If no bonds are needed, the plan is
+// a projection of the next renewal of the target tier.
+func (c *Core) planAndPostBonds(dc *dexConnection, cfg *dexBondCfg, state *dexAcctBondState, expiredStrength int64, unlocked bool) {
+	if state.TargetTier == 0 || cfg.bondExpiry <= 0 {
+		dc.acct.setBondPlan(nil)
+		return
+	}
+
+	var tiers uint64
+	if state.mustPost > 0 {
+		tiers = uint64(state.mustPost)
+		// The max bonded limit is in units of the primary bond asset, so
+		// convert it to tiers with that asset's bond amount.
+		if primary := cfg.bondAssets[state.BondAssetID]; state.MaxBondedAmt > 0 && primary != nil && primary.Amt > 0 {
+			bondedTiers := uint64(state.PendingStrength + state.LiveStrength + expiredStrength)
+			maxTiers := state.MaxBondedAmt / primary.Amt
+			if bondedTiers >= maxTiers {
+				tiers = 0
+			} else {
+				tiers = min(tiers, maxTiers-bondedTiers)
+			}
+			if tiers < uint64(state.mustPost) {
+				c.log.Warnf("Only planning %d of %d bond increments because of the bonding limit of %d tiers",
+					tiers, state.mustPost, maxTiers)
+			}
+		}
+	}
+
+	lockDur := minBondLifetime(c.net, cfg.bondExpiry)
+	projected := state.mustPost <= 0
+	var nextRenewal int64
+	if projected {
+		// Project the renewal of the target tier when the earliest bond needs
+		// to be replaced.
+		tiers = state.TargetTier
+		dc.acct.authMtx.RLock()
+		for _, bonds := range [][]*db.Bond{dc.acct.bonds, dc.acct.pendingBonds} {
+			for _, b := range bonds {
+				replaceTime := int64(b.LockTime) - cfg.bondExpiry - pendingBuffer(c.net)
+				if nextRenewal == 0 || replaceTime < nextRenewal {
+					nextRenewal = replaceTime
+				}
+			}
+		}
+		dc.acct.authMtx.RUnlock()
+	}
+
+	plan := planBonds(tiers, c.bondPlanCandidates(cfg, state.BondAssetIDs), state.SplitBonds)
+	plan.Projected = projected
+	if projected {
+		plan.Decision = "next renewal: " + plan.Decision
+		plan.NextRenewal = nextRenewal
+		if nextRenewal > 0 {
+			plan.RefundTime = nextRenewal + int64(lockDur/time.Second)
+		}
+		dc.acct.setBondPlan(plan)
+		return
+	}
+	plan.RefundTime = time.Now().Add(lockDur).Unix()
+	if tiers == 0 {
+		dc.acct.setBondPlan(plan)
+		return
+	}
+
+	c.log.Infof("Gotta post %d bond increments now. Target tier %d, current bonded tier %d (%d weak, %d pending), compensating %d penalties. Plan: %s",
+		tiers, state.TargetTier, state.Rep.BondedTier, state.WeakStrength, state.PendingStrength, state.toComp, plan.Decision)
+
+	if !unlocked || dc.status() != comms.Connected {
+		c.log.Warnf("Unable to post the required bonds while disconnected or account is locked.")
+		dc.acct.setBondPlan(plan)
+		return
+	}
+
+	lockTime, err := c.calculateMergingLockTime(dc)
+	if err != nil {
+		c.log.Errorf("Error calculating merging locktime: %v", err)
+		dc.acct.setBondPlan(plan)
+		return
+	}
+	plan.RefundTime = lockTime.Unix()
+	dc.acct.setBondPlan(plan)
+
+	for _, a := range plan.Allocations {
+		wallet, err := c.connectedWallet(a.AssetID)
+		if err != nil {
+			c.log.Errorf("%v wallet not available for bonds: %v", unbip(a.AssetID), err)
+			continue
+		}
+		if _, err := wallet.refreshUnlock(); err != nil {
+			c.log.Errorf("failed to unlock bond asset wallet %v: %v", unbip(a.AssetID), err)
+			continue
+		}
+		_, err = c.makeAndPostBond(dc, true, wallet, a.Amount, a.FeeRate, lockTime, cfg.bondAssets[a.AssetID])
+		if err != nil {
+			c.log.Errorf("Unable to post %s bond: %v", unbip(a.AssetID), err)
+		}
+	}
+}
+
+// setBondPlan sets the latest bond plan.
+func (a *dexAccount) setBondPlan(plan *BondPlan) {
+	a.authMtx.Lock()
+	a.bondPlan = plan
+	a.authMtx.Unlock()
+}
@@ -0,0 +1,251 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+func TestPlanBonds(t *testing.T) {
+	type tCand struct {
+		symbol   string
+		maxTiers uint64
+		feesFiat float64
+		fiatRate float64
+		excluded string
+	}
+	tests := []struct {
+		name        string
+		tiers       uint64
+		split       bool
+		cands       []tCand
+		allocations []string // symbol:tiers
+		shortfall   uint64
+		reasons     map[string]string
+	}{
+		{
+			name:        "lower fees",
+			tiers:       2,
+			cands:       []tCand{{"dcr", 5, 0.5, 20, ""}, {"btc", 5, 0.02, 6e4, ""}},
+			allocations: []string{"btc:2"},
+			reasons:     map[string]string{"dcr": "higher fees than BTC"},
+		},
+		{
+			name:        "similar fees keep preference",
+			tiers:       2,
+			cands:       []tCand{{"dcr", 5, 0.02, 20, ""}, {"btc", 5, 0.01, 6e4, ""}},
+			allocations: []string{"dcr:2"},
+			reasons:     map[string]string{"btc": "lower preference than DCR"},
+		},
+		{
+			name:        "no fiat rate keeps preference",
+			tiers:       2,
+			cands:       []tCand{{"dcr", 5, 0, 0, ""}, {"btc", 5, 0.01, 6e4, ""}},
+			allocations: []string{"dcr:2"},
+		},
+		{
+			name:        "one asset funds all tiers",
+			tiers:       2,
+			cands:       []tCand{{"dcr", 1, 0.01, 20, ""}, {"btc", 3, 0.5, 6e4, ""}},
+			allocations: []string{"btc:2"},
+			reasons:     map[string]string{"dcr": "can only fund 1 of 2 tiers"},
+		},
+		{
+			name:        "split",
+			tiers:       3,
+			split:       true,
+			cands:       []tCand{{"dcr", 1, 0.01, 20, ""}, {"btc", 2, 0.01, 6e4, ""}, {"ltc", 2, 0.01, 80, ""}},
+			allocations: []string{"dcr:1", "btc:2"},
+			reasons:     map[string]string{"ltc": "not needed"},
+		},
+		{
+			name:        "split with shortfall",
+			tiers:       4,
+			split:       true,
+			cands:       []tCand{{"dcr", 1, 0.01, 20, ""}, {"btc", 2, 0.01, 6e4, ""}},
+			allocations: []string{"dcr:1", "btc:2"},
+			shortfall:   1,
+		},
+		{
+			name:        "no split",
+			tiers:       3,
+			cands:       []tCand{{"dcr", 1, 0.01, 20, ""}, {"btc", 2, 0.01, 6e4, ""}},
+			allocations: []string{"btc:2"},
+			shortfall:   1,
+			reasons:     map[string]string{"dcr": "can only fund 1 of 3 tiers"},
+		},
+		{
+			name:      "no usable asset",
+			tiers:     1,
+			cands:     []tCand{{"dcr", 5, 0.01, 20, "wallet not connected"}, {"btc", 0, 0.01, 6e4, ""}},
+			shortfall: 1,
+			reasons: map[string]string{
+				"dcr": "wallet not connected",
+				"btc": "insufficient balance for a bond",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cands := make([]*BondPlanCandidate, 0, len(tt.cands))
+			for _, c := range tt.cands {
+				cands = append(cands, &BondPlanCandidate{
+					Symbol:   c.symbol,
+					BondAmt:  1e8,
+					MaxTiers: c.maxTiers,
+					FeesFiat: c.feesFiat,
+					FiatRate: c.fiatRate,
+					Reason:   c.excluded,
+				})
+			}
+			plan := planBonds(tt.tiers, cands, tt.split)
+			allocations := make([]string, 0, len(plan.Allocations))
+			for _, a := range plan.Allocations {
+				allocations = append(allocations, fmt.Sprintf("%s:%d", a.Symbol, a.Tiers))
+				if a.Amount != a.Tiers*1e8 {
+					t.Fatalf("wrong amount %d for %d tiers", a.Amount, a.Tiers)
+				}
+			}
+			if !slices.Equal(allocations, tt.allocations) {
+				t.Fatalf("wrong allocations %v, wanted %v. %s", allocations, tt.allocations, plan.Decision)
+			}
+			if plan.Shortfall != tt.shortfall {
+				t.Fatalf("wrong shortfall %d, wanted %d", plan.Shortfall, tt.shortfall)
+			}
+			for _, bc := range plan.Candidates {
+				if bc.Reason == "" {
+					t.Fatalf("no reason for %s", bc.Symbol)
+				}
+				if want, found := tt.reasons[bc.Symbol]; found && bc.Reason != want {
+					t.Fatalf("wrong reason for %s: %q, wanted %q", bc.Symbol, bc.Reason, want)
+				}
+			}
+			if plan.Decision == "" {
+				t.Fatalf("no decision")
+			}
+		})
+	}
+}
+
+func TestBondPlanCandidates(t *testing.T) {
+	const feeRate = 50
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	dcrWallet.Wallet = &TFeeRater{tDcrWallet, feeRate}
+	rig.core.wallets[tUTXOAssetA.ID] = dcrWallet
+	fees := tDcrWallet.BondsFeeBuffer(feeRate)
+	dcrWallet.setBalance(&WalletBalance{Balance: &db.Balance{
+		Balance: asset.Balance{Available: 2*dcrBondAsset.Amt + fees},
+	}})
+
+	cfg := rig.core.dexBondConfig(rig.dc, time.Now().Unix())
+	cands := rig.core.bondPlanCandidates(cfg, []uint32{tUTXOAssetB.ID, tUTXOAssetA.ID})
+	if len(cands) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(cands))
+	}
+	if btc := cands[0]; btc.Reason == "" {
+		t.Fatalf("unsupported bond asset not excluded")
+	}
+	dcr := cands[1]
+	if dcr.Reason != "" || dcr.FeeRate != feeRate || dcr.Fees != fees || dcr.MaxTiers != 2 {
+		t.Fatalf("wrong dcr candidate %+v", dcr)
+	}
+
+	plan := planBonds(2, cands, false)
+	if len(plan.Allocations) != 1 || plan.Allocations[0].AssetID != tUTXOAssetA.ID {
+		t.Fatalf("dcr not chosen: %s", plan.Decision)
+	}
+}
+
+func TestBondPlanFeeRateCache(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	feeRater := &TFeeRater{tDcrWallet, 50}
+	dcrWallet.Wallet = feeRater
+	rig.core.wallets[tUTXOAssetA.ID] = dcrWallet
+
+	if rate := rig.core.bondFeeRate(tUTXOAssetA.ID); rate != 50 {
+		t.Fatalf("wrong fee rate %d", rate)
+	}
+	feeRater.feeRate = 60
+	if rate := rig.core.bondFeeRate(tUTXOAssetA.ID); rate != 50 {
+		t.Fatalf("cached fee rate not used, got %d", rate)
+	}
+	rig.core.bondFeeRates[tUTXOAssetA.ID].stamp = time.Now().Add(-bondFeeRateExpiry)
+	if rate := rig.core.bondFeeRate(tUTXOAssetA.ID); rate != 60 {
+		t.Fatalf("expired fee rate not updated, got %d", rate)
+	}
+}
+
+func TestBondPlanReserves(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	rig.dc.cfgMtx.Lock()
+	rig.dc.cfg.BondAssets["btc"] = &msgjson.BondAsset{ID: tUTXOAssetB.ID, Amt: 1e6, Confs: 1}
+	rig.dc.cfgMtx.Unlock()
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	dcrWallet.Wallet = &TFeeRater{tDcrWallet, 50}
+	rig.core.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcWallet.Wallet = &TFeeRater{tBtcWallet, 10}
+	rig.core.wallets[tUTXOAssetB.ID] = btcWallet
+
+	rig.acct.authMtx.Lock()
+	rig.acct.targetTier = 1
+	rig.acct.bondAsset = tUTXOAssetA.ID
+	rig.acct.bondAssets = []uint32{tUTXOAssetB.ID, tUTXOAssetA.ID}
+	rig.acct.authMtx.Unlock()
+
+	check := func(name string, dcrTiers, btcTiers uint64) {
+		t.Helper()
+		rig.core.updateBondReserves()
+		for _, w := range []struct {
+			tw    *TXCWallet
+			id    uint32
+			amt   uint64
+			tiers uint64
+		}{
+			{tDcrWallet, tUTXOAssetA.ID, dcrBondAsset.Amt, dcrTiers},
+			{tBtcWallet, tUTXOAssetB.ID, 1e6, btcTiers},
+		} {
+			var exp uint64
+			if w.tiers > 0 {
+				exp = w.tiers*w.amt + w.tw.BondsFeeBuffer(rig.core.feeSuggestionAny(w.id))
+			}
+			if r := w.tw.reserves.Load(); r != exp {
+				t.Fatalf("%s: wrong %s reserves %d, expected %d", name, unbip(w.id), r, exp)
+			}
+		}
+	}
+
+	// Without a plan, funds are reserved in the primary bond asset. The
+	// missing tier is counted twice for the renewal.
+	check("no plan", 2, 0)
+
+	// Only the planned asset is reserved.
+	rig.acct.setBondPlan(&BondPlan{Tiers: 1, Allocations: []*BondPlanAllocation{
+		{AssetID: tUTXOAssetB.ID, Tiers: 1},
+	}})
+	check("one asset", 0, 2)
+
+	// A split plan splits the reserves.
+	rig.acct.authMtx.Lock()
+	rig.acct.targetTier = 2
+	rig.acct.authMtx.Unlock()
+	rig.acct.setBondPlan(&BondPlan{Tiers: 2, Split: true, Allocations: []*BondPlanAllocation{
+		{AssetID: tUTXOAssetB.ID, Tiers: 1},
+		{AssetID: tUTXOAssetA.ID, Tiers: 1},
+	}})
+	check("split", 2, 2)
+}
@@ -548,6 +548,9 @@ func (c *Core) SyncBook(host string, base, quote uint32) (*orderbook.OrderBook,
 // Book fetches the order book. If a subscription doesn't exist, one will be
 // attempted and immediately closed.
 func (c *Core) Book(dex string, base, quote uint32) (*OrderBook, error) {
+	if dex == MeshHost {
+		return c.meshBook(base, quote)
+	}
 	dex, err := addrHost(dex)
 	if err != nil {
 		return nil, newError(addressParseErr, "error parsing address: %w", err)
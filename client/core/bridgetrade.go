@@ -0,0 +1,978 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+	"decred.org/dcrdex/dex/order"
+)
+
+const (
+	// bridgeTradeInterval is how often active bridge trades are checked for
+	// progress.
+	bridgeTradeInterval = 30 * time.Second
+	// bridgeTradeMaxAttempts is the number of times a step is attempted
+	// before the bridge trade fails.
+	bridgeTradeMaxAttempts = 5
+	// defaultBridgeDuration is the estimated duration of a bridge that is
+	// not listed in bridgeDurations.
+	defaultBridgeDuration = 30 * time.Minute
+	// polygonWithdrawalDuration is the estimated duration of a Polygon PoS
+	// bridge withdrawal from Polygon, which must wait for a checkpoint.
+	polygonWithdrawalDuration = 3 * time.Hour
+	// tradeSettlementDuration is a rough estimate of the time for a match to
+	// settle, including the swap confirmations and redemptions of both
+	// parties.
+	tradeSettlementDuration = 30 * time.Minute
+	// polygonAssetID is the BIP ID of Polygon POL.
+	polygonAssetID = 966
+	// bridgeTradeRecoveryTxs is the number of recent bridges searched for a
+	// bridge whose initiation was interrupted.
+	bridgeTradeRecoveryTxs = 20
+	// bridgeTradeRecoveryOrders is the number of recent orders searched for
+	// an order whose placement was interrupted.
+	bridgeTradeRecoveryOrders = 20
+)
+
+// bridgeDurations are rough estimates of the time for a transfer with each
+// bridge. Bridgers do not report transfer times.
+var bridgeDurations = map[string]time.Duration{
+	"across":  5 * time.Minute,
+	"usdc":    20 * time.Minute,
+	"polygon": 30 * time.Minute,
+	"simnet":  time.Minute,
+}
+
+// BridgeTradeForm is the information necessary to start a bridge trade.
+type BridgeTradeForm struct {
+	// TradeFirst is true if the trade should be placed first, and its
+	// proceeds bridged. Otherwise the bridged funds are traded.
+	TradeFirst        bool   `json:"tradeFirst"`
+	BridgeFromAssetID uint32 `json:"bridgeFromAssetID"`
+	BridgeToAssetID   uint32 `json:"bridgeToAssetID"`
+	BridgeName        string `json:"bridgeName"`
+	// BridgeAmount is the amount to bridge for bridge-first flows. For
+	// trade-first flows, the trade proceeds are bridged.
+	BridgeAmount uint64 `json:"bridgeAmount"`
+	// Trade is the order. For bridge-first flows, a zero Qty is derived from
+	// the amount received from the bridge.
+	Trade TradeForm `json:"trade"`
+}
+
+// BridgeTradeEstimate is the estimated fees and duration of a bridge trade.
+type BridgeTradeEstimate struct {
+	// Qty is the order quantity. For bridge-first flows without a quantity,
+	// it is estimated from the bridge amount.
+	Qty uint64 `json:"qty"`
+	// BridgeFees and TradeFees are keyed by the asset the fees are paid in.
+	// Trade fees are the worst case of one match per lot.
+	BridgeFees map[uint32]uint64 `json:"bridgeFees"`
+	TradeFees  map[uint32]uint64 `json:"tradeFees"`
+	Fees       map[uint32]uint64 `json:"fees"`
+	// FeesFiat is the fiat value of the fees with known fiat rates.
+	// MissingFiatRates is true if any fee asset has no fiat rate.
+	FeesFiat         float64 `json:"feesFiat"`
+	MissingFiatRates bool    `json:"missingFiatRates,omitempty"`
+	BridgeMinLimit   uint64  `json:"bridgeMinLimit,omitempty"`
+	BridgeMaxLimit   uint64  `json:"bridgeMaxLimit,omitempty"`
+	BridgeSecs       uint64  `json:"bridgeSecs"`
+	TradeSecs        uint64  `json:"tradeSecs"`
+	TotalSecs        uint64  `json:"totalSecs"`
+	// OpenEnded is true if the order may wait on the book, in which case
+	// the flow may take much longer than estimated.
+	OpenEnded bool `json:"openEnded,omitempty"`
+}
+
+// BridgeTradeNote is a notification of a bridge trade's progress.
+type BridgeTradeNote struct {
+	db.Notification
+	BridgeTrade *db.BridgeTrade `json:"bridgeTrade"`
+}
+
+const (
+	TopicBridgeTradeUpdate   Topic = "BridgeTradeUpdate"
+	TopicBridgeTradeComplete Topic = "BridgeTradeComplete"
+	TopicBridgeTradeFailed   Topic = "BridgeTradeFailed"
+)
+
+func newBridgeTradeNote(topic Topic, subject, details string, severity db.Severity, bt *db.BridgeTrade) *BridgeTradeNote {
+	return &BridgeTradeNote{
+		Notification: db.NewNotification(NoteTypeBridgeTrade, topic, subject, details, severity),
+		BridgeTrade:  bt,
+	}
+}
+
+// bridgeTradeManager tracks the user's bridge trades.
+type bridgeTradeManager struct {
+	mtx    sync.Mutex
+	trades map[string]*db.BridgeTrade
+	// runMtx is held while a step is run, so that a bridge trade isn't
+	// canceled mid-step.
+	runMtx sync.Mutex
+}
+
+func newBridgeTradeManager(bts []*db.BridgeTrade) *bridgeTradeManager {
+	m := &bridgeTradeManager{
+		trades: make(map[string]*db.BridgeTrade, len(bts)),
+	}
+	for _, bt := range bts {
+		m.trades[bt.ID] = bt
+	}
+	return m
+}
+
+func (m *bridgeTradeManager) put(bt *db.BridgeTrade) {
+	m.mtx.Lock()
+	m.trades[bt.ID] = bt
+	m.mtx.Unlock()
+}
+
+// get returns a copy of the bridge trade.
+func (m *bridgeTradeManager) get(id string) (*db.BridgeTrade, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	bt, found := m.trades[id]
+	if !found {
+		return nil, false
+	}
+	return copyBridgeTrade(bt), true
+}
+
+// list returns copies of the bridge trades, oldest first. If activeOnly is
+// true, only active bridge trades are returned.
+func (m *bridgeTradeManager) list(activeOnly bool) []*db.BridgeTrade {
+	m.mtx.Lock()
+	bts := make([]*db.BridgeTrade, 0, len(m.trades))
+	for _, bt := range m.trades {
+		if activeOnly && bt.Status != db.BridgeTradeActive {
+			continue
+		}
+		bts = append(bts, copyBridgeTrade(bt))
+	}
+	m.mtx.Unlock()
+	sort.Slice(bts, func(i, j int) bool { return bts[i].Created < bts[j].Created })
+	return bts
+}
+
+func copyBridgeTrade(bt *db.BridgeTrade) *db.BridgeTrade {
+	btCopy := *bt
+	if bt.EstimatedFees != nil {
+		btCopy.EstimatedFees = make(map[uint32]uint64, len(bt.EstimatedFees))
+		for assetID, v := range bt.EstimatedFees {
+			btCopy.EstimatedFees[assetID] = v
+		}
+	}
+	return &btCopy
+}
+
+// bridgeTradeDescription describes the bridge trade for notifications.
+func bridgeTradeDescription(bt *db.BridgeTrade) string {
+	side := "buy"
+	if bt.Sell {
+		side = "sell"
+	}
+	bridge := fmt.Sprintf("bridge %s to %s", strings.ToUpper(unbip(bt.BridgeFromAssetID)), strings.ToUpper(unbip(bt.BridgeToAssetID)))
+	trade := fmt.Sprintf("%s on %s-%s @ %s", side, strings.ToUpper(unbip(bt.Base)), strings.ToUpper(unbip(bt.Quote)), bt.Host)
+	first, then := bridge, trade
+	if bt.TradeFirst {
+		first, then = trade, bridge
+	}
+	return strings.ToUpper(first[:1]) + first[1:] + ", then " + then
+}
+
+// bridgeTradeQty is the order quantity for the amount received from the
+// bridge. limit is the maximum number of lots that can be funded, or for
+// market buys, whose quantity is in units of the quote asset, the maximum
+// quantity.
+func bridgeTradeQty(amt, lotSize, rate uint64, sell, isLimit bool, limit uint64) uint64 {
+	if !sell && !isLimit {
+		if amt > limit {
+			return limit
+		}
+		return amt
+	}
+	baseQty := amt
+	if !sell {
+		baseQty = calc.QuoteToBase(rate, amt)
+	}
+	lots := baseQty / lotSize
+	if lots > limit {
+		lots = limit
+	}
+	return lots * lotSize
+}
+
+// orderProceeds is the amount of the order's to asset received from redeemed
+// matches, and whether the order is done, with no matches left to settle.
+func orderProceeds(ord *Order) (received uint64, done bool) {
+	switch ord.Status {
+	case order.OrderStatusExecuted, order.OrderStatusCanceled, order.OrderStatusRevoked:
+		done = true
+	}
+	for _, m := range ord.Matches {
+		if m.IsCancel {
+			continue
+		}
+		// Refunded matches may stay active until the refund confirms, but
+		// there are no proceeds to wait for.
+		if m.Active && m.Refund == nil {
+			done = false
+		}
+		if m.Redeem == nil || m.Refund != nil {
+			continue
+		}
+		if ord.Sell {
+			received += calc.BaseToQuote(m.Rate, m.Qty)
+		} else {
+			received += m.Qty
+		}
+	}
+	return
+}
+
+// bridgeDuration is the estimated duration of a bridge.
+func bridgeDuration(fromAssetID uint32, bridgeName string) time.Duration {
+	if bridgeName == "polygon" && feeAsset(fromAssetID) == polygonAssetID {
+		return polygonWithdrawalDuration
+	}
+	if d, found := bridgeDurations[bridgeName]; found {
+		return d
+	}
+	return defaultBridgeDuration
+}
+
+// bridgeTradeTo is the asset received from the trade.
+func bridgeTradeTo(form *TradeForm) uint32 {
+	if form.Sell {
+		return form.Quote
+	}
+	return form.Base
+}
+
+// bridgeTradeFrom is the asset spent in the trade.
+func bridgeTradeFrom(form *TradeForm) uint32 {
+	if form.Sell {
+		return form.Base
+	}
+	return form.Quote
+}
+
+// EstimateBridgeTrade validates the bridge trade form and estimates the fees
+// and duration of the bridge trade. The bridge contract must already be
+// approved for the bridged asset.
+func (c *Core) EstimateBridgeTrade(form *BridgeTradeForm) (*BridgeTradeEstimate, error) {
+	tf := &form.Trade
+	if tf.IsLimit && tf.Rate == 0 {
+		return nil, errors.New("limit order rate must be non-zero")
+	}
+	if form.TradeFirst {
+		if bridgeTradeTo(tf) != form.BridgeFromAssetID {
+			return nil, fmt.Errorf("the trade does not receive %s, the bridged asset", unbip(form.BridgeFromAssetID))
+		}
+		if tf.Qty == 0 {
+			return nil, errors.New("order quantity must be non-zero")
+		}
+	} else {
+		if bridgeTradeFrom(tf) != form.BridgeToAssetID {
+			return nil, fmt.Errorf("the trade does not spend %s, the bridge destination", unbip(form.BridgeToAssetID))
+		}
+		if form.BridgeAmount == 0 {
+			return nil, errors.New("bridge amount must be non-zero")
+		}
+	}
+
+	dests, err := c.SupportedBridgeDestinations(form.BridgeFromAssetID)
+	if err != nil {
+		return nil, err
+	}
+	var supported bool
+	for _, name := range dests[form.BridgeToAssetID] {
+		supported = supported || name == form.BridgeName
+	}
+	if !supported {
+		return nil, fmt.Errorf("no %q bridge from %s to %s", form.BridgeName, unbip(form.BridgeFromAssetID), unbip(form.BridgeToAssetID))
+	}
+	approval, err := c.BridgeContractApprovalStatus(form.BridgeFromAssetID, form.BridgeName)
+	if err != nil {
+		return nil, fmt.Errorf("error checking the %q bridge contract approval for %s: %w", form.BridgeName, unbip(form.BridgeFromAssetID), err)
+	}
+	switch approval {
+	case asset.Approved:
+	case asset.Pending:
+		return nil, fmt.Errorf("the %q bridge contract approval for %s is still pending", form.BridgeName, unbip(form.BridgeFromAssetID))
+	default:
+		return nil, fmt.Errorf("the %q bridge contract must be approved for %s before bridging", form.BridgeName, unbip(form.BridgeFromAssetID))
+	}
+
+	dc, err := c.registeredDEX(tf.Host)
+	if err != nil {
+		return nil, err
+	}
+	mktID := marketName(tf.Base, tf.Quote)
+	mktConf := dc.marketConfig(mktID)
+	if mktConf == nil {
+		return nil, newError(marketErr, "unknown market %q", mktID)
+	}
+
+	bridgeFees, err := c.BridgeFeesAndLimits(form.BridgeFromAssetID, form.BridgeToAssetID, form.BridgeName)
+	if err != nil {
+		return nil, fmt.Errorf("error estimating bridge fees: %w", err)
+	}
+	if !form.TradeFirst && bridgeFees.HasLimits && (form.BridgeAmount < bridgeFees.MinLimit || form.BridgeAmount > bridgeFees.MaxLimit) {
+		return nil, fmt.Errorf("bridge amount %d is outside of the bridge limits [%d, %d]", form.BridgeAmount, bridgeFees.MinLimit, bridgeFees.MaxLimit)
+	}
+
+	est := &BridgeTradeEstimate{
+		Qty:        tf.Qty,
+		BridgeFees: bridgeFees.Fees,
+		TradeFees:  make(map[uint32]uint64, 2),
+		Fees:       make(map[uint32]uint64, 3),
+		BridgeSecs: uint64(bridgeDuration(form.BridgeFromAssetID, form.BridgeName).Seconds()),
+		OpenEnded:  tf.IsLimit && !tf.TifNow,
+	}
+	if bridgeFees.HasLimits {
+		est.BridgeMinLimit, est.BridgeMaxLimit = bridgeFees.MinLimit, bridgeFees.MaxLimit
+	}
+	if est.Qty == 0 {
+		// Balances are not checked, since the funds aren't there yet.
+		est.Qty = bridgeTradeQty(form.BridgeAmount, mktConf.LotSize, tf.Rate, tf.Sell, tf.IsLimit, math.MaxUint64)
+		if est.Qty == 0 {
+			return nil, fmt.Errorf("bridge amount %d is less than one lot", form.BridgeAmount)
+		}
+	}
+
+	swapFees, redeemFees, _, err := c.SingleLotFees(&SingleLotFeesForm{
+		Host:  tf.Host,
+		Base:  tf.Base,
+		Quote: tf.Quote,
+		Sell:  tf.Sell,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error estimating trade fees: %w", err)
+	}
+	lots := est.Qty / mktConf.LotSize
+	if !tf.Sell && !tf.IsLimit {
+		// Market buy quantities are in units of the quote asset. Count at
+		// least one lot.
+		lots = 1
+		if book := dc.bookie(mktID); book != nil {
+			if midGap, err := book.MidGap(); err == nil && midGap > 0 {
+				lots = max(calc.QuoteToBase(midGap, est.Qty)/mktConf.LotSize, 1)
+			}
+		}
+	}
+	est.TradeFees[feeAsset(bridgeTradeFrom(tf))] += lots * swapFees
+	est.TradeFees[feeAsset(bridgeTradeTo(tf))] += lots * redeemFees
+
+	rates := c.fiatConversions()
+	for _, fees := range []map[uint32]uint64{est.BridgeFees, est.TradeFees} {
+		for assetID, v := range fees {
+			est.Fees[assetID] += v
+		}
+	}
+	for assetID, v := range est.Fees {
+		ui, err := asset.UnitInfo(assetID)
+		if err != nil || rates[assetID] == 0 {
+			est.MissingFiatRates = true
+			continue
+		}
+		est.FeesFiat += float64(v) / float64(ui.Conventional.ConversionFactor) * rates[assetID]
+	}
+
+	// Orders are matched at the end of the epoch they are placed in.
+	est.TradeSecs = 2*mktConf.EpochLen/1000 + uint64(tradeSettlementDuration.Seconds())
+	est.TotalSecs = est.BridgeSecs + est.TradeSecs
+	return est, nil
+}
+
+// StartBridgeTrade starts a bridge trade, which bridges funds and trades them,
+// or trades and bridges the proceeds. The steps are run in the background,
+// and resume after a restart. Progress is reported with BridgeTradeNotes.
+func (c *Core) StartBridgeTrade(pw []byte, form *BridgeTradeForm) (*db.BridgeTrade, error) {
+	crypter, err := c.encryptionKey(pw)
+	if err != nil {
+		return nil, codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+
+	est, err := c.EstimateBridgeTrade(form)
+	if err != nil {
+		return nil, err
+	}
+	for _, assetID := range bridgeTradeAssets(form.BridgeFromAssetID, form.BridgeToAssetID, form.Trade.Base, form.Trade.Quote) {
+		w, found := c.wallet(assetID)
+		if !found {
+			return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+		}
+		if err := c.connectAndUnlock(crypter, w); err != nil {
+			return nil, fmt.Errorf("error unlocking %s wallet: %w", unbip(assetID), err)
+		}
+	}
+	host, err := addrHost(form.Trade.Host)
+	if err != nil {
+		return nil, newError(addressParseErr, "error parsing address: %w", err)
+	}
+	now := uint64(time.Now().UnixMilli())
+	tf := &form.Trade
+	bt := &db.BridgeTrade{
+		ID:                hex.EncodeToString(encode.RandomBytes(8)),
+		TradeFirst:        form.TradeFirst,
+		BridgeFromAssetID: form.BridgeFromAssetID,
+		BridgeToAssetID:   form.BridgeToAssetID,
+		BridgeName:        form.BridgeName,
+		Host:              host,
+		Base:              tf.Base,
+		Quote:             tf.Quote,
+		Sell:              tf.Sell,
+		IsLimit:           tf.IsLimit,
+		Rate:              tf.Rate,
+		TifNow:            tf.TifNow,
+		Options:           tf.Options,
+		Qty:               tf.Qty,
+		Step:              db.BridgeTradeStepBridge,
+		Status:            db.BridgeTradeActive,
+		EstimatedFees:     est.Fees,
+		EstimatedSecs:     est.TotalSecs,
+		Created:           now,
+		Updated:           now,
+	}
+	if form.TradeFirst {
+		bt.Step = db.BridgeTradeStepTrade
+	} else {
+		bt.BridgeAmount = form.BridgeAmount
+	}
+	if err := c.db.UpdateBridgeTrade(bt); err != nil {
+		return nil, fmt.Errorf("error storing bridge trade: %w", err)
+	}
+	c.bridgeTrades.put(bt)
+	c.notify(newBridgeTradeNote(TopicBridgeTradeUpdate, "", "", db.Data, copyBridgeTrade(bt)))
+	c.signalBridgeTradesUpdated()
+	return copyBridgeTrade(bt), nil
+}
+
+// BridgeTrades returns the user's bridge trades, oldest first.
+func (c *Core) BridgeTrades() []*db.BridgeTrade {
+	return c.bridgeTrades.list(false)
+}
+
+// CancelBridgeTrade stops an active bridge trade. Steps that are in progress
+// are not reversed. A booked order is not canceled, and a bridge that was
+// initiated completes.
+func (c *Core) CancelBridgeTrade(id string) error {
+	m := c.bridgeTrades
+	m.runMtx.Lock()
+	defer m.runMtx.Unlock()
+	bt, found := m.get(id)
+	if !found {
+		return fmt.Errorf("bridge trade %q not found", id)
+	}
+	if bt.Status != db.BridgeTradeActive {
+		return fmt.Errorf("bridge trade %s is %s", id, bt.Status)
+	}
+	bt.Status = db.BridgeTradeCanceled
+	bt.Updated = uint64(time.Now().UnixMilli())
+	if err := c.db.UpdateBridgeTrade(bt); err != nil {
+		return fmt.Errorf("error storing bridge trade: %w", err)
+	}
+	m.put(bt)
+	c.notify(newBridgeTradeNote(TopicBridgeTradeUpdate, "", "", db.Data, copyBridgeTrade(bt)))
+	return nil
+}
+
+// bridgeTradeAssets lists the assets of a bridge trade's wallets, without
+// duplicates.
+func bridgeTradeAssets(bridgeFrom, bridgeTo, base, quote uint32) []uint32 {
+	assetIDs := make([]uint32, 0, 4)
+	for _, assetID := range []uint32{bridgeFrom, bridgeTo, base, quote} {
+		if !slices.Contains(assetIDs, assetID) {
+			assetIDs = append(assetIDs, assetID)
+		}
+	}
+	return assetIDs
+}
+
+// resumeBridgeTrades unlocks the wallets needed by the active bridge trades
+// on login, and signals the runner.
+func (c *Core) resumeBridgeTrades(crypter encrypt.Crypter) {
+	unlocked := make(map[uint32]bool)
+	for _, bt := range c.bridgeTrades.list(true) {
+		for _, assetID := range bridgeTradeAssets(bt.BridgeFromAssetID, bt.BridgeToAssetID, bt.Base, bt.Quote) {
+			if unlocked[assetID] {
+				continue
+			}
+			unlocked[assetID] = true
+			w, found := c.wallet(assetID)
+			if !found {
+				c.log.Errorf("No %s wallet for bridge trade %s", unbip(assetID), bt.ID)
+				continue
+			}
+			if err := c.connectAndUnlock(crypter, w); err != nil {
+				c.log.Errorf("Error unlocking %s wallet for bridge trades: %v", unbip(assetID), err)
+			}
+		}
+	}
+	c.signalBridgeTradesUpdated()
+}
+
+func (c *Core) signalBridgeTradesUpdated() {
+	select {
+	case c.bridgeTradesUpdated <- struct{}{}:
+	default:
+	}
+}
+
+// runBridgeTrades advances the active bridge trades periodically, and when
+// signaled, e.g. when a bridge completes.
+func (c *Core) runBridgeTrades(ctx context.Context) {
+	ticker := time.NewTicker(bridgeTradeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.bridgeTradesUpdated:
+		case <-ctx.Done():
+			return
+		}
+		c.advanceBridgeTrades()
+	}
+}
+
+// advanceBridgeTrades advances the active bridge trades. Steps are only run
+// while logged in, since wallets must be unlocked.
+func (c *Core) advanceBridgeTrades() {
+	c.loginMtx.Lock()
+	loggedIn := c.loggedIn
+	c.loginMtx.Unlock()
+	if !loggedIn {
+		return
+	}
+	m := c.bridgeTrades
+	m.runMtx.Lock()
+	defer m.runMtx.Unlock()
+	for _, bt := range m.list(true) {
+		c.advanceBridgeTrade(bt)
+	}
+}
+
+// advanceBridgeTrade runs or checks the bridge trade's current step, storing
+// the bridge trade and sending a notification if it was updated.
+func (c *Core) advanceBridgeTrade(bt *db.BridgeTrade) {
+	var updated bool
+	var err error
+	switch bt.Step {
+	case db.BridgeTradeStepBridge:
+		updated, err = c.advanceBridgeStep(bt)
+	case db.BridgeTradeStepTrade:
+		updated, err = c.advanceTradeStep(bt)
+	default:
+		c.log.Errorf("Active bridge trade %s has no remaining steps", bt.ID)
+		return
+	}
+	if err != nil {
+		c.log.Errorf("Bridge trade %s %s step error: %v", bt.ID, bt.Step, err)
+		bt.Attempts++
+		bt.Error = err.Error()
+		if bt.Attempts >= bridgeTradeMaxAttempts {
+			bt.Status = db.BridgeTradeFailed
+		}
+		updated = true
+	}
+	if !updated {
+		return
+	}
+	bt.Updated = uint64(time.Now().UnixMilli())
+	if err := c.db.UpdateBridgeTrade(bt); err != nil {
+		// A bridge or order may have just been sent, and a restart would not
+		// know about it. Stop the bridge trade so that it can be reviewed,
+		// rather than risk repeating the step.
+		c.log.Errorf("Error storing bridge trade %s: %v", bt.ID, err)
+		bt.Status, bt.Error = db.BridgeTradeFailed, fmt.Sprintf("error storing bridge trade: %v", err)
+	}
+	c.bridgeTrades.put(bt)
+	bt = copyBridgeTrade(bt)
+	switch bt.Status {
+	case db.BridgeTradeComplete:
+		subject, details := c.formatDetails(TopicBridgeTradeComplete, bridgeTradeDescription(bt))
+		c.notify(newBridgeTradeNote(TopicBridgeTradeComplete, subject, details, db.Success, bt))
+	case db.BridgeTradeFailed:
+		subject, details := c.formatDetails(TopicBridgeTradeFailed, bridgeTradeDescription(bt), bt.Error)
+		c.notify(newBridgeTradeNote(TopicBridgeTradeFailed, subject, details, db.ErrorLevel, bt))
+	default:
+		c.notify(newBridgeTradeNote(TopicBridgeTradeUpdate, "", "", db.Data, bt))
+	}
+}
+
+// completeStep moves the bridge trade to the step after the current one.
+func completeStep(bt *db.BridgeTrade) {
+	bt.Attempts, bt.Error = 0, ""
+	switch {
+	case bt.Step == db.BridgeTradeStepBridge && !bt.TradeFirst:
+		bt.Step = db.BridgeTradeStepTrade
+	case bt.Step == db.BridgeTradeStepTrade && bt.TradeFirst:
+		bt.Step = db.BridgeTradeStepBridge
+	default:
+		bt.Step = db.BridgeTradeStepDone
+		bt.Status = db.BridgeTradeComplete
+	}
+}
+
+// storeStepStart stores the bridge trade before a step that sends funds, with
+// the step's start time set by the caller.
+func (c *Core) storeStepStart(bt *db.BridgeTrade) error {
+	bt.Updated = uint64(time.Now().UnixMilli())
+	if err := c.db.UpdateBridgeTrade(bt); err != nil {
+		return fmt.Errorf("error storing bridge trade: %w", err)
+	}
+	return nil
+}
+
+// advanceBridgeStep initiates the bridge, or checks whether the initiated
+// bridge has completed.
+func (c *Core) advanceBridgeStep(bt *db.BridgeTrade) (updated bool, err error) {
+	if bt.BridgeTxID == "" && bt.BridgeInitiating > 0 {
+		return c.recoverBridgeTx(bt)
+	}
+	if bt.BridgeTxID == "" {
+		amt := bt.BridgeAmount
+		if bt.TradeFirst {
+			if amt, err = c.tradeProceedsBridgeAmount(bt); err != nil {
+				return false, err
+			}
+		}
+		bt.BridgeAmount, bt.BridgeInitiating = amt, uint64(time.Now().UnixMilli())
+		if err := c.storeStepStart(bt); err != nil {
+			bt.BridgeInitiating = 0
+			return false, err
+		}
+		txID, err := c.Bridge(bt.BridgeFromAssetID, bt.BridgeToAssetID, amt, bt.BridgeName)
+		if err != nil {
+			bt.BridgeInitiating = 0
+			return false, fmt.Errorf("error initiating bridge: %w", err)
+		}
+		bt.BridgeTxID = txID
+		bt.Attempts, bt.Error = 0, ""
+		return true, nil
+	}
+
+	// The source wallet records the completion of the bridge, including
+	// bridges that completed while we were offline.
+	w, err := c.connectedWallet(bt.BridgeFromAssetID)
+	if err != nil {
+		c.log.Debugf("Unable to check bridge %s for bridge trade %s: %v", bt.BridgeTxID, bt.ID, err)
+		return false, nil
+	}
+	tx, err := w.WalletTransaction(c.ctx, bt.BridgeTxID)
+	if err != nil || tx == nil {
+		c.log.Debugf("Unable to find bridge %s for bridge trade %s: %v", bt.BridgeTxID, bt.ID, err)
+		return false, nil
+	}
+	if tx.Rejected {
+		bt.Status, bt.Error = db.BridgeTradeFailed, "bridge transaction rejected"
+		return true, nil
+	}
+	if tx.BridgeCounterpartTx == nil || !tx.BridgeCounterpartTx.Complete {
+		return false, nil
+	}
+	received := tx.BridgeCounterpartTx.AmountReceived
+	if received == 0 {
+		// The source wallet didn't record the amount, so check the completion
+		// transactions in the destination wallet.
+		var found bool
+		if received, found = c.bridgeCompletionAmount(bt, tx.BridgeCounterpartTx); !found {
+			return false, nil
+		}
+	}
+	if received == 0 && !bt.TradeFirst {
+		// The bridge fees are unknown, so the amount to trade is too.
+		bt.Status, bt.Error = db.BridgeTradeFailed, "unable to determine the amount received from the bridge"
+		return true, nil
+	}
+	bt.BridgeReceived = received
+	completeStep(bt)
+	return true, nil
+}
+
+// bridgeCompletionAmount is the amount received in the destination wallet's
+// completion transactions for a bridge. found is false if the destination
+// wallet or any of its transactions can't be checked yet.
+func (c *Core) bridgeCompletionAmount(bt *db.BridgeTrade, counterpart *asset.BridgeCounterpartTx) (received uint64, found bool) {
+	w, err := c.connectedWallet(bt.BridgeToAssetID)
+	if err != nil {
+		c.log.Debugf("Unable to check bridge completion for bridge trade %s: %v", bt.ID, err)
+		return 0, false
+	}
+	for _, txID := range counterpart.IDs {
+		tx, err := w.WalletTransaction(c.ctx, txID)
+		if err != nil || tx == nil {
+			c.log.Debugf("Unable to find bridge completion %s for bridge trade %s: %v", txID, bt.ID, err)
+			return 0, false
+		}
+		if tx.Type == asset.CompleteBridge {
+			received += tx.Amount
+		}
+	}
+	return received, true
+}
+
+// recoverBridgeTx looks for the bridge of a bridge trade whose initiation was
+// interrupted before the transaction ID was stored. The bridge must match the
+// destination and amount, and not be claimed by another bridge trade. If it
+// is not found, the bridge trade fails so that the user can check the wallet,
+// since initiating the bridge again could send the funds twice.
+func (c *Core) recoverBridgeTx(bt *db.BridgeTrade) (updated bool, err error) {
+	w, err := c.connectedWallet(bt.BridgeFromAssetID)
+	if err != nil {
+		c.log.Debugf("Unable to look up the bridge for bridge trade %s: %v", bt.ID, err)
+		return false, nil
+	}
+	pending, err := w.PendingBridges()
+	if err != nil {
+		return false, fmt.Errorf("error retrieving pending bridges: %w", err)
+	}
+	history, err := w.BridgeHistory(bridgeTradeRecoveryTxs, nil, false)
+	if err != nil {
+		return false, fmt.Errorf("error retrieving bridge history: %w", err)
+	}
+	claimed := make(map[string]bool)
+	for _, other := range c.bridgeTrades.list(false) {
+		if other.ID != bt.ID && other.BridgeTxID != "" {
+			claimed[other.BridgeTxID] = true
+		}
+	}
+	var found []string
+	for _, tx := range append(pending, history...) {
+		if tx.BridgeCounterpartTx == nil || tx.BridgeCounterpartTx.AssetID != bt.BridgeToAssetID ||
+			tx.Amount != bt.BridgeAmount || claimed[tx.ID] || slices.Contains(found, tx.ID) {
+			continue
+		}
+		// Timestamp is the mined time in seconds, or zero if unmined.
+		if tx.Timestamp > 0 && tx.Timestamp < bt.BridgeInitiating/1000 {
+			continue
+		}
+		found = append(found, tx.ID)
+	}
+	switch len(found) {
+	case 1:
+		c.log.Infof("Found bridge %s for bridge trade %s", found[0], bt.ID)
+		bt.BridgeTxID = found[0]
+		bt.Attempts, bt.Error = 0, ""
+	case 0:
+		bt.Status, bt.Error = db.BridgeTradeFailed, "the bridge initiation was interrupted, and no bridge was found. "+
+			"Check the wallet's bridge history before retrying."
+	default:
+		bt.Status, bt.Error = db.BridgeTradeFailed, fmt.Sprintf("the bridge initiation was interrupted, "+
+			"and more than one matching bridge was found: %s", strings.Join(found, ", "))
+	}
+	return true, nil
+}
+
+// tradeProceedsBridgeAmount is the amount of the trade proceeds to bridge.
+// Bridge fees are deducted if they are paid in the bridged asset.
+func (c *Core) tradeProceedsBridgeAmount(bt *db.BridgeTrade) (uint64, error) {
+	amt := bt.TradeReceived
+	w, found := c.wallet(bt.BridgeFromAssetID)
+	if !found {
+		return 0, newError(missingWalletErr, "no wallet found for %s", unbip(bt.BridgeFromAssetID))
+	}
+	w.mtx.RLock()
+	if w.balance != nil && w.balance.Balance != nil && w.balance.Available < amt {
+		amt = w.balance.Available
+	}
+	w.mtx.RUnlock()
+	fees, err := c.BridgeFeesAndLimits(bt.BridgeFromAssetID, bt.BridgeToAssetID, bt.BridgeName)
+	if err != nil {
+		return 0, fmt.Errorf("error estimating bridge fees: %w", err)
+	}
+	if fee := fees.Fees[bt.BridgeFromAssetID]; fee > 0 {
+		if fee >= amt {
+			return 0, fmt.Errorf("bridge fees %d exceed the trade proceeds %d", fee, amt)
+		}
+		amt -= fee
+	}
+	if fees.HasLimits && (amt < fees.MinLimit || amt > fees.MaxLimit) {
+		return 0, fmt.Errorf("bridge amount %d is outside of the bridge limits [%d, %d]", amt, fees.MinLimit, fees.MaxLimit)
+	}
+	return amt, nil
+}
+
+// advanceTradeStep places the order, or checks whether the placed order is
+// done.
+func (c *Core) advanceTradeStep(bt *db.BridgeTrade) (updated bool, err error) {
+	if bt.OrderID == "" && bt.OrderPlacing > 0 {
+		return c.recoverBridgeTradeOrder(bt)
+	}
+	if bt.OrderID == "" {
+		form, err := c.bridgeTradeOrderForm(bt)
+		if err != nil {
+			return false, err
+		}
+		// The quantity is stored with the start time, so that the order can
+		// be recognized if placing it is interrupted.
+		qty := bt.Qty
+		bt.Qty, bt.OrderPlacing = form.Qty, uint64(time.Now().UnixMilli())
+		if err := c.storeStepStart(bt); err != nil {
+			bt.Qty, bt.OrderPlacing = qty, 0
+			return false, err
+		}
+		// Wallets are unlocked while logged in, so no password is needed.
+		ord, err := c.Trade(nil, form)
+		if err != nil {
+			bt.Qty, bt.OrderPlacing = qty, 0
+			return false, fmt.Errorf("error placing order: %w", err)
+		}
+		bt.OrderID = ord.ID.String()
+		bt.Attempts, bt.Error = 0, ""
+		return true, nil
+	}
+
+	oid, err := hex.DecodeString(bt.OrderID)
+	if err != nil {
+		bt.Status, bt.Error = db.BridgeTradeFailed, fmt.Sprintf("invalid order ID %s", bt.OrderID)
+		return true, nil
+	}
+	ord, err := c.Order(oid)
+	if err != nil {
+		c.log.Debugf("Unable to find order %s for bridge trade %s: %v", bt.OrderID, bt.ID, err)
+		return false, nil
+	}
+	received, done := orderProceeds(ord)
+	if received != bt.TradeReceived {
+		bt.TradeReceived, updated = received, true
+	}
+	if !done {
+		return updated, nil
+	}
+	if received == 0 {
+		bt.Status, bt.Error = db.BridgeTradeFailed, fmt.Sprintf("order %s completed with no proceeds", bt.OrderID)
+		return true, nil
+	}
+	completeStep(bt)
+	return true, nil
+}
+
+// recoverBridgeTradeOrder looks for the order of a bridge trade whose
+// placement was interrupted before the order ID was stored. The order must
+// match the bridge trade's market, side, type, rate and quantity, be placed
+// after the placement started, and not be claimed by another bridge trade. If
+// it is not found, the bridge trade fails so that the user can check their
+// orders, since placing the order again could trade the funds twice.
+func (c *Core) recoverBridgeTradeOrder(bt *db.BridgeTrade) (updated bool, err error) {
+	ords, err := c.db.Orders(&db.OrderFilter{
+		N:      bridgeTradeRecoveryOrders,
+		Hosts:  []string{bt.Host},
+		Market: &db.OrderFilterMarket{Base: bt.Base, Quote: bt.Quote},
+	})
+	if err != nil {
+		return false, fmt.Errorf("error retrieving orders: %w", err)
+	}
+	claimed := make(map[string]bool)
+	for _, other := range c.bridgeTrades.list(false) {
+		if other.ID != bt.ID && other.OrderID != "" {
+			claimed[other.OrderID] = true
+		}
+	}
+	var found []string
+	for _, mOrd := range ords {
+		ord := mOrd.Order
+		if ord.Type() == order.CancelOrderType {
+			continue
+		}
+		trade := ord.Trade()
+		oid := ord.ID().String()
+		if claimed[oid] || trade.Sell != bt.Sell || trade.Quantity != bt.Qty ||
+			uint64(ord.Prefix().ClientTime.UnixMilli()) < bt.OrderPlacing {
+			continue
+		}
+		if lo, isLimit := ord.(*order.LimitOrder); isLimit != bt.IsLimit || (isLimit && lo.Rate != bt.Rate) {
+			continue
+		}
+		found = append(found, oid)
+	}
+	switch len(found) {
+	case 1:
+		c.log.Infof("Found order %s for bridge trade %s", found[0], bt.ID)
+		bt.OrderID = found[0]
+		bt.Attempts, bt.Error = 0, ""
+	case 0:
+		bt.Status, bt.Error = db.BridgeTradeFailed, "placing the order was interrupted, and no order was found. "+
+			"Check the orders before retrying."
+	default:
+		bt.Status, bt.Error = db.BridgeTradeFailed, fmt.Sprintf("placing the order was interrupted, "+
+			"and more than one matching order was found: %s", strings.Join(found, ", "))
+	}
+	return true, nil
+}
+
+// bridgeTradeOrderForm is the TradeForm for the bridge trade's order. For
+// bridge-first flows without a quantity, the quantity is derived from the
+// amount received from the bridge and the funds available.
+func (c *Core) bridgeTradeOrderForm(bt *db.BridgeTrade) (*TradeForm, error) {
+	form := &TradeForm{
+		Host:    bt.Host,
+		IsLimit: bt.IsLimit,
+		Sell:    bt.Sell,
+		Base:    bt.Base,
+		Quote:   bt.Quote,
+		Qty:     bt.Qty,
+		Rate:    bt.Rate,
+		TifNow:  bt.TifNow,
+		Options: bt.Options,
+	}
+	if form.Qty > 0 {
+		return form, nil
+	}
+	dc, err := c.registeredDEX(bt.Host)
+	if err != nil {
+		return nil, err
+	}
+	mktID := marketName(bt.Base, bt.Quote)
+	mktConf := dc.marketConfig(mktID)
+	if mktConf == nil {
+		return nil, newError(marketErr, "unknown market %q", mktID)
+	}
+	var limit uint64
+	switch {
+	case bt.Sell:
+		est, err := c.MaxSell(bt.Host, bt.Base, bt.Quote)
+		if err != nil {
+			return nil, err
+		}
+		limit = est.Swap.Lots
+	case bt.IsLimit:
+		est, err := c.MaxBuy(bt.Host, bt.Base, bt.Quote, bt.Rate)
+		if err != nil {
+			return nil, err
+		}
+		limit = est.Swap.Lots
+	default:
+		w, found := c.wallet(bt.Quote)
+		if !found {
+			return nil, newError(missingWalletErr, "no wallet found for %s", unbip(bt.Quote))
+		}
+		w.mtx.RLock()
+		if w.balance != nil && w.balance.Balance != nil {
+			limit = w.balance.Available
+		}
+		w.mtx.RUnlock()
+	}
+	if form.Qty = bridgeTradeQty(bt.BridgeReceived, mktConf.LotSize, bt.Rate, bt.Sell, bt.IsLimit, limit); form.Qty == 0 {
+		return nil, fmt.Errorf("insufficient funds to trade the %d received from the bridge", bt.BridgeReceived)
+	}
+	return form, nil
+}
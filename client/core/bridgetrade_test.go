@@ -0,0 +1,509 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+type tBridger struct {
+	*TXCWallet
+	dests         map[uint32][]string
+	initFee       uint64
+	completionFee uint64
+	initiated     uint64
+	bridgeTx      *asset.WalletTransaction
+	pending       []*asset.WalletTransaction
+	approval      asset.ApprovalStatus
+}
+
+var _ asset.Bridger = (*tBridger)(nil)
+
+func (w *tBridger) ApproveBridgeContract(ctx context.Context, bridgeName string, onConfirm func()) (string, error) {
+	return "", nil
+}
+func (w *tBridger) UnapproveBridgeContract(ctx context.Context, bridgeName string, onConfirm func()) (string, error) {
+	return "", nil
+}
+func (w *tBridger) BridgeContractApprovalStatus(ctx context.Context, bridgeName string) (asset.ApprovalStatus, error) {
+	return w.approval, nil
+}
+func (w *tBridger) InitiateBridge(ctx context.Context, amt uint64, dest uint32, bridgeName string) (string, error) {
+	w.initiated = amt
+	return "bridgetx", nil
+}
+func (w *tBridger) CompleteBridge(ctx context.Context, bridgeTx *asset.BridgeCounterpartTx, amount uint64, mintData []byte, bridgeName string) error {
+	return nil
+}
+func (w *tBridger) MarkBridgeComplete(initiationTxID string, completionTxIDs []string, amtReceived, fees uint64, complete bool) {
+}
+func (w *tBridger) PendingBridges() ([]*asset.WalletTransaction, error) {
+	return w.pending, nil
+}
+func (w *tBridger) BridgeHistory(n int, refID *string, past bool) ([]*asset.WalletTransaction, error) {
+	return nil, nil
+}
+func (w *tBridger) SupportedDestinations() map[uint32][]string {
+	return w.dests
+}
+func (w *tBridger) BridgeInitiationFeesAndLimits(bridgeName string, destAssetID uint32) (uint64, [2]uint64, bool, error) {
+	return w.initFee, [2]uint64{}, false, nil
+}
+func (w *tBridger) BridgeCompletionFees(bridgeName string) (uint64, bool, error) {
+	return w.completionFee, true, nil
+}
+func (w *tBridger) WalletTransaction(ctx context.Context, txID string) (*asset.WalletTransaction, error) {
+	if w.bridgeTx == nil || w.bridgeTx.ID != txID {
+		return nil, asset.CoinNotFoundError
+	}
+	return w.bridgeTx, nil
+}
+
+func TestBridgeTradeQty(t *testing.T) {
+	const lotSize = 1e7
+	tests := []struct {
+		name          string
+		amt, rate     uint64
+		sell, isLimit bool
+		limit         uint64
+		wantQty       uint64
+	}{
+		{"sell", 35e6, 0, true, false, 10, 3e7},
+		{"sell capped", 35e6, 0, true, true, 2, 2e7},
+		{"sell less than a lot", 5e6, 0, true, true, 10, 0},
+		{"limit buy", 3e6, 1e7, false, true, 10, 3e7},
+		{"limit buy capped", 3e6, 1e7, false, true, 1, 1e7},
+		{"market buy", 3e6, 0, false, false, 5e6, 3e6},
+		{"market buy capped", 3e6, 0, false, false, 2e6, 2e6},
+	}
+	for _, tt := range tests {
+		if qty := bridgeTradeQty(tt.amt, lotSize, tt.rate, tt.sell, tt.isLimit, tt.limit); qty != tt.wantQty {
+			t.Fatalf("%s: wanted qty %d, got %d", tt.name, tt.wantQty, qty)
+		}
+	}
+}
+
+func TestOrderProceeds(t *testing.T) {
+	const rate = 1e6
+	redeem := &Coin{}
+	tests := []struct {
+		name         string
+		sell         bool
+		status       order.OrderStatus
+		matches      []*Match
+		wantReceived uint64
+		wantDone     bool
+	}{
+		{
+			name:     "booked",
+			status:   order.OrderStatusBooked,
+			matches:  []*Match{{Qty: 1e8, Rate: rate, Redeem: redeem}},
+			wantDone: false, wantReceived: 1e8,
+		},
+		{
+			name:     "executed with active match",
+			sell:     true,
+			status:   order.OrderStatusExecuted,
+			matches:  []*Match{{Qty: 1e8, Rate: rate, Redeem: redeem}, {Qty: 1e8, Rate: rate, Active: true}},
+			wantDone: false, wantReceived: calc.BaseToQuote(rate, 1e8),
+		},
+		{
+			name:   "executed with refund",
+			sell:   true,
+			status: order.OrderStatusExecuted,
+			matches: []*Match{
+				{Qty: 1e8, Rate: rate, Redeem: redeem},
+				{Qty: 1e8, Rate: rate, Active: true, Refund: &Coin{}},
+				{Qty: 1e8, IsCancel: true, Active: true},
+			},
+			wantDone: true, wantReceived: calc.BaseToQuote(rate, 1e8),
+		},
+		{
+			name:     "canceled without fills",
+			status:   order.OrderStatusCanceled,
+			wantDone: true,
+		},
+	}
+	for _, tt := range tests {
+		received, done := orderProceeds(&Order{Sell: tt.sell, Status: tt.status, Matches: tt.matches})
+		if received != tt.wantReceived || done != tt.wantDone {
+			t.Fatalf("%s: wanted received = %d, done = %t, got %d, %t", tt.name, tt.wantReceived, tt.wantDone, received, done)
+		}
+	}
+}
+
+func TestEstimateBridgeTradeValidation(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcWallet.Wallet = &tBridger{TXCWallet: tBtcWallet, dests: map[uint32][]string{tACCTAsset.ID: {"simnet"}}}
+	rig.core.wallets[tUTXOAssetB.ID] = btcWallet
+
+	form := func() *BridgeTradeForm {
+		return &BridgeTradeForm{
+			TradeFirst:        true,
+			BridgeFromAssetID: tUTXOAssetB.ID,
+			BridgeToAssetID:   tACCTAsset.ID,
+			BridgeName:        "simnet",
+			Trade: TradeForm{
+				Host:  tDexHost,
+				Sell:  true,
+				Base:  tUTXOAssetA.ID,
+				Quote: tUTXOAssetB.ID,
+				Qty:   dcrBtcLotSize,
+			},
+		}
+	}
+	for name, mod := range map[string]func(*BridgeTradeForm){
+		"trade does not receive bridged asset": func(f *BridgeTradeForm) { f.Trade.Sell = false },
+		"no quantity":                          func(f *BridgeTradeForm) { f.Trade.Qty = 0 },
+		"limit order without rate":             func(f *BridgeTradeForm) { f.Trade.IsLimit = true },
+		"unknown bridge":                       func(f *BridgeTradeForm) { f.BridgeName = "across" },
+		"trade does not spend bridged asset":   func(f *BridgeTradeForm) { f.TradeFirst = false; f.BridgeAmount = 1e8 },
+		"no bridge amount": func(f *BridgeTradeForm) {
+			f.TradeFirst = false
+			f.BridgeFromAssetID, f.BridgeToAssetID = tACCTAsset.ID, tUTXOAssetB.ID
+			f.Trade.Sell = false
+		},
+	} {
+		f := form()
+		mod(f)
+		if _, err := rig.core.EstimateBridgeTrade(f); err == nil {
+			t.Fatalf("%s: no error", name)
+		}
+	}
+
+	btcWallet.Wallet.(*tBridger).approval = asset.NotApproved
+	if _, err := rig.core.EstimateBridgeTrade(form()); err == nil || !strings.Contains(err.Error(), "must be approved") {
+		t.Fatalf("wrong error for unapproved bridge contract: %v", err)
+	}
+}
+
+func TestBridgeTradeFlow(t *testing.T) {
+	const rate, initFee = 1e6, 1000
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.loggedIn = true
+
+	dcrWallet, _ := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcBridger := &tBridger{
+		TXCWallet: tBtcWallet,
+		dests:     map[uint32][]string{tACCTAsset.ID: {"simnet"}},
+		initFee:   initFee,
+	}
+	btcWallet.Wallet = btcBridger
+	btcWallet.setBalance(&WalletBalance{Balance: &db.Balance{Balance: asset.Balance{Available: 1e8}}})
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	ethWallet, tEthWallet := newTWallet(tACCTAsset.ID)
+	ethWallet.Wallet = &tBridger{TXCWallet: tEthWallet}
+	tCore.wallets[tACCTAsset.ID] = ethWallet
+
+	// Sell DCR for BTC, then bridge the BTC. The order was placed and has
+	// been executed.
+	lo, dbOrder, _, _ := makeLimitOrder(rig.dc, true, 2*dcrBtcLotSize, rate)
+	dbOrder.MetaData.Status = order.OrderStatusExecuted
+	rig.db.orderOrders[lo.ID()] = dbOrder
+	match := &db.MetaMatch{
+		MetaData: &db.MatchMetaData{Proof: db.MatchProof{MakerRedeem: encode.RandomBytes(36)}},
+		UserMatch: &order.UserMatch{
+			OrderID:  lo.ID(),
+			MatchID:  ordertest.RandomMatchID(),
+			Quantity: 2 * dcrBtcLotSize,
+			Rate:     rate,
+			Status:   order.MakerRedeemed,
+			Side:     order.Maker,
+			Address:  "address",
+		},
+	}
+	rig.db.matchesByOrderID = map[order.OrderID][]*db.MetaMatch{lo.ID(): {match}}
+	proceeds := calc.BaseToQuote(rate, 2*dcrBtcLotSize)
+
+	bt := &db.BridgeTrade{
+		ID:                "abc",
+		TradeFirst:        true,
+		BridgeFromAssetID: tUTXOAssetB.ID,
+		BridgeToAssetID:   tACCTAsset.ID,
+		BridgeName:        "simnet",
+		Host:              tDexHost,
+		Base:              tUTXOAssetA.ID,
+		Quote:             tUTXOAssetB.ID,
+		Sell:              true,
+		Qty:               2 * dcrBtcLotSize,
+		Step:              db.BridgeTradeStepTrade,
+		Status:            db.BridgeTradeActive,
+		OrderID:           lo.ID().String(),
+	}
+	tCore.bridgeTrades.put(bt)
+	check := func(tag string, step db.BridgeTradeStep, status db.BridgeTradeStatus) *db.BridgeTrade {
+		t.Helper()
+		bt, _ := tCore.bridgeTrades.get("abc")
+		if bt.Step != step || bt.Status != status {
+			t.Fatalf("%s: wanted step %s, status %s, got %s, %s (error = %q)", tag, step, status, bt.Step, bt.Status, bt.Error)
+		}
+		return bt
+	}
+
+	// The maker swap is not confirmed yet, so the match is still active.
+	tCore.advanceBridgeTrades()
+	if bt = check("match active", db.BridgeTradeStepTrade, db.BridgeTradeActive); bt.TradeReceived != proceeds {
+		t.Fatalf("wrong trade received %d, wanted %d", bt.TradeReceived, proceeds)
+	}
+
+	// The match is confirmed, so the bridge is next.
+	match.UserMatch.Status = order.MatchConfirmed
+	tCore.advanceBridgeTrades()
+	check("trade done", db.BridgeTradeStepBridge, db.BridgeTradeActive)
+
+	// The proceeds less the bridge fee are bridged.
+	tCore.advanceBridgeTrades()
+	bt = check("bridge initiated", db.BridgeTradeStepBridge, db.BridgeTradeActive)
+	if btcBridger.initiated != proceeds-initFee || bt.BridgeAmount != proceeds-initFee || bt.BridgeTxID != "bridgetx" || bt.BridgeInitiating == 0 {
+		t.Fatalf("wrong bridge initiated. amount = %d, bridge trade = %+v", btcBridger.initiated, bt)
+	}
+
+	// Not complete yet.
+	btcBridger.bridgeTx = &asset.WalletTransaction{ID: "bridgetx", BridgeCounterpartTx: &asset.BridgeCounterpartTx{}}
+	tCore.advanceBridgeTrades()
+	check("bridge pending", db.BridgeTradeStepBridge, db.BridgeTradeActive)
+
+	btcBridger.bridgeTx.BridgeCounterpartTx = &asset.BridgeCounterpartTx{Complete: true, AmountReceived: proceeds - 2*initFee}
+	tCore.advanceBridgeTrades()
+	if bt = check("complete", db.BridgeTradeStepDone, db.BridgeTradeComplete); bt.BridgeReceived != proceeds-2*initFee {
+		t.Fatalf("wrong bridge received %d", bt.BridgeReceived)
+	}
+	// The start of the bridge is stored before it is initiated.
+	if n := len(rig.db.bridgeTrades); n != 5 {
+		t.Fatalf("expected 5 bridge trade updates stored, got %d", n)
+	}
+
+	// Complete bridge trades are not advanced, and can't be canceled.
+	tCore.advanceBridgeTrades()
+	if n := len(rig.db.bridgeTrades); n != 5 {
+		t.Fatalf("complete bridge trade was updated")
+	}
+	if err := tCore.CancelBridgeTrade("abc"); err == nil {
+		t.Fatalf("no error canceling complete bridge trade")
+	}
+}
+
+func TestBridgeReceived(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcBridger := &tBridger{TXCWallet: tBtcWallet}
+	btcWallet.Wallet = btcBridger
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	ethWallet, tEthWallet := newTWallet(tACCTAsset.ID)
+	ethBridger := &tBridger{TXCWallet: tEthWallet}
+	ethWallet.Wallet = ethBridger
+	tCore.wallets[tACCTAsset.ID] = ethWallet
+
+	// The bridge is complete, but the source wallet did not record the
+	// amount received.
+	btcBridger.bridgeTx = &asset.WalletTransaction{ID: "bridgetx", BridgeCounterpartTx: &asset.BridgeCounterpartTx{
+		Complete: true,
+		IDs:      []string{"mint"},
+	}}
+	newBridgeTrade := func() *db.BridgeTrade {
+		return &db.BridgeTrade{
+			ID:                "abc",
+			BridgeFromAssetID: tUTXOAssetB.ID,
+			BridgeToAssetID:   tACCTAsset.ID,
+			BridgeAmount:      1e8,
+			BridgeTxID:        "bridgetx",
+			Step:              db.BridgeTradeStepBridge,
+			Status:            db.BridgeTradeActive,
+		}
+	}
+
+	// The destination wallet doesn't have the completion yet.
+	bt := newBridgeTrade()
+	if updated, err := tCore.advanceBridgeStep(bt); err != nil || updated {
+		t.Fatalf("bridge step advanced without the completion: updated = %t, err = %v", updated, err)
+	}
+
+	// The amount is read from the destination wallet's completion.
+	ethBridger.bridgeTx = &asset.WalletTransaction{ID: "mint", Type: asset.CompleteBridge, Amount: 1e8 - 5000}
+	if _, err := tCore.advanceBridgeStep(bt); err != nil {
+		t.Fatalf("advanceBridgeStep error: %v", err)
+	}
+	if bt.Step != db.BridgeTradeStepTrade || bt.BridgeReceived != 1e8-5000 {
+		t.Fatalf("wrong bridge received %d at step %s", bt.BridgeReceived, bt.Step)
+	}
+
+	// The trade can't be sized without the amount received.
+	ethBridger.bridgeTx.Amount = 0
+	bt = newBridgeTrade()
+	if _, err := tCore.advanceBridgeStep(bt); err != nil {
+		t.Fatalf("advanceBridgeStep error: %v", err)
+	}
+	if bt.Status != db.BridgeTradeFailed || bt.BridgeReceived != 0 {
+		t.Fatalf("bridge trade not failed with an unknown amount received: %+v", bt)
+	}
+}
+
+func TestBridgeTradeFailure(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.loggedIn = true
+
+	// There is no BTC wallet, so the bridge can't be initiated.
+	bt := &db.BridgeTrade{
+		ID:                "abc",
+		BridgeFromAssetID: tUTXOAssetB.ID,
+		BridgeToAssetID:   tACCTAsset.ID,
+		BridgeName:        "simnet",
+		BridgeAmount:      1e8,
+		Step:              db.BridgeTradeStepBridge,
+		Status:            db.BridgeTradeActive,
+	}
+	tCore.bridgeTrades.put(bt)
+	for i := 0; i < bridgeTradeMaxAttempts; i++ {
+		tCore.advanceBridgeTrades()
+	}
+	if bt, _ = tCore.bridgeTrades.get("abc"); bt.Status != db.BridgeTradeFailed || bt.Attempts != bridgeTradeMaxAttempts || bt.Error == "" {
+		t.Fatalf("bridge trade not failed: %+v", bt)
+	}
+
+	bt.ID, bt.Status, bt.Attempts = "def", db.BridgeTradeActive, 0
+	tCore.bridgeTrades.put(bt)
+	if err := tCore.CancelBridgeTrade("def"); err != nil {
+		t.Fatalf("CancelBridgeTrade error: %v", err)
+	}
+	tCore.advanceBridgeTrades()
+	if bt, _ = tCore.bridgeTrades.get("def"); bt.Status != db.BridgeTradeCanceled || bt.Attempts != 0 {
+		t.Fatalf("canceled bridge trade advanced: %+v", bt)
+	}
+}
+
+func TestBridgeTradeRecovery(t *testing.T) {
+	const rate = 1e6
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.loggedIn = true
+
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcBridger := &tBridger{TXCWallet: tBtcWallet}
+	btcWallet.Wallet = btcBridger
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	initiating := uint64(time.Now().Add(-time.Minute).UnixMilli())
+	newBridgeTrade := func(id string) *db.BridgeTrade {
+		bt := &db.BridgeTrade{
+			ID:                id,
+			BridgeFromAssetID: tUTXOAssetB.ID,
+			BridgeToAssetID:   tACCTAsset.ID,
+			BridgeName:        "simnet",
+			BridgeAmount:      1e8,
+			BridgeInitiating:  initiating,
+			Host:              tDexHost,
+			Base:              tUTXOAssetA.ID,
+			Quote:             tUTXOAssetB.ID,
+			Step:              db.BridgeTradeStepBridge,
+			Status:            db.BridgeTradeActive,
+		}
+		tCore.bridgeTrades.put(bt)
+		return bt
+	}
+	bridgeTx := func(id string, amt uint64) *asset.WalletTransaction {
+		return &asset.WalletTransaction{
+			ID:                  id,
+			Amount:              amt,
+			BridgeCounterpartTx: &asset.BridgeCounterpartTx{AssetID: tACCTAsset.ID},
+		}
+	}
+
+	// The interrupted bridge is found, and is not initiated again.
+	btcBridger.pending = []*asset.WalletTransaction{bridgeTx("other", 2e8), bridgeTx("bridgetx", 1e8)}
+	newBridgeTrade("abc")
+	tCore.advanceBridgeTrades()
+	bt, _ := tCore.bridgeTrades.get("abc")
+	if bt.Status != db.BridgeTradeActive || bt.BridgeTxID != "bridgetx" || btcBridger.initiated != 0 {
+		t.Fatalf("bridge not recovered: %+v", bt)
+	}
+
+	// The bridge is claimed, so a second interrupted bridge trade fails.
+	newBridgeTrade("def")
+	tCore.advanceBridgeTrades()
+	if bt, _ = tCore.bridgeTrades.get("def"); bt.Status != db.BridgeTradeFailed || btcBridger.initiated != 0 {
+		t.Fatalf("bridge trade without a bridge not failed: %+v", bt)
+	}
+
+	// The interrupted order is found.
+	lo, dbOrder, _, _ := makeLimitOrder(rig.dc, true, 2*dcrBtcLotSize, rate)
+	dbOrder.MetaData.Status = order.OrderStatusBooked
+	rig.db.allOrders = []*db.MetaOrder{dbOrder}
+	rig.db.orderOrders[lo.ID()] = dbOrder
+	bt = &db.BridgeTrade{
+		ID:           "ghi",
+		Host:         tDexHost,
+		Base:         tUTXOAssetA.ID,
+		Quote:        tUTXOAssetB.ID,
+		Sell:         true,
+		IsLimit:      true,
+		Rate:         rate,
+		Qty:          2 * dcrBtcLotSize,
+		OrderPlacing: initiating,
+		Step:         db.BridgeTradeStepTrade,
+		Status:       db.BridgeTradeActive,
+	}
+	tCore.bridgeTrades.put(bt)
+	tCore.advanceBridgeTrades()
+	if bt, _ = tCore.bridgeTrades.get("ghi"); bt.Status != db.BridgeTradeActive || bt.OrderID != lo.ID().String() {
+		t.Fatalf("order not recovered: %+v", bt)
+	}
+
+	// An order for a different quantity is not taken.
+	bt.ID, bt.OrderID, bt.Qty = "jkl", "", dcrBtcLotSize
+	tCore.bridgeTrades.put(bt)
+	tCore.advanceBridgeTrades()
+	if bt, _ = tCore.bridgeTrades.get("jkl"); bt.Status != db.BridgeTradeFailed || bt.OrderID != "" {
+		t.Fatalf("bridge trade without an order not failed: %+v", bt)
+	}
+}
+
+func TestBridgeTradeStoreError(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.loggedIn = true
+
+	btcWallet, tBtcWallet := newTWallet(tUTXOAssetB.ID)
+	btcBridger := &tBridger{TXCWallet: tBtcWallet}
+	btcWallet.Wallet = btcBridger
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+
+	// The start of the bridge can't be stored, so it is not initiated, and
+	// the bridge trade stops.
+	rig.db.bridgeTradeErr = tErr
+	tCore.bridgeTrades.put(&db.BridgeTrade{
+		ID:                "abc",
+		BridgeFromAssetID: tUTXOAssetB.ID,
+		BridgeToAssetID:   tACCTAsset.ID,
+		BridgeName:        "simnet",
+		BridgeAmount:      1e8,
+		Step:              db.BridgeTradeStepBridge,
+		Status:            db.BridgeTradeActive,
+	})
+	tCore.advanceBridgeTrades()
+	bt, _ := tCore.bridgeTrades.get("abc")
+	if btcBridger.initiated != 0 || bt.BridgeInitiating != 0 || bt.Status != db.BridgeTradeFailed {
+		t.Fatalf("bridge trade not stopped: %+v", bt)
+	}
+}
@@ -0,0 +1,85 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+// coinController returns the asset.CoinController for the asset's wallet.
+func (c *Core) coinController(assetID uint32) (*xcWallet, asset.CoinController, error) {
+	wallet, err := c.connectedWallet(assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	cc, is := wallet.Wallet.(asset.CoinController)
+	if !is {
+		return nil, nil, fmt.Errorf("%s wallet does not support coin control", unbip(assetID))
+	}
+	return wallet, cc, nil
+}
+
+// SpendableCoins lists the unspent outputs in the asset's wallet. Coins locked
+// by active orders or bonds are included, but marked Locked.
+func (c *Core) SpendableCoins(assetID uint32) ([]*asset.SpendableCoin, error) {
+	_, cc, err := c.coinController(assetID)
+	if err != nil {
+		return nil, err
+	}
+	return cc.SpendableCoins()
+}
+
+// SendWithCoins sends from the asset's wallet, spending exactly the specified
+// coins. If send.Value is zero, the full value of the coins less fees is sent
+// with no change output. Coins locked by active orders cannot be spent.
+func (c *Core) SendWithCoins(pw []byte, assetID uint32, send *asset.CoinControlSend) (asset.Coin, error) {
+	var crypter encrypt.Crypter
+	// Empty password can be provided if wallet is already unlocked. Webserver
+	// and RPCServer should not allow empty password.
+	if len(pw) > 0 {
+		var err error
+		crypter, err = c.encryptionKey(pw)
+		if err != nil {
+			return nil, fmt.Errorf("Trade password error: %w", err)
+		}
+		defer crypter.Close()
+	}
+
+	if send == nil || len(send.Coins) == 0 {
+		return nil, errors.New("no coins specified")
+	}
+	if send.Address == "" {
+		return nil, errors.New("no address specified")
+	}
+	wallet, cc, err := c.coinController(assetID)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.connectAndUnlock(crypter, wallet); err != nil {
+		return nil, err
+	}
+	if err = wallet.checkPeersAndSyncStatus(); err != nil {
+		return nil, err
+	}
+
+	coin, err := cc.SendWithCoins(send, c.feeSuggestionAny(assetID))
+	if err != nil {
+		subject, details := c.formatDetails(TopicSendError, unbip(assetID), err)
+		c.notify(newSendNote(TopicSendError, subject, details, db.ErrorLevel))
+		return nil, err
+	}
+
+	sentValue := wallet.Info().UnitInfo.ConventionalString(coin.Value())
+	subject, details := c.formatDetails(TopicSendSuccess, sentValue, unbip(assetID), send.Address, coin)
+	c.notify(newSendNote(TopicSendSuccess, subject, details, db.Success))
+
+	c.updateAssetBalance(assetID)
+
+	return coin, nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+)
+
+func TestSendWithCoins(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.sendCoin = &tCoin{id: encode.RandomBytes(36)}
+	tWallet.spendableCoins = []*asset.SpendableCoin{
+		{ID: encode.RandomBytes(36), Value: 1e8},
+		{ID: encode.RandomBytes(36), Value: 2e8, Locked: true},
+	}
+
+	coins, err := tCore.SpendableCoins(tUTXOAssetA.ID)
+	if err != nil {
+		t.Fatalf("SpendableCoins error: %v", err)
+	}
+	if len(coins) != 2 {
+		t.Fatalf("expected 2 coins, got %d", len(coins))
+	}
+
+	send := &asset.CoinControlSend{
+		Coins:   []dex.Bytes{coins[0].ID},
+		Address: "addr",
+	}
+	if _, err := tCore.SendWithCoins(tPW, tUTXOAssetA.ID, send); err != nil {
+		t.Fatalf("SendWithCoins error: %v", err)
+	}
+	if tWallet.coinControlSend != send {
+		t.Fatalf("send not passed to wallet")
+	}
+
+	if _, err := tCore.SendWithCoins(tPW, tUTXOAssetA.ID, &asset.CoinControlSend{Address: "addr"}); err == nil {
+		t.Fatalf("no error for no coins")
+	}
+	if _, err := tCore.SendWithCoins(tPW, tUTXOAssetA.ID, &asset.CoinControlSend{Coins: send.Coins}); err == nil {
+		t.Fatalf("no error for no address")
+	}
+	if _, err := tCore.SendWithCoins(tPW, 12345, send); err == nil {
+		t.Fatalf("no error for unknown wallet")
+	}
+
+	tWallet.sendErr = tErr
+	if _, err := tCore.SendWithCoins(tPW, tUTXOAssetA.ID, send); err == nil {
+		t.Fatalf("no error for wallet error")
+	}
+}
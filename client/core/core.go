@@ -34,6 +34,7 @@ import (
 	"decred.org/dcrdex/client/db/bolt"
 	"decred.org/dcrdex/client/mnemonic"
 	"decred.org/dcrdex/client/orderbook"
+	"decred.org/dcrdex/client/refundkit"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/calc"
 	"decred.org/dcrdex/dex/config"
@@ -1620,6 +1621,22 @@ type Config struct {
 	// per DEX connection before new orders are deferred. Zero means use the
 	// default (48).
 	MaxActiveMatches int
+	// BackupDir is the directory for scheduled encrypted backups of the
+	// database and any sources added with AddBackupSource. Backups are
+	// encrypted with a key derived from the app password, so they are only
+	// written while the user is logged in. Scheduled backups are disabled if
+	// BackupDir is empty or BackupInterval is zero.
+	BackupDir string
+	// BackupInterval is the time between scheduled backups.
+	BackupInterval time.Duration
+	// BackupKeep is the number of scheduled backups to keep. Zero keeps all.
+	BackupKeep int
+	// BackupMaxAge is the age after which scheduled backups are deleted. Zero
+	// disables deletion by age. The newest backup is always kept.
+	BackupMaxAge time.Duration
+	// RefundKitPath is the location of the emergency refund kit. The default
+	// is refundkit.bwrk in the same directory as the database.
+	RefundKitPath string
 }
 
 // locale is data associated with the currently selected language.
@@ -1694,12 +1711,47 @@ type Core struct {
 
 	pokesCache *pokesCache
 
+	webhooks *webhookManager
+
+	priceAlerts        *priceAlertManager
+	priceAlertsUpdated chan struct{}
+
+	bridgeTrades        *bridgeTradeManager
+	bridgeTradesUpdated chan struct{}
+
+	bondFeeRatesMtx sync.Mutex
+	bondFeeRates    map[uint32]*bondFeeRate
+
 	requestedActionMtx sync.RWMutex
 	requestedActions   map[string]*asset.ActionRequiredNote
 
 	meshMtx sync.RWMutex
 	mesh    *mesh.Mesh
 	meshCM  *dex.ConnectionMaster
+	// meshOrders maps our mesh order IDs to our stored order IDs.
+	meshOrders map[tanka.ID40]order.OrderID
+	// meshBondPosts are the IDs of the mesh bonds being submitted by
+	// submitMeshBond.
+	meshBondPosts map[tanka.ID32]bool
+	// meshAcct is the account under the MeshHost with our mesh bonds that
+	// have not been refunded. It is nil until we post a mesh bond.
+	meshAcct *dexAccount
+	// meshTradeMtx serializes the db updates of mesh orders and matches.
+	meshTradeMtx sync.Mutex
+
+	backupMtx     sync.Mutex
+	backupCrypter encrypt.Crypter // derived from the app password on login
+	backupSources map[string]*backupSource
+
+	refundKitMtx     sync.Mutex
+	refundKitCrypter encrypt.Crypter // derived from the app password on login
+	refundKit        map[order.MatchID]*refundkit.Entry
+	refundKitDirty   bool // the kit has changes that are not written
+	// refundKitWriteMtx serializes writes of the refund kit file, so that an
+	// older kit is never written over a newer one.
+	refundKitWriteMtx sync.Mutex
+	// refundKitWrite signals runRefundKit to write the kit.
+	refundKitWrite chan struct{}
 }
 
 // New is the constructor for a new Core.
@@ -1842,9 +1894,22 @@ func New(cfg *Config) (*Core, error) {
 
 		fiatRateSources: make(map[string]*commonRateSource),
 		reFiat:          make(chan struct{}, 1),
+		refundKitWrite:  make(chan struct{}, 1),
 
 		notes:            make(chan asset.WalletNotification, 128),
 		requestedActions: make(map[string]*asset.ActionRequiredNote),
+		meshOrders:       make(map[tanka.ID40]order.OrderID),
+		meshBondPosts:    make(map[tanka.ID32]bool),
+
+		// Notifications can be sent before Run, so the webhook manager is
+		// created here. The webhooks are loaded from the DB in initialize.
+		webhooks: newWebhookManager(cfg.Logger.SubLogger("WEBHOOK"), nil),
+
+		priceAlertsUpdated:  make(chan struct{}, 1),
+		bridgeTradesUpdated: make(chan struct{}, 1),
+		bondFeeRates:        make(map[uint32]*bondFeeRate),
+
+		backupSources: make(map[string]*backupSource),
 	}
 
 	c.intl.Store(&locale{
@@ -1925,6 +1990,43 @@ fetchers:
 		}
 	}()
 
+	// Start webhook deliveries.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.webhooks.run(ctx)
+	}()
+
+	// Keep books synced for mid-gap price alerts.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.watchPriceAlertBooks(ctx)
+	}()
+
+	// Keep the refund kit up to date.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.runRefundKit(ctx)
+	}()
+
+	// Run the steps of bridge trades.
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.runBridgeTrades(ctx)
+	}()
+
+	// Write scheduled backups.
+	if c.scheduledBackups() {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.runBackups(ctx)
+		}()
+	}
+
 	// Start bond supervisor.
 	c.wg.Add(1)
 	go func() {
@@ -2159,12 +2261,21 @@ func (c *Core) Exchanges() map[string]*Exchange {
 	for _, dc := range dcs {
 		infos[dc.acct.host] = c.exchangeInfo(dc)
 	}
+	if xc := c.meshExchange(); xc != nil {
+		infos[MeshHost] = xc
+	}
 	return infos
 }
 
 // Exchange returns an exchange with a certain host. It returns an error if
 // no exchange exists at that host.
 func (c *Core) Exchange(host string) (*Exchange, error) {
+	if host == MeshHost {
+		if xc := c.meshExchange(); xc != nil {
+			return xc, nil
+		}
+		return nil, errors.New("not connected to the mesh")
+	}
 	dc, _, err := c.dex(host)
 	if err != nil {
 		return nil, err
@@ -2751,6 +2862,44 @@ func (c *Core) CreateWallet(appPW, walletPW []byte, form *WalletForm) error {
 	return c.createWallet(crypter, walletPW, form)
 }
 
+// AddCustomToken adds a user-defined token to the wallet for the parent asset
+// by contract address, and creates a wallet for the token. Custom tokens can be
+// sent and received, but are not traded on any DEX. The token's asset ID is
+// returned.
+func (c *Core) AddCustomToken(appPW []byte, parentID uint32, contractAddr string) (uint32, error) {
+	crypter, err := c.encryptionKey(appPW)
+	if err != nil {
+		return 0, err
+	}
+	parent, err := c.connectedWallet(parentID)
+	if err != nil {
+		return 0, err
+	}
+	adder, is := parent.Wallet.(asset.CustomTokenAdder)
+	if !is {
+		return 0, fmt.Errorf("%s wallet does not support custom tokens", unbip(parentID))
+	}
+	tokenID, err := adder.AddCustomToken(c.ctx, contractAddr)
+	if err != nil {
+		return 0, fmt.Errorf("error adding custom token: %w", err)
+	}
+	if _, exists := c.wallet(tokenID); exists {
+		return tokenID, nil
+	}
+	tkn := asset.TokenInfo(tokenID)
+	if tkn == nil {
+		return 0, fmt.Errorf("custom token %d not registered", tokenID)
+	}
+	if err := c.createWallet(crypter, nil, &WalletForm{
+		AssetID: tokenID,
+		Config:  make(map[string]string),
+		Type:    tkn.Definition.Type,
+	}); err != nil {
+		return 0, fmt.Errorf("error creating %s wallet: %w", unbip(tokenID), err)
+	}
+	return tokenID, nil
+}
+
 func (c *Core) createWallet(crypter encrypt.Crypter, walletPW []byte, form *WalletForm) (err error) {
 	assetID := form.AssetID
 	symbol := unbip(assetID)
@@ -3644,6 +3793,8 @@ func (c *Core) changeAppPass(newAppPW, innerKey []byte, creds *db.PrimaryCredent
 	}
 
 	c.setCredentials(newCreds)
+	c.replaceBackupKey(newAppPW)
+	c.replaceRefundKitKey(newAppPW)
 
 	return nil
 }
@@ -4856,6 +5007,7 @@ func (c *Core) Login(pw []byte) error {
 					DataDir:    filepath.Join(filepath.Dir(c.cfg.DBPath), "mesh"),
 					PrivateKey: meshPriv,
 					Logger:     c.log.SubLogger("MESH"),
+					Net:        c.net,
 					EntryNode: &mesh.TatankaCredentials{
 						PeerID: tanka.SimnetTatankaPeerID,
 						Addr:   "127.0.0.1:7323",
@@ -4891,6 +5043,8 @@ func (c *Core) Login(pw []byte) error {
 	if needsInit, err := login(); err != nil {
 		return err
 	} else if needsInit {
+		c.setBackupKey(pw)
+		c.loadRefundKit(pw)
 		// It is not an error if we can't connect, unless we need the wallet
 		// for active trades, but that condition is checked later in
 		// resolveActiveTrades. We won't try to unlock here, but if the wallet
@@ -4900,6 +5054,7 @@ func (c *Core) Login(pw []byte) error {
 		c.connectWallets(crypter) // initialize reserves
 		c.notify(newLoginNote("Resuming active trades..."))
 		c.resolveActiveTrades(crypter)
+		c.resumeBridgeTrades(crypter)
 		c.connectMesh()
 		c.notify(newLoginNote("Connecting to DEX servers..."))
 		c.initializeDEXConnections(crypter)
@@ -5141,6 +5296,14 @@ func (c *Core) ActiveOrders() (map[string][]*Order, map[string][]*InFlightOrder,
 		}
 	}
 
+	meshOrds, err := c.db.ActiveDEXOrders(MeshHost)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ord := range meshOrds {
+		dexActiveOrders[MeshHost] = append(dexActiveOrders[MeshHost], coreOrderFromTrade(ord.Order, ord.MetaData))
+	}
+
 	return dexActiveOrders, dexInflightOrders, nil
 }
 
@@ -5204,6 +5367,8 @@ func (c *Core) Logout() error {
 	c.bondXPriv = nil
 	c.multisigXPriv.Zero()
 	c.multisigXPriv = nil
+	c.clearBackupKey()
+	c.clearRefundKitKey()
 
 	c.loggedIn = false
 
@@ -6465,7 +6630,14 @@ func (c *Core) TxHistory(assetID uint32, req *asset.TxHistoryRequest) (*asset.Tx
 		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
 	}
 
-	return wallet.TxHistory(req)
+	res, err := wallet.TxHistory(req)
+	if err != nil {
+		return nil, err
+	}
+	return &asset.TxHistoryResponse{
+		Txs:           c.labelTxs(assetID, res.Txs),
+		MoreAvailable: res.MoreAvailable,
+	}, nil
 }
 
 // WalletTransaction returns information about a transaction that the wallet
@@ -6477,11 +6649,18 @@ func (c *Core) WalletTransaction(assetID uint32, txID string) (*asset.WalletTran
 		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
 	}
 
-	return wallet.WalletTransaction(c.ctx, txID)
+	tx, err := wallet.WalletTransaction(c.ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	return c.labelTxs(assetID, []*asset.WalletTransaction{tx})[0], nil
 }
 
 // Trade is used to place a market or limit order.
 func (c *Core) Trade(pw []byte, form *TradeForm) (*Order, error) {
+	if form.Host == MeshHost {
+		return c.tradeMesh(pw, form)
+	}
 	req, err := c.prepareTradeRequest(pw, form)
 	if err != nil {
 		return nil, err
@@ -6499,6 +6678,14 @@ func (c *Core) Trade(pw []byte, form *TradeForm) (*Order, error) {
 // server validation. This helps handle some issues related to UI/UX where
 // server response might take a fairly long time (15 - 20s).
 func (c *Core) TradeAsync(pw []byte, form *TradeForm) (*InFlightOrder, error) {
+	if form.Host == MeshHost {
+		// Mesh orders are booked without a server round trip.
+		corder, err := c.tradeMesh(pw, form)
+		if err != nil {
+			return nil, err
+		}
+		return &InFlightOrder{Order: corder}, nil
+	}
 	req, err := c.prepareTradeRequest(pw, form)
 	if err != nil {
 		return nil, err
@@ -6826,6 +7013,7 @@ func (c *Core) createTradeRequest(wallets *walletSet, coins asset.Coins, redeemS
 			RefundReserves:     refundReserves,
 			ChangeCoin:         changeID,
 			FundingFeesPaid:    fundingFees,
+			FiatRates:          c.orderFiatRates(ord),
 		},
 		Order: ord,
 	}
@@ -7317,6 +7505,9 @@ func (c *Core) Cancel(oidB dex.Bytes) error {
 }
 
 func (c *Core) cancelOrder(oid order.OrderID) error {
+	if found, err := c.cancelMeshOrder(oid); found || err != nil {
+		return err
+	}
 	for _, dc := range c.dexConnections() {
 		found, err := c.tryCancel(dc, oid)
 		if err != nil {
@@ -7896,10 +8087,34 @@ func (c *Core) initialize() error {
 		c.pokesCache.init(pokes)
 	}
 
+	hooks, err := c.db.Webhooks()
+	if err != nil {
+		c.log.Errorf("Error loading webhooks from db: %v", err)
+	}
+	for _, wh := range hooks {
+		c.webhooks.setHook(wh)
+	}
+
+	alerts, err := c.db.PriceAlerts()
+	if err != nil {
+		c.log.Errorf("Error loading price alerts from db: %v", err)
+	}
+	c.priceAlerts = newPriceAlertManager(alerts)
+
+	bridgeTrades, err := c.db.BridgeTrades()
+	if err != nil {
+		c.log.Errorf("Error loading bridge trades from db: %v", err)
+	}
+	c.bridgeTrades = newBridgeTradeManager(bridgeTrades)
+
 	// Start connecting to DEX servers.
 	var liveConns uint32
 	var wg sync.WaitGroup
 	for _, acct := range accts {
+		if acct.Host == MeshHost {
+			c.loadMeshAccount(acct)
+			continue
+		}
 		wg.Add(1)
 		go func(acct *db.AccountInfo) {
 			defer wg.Done()
@@ -10762,6 +10977,10 @@ func (c *Core) handleBridgeCompleted(n *asset.BridgeCompletedNote) {
 	sourceWallet.MarkBridgeComplete(n.InitiationTxID, n.CompletionTxIDs, n.AmtReceived, n.Fees, n.Complete)
 
 	c.notify(newBridgeNote(n))
+
+	if n.Complete {
+		c.signalBridgeTradesUpdated()
+	}
 }
 
 // handleWalletNotification processes an asynchronous wallet notification.
@@ -10788,6 +11007,13 @@ func (c *Core) handleWalletNotification(ni asset.WalletNotification) {
 			return
 		}
 		w.processWalletTransactions([]*asset.WalletTransaction{n.Transaction})
+	case *asset.WarningNote:
+		w, ok := c.wallet(n.AssetID)
+		if !ok {
+			return
+		}
+		subject, details := c.formatDetails(TopicWalletWarning, unbip(n.AssetID), n.Message)
+		c.notify(newWalletConfigNote(TopicWalletWarning, subject, details, db.WarningLevel, w.state()))
 	}
 	c.notify(newWalletNote(ni))
 }
@@ -11963,6 +12189,7 @@ func (c *Core) refreshFiatRates(ctx context.Context) {
 	fiatRatesMap := c.fiatConversions()
 	if len(fiatRatesMap) != 0 {
 		c.notify(newFiatRatesUpdate(fiatRatesMap))
+		c.checkFiatPriceAlerts(fiatRatesMap)
 	}
 }
 
@@ -11986,6 +12213,22 @@ func (c *Core) FiatConversionRates() map[uint32]float64 {
 
 // fiatConversions returns fiat rate for all supported assets that have a
 // wallet.
+// orderFiatRates are the current fiat rates of the order's assets and their fee
+// assets, to be stored with the order. Assets without a rate are omitted.
+func (c *Core) orderFiatRates(ord order.Order) map[uint32]float64 {
+	allRates := c.fiatConversions()
+	rates := make(map[uint32]float64, 4)
+	for _, assetID := range []uint32{ord.Base(), ord.Quote(), feeAsset(ord.Base()), feeAsset(ord.Quote())} {
+		if rate := allRates[assetID]; rate > 0 {
+			rates[assetID] = rate
+		}
+	}
+	if len(rates) == 0 {
+		return nil
+	}
+	return rates
+}
+
 func (c *Core) fiatConversions() map[uint32]float64 {
 	assetIDs := make(map[uint32]struct{})
 	supportedAssets := asset.Assets()
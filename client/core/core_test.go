@@ -13,6 +13,7 @@ import (
 	"math"
 	mrand "math/rand/v2"
 	"os"
+	"path/filepath"
 	"reflect"
 	"slices"
 	"sort"
@@ -358,6 +359,11 @@ func (conn *TWebsocket) UpdateURL(string) {}
 
 type TDB struct {
 	updateWalletErr  error
+	labels           []*db.Label
+	contacts         []*db.Contact
+	bridgeTrades     []*db.BridgeTrade
+	webhooks         []*db.Webhook
+	bridgeTradeErr   error
 	acct             *db.AccountInfo
 	acctErr          error
 	createAccountErr error
@@ -473,7 +479,11 @@ func (tdb *TDB) Order(oid order.OrderID) (*db.MetaOrder, error) {
 	if tdb.orderErr != nil {
 		return nil, tdb.orderErr
 	}
-	return tdb.orderOrders[oid], nil
+	mOrd, found := tdb.orderOrders[oid]
+	if !found {
+		return nil, db.ErrOrderNotFound
+	}
+	return mOrd, nil
 }
 
 func (tdb *TDB) Orders(filter *db.OrderFilter) ([]*db.MetaOrder, error) {
@@ -735,6 +745,52 @@ func (tdb *TDB) MMEpochSnapshots(host string, base, quote uint32, startEpoch, en
 func (tdb *TDB) PruneMMEpochSnapshots(host string, base, quote uint32, minEpochIdx uint64) (int, error) {
 	return 0, nil
 }
+func (tdb *TDB) UpdateWebhook(wh *db.Webhook) error {
+	tdb.webhooks = append(tdb.webhooks, wh)
+	return nil
+}
+func (tdb *TDB) DeleteWebhook(id string) error {
+	return nil
+}
+func (tdb *TDB) Webhooks() ([]*db.Webhook, error) {
+	return tdb.webhooks, nil
+}
+func (tdb *TDB) UpdatePriceAlert(alert *db.PriceAlert) error {
+	return nil
+}
+func (tdb *TDB) DeletePriceAlert(id string) error {
+	return nil
+}
+func (tdb *TDB) PriceAlerts() ([]*db.PriceAlert, error) {
+	return nil, nil
+}
+func (tdb *TDB) SetLabel(label *db.Label) error {
+	tdb.labels = append(tdb.labels, label)
+	return nil
+}
+func (tdb *TDB) Labels(assetID uint32) ([]*db.Label, error) {
+	return tdb.labels, nil
+}
+func (tdb *TDB) UpdateContact(contact *db.Contact) error {
+	tdb.contacts = append(tdb.contacts, contact)
+	return nil
+}
+func (tdb *TDB) DeleteContact(assetID uint32, name string) error {
+	return nil
+}
+func (tdb *TDB) Contacts(assetID uint32) ([]*db.Contact, error) {
+	return tdb.contacts, nil
+}
+func (tdb *TDB) UpdateBridgeTrade(bt *db.BridgeTrade) error {
+	if tdb.bridgeTradeErr != nil {
+		return tdb.bridgeTradeErr
+	}
+	tdb.bridgeTrades = append(tdb.bridgeTrades, bt)
+	return nil
+}
+func (tdb *TDB) BridgeTrades() ([]*db.BridgeTrade, error) {
+	return nil, nil
+}
 
 type tCoin struct {
 	id []byte
@@ -759,9 +815,10 @@ func (c *tCoin) Value() uint64 {
 }
 
 type tReceipt struct {
-	coin       *tCoin
-	contract   []byte
-	expiration time.Time
+	coin         *tCoin
+	contract     []byte
+	expiration   time.Time
+	signedRefund []byte
 }
 
 func (r *tReceipt) Coin() asset.Coin {
@@ -781,7 +838,7 @@ func (r *tReceipt) String() string {
 }
 
 func (r *tReceipt) SignedRefund() dex.Bytes {
-	return nil
+	return r.signedRefund
 }
 
 type TXCWallet struct {
@@ -789,6 +846,12 @@ type TXCWallet struct {
 	sendFeeSuggestion   uint64
 	sendCoin            *tCoin
 	sendErr             error
+	batchRecipients     []*asset.BatchRecipient
+	batchSendErr        error
+	spendableCoins      []*asset.SpendableCoin
+	txHistory           []*asset.WalletTransaction
+	walletTx            *asset.WalletTransaction
+	coinControlSend     *asset.CoinControlSend
 	addrErr             error
 	signCoinErr         error
 	lastSwapsMtx        sync.Mutex
@@ -1095,6 +1158,29 @@ func (w *TXCWallet) Send(address string, value, feeSuggestion uint64) (asset.Coi
 	return w.sendCoin, w.sendErr
 }
 
+func (w *TXCWallet) SendBatch(recipients []*asset.BatchRecipient, feeSuggestion uint64) ([]string, error) {
+	w.sendFeeSuggestion = feeSuggestion
+	w.batchRecipients = recipients
+	if w.batchSendErr != nil {
+		return nil, w.batchSendErr
+	}
+	return []string{"abc"}, nil
+}
+
+func (w *TXCWallet) EstimateBatchSendTxFee(recipients []*asset.BatchRecipient, feeRate uint64) (uint64, error) {
+	return w.estFee, w.estFeeErr
+}
+
+func (w *TXCWallet) SpendableCoins() ([]*asset.SpendableCoin, error) {
+	return w.spendableCoins, nil
+}
+
+func (w *TXCWallet) SendWithCoins(send *asset.CoinControlSend, feeSuggestion uint64) (asset.Coin, error) {
+	w.sendFeeSuggestion = feeSuggestion
+	w.coinControlSend = send
+	return w.sendCoin, w.sendErr
+}
+
 func (w *TXCWallet) SendTransaction(rawTx []byte) ([]byte, error) {
 	return w.feeCoinSent, w.sendTxnErr
 }
@@ -1279,10 +1365,10 @@ func (w *TXCWallet) MakeBondTx(ver uint16, amt, feeRate uint64, lockTime time.Ti
 }
 
 func (w *TXCWallet) TxHistory(*asset.TxHistoryRequest) (*asset.TxHistoryResponse, error) {
-	return nil, nil
+	return &asset.TxHistoryResponse{Txs: w.txHistory}, nil
 }
 func (w *TXCWallet) WalletTransaction(ctx context.Context, txID string) (*asset.WalletTransaction, error) {
-	return nil, nil
+	return w.walletTx, nil
 }
 
 func (w *TXCWallet) PendingTransactions(ctx context.Context) []*asset.WalletTransaction {
@@ -1425,6 +1511,9 @@ func (c *tCrypter) Close() {}
 
 var tAssetID uint32
 
+// tRefundKitPath keeps the test rig's refund kit out of the package directory.
+var tRefundKitPath string
+
 func randomAsset() *msgjson.Asset {
 	tAssetID++
 	return &msgjson.Asset{
@@ -1495,7 +1584,7 @@ func newTestRig() *testRig {
 		shutdown: shutdown,
 		core: &Core{
 			ctx:      ctx,
-			cfg:      &Config{},
+			cfg:      &Config{RefundKitPath: tRefundKitPath},
 			db:       tdb,
 			log:      tLogger,
 			latencyQ: queue,
@@ -1524,6 +1613,10 @@ func newTestRig() *testRig {
 			fiatRateSources:  make(map[string]*commonRateSource),
 			notes:            make(chan asset.WalletNotification, 128),
 			pokesCache:       newPokesCache(pokesCapacity),
+			webhooks:         newWebhookManager(tLogger, nil),
+			priceAlerts:      newPriceAlertManager(nil),
+			bridgeTrades:     newBridgeTradeManager(nil),
+			bondFeeRates:     make(map[uint32]*bondFeeRate),
 			requestedActions: make(map[string]*asset.ActionRequiredNote),
 		},
 		db:      tdb,
@@ -1673,9 +1766,17 @@ func TestMain(m *testing.M) {
 	tDexPriv, _ = secp256k1.GeneratePrivateKey()
 	tDexKey = tDexPriv.PubKey()
 
+	refundKitDir, err := os.MkdirTemp("", "refundkit")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error creating refund kit directory: %v\n", err)
+		os.Exit(1)
+	}
+	tRefundKitPath = filepath.Join(refundKitDir, "refundkit.bwrk")
+
 	doIt := func() int {
 		// Not counted as coverage, must test Archiver constructor explicitly.
 		defer shutdown()
+		defer os.RemoveAll(refundKitDir)
 		return m.Run()
 	}
 	os.Exit(doIt())
@@ -11372,6 +11473,7 @@ func TestUpdateBondOptions(t *testing.T) {
 	type acctState struct {
 		targetTier   uint64
 		maxBondedAmt uint64
+		bondAssetIDs []uint32
 	}
 
 	for _, tt := range []struct {
@@ -11459,11 +11561,47 @@ func TestUpdateBondOptions(t *testing.T) {
 			after:       acctState{},
 			expReserves: bondFeeBuffer,
 		},
+		{
+			name: "set bond asset list",
+			bal:  singlyBondedReserves,
+			form: BondOptionsForm{
+				Host:         acct.host,
+				TargetTier:   &targetTier,
+				BondAssetIDs: &[]uint32{bondAsset.ID},
+			},
+			after: acctState{
+				targetTier:   1,
+				maxBondedAmt: defaultMaxBondedAmt,
+				bondAssetIDs: []uint32{bondAsset.ID},
+			},
+			expReserves: singlyBondedReserves,
+		},
+		{
+			name: "unsupported asset in bond asset list",
+			bal:  singlyBondedReserves,
+			form: BondOptionsForm{
+				Host:         acct.host,
+				TargetTier:   &targetTier,
+				BondAssetIDs: &[]uint32{bondAsset.ID, wrongBondAssetID},
+			},
+			wantErr: true,
+		},
+		{
+			name: "duplicate asset in bond asset list",
+			bal:  singlyBondedReserves,
+			form: BondOptionsForm{
+				Host:         acct.host,
+				TargetTier:   &targetTier,
+				BondAssetIDs: &[]uint32{bondAsset.ID, bondAsset.ID},
+			},
+			wantErr: true,
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			before, after := tt.before, tt.after
 			acct.targetTier = before.targetTier
 			acct.maxBondedAmt = before.maxBondedAmt
+			acct.bondAssets = before.bondAssetIDs
 			tDcrWallet.bal = &asset.Balance{Available: tt.bal}
 
 			if tt.addOtherDC {
@@ -11491,6 +11629,9 @@ func TestUpdateBondOptions(t *testing.T) {
 			if acct.maxBondedAmt != after.maxBondedAmt {
 				t.Fatalf("Wrong maxBondedAmt. %d != %d", acct.maxBondedAmt, after.maxBondedAmt)
 			}
+			if !slices.Equal(acct.bondAssets, after.bondAssetIDs) {
+				t.Fatalf("Wrong bondAssets. %v != %v", acct.bondAssets, after.bondAssetIDs)
+			}
 			if tDcrWallet.reserves.Load() != tt.expReserves {
 				t.Fatalf("Wrong reserves. %d != %d", tDcrWallet.reserves.Load(), tt.expReserves)
 			}
@@ -0,0 +1,503 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+)
+
+// exportPageSize is the number of orders loaded from the database at a time
+// when exporting trades.
+const exportPageSize = 100
+
+// TradeExportFilter selects the matches to export with ExportTrades. The zero
+// value exports every trade match.
+type TradeExportFilter struct {
+	// From and To limit the matches to those with a match time (ms UNIX) in
+	// the range [From, To). A zero To means no upper bound.
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	// Hosts, Assets and Market filter the orders as with OrderFilter.
+	Hosts  []string `json:"hosts"`
+	Assets []uint32 `json:"assets"`
+	Market *struct {
+		Base  uint32 `json:"baseID"`
+		Quote uint32 `json:"quoteID"`
+	} `json:"market"`
+	// OrderID limits the export to a single order. The other filters are
+	// still applied.
+	OrderID dex.Bytes `json:"orderID"`
+}
+
+// TradeExportRecord describes a single trade match for accounting. Amounts are
+// in conventional units. Fees are in the fee asset of the swap or redeem
+// asset, which for tokens is the parent chain's asset. The swap, redeem and
+// funding fees are the order's total fees, divided between the order's
+// matches in proportion to the match quantity. The refund fees are looked up
+// in the wallet's transaction history, and are zero if the wallet is not
+// available or does not have the refund transaction.
+//
+// The fiat values use the fiat rates recorded when the order was placed. A zero
+// rate means no rate was recorded, as with orders placed before the rates were
+// recorded, and the fiat values that need it are zero.
+type TradeExportRecord struct {
+	Host        string    `json:"host"`
+	Market      string    `json:"market"`
+	BaseID      uint32    `json:"baseID"`
+	BaseSymbol  string    `json:"baseSymbol"`
+	QuoteID     uint32    `json:"quoteID"`
+	QuoteSymbol string    `json:"quoteSymbol"`
+	OrderID     dex.Bytes `json:"orderID"`
+	OrderType   string    `json:"orderType"`
+	MatchID     dex.Bytes `json:"matchID"`
+	// Side is "buy" or "sell", and Role is "maker" or "taker".
+	Side     string  `json:"side"`
+	Role     string  `json:"role"`
+	Status   string  `json:"status"`
+	Revoked  bool    `json:"revoked"`
+	Refunded bool    `json:"refunded"`
+	Rate     float64 `json:"rate"`
+	Qty      float64 `json:"qty"`
+	QuoteQty float64 `json:"quoteQty"`
+
+	SwapFees         float64 `json:"swapFees"`
+	SwapFeeSymbol    string  `json:"swapFeeSymbol"`
+	RedeemFees       float64 `json:"redeemFees"`
+	RedeemFeeSymbol  string  `json:"redeemFeeSymbol"`
+	FundingFees      float64 `json:"fundingFees"`
+	FundingFeeSymbol string  `json:"fundingFeeSymbol"`
+	RefundFees       float64 `json:"refundFees"`
+	RefundFeeSymbol  string  `json:"refundFeeSymbol"`
+
+	SwapTx          string `json:"swapTx,omitempty"`
+	CounterSwapTx   string `json:"counterSwapTx,omitempty"`
+	RedeemTx        string `json:"redeemTx,omitempty"`
+	CounterRedeemTx string `json:"counterRedeemTx,omitempty"`
+	RefundTx        string `json:"refundTx,omitempty"`
+
+	// The times (ms UNIX) of the match and of each swap step, according to
+	// the server. A zero time means the step did not happen.
+	MatchStamp         uint64 `json:"matchStamp"`
+	SwapStamp          uint64 `json:"swapStamp"`
+	CounterSwapStamp   uint64 `json:"counterSwapStamp"`
+	RedeemStamp        uint64 `json:"redeemStamp"`
+	CounterRedeemStamp uint64 `json:"counterRedeemStamp"`
+
+	BaseFiatRate  float64 `json:"baseFiatRate"`
+	QuoteFiatRate float64 `json:"quoteFiatRate"`
+	// FiatValue is the value of the base quantity, or the quote quantity if
+	// there is no base fiat rate.
+	FiatValue float64 `json:"fiatValue"`
+	// FeesFiatValue is the value of all fees with a fiat rate.
+	FeesFiatValue float64 `json:"feesFiatValue"`
+}
+
+// ExportTrades returns a record for each trade match that passes the filter,
+// sorted by match time. Cancel order matches are not included.
+func (c *Core) ExportTrades(filter *TradeExportFilter) ([]*TradeExportRecord, error) {
+	if filter.To > 0 && filter.To <= filter.From {
+		return nil, fmt.Errorf("invalid time range %d - %d", filter.From, filter.To)
+	}
+	var mkt *db.OrderFilterMarket
+	if filter.Market != nil {
+		mkt = &db.OrderFilterMarket{
+			Base:  filter.Market.Base,
+			Quote: filter.Market.Quote,
+		}
+	}
+	dbFilter := &db.OrderFilter{
+		N:      exportPageSize,
+		Hosts:  filter.Hosts,
+		Assets: filter.Assets,
+		Market: mkt,
+	}
+	refundFees := func(assetID uint32, coinID []byte) uint64 {
+		wallet, found := c.wallet(assetID)
+		if !found {
+			return 0
+		}
+		tx, err := wallet.WalletTransaction(c.ctx, hex.EncodeToString(coinID))
+		if err != nil || tx == nil {
+			c.log.Debugf("Refund transaction %s not found for %s export: %v", coinIDString(assetID, coinID), unbip(assetID), err)
+			return 0
+		}
+		return tx.Fees
+	}
+
+	var recs []*TradeExportRecord
+	exportOrder := func(mOrd *db.MetaOrder) error {
+		if !exportOrderPasses(mOrd, dbFilter) {
+			return nil
+		}
+		// Matches are never before the order, so skip orders placed after the
+		// end of the range.
+		if filter.To > 0 && uint64(mOrd.Order.Prefix().ServerTime.UnixMilli()) >= filter.To {
+			return nil
+		}
+		oid := mOrd.Order.ID()
+		matches, err := c.db.MatchesForOrder(oid, true)
+		if err != nil {
+			return fmt.Errorf("error loading matches for order %s: %w", oid, err)
+		}
+		ordRecs, err := tradeExportRecords(mOrd, matches, refundFees)
+		if err != nil {
+			return fmt.Errorf("error exporting order %s: %w", oid, err)
+		}
+		for _, r := range ordRecs {
+			if r.MatchStamp >= filter.From && (filter.To == 0 || r.MatchStamp < filter.To) {
+				recs = append(recs, r)
+			}
+		}
+		return nil
+	}
+
+	if len(filter.OrderID) > 0 {
+		oid, err := order.IDFromBytes(filter.OrderID)
+		if err != nil {
+			return nil, err
+		}
+		mOrd, err := c.db.Order(oid)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving order %s: %w", oid, err)
+		}
+		if err := exportOrder(mOrd); err != nil {
+			return nil, err
+		}
+	} else {
+		for {
+			ords, err := c.db.Orders(dbFilter)
+			if err != nil {
+				return nil, fmt.Errorf("Orders error: %w", err)
+			}
+			for _, mOrd := range ords {
+				if err := exportOrder(mOrd); err != nil {
+					return nil, err
+				}
+			}
+			if len(ords) < exportPageSize {
+				break
+			}
+			dbFilter.Offset = ords[len(ords)-1].Order.ID()
+		}
+	}
+
+	sort.SliceStable(recs, func(i, j int) bool {
+		return recs[i].MatchStamp < recs[j].MatchStamp
+	})
+	return recs, nil
+}
+
+// exportOrderPasses checks whether an order loaded by ID passes the host,
+// asset, and market filters, and is a trade order.
+func exportOrderPasses(mOrd *db.MetaOrder, filter *db.OrderFilter) bool {
+	if mOrd.Order.Type() == order.CancelOrderType {
+		return false
+	}
+	if len(filter.Hosts) > 0 && !slices.Contains(filter.Hosts, mOrd.MetaData.Host) {
+		return false
+	}
+	base, quote := mOrd.Order.Base(), mOrd.Order.Quote()
+	if len(filter.Assets) > 0 && !slices.Contains(filter.Assets, base) && !slices.Contains(filter.Assets, quote) {
+		return false
+	}
+	if filter.Market != nil && (filter.Market.Base != base || filter.Market.Quote != quote) {
+		return false
+	}
+	return true
+}
+
+// feeAsset is the asset ID that fees are paid in for the asset.
+func feeAsset(assetID uint32) uint32 {
+	if tkn := asset.TokenInfo(assetID); tkn != nil {
+		return tkn.ParentID
+	}
+	return assetID
+}
+
+// tradeExportRecords creates the export records for an order's trade matches,
+// valued with the fiat rates recorded with the order. refundFees returns the fees paid by a refund transaction, in atomic units of
+// the fee asset, or zero if they are not known.
+func tradeExportRecords(mOrd *db.MetaOrder, matches []*db.MetaMatch,
+	refundFees func(assetID uint32, coinID []byte) uint64) ([]*TradeExportRecord, error) {
+	ord, md := mOrd.Order, mOrd.MetaData
+	fiatRates := md.FiatRates // may be nil
+	trade := ord.Trade()
+	baseID, quoteID := ord.Base(), ord.Quote()
+	baseUI, err := asset.UnitInfo(baseID)
+	if err != nil {
+		return nil, err
+	}
+	quoteUI, err := asset.UnitInfo(quoteID)
+	if err != nil {
+		return nil, err
+	}
+	fromID, toID := quoteID, baseID
+	if trade.Sell {
+		fromID, toID = baseID, quoteID
+	}
+	fromFeeID, toFeeID := feeAsset(fromID), feeAsset(toID)
+	fromFeeUI, err := asset.UnitInfo(fromFeeID)
+	if err != nil {
+		return nil, err
+	}
+	toFeeUI, err := asset.UnitInfo(toFeeID)
+	if err != nil {
+		return nil, err
+	}
+	mktName, err := dex.MarketName(baseID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The fees are recorded for the order, so divide them between the matches
+	// that paid them.
+	trades := make([]*db.MetaMatch, 0, len(matches))
+	var totalQty, swapQty, redeemQty uint64
+	for _, m := range matches {
+		if m.Address == "" { // cancel order match
+			continue
+		}
+		trades = append(trades, m)
+		totalQty += m.Quantity
+		if ourSwap(m) != nil {
+			swapQty += m.Quantity
+		}
+		if ourRedeem(m) != nil {
+			redeemQty += m.Quantity
+		}
+	}
+	share := func(fees, qty, total uint64) uint64 {
+		if total == 0 {
+			return 0
+		}
+		return uint64(float64(fees) * float64(qty) / float64(total))
+	}
+
+	side := "buy"
+	if trade.Sell {
+		side = "sell"
+	}
+	recs := make([]*TradeExportRecord, 0, len(trades))
+	for _, m := range trades {
+		match := matchFromMetaMatch(ord, m)
+		proof := &m.MetaData.Proof
+		var swapFees, redeemFees uint64
+		if ourSwap(m) != nil {
+			swapFees = share(md.SwapFeesPaid, m.Quantity, swapQty)
+		}
+		if ourRedeem(m) != nil {
+			redeemFees = share(md.RedemptionFeesPaid, m.Quantity, redeemQty)
+		}
+		fundingFees := share(md.FundingFeesPaid, m.Quantity, totalQty)
+		var refundFee uint64
+		if len(proof.RefundCoin) > 0 {
+			refundFee = refundFees(fromID, proof.RefundCoin)
+		}
+
+		r := &TradeExportRecord{
+			Host:               md.Host,
+			Market:             mktName,
+			BaseID:             baseID,
+			BaseSymbol:         unbip(baseID),
+			QuoteID:            quoteID,
+			QuoteSymbol:        unbip(quoteID),
+			OrderID:            m.OrderID[:],
+			OrderType:          ord.Type().String(),
+			MatchID:            m.MatchID[:],
+			Side:               side,
+			Role:               strings.ToLower(m.Side.String()),
+			Status:             m.Status.String(),
+			Revoked:            proof.IsRevoked(),
+			Refunded:           len(proof.RefundCoin) > 0,
+			Rate:               calc.ConventionalRate(m.Rate, baseUI, quoteUI),
+			Qty:                float64(m.Quantity) / float64(baseUI.Conventional.ConversionFactor),
+			QuoteQty:           float64(calc.BaseToQuote(m.Rate, m.Quantity)) / float64(quoteUI.Conventional.ConversionFactor),
+			SwapFees:           float64(swapFees) / float64(fromFeeUI.Conventional.ConversionFactor),
+			SwapFeeSymbol:      unbip(fromFeeID),
+			RedeemFees:         float64(redeemFees) / float64(toFeeUI.Conventional.ConversionFactor),
+			RedeemFeeSymbol:    unbip(toFeeID),
+			FundingFees:        float64(fundingFees) / float64(fromFeeUI.Conventional.ConversionFactor),
+			FundingFeeSymbol:   unbip(fromFeeID),
+			RefundFees:         float64(refundFee) / float64(fromFeeUI.Conventional.ConversionFactor),
+			RefundFeeSymbol:    unbip(fromFeeID),
+			MatchStamp:         m.MetaData.Stamp,
+			SwapStamp:          proof.Auth.InitStamp,
+			CounterSwapStamp:   proof.Auth.AuditStamp,
+			RedeemStamp:        proof.Auth.RedeemStamp,
+			CounterRedeemStamp: proof.Auth.RedemptionStamp,
+			BaseFiatRate:       fiatRates[baseID],
+			QuoteFiatRate:      fiatRates[quoteID],
+		}
+		for _, c := range []struct {
+			coin *Coin
+			id   *string
+		}{
+			{match.Swap, &r.SwapTx},
+			{match.CounterSwap, &r.CounterSwapTx},
+			{match.Redeem, &r.RedeemTx},
+			{match.CounterRedeem, &r.CounterRedeemTx},
+			{match.Refund, &r.RefundTx},
+		} {
+			if c.coin != nil {
+				*c.id = c.coin.StringID
+			}
+		}
+		switch {
+		case r.BaseFiatRate > 0:
+			r.FiatValue = r.Qty * r.BaseFiatRate
+		case r.QuoteFiatRate > 0:
+			r.FiatValue = r.QuoteQty * r.QuoteFiatRate
+		}
+		r.FeesFiatValue = (r.SwapFees+r.FundingFees+r.RefundFees)*fiatRates[fromFeeID] + r.RedeemFees*fiatRates[toFeeID]
+		recs = append(recs, r)
+	}
+	return recs, nil
+}
+
+// ourSwap is the match's swap coin that we broadcast, if any.
+func ourSwap(m *db.MetaMatch) order.CoinID {
+	if m.Side == order.Maker {
+		return m.MetaData.Proof.MakerSwap
+	}
+	return m.MetaData.Proof.TakerSwap
+}
+
+// ourRedeem is the match's redeem coin that we broadcast, if any.
+func ourRedeem(m *db.MetaMatch) order.CoinID {
+	if m.Side == order.Maker {
+		return m.MetaData.Proof.MakerRedeem
+	}
+	return m.MetaData.Proof.TakerRedeem
+}
+
+// WriteTradesCSV writes the trade export records as CSV. Times are written
+// in RFC 3339 format in the local time zone.
+func WriteTradesCSV(w io.Writer, recs []*TradeExportRecord, useCRLF bool) error {
+	csvWriter := csv.NewWriter(w)
+	csvWriter.UseCRLF = useCRLF
+	err := csvWriter.Write([]string{
+		"Host",
+		"Market",
+		"Order ID",
+		"Order Type",
+		"Match ID",
+		"Side",
+		"Role",
+		"Status",
+		"Revoked",
+		"Refunded",
+		"Rate",
+		"Quantity",
+		"Quantity Asset",
+		"Quote Quantity",
+		"Quote Quantity Asset",
+		"Swap Fees",
+		"Swap Fees Asset",
+		"Redeem Fees",
+		"Redeem Fees Asset",
+		"Funding Fees",
+		"Funding Fees Asset",
+		"Refund Fees",
+		"Refund Fees Asset",
+		"Swap Tx",
+		"Counterparty Swap Tx",
+		"Redeem Tx",
+		"Counterparty Redeem Tx",
+		"Refund Tx",
+		"Match Time",
+		"Swap Time",
+		"Counterparty Swap Time",
+		"Redeem Time",
+		"Counterparty Redeem Time",
+		"Base Fiat Rate (USD)",
+		"Quote Fiat Rate (USD)",
+		"Fiat Value (USD)",
+		"Fees Fiat Value (USD)",
+	})
+	if err != nil {
+		return fmt.Errorf("error writing CSV: %w", err)
+	}
+	floatStr := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	// Zero fiat rates and values are unknown, so are left blank.
+	rateStr := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return floatStr(v)
+	}
+	fiatStr := func(v float64) string {
+		if v == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	timeStr := func(stamp uint64) string {
+		if stamp == 0 {
+			return ""
+		}
+		return time.UnixMilli(int64(stamp)).Local().Format(time.RFC3339Nano)
+	}
+	for _, r := range recs {
+		err = csvWriter.Write([]string{
+			r.Host,                         // Host
+			r.Market,                       // Market
+			r.OrderID.String(),             // Order ID
+			r.OrderType,                    // Order Type
+			r.MatchID.String(),             // Match ID
+			r.Side,                         // Side
+			r.Role,                         // Role
+			r.Status,                       // Status
+			strconv.FormatBool(r.Revoked),  // Revoked
+			strconv.FormatBool(r.Refunded), // Refunded
+			floatStr(r.Rate),               // Rate
+			floatStr(r.Qty),                // Quantity
+			r.BaseSymbol,                   // Quantity Asset
+			floatStr(r.QuoteQty),           // Quote Quantity
+			r.QuoteSymbol,                  // Quote Quantity Asset
+			floatStr(r.SwapFees),           // Swap Fees
+			r.SwapFeeSymbol,                // Swap Fees Asset
+			floatStr(r.RedeemFees),         // Redeem Fees
+			r.RedeemFeeSymbol,              // Redeem Fees Asset
+			floatStr(r.FundingFees),        // Funding Fees
+			r.FundingFeeSymbol,             // Funding Fees Asset
+			floatStr(r.RefundFees),         // Refund Fees
+			r.RefundFeeSymbol,              // Refund Fees Asset
+			r.SwapTx,                       // Swap Tx
+			r.CounterSwapTx,                // Counterparty Swap Tx
+			r.RedeemTx,                     // Redeem Tx
+			r.CounterRedeemTx,              // Counterparty Redeem Tx
+			r.RefundTx,                     // Refund Tx
+			timeStr(r.MatchStamp),          // Match Time
+			timeStr(r.SwapStamp),           // Swap Time
+			timeStr(r.CounterSwapStamp),    // Counterparty Swap Time
+			timeStr(r.RedeemStamp),         // Redeem Time
+			timeStr(r.CounterRedeemStamp),  // Counterparty Redeem Time
+			rateStr(r.BaseFiatRate),        // Base Fiat Rate
+			rateStr(r.QuoteFiatRate),       // Quote Fiat Rate
+			fiatStr(r.FiatValue),           // Fiat Value
+			fiatStr(r.FeesFiatValue),       // Fees Fiat Value
+		})
+		if err != nil {
+			return fmt.Errorf("error writing CSV: %w", err)
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
@@ -0,0 +1,168 @@
+package core
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+func tExportMatch(oid order.OrderID, side order.MatchSide, qty, rate, stamp uint64) *db.MetaMatch {
+	m := &db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID:  oid,
+			MatchID:  ordertest.RandomMatchID(),
+			Quantity: qty,
+			Rate:     rate,
+			Address:  ordertest.RandomAddress(),
+			Status:   order.MatchConfirmed,
+			Side:     side,
+		},
+		MetaData: &db.MatchMetaData{
+			DEX:   tDexHost,
+			Base:  tUTXOAssetA.ID,
+			Quote: tUTXOAssetB.ID,
+			Stamp: stamp,
+		},
+	}
+	m.MetaData.Proof.Auth.InitStamp = stamp + 1
+	m.MetaData.Proof.Auth.AuditStamp = stamp + 2
+	return m
+}
+
+func TestExportTrades(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	const rate = 1e6 // 0.01 BTC per DCR
+	_, dbOrder, _, _ := makeLimitOrder(rig.dc, true, 3e8, rate)
+	dbOrder.MetaData.Status = order.OrderStatusExecuted
+	dbOrder.MetaData.SwapFeesPaid = 3000
+	dbOrder.MetaData.RedemptionFeesPaid = 1000
+	// The rates recorded when the order was placed.
+	dbOrder.MetaData.FiatRates = map[uint32]float64{tUTXOAssetA.ID: 20, tUTXOAssetB.ID: 50_000}
+	oid := dbOrder.Order.ID()
+
+	stamp := uint64(dbOrder.Order.Prefix().ServerTime.UnixMilli())
+	// Swapped and redeemed.
+	m1 := tExportMatch(oid, order.Maker, 1e8, rate, stamp+1000)
+	m1.MetaData.Proof.MakerSwap = []byte{0x01}
+	m1.MetaData.Proof.TakerSwap = []byte{0x02}
+	m1.MetaData.Proof.MakerRedeem = []byte{0x03}
+	// Swapped and refunded.
+	m2 := tExportMatch(oid, order.Taker, 2e8, rate, stamp+2000)
+	m2.MetaData.Proof.TakerSwap = []byte{0x04}
+	m2.MetaData.Proof.RefundCoin = []byte{0x05}
+	m2.Status = order.MakerRedeemed
+	// Cancel order match.
+	cancelMatch := tExportMatch(oid, order.Taker, 1e8, rate, stamp+3000)
+	cancelMatch.Address = ""
+
+	dcrWallet, tDcrWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	tDcrWallet.walletTx = &asset.WalletTransaction{Fees: 500}
+
+	rig.db.allOrders = []*db.MetaOrder{dbOrder}
+	rig.db.orderOrders[oid] = dbOrder
+	rig.db.matchesByOrderID = map[order.OrderID][]*db.MetaMatch{
+		oid: {m2, cancelMatch, m1},
+	}
+
+	recs, err := tCore.ExportTrades(&TradeExportFilter{})
+	if err != nil {
+		t.Fatalf("ExportTrades error: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("expected 2 records, got %d", len(recs))
+	}
+	r1, r2 := recs[0], recs[1]
+	if !bytes.Equal(r1.MatchID, m1.MatchID[:]) {
+		t.Fatalf("records not sorted by match time")
+	}
+	if r1.Side != "sell" || r1.Role != "maker" || r1.Market != "dcr_btc" {
+		t.Fatalf("wrong side, role or market: %s %s %s", r1.Side, r1.Role, r1.Market)
+	}
+	if r1.Qty != 1 || r1.Rate != 0.01 || r1.QuoteQty != 0.01 {
+		t.Fatalf("wrong amounts: qty = %f, rate = %f, quote qty = %f", r1.Qty, r1.Rate, r1.QuoteQty)
+	}
+	// Swap fees are divided 1:2 between the matches. Only the first match
+	// redeemed.
+	if r1.SwapFees != 1000e-8 || r2.SwapFees != 2000e-8 || r1.RedeemFees != 1000e-8 || r2.RedeemFees != 0 {
+		t.Fatalf("wrong fees: swap %f, %f, redeem %f, %f", r1.SwapFees, r2.SwapFees, r1.RedeemFees, r2.RedeemFees)
+	}
+	if r1.SwapFeeSymbol != "dcr" || r1.RedeemFeeSymbol != "btc" {
+		t.Fatalf("wrong fee assets %s, %s", r1.SwapFeeSymbol, r1.RedeemFeeSymbol)
+	}
+	if r1.SwapTx == "" || r1.CounterSwapTx == "" || r1.RedeemTx == "" || r1.RefundTx != "" {
+		t.Fatalf("wrong tx IDs for redeemed match: %+v", r1)
+	}
+	if !r2.Refunded || r2.RefundTx == "" || r2.RedeemTx != "" {
+		t.Fatalf("wrong tx IDs for refunded match: %+v", r2)
+	}
+	if r1.RefundFees != 0 || r2.RefundFees != 500e-8 || r2.RefundFeeSymbol != "dcr" {
+		t.Fatalf("wrong refund fees: %f, %f %s", r1.RefundFees, r2.RefundFees, r2.RefundFeeSymbol)
+	}
+	if r1.SwapStamp != m1.MetaData.Stamp+1 || r1.CounterSwapStamp != m1.MetaData.Stamp+2 || r1.RedeemStamp != 0 {
+		t.Fatalf("wrong swap times")
+	}
+	if r1.BaseFiatRate != 20 || r1.QuoteFiatRate != 50_000 || r1.FiatValue != 20 {
+		t.Fatalf("wrong fiat values: %f, %f, %f", r1.BaseFiatRate, r1.QuoteFiatRate, r1.FiatValue)
+	}
+	if expFees := 1000e-8*20 + 1000e-8*50_000; math.Abs(r1.FeesFiatValue-expFees) > 1e-9 {
+		t.Fatalf("wrong fees fiat value %f, expected %f", r1.FeesFiatValue, expFees)
+	}
+
+	// Date range.
+	recs, err = tCore.ExportTrades(&TradeExportFilter{From: stamp + 1500})
+	if err != nil || len(recs) != 1 || !bytes.Equal(recs[0].MatchID, m2.MatchID[:]) {
+		t.Fatalf("wrong export from time: err = %v, %d records", err, len(recs))
+	}
+	recs, err = tCore.ExportTrades(&TradeExportFilter{To: stamp + 1500})
+	if err != nil || len(recs) != 1 || !bytes.Equal(recs[0].MatchID, m1.MatchID[:]) {
+		t.Fatalf("wrong export to time: err = %v, %d records", err, len(recs))
+	}
+	if _, err = tCore.ExportTrades(&TradeExportFilter{From: 2, To: 1}); err == nil {
+		t.Fatalf("no error for invalid range")
+	}
+
+	// Order filters.
+	recs, err = tCore.ExportTrades(&TradeExportFilter{OrderID: oid[:]})
+	if err != nil || len(recs) != 2 {
+		t.Fatalf("wrong order export: err = %v, %d records", err, len(recs))
+	}
+	recs, err = tCore.ExportTrades(&TradeExportFilter{OrderID: oid[:], Hosts: []string{"other.tld"}})
+	if err != nil || len(recs) != 0 {
+		t.Fatalf("wrong order export for other host: err = %v, %d records", err, len(recs))
+	}
+
+	var b bytes.Buffer
+	if err := WriteTradesCSV(&b, recs, false); err != nil {
+		t.Fatalf("WriteTradesCSV error: %v", err)
+	}
+	rows, err := csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV: %v", err)
+	}
+	if len(rows) != 1 {
+		t.Fatalf("expected header only, got %d rows", len(rows))
+	}
+
+	recs, _ = tCore.ExportTrades(&TradeExportFilter{})
+	b.Reset()
+	if err := WriteTradesCSV(&b, recs, false); err != nil {
+		t.Fatalf("WriteTradesCSV error: %v", err)
+	}
+	rows, err = csv.NewReader(&b).ReadAll()
+	if err != nil {
+		t.Fatalf("error reading CSV: %v", err)
+	}
+	if len(rows) != 3 || len(rows[1]) != len(rows[0]) {
+		t.Fatalf("wrong CSV rows: %v", rows)
+	}
+}
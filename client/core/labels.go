@@ -0,0 +1,216 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+)
+
+const (
+	// maxLabelLength is the maximum length of a transaction or address label.
+	maxLabelLength = 256
+	// maxContactNameLength is the maximum length of an address book name.
+	maxContactNameLength = 64
+)
+
+// SetLabel sets the label for a wallet transaction or address. An empty label
+// removes any existing label.
+func (c *Core) SetLabel(assetID uint32, kind db.LabelKind, id, label string) error {
+	if _, err := asset.UnitInfo(assetID); err != nil {
+		return fmt.Errorf("unknown asset %d", assetID)
+	}
+	id, label = strings.TrimSpace(id), strings.TrimSpace(label)
+	if id == "" {
+		return errors.New("no transaction ID or address specified")
+	}
+	if len(label) > maxLabelLength {
+		return fmt.Errorf("label is too long. %d > %d", len(label), maxLabelLength)
+	}
+	return c.db.SetLabel(&db.Label{
+		AssetID: assetID,
+		Kind:    kind,
+		ID:      id,
+		Label:   label,
+	})
+}
+
+// Labels returns the asset's transaction and address labels.
+func (c *Core) Labels(assetID uint32) ([]*db.Label, error) {
+	return c.db.Labels(assetID)
+}
+
+// AddContact adds a contact to the asset's address book, replacing any
+// existing contact with the same name. If the asset has a wallet, the address
+// is validated by the wallet.
+func (c *Core) AddContact(contact *db.Contact) error {
+	if _, err := asset.UnitInfo(contact.AssetID); err != nil {
+		return fmt.Errorf("unknown asset %d", contact.AssetID)
+	}
+	contact.Name = strings.TrimSpace(contact.Name)
+	contact.Address = strings.TrimSpace(contact.Address)
+	if contact.Name == "" {
+		return errors.New("no contact name specified")
+	}
+	if len(contact.Name) > maxContactNameLength {
+		return fmt.Errorf("contact name is too long. %d > %d", len(contact.Name), maxContactNameLength)
+	}
+	if contact.Address == "" {
+		return errors.New("no contact address specified")
+	}
+	if len(contact.Note) > maxLabelLength {
+		return fmt.Errorf("contact note is too long. %d > %d", len(contact.Note), maxLabelLength)
+	}
+	if wallet, found := c.wallet(contact.AssetID); found && !wallet.Wallet.ValidateAddress(contact.Address) {
+		return fmt.Errorf("invalid %s address %q", unbip(contact.AssetID), contact.Address)
+	}
+	return c.db.UpdateContact(contact)
+}
+
+// RemoveContact removes the named contact from the asset's address book.
+func (c *Core) RemoveContact(assetID uint32, name string) error {
+	return c.db.DeleteContact(assetID, strings.TrimSpace(name))
+}
+
+// Contacts returns the asset's address book, sorted by name.
+func (c *Core) Contacts(assetID uint32) ([]*db.Contact, error) {
+	return c.db.Contacts(assetID)
+}
+
+// ContactAddress returns the address of the named contact in the asset's
+// address book. Names are case-insensitive.
+func (c *Core) ContactAddress(assetID uint32, name string) (string, error) {
+	contacts, err := c.db.Contacts(assetID)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSpace(name)
+	for _, contact := range contacts {
+		if strings.EqualFold(contact.Name, name) {
+			return contact.Address, nil
+		}
+	}
+	return "", fmt.Errorf("no %s contact named %q", unbip(assetID), name)
+}
+
+// txLabeler applies the user's labels to wallet transactions.
+type txLabeler struct {
+	txLabels   map[string]string
+	addrLabels map[string]string
+}
+
+// newTxLabeler loads the asset's labels and address book. Address labels take
+// precedence over contact names.
+func (c *Core) newTxLabeler(assetID uint32) (*txLabeler, error) {
+	labels, err := c.db.Labels(assetID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading labels: %w", err)
+	}
+	contacts, err := c.db.Contacts(assetID)
+	if err != nil {
+		return nil, fmt.Errorf("error loading contacts: %w", err)
+	}
+	l := &txLabeler{
+		txLabels:   make(map[string]string),
+		addrLabels: make(map[string]string, len(contacts)),
+	}
+	for _, contact := range contacts {
+		l.addrLabels[contact.Address] = contact.Name
+	}
+	for _, label := range labels {
+		switch label.Kind {
+		case db.LabelTx:
+			l.txLabels[label.ID] = label.Label
+		case db.LabelAddress:
+			l.addrLabels[label.ID] = label.Label
+		}
+	}
+	return l, nil
+}
+
+// label returns a labeled copy of the transaction. The wallet's transaction is
+// not modified.
+func (l *txLabeler) label(tx *asset.WalletTransaction) *asset.WalletTransaction {
+	txLabel := l.txLabels[tx.ID]
+	var recipientLabel string
+	if tx.Recipient != nil {
+		recipientLabel = l.addrLabels[*tx.Recipient]
+	}
+	if txLabel == "" && recipientLabel == "" {
+		return tx
+	}
+	labeled := *tx
+	labeled.Label = txLabel
+	labeled.RecipientLabel = recipientLabel
+	return &labeled
+}
+
+// labelTxs returns a copy of the slice with the asset's labels applied to
+// the transactions. Errors loading the labels are logged, and the unlabeled
+// transactions are returned.
+func (c *Core) labelTxs(assetID uint32, txs []*asset.WalletTransaction) []*asset.WalletTransaction {
+	if len(txs) == 0 {
+		return txs
+	}
+	l, err := c.newTxLabeler(assetID)
+	if err != nil {
+		c.log.Errorf("Error labeling %s transactions: %v", unbip(assetID), err)
+		return txs
+	}
+	labeled := make([]*asset.WalletTransaction, len(txs))
+	for i, tx := range txs {
+		labeled[i] = l.label(tx)
+	}
+	return labeled
+}
+
+// SearchTxHistory returns up to n of the asset's wallet transactions, most
+// recent first, where the transaction ID, label, recipient address, or
+// recipient label contains the query, ignoring case. If n <= 0, all matching
+// transactions are returned.
+func (c *Core) SearchTxHistory(assetID uint32, query string, n int) ([]*asset.WalletTransaction, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, errors.New("no search query specified")
+	}
+	wallet, found := c.wallet(assetID)
+	if !found {
+		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+	}
+	res, err := wallet.TxHistory(&asset.TxHistoryRequest{Past: true})
+	if err != nil {
+		return nil, err
+	}
+	l, err := c.newTxLabeler(assetID)
+	if err != nil {
+		return nil, err
+	}
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), query)
+	}
+	matches := make([]*asset.WalletTransaction, 0)
+	for _, tx := range res.Txs {
+		tx = l.label(tx)
+		match := contains(tx.ID) || contains(tx.Label) || contains(tx.RecipientLabel) ||
+			(tx.Recipient != nil && contains(*tx.Recipient))
+		for _, r := range tx.BatchRecipients {
+			if match {
+				break
+			}
+			match = contains(r.Address) || contains(l.addrLabels[r.Address])
+		}
+		if !match {
+			continue
+		}
+		matches = append(matches, tx)
+		if n > 0 && len(matches) == n {
+			break
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,102 @@
+package core
+
+import (
+	"testing"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+)
+
+func TestTxLabels(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = wallet
+	tWallet.validAddr = true
+
+	landlord, alice, stranger := "addr1", "addr2", "addr3"
+	rentTx := &asset.WalletTransaction{ID: "tx1", Type: asset.Send, Recipient: &landlord}
+	aliceTx := &asset.WalletTransaction{ID: "tx2", Type: asset.Send, Recipient: &alice}
+	batchTx := &asset.WalletTransaction{ID: "tx3", Type: asset.Send, BatchRecipients: []*asset.BatchRecipient{
+		{Address: stranger, Value: 1}, {Address: alice, Value: 1},
+	}}
+	tWallet.txHistory = []*asset.WalletTransaction{rentTx, aliceTx, batchTx}
+
+	if err := tCore.SetLabel(tUTXOAssetA.ID, db.LabelTx, "tx1", "March rent"); err != nil {
+		t.Fatalf("SetLabel error: %v", err)
+	}
+	if err := tCore.SetLabel(tUTXOAssetA.ID, db.LabelAddress, landlord, "Landlord"); err != nil {
+		t.Fatalf("SetLabel error: %v", err)
+	}
+	if err := tCore.SetLabel(tUTXOAssetA.ID, db.LabelTx, " ", "x"); err == nil {
+		t.Fatalf("no error for empty ID")
+	}
+	if err := tCore.SetLabel(12345, db.LabelTx, "tx1", "x"); err == nil {
+		t.Fatalf("no error for unknown asset")
+	}
+
+	if err := tCore.AddContact(&db.Contact{AssetID: tUTXOAssetA.ID, Name: " Alice ", Address: alice}); err != nil {
+		t.Fatalf("AddContact error: %v", err)
+	}
+	if err := tCore.AddContact(&db.Contact{AssetID: tUTXOAssetA.ID, Address: alice}); err == nil {
+		t.Fatalf("no error for contact without name")
+	}
+	tWallet.validAddr = false
+	if err := tCore.AddContact(&db.Contact{AssetID: tUTXOAssetA.ID, Name: "Bob", Address: "bad"}); err == nil {
+		t.Fatalf("no error for invalid address")
+	}
+
+	addr, err := tCore.ContactAddress(tUTXOAssetA.ID, "alice")
+	if err != nil {
+		t.Fatalf("ContactAddress error: %v", err)
+	}
+	if addr != alice {
+		t.Fatalf("wrong contact address %s", addr)
+	}
+	if _, err := tCore.ContactAddress(tUTXOAssetA.ID, "bob"); err == nil {
+		t.Fatalf("no error for unknown contact")
+	}
+
+	res, err := tCore.TxHistory(tUTXOAssetA.ID, &asset.TxHistoryRequest{})
+	if err != nil {
+		t.Fatalf("TxHistory error: %v", err)
+	}
+	if tx := res.Txs[0]; tx.Label != "March rent" || tx.RecipientLabel != "Landlord" {
+		t.Fatalf("wrong labels for first tx, %q, %q", tx.Label, tx.RecipientLabel)
+	}
+	if tx := res.Txs[1]; tx.Label != "" || tx.RecipientLabel != "Alice" {
+		t.Fatalf("wrong labels for second tx, %q, %q", tx.Label, tx.RecipientLabel)
+	}
+	if rentTx.Label != "" {
+		t.Fatalf("wallet's transaction was modified")
+	}
+
+	for query, expIDs := range map[string][]string{
+		"rent":     {"tx1"},
+		"LANDLORD": {"tx1"},
+		"alice":    {"tx2", "tx3"},
+		"addr3":    {"tx3"},
+		"tx":       {"tx1", "tx2", "tx3"},
+		"nothing":  {},
+	} {
+		txs, err := tCore.SearchTxHistory(tUTXOAssetA.ID, query, 0)
+		if err != nil {
+			t.Fatalf("%s: SearchTxHistory error: %v", query, err)
+		}
+		if len(txs) != len(expIDs) {
+			t.Fatalf("%s: expected %d results, got %d", query, len(expIDs), len(txs))
+		}
+		for i, tx := range txs {
+			if tx.ID != expIDs[i] {
+				t.Fatalf("%s: wrong tx %s at index %d", query, tx.ID, i)
+			}
+		}
+	}
+	if txs, _ := tCore.SearchTxHistory(tUTXOAssetA.ID, "tx", 2); len(txs) != 2 {
+		t.Fatalf("search results not limited")
+	}
+	if _, err := tCore.SearchTxHistory(tUTXOAssetA.ID, "", 0); err == nil {
+		t.Fatalf("no error for empty query")
+	}
+}
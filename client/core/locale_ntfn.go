@@ -57,6 +57,10 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Wallet connection issue"},
 		template: intl.Translation{T: "Unable to communicate with %v wallet! Reason: %v", Notes: "args: [asset name, error message]"},
 	},
+	TopicWalletWarning: {
+		subject:  intl.Translation{T: "Wallet warning"},
+		template: intl.Translation{T: "%v wallet: %v", Notes: "args: [asset name, warning message]"},
+	},
 	TopicWalletPeersWarning: {
 		subject:  intl.Translation{T: "Wallet network issue"},
 		template: intl.Translation{T: "%v wallet has no network peers!", Notes: "args: [asset name]"},
@@ -73,6 +77,10 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "Send successful"},
 		template: intl.Translation{Version: 1, T: "Sending %s %s to %s has completed successfully. Tx ID = %s", Notes: "args: [value string, ticker, destination address, coin ID]"},
 	},
+	TopicBatchSendSuccess: {
+		subject:  intl.Translation{T: "Batch send successful"},
+		template: intl.Translation{T: "Sending %s %s to %d recipients has completed successfully. Tx IDs = %s", Notes: "args: [total value string, ticker, number of recipients, tx IDs]"},
+	},
 	TopicAsyncOrderFailure: {
 		subject:  intl.Translation{T: "In-Flight Order Error"},
 		template: intl.Translation{T: "In-Flight order with ID %v failed: %v", Notes: "args: order ID, error]"},
@@ -379,6 +387,18 @@ var originLocale = map[Topic]*translation{
 		subject:  intl.Translation{T: "DEX server status"},
 		template: intl.Translation{T: "DEX server %s has been enabled.", Notes: "args: [host]"},
 	},
+	TopicPriceAlertTriggered: {
+		subject:  intl.Translation{T: "Price alert"},
+		template: intl.Translation{T: "%s price is %s (alert: %s)", Notes: "args: [market or asset, price, alert condition]"},
+	},
+	TopicBridgeTradeComplete: {
+		subject:  intl.Translation{T: "Bridge trade complete"},
+		template: intl.Translation{T: "%s is complete.", Notes: "args: [bridge trade description]"},
+	},
+	TopicBridgeTradeFailed: {
+		subject:  intl.Translation{T: "Bridge trade failed"},
+		template: intl.Translation{T: "%s failed: %v", Notes: "args: [bridge trade description, error message]"},
+	},
 }
 
 var ptBR = map[Topic]*translation{
@@ -1,16 +1,54 @@
 package core
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/comms"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encrypt"
 	"decred.org/dcrdex/dex/keygen"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/tatanka/client/mesh"
 	"decred.org/dcrdex/tatanka/mj"
+	"decred.org/dcrdex/tatanka/tanka"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
+// MeshHost is the Exchange host under which the Tatanka Mesh markets, orders,
+// and matches are presented.
+const MeshHost = "mesh"
+
+const (
+	// meshBondLifetime is the lock time duration of a mesh bond if not
+	// specified.
+	meshBondLifetime = 30 * 24 * time.Hour
+	// meshBondVersion is the bond version validated by Tatanka nodes.
+	meshBondVersion = 0
+	// meshLotSizeOption is the order option under which the lot size of a
+	// mesh order is stored, since the lot size is part of the mesh order ID.
+	meshLotSizeOption = "meshlotsize"
+)
+
+// meshBondTick is how often the confirmations of a new mesh bond are checked
+// before it is posted.
+var meshBondTick = time.Second * 10
+
 func (c *Core) handleMeshNotification(noteI any) {
 	switch n := noteI.(type) {
 	case *mj.Broadcast:
 		c.handleMeshBroadcast(n)
+	case *mesh.SwapUpdate:
+		c.handleMeshSwapUpdate(n)
 	}
 }
 
@@ -32,15 +70,31 @@ func (c *Core) handleMeshBroadcast(bcast *mj.Broadcast) {
 	// c.meshEmit(bcast)
 }
 
-func (c *Core) coreMesh() *Mesh {
+// connectedMesh returns the Mesh if it is connected, else nil.
+func (c *Core) connectedMesh() *mesh.Mesh {
 	c.meshMtx.RLock()
 	mesh, meshCM := c.mesh, c.meshCM
 	c.meshMtx.RUnlock()
 	if mesh == nil || !meshCM.On() {
 		return nil
 	}
-	return &Mesh{}
+	return mesh
+}
 
+func (c *Core) coreMesh() *Mesh {
+	m := c.connectedMesh()
+	if m == nil {
+		return nil
+	}
+	mkts := m.Markets()
+	cm := &Mesh{Markets: make(map[string]*MeshMarket, len(mkts))}
+	for _, mkt := range mkts {
+		cm.Markets[marketName(mkt.BaseID, mkt.QuoteID)] = &MeshMarket{
+			BaseID:  mkt.BaseID,
+			QuoteID: mkt.QuoteID,
+		}
+	}
+	return cm
 }
 
 func (c *Core) connectMesh() {
@@ -72,6 +126,917 @@ func (c *Core) connectMesh() {
 		c.log.Error("error subscribing to mesh fiat rates: %v", err)
 		return
 	}
+
+	c.subscribeMeshMarkets(mesh)
+	c.resumeMeshOrders(mesh)
+	c.resumeMeshBonds(mesh)
+}
+
+// subscribeMeshMarkets subscribes to the mesh markets that are also listed by
+// our exchanges and for which we have both wallets.
+func (c *Core) subscribeMeshMarkets(m *mesh.Mesh) {
+	for _, dc := range c.dexConnections() {
+		cfg := dc.config()
+		if cfg == nil {
+			continue
+		}
+		for _, mkt := range cfg.Markets {
+			_, baseOK := c.wallet(mkt.Base)
+			_, quoteOK := c.wallet(mkt.Quote)
+			if !baseOK || !quoteOK {
+				continue
+			}
+			if err := m.SubscribeMarket(mkt.Base, mkt.Quote); err != nil {
+				c.log.Errorf("Error subscribing to mesh market %s: %v", mkt.Name, err)
+			}
+		}
+	}
+}
+
+// resumeMeshOrders places our booked mesh orders from a previous session
+// again. The wallets must be unlocked for any new matches to be swapped.
+func (c *Core) resumeMeshOrders(m *mesh.Mesh) {
+	ords, err := c.db.ActiveDEXOrders(MeshHost)
+	if err != nil {
+		c.log.Errorf("Error loading active mesh orders: %v", err)
+		return
+	}
+	for _, mOrd := range ords {
+		if mOrd.MetaData.Status != order.OrderStatusBooked {
+			continue
+		}
+		ord, err := meshOrder(m.ID(), mOrd)
+		if err != nil {
+			c.log.Errorf("Mesh order %s not resumed: %v", mOrd.Order.ID(), err)
+			continue
+		}
+		lo := mOrd.Order.(*order.LimitOrder)
+		if err := c.addMeshWallets(nil, lo.BaseAsset, lo.QuoteAsset, m); err != nil {
+			c.log.Warnf("Mesh order %s not resumed: %v", lo.ID(), err)
+			continue
+		}
+		if err := m.SubscribeMarket(lo.BaseAsset, lo.QuoteAsset); err != nil {
+			c.log.Errorf("Error subscribing to mesh market for order %s: %v", lo.ID(), err)
+			continue
+		}
+		c.meshMtx.Lock()
+		c.meshOrders[ord.ID()] = lo.ID()
+		c.meshMtx.Unlock()
+		if err := m.ResumeOrder(ord, lo.Filled()); err != nil {
+			c.log.Errorf("Error resuming mesh order %s: %v", lo.ID(), err)
+		}
+	}
+}
+
+// meshOrder is our mesh order for the stored limit order. The order's nonce is
+// taken from the limit order's ID, and the lot size from the order options, so
+// the mesh order ID can be recreated from the stored order.
+func meshOrder(peerID tanka.PeerID, mOrd *db.MetaOrder) (*tanka.Order, error) {
+	lo, ok := mOrd.Order.(*order.LimitOrder)
+	if !ok {
+		return nil, fmt.Errorf("mesh order is a %s order, not a limit order", mOrd.Order.Type())
+	}
+	lotSize, err := strconv.ParseUint(mOrd.MetaData.Options[meshLotSizeOption], 10, 64)
+	if err != nil || lotSize == 0 {
+		return nil, errors.New("unknown lot size")
+	}
+	oid := lo.ID()
+	return &tanka.Order{
+		From:    peerID,
+		BaseID:  lo.BaseAsset,
+		QuoteID: lo.QuoteAsset,
+		Sell:    lo.Sell,
+		Qty:     lo.Quantity,
+		Rate:    lo.Rate,
+		LotSize: lotSize,
+		Nonce:   binary.BigEndian.Uint64(oid[:8]),
+		Stamp:   lo.ServerTime,
+	}, nil
+}
+
+// meshOrderID finds the ID of our stored order for the mesh order ID.
+func (c *Core) meshOrderID(m *mesh.Mesh, id tanka.ID40) (order.OrderID, bool) {
+	c.meshMtx.RLock()
+	oid, found := c.meshOrders[id]
+	c.meshMtx.RUnlock()
+	if found {
+		return oid, true
+	}
+	ords, err := c.db.ActiveDEXOrders(MeshHost)
+	if err != nil {
+		c.log.Errorf("Error loading active mesh orders: %v", err)
+		return oid, false
+	}
+	c.meshMtx.Lock()
+	defer c.meshMtx.Unlock()
+	for _, mOrd := range ords {
+		if ord, err := meshOrder(m.ID(), mOrd); err == nil {
+			c.meshOrders[ord.ID()] = mOrd.Order.ID()
+		}
+	}
+	oid, found = c.meshOrders[id]
+	return oid, found
+}
+
+// meshExchange is the Exchange for the mesh, with our subscribed markets.
+func (c *Core) meshExchange() *Exchange {
+	m := c.connectedMesh()
+	if m == nil {
+		return nil
+	}
+	peerID := m.ID()
+	xc := &Exchange{
+		Host:             MeshHost,
+		AcctID:           account.NewID(peerID[:]).String(),
+		Markets:          make(map[string]*Market),
+		Assets:           make(map[uint32]*dex.Asset),
+		BondAssets:       make(map[string]*BondAsset),
+		ConnectionStatus: comms.Connected,
+	}
+	if bonds, err := m.ActiveBonds(); err != nil {
+		c.log.Errorf("Error loading mesh bonds: %v", err)
+	} else {
+		for _, b := range bonds {
+			if time.Now().Before(b.Expiration) {
+				xc.Auth.LiveStrength += int64(b.Strength)
+			}
+		}
+		xc.Auth.EffectiveTier = xc.Auth.LiveStrength
+	}
+
+	ords := make(map[string][]*Order)
+	if mOrds, err := c.db.ActiveDEXOrders(MeshHost); err != nil {
+		c.log.Errorf("Error loading active mesh orders: %v", err)
+	} else {
+		for _, mOrd := range mOrds {
+			corder, err := c.coreOrderFromMetaOrder(mOrd)
+			if err != nil {
+				c.log.Errorf("Error loading mesh order %s: %v", mOrd.Order.ID(), err)
+				continue
+			}
+			ords[corder.MarketID] = append(ords[corder.MarketID], corder)
+		}
+	}
+
+	addAsset := func(assetID uint32) (uint64, bool) {
+		if a, found := xc.Assets[assetID]; found {
+			return a.UnitInfo.Conventional.ConversionFactor, true
+		}
+		ui, err := asset.UnitInfo(assetID)
+		if err != nil {
+			return 0, false
+		}
+		xc.Assets[assetID] = &dex.Asset{
+			ID:       assetID,
+			Symbol:   unbip(assetID),
+			UnitInfo: ui,
+		}
+		return ui.Conventional.ConversionFactor, true
+	}
+	for _, p := range m.Markets() {
+		bconv, baseOK := addAsset(p.BaseID)
+		qconv, quoteOK := addAsset(p.QuoteID)
+		if !baseOK || !quoteOK {
+			continue
+		}
+		mktName := marketName(p.BaseID, p.QuoteID)
+		xc.Markets[mktName] = &Market{
+			Name:        mktName,
+			BaseID:      p.BaseID,
+			BaseSymbol:  unbip(p.BaseID),
+			QuoteID:     p.QuoteID,
+			QuoteSymbol: unbip(p.QuoteID),
+			// Mesh orders set their own lot sizes.
+			LotSize:    1,
+			ParcelSize: 1,
+			RateStep:   1,
+			Orders:     ords[mktName],
+			AtomToConv: float64(bconv) / float64(qconv),
+		}
+	}
+	return xc
+}
+
+// meshBook is the order book for the mesh market, subscribing to the market if
+// necessary.
+func (c *Core) meshBook(base, quote uint32) (*OrderBook, error) {
+	m := c.connectedMesh()
+	if m == nil {
+		return nil, errors.New("not connected to the mesh")
+	}
+	if err := m.SubscribeMarket(base, quote); err != nil {
+		return nil, fmt.Errorf("error subscribing to mesh market: %w", err)
+	}
+	ords, err := m.Book(base, quote)
+	if err != nil {
+		return nil, err
+	}
+	bui, err := asset.UnitInfo(base)
+	if err != nil {
+		return nil, err
+	}
+	qui, err := asset.UnitInfo(quote)
+	if err != nil {
+		return nil, err
+	}
+	book := new(OrderBook)
+	for _, ord := range ords {
+		id := ord.ID()
+		mo := &MiniOrder{
+			Qty:       float64(ord.Qty) / float64(bui.Conventional.ConversionFactor),
+			QtyAtomic: ord.Qty,
+			Rate:      calc.ConventionalRate(ord.Rate, bui, qui),
+			MsgRate:   ord.Rate,
+			Sell:      ord.Sell,
+			Token:     token(id[32:]),
+		}
+		if ord.Sell {
+			book.Sells = append(book.Sells, mo)
+		} else {
+			book.Buys = append(book.Buys, mo)
+		}
+	}
+	return book, nil
+}
+
+// addMeshWallets connects and unlocks the wallets for the market, and adds
+// them to the mesh for swaps. The crypter may be nil if the wallets are
+// already unlocked.
+func (c *Core) addMeshWallets(crypter encrypt.Crypter, base, quote uint32, m *mesh.Mesh) error {
+	for _, assetID := range []uint32{base, quote} {
+		w, found := c.wallet(assetID)
+		if !found {
+			return newError(missingWalletErr, "no wallet found for %s", unbip(assetID))
+		}
+		if crypter == nil {
+			if !w.connected() || !w.unlocked() {
+				return fmt.Errorf("%s wallet is not unlocked", unbip(assetID))
+			}
+		} else if err := c.connectAndUnlock(crypter, w); err != nil {
+			return fmt.Errorf("%s connectAndUnlock error: %w", unbip(assetID), err)
+		}
+		m.AddWallet(assetID, w.Wallet, 0)
+	}
+	return nil
+}
+
+// meshOrdersLocked is the amount of the asset reserved for the unmatched
+// quantities of our booked mesh orders.
+func (c *Core) meshOrdersLocked(assetID uint32) (uint64, error) {
+	ords, err := c.db.ActiveDEXOrders(MeshHost)
+	if err != nil {
+		return 0, err
+	}
+	var locked uint64
+	for _, mOrd := range ords {
+		lo, ok := mOrd.Order.(*order.LimitOrder)
+		if !ok || mOrd.MetaData.Status != order.OrderStatusBooked {
+			continue
+		}
+		if lo.Sell && lo.BaseAsset == assetID {
+			locked += lo.Remaining()
+		} else if !lo.Sell && lo.QuoteAsset == assetID {
+			locked += calc.BaseToQuote(lo.Rate, lo.Remaining())
+		}
+	}
+	return locked, nil
+}
+
+// tradeMesh places a standing limit order on a mesh market. The order is
+// stored with the MeshHost. The from-wallet must have the order's funds
+// available in addition to the funds reserved for our other booked mesh
+// orders, but coins are not locked until a match is negotiated.
+func (c *Core) tradeMesh(pw []byte, form *TradeForm) (*Order, error) {
+	m := c.connectedMesh()
+	if m == nil {
+		return nil, errors.New("not connected to the mesh")
+	}
+	if !form.IsLimit || form.TifNow {
+		return nil, newError(orderParamsErr, "only standing limit orders are supported on mesh markets")
+	}
+	if form.Rate == 0 {
+		return nil, newError(orderParamsErr, "zero-rate order not allowed")
+	}
+	if form.Qty == 0 {
+		return nil, newError(orderParamsErr, "zero quantity not allowed")
+	}
+
+	var crypter encrypt.Crypter
+	if len(pw) > 0 {
+		var err error
+		crypter, err = c.encryptionKey(pw)
+		if err != nil {
+			return nil, fmt.Errorf("Trade password error: %w", err)
+		}
+		defer crypter.Close()
+	}
+	if err := c.addMeshWallets(crypter, form.Base, form.Quote, m); err != nil {
+		return nil, err
+	}
+	if err := m.SubscribeMarket(form.Base, form.Quote); err != nil {
+		return nil, fmt.Errorf("error subscribing to mesh market: %w", err)
+	}
+	lotSize, err := m.LotSize(form.Base, form.Quote, form.Rate)
+	if err != nil {
+		return nil, newError(orderParamsErr, "unknown lot size for mesh market %s: %v", marketName(form.Base, form.Quote), err)
+	}
+	if form.Qty%lotSize != 0 {
+		return nil, newError(orderParamsErr, "order quantity %d is not a multiple of the mesh market lot size %d", form.Qty, lotSize)
+	}
+
+	fromID, sendQty := form.Quote, calc.BaseToQuote(form.Rate, form.Qty)
+	if form.Sell {
+		fromID, sendQty = form.Base, form.Qty
+	}
+	fromWallet, found := c.wallet(fromID)
+	if !found {
+		return nil, newError(missingWalletErr, "no wallet found for %s", unbip(fromID))
+	}
+
+	options := make(map[string]string, len(form.Options)+1)
+	for k, v := range form.Options {
+		options[k] = v
+	}
+	options[meshLotSizeOption] = strconv.FormatUint(lotSize, 10)
+
+	peerID := m.ID()
+	now := time.Now().Truncate(time.Millisecond)
+	lo := &order.LimitOrder{
+		P: order.Prefix{
+			AccountID:  account.NewID(peerID[:]),
+			BaseAsset:  form.Base,
+			QuoteAsset: form.Quote,
+			OrderType:  order.LimitOrderType,
+			ClientTime: now,
+			ServerTime: now,
+		},
+		T: order.Trade{
+			Sell:     form.Sell,
+			Quantity: form.Qty,
+		},
+		Rate:  form.Rate,
+		Force: order.StandingTiF,
+	}
+	dbOrder := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Status:    order.OrderStatusBooked,
+			Host:      MeshHost,
+			Options:   options,
+			FiatRates: c.orderFiatRates(lo),
+		},
+		Order: lo,
+	}
+	oid := lo.ID()
+	ord, err := meshOrder(peerID, dbOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// The balance check and the storing of the order are done under the
+	// meshTradeMtx so that concurrent orders can't reserve the same funds.
+	c.meshTradeMtx.Lock()
+	locked, err := c.meshOrdersLocked(fromID)
+	if err != nil {
+		c.meshTradeMtx.Unlock()
+		return nil, fmt.Errorf("error loading active mesh orders: %w", err)
+	}
+	bal, err := fromWallet.Balance()
+	if err != nil {
+		c.meshTradeMtx.Unlock()
+		return nil, newError(walletBalanceErr, "error getting %s balance: %v", unbip(fromID), err)
+	}
+	if bal.Available < locked+sendQty {
+		c.meshTradeMtx.Unlock()
+		return nil, newError(walletBalanceErr, "insufficient %s balance: %d available, %d reserved for other mesh orders, %d required",
+			unbip(fromID), bal.Available, locked, sendQty)
+	}
+	if err := c.db.UpdateOrder(dbOrder); err != nil {
+		c.meshTradeMtx.Unlock()
+		return nil, fmt.Errorf("db error storing mesh order: %w", err)
+	}
+	c.meshMtx.Lock()
+	c.meshOrders[ord.ID()] = oid
+	c.meshMtx.Unlock()
+	c.meshTradeMtx.Unlock()
+
+	if err := m.PlaceOrder(ord); err != nil {
+		if err := c.db.UpdateOrderStatus(oid, order.OrderStatusRevoked); err != nil {
+			c.log.Errorf("Error revoking failed mesh order %s: %v", oid, err)
+		}
+		return nil, fmt.Errorf("error placing mesh order: %w", err)
+	}
+
+	corder, err := c.Order(oid[:])
+	if err != nil {
+		return nil, err
+	}
+	bui, _ := asset.UnitInfo(form.Base)
+	qui, _ := asset.UnitInfo(form.Quote)
+	rateString := strconv.FormatFloat(calc.ConventionalRate(form.Rate, bui, qui), 'f', 8, 64)
+	rateString = strings.TrimRight(strings.TrimRight(rateString, "0"), ".")
+	topic := TopicBuyOrderPlaced
+	if form.Sell {
+		topic = TopicSellOrderPlaced
+	}
+	subject, details := c.formatDetails(topic, bui.ConventionalString(form.Qty), bui.Conventional.Unit, rateString, makeOrderToken(oid.String()))
+	c.notify(newOrderNote(topic, subject, details, db.Poke, corder))
+	return corder, nil
+}
+
+// cancelMeshOrder cancels our mesh order, if the order is a mesh order.
+func (c *Core) cancelMeshOrder(oid order.OrderID) (bool, error) {
+	mOrd, err := c.db.Order(oid)
+	if err != nil {
+		if errors.Is(err, db.ErrOrderNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("db error loading order %s: %w", oid, err)
+	}
+	if mOrd.MetaData.Host != MeshHost {
+		return false, nil
+	}
+	m := c.connectedMesh()
+	if m == nil {
+		return true, errors.New("not connected to the mesh")
+	}
+	if mOrd.MetaData.Status != order.OrderStatusBooked {
+		return true, fmt.Errorf("mesh order %s is not booked", oid)
+	}
+	ord, err := meshOrder(m.ID(), mOrd)
+	if err != nil {
+		return true, fmt.Errorf("mesh order %s: %w", oid, err)
+	}
+	if err := m.CancelOrder(ord.BaseID, ord.QuoteID, ord.ID()); err != nil {
+		return true, err
+	}
+	lo := mOrd.Order.(*order.LimitOrder)
+
+	c.meshTradeMtx.Lock()
+	defer c.meshTradeMtx.Unlock()
+	if err := c.db.UpdateOrderStatus(oid, order.OrderStatusCanceled); err != nil {
+		return true, fmt.Errorf("db error canceling mesh order: %w", err)
+	}
+	corder, err := c.Order(oid[:])
+	if err != nil {
+		return true, err
+	}
+	topic := TopicBuyOrderCanceled
+	if lo.Sell {
+		topic = TopicSellOrderCanceled
+	}
+	subject, details := c.formatDetails(topic, unbip(lo.BaseAsset), unbip(lo.QuoteAsset), MeshHost, makeOrderToken(oid.String()))
+	c.notify(newOrderNote(topic, subject, details, db.Poke, corder))
+	return true, nil
+}
+
+// meshMetaMatch translates the swap update to a match for our stored order.
+func meshMetaMatch(oid order.OrderID, u *mesh.SwapUpdate) *db.MetaMatch {
+	side := order.Taker
+	proof := db.MatchProof{
+		ContractData:    u.Contract,
+		CounterContract: u.CounterContract,
+		SecretHash:      u.SecretHash,
+		Secret:          u.Secret,
+		MakerSwap:       order.CoinID(u.CounterSwapCoinID),
+		TakerSwap:       order.CoinID(u.SwapCoinID),
+		TakerRedeem:     order.CoinID(u.RedeemCoinID),
+		RefundCoin:      order.CoinID(u.RefundCoinID),
+		SelfRevoked:     u.Revoked,
+	}
+	if u.Maker {
+		side = order.Maker
+		proof.MakerSwap, proof.TakerSwap = proof.TakerSwap, proof.MakerSwap
+		proof.MakerRedeem, proof.TakerRedeem = proof.TakerRedeem, nil
+	}
+	// An empty address signifies a cancel match, so the counterparty's peer
+	// ID stands in until their address is received.
+	addr := u.CounterAddr
+	if addr == "" {
+		addr = u.Counterparty.String()
+	}
+	return &db.MetaMatch{
+		UserMatch: &order.UserMatch{
+			OrderID:  oid,
+			MatchID:  order.MatchID(u.MatchID),
+			Quantity: u.Qty,
+			Rate:     u.Rate,
+			Address:  addr,
+			Status:   u.Status,
+			Side:     side,
+		},
+		MetaData: &db.MatchMetaData{
+			Proof:            proof,
+			DEX:              MeshHost,
+			Base:             u.BaseID,
+			Quote:            u.QuoteID,
+			Stamp:            uint64(u.Stamp.UnixMilli()),
+			CounterPartyAddr: u.CounterAddr,
+		},
+	}
+}
+
+// handleMeshSwapUpdate stores the match and the order's fill, and translates
+// the changes into order and match notifications.
+func (c *Core) handleMeshSwapUpdate(u *mesh.SwapUpdate) {
+	m := c.connectedMesh()
+	if m == nil {
+		return
+	}
+	c.meshTradeMtx.Lock()
+	defer c.meshTradeMtx.Unlock()
+
+	oid, found := c.meshOrderID(m, u.OrderID)
+	if !found {
+		c.log.Warnf("Received swap update for match %s for unknown mesh order %s", u.MatchID, u.OrderID)
+		return
+	}
+	mOrd, err := c.db.Order(oid)
+	if err != nil {
+		c.log.Errorf("Error loading mesh order %s: %v", oid, err)
+		return
+	}
+	lo, ok := mOrd.Order.(*order.LimitOrder)
+	if !ok {
+		c.log.Errorf("Mesh order %s is not a limit order", oid)
+		return
+	}
+	matches, err := c.db.MatchesForOrder(oid, true)
+	if err != nil {
+		c.log.Errorf("Error loading matches for mesh order %s: %v", oid, err)
+		return
+	}
+	mid := order.MatchID(u.MatchID)
+	var prev *db.MetaMatch
+	var filled uint64
+	for _, mm := range matches {
+		if mm.MatchID == mid {
+			prev = mm
+		}
+		filled += mm.Quantity
+	}
+	if prev == nil {
+		filled += u.Qty
+	}
+
+	match := meshMetaMatch(oid, u)
+	if err := c.db.UpdateMatch(match); err != nil {
+		c.log.Errorf("Error storing match %s for mesh order %s: %v", mid, oid, err)
+		return
+	}
+	lo.SetFill(filled)
+	if mOrd.MetaData.Status == order.OrderStatusBooked && filled >= lo.Quantity {
+		mOrd.MetaData.Status = order.OrderStatusExecuted
+	}
+	if err := c.db.UpdateOrder(mOrd); err != nil {
+		c.log.Errorf("Error updating mesh order %s: %v", oid, err)
+		return
+	}
+	corder, err := c.coreOrderFromMetaOrder(mOrd)
+	if err != nil {
+		c.log.Errorf("Error loading mesh order %s: %v", oid, err)
+		return
+	}
+
+	matchTopic := TopicConfirms
+	if prev == nil {
+		matchTopic = TopicNewMatch
+	} else if len(u.CounterContract) > 0 && len(prev.MetaData.Proof.CounterContract) == 0 {
+		matchTopic = TopicAudit
+	}
+	c.notify(&MatchNote{
+		Notification: db.NewNotification(NoteTypeMatch, matchTopic, "", "", db.Data),
+		OrderID:      oid.Bytes(),
+		Match:        matchFromMetaMatch(lo, match),
+		Host:         MeshHost,
+		MarketID:     marketName(lo.BaseAsset, lo.QuoteAsset),
+	})
+
+	fromID, toID := lo.QuoteAsset, lo.BaseAsset
+	sendQty, recvQty := calc.BaseToQuote(u.Rate, u.Qty), u.Qty
+	if lo.Sell {
+		fromID, toID = toID, fromID
+		sendQty, recvQty = recvQty, sendQty
+	}
+	fromUI, _ := asset.UnitInfo(fromID)
+	toUI, _ := asset.UnitInfo(toID)
+	ordToken := makeOrderToken(oid.String())
+	var prevProof db.MatchProof
+	if prev != nil {
+		prevProof = prev.MetaData.Proof
+	}
+	ourSwap, prevSwap := match.MetaData.Proof.TakerSwap, prevProof.TakerSwap
+	ourRedeem, prevRedeem := match.MetaData.Proof.TakerRedeem, prevProof.TakerRedeem
+	if u.Maker {
+		ourSwap, prevSwap = match.MetaData.Proof.MakerSwap, prevProof.MakerSwap
+		ourRedeem, prevRedeem = match.MetaData.Proof.MakerRedeem, prevProof.MakerRedeem
+	}
+
+	if prev == nil {
+		topic := TopicBuyMatchesMade
+		if lo.Sell {
+			topic = TopicSellMatchesMade
+		}
+		fillPct := 100 * float64(filled) / float64(lo.Quantity)
+		subject, details := c.formatDetails(topic, unbip(lo.BaseAsset), unbip(lo.QuoteAsset), fillPct, ordToken)
+		c.notify(newOrderNote(topic, subject, details, db.Poke, corder))
+	}
+	if len(ourSwap) > 0 && len(prevSwap) == 0 {
+		subject, details := c.formatDetails(TopicSwapsInitiated, fromUI.ConventionalString(sendQty), fromUI.Conventional.Unit, ordToken)
+		c.notify(newOrderNote(TopicSwapsInitiated, subject, details, db.Poke, corder))
+	}
+	if len(ourRedeem) > 0 && len(prevRedeem) == 0 {
+		subject, details := c.formatDetails(TopicMatchComplete, toUI.ConventionalString(recvQty), toUI.Conventional.Unit, ordToken)
+		c.notify(newOrderNote(TopicMatchComplete, subject, details, db.Poke, corder))
+	}
+	if len(u.RefundCoinID) > 0 && len(prevProof.RefundCoin) == 0 {
+		subject, details := c.formatDetails(TopicMatchesRefunded, fromUI.ConventionalString(sendQty), fromUI.Conventional.Unit, ordToken)
+		c.notify(newOrderNote(TopicMatchesRefunded, subject, details, db.WarningLevel, corder))
+	}
+	if u.Revoked && !prevProof.SelfRevoked {
+		subject, details := c.formatDetails(TopicMatchRevoked, token(mid[:]))
+		c.notify(newOrderNote(TopicMatchRevoked, subject, details, db.WarningLevel, corder))
+	}
+}
+
+// loadMeshAccount loads the mesh bonds that have not been refunded from the
+// stored mesh account. The bonds are refunded by rotateBonds once their lock
+// times pass.
+func (c *Core) loadMeshAccount(acctInfo *db.AccountInfo) {
+	acct := newDEXAccount(acctInfo, true)
+	for _, bond := range acctInfo.Bonds {
+		if !bond.Refunded {
+			acct.expiredBonds = append(acct.expiredBonds, bond)
+		}
+	}
+	c.meshMtx.Lock()
+	c.meshAcct = acct
+	c.meshMtx.Unlock()
+}
+
+// meshAccount is the account that stores our mesh bonds, created on first use.
+func (c *Core) meshAccount(peerID tanka.PeerID) (*dexAccount, error) {
+	c.meshMtx.Lock()
+	defer c.meshMtx.Unlock()
+	if c.meshAcct != nil {
+		return c.meshAcct, nil
+	}
+	// There is no server key for the mesh, so our mesh public key stands in
+	// for the DEX public key of the account.
+	pubKey, err := secp256k1.ParsePubKey(peerID[:])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing mesh public key: %w", err)
+	}
+	acctInfo := &db.AccountInfo{
+		Host:      MeshHost,
+		DEXPubKey: pubKey,
+	}
+	if err := c.db.CreateAccount(acctInfo); err != nil {
+		return nil, fmt.Errorf("error creating mesh account: %w", err)
+	}
+	c.meshAcct = newDEXAccount(acctInfo, true)
+	return c.meshAcct, nil
+}
+
+// refundMeshBonds refunds the mesh bonds with passed lock times. Mesh bonds are
+// never renewed. Only accepted bonds count toward our mesh tier, so there is
+// nothing to post in their place.
+func (c *Core) refundMeshBonds(ctx context.Context, now int64) {
+	c.meshMtx.RLock()
+	acct := c.meshAcct
+	c.meshMtx.RUnlock()
+	if acct == nil {
+		return
+	}
+	acct.authMtx.RLock()
+	bonds := make([]*db.Bond, len(acct.expiredBonds))
+	copy(bonds, acct.expiredBonds)
+	acct.authMtx.RUnlock()
+	if len(bonds) == 0 {
+		return
+	}
+	state := &dexAcctBondState{ExchangeAuth: ExchangeAuth{ExpiredBonds: bonds}}
+	refundedAssets, _, err := c.refundExpiredBonds(ctx, acct, &dexBondCfg{}, state, now)
+	if err != nil {
+		c.log.Errorf("Failed to refund mesh bonds: %v", err)
+		return
+	}
+	for assetID := range refundedAssets {
+		c.updateAssetBalance(assetID)
+	}
+}
+
+// postMeshBond broadcasts a bond committed to our mesh peer ID, and posts it
+// to the mesh once it is confirmed. The bond is stored with the MeshHost
+// account and refunded by rotateBonds after its lock time.
+func (c *Core) postMeshBond(form *PostBondForm) (*PostBondResult, error) {
+	m := c.connectedMesh()
+	if m == nil {
+		return nil, errors.New("not connected to the mesh")
+	}
+	if form.Bond == 0 {
+		return nil, newError(bondAmtErr, "zero bond amount not allowed")
+	}
+	bondAssetID := uint32(defaultBondAsset)
+	if form.Asset != nil {
+		bondAssetID = *form.Asset
+	}
+	wallet, err := c.connectedWallet(bondAssetID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect to %s wallet to post bond: %w", unbip(bondAssetID), err)
+	}
+	if _, ok := wallet.Wallet.(asset.Bonder); !ok {
+		return nil, fmt.Errorf("wallet %v is not an asset.Bonder", unbip(bondAssetID))
+	}
+	crypter, err := c.encryptionKey(form.AppPass)
+	if err != nil {
+		return nil, codedError(passwordErr, err)
+	}
+	defer crypter.Close()
+	if !wallet.unlocked() {
+		if err := wallet.Unlock(crypter); err != nil {
+			return nil, newError(walletAuthErr, "failed to unlock %s wallet: %v", unbip(bondAssetID), err)
+		}
+	}
+
+	lockTime := time.Now().Add(meshBondLifetime).Truncate(time.Second)
+	if form.LockTime > 0 {
+		lockTime = time.Unix(int64(form.LockTime), 0)
+	}
+	if lockDur := time.Until(lockTime); lockDur > lockTimeLimit {
+		return nil, newError(bondTimeErr, "excessive lock time (%v>%v)", lockDur, lockTimeLimit)
+	} else if lockDur <= 0 {
+		return nil, newError(bondTimeErr, "lock time %v is in the past", lockTime)
+	}
+
+	acct, err := c.meshAccount(m.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	bondKey, keyIndex, err := c.nextBondKey(bondAssetID)
+	if err != nil {
+		return nil, fmt.Errorf("bond key derivation failed: %v", err)
+	}
+	defer bondKey.Zero()
+
+	peerID := m.ID()
+	acctID := account.NewID(peerID[:])
+	bond, abandon, err := wallet.MakeBondTx(meshBondVersion, form.Bond, c.feeSuggestionAny(bondAssetID), lockTime, bondKey, acctID[:])
+	if err != nil {
+		return nil, codedError(bondPostErr, err)
+	}
+	bondCoinStr := coinIDString(bond.AssetID, bond.CoinID)
+	c.log.Infof("Broadcasting mesh bond %v (%s) with lock time %v, data = %x.\n\n"+
+		"BACKUP refund tx paying to current wallet: %x\n\n",
+		bondCoinStr, unbip(bond.AssetID), lockTime, bond.Data, bond.RedeemTx)
+	dbBond := &db.Bond{
+		Version:    bond.Version,
+		AssetID:    bond.AssetID,
+		CoinID:     bond.CoinID,
+		UnsignedTx: bond.UnsignedTx,
+		SignedTx:   bond.SignedTx,
+		Data:       bond.Data,
+		Amount:     form.Bond,
+		LockTime:   uint64(lockTime.Unix()),
+		KeyIndex:   keyIndex,
+		RefundTx:   bond.RedeemTx,
+		Strength:   1,
+	}
+	if err := c.db.AddBond(MeshHost, dbBond); err != nil {
+		abandon()
+		return nil, fmt.Errorf("failed to store mesh bond %v (%s): %w", bondCoinStr, unbip(bond.AssetID), err)
+	}
+	meshBond := &tanka.Bond{
+		PeerID:  peerID,
+		AssetID: bondAssetID,
+		CoinID:  bond.CoinID,
+		// Tatanka nodes do not advertise a bond increment, so each bond
+		// is a single tier.
+		Strength:   1,
+		Expiration: lockTime,
+		Maturation: time.Now(),
+	}
+	// Store the bond before broadcasting so that posting resumes if we
+	// restart before the mesh accepts it.
+	if err := m.AddPendingBond(meshBond); err != nil {
+		abandon()
+		c.dropMeshBond(dbBond)
+		return nil, codedError(bondPostErr, err)
+	}
+	if _, err := wallet.SendTransaction(bond.SignedTx); err != nil {
+		abandon()
+		c.dropMeshBond(dbBond)
+		if err := m.RemovePendingBond(meshBond); err != nil {
+			c.log.Errorf("Error removing pending mesh bond %s: %v", bondCoinStr, err)
+		}
+		return nil, codedError(bondPostErr, err)
+	}
+	acct.authMtx.Lock()
+	acct.expiredBonds = append(acct.expiredBonds, dbBond)
+	acct.authMtx.Unlock()
+	c.updateAssetBalance(bondAssetID)
+
+	c.startMeshBondSubmission(m, meshBond)
+
+	return &PostBondResult{BondID: bondCoinStr, ReqConfirms: 1}, nil
+}
+
+// dropMeshBond marks a stored mesh bond that was never broadcast as refunded,
+// so that no refund is attempted.
+func (c *Core) dropMeshBond(bond *db.Bond) {
+	if err := c.db.BondRefunded(MeshHost, bond.AssetID, bond.CoinID); err != nil {
+		c.log.Errorf("Error marking abandoned mesh bond %s refunded: %v", coinIDString(bond.AssetID, bond.CoinID), err)
+	}
+}
+
+// resumeMeshBonds resumes posting the bonds that were broadcast but not yet
+// accepted by the mesh.
+func (c *Core) resumeMeshBonds(m *mesh.Mesh) {
+	bonds, err := m.PendingBonds()
+	if err != nil {
+		c.log.Errorf("Error loading pending mesh bonds: %v", err)
+		return
+	}
+	for _, bond := range bonds {
+		c.startMeshBondSubmission(m, bond)
+	}
+}
+
+// startMeshBondSubmission starts submitMeshBond for the bond, unless it is
+// already running.
+func (c *Core) startMeshBondSubmission(m *mesh.Mesh, bond *tanka.Bond) {
+	id := bond.ID()
+	c.meshMtx.Lock()
+	if c.meshBondPosts[id] {
+		c.meshMtx.Unlock()
+		return
+	}
+	c.meshBondPosts[id] = true
+	c.meshMtx.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer func() {
+			c.meshMtx.Lock()
+			delete(c.meshBondPosts, id)
+			c.meshMtx.Unlock()
+		}()
+		c.submitMeshBond(m, bond)
+	}()
+}
+
+// submitMeshBond posts the bond to the mesh once it has confirmations. Posting
+// is retried until the mesh accepts the bond or the bond expires. Only
+// accepted bonds count toward our mesh tier.
+func (c *Core) submitMeshBond(m *mesh.Mesh, bond *tanka.Bond) {
+	coinIDStr := coinIDString(bond.AssetID, bond.CoinID)
+	tick := time.NewTicker(meshBondTick)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+		case <-c.ctx.Done():
+			return
+		}
+		if time.Now().After(bond.Expiration) {
+			c.log.Errorf("Mesh bond %s expired before it was accepted", coinIDStr)
+			if err := m.RemovePendingBond(bond); err != nil {
+				c.log.Errorf("Error removing pending mesh bond %s: %v", coinIDStr, err)
+			}
+			return
+		}
+		wallet, err := c.connectedWallet(bond.AssetID)
+		if err != nil {
+			c.log.Debugf("Unable to check confirmations of mesh bond %s: %v", coinIDStr, err)
+			continue
+		}
+		confs, err := wallet.RegFeeConfirmations(c.ctx, bond.CoinID)
+		if err != nil || confs == 0 {
+			continue
+		}
+		if err := m.PostBond(bond); err != nil {
+			c.log.Debugf("Mesh bond %s not yet accepted: %v", coinIDStr, err)
+			continue
+		}
+		c.log.Infof("Mesh bond %s accepted", coinIDStr)
+		if err := c.db.ConfirmBond(MeshHost, bond.AssetID, bond.CoinID); err != nil {
+			c.log.Errorf("Error marking mesh bond %s confirmed: %v", coinIDStr, err)
+		}
+		var tier int64
+		if xc := c.meshExchange(); xc != nil {
+			tier = xc.Auth.EffectiveTier
+		}
+		subject, details := c.formatDetails(TopicBondConfirmed, tier, tier)
+		c.notify(&BondPostNote{
+			Notification: db.NewNotification(NoteTypeBondPost, TopicBondConfirmed, subject, details, db.Success),
+			Asset:        &bond.AssetID,
+			BondedTier:   &tier,
+			CoinID:       &coinIDStr,
+			Dex:          MeshHost,
+		})
+		return
+	}
 }
 
 func deriveMeshPriv(seed []byte) (*secp256k1.PrivateKey, error) {
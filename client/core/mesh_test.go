@@ -0,0 +1,179 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/tatanka/client/mesh"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func TestMeshOrder(t *testing.T) {
+	var peerID tanka.PeerID
+	copy(peerID[:], encode.RandomBytes(33))
+	now := time.Now().Truncate(time.Millisecond)
+	lo := &order.LimitOrder{
+		P: order.Prefix{
+			AccountID:  account.NewID(peerID[:]),
+			BaseAsset:  tUTXOAssetA.ID,
+			QuoteAsset: tUTXOAssetB.ID,
+			OrderType:  order.LimitOrderType,
+			ClientTime: now,
+			ServerTime: now,
+		},
+		T:     order.Trade{Sell: true, Quantity: 3 * 1e8},
+		Rate:  5e6,
+		Force: order.StandingTiF,
+	}
+	mOrd := &db.MetaOrder{
+		MetaData: &db.OrderMetaData{
+			Host:    MeshHost,
+			Options: map[string]string{meshLotSizeOption: "1048576"},
+		},
+		Order: lo,
+	}
+	meshID := func() tanka.ID40 {
+		t.Helper()
+		ord, err := meshOrder(peerID, mOrd)
+		if err != nil {
+			t.Fatalf("meshOrder error: %v", err)
+		}
+		return ord.ID()
+	}
+	ord, err := meshOrder(peerID, mOrd)
+	if err != nil {
+		t.Fatalf("meshOrder error: %v", err)
+	}
+	if err := ord.Valid(); err != nil {
+		t.Fatalf("invalid mesh order: %v", err)
+	}
+	if ord.LotSize != 1<<20 {
+		t.Fatalf("wrong lot size %d", ord.LotSize)
+	}
+	// The ID must be reproducible from the stored order.
+	if meshID() != ord.ID() {
+		t.Fatal("mesh order ID not deterministic")
+	}
+	lo.Quantity += 1 << 20
+	if meshID() == ord.ID() {
+		t.Fatal("different orders have the same mesh order ID")
+	}
+
+	// An order without a known lot size is rejected.
+	delete(mOrd.MetaData.Options, meshLotSizeOption)
+	if _, err := meshOrder(peerID, mOrd); err == nil {
+		t.Fatal("no error for unknown lot size")
+	}
+}
+
+func TestCancelMeshOrderDBError(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	var oid order.OrderID
+	copy(oid[:], encode.RandomBytes(32))
+
+	// An unknown order is not a mesh order.
+	if found, err := rig.core.cancelMeshOrder(oid); found || err != nil {
+		t.Fatalf("unknown order: found = %t, err = %v", found, err)
+	}
+
+	// Other db errors are returned.
+	rig.db.orderErr = errors.New("test error")
+	if _, err := rig.core.cancelMeshOrder(oid); err == nil {
+		t.Fatal("no error for db error")
+	}
+	if err := rig.core.cancelOrder(oid); err == nil || !errors.Is(err, rig.db.orderErr) {
+		t.Fatalf("wrong cancelOrder error for db error: %v", err)
+	}
+}
+
+func TestMeshMetaMatch(t *testing.T) {
+	var oid order.OrderID
+	copy(oid[:], encode.RandomBytes(32))
+	ourSwap, theirSwap, redeem := encode.RandomBytes(36), encode.RandomBytes(36), encode.RandomBytes(36)
+	u := &mesh.SwapUpdate{
+		BaseID:            tUTXOAssetA.ID,
+		QuoteID:           tUTXOAssetB.ID,
+		Qty:               1e8,
+		Rate:              5e6,
+		Stamp:             time.Now(),
+		Status:            order.MatchComplete,
+		SwapCoinID:        ourSwap,
+		CounterSwapCoinID: theirSwap,
+		RedeemCoinID:      redeem,
+	}
+	copy(u.MatchID[:], encode.RandomBytes(32))
+	copy(u.Counterparty[:], encode.RandomBytes(33))
+
+	for _, maker := range []bool{true, false} {
+		u.Maker = maker
+		m := meshMetaMatch(oid, u)
+		if m.Address == "" {
+			t.Fatal("match without a counterparty address would be a cancel match")
+		}
+		if m.MetaData.DEX != MeshHost {
+			t.Fatalf("wrong host %q", m.MetaData.DEX)
+		}
+		proof := &m.MetaData.Proof
+		swap, counterSwap, ourRedeem := proof.TakerSwap, proof.MakerSwap, proof.TakerRedeem
+		if maker {
+			if m.Side != order.Maker {
+				t.Fatal("wrong side for maker")
+			}
+			swap, counterSwap, ourRedeem = proof.MakerSwap, proof.TakerSwap, proof.MakerRedeem
+		}
+		if !bytes.Equal(swap, ourSwap) || !bytes.Equal(counterSwap, theirSwap) || !bytes.Equal(ourRedeem, redeem) {
+			t.Fatalf("wrong coins for maker = %t", maker)
+		}
+		// Without an InitSig, a complete match must not be considered active.
+		if db.MatchIsActive(m.UserMatch, &m.MetaData.Proof) {
+			t.Fatal("complete mesh match is active")
+		}
+	}
+}
+
+func TestRefundMeshBonds(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	rig.core.Login(tPW)
+
+	wallet, tWallet := newTWallet(tUTXOAssetA.ID)
+	rig.core.wallets[tUTXOAssetA.ID] = wallet
+
+	now := time.Now().Unix()
+	newBond := func(lockTime int64, refunded bool) *db.Bond {
+		return &db.Bond{
+			AssetID:  tUTXOAssetA.ID,
+			CoinID:   encode.RandomBytes(36),
+			Amount:   1e8,
+			LockTime: uint64(lockTime),
+			Strength: 1,
+			Refunded: refunded,
+		}
+	}
+	refundable, locked := newBond(now-1, false), newBond(now+3600, false)
+	priv, _ := secp256k1.GeneratePrivateKey()
+	rig.core.loadMeshAccount(&db.AccountInfo{
+		Host:      MeshHost,
+		DEXPubKey: priv.PubKey(),
+		Bonds:     []*db.Bond{refundable, locked, newBond(now-1, true)},
+	})
+	acct := rig.core.meshAcct
+	if len(acct.expiredBonds) != 2 {
+		t.Fatalf("wanted 2 mesh bonds to refund, got %d", len(acct.expiredBonds))
+	}
+
+	tWallet.contractExpired = true
+	tWallet.refundBondCoin = &tCoin{}
+	rig.core.refundMeshBonds(rig.core.ctx, now)
+	if len(acct.expiredBonds) != 1 || acct.expiredBonds[0] != locked {
+		t.Fatalf("wrong mesh bonds after refund: %v", acct.expiredBonds)
+	}
+}
@@ -44,6 +44,8 @@ const (
 	NoteTypeReputation     = "reputation"
 	NoteTypeActionRequired = "actionrequired"
 	NoteTypeBridge         = "bridge"
+	NoteTypePriceAlert     = "pricealert"
+	NoteTypeBridgeTrade    = "bridgetrade"
 )
 
 var noteChanCounter uint64
@@ -91,6 +93,8 @@ func (c *Core) notify(n Notification) {
 
 	c.logNote(n)
 
+	c.webhooks.dispatch(n)
+
 	c.noteMtx.RLock()
 	for _, ch := range c.noteChans {
 		select {
@@ -245,6 +249,7 @@ const (
 	TopicWalletConnectionWarning Topic = "WalletConnectionWarning"
 	TopicWalletUnlockError       Topic = "WalletUnlockError"
 	TopicWalletCommsWarning      Topic = "WalletCommsWarning"
+	TopicWalletWarning           Topic = "WalletWarning"
 	TopicWalletPeersRestored     Topic = "WalletPeersRestored"
 )
 
@@ -344,8 +349,9 @@ type SendNote struct {
 }
 
 const (
-	TopicSendError   Topic = "SendError"
-	TopicSendSuccess Topic = "SendSuccess"
+	TopicSendError        Topic = "SendError"
+	TopicSendSuccess      Topic = "SendSuccess"
+	TopicBatchSendSuccess Topic = "BatchSendSuccess"
 )
 
 func newSendNote(topic Topic, subject, details string, severity db.Severity) *SendNote {
@@ -0,0 +1,472 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+)
+
+const (
+	// maxPriceAlertWindow is the longest look-back period allowed for a
+	// PriceAlertChange condition. Price history is kept in memory for this
+	// long.
+	maxPriceAlertWindow = 7 * 24 * time.Hour
+	// midGapSampleInterval limits how often mid-gap rates are added to the
+	// price history. Fiat rates are sampled at every refresh.
+	midGapSampleInterval = time.Minute
+	// priceAlertFeedInterval is how often the book subscriptions needed by
+	// mid-gap alerts are checked.
+	priceAlertFeedInterval = time.Minute
+)
+
+// PriceAlertForm is the information necessary to create a price alert.
+type PriceAlertForm struct {
+	Source     db.PriceAlertSource    `json:"source"`
+	Host       string                 `json:"host"`
+	BaseID     uint32                 `json:"baseID"`
+	QuoteID    uint32                 `json:"quoteID"`
+	AssetID    uint32                 `json:"assetID"`
+	Condition  db.PriceAlertCondition `json:"condition"`
+	Threshold  float64                `json:"threshold"`
+	WindowSecs uint64                 `json:"windowSecs"`
+	Rearm      bool                   `json:"rearm"`
+}
+
+// PriceAlertNote is a notification that a price alert has triggered.
+type PriceAlertNote struct {
+	db.Notification
+	Alert *db.PriceAlert `json:"alert"`
+	Price float64        `json:"price"`
+	// ChangePct is the percentage price change over the alert's window, for
+	// PriceAlertChange alerts.
+	ChangePct float64 `json:"changePct,omitempty"`
+}
+
+const TopicPriceAlertTriggered Topic = "PriceAlertTriggered"
+
+func newPriceAlertNote(subject, details string, alert *db.PriceAlert, price, changePct float64) *PriceAlertNote {
+	return &PriceAlertNote{
+		Notification: db.NewNotification(NoteTypePriceAlert, TopicPriceAlertTriggered, subject, details, db.Success),
+		Alert:        alert,
+		Price:        price,
+		ChangePct:    changePct,
+	}
+}
+
+type priceSample struct {
+	stamp time.Time
+	price float64
+}
+
+// priceAlertManager tracks the user's price alerts and the price history
+// needed to evaluate PriceAlertChange conditions.
+type priceAlertManager struct {
+	mtx     sync.Mutex
+	alerts  map[string]*db.PriceAlert
+	history map[string][]*priceSample
+	// feeds are the book feeds kept open for markets with mid-gap alerts.
+	feeds map[string]*priceAlertFeed
+}
+
+// priceAlertFeed is a book feed watched for mid-gap price alerts. Closing a
+// BookFeed does not close its update channel, so the quit channel is used to
+// stop the goroutine watching the feed.
+type priceAlertFeed struct {
+	BookFeed
+	quit chan struct{}
+}
+
+// close closes the book feed and stops the goroutine watching it.
+func (f *priceAlertFeed) close() {
+	f.Close()
+	close(f.quit)
+}
+
+func newPriceAlertManager(alerts []*db.PriceAlert) *priceAlertManager {
+	m := &priceAlertManager{
+		alerts:  make(map[string]*db.PriceAlert, len(alerts)),
+		history: make(map[string][]*priceSample),
+		feeds:   make(map[string]*priceAlertFeed),
+	}
+	for _, a := range alerts {
+		m.alerts[a.ID] = a
+	}
+	return m
+}
+
+// priceKey is the key for the alert's price history.
+func priceKey(a *db.PriceAlert) string {
+	if a.Source == db.PriceAlertFiat {
+		return fiatPriceKey(a.AssetID)
+	}
+	return midGapPriceKey(a.Host, a.BaseID, a.QuoteID)
+}
+
+func fiatPriceKey(assetID uint32) string {
+	return fmt.Sprintf("fiat:%d", assetID)
+}
+
+func midGapPriceKey(host string, base, quote uint32) string {
+	return fmt.Sprintf("midgap:%s:%s", host, marketName(base, quote))
+}
+
+// addSample adds a price to the history, trimming samples older than
+// maxPriceAlertWindow. If minInterval > 0, the sample is skipped if the last
+// sample is more recent than minInterval.
+func (m *priceAlertManager) addSample(key string, price float64, now time.Time, minInterval time.Duration) {
+	samples := m.history[key]
+	if n := len(samples); n > 0 && minInterval > 0 && now.Sub(samples[n-1].stamp) < minInterval {
+		return
+	}
+	cutoff := now.Add(-maxPriceAlertWindow)
+	var i int
+	for i < len(samples) && samples[i].stamp.Before(cutoff) {
+		i++
+	}
+	m.history[key] = append(samples[i:], &priceSample{stamp: now, price: price})
+}
+
+// referencePrice returns the most recent price that is at least window old.
+// If the history does not extend back that far, ok is false.
+func (m *priceAlertManager) referencePrice(key string, window time.Duration, now time.Time) (price float64, ok bool) {
+	target := now.Add(-window)
+	for _, s := range m.history[key] {
+		if s.stamp.After(target) {
+			break
+		}
+		price, ok = s.price, true
+	}
+	return
+}
+
+// priceAlertMet checks whether the alert's condition is met. ok is false if
+// the condition cannot yet be evaluated.
+func priceAlertMet(a *db.PriceAlert, price, refPrice float64, haveRef bool) (met, ok bool, changePct float64) {
+	switch a.Condition {
+	case db.PriceAlertAbove:
+		return price >= a.Threshold, true, 0
+	case db.PriceAlertBelow:
+		return price <= a.Threshold, true, 0
+	case db.PriceAlertChange:
+		if !haveRef || refPrice <= 0 {
+			return false, false, 0
+		}
+		changePct = (price - refPrice) / refPrice * 100
+		if a.Threshold < 0 {
+			return changePct <= a.Threshold, true, changePct
+		}
+		return changePct >= a.Threshold, true, changePct
+	}
+	return false, false, 0
+}
+
+// alertDescription describes the alert's subject and condition for
+// notifications.
+func alertDescription(a *db.PriceAlert) (subject, condition string) {
+	if a.Source == db.PriceAlertFiat {
+		subject = strings.ToUpper(unbip(a.AssetID)) + " fiat"
+	} else {
+		subject = fmt.Sprintf("%s-%s @ %s", strings.ToUpper(unbip(a.BaseID)), strings.ToUpper(unbip(a.QuoteID)), a.Host)
+	}
+	switch a.Condition {
+	case db.PriceAlertChange:
+		condition = fmt.Sprintf("%+.2f%% in %s", a.Threshold, time.Duration(a.WindowSecs)*time.Second)
+	default:
+		condition = fmt.Sprintf("%s %s", a.Condition, formatPrice(a.Threshold))
+	}
+	return
+}
+
+func formatPrice(p float64) string {
+	return strings.TrimRight(strings.TrimRight(fmt.Sprintf("%.8f", p), "0"), ".")
+}
+
+// evaluate checks the alerts matching the price key and returns the
+// notifications for any that triggered. Alerts that change state are returned
+// so they can be stored.
+func (m *priceAlertManager) evaluate(key string, price float64, now time.Time, minInterval time.Duration) (updated []*db.PriceAlert, triggered []*priceAlertTrigger) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.addSample(key, price, now, minInterval)
+	for id, a := range m.alerts {
+		if priceKey(a) != key || (a.Triggered && !a.Rearm) {
+			continue
+		}
+		refPrice, haveRef := m.referencePrice(key, time.Duration(a.WindowSecs)*time.Second, now)
+		met, ok, changePct := priceAlertMet(a, price, refPrice, haveRef)
+		if !ok {
+			continue
+		}
+		switch {
+		case met && !a.Triggered:
+			aCopy := *a
+			aCopy.Triggered = true
+			aCopy.TriggerStamp = uint64(now.UnixMilli())
+			m.alerts[id] = &aCopy
+			updated = append(updated, &aCopy)
+			triggered = append(triggered, &priceAlertTrigger{alert: &aCopy, price: price, changePct: changePct})
+		case !met && a.Triggered: // a.Rearm is true
+			aCopy := *a
+			aCopy.Triggered = false
+			m.alerts[id] = &aCopy
+			updated = append(updated, &aCopy)
+		}
+	}
+	return
+}
+
+type priceAlertTrigger struct {
+	alert     *db.PriceAlert
+	price     float64
+	changePct float64
+}
+
+// processPriceAlerts evaluates the alerts for the price, stores any updated
+// alerts, and sends notifications for triggered alerts.
+func (c *Core) processPriceAlerts(key string, price float64, minInterval time.Duration) {
+	updated, triggered := c.priceAlerts.evaluate(key, price, time.Now(), minInterval)
+	for _, a := range updated {
+		if err := c.db.UpdatePriceAlert(a); err != nil {
+			c.log.Errorf("Error storing price alert %s: %v", a.ID, err)
+		}
+	}
+	for _, t := range triggered {
+		subject, condition := alertDescription(t.alert)
+		priceStr := formatPrice(t.price)
+		if t.alert.Condition == db.PriceAlertChange {
+			priceStr = fmt.Sprintf("%s (%+.2f%%)", priceStr, t.changePct)
+		}
+		sub, details := c.formatDetails(TopicPriceAlertTriggered, subject, priceStr, condition)
+		c.notify(newPriceAlertNote(sub, details, t.alert, t.price, t.changePct))
+	}
+}
+
+// checkFiatPriceAlerts evaluates fiat price alerts against the latest fiat
+// rates.
+func (c *Core) checkFiatPriceAlerts(rates map[uint32]float64) {
+	for assetID, rate := range rates {
+		c.processPriceAlerts(fiatPriceKey(assetID), rate, 0)
+	}
+}
+
+// checkMidGapPriceAlerts evaluates mid-gap price alerts for the market.
+func (c *Core) checkMidGapPriceAlerts(host string, base, quote uint32) {
+	c.connMtx.RLock()
+	dc, found := c.conns[host]
+	c.connMtx.RUnlock()
+	if !found {
+		return
+	}
+	book := dc.bookie(marketName(base, quote))
+	if book == nil {
+		return
+	}
+	midGap, err := book.MidGap()
+	if err != nil {
+		return // empty book side
+	}
+	rate := calc.ConventionalRate(midGap, book.baseUnits, book.quoteUnits)
+	c.processPriceAlerts(midGapPriceKey(host, base, quote), rate, midGapSampleInterval)
+}
+
+// watchPriceAlertBooks keeps book subscriptions open for markets with mid-gap
+// price alerts, evaluating the alerts when the book is synced and at the end
+// of every epoch.
+func (c *Core) watchPriceAlertBooks(ctx context.Context) {
+	m := c.priceAlerts
+	var wg sync.WaitGroup
+	defer func() {
+		m.mtx.Lock()
+		for key, feed := range m.feeds {
+			feed.close()
+			delete(m.feeds, key)
+		}
+		m.mtx.Unlock()
+		wg.Wait()
+	}()
+
+	type market struct {
+		host        string
+		base, quote uint32
+	}
+
+	syncFeeds := func() {
+		m.mtx.Lock()
+		needed := make(map[string]*market)
+		for _, a := range m.alerts {
+			if a.Source != db.PriceAlertMidGap || (a.Triggered && !a.Rearm) {
+				continue
+			}
+			needed[midGapPriceKey(a.Host, a.BaseID, a.QuoteID)] = &market{a.Host, a.BaseID, a.QuoteID}
+		}
+		for key, feed := range m.feeds {
+			if needed[key] == nil {
+				feed.close()
+				delete(m.feeds, key)
+			}
+		}
+		var missing []*market
+		for key, mkt := range needed {
+			if m.feeds[key] == nil {
+				missing = append(missing, mkt)
+			}
+		}
+		m.mtx.Unlock()
+
+		for _, mkt := range missing {
+			_, bookFeed, err := c.SyncBook(mkt.host, mkt.base, mkt.quote)
+			if err != nil {
+				c.log.Debugf("Unable to sync %s book at %s for price alerts: %v", marketName(mkt.base, mkt.quote), mkt.host, err)
+				continue
+			}
+			feed := &priceAlertFeed{BookFeed: bookFeed, quit: make(chan struct{})}
+			key := midGapPriceKey(mkt.host, mkt.base, mkt.quote)
+			m.mtx.Lock()
+			m.feeds[key] = feed
+			m.mtx.Unlock()
+			wg.Add(1)
+			go func(mkt *market) {
+				defer wg.Done()
+				for {
+					select {
+					case u, ok := <-feed.Next():
+						if !ok { // book closed, e.g. disconnected
+							m.mtx.Lock()
+							if m.feeds[key] == feed {
+								delete(m.feeds, key)
+							}
+							m.mtx.Unlock()
+							return
+						}
+						switch u.Action {
+						case FreshBookAction, EpochMatchSummary:
+							c.checkMidGapPriceAlerts(mkt.host, mkt.base, mkt.quote)
+						}
+					case <-feed.quit:
+						return
+					case <-ctx.Done():
+						return
+					}
+				}
+			}(mkt)
+		}
+	}
+
+	ticker := time.NewTicker(priceAlertFeedInterval)
+	defer ticker.Stop()
+	for {
+		syncFeeds()
+		select {
+		case <-ticker.C:
+		case <-c.priceAlertsUpdated:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PriceAlerts returns the user's price alerts.
+func (c *Core) PriceAlerts() []*db.PriceAlert {
+	c.priceAlerts.mtx.Lock()
+	defer c.priceAlerts.mtx.Unlock()
+	alerts := make([]*db.PriceAlert, 0, len(c.priceAlerts.alerts))
+	for _, a := range c.priceAlerts.alerts {
+		aCopy := *a
+		alerts = append(alerts, &aCopy)
+	}
+	return alerts
+}
+
+// AddPriceAlert creates a new price alert. Mid-gap alerts keep the market's
+// order book synced while the alert is armed.
+func (c *Core) AddPriceAlert(form *PriceAlertForm) (*db.PriceAlert, error) {
+	a := &db.PriceAlert{
+		ID:         hex.EncodeToString(encode.RandomBytes(8)),
+		Source:     form.Source,
+		Condition:  form.Condition,
+		Threshold:  form.Threshold,
+		WindowSecs: form.WindowSecs,
+		Rearm:      form.Rearm,
+		Created:    uint64(time.Now().UnixMilli()),
+	}
+	switch form.Source {
+	case db.PriceAlertMidGap:
+		host, err := addrHost(form.Host)
+		if err != nil {
+			return nil, newError(addressParseErr, "error parsing address: %w", err)
+		}
+		c.connMtx.RLock()
+		dc, found := c.conns[host]
+		c.connMtx.RUnlock()
+		if !found {
+			return nil, fmt.Errorf("unknown DEX %s", host)
+		}
+		if dc.marketConfig(marketName(form.BaseID, form.QuoteID)) == nil {
+			return nil, fmt.Errorf("unknown market %s at %s", marketName(form.BaseID, form.QuoteID), host)
+		}
+		a.Host, a.BaseID, a.QuoteID = host, form.BaseID, form.QuoteID
+	case db.PriceAlertFiat:
+		if unbip(form.AssetID) == "" {
+			return nil, fmt.Errorf("unknown asset %d", form.AssetID)
+		}
+		a.AssetID = form.AssetID
+	default:
+		return nil, fmt.Errorf("unknown price alert source %q", form.Source)
+	}
+	switch form.Condition {
+	case db.PriceAlertAbove, db.PriceAlertBelow:
+		if form.Threshold <= 0 {
+			return nil, errors.New("price threshold must be positive")
+		}
+		a.WindowSecs = 0
+	case db.PriceAlertChange:
+		if form.Threshold == 0 {
+			return nil, errors.New("percent change threshold must be non-zero")
+		}
+		window := time.Duration(form.WindowSecs) * time.Second
+		if window <= 0 || window > maxPriceAlertWindow {
+			return nil, fmt.Errorf("change window must be between 1 second and %s", maxPriceAlertWindow)
+		}
+	default:
+		return nil, fmt.Errorf("unknown price alert condition %q", form.Condition)
+	}
+	if err := c.db.UpdatePriceAlert(a); err != nil {
+		return nil, fmt.Errorf("error storing price alert: %w", err)
+	}
+	c.priceAlerts.mtx.Lock()
+	c.priceAlerts.alerts[a.ID] = a
+	c.priceAlerts.mtx.Unlock()
+	c.signalPriceAlertsUpdated()
+	aCopy := *a
+	return &aCopy, nil
+}
+
+// RemovePriceAlert deletes the price alert.
+func (c *Core) RemovePriceAlert(id string) error {
+	if err := c.db.DeletePriceAlert(id); err != nil {
+		return err
+	}
+	c.priceAlerts.mtx.Lock()
+	delete(c.priceAlerts.alerts, id)
+	c.priceAlerts.mtx.Unlock()
+	c.signalPriceAlertsUpdated()
+	return nil
+}
+
+func (c *Core) signalPriceAlertsUpdated() {
+	select {
+	case c.priceAlertsUpdated <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,144 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+)
+
+func TestPriceAlertEvaluate(t *testing.T) {
+	above := &db.PriceAlert{ID: "above", Source: db.PriceAlertFiat, AssetID: 42, Condition: db.PriceAlertAbove, Threshold: 20}
+	below := &db.PriceAlert{ID: "below", Source: db.PriceAlertFiat, AssetID: 42, Condition: db.PriceAlertBelow, Threshold: 10, Rearm: true}
+	drop := &db.PriceAlert{ID: "drop", Source: db.PriceAlertFiat, AssetID: 42, Condition: db.PriceAlertChange, Threshold: -10, WindowSecs: 3600}
+	other := &db.PriceAlert{ID: "other", Source: db.PriceAlertFiat, AssetID: 0, Condition: db.PriceAlertAbove, Threshold: 1}
+	m := newPriceAlertManager([]*db.PriceAlert{above, below, drop, other})
+
+	key := fiatPriceKey(42)
+	now := time.Now()
+	step := func(price float64, d time.Duration, expTriggered ...string) {
+		t.Helper()
+		now = now.Add(d)
+		_, triggered := m.evaluate(key, price, now, 0)
+		if len(triggered) != len(expTriggered) {
+			t.Fatalf("price %f: expected %d triggered alerts, got %d", price, len(expTriggered), len(triggered))
+		}
+		ids := make(map[string]bool, len(triggered))
+		for _, tr := range triggered {
+			ids[tr.alert.ID] = true
+		}
+		for _, id := range expTriggered {
+			if !ids[id] {
+				t.Fatalf("price %f: alert %s not triggered", price, id)
+			}
+		}
+	}
+
+	step(15, 0)
+	step(21, time.Minute, "above")
+	// Not re-armed, so it doesn't trigger again.
+	step(15, time.Minute)
+	step(25, time.Minute)
+	step(9, time.Minute, "below")
+	step(8, time.Minute)
+	// Re-arms when the condition is no longer met.
+	step(11, time.Minute)
+	if m.alerts["below"].Triggered {
+		t.Fatalf("alert not re-armed")
+	}
+	// An hour after the 15 sample, 13.4 is a -10.7% change.
+	step(13.4, time.Hour-time.Minute*6, "drop")
+	if tr := m.alerts["drop"]; !tr.Triggered || tr.TriggerStamp != uint64(now.UnixMilli()) {
+		t.Fatalf("change alert not marked triggered")
+	}
+	if m.alerts["other"].Triggered {
+		t.Fatalf("alert for other asset triggered")
+	}
+}
+
+func TestPriceAlertMet(t *testing.T) {
+	change := &db.PriceAlert{Condition: db.PriceAlertChange, Threshold: 5}
+	if _, ok, _ := priceAlertMet(change, 10, 0, false); ok {
+		t.Fatalf("change alert evaluated without reference price")
+	}
+	met, ok, pct := priceAlertMet(change, 10.5, 10, true)
+	if !met || !ok || pct < 4.99 || pct > 5.01 {
+		t.Fatalf("wrong change result, met = %t, ok = %t, pct = %f", met, ok, pct)
+	}
+	if met, _, _ := priceAlertMet(change, 10.4, 10, true); met {
+		t.Fatalf("change alert met below threshold")
+	}
+}
+
+func TestCheckFiatPriceAlerts(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+
+	if _, err := tCore.AddPriceAlert(&PriceAlertForm{
+		Source:    db.PriceAlertFiat,
+		AssetID:   tUTXOAssetA.ID,
+		Condition: db.PriceAlertAbove,
+		Threshold: 0,
+	}); err == nil {
+		t.Fatalf("no error for zero threshold")
+	}
+	if _, err := tCore.AddPriceAlert(&PriceAlertForm{
+		Source:     db.PriceAlertFiat,
+		AssetID:    tUTXOAssetA.ID,
+		Condition:  db.PriceAlertChange,
+		Threshold:  5,
+		WindowSecs: uint64(maxPriceAlertWindow/time.Second) + 1,
+	}); err == nil {
+		t.Fatalf("no error for window too long")
+	}
+	if _, err := tCore.AddPriceAlert(&PriceAlertForm{
+		Source:    db.PriceAlertMidGap,
+		Host:      tDexHost,
+		BaseID:    tUTXOAssetA.ID,
+		QuoteID:   12345,
+		Condition: db.PriceAlertAbove,
+		Threshold: 1,
+	}); err == nil {
+		t.Fatalf("no error for unknown market")
+	}
+
+	alert, err := tCore.AddPriceAlert(&PriceAlertForm{
+		Source:    db.PriceAlertFiat,
+		AssetID:   tUTXOAssetA.ID,
+		Condition: db.PriceAlertAbove,
+		Threshold: 25,
+	})
+	if err != nil {
+		t.Fatalf("AddPriceAlert error: %v", err)
+	}
+
+	feed := tCore.NotificationFeed()
+	defer feed.ReturnFeed()
+
+	tCore.checkFiatPriceAlerts(map[uint32]float64{tUTXOAssetA.ID: 26})
+
+	select {
+	case n := <-feed.C:
+		note, ok := n.(*PriceAlertNote)
+		if !ok {
+			t.Fatalf("wrong notification type %T", n)
+		}
+		if note.Alert.ID != alert.ID || note.Price != 26 {
+			t.Fatalf("wrong price alert note %+v", note)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("no price alert notification")
+	}
+
+	if alerts := tCore.PriceAlerts(); len(alerts) != 1 || !alerts[0].Triggered {
+		t.Fatalf("alert not marked triggered")
+	}
+
+	if err := tCore.RemovePriceAlert(alert.ID); err != nil {
+		t.Fatalf("RemovePriceAlert error: %v", err)
+	}
+	if len(tCore.PriceAlerts()) != 0 {
+		t.Fatalf("alert not removed")
+	}
+}
@@ -0,0 +1,370 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/refundkit"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/order"
+)
+
+// refundKitInterval is how often the refund kit is checked for swaps that are
+// no longer active.
+const refundKitInterval = time.Minute
+
+// RefundKit is the emergency refund kit for our active swaps. The kit is
+// written, encrypted with the app password, to Path whenever it changes.
+type RefundKit struct {
+	Path    string             `json:"path"`
+	Entries []*refundkit.Entry `json:"entries"`
+}
+
+// refundKitPath is the location of the refund kit file.
+func (c *Core) refundKitPath() string {
+	if c.cfg.RefundKitPath != "" {
+		return c.cfg.RefundKitPath
+	}
+	return filepath.Join(filepath.Dir(c.cfg.DBPath), refundkit.FileName)
+}
+
+// RefundKit returns the current refund kit.
+func (c *Core) RefundKit() *RefundKit {
+	c.refundKitMtx.Lock()
+	defer c.refundKitMtx.Unlock()
+	return &RefundKit{
+		Path:    c.refundKitPath(),
+		Entries: c.refundKitEntries(),
+	}
+}
+
+// refundKitEntries returns the refund kit entries sorted by lock time. The
+// refundKitMtx MUST be locked.
+func (c *Core) refundKitEntries() []*refundkit.Entry {
+	entries := make([]*refundkit.Entry, 0, len(c.refundKit))
+	for _, e := range c.refundKit {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LockTime < entries[j].LockTime
+	})
+	return entries
+}
+
+// loadRefundKit derives the refund kit key from the app password and loads
+// the existing kit. The signed refunds are only kept in the kit, so they are
+// loaded before the active trades are resolved.
+func (c *Core) loadRefundKit(pw []byte) {
+	crypter := c.newCrypter(pw)
+	path := c.refundKitPath()
+	kit, err := refundkit.Read(path, pw)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		// Don't overwrite a kit that we can't read, e.g. one encrypted with a
+		// password that was reset.
+		c.log.Errorf("Error reading refund kit %s: %v", path, err)
+		if err := preserveRefundKit(path); err != nil {
+			c.log.Errorf("Error moving refund kit: %v", err)
+		}
+	}
+
+	c.refundKitMtx.Lock()
+	defer c.refundKitMtx.Unlock()
+	if c.refundKitCrypter != nil {
+		c.refundKitCrypter.Close()
+	}
+	c.refundKitCrypter = crypter
+	c.refundKit = make(map[order.MatchID]*refundkit.Entry)
+	if kit != nil {
+		for _, e := range kit.Entries {
+			var mid order.MatchID
+			copy(mid[:], e.MatchID)
+			c.refundKit[mid] = e
+		}
+	}
+}
+
+// preserveRefundKit moves an unreadable refund kit aside, to a new file named
+// for the time it was moved. An existing file is never replaced, so older
+// unreadable kits are kept too.
+func preserveRefundKit(path string) error {
+	stamp := time.Now().UTC().Format("20060102-150405.000")
+	for i := 0; i < 100; i++ {
+		movedPath := path + ".unreadable-" + stamp
+		if i > 0 {
+			movedPath += "-" + strconv.Itoa(i)
+		}
+		if _, err := os.Lstat(movedPath); !errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return os.Rename(path, movedPath)
+	}
+	return fmt.Errorf("no free name to move refund kit %s to", path)
+}
+
+// replaceRefundKitKey replaces the refund kit key after a password change and
+// rewrites the kit, if the user is logged in.
+func (c *Core) replaceRefundKitKey(pw []byte) {
+	crypter := c.newCrypter(pw)
+	c.refundKitMtx.Lock()
+	if c.refundKitCrypter == nil {
+		c.refundKitMtx.Unlock()
+		crypter.Close()
+		return
+	}
+	c.refundKitCrypter.Close()
+	c.refundKitCrypter = crypter
+	c.refundKitDirty = true
+	c.refundKitMtx.Unlock()
+	c.writeRefundKit()
+}
+
+// clearRefundKitKey writes any unwritten changes to the refund kit, then zeros
+// and discards the refund kit key.
+func (c *Core) clearRefundKitKey() {
+	c.writeRefundKit()
+	c.refundKitMtx.Lock()
+	defer c.refundKitMtx.Unlock()
+	if c.refundKitCrypter != nil {
+		c.refundKitCrypter.Close()
+		c.refundKitCrypter = nil
+	}
+	c.refundKit = nil
+}
+
+// markRefundKitDirty records that the refund kit has changed, and signals
+// runRefundKit to write it. The refundKitMtx MUST be locked.
+func (c *Core) markRefundKitDirty() {
+	c.refundKitDirty = true
+	select {
+	case c.refundKitWrite <- struct{}{}:
+	default:
+	}
+}
+
+// writeRefundKit writes the refund kit file if it has unwritten changes. The
+// kit is encrypted with the refundKitMtx locked, but the file is written and
+// synced without it, so writeRefundKit may be slow but never holds up the
+// callers that add entries. The refundKitMtx MUST NOT be locked.
+func (c *Core) writeRefundKit() {
+	c.refundKitWriteMtx.Lock()
+	defer c.refundKitWriteMtx.Unlock()
+
+	c.refundKitMtx.Lock()
+	if !c.refundKitDirty || c.refundKitCrypter == nil {
+		c.refundKitMtx.Unlock()
+		return
+	}
+	b, err := refundkit.Encode(c.refundKitCrypter, &refundkit.Kit{
+		Updated: time.Now().UnixMilli(),
+		Entries: c.refundKitEntries(),
+	})
+	path := c.refundKitPath()
+	c.refundKitDirty = false
+	c.refundKitMtx.Unlock()
+	if err == nil {
+		err = refundkit.WriteEncoded(path, b)
+	}
+	if err != nil {
+		c.log.Errorf("Error writing refund kit: %v", err)
+		// Try again on the next write or check.
+		c.refundKitMtx.Lock()
+		c.refundKitDirty = true
+		c.refundKitMtx.Unlock()
+	}
+}
+
+// addRefundKitEntries adds our new swaps to the refund kit, with the signed
+// refunds from the swap receipts. The kit is written by runRefundKit, not
+// here, since the trackedTrade mutex MUST be locked.
+func (c *Core) addRefundKitEntries(t *trackedTrade, matches []*matchTracker, receipts []asset.Receipt) {
+	c.refundKitMtx.Lock()
+	defer c.refundKitMtx.Unlock()
+	if c.refundKit == nil {
+		return
+	}
+	fromWallet := t.wallets.fromWallet
+	for i, r := range receipts {
+		match := matches[i]
+		coin := r.Coin()
+		c.refundKit[match.MatchID] = &refundkit.Entry{
+			Host:         t.dc.acct.host,
+			OrderID:      t.ID().Bytes(),
+			MatchID:      match.MatchID[:],
+			AssetID:      fromWallet.AssetID,
+			Symbol:       fromWallet.Symbol,
+			Value:        coin.Value(),
+			SwapCoin:     coin.String(),
+			SwapCoinID:   coin.ID(),
+			Contract:     r.Contract(),
+			SecretHash:   match.MetaData.Proof.SecretHash,
+			LockTime:     r.Expiration().Unix(),
+			SignedRefund: r.SignedRefund(),
+			Details:      r.String(),
+		}
+	}
+	c.markRefundKitDirty()
+}
+
+// updateRefundKit removes swaps that no longer need a refund from the refund
+// kit, and adds any active swaps that are missing, e.g. swaps sent before the
+// kit was kept. The added swaps have no signed refund.
+func (c *Core) updateRefundKit() {
+	active := make(map[order.MatchID]*refundkit.Entry)
+	for _, dc := range c.dexConnections() {
+		for _, t := range dc.trackedTrades() {
+			t.mtx.RLock()
+			for _, match := range t.matches {
+				proof := &match.MetaData.Proof
+				if len(ourSwap(&match.MetaMatch)) == 0 || len(proof.RefundCoin) > 0 || len(proof.ContractData) == 0 {
+					continue
+				}
+				active[match.MatchID] = refundKitEntryFromMatch(t, match)
+			}
+			t.mtx.RUnlock()
+		}
+	}
+
+	c.refundKitMtx.Lock()
+	if c.refundKit == nil {
+		c.refundKitMtx.Unlock()
+		return
+	}
+	inactive := make(map[order.MatchID]order.OrderID)
+	for mid, e := range c.refundKit {
+		if active[mid] == nil {
+			var oid order.OrderID
+			copy(oid[:], e.OrderID)
+			inactive[mid] = oid
+		}
+	}
+	c.refundKitMtx.Unlock()
+
+	// A swap that is not tracked may still need a refund, e.g. if the trades
+	// have not been loaded yet, so check the database.
+	settled := make([]order.MatchID, 0, len(inactive))
+	for mid, oid := range inactive {
+		if c.swapSettled(oid, mid) {
+			settled = append(settled, mid)
+		}
+	}
+
+	c.refundKitMtx.Lock()
+	if c.refundKit == nil {
+		c.refundKitMtx.Unlock()
+		return
+	}
+	for _, mid := range settled {
+		if c.refundKit[mid] != nil {
+			delete(c.refundKit, mid)
+			c.refundKitDirty = true
+		}
+	}
+	for mid, e := range active {
+		if c.refundKit[mid] == nil {
+			c.refundKit[mid] = e
+			c.refundKitDirty = true
+		}
+	}
+	c.refundKitMtx.Unlock()
+	c.writeRefundKit()
+}
+
+// swapSettled checks the database for whether our swap for the match no
+// longer needs a refund because the match is complete or the swap was
+// refunded. Unknown matches are not settled.
+func (c *Core) swapSettled(oid order.OrderID, mid order.MatchID) bool {
+	matches, err := c.db.MatchesForOrder(oid, true)
+	if err != nil {
+		c.log.Errorf("Error loading matches for order %s: %v", oid, err)
+		return false
+	}
+	for _, m := range matches {
+		if m.MatchID == mid {
+			return len(m.MetaData.Proof.RefundCoin) > 0 || !db.MatchIsActive(m.UserMatch, &m.MetaData.Proof)
+		}
+	}
+	return false
+}
+
+// refundKitEntryFromMatch creates a refund kit entry from the stored swap
+// details. The trackedTrade mutex MUST be locked.
+func refundKitEntryFromMatch(t *trackedTrade, match *matchTracker) *refundkit.Entry {
+	fromWallet := t.wallets.fromWallet
+	proof := &match.MetaData.Proof
+	value := match.Quantity
+	if !t.Trade().Sell {
+		value = calc.BaseToQuote(match.Rate, match.Quantity)
+	}
+	lockTime := match.matchTime().Add(t.lockTimeTaker)
+	if match.Side == order.Maker {
+		lockTime = match.matchTime().Add(t.lockTimeMaker)
+	}
+	swapCoin := ourSwap(&match.MetaMatch)
+	return &refundkit.Entry{
+		Host:       t.dc.acct.host,
+		OrderID:    t.ID().Bytes(),
+		MatchID:    match.MatchID[:],
+		AssetID:    fromWallet.AssetID,
+		Symbol:     fromWallet.Symbol,
+		Value:      value,
+		SwapCoin:   coinIDString(fromWallet.AssetID, swapCoin),
+		SwapCoinID: []byte(swapCoin),
+		Contract:   proof.ContractData,
+		SecretHash: proof.SecretHash,
+		LockTime:   lockTime.Unix(),
+	}
+}
+
+// runRefundKit keeps the refund kit up to date while the user is logged in.
+func (c *Core) runRefundKit(ctx context.Context) {
+	tick := time.NewTicker(refundKitInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-c.refundKitWrite:
+			c.writeRefundKit()
+		case <-tick.C:
+			c.updateRefundKit()
+		case <-ctx.Done():
+			// Don't lose a signed refund added since the last write.
+			c.writeRefundKit()
+			return
+		}
+	}
+}
+
+// ExportRefundKit writes a copy of the refund kit, encrypted with the app
+// password, to path.
+func (c *Core) ExportRefundKit(path string) error {
+	if path == c.refundKitPath() {
+		return fmt.Errorf("%s is the refund kit", path)
+	}
+	c.refundKitMtx.Lock()
+	if c.refundKitCrypter == nil {
+		c.refundKitMtx.Unlock()
+		return errors.New("not logged in")
+	}
+	b, err := refundkit.Encode(c.refundKitCrypter, &refundkit.Kit{
+		Updated: time.Now().UnixMilli(),
+		Entries: c.refundKitEntries(),
+	})
+	c.refundKitMtx.Unlock()
+	if err != nil {
+		return err
+	}
+	return refundkit.WriteEncoded(path, b)
+}
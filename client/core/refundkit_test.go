@@ -0,0 +1,185 @@
+package core
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/refundkit"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+	"decred.org/dcrdex/dex/order"
+	ordertest "decred.org/dcrdex/dex/order/test"
+)
+
+func TestRefundKit(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+	tCore := rig.core
+	tCore.newCrypter = encrypt.NewCrypter
+	kitPath := filepath.Join(t.TempDir(), refundkit.FileName)
+	tCore.cfg.RefundKitPath = kitPath
+	tCore.refundKitWrite = make(chan struct{}, 1)
+
+	dcrWallet, _ := newTWallet(tUTXOAssetA.ID)
+	tCore.wallets[tUTXOAssetA.ID] = dcrWallet
+	btcWallet, _ := newTWallet(tUTXOAssetB.ID)
+	tCore.wallets[tUTXOAssetB.ID] = btcWallet
+	walletSet, _, _, _ := tCore.walletSet(rig.dc, tUTXOAssetA.ID, tUTXOAssetB.ID, true)
+	tracker := makeTradeTracker(rig, walletSet, order.StandingTiF, order.OrderStatusBooked)
+	rig.dc.tradeMtx.Lock()
+	rig.dc.trades[tracker.ID()] = tracker
+	rig.dc.tradeMtx.Unlock()
+
+	matchStamp := time.Now()
+	newMatch := func() *matchTracker {
+		m := &matchTracker{
+			MetaMatch: db.MetaMatch{
+				UserMatch: &order.UserMatch{
+					OrderID:  tracker.ID(),
+					MatchID:  ordertest.RandomMatchID(),
+					Quantity: dcrBtcLotSize,
+					Rate:     dcrBtcRateStep,
+					Address:  ordertest.RandomAddress(),
+					Status:   order.MakerSwapCast,
+					Side:     order.Maker,
+				},
+				MetaData: &db.MatchMetaData{},
+			},
+		}
+		proof := &m.MetaData.Proof
+		proof.Auth.MatchStamp = uint64(matchStamp.UnixMilli())
+		proof.MakerSwap = encode.RandomBytes(36)
+		proof.ContractData = encode.RandomBytes(20)
+		tracker.mtx.Lock()
+		tracker.matches[m.MatchID] = m
+		tracker.mtx.Unlock()
+		return m
+	}
+	readKit := func(pw []byte) *refundkit.Kit {
+		t.Helper()
+		kit, err := refundkit.Read(kitPath, pw)
+		if err != nil {
+			t.Fatalf("error reading refund kit: %v", err)
+		}
+		return kit
+	}
+
+	tCore.loadRefundKit(tPW)
+
+	// A new swap with a signed refund.
+	m1 := newMatch()
+	receipt := &tReceipt{
+		coin:         &tCoin{id: m1.MetaData.Proof.MakerSwap, val: dcrBtcLotSize},
+		contract:     m1.MetaData.Proof.ContractData,
+		expiration:   matchStamp.Add(time.Hour),
+		signedRefund: []byte{0x01},
+	}
+	tracker.mtx.Lock()
+	tCore.addRefundKitEntries(tracker, []*matchTracker{m1}, []asset.Receipt{receipt})
+	tracker.mtx.Unlock()
+	// The kit is written by runRefundKit, not with the trade locked.
+	select {
+	case <-tCore.refundKitWrite:
+	default:
+		t.Fatalf("refund kit write not signaled")
+	}
+	tCore.writeRefundKit()
+	kit := readKit(tPW)
+	if len(kit.Entries) != 1 || !bytes.Equal(kit.Entries[0].SignedRefund, receipt.signedRefund) {
+		t.Fatalf("swap not added to refund kit")
+	}
+
+	// A swap sent before the kit was kept is added without a signed refund.
+	m2 := newMatch()
+	tCore.updateRefundKit()
+	kit = readKit(tPW)
+	if len(kit.Entries) != 2 {
+		t.Fatalf("expected 2 refund kit entries, got %d", len(kit.Entries))
+	}
+	var e2 *refundkit.Entry
+	for _, e := range kit.Entries {
+		if bytes.Equal(e.MatchID, m2.MatchID[:]) {
+			e2 = e
+		}
+	}
+	if e2 == nil || len(e2.SignedRefund) != 0 || e2.Value != dcrBtcLotSize ||
+		e2.LockTime != m2.matchTime().Add(tCore.lockTimeMaker).Unix() {
+		t.Fatalf("wrong entry for existing swap: %+v", e2)
+	}
+
+	// The kit is loaded on login.
+	tCore.clearRefundKitKey()
+	if n := len(tCore.RefundKit().Entries); n != 0 {
+		t.Fatalf("refund kit not cleared on logout")
+	}
+	tCore.loadRefundKit(tPW)
+	if n := len(tCore.RefundKit().Entries); n != 2 {
+		t.Fatalf("expected 2 loaded entries, got %d", n)
+	}
+
+	// Neither match is tracked. The first is complete in the database, but
+	// the second is unknown, so might still need a refund.
+	tracker.mtx.Lock()
+	delete(tracker.matches, m1.MatchID)
+	delete(tracker.matches, m2.MatchID)
+	tracker.mtx.Unlock()
+	m1.Status = order.MatchConfirmed
+	rig.db.matchesByOrderID = map[order.OrderID][]*db.MetaMatch{
+		tracker.ID(): {&m1.MetaMatch},
+	}
+	tCore.updateRefundKit()
+	kit = readKit(tPW)
+	if len(kit.Entries) != 1 || !bytes.Equal(kit.Entries[0].MatchID, m2.MatchID[:]) {
+		t.Fatalf("completed swap not removed from refund kit")
+	}
+
+	// A password change rewrites the kit.
+	newPW := []byte("newpw")
+	tCore.replaceRefundKitKey(newPW)
+	if kit := readKit(newPW); len(kit.Entries) != 1 {
+		t.Fatalf("wrong entries after password change")
+	}
+	if _, err := refundkit.Read(kitPath, tPW); err == nil {
+		t.Fatalf("kit readable with old password")
+	}
+
+	// Export a copy.
+	exportPath := filepath.Join(t.TempDir(), "export.bwrk")
+	if err := tCore.ExportRefundKit(exportPath); err != nil {
+		t.Fatalf("ExportRefundKit error: %v", err)
+	}
+	if kit, err := refundkit.Read(exportPath, newPW); err != nil || len(kit.Entries) != 1 {
+		t.Fatalf("error reading exported kit: %v", err)
+	}
+	tCore.clearRefundKitKey()
+	if err := tCore.ExportRefundKit(exportPath); err == nil {
+		t.Fatalf("no error exporting while logged out")
+	}
+
+	// Unreadable kits are moved aside, and never replace each other.
+	tCore.loadRefundKit(tPW)
+	tCore.clearRefundKitKey()
+	if err := os.WriteFile(kitPath, []byte("junk"), 0600); err != nil {
+		t.Fatalf("error writing junk kit: %v", err)
+	}
+	tCore.loadRefundKit(tPW)
+	tCore.clearRefundKitKey()
+	moved, _ := filepath.Glob(kitPath + ".unreadable-*")
+	if len(moved) != 2 {
+		t.Fatalf("expected 2 unreadable kits, got %d", len(moved))
+	}
+	var found bool
+	for _, p := range moved {
+		if kit, err := refundkit.Read(p, newPW); err == nil && len(kit.Entries) == 1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("unreadable kit not preserved")
+	}
+}
@@ -2773,6 +2773,8 @@ func (c *Core) swapMatchGroup(t *trackedTrade, matches []*matchTracker, highestF
 		return
 	}
 
+	c.addRefundKitEntries(t, matches, receipts)
+
 	refundTxs := ""
 	for i, r := range receipts {
 		rawRefund := r.SignedRefund()
@@ -254,6 +254,12 @@ type BondOptionsForm struct {
 	MaxBondedAmt *uint64 `json:"maxBondedAmt,omitempty"`
 	BondAssetID  *uint32 `json:"bondAssetID,omitempty"`
 	PenaltyComps *uint16 `json:"penaltyComps,omitempty"`
+	// BondAssetIDs is an ordered list of assets for the bond renewal planner
+	// to choose from. The first is the primary bond asset. An empty list
+	// disables the planner, so that bonds are always posted with BondAssetID.
+	BondAssetIDs *[]uint32 `json:"bondAssetIDs,omitempty"`
+	// SplitBonds allows the planner to split the tier across assets.
+	SplitBonds *bool `json:"splitBonds,omitempty"`
 }
 
 // PostBondForm is information necessary to post a new bond for a new or
@@ -729,6 +735,13 @@ type ExchangeAuth struct {
 	// PenaltyComps is the maximum number of penalized tiers to automatically
 	// compensate.
 	PenaltyComps uint16 `json:"penaltyComps"`
+	// BondAssetIDs are the assets the bond renewal planner chooses from, in
+	// order of preference. Empty if the planner is not used.
+	BondAssetIDs []uint32 `json:"bondAssetIDs,omitempty"`
+	// SplitBonds is whether the planner may split the tier across assets.
+	SplitBonds bool `json:"splitBonds"`
+	// BondPlan is the planner's latest decision.
+	BondPlan *BondPlan `json:"bondPlan,omitempty"`
 	// PendingBonds are currently pending bonds and their confirmation count.
 	PendingBonds []*PendingBondState `json:"pendingBonds"`
 	// ExpiredBonds are bonds that have expired but have not yet reached their
@@ -867,8 +880,11 @@ type dexAccount struct {
 	rep               account.Reputation
 	targetTier        uint64
 	maxBondedAmt      uint64
-	penaltyComps      uint16 // max penalties to compensate for
-	bondAsset         uint32 // asset used for bond maintenance/rotation
+	penaltyComps      uint16   // max penalties to compensate for
+	bondAsset         uint32   // asset used for bond maintenance/rotation
+	bondAssets        []uint32 // ordered bond assets for the planner, if used
+	splitBonds        bool
+	bondPlan          *BondPlan // latest plan
 }
 
 // newDEXAccount is a constructor for a new *dexAccount.
@@ -885,6 +901,8 @@ func newDEXAccount(acctInfo *db.AccountInfo, viewOnly bool) *dexAccount {
 		targetTier:   acctInfo.TargetTier,
 		maxBondedAmt: acctInfo.MaxBondedAmt,
 		bondAsset:    acctInfo.BondAsset,
+		bondAssets:   acctInfo.BondAssets,
+		splitBonds:   acctInfo.SplitBonds,
 		penaltyComps: acctInfo.PenaltyComps,
 	}
 }
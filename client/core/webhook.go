@@ -0,0 +1,403 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package core
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/dexnet"
+	"decred.org/dcrdex/dex/encode"
+)
+
+const (
+	// WebhookSignatureHeader is the HTTP header carrying the hex-encoded
+	// HMAC-SHA256 signature of the request body, prefixed with "sha256=".
+	WebhookSignatureHeader = "X-Bisonw-Signature"
+	// WebhookDeliveryHeader is the HTTP header carrying the delivery ID.
+	// Retries of the same delivery use the same ID.
+	WebhookDeliveryHeader = "X-Bisonw-Delivery"
+	// WebhookTimestampHeader is the HTTP header carrying the UNIX timestamp
+	// (seconds) of the delivery attempt.
+	WebhookTimestampHeader = "X-Bisonw-Timestamp"
+
+	webhookQueueSize        = 256
+	webhookWorkers          = 8
+	webhookMaxAttempts      = 5
+	webhookInitialBackoff   = 2 * time.Second
+	webhookMaxBackoff       = 2 * time.Minute
+	webhookRequestTimeout   = 15 * time.Second
+	webhookDeliveryLogLimit = 500
+)
+
+// WebhookForm is the information necessary to add a new webhook.
+type WebhookForm struct {
+	URL string `json:"url"`
+	// Secret is the HMAC key. If not specified, a random secret is generated
+	// and returned with the new webhook.
+	Secret    string   `json:"secret"`
+	Topics    []Topic  `json:"topics"`
+	NoteTypes []string `json:"noteTypes"`
+}
+
+// WebhookDelivery is a record of the delivery of a notification to a webhook.
+type WebhookDelivery struct {
+	ID         string `json:"id"`
+	WebhookID  string `json:"webhookID"`
+	URL        string `json:"url"`
+	NoteType   string `json:"noteType"`
+	Topic      Topic  `json:"topic"`
+	Attempts   int    `json:"attempts"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Delivered  bool   `json:"delivered"`
+	// Stamp is the time of the most recent attempt, in milliseconds.
+	Stamp uint64 `json:"stamp"`
+}
+
+// webhookPayload is the JSON body POSTed to a webhook.
+type webhookPayload struct {
+	DeliveryID string       `json:"deliveryID"`
+	WebhookID  string       `json:"webhookID"`
+	Type       string       `json:"type"`
+	Topic      Topic        `json:"topic"`
+	Severity   db.Severity  `json:"severity"`
+	Stamp      uint64       `json:"stamp"`
+	Note       Notification `json:"note"`
+}
+
+// SignWebhookPayload generates the value of the WebhookSignatureHeader for the
+// body.
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks the WebhookSignatureHeader value against the
+// body. Receivers can use VerifyWebhookSignature to authenticate deliveries.
+func VerifyWebhookSignature(secret string, body []byte, sig string) bool {
+	return hmac.Equal([]byte(SignWebhookPayload(secret, body)), []byte(sig))
+}
+
+// webhookMatches checks whether the notification should be delivered to the
+// webhook. A webhook with no topic or note type filters receives every
+// notification of severity Success or higher.
+func webhookMatches(wh *db.Webhook, n Notification) bool {
+	if wh.Disabled {
+		return false
+	}
+	if len(wh.Topics) == 0 && len(wh.NoteTypes) == 0 {
+		return n.Severity() >= db.Success
+	}
+	for _, t := range wh.Topics {
+		if t == n.Topic() {
+			return true
+		}
+	}
+	for _, t := range wh.NoteTypes {
+		if t == n.Type() {
+			return true
+		}
+	}
+	return false
+}
+
+type webhookJob struct {
+	wh   *db.Webhook
+	note Notification
+}
+
+// webhookManager delivers notifications to the user's webhooks. Deliveries
+// are retried with exponential backoff, and a bounded log of recent deliveries
+// is kept in memory.
+type webhookManager struct {
+	log         dex.Logger
+	queue       chan *webhookJob
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+
+	hooksMtx sync.RWMutex
+	hooks    map[string]*db.Webhook
+
+	deliveriesMtx sync.Mutex
+	deliveries    []*WebhookDelivery
+	cursor        int
+}
+
+func newWebhookManager(log dex.Logger, hooks []*db.Webhook) *webhookManager {
+	m := &webhookManager{
+		log:         log,
+		queue:       make(chan *webhookJob, webhookQueueSize),
+		maxAttempts: webhookMaxAttempts,
+		backoff:     webhookInitialBackoff,
+		maxBackoff:  webhookMaxBackoff,
+		hooks:       make(map[string]*db.Webhook, len(hooks)),
+	}
+	for _, wh := range hooks {
+		m.hooks[wh.ID] = wh
+	}
+	return m
+}
+
+// run processes queued deliveries until the context is canceled. Deliveries
+// are handled by a fixed pool of workers, so a slow receiver backs up the
+// queue rather than spawning goroutines without limit.
+func (m *webhookManager) run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < webhookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case job := <-m.queue:
+					m.deliver(ctx, job)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// dispatch queues the notification for delivery to any matching webhooks.
+func (m *webhookManager) dispatch(n Notification) {
+	m.hooksMtx.RLock()
+	defer m.hooksMtx.RUnlock()
+	for _, wh := range m.hooks {
+		if !webhookMatches(wh, n) {
+			continue
+		}
+		select {
+		case m.queue <- &webhookJob{wh: wh, note: n}:
+		default:
+			m.log.Errorf("Webhook queue full. Dropping %q notification for webhook %s", n.Topic(), wh.ID)
+		}
+	}
+}
+
+func (m *webhookManager) setHook(wh *db.Webhook) {
+	m.hooksMtx.Lock()
+	m.hooks[wh.ID] = wh
+	m.hooksMtx.Unlock()
+}
+
+func (m *webhookManager) removeHook(id string) {
+	m.hooksMtx.Lock()
+	delete(m.hooks, id)
+	m.hooksMtx.Unlock()
+}
+
+func (m *webhookManager) hook(id string) *db.Webhook {
+	m.hooksMtx.RLock()
+	defer m.hooksMtx.RUnlock()
+	return m.hooks[id]
+}
+
+// logDelivery adds the delivery to the delivery log, overwriting the oldest
+// entry if the log is full.
+func (m *webhookManager) logDelivery(d *WebhookDelivery) {
+	m.deliveriesMtx.Lock()
+	defer m.deliveriesMtx.Unlock()
+	if len(m.deliveries) >= webhookDeliveryLogLimit {
+		m.deliveries[m.cursor] = d
+	} else {
+		m.deliveries = append(m.deliveries, d)
+	}
+	m.cursor = (m.cursor + 1) % webhookDeliveryLogLimit
+}
+
+// updateDelivery applies f to the logged delivery under lock.
+func (m *webhookManager) updateDelivery(d *WebhookDelivery, f func(d *WebhookDelivery)) {
+	m.deliveriesMtx.Lock()
+	f(d)
+	m.deliveriesMtx.Unlock()
+}
+
+// deliveryLog returns copies of up to n of the most recent deliveries, newest
+// first. If n <= 0, all logged deliveries are returned.
+func (m *webhookManager) deliveryLog(n int) []*WebhookDelivery {
+	m.deliveriesMtx.Lock()
+	defer m.deliveriesMtx.Unlock()
+	count := len(m.deliveries)
+	if n <= 0 || n > count {
+		n = count
+	}
+	ds := make([]*WebhookDelivery, 0, n)
+	for i := 1; i <= n; i++ {
+		d := *m.deliveries[(m.cursor-i+webhookDeliveryLogLimit)%webhookDeliveryLogLimit]
+		ds = append(ds, &d)
+	}
+	return ds
+}
+
+// deliver POSTs the notification to the webhook, retrying with exponential
+// backoff until it is accepted, the attempts are exhausted, or the receiver
+// responds with a client error that would not be resolved by retrying.
+func (m *webhookManager) deliver(ctx context.Context, job *webhookJob) {
+	wh, n := job.wh, job.note
+	d := &WebhookDelivery{
+		ID:        hex.EncodeToString(encode.RandomBytes(8)),
+		WebhookID: wh.ID,
+		URL:       wh.URL,
+		NoteType:  n.Type(),
+		Topic:     n.Topic(),
+	}
+	m.logDelivery(d)
+
+	body, err := json.Marshal(&webhookPayload{
+		DeliveryID: d.ID,
+		WebhookID:  wh.ID,
+		Type:       n.Type(),
+		Topic:      n.Topic(),
+		Severity:   n.Severity(),
+		Stamp:      n.Time(),
+		Note:       n,
+	})
+	if err != nil {
+		m.updateDelivery(d, func(d *WebhookDelivery) { d.Error = fmt.Sprintf("error encoding payload: %v", err) })
+		m.log.Errorf("Error encoding webhook payload for %q notification: %v", n.Topic(), err)
+		return
+	}
+	sig := SignWebhookPayload(wh.Secret, body)
+
+	backoff := m.backoff
+	for attempt := 1; ; attempt++ {
+		var code int
+		err := func() error {
+			reqCtx, cancel := context.WithTimeout(ctx, webhookRequestTimeout)
+			defer cancel()
+			return dexnet.Post(reqCtx, wh.URL, nil, body,
+				dexnet.WithRequestHeader("Content-Type", "application/json"),
+				dexnet.WithRequestHeader(WebhookSignatureHeader, sig),
+				dexnet.WithRequestHeader(WebhookDeliveryHeader, d.ID),
+				dexnet.WithRequestHeader(WebhookTimestampHeader, strconv.FormatInt(time.Now().Unix(), 10)),
+				dexnet.WithStatusFunc(func(c int) { code = c }),
+			)
+		}()
+		m.updateDelivery(d, func(d *WebhookDelivery) {
+			d.Attempts = attempt
+			d.StatusCode = code
+			d.Stamp = uint64(time.Now().UnixMilli())
+			d.Delivered = err == nil
+			d.Error = ""
+			if err != nil {
+				d.Error = err.Error()
+			}
+		})
+		if err == nil {
+			return
+		}
+		// Client errors other than 408 and 429 will not succeed on retry.
+		permanent := code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+		if permanent || attempt >= m.maxAttempts {
+			m.log.Warnf("Failed to deliver %q notification to webhook %s after %d attempt(s): %v", n.Topic(), wh.ID, attempt, err)
+			return
+		}
+		m.log.Debugf("Webhook %s delivery attempt %d failed: %v. Retrying in %s", wh.ID, attempt, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > m.maxBackoff {
+			backoff = m.maxBackoff
+		}
+	}
+}
+
+// Webhooks returns the user's configured webhooks. The secrets are not
+// included. They are only returned by AddWebhook.
+func (c *Core) Webhooks() ([]*db.Webhook, error) {
+	whs, err := c.db.Webhooks()
+	if err != nil {
+		return nil, err
+	}
+	redacted := make([]*db.Webhook, 0, len(whs))
+	for _, wh := range whs {
+		whCopy := *wh
+		whCopy.Secret = ""
+		redacted = append(redacted, &whCopy)
+	}
+	return redacted, nil
+}
+
+// AddWebhook adds a new webhook that will receive matching notifications. If
+// the form does not specify a secret, one is generated. The returned webhook
+// is the only place the secret is shown.
+func (c *Core) AddWebhook(form *WebhookForm) (*db.Webhook, error) {
+	u, err := url.Parse(form.URL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing webhook URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("webhook URL scheme must be http or https, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, errors.New("webhook URL has no host")
+	}
+	secret := form.Secret
+	if secret == "" {
+		secret = hex.EncodeToString(encode.RandomBytes(32))
+	}
+	wh := &db.Webhook{
+		ID:        hex.EncodeToString(encode.RandomBytes(8)),
+		URL:       u.String(),
+		Secret:    secret,
+		Topics:    form.Topics,
+		NoteTypes: form.NoteTypes,
+	}
+	if err := c.db.UpdateWebhook(wh); err != nil {
+		return nil, fmt.Errorf("error storing webhook: %w", err)
+	}
+	c.webhooks.setHook(wh)
+	whCopy := *wh
+	return &whCopy, nil
+}
+
+// ToggleWebhook enables or disables deliveries to the webhook.
+func (c *Core) ToggleWebhook(id string, disable bool) error {
+	wh := c.webhooks.hook(id)
+	if wh == nil {
+		return fmt.Errorf("unknown webhook %q", id)
+	}
+	whCopy := *wh
+	whCopy.Disabled = disable
+	if err := c.db.UpdateWebhook(&whCopy); err != nil {
+		return fmt.Errorf("error updating webhook: %w", err)
+	}
+	c.webhooks.setHook(&whCopy)
+	return nil
+}
+
+// RemoveWebhook deletes the webhook.
+func (c *Core) RemoveWebhook(id string) error {
+	if err := c.db.DeleteWebhook(id); err != nil {
+		return err
+	}
+	c.webhooks.removeHook(id)
+	return nil
+}
+
+// WebhookDeliveries returns up to n of the most recent webhook deliveries,
+// newest first. If n <= 0, all logged deliveries are returned.
+func (c *Core) WebhookDeliveries(n int) []*WebhookDelivery {
+	return c.webhooks.deliveryLog(n)
+}
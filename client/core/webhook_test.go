@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/db"
+)
+
+func tWebhookMatchNote(topic Topic, severity db.Severity) *MatchNote {
+	return &MatchNote{Notification: db.NewNotification(NoteTypeMatch, topic, "subject", "details", severity)}
+}
+
+func TestWebhookMatches(t *testing.T) {
+	errNote := tWebhookMatchNote(TopicMatchErrorCoin, db.ErrorLevel)
+	botNote := db.NewNotification(NoteTypeBot, "", "", "", db.Data)
+	dataNote := &SpotPriceNote{Notification: db.NewNotification(NoteTypeSpots, TopicSpotsUpdate, "", "", db.Data)}
+
+	tests := []struct {
+		name string
+		wh   *db.Webhook
+		note Notification
+		want bool
+	}{{
+		name: "no filter, error note",
+		wh:   &db.Webhook{},
+		note: errNote,
+		want: true,
+	}, {
+		name: "no filter, data note",
+		wh:   &db.Webhook{},
+		note: dataNote,
+	}, {
+		name: "topic filter match",
+		wh:   &db.Webhook{Topics: []Topic{TopicMatchErrorCoin}},
+		note: errNote,
+		want: true,
+	}, {
+		name: "topic filter mismatch",
+		wh:   &db.Webhook{Topics: []Topic{TopicBondRefunded}},
+		note: errNote,
+	}, {
+		name: "note type filter",
+		wh:   &db.Webhook{NoteTypes: []string{NoteTypeBot}},
+		note: &botNote,
+		want: true,
+	}, {
+		name: "disabled",
+		wh:   &db.Webhook{Disabled: true},
+		note: errNote,
+	}}
+
+	for _, tt := range tests {
+		if got := webhookMatches(tt.wh, tt.note); got != tt.want {
+			t.Fatalf("%s: wanted %t, got %t", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	const secret = "abc"
+
+	var mtx sync.Mutex
+	var reqs int
+	failFirst := 2
+	received := make(chan *webhookPayload, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		reqs++
+		fail := reqs <= failFirst
+		mtx.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if !VerifyWebhookSignature(secret, body, r.Header.Get(WebhookSignatureHeader)) {
+			t.Errorf("bad signature")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get(WebhookDeliveryHeader) == "" {
+			t.Errorf("no delivery ID")
+		}
+		var p webhookPayload
+		p.Note = &db.Notification{}
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Errorf("error decoding payload: %v", err)
+		}
+		received <- &p
+	}))
+	defer srv.Close()
+
+	wh := &db.Webhook{ID: "wh1", URL: srv.URL, Secret: secret, Topics: []Topic{TopicMatchErrorCoin}}
+	m := newWebhookManager(tLogger, []*db.Webhook{wh})
+	m.backoff = time.Millisecond
+	m.maxBackoff = time.Millisecond * 5
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.run(ctx)
+	}()
+
+	m.dispatch(tWebhookMatchNote(TopicMatchErrorCoin, db.ErrorLevel))
+	m.dispatch(tWebhookMatchNote(TopicMatchComplete, db.Success))
+
+	select {
+	case p := <-received:
+		if p.WebhookID != wh.ID || p.Topic != TopicMatchErrorCoin || p.Type != NoteTypeMatch {
+			t.Fatalf("wrong payload: %+v", p)
+		}
+		if p.Note.Subject() != "subject" {
+			t.Fatalf("wrong note subject %q", p.Note.Subject())
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("webhook not delivered")
+	}
+
+	// The second note did not match the topic filter.
+	var ds []*WebhookDelivery
+	for i := 0; i < 100; i++ {
+		if ds = m.deliveryLog(0); len(ds) == 1 && ds[0].Delivered {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	cancel()
+	wg.Wait()
+
+	if len(ds) != 1 {
+		t.Fatalf("expected 1 logged delivery, got %d", len(ds))
+	}
+	if d := ds[0]; !d.Delivered || d.Attempts != 3 || d.StatusCode != http.StatusOK {
+		t.Fatalf("wrong delivery log entry: %+v", d)
+	}
+
+	// A permanent failure should not be retried.
+	mtx.Lock()
+	reqs, failFirst = 0, 0
+	mtx.Unlock()
+	rejectSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		reqs++
+		mtx.Unlock()
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer rejectSrv.Close()
+	m.setHook(&db.Webhook{ID: "wh1", URL: rejectSrv.URL, Secret: secret})
+	m.deliver(context.Background(), &webhookJob{wh: m.hook("wh1"), note: tWebhookMatchNote(TopicMatchErrorCoin, db.ErrorLevel)})
+	mtx.Lock()
+	n := reqs
+	mtx.Unlock()
+	if n != 1 {
+		t.Fatalf("expected 1 request for permanent failure, got %d", n)
+	}
+	if d := m.deliveryLog(1)[0]; d.Delivered || d.StatusCode != http.StatusBadRequest {
+		t.Fatalf("wrong delivery log entry for permanent failure: %+v", d)
+	}
+}
+
+func TestWebhookSecrets(t *testing.T) {
+	rig := newTestRig()
+	defer rig.shutdown()
+
+	wh, err := rig.core.AddWebhook(&WebhookForm{URL: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("AddWebhook error: %v", err)
+	}
+	if wh.Secret == "" {
+		t.Fatalf("no secret returned for new webhook")
+	}
+	whs, err := rig.core.Webhooks()
+	if err != nil {
+		t.Fatalf("Webhooks error: %v", err)
+	}
+	if len(whs) != 1 || whs[0].ID != wh.ID || whs[0].Secret != "" {
+		t.Fatalf("webhook secret not redacted: %+v", whs)
+	}
+	// The manager keeps the secret for signing.
+	if hook := rig.core.webhooks.hook(wh.ID); hook == nil || hook.Secret != wh.Secret {
+		t.Fatalf("webhook secret not kept for deliveries")
+	}
+}
@@ -64,6 +64,7 @@ var (
 	bondsSubBucket         = []byte("bonds") // sub bucket of accounts
 	activeOrdersBucket     = []byte("activeOrders")
 	archivedOrdersBucket   = []byte("orders")
+	orderIndexesBucket     = []byte("orderIndexes")
 	activeMatchesBucket    = []byte("activeMatches")
 	archivedMatchesBucket  = []byte("matches")
 	botProgramsBucket      = []byte("botPrograms")
@@ -74,6 +75,11 @@ var (
 	multisigIndexesBucket  = []byte("multiIndexes")
 	multisigPubKeysBucket  = []byte("multiPubKeys")
 	mmEpochSnapshotsBucket = []byte("mmEpochSnapshots")
+	webhooksBucket         = []byte("webhooks")
+	priceAlertsBucket      = []byte("priceAlerts")
+	labelsBucket           = []byte("labels")
+	contactsBucket         = []byte("contacts")
+	bridgeTradesBucket     = []byte("bridgeTrades")
 
 	// value keys
 	versionKey = []byte("version")
@@ -133,6 +139,7 @@ var (
 	companionTokenKey   = []byte("companionToken")
 	swapAddrKey         = []byte("swapAddr")
 	counterPartyAddrKey = []byte("counterPartyAddr")
+	fiatRatesKey        = []byte("fiatRates")
 
 	// values
 	byteTrue  = encode.ByteTrue
@@ -186,11 +193,12 @@ func NewDB(dbPath string, logger dex.Logger, opts ...Opts) (dexdb.DB, error) {
 
 	if err = bdb.makeTopLevelBuckets([][]byte{
 		appBucket, accountsBucket, bondIndexesBucket,
-		activeOrdersBucket, archivedOrdersBucket,
+		activeOrdersBucket, archivedOrdersBucket, orderIndexesBucket,
 		activeMatchesBucket, archivedMatchesBucket,
 		walletsBucket, notesBucket, credentialsBucket,
 		botProgramsBucket, pokesBucket, multisigIndexesBucket,
-		multisigPubKeysBucket, mmEpochSnapshotsBucket,
+		multisigPubKeysBucket, mmEpochSnapshotsBucket, webhooksBucket,
+		priceAlertsBucket, labelsBucket, contactsBucket, bridgeTradesBucket,
 	}); err != nil {
 		return nil, err
 	}
@@ -918,55 +926,63 @@ func (db *BoltDB) UpdateOrder(m *dexdb.MetaOrder) error {
 	if len(md.Proof.DEXSig) == 0 {
 		return fmt.Errorf("cannot save order without DEX signature")
 	}
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
-		oid := ord.ID()
-
-		// Create or move an order bucket based on order status. Active
-		// orders go in the activeOrdersBucket. Inactive orders go in the
-		// archivedOrdersBucket.
-		bkt := ob
-		whichBkt := "active"
-		inactive := !md.Status.IsActive()
-		if inactive {
-			// No order means that it was never added to the active
-			// orders bucket, or UpdateOrder was already called on
-			// this order after it became inactive.
-			if ob.Bucket(oid[:]) != nil {
-				// This order now belongs in the archived orders
-				// bucket. Delete it from active orders.
-				if err := ob.DeleteBucket(oid[:]); err != nil {
-					return fmt.Errorf("archived order bucket delete error: %w", err)
-				}
+	return db.orderUpdate(ord.ID(), func(ob, archivedOB *bbolt.Bucket) error {
+		return putOrder(ob, archivedOB, m)
+	})
+}
+
+// putOrder stores the order in the active or archived orders bucket, depending
+// on its status.
+func putOrder(ob, archivedOB *bbolt.Bucket, m *dexdb.MetaOrder) error {
+	ord, md := m.Order, m.MetaData
+	oid := ord.ID()
+
+	// Create or move an order bucket based on order status. Active
+	// orders go in the activeOrdersBucket. Inactive orders go in the
+	// archivedOrdersBucket.
+	bkt := ob
+	whichBkt := "active"
+	inactive := !md.Status.IsActive()
+	if inactive {
+		// No order means that it was never added to the active
+		// orders bucket, or UpdateOrder was already called on
+		// this order after it became inactive.
+		if ob.Bucket(oid[:]) != nil {
+			// This order now belongs in the archived orders
+			// bucket. Delete it from active orders.
+			if err := ob.DeleteBucket(oid[:]); err != nil {
+				return fmt.Errorf("archived order bucket delete error: %w", err)
 			}
-			bkt = archivedOB
-			whichBkt = "archived"
 		}
-		oBkt, err := bkt.CreateBucketIfNotExists(oid[:])
-		if err != nil {
-			return fmt.Errorf("%s bucket error: %w", whichBkt, err)
-		}
-
-		err = newBucketPutter(oBkt).
-			put(baseKey, uint32Bytes(ord.Base())).
-			put(quoteKey, uint32Bytes(ord.Quote())).
-			put(dexKey, []byte(md.Host)).
-			put(typeKey, []byte{byte(ord.Type())}).
-			put(orderKey, order.EncodeOrder(ord)).
-			put(epochDurKey, uint64Bytes(md.EpochDur)).
-			put(fromVersionKey, uint32Bytes(md.FromVersion)).
-			put(toVersionKey, uint32Bytes(md.ToVersion)).
-			put(fromSwapConfKey, uint32Bytes(md.FromSwapConf)).
-			put(toSwapConfKey, uint32Bytes(md.ToSwapConf)).
-			put(redeemMaxFeeRateKey, uint64Bytes(md.RedeemMaxFeeRate)).
-			put(maxFeeRateKey, uint64Bytes(md.MaxFeeRate)).
-			err()
+		bkt = archivedOB
+		whichBkt = "archived"
+	}
+	oBkt, err := bkt.CreateBucketIfNotExists(oid[:])
+	if err != nil {
+		return fmt.Errorf("%s bucket error: %w", whichBkt, err)
+	}
+
+	err = newBucketPutter(oBkt).
+		put(baseKey, uint32Bytes(ord.Base())).
+		put(quoteKey, uint32Bytes(ord.Quote())).
+		put(dexKey, []byte(md.Host)).
+		put(typeKey, []byte{byte(ord.Type())}).
+		put(orderKey, order.EncodeOrder(ord)).
+		put(epochDurKey, uint64Bytes(md.EpochDur)).
+		put(fromVersionKey, uint32Bytes(md.FromVersion)).
+		put(toVersionKey, uint32Bytes(md.ToVersion)).
+		put(fromSwapConfKey, uint32Bytes(md.FromSwapConf)).
+		put(toSwapConfKey, uint32Bytes(md.ToSwapConf)).
+		put(redeemMaxFeeRateKey, uint64Bytes(md.RedeemMaxFeeRate)).
+		put(maxFeeRateKey, uint64Bytes(md.MaxFeeRate)).
+		put(fiatRatesKey, encodeFiatRates(md.FiatRates)).
+		err()
 
-		if err != nil {
-			return err
-		}
+	if err != nil {
+		return err
+	}
 
-		return updateOrderMetaData(oBkt, md)
-	})
+	return updateOrderMetaData(oBkt, md)
 }
 
 // ActiveOrders retrieves all orders which appear to be in an active state,
@@ -981,17 +997,11 @@ func (db *BoltDB) ActiveOrders() ([]*dexdb.MetaOrder, error) {
 // applies no limit on number of orders returned. since = 0 is equivalent to
 // disabling the time filter, since no orders were created before 1970.
 func (db *BoltDB) AccountOrders(dex string, n int, since uint64) ([]*dexdb.MetaOrder, error) {
-	dexB := []byte(dex)
-	if n == 0 && since == 0 {
-		return db.filteredOrders(func(oBkt *bbolt.Bucket) bool {
-			return bEqual(dexB, oBkt.Get(dexKey))
-		}, true)
-	}
-	sinceB := uint64Bytes(since)
-	return db.newestOrders(n, func(_ []byte, oBkt *bbolt.Bucket) bool {
-		timeB := oBkt.Get(updateTimeKey)
-		return bEqual(dexB, oBkt.Get(dexKey)) && bytes.Compare(timeB, sinceB) >= 0
-	}, true)
+	return db.indexedOrders(&orderIndexQuery{
+		buckets: hostIndexes(dex),
+		n:       n,
+		since:   since,
+	})
 }
 
 // MarketOrders retrieves all orders for the specified DEX and market. n = 0
@@ -999,12 +1009,14 @@ func (db *BoltDB) AccountOrders(dex string, n int, since uint64) ([]*dexdb.MetaO
 // disabling the time filter, since no orders were created before 1970.
 func (db *BoltDB) MarketOrders(dex string, base, quote uint32, n int, since uint64) ([]*dexdb.MetaOrder, error) {
 	dexB := []byte(dex)
-	baseB := uint32Bytes(base)
-	quoteB := uint32Bytes(quote)
-	if n == 0 && since == 0 {
-		return db.marketOrdersAll(dexB, baseB, quoteB)
-	}
-	return db.marketOrdersSince(dexB, baseB, quoteB, n, since)
+	return db.indexedOrders(&orderIndexQuery{
+		buckets: marketIndex(base, quote),
+		n:       n,
+		since:   since,
+		filter: func(_ []byte, oBkt *bbolt.Bucket) bool {
+			return bEqual(dexB, oBkt.Get(dexKey))
+		},
+	})
 }
 
 // ActiveDEXOrders retrieves all orders for the specified DEX.
@@ -1015,50 +1027,6 @@ func (db *BoltDB) ActiveDEXOrders(dex string) ([]*dexdb.MetaOrder, error) {
 	}, false)
 }
 
-// marketOrdersAll retrieves all orders for the specified DEX and market.
-func (db *BoltDB) marketOrdersAll(dexB, baseB, quoteB []byte) ([]*dexdb.MetaOrder, error) {
-	return db.filteredOrders(func(oBkt *bbolt.Bucket) bool {
-		return bEqual(dexB, oBkt.Get(dexKey)) && bEqual(baseB, oBkt.Get(baseKey)) &&
-			bEqual(quoteB, oBkt.Get(quoteKey))
-	}, true)
-}
-
-// marketOrdersSince grabs market orders with optional filters for maximum count
-// and age. The sort order is always newest first. If n is 0, there is no limit
-// to the number of orders returned. If using with both n = 0, and since = 0,
-// use marketOrdersAll instead.
-func (db *BoltDB) marketOrdersSince(dexB, baseB, quoteB []byte, n int, since uint64) ([]*dexdb.MetaOrder, error) {
-	sinceB := uint64Bytes(since)
-	return db.newestOrders(n, func(_ []byte, oBkt *bbolt.Bucket) bool {
-		timeB := oBkt.Get(updateTimeKey)
-		return bEqual(dexB, oBkt.Get(dexKey)) && bEqual(baseB, oBkt.Get(baseKey)) &&
-			bEqual(quoteB, oBkt.Get(quoteKey)) && bytes.Compare(timeB, sinceB) >= 0
-	}, true)
-}
-
-// newestOrders returns the n newest orders, filtered with a supplied filter
-// function. Each order's bucket is provided to the filter, and a boolean true
-// return value indicates the order should is eligible to be decoded and
-// returned.
-func (db *BoltDB) newestOrders(n int, filter func([]byte, *bbolt.Bucket) bool, includeArchived bool) ([]*dexdb.MetaOrder, error) {
-	orders := make([]*dexdb.MetaOrder, 0, n)
-	return orders, db.ordersView(func(ob, archivedOB *bbolt.Bucket) error {
-		buckets := []*bbolt.Bucket{ob}
-		if includeArchived {
-			buckets = append(buckets, archivedOB)
-		}
-		trios := newestBuckets(buckets, n, updateTimeKey, filter)
-		for _, trio := range trios {
-			o, err := decodeOrderBucket(trio.k, trio.b)
-			if err != nil {
-				return err
-			}
-			orders = append(orders, o)
-		}
-		return nil
-	})
-}
-
 // filteredOrders gets all orders that pass the provided filter function. Each
 // order's bucket is provided to the filter, and a boolean true return value
 // indicates the order should be decoded and returned.
@@ -1103,7 +1071,7 @@ func (db *BoltDB) Order(oid order.OrderID) (mord *dexdb.MetaOrder, err error) {
 			oBkt = archivedOB.Bucket(oidB)
 		}
 		if oBkt == nil {
-			return fmt.Errorf("order %s not found", oid)
+			return fmt.Errorf("%w: %s", dexdb.ErrOrderNotFound, oid)
 		}
 		var err error
 		mord, err = decodeOrderBucket(oidB, oBkt)
@@ -1129,7 +1097,9 @@ func (fs filterSet) check(oidB []byte, oBkt *bbolt.Bucket) bool {
 }
 
 // Orders fetches a slice of orders, sorted by descending time, and filtered
-// with the provided OrderFilter. Orders does not return cancel orders.
+// with the provided OrderFilter. Orders does not return cancel orders. The
+// search is done on the most selective of the market, host and status indexes
+// that applies to the filter.
 func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrder, err error) {
 	// Default filter is just to exclude cancel orders.
 	filters := filterSet{
@@ -1164,7 +1134,14 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 		})
 	}
 
-	includeArchived := true
+	q := &orderIndexQuery{
+		buckets: timeIndex,
+		n:       orderFilter.N,
+	}
+	if len(orderFilter.Hosts) > 0 {
+		q.buckets = hostIndexes(orderFilter.Hosts...)
+	}
+
 	if len(orderFilter.Statuses) > 0 {
 		filters = append(filters, func(oidB []byte, oBkt *bbolt.Bucket) bool {
 			status := order.OrderStatus(intCoder.Uint16(oBkt.Get(statusKey)))
@@ -1191,16 +1168,17 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 
 			return false
 		})
-		includeArchived = false
-		for _, status := range orderFilter.Statuses {
-			if !status.IsActive() {
-				includeArchived = true
-				break
+		if len(orderFilter.Hosts) == 0 {
+			statuses := orderFilter.Statuses
+			if orderFilter.IncludePartial && slices.Contains(statuses, order.OrderStatusExecuted) {
+				statuses = append(slices.Clone(statuses), order.OrderStatusCanceled, order.OrderStatusRevoked)
 			}
+			q.buckets = statusIndexes(statuses...)
 		}
 	}
 
 	if orderFilter.Market != nil {
+		q.buckets = marketIndex(orderFilter.Market.Base, orderFilter.Market.Quote)
 		filters = append(filters, func(_ []byte, oBkt *bbolt.Bucket) bool {
 			baseID, quoteID := intCoder.Uint32(oBkt.Get(baseKey)), intCoder.Uint32(oBkt.Get(quoteKey))
 			return orderFilter.Market.Base == baseID && orderFilter.Market.Quote == quoteID
@@ -1227,13 +1205,16 @@ func (db *BoltDB) Orders(orderFilter *dexdb.OrderFilter) (ords []*dexdb.MetaOrde
 			return nil, err
 		}
 
+		stamp := intCoder.Uint64(stampB)
+		q.before = &stamp
 		filters = append(filters, func(oidB []byte, oBkt *bbolt.Bucket) bool {
 			comp := bytes.Compare(oBkt.Get(updateTimeKey), stampB)
 			return comp < 0 || (comp == 0 && bytes.Compare(offsetOID[:], oidB) < 0)
 		})
 	}
 
-	return db.newestOrders(orderFilter.N, filters.check, includeArchived)
+	q.filter = filters.check
+	return db.indexedOrders(q)
 }
 
 // decodeOrderBucket decodes the order's *bbolt.Bucket into a *MetaOrder.
@@ -1331,6 +1312,11 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 		fundingFeesPaid = intCoder.Uint64(fundingFeesB)
 	}
 
+	fiatRates, err := decodeFiatRates(oBkt.Get(fiatRatesKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode fiat rates: %w", err)
+	}
+
 	return &dexdb.MetaOrder{
 		MetaData: &dexdb.OrderMetaData{
 			Proof:              *proof,
@@ -1352,11 +1338,53 @@ func decodeOrderBucket(oid []byte, oBkt *bbolt.Bucket) (*dexdb.MetaOrder, error)
 			RefundReserves:     refundReserves,
 			AccelerationCoins:  accelerationCoinIDs,
 			FundingFeesPaid:    fundingFeesPaid,
+			FiatRates:          fiatRates,
 		},
 		Order: ord,
 	}, nil
 }
 
+// encodeFiatRates encodes an order's fiat rates as a versioned blob of asset
+// ID and rate pairs. Nil is returned if there are no rates.
+func encodeFiatRates(rates map[uint32]float64) []byte {
+	if len(rates) == 0 {
+		return nil
+	}
+	assetIDs := make([]uint32, 0, len(rates))
+	for assetID := range rates {
+		assetIDs = append(assetIDs, assetID)
+	}
+	sort.Slice(assetIDs, func(i, j int) bool { return assetIDs[i] < assetIDs[j] })
+	b := encode.BuildyBytes{0}
+	for _, assetID := range assetIDs {
+		b = b.AddData(append(uint32Bytes(assetID), uint64Bytes(math.Float64bits(rates[assetID]))...))
+	}
+	return b
+}
+
+// decodeFiatRates decodes fiat rates encoded with encodeFiatRates. Orders
+// stored before fiat rates were recorded have none.
+func decodeFiatRates(b []byte) (map[uint32]float64, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	ver, pushes, err := encode.DecodeBlob(b)
+	if err != nil {
+		return nil, err
+	}
+	if ver != 0 {
+		return nil, fmt.Errorf("unknown fiat rates version %d", ver)
+	}
+	rates := make(map[uint32]float64, len(pushes))
+	for _, p := range pushes {
+		if len(p) != 12 {
+			return nil, fmt.Errorf("invalid fiat rate length %d", len(p))
+		}
+		rates[intCoder.Uint32(p[:4])] = math.Float64frombits(intCoder.Uint64(p[4:]))
+	}
+	return rates, nil
+}
+
 // updateOrderBucket expects an oid to exist in either the orders or archived
 // order buckets. If status is not active, it first checks active orders. If
 // found it moves the order to the archived bucket and returns that order
@@ -1403,7 +1431,7 @@ func updateOrderBucket(ob, archivedOB *bbolt.Bucket, oid order.OrderID, status o
 
 // UpdateOrderMetaData updates the order metadata, not including the Host.
 func (db *BoltDB) UpdateOrderMetaData(oid order.OrderID, md *dexdb.OrderMetaData) error {
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.orderUpdate(oid, func(ob, archivedOB *bbolt.Bucket) error {
 		oBkt, err := updateOrderBucket(ob, archivedOB, oid, md.Status)
 		if err != nil {
 			return fmt.Errorf("UpdateOrderMetaData: %w", err)
@@ -1445,7 +1473,7 @@ func updateOrderMetaData(bkt *bbolt.Bucket, md *dexdb.OrderMetaData) error {
 
 // UpdateOrderStatus sets the order status for an order.
 func (db *BoltDB) UpdateOrderStatus(oid order.OrderID, status order.OrderStatus) error {
-	return db.ordersUpdate(func(ob, archivedOB *bbolt.Bucket) error {
+	return db.orderUpdate(oid, func(ob, archivedOB *bbolt.Bucket) error {
 		oBkt, err := updateOrderBucket(ob, archivedOB, oid, status)
 		if err != nil {
 			return fmt.Errorf("UpdateOrderStatus: %w", err)
@@ -2329,6 +2357,10 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 			if archivedOB == nil {
 				return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
 			}
+			idx := tx.Bucket(orderIndexesBucket)
+			if idx == nil {
+				return fmt.Errorf("failed to open %s bucket", string(orderIndexesBucket))
+			}
 			for j := i; j < end; j++ {
 				key := keys[j]
 				oBkt := archivedOB.Bucket(key)
@@ -2358,6 +2390,9 @@ func (db *BoltDB) DeleteInactiveOrders(ctx context.Context, olderThan *time.Time
 				if err != nil {
 					return fmt.Errorf("failed to decode order bucket: %v", err)
 				}
+				if err := deleteOrderIndex(idx, key, orderIndexEntryFromBucket(oBkt)); err != nil {
+					return fmt.Errorf("failed to delete order index entries: %v", err)
+				}
 				if err := archivedOB.DeleteBucket(key); err != nil {
 					return fmt.Errorf("failed to delete order bucket: %v", err)
 				}
@@ -2725,3 +2760,214 @@ func (db *BoltDB) MMEpochSnapshots(host string, base, quote uint32, startEpoch,
 // A couple of common bbolt functions.
 type bucketFunc func(*bbolt.Bucket) error
 type txFunc func(func(*bbolt.Tx) error) error
+
+// UpdateWebhook stores the webhook, overwriting any existing webhook with the
+// same ID.
+func (db *BoltDB) UpdateWebhook(wh *dexdb.Webhook) error {
+	if wh.ID == "" {
+		return errors.New("webhook has no ID")
+	}
+	b, err := json.Marshal(wh)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(webhooksBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		return bkt.Put([]byte(wh.ID), b)
+	})
+}
+
+// DeleteWebhook deletes the webhook with the specified ID.
+func (db *BoltDB) DeleteWebhook(id string) error {
+	return db.withBucket(webhooksBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		if bkt.Get([]byte(id)) == nil {
+			return fmt.Errorf("webhook %q not found", id)
+		}
+		return bkt.Delete([]byte(id))
+	})
+}
+
+// Webhooks retrieves all stored webhooks.
+func (db *BoltDB) Webhooks() ([]*dexdb.Webhook, error) {
+	var whs []*dexdb.Webhook
+	return whs, db.withBucket(webhooksBucket, db.View, func(bkt *bbolt.Bucket) error {
+		return bkt.ForEach(func(k, v []byte) error {
+			var wh dexdb.Webhook
+			if err := json.Unmarshal(v, &wh); err != nil {
+				db.log.Errorf("Failed to unmarshal webhook %s: %v", string(k), err)
+				return nil
+			}
+			whs = append(whs, &wh)
+			return nil
+		})
+	})
+}
+
+// UpdatePriceAlert stores the price alert, overwriting any existing alert with
+// the same ID.
+func (db *BoltDB) UpdatePriceAlert(alert *dexdb.PriceAlert) error {
+	if alert.ID == "" {
+		return errors.New("price alert has no ID")
+	}
+	b, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(priceAlertsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		return bkt.Put([]byte(alert.ID), b)
+	})
+}
+
+// DeletePriceAlert deletes the price alert with the specified ID.
+func (db *BoltDB) DeletePriceAlert(id string) error {
+	return db.withBucket(priceAlertsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		if bkt.Get([]byte(id)) == nil {
+			return fmt.Errorf("price alert %q not found", id)
+		}
+		return bkt.Delete([]byte(id))
+	})
+}
+
+// PriceAlerts retrieves all stored price alerts.
+func (db *BoltDB) PriceAlerts() ([]*dexdb.PriceAlert, error) {
+	var alerts []*dexdb.PriceAlert
+	return alerts, db.withBucket(priceAlertsBucket, db.View, func(bkt *bbolt.Bucket) error {
+		return bkt.ForEach(func(k, v []byte) error {
+			var alert dexdb.PriceAlert
+			if err := json.Unmarshal(v, &alert); err != nil {
+				db.log.Errorf("Failed to unmarshal price alert %s: %v", string(k), err)
+				return nil
+			}
+			alerts = append(alerts, &alert)
+			return nil
+		})
+	})
+}
+
+// labelKey is the key for a label in the labelsBucket. Keys are prefixed with
+// the asset ID.
+func labelKey(label *dexdb.Label) []byte {
+	return append(encode.Uint32Bytes(label.AssetID), []byte(string(label.Kind)+":"+label.ID)...)
+}
+
+// contactKey is the key for a contact in the contactsBucket. Keys are prefixed
+// with the asset ID, and names are case-insensitive.
+func contactKey(assetID uint32, name string) []byte {
+	return append(encode.Uint32Bytes(assetID), []byte(strings.ToLower(name))...)
+}
+
+// SetLabel stores the label, replacing any existing label for the same asset,
+// kind and ID. A label with an empty Label string is deleted.
+func (db *BoltDB) SetLabel(label *dexdb.Label) error {
+	if label.ID == "" {
+		return errors.New("label has no ID")
+	}
+	if label.Kind != dexdb.LabelTx && label.Kind != dexdb.LabelAddress {
+		return fmt.Errorf("unknown label kind %q", label.Kind)
+	}
+	k := labelKey(label)
+	if label.Label == "" {
+		return db.withBucket(labelsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+			return bkt.Delete(k)
+		})
+	}
+	b, err := json.Marshal(label)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(labelsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		return bkt.Put(k, b)
+	})
+}
+
+// Labels retrieves all labels for the asset.
+func (db *BoltDB) Labels(assetID uint32) ([]*dexdb.Label, error) {
+	var labels []*dexdb.Label
+	prefix := encode.Uint32Bytes(assetID)
+	return labels, db.withBucket(labelsBucket, db.View, func(bkt *bbolt.Bucket) error {
+		c := bkt.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var label dexdb.Label
+			if err := json.Unmarshal(v, &label); err != nil {
+				db.log.Errorf("Failed to unmarshal label %x: %v", k, err)
+				continue
+			}
+			labels = append(labels, &label)
+		}
+		return nil
+	})
+}
+
+// UpdateContact stores the contact, overwriting any existing contact with the
+// same asset and name.
+func (db *BoltDB) UpdateContact(contact *dexdb.Contact) error {
+	if contact.Name == "" {
+		return errors.New("contact has no name")
+	}
+	b, err := json.Marshal(contact)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(contactsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		return bkt.Put(contactKey(contact.AssetID, contact.Name), b)
+	})
+}
+
+// DeleteContact deletes the asset's contact with the specified name.
+func (db *BoltDB) DeleteContact(assetID uint32, name string) error {
+	k := contactKey(assetID, name)
+	return db.withBucket(contactsBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		if bkt.Get(k) == nil {
+			return fmt.Errorf("contact %q not found", name)
+		}
+		return bkt.Delete(k)
+	})
+}
+
+// Contacts retrieves the asset's address book, sorted by name.
+func (db *BoltDB) Contacts(assetID uint32) ([]*dexdb.Contact, error) {
+	var contacts []*dexdb.Contact
+	prefix := encode.Uint32Bytes(assetID)
+	return contacts, db.withBucket(contactsBucket, db.View, func(bkt *bbolt.Bucket) error {
+		c := bkt.Cursor()
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+			var contact dexdb.Contact
+			if err := json.Unmarshal(v, &contact); err != nil {
+				db.log.Errorf("Failed to unmarshal contact %x: %v", k, err)
+				continue
+			}
+			contacts = append(contacts, &contact)
+		}
+		return nil
+	})
+}
+
+// UpdateBridgeTrade stores the bridge trade, overwriting any existing bridge
+// trade with the same ID.
+func (db *BoltDB) UpdateBridgeTrade(bt *dexdb.BridgeTrade) error {
+	if bt.ID == "" {
+		return errors.New("bridge trade has no ID")
+	}
+	b, err := json.Marshal(bt)
+	if err != nil {
+		return fmt.Errorf("JSON marshal error: %w", err)
+	}
+	return db.withBucket(bridgeTradesBucket, db.Update, func(bkt *bbolt.Bucket) error {
+		return bkt.Put([]byte(bt.ID), b)
+	})
+}
+
+// BridgeTrades retrieves all stored bridge trades.
+func (db *BoltDB) BridgeTrades() ([]*dexdb.BridgeTrade, error) {
+	var bts []*dexdb.BridgeTrade
+	return bts, db.withBucket(bridgeTradesBucket, db.View, func(bkt *bbolt.Bucket) error {
+		return bkt.ForEach(func(k, v []byte) error {
+			var bt dexdb.BridgeTrade
+			if err := json.Unmarshal(v, &bt); err != nil {
+				db.log.Errorf("Failed to unmarshal bridge trade %s: %v", string(k), err)
+				return nil
+			}
+			bts = append(bts, &bt)
+			return nil
+		})
+	})
+}
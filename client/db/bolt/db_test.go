@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -25,7 +26,7 @@ var (
 	tLogger = dex.StdOutLogger("db_TEST", dex.LevelTrace)
 )
 
-func newTestDB(t *testing.T) (*BoltDB, func()) {
+func newTestDB(t testing.TB) (*BoltDB, func()) {
 	t.Helper()
 	dbPath := filepath.Join(t.TempDir(), "db.db")
 	dbi, err := NewDB(dbPath, tLogger)
@@ -472,6 +473,7 @@ func TestOrders(t *testing.T) {
 				SwapFeesPaid:       rand.Uint64(),
 				RedemptionFeesPaid: rand.Uint64(),
 				MaxFeeRate:         rand.Uint64(),
+				FiatRates:          map[uint32]float64{base: rand.Float64(), quote: rand.Float64()},
 			},
 			Order: ord,
 		}
@@ -512,6 +514,9 @@ func TestOrders(t *testing.T) {
 	if firstOrd.MetaData.MaxFeeRate != mord.MetaData.MaxFeeRate {
 		t.Fatalf("wrong MaxFeeRate. wanted %d, got %d", firstOrd.MetaData.MaxFeeRate, mord.MetaData.MaxFeeRate)
 	}
+	if !maps.Equal(firstOrd.MetaData.FiatRates, mord.MetaData.FiatRates) {
+		t.Fatalf("wrong FiatRates. wanted %v, got %v", firstOrd.MetaData.FiatRates, mord.MetaData.FiatRates)
+	}
 
 	// Check the active orders.
 	activeOrders, err := boltdb.ActiveOrders()
@@ -673,7 +678,7 @@ func TestOrderFilters(t *testing.T) {
 		}
 
 		// Set the update time.
-		boltdb.ordersUpdate(func(aob, eob *bbolt.Bucket) error {
+		boltdb.orderUpdate(oid, func(aob, eob *bbolt.Bucket) error {
 			oBkt := aob.Bucket(oid[:])
 			if oBkt == nil {
 				oBkt = eob.Bucket(oid[:])
@@ -771,22 +776,190 @@ func TestOrderFilters(t *testing.T) {
 			},
 			expected: []int{4, 3},
 		},
+		{
+			name: "executed",
+			filter: &db.OrderFilter{
+				N:        orderCount,
+				Statuses: []order.OrderStatus{order.OrderStatusExecuted},
+			},
+			expected: []int{5, 0},
+		},
+		{
+			name: "host2 + executed",
+			filter: &db.OrderFilter{
+				N:        orderCount,
+				Hosts:    []string{host2},
+				Statuses: []order.OrderStatus{order.OrderStatusExecuted},
+			},
+			expected: []int{5},
+		},
+		{
+			name: "market",
+			filter: &db.OrderFilter{
+				N:      orderCount,
+				Market: &db.OrderFilterMarket{Base: asset3, Quote: asset1},
+			},
+			expected: []int{5, 2},
+		},
+		{
+			name: "market + offset",
+			filter: &db.OrderFilter{
+				N:      orderCount,
+				Market: &db.OrderFilterMarket{Base: asset3, Quote: asset1},
+				Offset: orders[5].Order.ID(),
+			},
+			expected: []int{2},
+		},
+		{
+			name: "n",
+			filter: &db.OrderFilter{
+				N: 2,
+			},
+			expected: []int{4, 5},
+		},
 	}
 
-	for _, test := range tests {
-		ords, err := boltdb.Orders(test.filter)
+	runTests := func() {
+		t.Helper()
+		for _, test := range tests {
+			ords, err := boltdb.Orders(test.filter)
+			if err != nil {
+				t.Fatalf("%s: Orders error: %v", test.name, err)
+			}
+			if len(ords) != len(test.expected) {
+				t.Fatalf("%s: wrong number of orders. wanted %d, got %d", test.name, len(test.expected), len(ords))
+			}
+			for i, j := range test.expected {
+				if ords[i].Order.ID() != orders[j].Order.ID() {
+					t.Fatalf("%s: index %d wrong ID. wanted %s, got %s", test.name, i, orders[j].Order.ID(), ords[i].Order.ID())
+				}
+			}
+		}
+	}
+	runTests()
+
+	// Rebuilding the indexes from scratch, as the v8 upgrade does, should
+	// give the same results.
+	err := boltdb.Update(func(tx *bbolt.Tx) error {
+		if err := tx.DeleteBucket(orderIndexesBucket); err != nil {
+			return err
+		}
+		n, err := buildOrderIndexes(tx)
 		if err != nil {
-			t.Fatalf("%s: Orders error: %v", test.name, err)
+			return err
 		}
-		if len(ords) != len(test.expected) {
-			t.Fatalf("%s: wrong number of orders. wanted %d, got %d", test.name, len(test.expected), len(ords))
+		if n != orderCount {
+			return fmt.Errorf("indexed %d orders, expected %d", n, orderCount)
 		}
-		for i, j := range test.expected {
-			if ords[i].Order.ID() != orders[j].Order.ID() {
-				t.Fatalf("%s: index %d wrong ID. wanted %s, got %s", test.name, i, orders[j].Order.ID(), ords[i].Order.ID())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error rebuilding order indexes: %v", err)
+	}
+	runTests()
+}
+
+// BenchmarkOrders measures order history queries against a DB with a large
+// number of archived orders, as a heavy market maker would have.
+func BenchmarkOrders(b *testing.B) {
+	boltdb, shutdown := newTestDB(b)
+	defer shutdown()
+
+	const (
+		nOrders   = 200_000
+		batchSize = 5_000
+	)
+	hosts := []string{"dex.decred.org:7232", "bison.exchange:17232", "dex.example.com:7232"}
+	markets := [][2]uint32{{42, 0}, {60, 0}, {42, 60}, {0, 60001}}
+	statuses := []order.OrderStatus{order.OrderStatusExecuted, order.OrderStatusCanceled, order.OrderStatusRevoked}
+
+	start := uint64(time.Now().Add(-time.Hour * 24 * 365).UnixMilli())
+	var offset order.OrderID
+	for i := 0; i < nOrders; i += batchSize {
+		err := boltdb.Update(func(tx *bbolt.Tx) error {
+			for j := i; j < i+batchSize; j++ {
+				mkt := markets[j%len(markets)]
+				ord, _ := ordertest.RandomLimitOrder()
+				ord.BaseAsset, ord.QuoteAsset = mkt[0], mkt[1]
+				status := statuses[j%len(statuses)]
+				if j%100 == 0 {
+					status = order.OrderStatusBooked
+				}
+				mord := &db.MetaOrder{
+					MetaData: &db.OrderMetaData{
+						Status: status,
+						Host:   hosts[j%len(hosts)],
+						Proof:  db.OrderProof{DEXSig: randBytes(73)},
+					},
+					Order: ord,
+				}
+				oid := ord.ID()
+				if j == nOrders/2 {
+					offset = oid
+				}
+				stamp := uint64Bytes(start + uint64(j)*100)
+				err := indexedOrderUpdate(tx, oid, func(ob, archivedOB *bbolt.Bucket) error {
+					if err := putOrder(ob, archivedOB, mord); err != nil {
+						return err
+					}
+					return findOrderBucket(ob, archivedOB, oid[:]).Put(updateTimeKey, stamp)
+				})
+				if err != nil {
+					return err
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			b.Fatalf("error inserting orders: %v", err)
 		}
 	}
+
+	tests := []struct {
+		name   string
+		filter *db.OrderFilter
+	}{
+		{
+			name:   "newest",
+			filter: &db.OrderFilter{N: 50},
+		},
+		{
+			name:   "offset",
+			filter: &db.OrderFilter{N: 50, Offset: offset},
+		},
+		{
+			name:   "host",
+			filter: &db.OrderFilter{N: 50, Hosts: hosts[1:2]},
+		},
+		{
+			name:   "market",
+			filter: &db.OrderFilter{N: 50, Market: &db.OrderFilterMarket{Base: 60, Quote: 0}},
+		},
+		{
+			name:   "booked",
+			filter: &db.OrderFilter{N: 50, Statuses: []order.OrderStatus{order.OrderStatusBooked}},
+		},
+		{
+			name: "host+market+offset",
+			filter: &db.OrderFilter{
+				N:      50,
+				Hosts:  hosts[:1],
+				Market: &db.OrderFilterMarket{Base: 42, Quote: 0},
+				Offset: offset,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := boltdb.Orders(test.filter); err != nil {
+					b.Fatalf("Orders error: %v", err)
+				}
+			}
+		})
+	}
 }
 
 func TestIncludePartialFilter(t *testing.T) {
@@ -1667,6 +1840,18 @@ func TestDeleteInactiveOrders(t *testing.T) {
 	if numArchivedOrders != numUntouched {
 		t.Fatalf("expected %d archived orders left after deletion but got %d", numUntouched, numArchivedOrders)
 	}
+
+	// Deleted orders should be removed from the indexes.
+	var numIndexed int
+	if err = boltdb.View(func(tx *bbolt.Tx) error {
+		numIndexed = tx.Bucket(orderIndexesBucket).Bucket(timeIndexBucket).Stats().KeyN
+		return nil
+	}); err != nil {
+		t.Fatalf("unable to count indexed orders: %v", err)
+	}
+	if numIndexed != numActive+numUntouched {
+		t.Fatalf("expected %d indexed orders after deletion but got %d", numActive+numUntouched, numIndexed)
+	}
 }
 
 func TestOrderSide(t *testing.T) {
@@ -1937,3 +2122,206 @@ func TestPruneMMEpochSnapshots(t *testing.T) {
 		t.Fatalf("expected 0 pruned on missing host, got %d", n)
 	}
 }
+
+func TestWebhooks(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	wh := &db.Webhook{
+		ID:        "abc",
+		URL:       "https://example.com/hook",
+		Secret:    "shh",
+		Topics:    []db.Topic{"MatchErrorCoin"},
+		NoteTypes: []string{"bot"},
+	}
+	if err := boltdb.UpdateWebhook(wh); err != nil {
+		t.Fatalf("UpdateWebhook error: %v", err)
+	}
+	if err := boltdb.UpdateWebhook(&db.Webhook{URL: "https://example.com"}); err == nil {
+		t.Fatal("no error for webhook without ID")
+	}
+
+	whs, err := boltdb.Webhooks()
+	if err != nil {
+		t.Fatalf("Webhooks error: %v", err)
+	}
+	if len(whs) != 1 {
+		t.Fatalf("expected 1 webhook, got %d", len(whs))
+	}
+	if whs[0].URL != wh.URL || whs[0].Secret != wh.Secret || len(whs[0].Topics) != 1 || len(whs[0].NoteTypes) != 1 {
+		t.Fatalf("wrong webhook loaded: %+v", whs[0])
+	}
+
+	wh.Disabled = true
+	if err := boltdb.UpdateWebhook(wh); err != nil {
+		t.Fatalf("UpdateWebhook (overwrite) error: %v", err)
+	}
+	whs, _ = boltdb.Webhooks()
+	if len(whs) != 1 || !whs[0].Disabled {
+		t.Fatalf("webhook not updated")
+	}
+
+	if err := boltdb.DeleteWebhook(wh.ID); err != nil {
+		t.Fatalf("DeleteWebhook error: %v", err)
+	}
+	if err := boltdb.DeleteWebhook(wh.ID); err == nil {
+		t.Fatal("no error deleting unknown webhook")
+	}
+	whs, _ = boltdb.Webhooks()
+	if len(whs) != 0 {
+		t.Fatalf("webhook not deleted")
+	}
+}
+
+func TestPriceAlerts(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	alert := &db.PriceAlert{
+		ID:        "abc",
+		Source:    db.PriceAlertMidGap,
+		Host:      "somedex.tld:7232",
+		BaseID:    42,
+		QuoteID:   0,
+		Condition: db.PriceAlertAbove,
+		Threshold: 0.00025,
+		Rearm:     true,
+	}
+	if err := boltdb.UpdatePriceAlert(alert); err != nil {
+		t.Fatalf("UpdatePriceAlert error: %v", err)
+	}
+	if err := boltdb.UpdatePriceAlert(&db.PriceAlert{}); err == nil {
+		t.Fatal("no error for price alert without ID")
+	}
+
+	alert.Triggered = true
+	if err := boltdb.UpdatePriceAlert(alert); err != nil {
+		t.Fatalf("UpdatePriceAlert (overwrite) error: %v", err)
+	}
+
+	alerts, err := boltdb.PriceAlerts()
+	if err != nil {
+		t.Fatalf("PriceAlerts error: %v", err)
+	}
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 price alert, got %d", len(alerts))
+	}
+	if a := alerts[0]; a.Host != alert.Host || a.Threshold != alert.Threshold || !a.Triggered || a.Condition != db.PriceAlertAbove {
+		t.Fatalf("wrong price alert loaded: %+v", a)
+	}
+
+	if err := boltdb.DeletePriceAlert(alert.ID); err != nil {
+		t.Fatalf("DeletePriceAlert error: %v", err)
+	}
+	if err := boltdb.DeletePriceAlert(alert.ID); err == nil {
+		t.Fatal("no error deleting unknown price alert")
+	}
+	if alerts, _ = boltdb.PriceAlerts(); len(alerts) != 0 {
+		t.Fatal("price alert not deleted")
+	}
+}
+
+func TestLabelsAndContacts(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	txLabel := &db.Label{AssetID: 42, Kind: db.LabelTx, ID: "abc", Label: "rent"}
+	addrLabel := &db.Label{AssetID: 42, Kind: db.LabelAddress, ID: "Dsabc", Label: "landlord"}
+	otherAsset := &db.Label{AssetID: 0, Kind: db.LabelTx, ID: "abc", Label: "btc"}
+	for _, l := range []*db.Label{txLabel, addrLabel, otherAsset} {
+		if err := boltdb.SetLabel(l); err != nil {
+			t.Fatalf("SetLabel error: %v", err)
+		}
+	}
+	if err := boltdb.SetLabel(&db.Label{AssetID: 42, Kind: "bad", ID: "abc", Label: "x"}); err == nil {
+		t.Fatal("no error for unknown label kind")
+	}
+	labels, err := boltdb.Labels(42)
+	if err != nil {
+		t.Fatalf("Labels error: %v", err)
+	}
+	if len(labels) != 2 {
+		t.Fatalf("expected 2 labels, got %d", len(labels))
+	}
+	// An empty label deletes.
+	if err := boltdb.SetLabel(&db.Label{AssetID: 42, Kind: db.LabelTx, ID: "abc"}); err != nil {
+		t.Fatalf("SetLabel (delete) error: %v", err)
+	}
+	labels, _ = boltdb.Labels(42)
+	if len(labels) != 1 || labels[0].Label != "landlord" {
+		t.Fatalf("label not deleted")
+	}
+
+	alice := &db.Contact{AssetID: 42, Name: "Alice", Address: "Dsabc"}
+	if err := boltdb.UpdateContact(alice); err != nil {
+		t.Fatalf("UpdateContact error: %v", err)
+	}
+	if err := boltdb.UpdateContact(&db.Contact{AssetID: 42, Address: "Dsabc"}); err == nil {
+		t.Fatal("no error for contact without name")
+	}
+	if err := boltdb.UpdateContact(&db.Contact{AssetID: 0, Name: "alice", Address: "bc1abc"}); err != nil {
+		t.Fatalf("UpdateContact error: %v", err)
+	}
+	// Names are case-insensitive.
+	if err := boltdb.UpdateContact(&db.Contact{AssetID: 42, Name: "ALICE", Address: "Dsdef"}); err != nil {
+		t.Fatalf("UpdateContact (overwrite) error: %v", err)
+	}
+	contacts, err := boltdb.Contacts(42)
+	if err != nil {
+		t.Fatalf("Contacts error: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Address != "Dsdef" {
+		t.Fatalf("wrong contacts %+v", contacts)
+	}
+	if err := boltdb.DeleteContact(42, "alice"); err != nil {
+		t.Fatalf("DeleteContact error: %v", err)
+	}
+	if err := boltdb.DeleteContact(42, "alice"); err == nil {
+		t.Fatal("no error deleting unknown contact")
+	}
+	if contacts, _ = boltdb.Contacts(0); len(contacts) != 1 {
+		t.Fatalf("wrong contacts for other asset")
+	}
+}
+
+func TestBridgeTrades(t *testing.T) {
+	boltdb, shutdown := newTestDB(t)
+	defer shutdown()
+
+	bt := &db.BridgeTrade{
+		ID:                "abc",
+		BridgeFromAssetID: 966001,
+		BridgeToAssetID:   60001,
+		BridgeName:        "usdc",
+		BridgeAmount:      1e6,
+		Host:              "somedex.tld:7232",
+		Base:              42,
+		Quote:             60001,
+		Step:              db.BridgeTradeStepBridge,
+		Status:            db.BridgeTradeActive,
+		EstimatedFees:     map[uint32]uint64{966: 1e5},
+	}
+	if err := boltdb.UpdateBridgeTrade(bt); err != nil {
+		t.Fatalf("UpdateBridgeTrade error: %v", err)
+	}
+	if err := boltdb.UpdateBridgeTrade(&db.BridgeTrade{}); err == nil {
+		t.Fatal("no error for bridge trade without ID")
+	}
+
+	bt.Step = db.BridgeTradeStepTrade
+	bt.BridgeTxID = "0x1234"
+	if err := boltdb.UpdateBridgeTrade(bt); err != nil {
+		t.Fatalf("UpdateBridgeTrade (overwrite) error: %v", err)
+	}
+
+	bts, err := boltdb.BridgeTrades()
+	if err != nil {
+		t.Fatalf("BridgeTrades error: %v", err)
+	}
+	if len(bts) != 1 {
+		t.Fatalf("expected 1 bridge trade, got %d", len(bts))
+	}
+	if b := bts[0]; b.Step != db.BridgeTradeStepTrade || b.BridgeTxID != bt.BridgeTxID || b.EstimatedFees[966] != 1e5 {
+		t.Fatalf("wrong bridge trade loaded: %+v", b)
+	}
+}
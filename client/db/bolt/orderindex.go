@@ -0,0 +1,391 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package bolt
+
+import (
+	"bytes"
+	"fmt"
+
+	dexdb "decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/dex/order"
+	"go.etcd.io/bbolt"
+)
+
+// The orderIndexesBucket holds secondary indexes of the orders in both the
+// active and archived order buckets. Each index is a bucket of empty values
+// keyed by the order's update time followed by the order ID, so a reverse
+// cursor iterates newest first. The host, market and status indexes have a
+// sub-bucket for each host, market and status.
+var (
+	timeIndexBucket   = []byte("time")
+	hostIndexBucket   = []byte("host")
+	marketIndexBucket = []byte("market")
+	statusIndexBucket = []byte("status")
+)
+
+// orderIndexEntry is the indexed fields of an order.
+type orderIndexEntry struct {
+	stamp  uint64
+	host   string
+	base   uint32
+	quote  uint32
+	status order.OrderStatus
+}
+
+// orderIndexEntryFromBucket reads the indexed fields from the order's bucket.
+// The entry is nil if there is no bucket.
+func orderIndexEntryFromBucket(oBkt *bbolt.Bucket) *orderIndexEntry {
+	if oBkt == nil {
+		return nil
+	}
+	e := &orderIndexEntry{host: string(oBkt.Get(dexKey))}
+	if b := oBkt.Get(updateTimeKey); len(b) == 8 {
+		e.stamp = intCoder.Uint64(b)
+	}
+	if b := oBkt.Get(baseKey); len(b) == 4 {
+		e.base = intCoder.Uint32(b)
+	}
+	if b := oBkt.Get(quoteKey); len(b) == 4 {
+		e.quote = intCoder.Uint32(b)
+	}
+	if b := oBkt.Get(statusKey); len(b) == 2 {
+		e.status = order.OrderStatus(intCoder.Uint16(b))
+	}
+	return e
+}
+
+// orderIndexKey is the key of an order in an index bucket.
+func orderIndexKey(stamp uint64, oid []byte) []byte {
+	return append(uint64Bytes(stamp), oid...)
+}
+
+// marketIndexKey is the key of a market's sub-bucket of the market index.
+func marketIndexKey(base, quote uint32) []byte {
+	return append(uint32Bytes(base), uint32Bytes(quote)...)
+}
+
+// indexBuckets returns the index buckets that the entry belongs in. If create
+// is true, missing buckets are created. Otherwise, missing buckets are omitted.
+func (e *orderIndexEntry) indexBuckets(idx *bbolt.Bucket, create bool) ([]*bbolt.Bucket, error) {
+	bkts := make([]*bbolt.Bucket, 0, 4)
+	add := func(parent *bbolt.Bucket, k []byte) error {
+		if parent == nil {
+			return nil
+		}
+		if !create {
+			if bkt := parent.Bucket(k); bkt != nil {
+				bkts = append(bkts, bkt)
+			}
+			return nil
+		}
+		bkt, err := parent.CreateBucketIfNotExists(k)
+		if err != nil {
+			return fmt.Errorf("error creating %q index bucket: %w", k, err)
+		}
+		bkts = append(bkts, bkt)
+		return nil
+	}
+	if err := add(idx, timeIndexBucket); err != nil {
+		return nil, err
+	}
+	parents := []struct {
+		parentKey, key []byte
+	}{
+		{hostIndexBucket, []byte(e.host)},
+		{marketIndexBucket, marketIndexKey(e.base, e.quote)},
+		{statusIndexBucket, uint16Bytes(uint16(e.status))},
+	}
+	for _, p := range parents {
+		if len(p.key) == 0 {
+			continue
+		}
+		parent := idx.Bucket(p.parentKey)
+		if parent == nil && create {
+			var err error
+			if parent, err = idx.CreateBucket(p.parentKey); err != nil {
+				return nil, fmt.Errorf("error creating %q index bucket: %w", p.parentKey, err)
+			}
+		}
+		if err := add(parent, p.key); err != nil {
+			return nil, err
+		}
+	}
+	return bkts, nil
+}
+
+// putOrderIndex adds the order to the indexes.
+func putOrderIndex(idx *bbolt.Bucket, oid []byte, e *orderIndexEntry) error {
+	bkts, err := e.indexBuckets(idx, true)
+	if err != nil {
+		return err
+	}
+	k := orderIndexKey(e.stamp, oid)
+	for _, bkt := range bkts {
+		if err := bkt.Put(k, []byte{}); err != nil {
+			return fmt.Errorf("error indexing order %x: %w", oid, err)
+		}
+	}
+	return nil
+}
+
+// deleteOrderIndex removes the order from the indexes.
+func deleteOrderIndex(idx *bbolt.Bucket, oid []byte, e *orderIndexEntry) error {
+	bkts, err := e.indexBuckets(idx, false)
+	if err != nil {
+		return err
+	}
+	k := orderIndexKey(e.stamp, oid)
+	for _, bkt := range bkts {
+		if err := bkt.Delete(k); err != nil {
+			return fmt.Errorf("error removing order %x from index: %w", oid, err)
+		}
+	}
+	return nil
+}
+
+// findOrderBucket returns the order's bucket from the active or archived
+// orders bucket, or nil if the order is not found.
+func findOrderBucket(ob, archivedOB *bbolt.Bucket, oid []byte) *bbolt.Bucket {
+	if oBkt := ob.Bucket(oid); oBkt != nil {
+		return oBkt
+	}
+	return archivedOB.Bucket(oid)
+}
+
+// indexedOrderUpdate runs f with the order buckets and then updates the
+// order's indexes to match any changes f made to the order. Any change to an
+// order's host, market, status or update time must be made through
+// indexedOrderUpdate.
+func indexedOrderUpdate(tx *bbolt.Tx, oid order.OrderID, f func(ob, archivedOB *bbolt.Bucket) error) error {
+	ob := tx.Bucket(activeOrdersBucket)
+	if ob == nil {
+		return fmt.Errorf("failed to open %s bucket", string(activeOrdersBucket))
+	}
+	archivedOB := tx.Bucket(archivedOrdersBucket)
+	if archivedOB == nil {
+		return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+	}
+	idx := tx.Bucket(orderIndexesBucket)
+	if idx == nil {
+		return fmt.Errorf("failed to open %s bucket", string(orderIndexesBucket))
+	}
+	before := orderIndexEntryFromBucket(findOrderBucket(ob, archivedOB, oid[:]))
+	if err := f(ob, archivedOB); err != nil {
+		return err
+	}
+	after := orderIndexEntryFromBucket(findOrderBucket(ob, archivedOB, oid[:]))
+	if before != nil && after != nil && *before == *after {
+		return nil
+	}
+	if before != nil {
+		if err := deleteOrderIndex(idx, oid[:], before); err != nil {
+			return err
+		}
+	}
+	if after == nil {
+		return nil
+	}
+	return putOrderIndex(idx, oid[:], after)
+}
+
+// orderUpdate is like ordersUpdate, but for changes to a single order that may
+// change its indexed fields.
+func (db *BoltDB) orderUpdate(oid order.OrderID, f func(ob, archivedOB *bbolt.Bucket) error) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		return indexedOrderUpdate(tx, oid, f)
+	})
+}
+
+// buildOrderIndexes indexes every order in the active and archived order
+// buckets.
+func buildOrderIndexes(tx *bbolt.Tx) (int, error) {
+	idx, err := tx.CreateBucketIfNotExists(orderIndexesBucket)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, bktKey := range [][]byte{activeOrdersBucket, archivedOrdersBucket} {
+		master := tx.Bucket(bktKey)
+		if master == nil {
+			return 0, fmt.Errorf("failed to open %s bucket", string(bktKey))
+		}
+		err := master.ForEach(func(oid, _ []byte) error {
+			oBkt := master.Bucket(oid)
+			if oBkt == nil {
+				return fmt.Errorf("order %x bucket is not a bucket", oid)
+			}
+			n++
+			return putOrderIndex(idx, oid, orderIndexEntryFromBucket(oBkt))
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return n, nil
+}
+
+// orderIndexQuery describes a search of the order indexes.
+type orderIndexQuery struct {
+	// buckets selects the index buckets to search. Orders in any of the
+	// buckets are candidates.
+	buckets func(idx *bbolt.Bucket) []*bbolt.Bucket
+	// n is the maximum number of orders to return. 0 is no limit.
+	n int
+	// since excludes orders with an update time before since.
+	since uint64
+	// before, if set, excludes orders with an update time after before.
+	before *uint64
+	// filter is applied to each candidate order's bucket.
+	filter func(oidB []byte, oBkt *bbolt.Bucket) bool
+}
+
+// indexedOrders returns the orders matching the query, newest first. Orders
+// with the same update time are sorted by descending order ID.
+func (db *BoltDB) indexedOrders(q *orderIndexQuery) ([]*dexdb.MetaOrder, error) {
+	var orders []*dexdb.MetaOrder
+	return orders, db.View(func(tx *bbolt.Tx) error {
+		ob := tx.Bucket(activeOrdersBucket)
+		if ob == nil {
+			return fmt.Errorf("failed to open %s bucket", string(activeOrdersBucket))
+		}
+		archivedOB := tx.Bucket(archivedOrdersBucket)
+		if archivedOB == nil {
+			return fmt.Errorf("failed to open %s bucket", string(archivedOrdersBucket))
+		}
+		idx := tx.Bucket(orderIndexesBucket)
+		if idx == nil {
+			return fmt.Errorf("failed to open %s bucket", string(orderIndexesBucket))
+		}
+
+		var seek []byte
+		if q.before != nil {
+			seek = uint64Bytes(*q.before + 1)
+		}
+		curs := newIndexCursors(q.buckets(idx), seek)
+		sinceB := uint64Bytes(q.since)
+		for k := curs.next(); k != nil; k = curs.next() {
+			if bytes.Compare(k[:8], sinceB) < 0 {
+				// All remaining orders are older.
+				return nil
+			}
+			oid := k[8:]
+			oBkt := findOrderBucket(ob, archivedOB, oid)
+			if oBkt == nil {
+				db.log.Warnf("indexed order %x not found", oid)
+				continue
+			}
+			if q.filter != nil && !q.filter(oid, oBkt) {
+				continue
+			}
+			o, err := decodeOrderBucket(oid, oBkt)
+			if err != nil {
+				return err
+			}
+			orders = append(orders, o)
+			if q.n > 0 && len(orders) >= q.n {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// indexCursors merges the keys of several index buckets in descending order.
+type indexCursors struct {
+	cursors []*bbolt.Cursor
+	keys    [][]byte
+}
+
+// newIndexCursors positions a cursor in each bucket at the last key. If seek is
+// non-nil, the cursors are instead positioned at the last key before seek.
+func newIndexCursors(bkts []*bbolt.Bucket, seek []byte) *indexCursors {
+	ic := &indexCursors{
+		cursors: make([]*bbolt.Cursor, 0, len(bkts)),
+		keys:    make([][]byte, 0, len(bkts)),
+	}
+	for _, bkt := range bkts {
+		if bkt == nil {
+			continue
+		}
+		c := bkt.Cursor()
+		var k []byte
+		if seek == nil {
+			k, _ = c.Last()
+		} else if k, _ = c.Seek(seek); k == nil {
+			k, _ = c.Last()
+		} else {
+			k, _ = c.Prev()
+		}
+		ic.cursors = append(ic.cursors, c)
+		ic.keys = append(ic.keys, k)
+	}
+	return ic
+}
+
+// next returns the largest remaining key, or nil when all cursors are
+// exhausted. A key that is in more than one bucket is only returned once.
+func (ic *indexCursors) next() []byte {
+	var best []byte
+	for _, k := range ic.keys {
+		if k != nil && (best == nil || bytes.Compare(k, best) > 0) {
+			best = k
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	best = bytes.Clone(best)
+	for i, k := range ic.keys {
+		if k != nil && bytes.Equal(k, best) {
+			ic.keys[i], _ = ic.cursors[i].Prev()
+		}
+	}
+	return best
+}
+
+// timeIndex returns the index of all orders.
+func timeIndex(idx *bbolt.Bucket) []*bbolt.Bucket {
+	return []*bbolt.Bucket{idx.Bucket(timeIndexBucket)}
+}
+
+// hostIndexes returns the indexes of the orders for the hosts.
+func hostIndexes(hosts ...string) func(idx *bbolt.Bucket) []*bbolt.Bucket {
+	return func(idx *bbolt.Bucket) []*bbolt.Bucket {
+		parent := idx.Bucket(hostIndexBucket)
+		if parent == nil {
+			return nil
+		}
+		bkts := make([]*bbolt.Bucket, 0, len(hosts))
+		for _, host := range hosts {
+			bkts = append(bkts, parent.Bucket([]byte(host)))
+		}
+		return bkts
+	}
+}
+
+// marketIndex returns the index of the orders for the market.
+func marketIndex(base, quote uint32) func(idx *bbolt.Bucket) []*bbolt.Bucket {
+	return func(idx *bbolt.Bucket) []*bbolt.Bucket {
+		parent := idx.Bucket(marketIndexBucket)
+		if parent == nil {
+			return nil
+		}
+		return []*bbolt.Bucket{parent.Bucket(marketIndexKey(base, quote))}
+	}
+}
+
+// statusIndexes returns the indexes of the orders with the statuses.
+func statusIndexes(statuses ...order.OrderStatus) func(idx *bbolt.Bucket) []*bbolt.Bucket {
+	return func(idx *bbolt.Bucket) []*bbolt.Bucket {
+		parent := idx.Bucket(statusIndexBucket)
+		if parent == nil {
+			return nil
+		}
+		bkts := make([]*bbolt.Bucket, 0, len(statuses))
+		for _, status := range statuses {
+			bkts = append(bkts, parent.Bucket(uint16Bytes(uint16(status))))
+		}
+		return bkts
+	}
+}
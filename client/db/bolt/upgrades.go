@@ -45,6 +45,9 @@ var upgrades = [...]upgradefunc{
 	// v6 => v7 sets the status of all refunded matches to order.MatchConfirmed
 	// status.
 	v7Upgrade,
+	// v7 => v8 builds secondary indexes of orders by host, market, status and
+	// update time.
+	v8Upgrade,
 }
 
 // DBVersion is the latest version of the database that is understood. Databases
@@ -431,6 +434,22 @@ func v7Upgrade(dbtx *bbolt.Tx) error {
 	})
 }
 
+// v8Upgrade builds the order indexes from the active and archived orders.
+func v8Upgrade(dbtx *bbolt.Tx) error {
+	const oldVersion = 7
+
+	if err := ensureVersion(dbtx, oldVersion); err != nil {
+		return err
+	}
+
+	n, err := buildOrderIndexes(dbtx)
+	if err != nil {
+		return fmt.Errorf("error building order indexes: %w", err)
+	}
+	upgradeLog.Infof("indexed %d orders", n)
+	return nil
+}
+
 func ensureVersion(tx *bbolt.Tx, ver uint32) error {
 	dbVersion, err := getVersionTx(tx)
 	if err != nil {
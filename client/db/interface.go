@@ -179,4 +179,35 @@ type DB interface {
 	// PruneMMEpochSnapshots deletes MM epoch snapshots for a market with
 	// epochIdx strictly less than minEpochIdx, returning the number deleted.
 	PruneMMEpochSnapshots(host string, base, quote uint32, minEpochIdx uint64) (int, error)
+	// UpdateWebhook stores the webhook, overwriting any existing webhook
+	// with the same ID.
+	UpdateWebhook(wh *Webhook) error
+	// DeleteWebhook deletes the webhook with the specified ID.
+	DeleteWebhook(id string) error
+	// Webhooks retrieves all stored webhooks.
+	Webhooks() ([]*Webhook, error)
+	// UpdatePriceAlert stores the price alert, overwriting any existing alert
+	// with the same ID.
+	UpdatePriceAlert(alert *PriceAlert) error
+	// DeletePriceAlert deletes the price alert with the specified ID.
+	DeletePriceAlert(id string) error
+	// PriceAlerts retrieves all stored price alerts.
+	PriceAlerts() ([]*PriceAlert, error)
+	// SetLabel stores the label, replacing any existing label for the same
+	// asset, kind and ID. A label with an empty Label string is deleted.
+	SetLabel(label *Label) error
+	// Labels retrieves all labels for the asset.
+	Labels(assetID uint32) ([]*Label, error)
+	// UpdateContact stores the contact, overwriting any existing contact with
+	// the same asset and name.
+	UpdateContact(contact *Contact) error
+	// DeleteContact deletes the asset's contact with the specified name.
+	DeleteContact(assetID uint32, name string) error
+	// Contacts retrieves the asset's address book.
+	Contacts(assetID uint32) ([]*Contact, error)
+	// UpdateBridgeTrade stores the bridge trade, overwriting any existing
+	// bridge trade with the same ID.
+	UpdateBridgeTrade(bt *BridgeTrade) error
+	// BridgeTrades retrieves all stored bridge trades.
+	BridgeTrades() ([]*BridgeTrade, error)
 }
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	mrand "math/rand/v2"
+	"slices"
 	"strings"
 	"time"
 
@@ -41,6 +42,8 @@ func RandomAccountInfo() *db.AccountInfo {
 		TargetTier:       uint64(rand.IntN(34)),
 		MaxBondedAmt:     uint64(rand.IntN(40e8)),
 		BondAsset:        uint32(rand.IntN(66)),
+		BondAssets:       []uint32{uint32(rand.IntN(66)), uint32(rand.IntN(66))},
+		SplitBonds:       rand.IntN(2) == 1,
 		LegacyFeeAssetID: uint32(rand.IntN(64)),
 		LegacyFeeCoin:    randBytes(32),
 		Cert:             randBytes(100),
@@ -273,6 +276,12 @@ func MustCompareAccountInfo(t testKiller, a1, a2 *db.AccountInfo) {
 	if !bytes.Equal(a1.LegacyFeeCoin, a2.LegacyFeeCoin) {
 		t.Fatalf("EncKey mismatch. %x != %x", a1.LegacyFeeCoin, a2.LegacyFeeCoin)
 	}
+	if !slices.Equal(a1.BondAssets, a2.BondAssets) {
+		t.Fatalf("BondAssets mismatch. %v != %v", a1.BondAssets, a2.BondAssets)
+	}
+	if a1.SplitBonds != a2.SplitBonds {
+		t.Fatalf("SplitBonds mismatch. %t != %t", a1.SplitBonds, a2.SplitBonds)
+	}
 }
 
 // MustCompareOrderProof ensures the two OrderProof are identical, calling the
@@ -44,6 +44,7 @@ const (
 const (
 	ErrNoCredentials = dex.ErrorKind("no credentials have been stored")
 	ErrAcctNotFound  = dex.ErrorKind("account not found")
+	ErrOrderNotFound = dex.ErrorKind("order not found")
 	ErrNoSeedGenTime = dex.ErrorKind("seed generation time has not been stored")
 )
 
@@ -232,7 +233,11 @@ type AccountInfo struct {
 	MaxBondedAmt uint64
 	PenaltyComps uint16
 	BondAsset    uint32 // the asset to use when auto-posting bonds
-	Disabled     bool   // whether the account is disabled
+	// BondAssets is an ordered preference list of assets that the bond
+	// renewal planner may choose from. Empty means always use BondAsset.
+	BondAssets []uint32
+	SplitBonds bool // whether the planner may split the tier across assets
+	Disabled   bool // whether the account is disabled
 
 	// DEPRECATED reg fee data. Bond txns are in a sub-bucket.
 	// Left until we need to upgrade just for serialization simplicity.
@@ -245,7 +250,15 @@ type AccountInfo struct {
 // DB upgrade at some point. But how to deal with old accounts needing to store
 // this data forever?
 func (ai *AccountInfo) Encode() []byte {
-	return versionedBytes(4).
+	bondAssetsB := make([]byte, 0, 4*len(ai.BondAssets))
+	for _, assetID := range ai.BondAssets {
+		bondAssetsB = append(bondAssetsB, encode.Uint32Bytes(assetID)...)
+	}
+	splitB := encode.ByteFalse
+	if ai.SplitBonds {
+		splitB = encode.ByteTrue
+	}
+	return versionedBytes(5).
 		AddData([]byte(ai.Host)).
 		AddData(ai.Cert).
 		AddData(ai.DEXPubKey.SerializeCompressed()).
@@ -256,7 +269,9 @@ func (ai *AccountInfo) Encode() []byte {
 		AddData(encode.Uint32Bytes(ai.BondAsset)).
 		AddData(encode.Uint32Bytes(ai.LegacyFeeAssetID)).
 		AddData(ai.LegacyFeeCoin).
-		AddData(encode.Uint16Bytes(ai.PenaltyComps))
+		AddData(encode.Uint16Bytes(ai.PenaltyComps)).
+		AddData(bondAssetsB).
+		AddData(splitB)
 }
 
 // ViewOnly is true if account keys are not saved.
@@ -290,6 +305,8 @@ func DecodeAccountInfo(b []byte) (*AccountInfo, error) {
 		return decodeAccountInfo_v3(pushes)
 	case 4:
 		return decodeAccountInfo_v4(pushes)
+	case 5:
+		return decodeAccountInfo_v5(pushes)
 	}
 	return nil, fmt.Errorf("unknown AccountInfo version %d", ver)
 }
@@ -355,7 +372,23 @@ func decodeAccountInfo_v3(pushes [][]byte) (*AccountInfo, error) {
 
 func decodeAccountInfo_v4(pushes [][]byte) (*AccountInfo, error) {
 	if len(pushes) != 11 {
-		return nil, fmt.Errorf("decodeAccountInfo: expected 11 data pushes, got %d", len(pushes))
+		return nil, fmt.Errorf("decodeAccountInfo_v4: expected 11 data pushes, got %d", len(pushes))
+	}
+	pushes = append(pushes, nil, encode.ByteFalse) // BondAssets, SplitBonds
+	return decodeAccountInfo_v5(pushes)
+}
+
+func decodeAccountInfo_v5(pushes [][]byte) (*AccountInfo, error) {
+	if len(pushes) != 13 {
+		return nil, fmt.Errorf("decodeAccountInfo_v5: expected 13 data pushes, got %d", len(pushes))
+	}
+	bondAssetsB, splitB := pushes[11], pushes[12] // bond planner options
+	if len(bondAssetsB)%4 != 0 {
+		return nil, fmt.Errorf("decodeAccountInfo_v5: invalid bond assets length %d", len(bondAssetsB))
+	}
+	var bondAssets []uint32
+	for i := 0; i < len(bondAssetsB); i += 4 {
+		bondAssets = append(bondAssets, intCoder.Uint32(bondAssetsB[i:i+4]))
 	}
 	hostB, certB, dexPkB := pushes[0], pushes[1], pushes[2]                // dex identity
 	v2Key, legacyKeyB := pushes[3], pushes[4]                              // account identity
@@ -376,6 +409,8 @@ func decodeAccountInfo_v4(pushes [][]byte) (*AccountInfo, error) {
 		MaxBondedAmt:     intCoder.Uint64(maxBondedB),
 		PenaltyComps:     intCoder.Uint16(penaltyComps),
 		BondAsset:        intCoder.Uint32(bondAssetB),
+		BondAssets:       bondAssets,
+		SplitBonds:       bytes.Equal(splitB, encode.ByteTrue),
 		LegacyFeeAssetID: intCoder.Uint32(regAssetB),
 		LegacyFeeCoin:    coinB, // NOTE: no longer in current serialization.
 		// LegacyFeePaid comes from AccountProof.
@@ -479,6 +514,10 @@ type OrderMetaData struct {
 	// AccelerationCoins keeps track of all the change coins generated from doing
 	// accelerations on this order.
 	AccelerationCoins []order.CoinID
+	// FiatRates are the fiat exchange rates of the order's assets and their
+	// fee assets when the order was placed, keyed by asset ID. Assets without
+	// a rate are omitted, and older orders have none.
+	FiatRates map[uint32]float64
 }
 
 // MetaMatch is a match and its metadata.
@@ -1237,3 +1276,182 @@ func hashKey(b []byte) []byte {
 	h := blake2s.Sum256(b)
 	return h[:]
 }
+
+// Webhook is a user-configured HTTP endpoint that receives notifications.
+type Webhook struct {
+	// ID is a unique identifier for the webhook.
+	ID string `json:"id"`
+	// URL is the endpoint that notifications are POSTed to.
+	URL string `json:"url"`
+	// Secret is the key used to HMAC-sign the payloads.
+	Secret string `json:"secret"`
+	// Topics limits delivery to notifications with one of these topics.
+	Topics []Topic `json:"topics,omitempty"`
+	// NoteTypes limits delivery to notifications with one of these types.
+	NoteTypes []string `json:"noteTypes,omitempty"`
+	// Disabled webhooks are stored but receive no deliveries.
+	Disabled bool `json:"disabled"`
+}
+
+// PriceAlertSource is the source of the price that a PriceAlert monitors.
+type PriceAlertSource string
+
+const (
+	// PriceAlertMidGap alerts monitor a market's book mid-gap rate.
+	PriceAlertMidGap PriceAlertSource = "midgap"
+	// PriceAlertFiat alerts monitor an asset's fiat exchange rate.
+	PriceAlertFiat PriceAlertSource = "fiat"
+)
+
+// PriceAlertCondition is the condition that triggers a PriceAlert.
+type PriceAlertCondition string
+
+const (
+	// PriceAlertAbove triggers when the price is at or above the threshold.
+	PriceAlertAbove PriceAlertCondition = "above"
+	// PriceAlertBelow triggers when the price is at or below the threshold.
+	PriceAlertBelow PriceAlertCondition = "below"
+	// PriceAlertChange triggers when the price has changed by the threshold
+	// percentage over the window. A negative threshold is a drop.
+	PriceAlertChange PriceAlertCondition = "change"
+)
+
+// PriceAlert is a user-defined alert on a market or fiat price.
+type PriceAlert struct {
+	ID     string           `json:"id"`
+	Source PriceAlertSource `json:"source"`
+	// Host, BaseID and QuoteID identify the market for PriceAlertMidGap.
+	Host    string `json:"host,omitempty"`
+	BaseID  uint32 `json:"baseID"`
+	QuoteID uint32 `json:"quoteID"`
+	// AssetID identifies the asset for PriceAlertFiat.
+	AssetID   uint32              `json:"assetID"`
+	Condition PriceAlertCondition `json:"condition"`
+	// Threshold is a conventional rate for a PriceAlertMidGap alert, a fiat
+	// price for a PriceAlertFiat alert, or a percentage for a
+	// PriceAlertChange condition.
+	Threshold float64 `json:"threshold"`
+	// WindowSecs is the look-back period for a PriceAlertChange condition.
+	WindowSecs uint64 `json:"windowSecs,omitempty"`
+	// Rearm re-arms the alert once its condition is no longer met. Alerts
+	// that do not re-arm only trigger once.
+	Rearm bool `json:"rearm"`
+	// Triggered is true if the alert has triggered and has not re-armed.
+	Triggered bool `json:"triggered"`
+	// TriggerStamp is the time of the last trigger, in milliseconds.
+	TriggerStamp uint64 `json:"triggerStamp,omitempty"`
+	// Created is the time the alert was created, in milliseconds.
+	Created uint64 `json:"created"`
+}
+
+// LabelKind is the kind of item annotated by a Label.
+type LabelKind string
+
+const (
+	// LabelTx labels a wallet transaction. The Label ID is the transaction
+	// ID.
+	LabelTx LabelKind = "tx"
+	// LabelAddress labels an address. The Label ID is the address.
+	LabelAddress LabelKind = "address"
+)
+
+// Label is a user-defined annotation of a wallet transaction or address.
+type Label struct {
+	AssetID uint32    `json:"assetID"`
+	Kind    LabelKind `json:"kind"`
+	// ID is the transaction ID or address being labeled.
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// Contact is an entry in an asset's address book.
+type Contact struct {
+	AssetID uint32 `json:"assetID"`
+	// Name is unique, case-insensitively, for the asset.
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Note    string `json:"note,omitempty"`
+}
+
+// BridgeTradeStep is the step a BridgeTrade is on.
+type BridgeTradeStep string
+
+const (
+	// BridgeTradeStepBridge is the bridge leg of the flow.
+	BridgeTradeStepBridge BridgeTradeStep = "bridge"
+	// BridgeTradeStepTrade is the trade leg of the flow.
+	BridgeTradeStepTrade BridgeTradeStep = "trade"
+	// BridgeTradeStepDone indicates that there are no more steps.
+	BridgeTradeStepDone BridgeTradeStep = "done"
+)
+
+// BridgeTradeStatus is the status of a BridgeTrade.
+type BridgeTradeStatus string
+
+const (
+	BridgeTradeActive   BridgeTradeStatus = "active"
+	BridgeTradeComplete BridgeTradeStatus = "complete"
+	BridgeTradeFailed   BridgeTradeStatus = "failed"
+	BridgeTradeCanceled BridgeTradeStatus = "canceled"
+)
+
+// BridgeTrade is a composite workflow that bridges an asset to another chain
+// and trades it, or trades and then bridges the proceeds. The state is stored
+// so that the flow can resume after a restart.
+type BridgeTrade struct {
+	ID string `json:"id"`
+	// TradeFirst is true if the trade is placed before the bridge.
+	TradeFirst bool `json:"tradeFirst"`
+
+	BridgeFromAssetID uint32 `json:"bridgeFromAssetID"`
+	BridgeToAssetID   uint32 `json:"bridgeToAssetID"`
+	BridgeName        string `json:"bridgeName"`
+	// BridgeAmount is the amount to bridge. For trade-first flows, it is set
+	// from the trade proceeds once the trade is complete.
+	BridgeAmount uint64 `json:"bridgeAmount"`
+
+	Host    string            `json:"host"`
+	Base    uint32            `json:"base"`
+	Quote   uint32            `json:"quote"`
+	Sell    bool              `json:"sell"`
+	IsLimit bool              `json:"isLimit"`
+	Rate    uint64            `json:"rate,omitempty"`
+	TifNow  bool              `json:"tifnow,omitempty"`
+	Options map[string]string `json:"options,omitempty"`
+	// Qty is the order quantity. Zero means the quantity is derived from
+	// the bridged amount.
+	Qty uint64 `json:"qty,omitempty"`
+
+	Step   BridgeTradeStep   `json:"step"`
+	Status BridgeTradeStatus `json:"status"`
+	// BridgeInitiating is the time, in milliseconds, that the bridge began
+	// to be initiated. It is stored before the bridge is initiated, so that
+	// an interrupted initiation is found instead of being repeated.
+	BridgeInitiating uint64 `json:"bridgeInitiating,omitempty"`
+	// BridgeTxID is the ID of the bridge initiation transaction.
+	BridgeTxID string `json:"bridgeTxID,omitempty"`
+	// BridgeReceived is the amount received on the destination chain. It is
+	// zero if neither wallet recorded the amount of a bridge after the trade.
+	BridgeReceived uint64 `json:"bridgeReceived,omitempty"`
+	// OrderPlacing is the time, in milliseconds, that the order began to be
+	// placed. It is stored before the order is placed, so that an
+	// interrupted order is found instead of being placed again.
+	OrderPlacing uint64 `json:"orderPlacing,omitempty"`
+	// OrderID is the ID of the order placed for the trade leg.
+	OrderID string `json:"orderID,omitempty"`
+	// TradeReceived is the amount of the trade's to asset received.
+	TradeReceived uint64 `json:"tradeReceived,omitempty"`
+	// Attempts is the number of failed attempts of the current step.
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+
+	// EstimatedFees are the estimated fees of both legs, keyed by the asset
+	// the fees are paid in.
+	EstimatedFees map[uint32]uint64 `json:"estimatedFees,omitempty"`
+	// EstimatedSecs is the estimated duration of the whole flow.
+	EstimatedSecs uint64 `json:"estimatedSecs,omitempty"`
+
+	// Created and Updated are in milliseconds.
+	Created uint64 `json:"created"`
+	Updated uint64 `json:"updated"`
+}
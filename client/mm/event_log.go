@@ -9,6 +9,7 @@ import (
 	"encoding/binary"
 	"encoding/json"
 	"fmt"
+	"io"
 	"sync"
 	"time"
 
@@ -130,6 +131,8 @@ type eventLogDB interface {
 	// including and after the event with the ID will be returned. If
 	// pendingOnly is true, only pending events will be returned.
 	runEvents(startTime int64, mkt *MarketWithHost, n uint64, refID *uint64, pendingOnly bool, filters *RunLogFilters) ([]*MarketMakingEvent, error)
+	// writeTo writes a consistent copy of the database to w.
+	writeTo(w io.Writer) error
 }
 
 // eventUpdate is used to asynchronously add events to the event log.
@@ -745,3 +748,11 @@ func (db *boltEventLogDB) runEvents(startTime int64, mkt *MarketWithHost, n uint
 		return nil
 	})
 }
+
+// writeTo writes a consistent copy of the database to w.
+func (db *boltEventLogDB) writeTo(w io.Writer) error {
+	return db.View(func(tx *bbolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
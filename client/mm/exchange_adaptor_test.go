@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"maps"
 	"math"
 	"reflect"
@@ -108,6 +109,9 @@ func (db *tEventLogDB) runs(n uint64, refStartTime *uint64, refMkt *MarketWithHo
 func (db *tEventLogDB) runOverview(startTime int64, mkt *MarketWithHost) (*MarketMakingRunOverview, error) {
 	return nil, nil
 }
+func (db *tEventLogDB) writeTo(w io.Writer) error {
+	return nil
+}
 func (db *tEventLogDB) runEvents(startTime int64, mkt *MarketWithHost, n uint64, refID *uint64, pendingOnly bool, filters *RunLogFilters) ([]*MarketMakingEvent, error) {
 	return nil, nil
 }
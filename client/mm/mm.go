@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"maps"
 	"os"
 	"slices"
@@ -153,8 +154,11 @@ type MarketMaker struct {
 	core           clientCore
 	defaultCfgPath string
 	eventLogDBPath string
-	eventLogDB     eventLogDB
-	oracle         *priceOracle
+	// eventLogDBMtx guards eventLogDB, which is set by Connect. Methods that
+	// may run concurrently with Connect, such as BackupEventLog, must hold it.
+	eventLogDBMtx sync.RWMutex
+	eventLogDB    eventLogDB
+	oracle        *priceOracle
 
 	defaultCfgMtx sync.RWMutex
 	// defaultCfg is the configuration specified by the file at the path passed
@@ -711,7 +715,9 @@ func (m *MarketMaker) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating event log DB: %v", err)
 	}
+	m.eventLogDBMtx.Lock()
 	m.eventLogDB = eventLogDB
+	m.eventLogDBMtx.Unlock()
 
 	m.oracle = newPriceOracle(m.ctx, m.log.SubLogger("oracle"))
 
@@ -1428,6 +1434,18 @@ func (m *MarketMaker) UpdateRunningBotCfg(cfg *BotConfig, balanceDiffs *BotInven
 	return nil
 }
 
+// BackupEventLog writes a consistent copy of the event log database to w. The
+// MarketMaker must be running.
+func (m *MarketMaker) BackupEventLog(w io.Writer) error {
+	m.eventLogDBMtx.RLock()
+	eventLogDB := m.eventLogDB
+	m.eventLogDBMtx.RUnlock()
+	if eventLogDB == nil {
+		return errors.New("event log database is not open")
+	}
+	return eventLogDB.writeTo(w)
+}
+
 // ArchivedRuns returns all archived market making runs.
 func (m *MarketMaker) ArchivedRuns() ([]*MarketMakingRun, error) {
 	allRuns, err := m.eventLogDB.runs(0, nil, nil)
@@ -1884,14 +1902,14 @@ func marketFees(c clientCore, host string, baseID, quoteID uint32, useMaxFeeRate
 	}
 
 	return &LotFees{
-			Swap:   sellSwapFees,
-			Redeem: buyRedeemFees,
-			Refund: sellRefundFees,
-		}, &LotFees{
-			Swap:   buySwapFees,
-			Redeem: sellRedeemFees,
-			Refund: buyRefundFees,
-		}, nil
+		Swap:   sellSwapFees,
+		Redeem: buyRedeemFees,
+		Refund: sellRefundFees,
+	}, &LotFees{
+		Swap:   buySwapFees,
+		Redeem: sellRedeemFees,
+		Refund: buyRefundFees,
+	}, nil
 }
 
 func (m *MarketMaker) availableBalances(mkt *MarketWithHost, cexBaseID, cexQuoteID uint32, cexCfg *CEXConfig) (dexBalances, cexBalances map[uint32]uint64, _ error) {
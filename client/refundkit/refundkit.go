@@ -0,0 +1,191 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package refundkit reads and writes the emergency refund kit. The kit lists
+// the client's swap contracts that may need to be refunded, with the signed
+// refund transactions where the asset supports them, so that funds can be
+// recovered if the client's database or machine is lost. The kit is encrypted
+// with a key derived from the app password. The key derivation parameters are
+// stored in the file, so the kit can be read with just the app password.
+package refundkit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+const (
+	// FileName is the default name of the refund kit file.
+	FileName = "refundkit.bwrk"
+
+	magic      = "BWRK"
+	kitVersion = 0
+)
+
+// ErrInvalidKit is returned for a file that is not a refund kit, or that is
+// corrupted.
+var ErrInvalidKit = errors.New("invalid refund kit")
+
+// Entry is a swap contract that we funded.
+type Entry struct {
+	Host    string    `json:"host"`
+	OrderID dex.Bytes `json:"orderID"`
+	MatchID dex.Bytes `json:"matchID"`
+	AssetID uint32    `json:"assetID"`
+	Symbol  string    `json:"symbol"`
+	// Value is the contract value, in atoms.
+	Value uint64 `json:"value"`
+	// SwapCoin is the swap coin ID, as a string, e.g. txid:vout. SwapCoinID
+	// is the raw coin ID.
+	SwapCoin   string    `json:"swapCoin"`
+	SwapCoinID dex.Bytes `json:"swapCoinID"`
+	// Contract is the contract data. This is the redeem script for UTXO
+	// assets, or the contract version and secret hash for account-based
+	// assets.
+	Contract   dex.Bytes `json:"contract"`
+	SecretHash dex.Bytes `json:"secretHash"`
+	// LockTime is the time (unix seconds) after which the contract can be
+	// refunded.
+	LockTime int64 `json:"lockTime"`
+	// SignedRefund is a signed refund transaction that is valid after the
+	// lock time. It is empty if the asset does not support pre-signed
+	// refunds, e.g. for account-based assets, or if the swap was sent before
+	// the kit was kept.
+	SignedRefund dex.Bytes `json:"signedRefund,omitempty"`
+	// Details is supplementary information to locate the swap, such as the
+	// contract address for account-based assets.
+	Details string `json:"details,omitempty"`
+}
+
+// Refundable checks whether the entry has a signed refund that can be
+// broadcast at the time.
+func (e *Entry) Refundable(now time.Time) bool {
+	return len(e.SignedRefund) > 0 && !now.Before(time.Unix(e.LockTime, 0))
+}
+
+// Kit is the refund kit.
+type Kit struct {
+	Version uint8    `json:"version"`
+	Updated int64    `json:"updated"` // unix ms
+	Entries []*Entry `json:"entries"`
+}
+
+// Write encrypts the kit and writes it to path, replacing any existing file.
+func Write(path string, crypter encrypt.Crypter, kit *Kit) error {
+	b, err := Encode(crypter, kit)
+	if err != nil {
+		return err
+	}
+	return WriteEncoded(path, b)
+}
+
+// Encode encrypts the kit, returning the file contents to be written with
+// WriteEncoded.
+func Encode(crypter encrypt.Crypter, kit *Kit) ([]byte, error) {
+	b, err := json.Marshal(kit)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := crypter.Encrypt(b)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting refund kit: %w", err)
+	}
+	blob := encode.BuildyBytes{kitVersion}.AddData(crypter.Serialize()).AddData(enc)
+	return append([]byte(magic), blob...), nil
+}
+
+// WriteEncoded writes a kit encoded with Encode to path, replacing any
+// existing file.
+func WriteEncoded(path string, b []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that a failed write does not leave
+	// a corrupted kit. The file is synced before the rename, and the directory
+	// after it, so that the kit survives a crash or power loss.
+	tmpPath := path + ".tmp"
+	if err := writeSynced(tmpPath, b); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return syncDir(filepath.Dir(path))
+}
+
+// writeSynced writes the file and syncs it to disk.
+func writeSynced(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// syncDir syncs the directory so that a rename in it is durable. Directories
+// cannot be synced on Windows, so this is a no-op there.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// Read reads and decrypts the refund kit at path.
+func Read(path string, pw []byte) (*Kit, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%w: not a refund kit", ErrInvalidKit)
+	}
+	ver, pushes, err := encode.DecodeBlob(b[len(magic):], 2)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKit, err)
+	}
+	if ver != kitVersion {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidKit, ver)
+	}
+	if len(pushes) != 2 {
+		return nil, fmt.Errorf("%w: expected 2 pushes, got %d", ErrInvalidKit, len(pushes))
+	}
+	crypter, err := encrypt.Deserialize(pw, pushes[0])
+	if err != nil {
+		return nil, fmt.Errorf("incorrect password or corrupted refund kit: %w", err)
+	}
+	defer crypter.Close()
+	plain, err := crypter.Decrypt(pushes[1])
+	if err != nil {
+		return nil, fmt.Errorf("incorrect password or corrupted refund kit: %w", err)
+	}
+	kit := new(Kit)
+	if err := json.Unmarshal(plain, kit); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKit, err)
+	}
+	return kit, nil
+}
@@ -0,0 +1,87 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package refundkit
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/encrypt"
+)
+
+func TestWriteRead(t *testing.T) {
+	pw := []byte("abc")
+	crypter := encrypt.NewCrypter(pw)
+	defer crypter.Close()
+
+	now := time.Now()
+	kit := &Kit{
+		Updated: now.UnixMilli(),
+		Entries: []*Entry{{
+			Host:         "dex.example.com",
+			AssetID:      0,
+			Symbol:       "btc",
+			Value:        1e8,
+			SwapCoin:     "abcd:0",
+			Contract:     []byte{0x01, 0x02},
+			LockTime:     now.Add(time.Hour).Unix(),
+			SignedRefund: []byte{0x03, 0x04},
+		}, {
+			Symbol:   "eth",
+			AssetID:  60,
+			LockTime: now.Add(-time.Hour).Unix(),
+			Details:  "contract address",
+		}},
+	}
+	path := filepath.Join(t.TempDir(), "kit", FileName)
+	if err := Write(path, crypter, kit); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+	// Overwrite.
+	if err := Write(path, crypter, kit); err != nil {
+		t.Fatalf("Write error: %v", err)
+	}
+
+	readKit, err := Read(path, pw)
+	if err != nil {
+		t.Fatalf("Read error: %v", err)
+	}
+	if readKit.Updated != kit.Updated || len(readKit.Entries) != 2 {
+		t.Fatalf("wrong kit read: %+v", readKit)
+	}
+	e := readKit.Entries[0]
+	if e.Value != 1e8 || !bytes.Equal(e.SignedRefund, kit.Entries[0].SignedRefund) {
+		t.Fatalf("wrong entry read: %+v", e)
+	}
+	if e.Refundable(now) || !e.Refundable(now.Add(time.Hour)) {
+		t.Fatalf("wrong refundable result before and after lock time")
+	}
+	// No signed refund.
+	if readKit.Entries[1].Refundable(now) {
+		t.Fatalf("entry without a signed refund is refundable")
+	}
+
+	if _, err := Read(path, []byte("wrong")); err == nil {
+		t.Fatalf("no error for wrong password")
+	}
+
+	b, _ := os.ReadFile(path)
+	b[len(b)-1] ^= 0x01
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, pw); err == nil {
+		t.Fatalf("no error for corrupted kit")
+	}
+	if err := os.WriteFile(path, []byte("not a kit"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Read(path, pw); !errors.Is(err, ErrInvalidKit) {
+		t.Fatalf("expected ErrInvalidKit, got %v", err)
+	}
+}
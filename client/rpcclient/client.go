@@ -0,0 +1,144 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package rpcclient is a typed Go client for the bisonw RPC server. Each route
+// served by client/rpcserver has a corresponding method, and core
+// notifications can be streamed over the RPC server's websocket endpoint.
+package rpcclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"decred.org/dcrdex/dex/msgjson"
+)
+
+// Config is the configuration for a Client.
+type Config struct {
+	// Addr is the host:port of the RPC server.
+	Addr string
+	// User and Pass are the RPC server's basic auth credentials. They are
+	// ignored if Token is set.
+	User string
+	Pass string
+	// Token is an API token issued with the addapitoken route. The token is
+	// sent as a Bearer token.
+	Token string
+	// Cert is the PEM-encoded TLS certificate of the RPC server, i.e. the
+	// contents of rpc.cert. If empty, the system roots are used.
+	Cert []byte
+}
+
+// Client is a client for the bisonw RPC server. Client is safe for concurrent
+// use.
+type Client struct {
+	addr       string
+	authHeader string
+	tlsConfig  *tls.Config
+	httpClient *http.Client
+	reqID      atomic.Uint64
+}
+
+// New is the constructor for a Client.
+func New(cfg *Config) (*Client, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("no RPC server address")
+	}
+	var authHeader string
+	switch {
+	case cfg.Token != "":
+		authHeader = "Bearer " + cfg.Token
+	case cfg.User != "" || cfg.Pass != "":
+		authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(cfg.User+":"+cfg.Pass))
+	default:
+		return nil, errors.New("no RPC credentials")
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(cfg.Cert) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.Cert) {
+			return nil, errors.New("invalid TLS certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return &Client{
+		addr:       cfg.Addr,
+		authHeader: authHeader,
+		tlsConfig:  tlsConfig,
+		httpClient: &http.Client{
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+// Call sends a request for the route with the given params and decodes the
+// result into result, which should be a pointer. params and result may be nil.
+// Errors returned by the RPC server are of type *msgjson.Error. The typed
+// methods of Client should be preferred.
+func (c *Client) Call(ctx context.Context, route string, params, result any) error {
+	msg, err := msgjson.NewRequest(c.reqID.Add(1), route, params)
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %w", route, err)
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("error encoding %s request: %w", route, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://"+c.addr, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", c.authHeader)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	respB, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("error reading %s response: %w", route, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if body := strings.TrimSpace(string(respB)); body != "" {
+			return fmt.Errorf("%s: %s", resp.Status, body)
+		}
+		return errors.New(resp.Status)
+	}
+
+	var respMsg msgjson.Message
+	if err := json.Unmarshal(respB, &respMsg); err != nil {
+		return fmt.Errorf("error decoding %s response: %w", route, err)
+	}
+	payload, err := respMsg.Response()
+	if err != nil {
+		return fmt.Errorf("error decoding %s response: %w", route, err)
+	}
+	if payload.Error != nil {
+		return payload.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(payload.Result, result); err != nil {
+		return fmt.Errorf("error decoding %s result: %w", route, err)
+	}
+	return nil
+}
+
+// callString is Call for routes that return a message string.
+func (c *Client) callString(ctx context.Context, route string, params any) (string, error) {
+	var s string
+	return s, c.Call(ctx, route, params, &s)
+}
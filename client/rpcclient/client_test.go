@@ -0,0 +1,380 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/rpcserver"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	tUser  = "user"
+	tPass  = "pass"
+	tToken = "bwt_abc_123"
+)
+
+// tServer is a fake RPC server that returns canned results.
+type tServer struct {
+	t   *testing.T
+	srv *httptest.Server
+
+	mtx     sync.Mutex
+	results map[string]any
+	errs    map[string]*msgjson.Error
+	routes  []string
+	auth    []string
+
+	notes chan *msgjson.Message
+}
+
+func newTServer(t *testing.T) *tServer {
+	s := &tServer{
+		t:       t,
+		results: make(map[string]any),
+		errs:    make(map[string]*msgjson.Error),
+		notes:   make(chan *msgjson.Message, 8),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ws", s.handleWS)
+	mux.HandleFunc("/", s.handleRPC)
+	s.srv = httptest.NewTLSServer(mux)
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+func (s *tServer) authorized(r *http.Request) bool {
+	user, pass, ok := r.BasicAuth()
+	if ok && user == tUser && pass == tPass {
+		return true
+	}
+	return r.Header.Get("Authorization") == "Bearer "+tToken
+}
+
+func (s *tServer) handleRPC(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	s.mtx.Unlock()
+	if !s.authorized(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	var msg msgjson.Message
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	s.routes = append(s.routes, msg.Route)
+	result, rpcErr := s.results[msg.Route], s.errs[msg.Route]
+	s.mtx.Unlock()
+	if rpcErr != nil {
+		result = nil
+	}
+	resp, err := msgjson.NewResponse(msg.ID, result, rpcErr)
+	if err != nil {
+		s.t.Errorf("error encoding response: %v", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (s *tServer) handleWS(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		s.t.Errorf("websocket upgrade error: %v", err)
+		return
+	}
+	defer conn.Close()
+	go func() {
+		// Read until the client closes the connection.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	for msg := range s.notes {
+		if err := conn.WriteJSON(msg); err != nil {
+			return
+		}
+	}
+}
+
+func (s *tServer) client(t *testing.T, token string) *Client {
+	t.Helper()
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.srv.Certificate().Raw})
+	cfg := &Config{
+		Addr: strings.TrimPrefix(s.srv.URL, "https://"),
+		Cert: cert,
+	}
+	if token != "" {
+		cfg.Token = token
+	} else {
+		cfg.User, cfg.Pass = tUser, tPass
+	}
+	cl, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	return cl
+}
+
+// TestRoutes checks that Client has a method for every RPC server route.
+func TestRoutes(t *testing.T) {
+	s := newTServer(t)
+	cl := s.client(t, "")
+	ctx := context.Background()
+
+	ctxType := reflect.TypeFor[context.Context]()
+	errType := reflect.TypeFor[error]()
+	v := reflect.ValueOf(cl)
+	for i := 0; i < v.NumMethod(); i++ {
+		name := v.Type().Method(i).Name
+		m := v.Method(i)
+		mt := m.Type()
+		if mt.NumIn() < 1 || mt.NumIn() > 2 || mt.In(0) != ctxType ||
+			mt.NumOut() != 2 || mt.Out(1) != errType || name == "SubscribeNotifications" {
+			continue
+		}
+		args := []reflect.Value{reflect.ValueOf(ctx)}
+		if mt.NumIn() == 2 {
+			args = append(args, reflect.Zero(mt.In(1)))
+		}
+		if err, _ := m.Call(args)[1].Interface().(error); err != nil {
+			t.Errorf("%s error: %v", name, err)
+		}
+	}
+
+	routes := slices.Clone(s.routes)
+	slices.Sort(routes)
+	routes = slices.Compact(routes)
+	want := rpcserver.Routes()
+	slices.Sort(want)
+	if !slices.Equal(routes, want) {
+		for _, r := range want {
+			if !slices.Contains(routes, r) {
+				t.Errorf("no method for route %q", r)
+			}
+		}
+		for _, r := range routes {
+			if !slices.Contains(want, r) {
+				t.Errorf("method for unknown route %q", r)
+			}
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	s := newTServer(t)
+	ctx := context.Background()
+
+	// Basic auth.
+	cl := s.client(t, "")
+	s.results["version"] = &rpcserver.VersionResponse{
+		RPCServerVer: &dex.Semver{Major: 1},
+		BWVersion:    &rpcserver.SemVersion{VersionString: "1.1.0"},
+	}
+	ver, err := cl.Version(ctx)
+	if err != nil {
+		t.Fatalf("Version error: %v", err)
+	}
+	if ver.RPCServerVer.Major != 1 || ver.BWVersion.VersionString != "1.1.0" {
+		t.Fatalf("wrong version %+v", ver)
+	}
+	if !strings.HasPrefix(s.auth[len(s.auth)-1], "Basic ") {
+		t.Fatalf("expected basic auth, got %q", s.auth[len(s.auth)-1])
+	}
+
+	// Bearer token.
+	cl = s.client(t, tToken)
+	s.results["trade"] = &rpcserver.TradeResponse{OrderID: "abcd", Stamp: 5}
+	tr, err := cl.Trade(ctx, &rpcserver.TradeParams{})
+	if err != nil {
+		t.Fatalf("Trade error: %v", err)
+	}
+	if tr.OrderID != "abcd" || tr.Stamp != 5 {
+		t.Fatalf("wrong trade result %+v", tr)
+	}
+	if s.auth[len(s.auth)-1] != "Bearer "+tToken {
+		t.Fatalf("expected bearer auth, got %q", s.auth[len(s.auth)-1])
+	}
+
+	// Bad credentials.
+	if _, err := s.client(t, "bwt_bad_token").Wallets(ctx); err == nil {
+		t.Fatal("no error for bad credentials")
+	}
+
+	// RPC errors are *msgjson.Error.
+	s.errs["cancel"] = msgjson.NewError(msgjson.RPCCancelError, "order not found")
+	_, err = cl.Cancel(ctx, &rpcserver.CancelParams{OrderID: "abcd"})
+	var msgErr *msgjson.Error
+	if !errors.As(err, &msgErr) || msgErr.Code != msgjson.RPCCancelError {
+		t.Fatalf("expected cancel error, got %v", err)
+	}
+
+	// Fields omitted by the exchanges route are restored.
+	s.results["exchanges"] = map[string]any{
+		"dex.example.com": map[string]any{
+			"markets": map[string]any{"dcr_btc": map[string]any{"baseid": 42}},
+			"assets":  map[string]any{"42": map[string]any{"symbol": "dcr"}},
+		},
+	}
+	xcs, err := cl.Exchanges(ctx)
+	if err != nil {
+		t.Fatalf("Exchanges error: %v", err)
+	}
+	xc := xcs["dex.example.com"]
+	if xc == nil || xc.Host != "dex.example.com" || xc.Markets["dcr_btc"].Name != "dcr_btc" ||
+		xc.Markets["dcr_btc"].BaseID != 42 || xc.Assets[42].ID != 42 {
+		t.Fatalf("wrong exchanges result %+v", xc)
+	}
+
+	// postbond returns a message if no bond was posted.
+	s.results["postbond"] = "existing account configured - no bond posted"
+	res, err := cl.PostBond(ctx, &core.PostBondForm{})
+	if err != nil || res != nil {
+		t.Fatalf("expected nil result, got %v, %v", res, err)
+	}
+	s.results["postbond"] = &core.PostBondResult{BondID: "abcd:0", ReqConfirms: 2}
+	res, err = cl.PostBond(ctx, &core.PostBondForm{})
+	if err != nil || res == nil || res.BondID != "abcd:0" || res.ReqConfirms != 2 {
+		t.Fatalf("wrong postbond result %+v, %v", res, err)
+	}
+}
+
+func TestDecodeNotification(t *testing.T) {
+	encode := func(note any) []byte {
+		t.Helper()
+		b, err := json.Marshal(note)
+		if err != nil {
+			t.Fatalf("error encoding note: %v", err)
+		}
+		return b
+	}
+
+	orderNote := &core.OrderNote{
+		Notification: db.NewNotification(core.NoteTypeOrder, core.TopicBuyOrderPlaced, "subject", "details", db.Success),
+		Order:        &core.Order{Host: "dex.example.com", Qty: 10},
+	}
+	note, err := DecodeNotification(encode(orderNote))
+	if err != nil {
+		t.Fatalf("error decoding order note: %v", err)
+	}
+	on, ok := note.(*core.OrderNote)
+	if !ok {
+		t.Fatalf("wrong type %T for order note", note)
+	}
+	if on.Order.Host != "dex.example.com" || on.Order.Qty != 10 || on.Topic() != core.TopicBuyOrderPlaced {
+		t.Fatalf("wrong order note %+v", on)
+	}
+
+	walletNote := &core.WalletNote{
+		Notification: db.NewNotification(core.NoteTypeWalletNote, core.TopicWalletNotification, "", "", db.Data),
+		Payload:      map[string]any{"route": "tipChange", "assetID": 42},
+	}
+	note, err = DecodeNotification(encode(walletNote))
+	if err != nil {
+		t.Fatalf("error decoding wallet note: %v", err)
+	}
+	wn, ok := note.(*WalletNote)
+	if !ok {
+		t.Fatalf("wrong type %T for wallet note", note)
+	}
+	var payload struct {
+		Route string `json:"route"`
+	}
+	if err := json.Unmarshal(wn.Payload, &payload); err != nil || payload.Route != "tipChange" {
+		t.Fatalf("wrong wallet note payload %s", wn.Payload)
+	}
+
+	botNote := map[string]any{"type": "runstats", "topic": "", "severity": db.Data}
+	note, err = DecodeNotification(encode(botNote))
+	if err != nil {
+		t.Fatalf("error decoding unknown note: %v", err)
+	}
+	if un, ok := note.(*UnknownNote); !ok || un.Type() != "runstats" || len(un.Raw) == 0 {
+		t.Fatalf("wrong unknown note %+v", note)
+	}
+
+	if _, err := DecodeNotification([]byte("[]")); err == nil {
+		t.Fatal("no error for invalid notification")
+	}
+}
+
+func TestSubscribeNotifications(t *testing.T) {
+	s := newTServer(t)
+	cl := s.client(t, tToken)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := cl.SubscribeNotifications(ctx)
+	if err != nil {
+		t.Fatalf("SubscribeNotifications error: %v", err)
+	}
+
+	// Only notify notifications are delivered.
+	other, _ := msgjson.NewNotification("book", map[string]any{"type": core.NoteTypeBalance})
+	s.notes <- other
+	balNote := &core.BalanceNote{
+		Notification: db.NewNotification(core.NoteTypeBalance, core.TopicBalanceUpdated, "", "", db.Data),
+		AssetID:      42,
+		Balance:      &core.WalletBalance{},
+	}
+	msg, _ := msgjson.NewNotification(rpcserver.NotifyRoute, balNote)
+	s.notes <- msg
+
+	select {
+	case note := <-stream.C:
+		bn, ok := note.(*core.BalanceNote)
+		if !ok {
+			t.Fatalf("wrong type %T for balance note", note)
+		}
+		if bn.AssetID != 42 {
+			t.Fatalf("wrong asset ID %d", bn.AssetID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-stream.C:
+		if ok {
+			t.Fatal("unexpected notification")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not closed after context cancellation")
+	}
+	if err := stream.Err(); err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+	close(s.notes)
+
+	// Bad credentials.
+	if _, err := s.client(t, "bwt_bad_token").SubscribeNotifications(context.Background()); err == nil {
+		t.Fatal("no error for bad credentials")
+	}
+}
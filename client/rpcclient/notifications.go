@@ -0,0 +1,233 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/rpcserver"
+	"decred.org/dcrdex/dex/msgjson"
+	"github.com/gorilla/websocket"
+)
+
+// noteBufferSize is the capacity of a NoteStream's channel.
+const noteBufferSize = 128
+
+// WalletNote is a wallet notification. The payload of a core.WalletNote may be
+// any of the asset package's wallet notification types, so it is left encoded
+// for the caller. The payload's "route" field identifies its type.
+type WalletNote struct {
+	db.Notification
+	Payload json.RawMessage `json:"payload"`
+}
+
+// ActionRequiredNote is a notification that the user must take an action. It
+// is structured like a WalletNote, and the payload is an encoded
+// *asset.ActionRequiredNote.
+type ActionRequiredNote WalletNote
+
+// UnknownNote is a notification of a type that DecodeNotification does not
+// recognize, such as the market maker's notifications. The full notification
+// is in Raw.
+type UnknownNote struct {
+	db.Notification
+	Raw json.RawMessage `json:"-"`
+}
+
+// DecodeNotification decodes an encoded core notification into the concrete
+// type for its "type" field.
+func DecodeNotification(b []byte) (core.Notification, error) {
+	var hdr db.Notification
+	if err := json.Unmarshal(b, &hdr); err != nil {
+		return nil, fmt.Errorf("error decoding notification: %w", err)
+	}
+	var note core.Notification
+	switch hdr.NoteType {
+	case core.NoteTypeFeePayment:
+		note = new(core.FeePaymentNote)
+	case core.NoteTypeBondPost:
+		note = new(core.BondPostNote)
+	case core.NoteTypeBondRefund:
+		note = new(core.BondRefundNote)
+	case core.NoteTypeUnknownBond:
+		note = new(db.Notification)
+	case core.NoteTypeSend:
+		note = new(core.SendNote)
+	case core.NoteTypeOrder:
+		note = new(core.OrderNote)
+	case core.NoteTypeMatch:
+		note = new(core.MatchNote)
+	case core.NoteTypeEpoch:
+		note = new(core.EpochNotification)
+	case core.NoteTypeConnEvent:
+		note = new(core.ConnEventNote)
+	case core.NoteTypeBalance:
+		note = new(core.BalanceNote)
+	case core.NoteTypeSpots:
+		note = new(core.SpotPriceNote)
+	case core.NoteTypeWalletConfig:
+		note = new(core.WalletConfigNote)
+	case core.NoteTypeWalletState:
+		note = new(core.WalletStateNote)
+	case core.NoteTypeWalletSync:
+		note = new(core.WalletSyncNote)
+	case core.NoteTypeServerNotify:
+		if hdr.TopicID == core.TopicServerConfigUpdate {
+			note = new(core.ServerConfigUpdateNote)
+		} else {
+			note = new(core.ServerNotifyNote)
+		}
+	case core.NoteTypeSecurity:
+		note = new(core.SecurityNote)
+	case core.NoteTypeUpgrade:
+		note = new(core.UpgradeNote)
+	case core.NoteTypeDEXAuth:
+		note = new(core.DEXAuthNote)
+	case core.NoteTypeFiatRates:
+		note = new(core.FiatRatesNote)
+	case core.NoteTypeCreateWallet:
+		note = new(core.WalletCreationNote)
+	case core.NoteTypeLogin:
+		note = new(core.LoginNote)
+	case core.NoteTypeWalletNote:
+		note = new(WalletNote)
+	case core.NoteTypeReputation:
+		note = new(core.ReputationNote)
+	case core.NoteTypeActionRequired:
+		note = new(ActionRequiredNote)
+	case core.NoteTypeBridge:
+		note = new(core.BridgeNote)
+	case core.NoteTypePriceAlert:
+		note = new(core.PriceAlertNote)
+	case core.NoteTypeBridgeTrade:
+		note = new(core.BridgeTradeNote)
+	default:
+		return &UnknownNote{Notification: hdr, Raw: b}, nil
+	}
+	if err := json.Unmarshal(b, note); err != nil {
+		return nil, fmt.Errorf("error decoding %q notification: %w", hdr.NoteType, err)
+	}
+	return note, nil
+}
+
+// NoteStream is a stream of core notifications from the RPC server's websocket
+// endpoint.
+type NoteStream struct {
+	// C receives the notifications. C is closed when the stream ends.
+	C <-chan core.Notification
+
+	conn      *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+
+	errMtx sync.Mutex
+	err    error
+}
+
+// SubscribeNotifications connects to the RPC server's websocket endpoint and
+// streams the core notifications. The stream ends when ctx is canceled, Close
+// is called, or the connection fails.
+func (c *Client) SubscribeNotifications(ctx context.Context) (*NoteStream, error) {
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:  c.tlsConfig,
+	}
+	header := http.Header{"Authorization": []string{c.authHeader}}
+	conn, resp, err := dialer.DialContext(ctx, "wss://"+c.addr+"/ws", header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("websocket dial error: %w (%s)", err, resp.Status)
+		}
+		return nil, fmt.Errorf("websocket dial error: %w", err)
+	}
+	ch := make(chan core.Notification, noteBufferSize)
+	s := &NoteStream{
+		C:    ch,
+		conn: conn,
+		done: make(chan struct{}),
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Close()
+		case <-s.done:
+		}
+	}()
+	go s.read(ch)
+	return s, nil
+}
+
+// read reads notifications until the connection is closed.
+func (s *NoteStream) read(ch chan<- core.Notification) {
+	defer close(ch)
+	for {
+		_, b, err := s.conn.ReadMessage()
+		if err != nil {
+			select {
+			case <-s.done:
+			default:
+				s.setErr(err)
+				s.Close()
+			}
+			return
+		}
+		var msg msgjson.Message
+		if err := json.Unmarshal(b, &msg); err != nil {
+			s.setErr(fmt.Errorf("error decoding websocket message: %w", err))
+			s.Close()
+			return
+		}
+		if msg.Type != msgjson.Notification || msg.Route != rpcserver.NotifyRoute {
+			continue
+		}
+		note, err := DecodeNotification(msg.Payload)
+		if err != nil {
+			// Deliver what can be decoded rather than dropping the
+			// notification.
+			un := &UnknownNote{Raw: msg.Payload}
+			if json.Unmarshal(msg.Payload, &un.Notification) != nil {
+				continue
+			}
+			note = un
+		}
+		select {
+		case ch <- note:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *NoteStream) setErr(err error) {
+	s.errMtx.Lock()
+	s.err = err
+	s.errMtx.Unlock()
+}
+
+// Err returns the error that ended the stream, if any. Err is nil if the
+// stream is still running or was ended with Close or context cancellation.
+func (s *NoteStream) Err() error {
+	s.errMtx.Lock()
+	defer s.errMtx.Unlock()
+	return s.err
+}
+
+// Close ends the stream and closes the websocket connection.
+func (s *NoteStream) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""),
+			time.Now().Add(time.Second))
+		s.conn.Close()
+	})
+}
@@ -0,0 +1,726 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcclient
+
+import (
+	"context"
+	"encoding/json"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/mm"
+	"decred.org/dcrdex/client/rpcserver"
+)
+
+//
+// System
+//
+
+// Help returns help for all routes, or detailed help for a single route.
+func (c *Client) Help(ctx context.Context, params *rpcserver.HelpParams) (string, error) {
+	return c.callString(ctx, "help", params)
+}
+
+// Init initializes the client with an app password and optional seed.
+func (c *Client) Init(ctx context.Context, params *rpcserver.InitParams) (string, error) {
+	return c.callString(ctx, "init", params)
+}
+
+// Version returns the versions of bisonw and the RPC server.
+func (c *Client) Version(ctx context.Context) (*rpcserver.VersionResponse, error) {
+	res := new(rpcserver.VersionResponse)
+	if err := c.Call(ctx, "version", nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Login unlocks the app and connects to all configured DEX hosts.
+func (c *Client) Login(ctx context.Context, params *rpcserver.LoginParams) (string, error) {
+	return c.callString(ctx, "login", params)
+}
+
+// Logout locks the app and disconnects from all DEX hosts.
+func (c *Client) Logout(ctx context.Context) (string, error) {
+	return c.callString(ctx, "logout", nil)
+}
+
+//
+// Wallets
+//
+
+// NewWallet creates a new wallet.
+func (c *Client) NewWallet(ctx context.Context, params *rpcserver.NewWalletParams) (string, error) {
+	return c.callString(ctx, "newwallet", params)
+}
+
+// ReconfigureWallet changes a wallet's settings or type.
+func (c *Client) ReconfigureWallet(ctx context.Context, params *rpcserver.ReconfigureWalletParams) (string, error) {
+	return c.callString(ctx, "reconfigurewallet", params)
+}
+
+// OpenWallet unlocks a wallet for use.
+func (c *Client) OpenWallet(ctx context.Context, params *rpcserver.OpenWalletParams) (string, error) {
+	return c.callString(ctx, "openwallet", params)
+}
+
+// CloseWallet locks a wallet.
+func (c *Client) CloseWallet(ctx context.Context, params *rpcserver.CloseWalletParams) (string, error) {
+	return c.callString(ctx, "closewallet", params)
+}
+
+// ToggleWalletStatus enables or disables a wallet.
+func (c *Client) ToggleWalletStatus(ctx context.Context, params *rpcserver.ToggleWalletStatusParams) (string, error) {
+	return c.callString(ctx, "togglewalletstatus", params)
+}
+
+// Wallets returns the state of every wallet.
+func (c *Client) Wallets(ctx context.Context) ([]*core.WalletState, error) {
+	var res []*core.WalletState
+	return res, c.Call(ctx, "wallets", nil, &res)
+}
+
+// WalletBalance returns the balance of a wallet.
+func (c *Client) WalletBalance(ctx context.Context, params *rpcserver.WalletBalanceParams) (*core.WalletBalance, error) {
+	res := new(core.WalletBalance)
+	if err := c.Call(ctx, "walletbalance", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WalletState returns the state of a wallet.
+func (c *Client) WalletState(ctx context.Context, params *rpcserver.WalletStateParams) (*core.WalletState, error) {
+	res := new(core.WalletState)
+	if err := c.Call(ctx, "walletstate", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RescanWallet starts a wallet rescan.
+func (c *Client) RescanWallet(ctx context.Context, params *rpcserver.RescanWalletParams) (string, error) {
+	return c.callString(ctx, "rescanwallet", params)
+}
+
+// AddCustomToken adds a user-defined token and returns its asset ID.
+func (c *Client) AddCustomToken(ctx context.Context, params *rpcserver.AddCustomTokenParams) (uint32, error) {
+	var res uint32
+	return res, c.Call(ctx, "addcustomtoken", params, &res)
+}
+
+//
+// Trading
+//
+
+// Trade places an order.
+func (c *Client) Trade(ctx context.Context, params *rpcserver.TradeParams) (*rpcserver.TradeResponse, error) {
+	res := new(rpcserver.TradeResponse)
+	if err := c.Call(ctx, "trade", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MultiTrade places several orders on one market. Orders that could not be
+// placed have their Error field set.
+func (c *Client) MultiTrade(ctx context.Context, params *rpcserver.MultiTradeParams) ([]*rpcserver.TradeResponse, error) {
+	var res []*rpcserver.TradeResponse
+	return res, c.Call(ctx, "multitrade", params, &res)
+}
+
+// Cancel cancels an order.
+func (c *Client) Cancel(ctx context.Context, params *rpcserver.CancelParams) (string, error) {
+	return c.callString(ctx, "cancel", params)
+}
+
+// OrderBook returns a market's order book.
+func (c *Client) OrderBook(ctx context.Context, params *rpcserver.OrderBookParams) (*core.OrderBook, error) {
+	res := new(core.OrderBook)
+	if err := c.Call(ctx, "orderbook", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MyOrders returns the user's active and recent orders.
+func (c *Client) MyOrders(ctx context.Context, params *rpcserver.MyOrdersParams) (rpcserver.MyOrdersResponse, error) {
+	var res rpcserver.MyOrdersResponse
+	return res, c.Call(ctx, "myorders", params, &res)
+}
+
+// Exchanges returns the known DEX hosts. The RPC server omits some redundant
+// fields, which are restored from the map keys.
+func (c *Client) Exchanges(ctx context.Context) (map[string]*core.Exchange, error) {
+	var res map[string]*core.Exchange
+	if err := c.Call(ctx, "exchanges", nil, &res); err != nil {
+		return nil, err
+	}
+	for host, xc := range res {
+		xc.Host = host
+		for name, mkt := range xc.Markets {
+			mkt.Name = name
+		}
+		for assetID, a := range xc.Assets {
+			a.ID = assetID
+		}
+	}
+	return res, nil
+}
+
+//
+// Transactions
+//
+
+// Withdraw sends funds with the fees subtracted from the value and returns the
+// coin ID.
+func (c *Client) Withdraw(ctx context.Context, params *rpcserver.SendParams) (string, error) {
+	return c.callString(ctx, "withdraw", params)
+}
+
+// Send sends funds and returns the coin ID.
+func (c *Client) Send(ctx context.Context, params *rpcserver.SendParams) (string, error) {
+	return c.callString(ctx, "send", params)
+}
+
+// SendBatch sends funds to several recipients and returns the transaction IDs.
+func (c *Client) SendBatch(ctx context.Context, params *rpcserver.SendBatchParams) ([]string, error) {
+	var res []string
+	return res, c.Call(ctx, "sendbatch", params, &res)
+}
+
+// BatchTxFee estimates the fees for a batch send.
+func (c *Client) BatchTxFee(ctx context.Context, params *rpcserver.BatchTxFeeParams) (uint64, error) {
+	var res uint64
+	return res, c.Call(ctx, "batchtxfee", params, &res)
+}
+
+// ListCoins returns a wallet's spendable coins.
+func (c *Client) ListCoins(ctx context.Context, params *rpcserver.ListCoinsParams) ([]*asset.SpendableCoin, error) {
+	var res []*asset.SpendableCoin
+	return res, c.Call(ctx, "listcoins", params, &res)
+}
+
+// SendWithCoins sends funds using specific coins and returns the coin ID.
+func (c *Client) SendWithCoins(ctx context.Context, params *rpcserver.SendWithCoinsParams) (string, error) {
+	return c.callString(ctx, "sendwithcoins", params)
+}
+
+// AbandonTx abandons an unconfirmed transaction.
+func (c *Client) AbandonTx(ctx context.Context, params *rpcserver.AbandonTxParams) (string, error) {
+	return c.callString(ctx, "abandontx", params)
+}
+
+// AppSeed returns the app seed.
+func (c *Client) AppSeed(ctx context.Context, params *rpcserver.AppSeedParams) (string, error) {
+	return c.callString(ctx, "appseed", params)
+}
+
+// DeleteArchivedRecords deletes archived orders and matches.
+func (c *Client) DeleteArchivedRecords(ctx context.Context, params *rpcserver.DeleteRecordsParams) (string, error) {
+	return c.callString(ctx, "deletearchivedrecords", params)
+}
+
+// Notifications returns recent notifications. Use SubscribeNotifications to
+// stream new notifications.
+func (c *Client) Notifications(ctx context.Context, params *rpcserver.NotificationsParams) ([]*db.Notification, error) {
+	var res []*db.Notification
+	return res, c.Call(ctx, "notifications", params, &res)
+}
+
+// TxHistory returns a wallet's transaction history.
+func (c *Client) TxHistory(ctx context.Context, params *rpcserver.TxHistoryParams) (*asset.TxHistoryResponse, error) {
+	res := new(asset.TxHistoryResponse)
+	if err := c.Call(ctx, "txhistory", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WalletTx returns a single wallet transaction.
+func (c *Client) WalletTx(ctx context.Context, params *rpcserver.WalletTxParams) (*asset.WalletTransaction, error) {
+	res := new(asset.WalletTransaction)
+	if err := c.Call(ctx, "wallettx", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GaslessRedeemCalldata returns the calldata for a gasless redeem.
+func (c *Client) GaslessRedeemCalldata(ctx context.Context, params *rpcserver.GaslessRedeemCalldataParams) (*rpcserver.GaslessRedeemCalldataResponse, error) {
+	res := new(rpcserver.GaslessRedeemCalldataResponse)
+	if err := c.Call(ctx, "gaslessredeemcalldata", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// ValidateGaslessRedeem validates a signed gasless redeem.
+func (c *Client) ValidateGaslessRedeem(ctx context.Context, params *rpcserver.ValidateGaslessRedeemParams) (*rpcserver.ValidateGaslessRedeemResponse, error) {
+	res := new(rpcserver.ValidateGaslessRedeemResponse)
+	if err := c.Call(ctx, "validategaslessredeem", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SubmitGaslessRedeem submits a gasless redeem.
+func (c *Client) SubmitGaslessRedeem(ctx context.Context, params *rpcserver.SubmitGaslessRedeemParams) (*rpcserver.SubmitGaslessRedeemResponse, error) {
+	res := new(rpcserver.SubmitGaslessRedeemResponse)
+	if err := c.Call(ctx, "submitgaslessredeem", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WithdrawBchSpv returns a signed transaction that withdraws all funds from a
+// BCH SPV wallet.
+func (c *Client) WithdrawBchSpv(ctx context.Context, params *rpcserver.BchWithdrawParams) (string, error) {
+	return c.callString(ctx, "withdrawbchspv", params)
+}
+
+//
+// DEX
+//
+
+// BondAssets returns the assets a DEX host accepts for bonds.
+func (c *Client) BondAssets(ctx context.Context, params *rpcserver.BondAssetsParams) (*rpcserver.BondAssetsResponse, error) {
+	res := new(rpcserver.BondAssetsResponse)
+	if err := c.Call(ctx, "bondassets", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetDEXConfig returns a DEX host's configuration.
+func (c *Client) GetDEXConfig(ctx context.Context, params *rpcserver.GetDEXConfigParams) (*core.Exchange, error) {
+	res := new(core.Exchange)
+	if err := c.Call(ctx, "getdexconfig", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// DiscoverAcct discovers an existing account on a DEX host and returns whether
+// it is paid.
+func (c *Client) DiscoverAcct(ctx context.Context, params *rpcserver.DiscoverAcctParams) (bool, error) {
+	var res bool
+	return res, c.Call(ctx, "discoveracct", params, &res)
+}
+
+// PostBond posts a bond to a DEX host. The result is nil if an existing
+// account was found and no bond was posted.
+func (c *Client) PostBond(ctx context.Context, form *core.PostBondForm) (*core.PostBondResult, error) {
+	var raw json.RawMessage
+	if err := c.Call(ctx, "postbond", form, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) > 0 && raw[0] == '"' {
+		return nil, nil
+	}
+	res := new(core.PostBondResult)
+	if err := json.Unmarshal(raw, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// BondOptions changes the bond options for a DEX host.
+func (c *Client) BondOptions(ctx context.Context, params *core.BondOptionsForm) (string, error) {
+	return c.callString(ctx, "bondopts", params)
+}
+
+//
+// Market making
+//
+
+// MMAvailableBalances returns the balances available to a bot.
+func (c *Client) MMAvailableBalances(ctx context.Context, params *rpcserver.MMAvailableBalancesParams) (*rpcserver.MMAvailableBalancesResponse, error) {
+	res := new(rpcserver.MMAvailableBalancesResponse)
+	if err := c.Call(ctx, "mmavailablebalances", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// StartBot starts one or all configured bots.
+func (c *Client) StartBot(ctx context.Context, params *rpcserver.StartBotParams) (string, error) {
+	return c.callString(ctx, "startmmbot", params)
+}
+
+// StopBot stops one or all running bots.
+func (c *Client) StopBot(ctx context.Context, params *rpcserver.StopBotParams) (string, error) {
+	return c.callString(ctx, "stopmmbot", params)
+}
+
+// UpdateRunningBotCfg updates the configuration of a running bot.
+func (c *Client) UpdateRunningBotCfg(ctx context.Context, params *rpcserver.UpdateRunningBotParams) (string, error) {
+	return c.callString(ctx, "updaterunningbotcfg", params)
+}
+
+// UpdateRunningBotInventory changes the inventory of a running bot.
+func (c *Client) UpdateRunningBotInventory(ctx context.Context, params *rpcserver.UpdateRunningBotInventoryParams) (string, error) {
+	return c.callString(ctx, "updaterunningbotinv", params)
+}
+
+// MMStatus returns the status of the market maker.
+func (c *Client) MMStatus(ctx context.Context) (*mm.Status, error) {
+	res := new(mm.Status)
+	if err := c.Call(ctx, "mmstatus", nil, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// MMReport writes a bot's snapshots to a file and returns the file path.
+func (c *Client) MMReport(ctx context.Context, params *rpcserver.MMReportParams) (string, error) {
+	return c.callString(ctx, "mmreport", params)
+}
+
+// PruneMMSnapshots deletes old bot snapshots and returns the number deleted.
+func (c *Client) PruneMMSnapshots(ctx context.Context, params *rpcserver.PruneMMSnapshotsParams) (int, error) {
+	var res int
+	return res, c.Call(ctx, "prunemmsnapshots", params, &res)
+}
+
+//
+// Staking
+//
+
+// StakeStatus returns a wallet's ticket staking status.
+func (c *Client) StakeStatus(ctx context.Context, params *rpcserver.StakeStatusParams) (*asset.TicketStakingStatus, error) {
+	res := new(asset.TicketStakingStatus)
+	if err := c.Call(ctx, "stakestatus", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// SetVSP sets a wallet's voting service provider.
+func (c *Client) SetVSP(ctx context.Context, params *rpcserver.SetVSPParams) (string, error) {
+	return c.callString(ctx, "setvsp", params)
+}
+
+// PurchaseTickets starts a ticket purchase.
+func (c *Client) PurchaseTickets(ctx context.Context, params *rpcserver.PurchaseTicketsParams) (bool, error) {
+	var res bool
+	return res, c.Call(ctx, "purchasetickets", params, &res)
+}
+
+// SetVotingPreferences sets a wallet's voting preferences.
+func (c *Client) SetVotingPreferences(ctx context.Context, params *rpcserver.SetVotingPreferencesParams) (string, error) {
+	return c.callString(ctx, "setvotingprefs", params)
+}
+
+//
+// Bridge
+//
+
+// Bridge starts a bridge and returns the transaction ID.
+func (c *Client) Bridge(ctx context.Context, params *rpcserver.BridgeParams) (string, error) {
+	return c.callString(ctx, "bridge", params)
+}
+
+// CheckBridgeApproval returns the approval status of a bridge contract.
+func (c *Client) CheckBridgeApproval(ctx context.Context, params *rpcserver.CheckBridgeApprovalParams) (string, error) {
+	return c.callString(ctx, "checkbridgeapproval", params)
+}
+
+// ApproveBridgeContract approves or unapproves a bridge contract and returns
+// the transaction ID.
+func (c *Client) ApproveBridgeContract(ctx context.Context, params *rpcserver.ApproveBridgeParams) (string, error) {
+	return c.callString(ctx, "approvebridgecontract", params)
+}
+
+// PendingBridges returns a wallet's unfinished bridges.
+func (c *Client) PendingBridges(ctx context.Context, params *rpcserver.PendingBridgesParams) ([]*asset.WalletTransaction, error) {
+	var res []*asset.WalletTransaction
+	return res, c.Call(ctx, "pendingbridges", params, &res)
+}
+
+// BridgeHistory returns a wallet's bridge history.
+func (c *Client) BridgeHistory(ctx context.Context, params *rpcserver.TxHistoryParams) ([]*asset.WalletTransaction, error) {
+	var res []*asset.WalletTransaction
+	return res, c.Call(ctx, "bridgehistory", params, &res)
+}
+
+// SupportedBridges returns the bridges available for each destination asset
+// symbol.
+func (c *Client) SupportedBridges(ctx context.Context, params *rpcserver.SupportedBridgesParams) (map[string][]string, error) {
+	var res map[string][]string
+	return res, c.Call(ctx, "supportedbridges", params, &res)
+}
+
+// BridgeFeesAndLimits returns the fees and limits of a bridge.
+func (c *Client) BridgeFeesAndLimits(ctx context.Context, params *rpcserver.BridgeFeesAndLimitsParams) (*core.BridgeFeesAndLimits, error) {
+	res := new(core.BridgeFeesAndLimits)
+	if err := c.Call(ctx, "bridgefeesandlimits", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+//
+// Multisig
+//
+
+// PaymentMultisigPubkey returns a wallet's multisig pubkey.
+func (c *Client) PaymentMultisigPubkey(ctx context.Context, params *rpcserver.PaymentMultisigPubkeyParams) (string, error) {
+	return c.callString(ctx, "paymentmultisigpubkey", params)
+}
+
+// SendFundsToMultisig funds the multisig described by a csv file.
+func (c *Client) SendFundsToMultisig(ctx context.Context, params *rpcserver.CsvFileParams) (string, error) {
+	return c.callString(ctx, "sendfundstomultisig", params)
+}
+
+// SignMultisig signs the multisig spend described by a csv file.
+func (c *Client) SignMultisig(ctx context.Context, params *rpcserver.SignMultisigParams) (string, error) {
+	return c.callString(ctx, "signmultisig", params)
+}
+
+// RefundPaymentMultisig refunds the multisig described by a csv file.
+func (c *Client) RefundPaymentMultisig(ctx context.Context, params *rpcserver.CsvFileParams) (string, error) {
+	return c.callString(ctx, "refundpaymentmultisig", params)
+}
+
+// ViewPaymentMultisig describes the multisig in a csv file.
+func (c *Client) ViewPaymentMultisig(ctx context.Context, params *rpcserver.CsvFileParams) (string, error) {
+	return c.callString(ctx, "viewpaymentmultisig", params)
+}
+
+// SendPaymentMultisig broadcasts the multisig spend described by a csv file.
+func (c *Client) SendPaymentMultisig(ctx context.Context, params *rpcserver.CsvFileParams) (string, error) {
+	return c.callString(ctx, "sendpaymentmultisig", params)
+}
+
+//
+// Peers
+//
+
+// WalletPeers returns a wallet's peers.
+func (c *Client) WalletPeers(ctx context.Context, params *rpcserver.WalletPeersParams) ([]*asset.WalletPeer, error) {
+	var res []*asset.WalletPeer
+	return res, c.Call(ctx, "walletpeers", params, &res)
+}
+
+// AddWalletPeer adds a wallet peer.
+func (c *Client) AddWalletPeer(ctx context.Context, params *rpcserver.AddRemovePeerParams) (string, error) {
+	return c.callString(ctx, "addwalletpeer", params)
+}
+
+// RemoveWalletPeer removes a wallet peer.
+func (c *Client) RemoveWalletPeer(ctx context.Context, params *rpcserver.AddRemovePeerParams) (string, error) {
+	return c.callString(ctx, "removewalletpeer", params)
+}
+
+//
+// Developer
+//
+
+// DeployContract deploys a contract. Requires the RPC server to be run with
+// --rpcdev.
+func (c *Client) DeployContract(ctx context.Context, params *rpcserver.DeployContractParams) ([]*core.DeployContractResult, error) {
+	var res []*core.DeployContractResult
+	return res, c.Call(ctx, "deploycontract", params, &res)
+}
+
+// TestContractGas measures contract gas use. Requires the RPC server to be run
+// with --rpcdev.
+func (c *Client) TestContractGas(ctx context.Context, params *rpcserver.TestContractGasParams) ([]*core.ContractGasTestResult, error) {
+	var res []*core.ContractGasTestResult
+	return res, c.Call(ctx, "testcontractgas", params, &res)
+}
+
+//
+// Webhooks
+//
+
+// AddWebhook registers a webhook.
+func (c *Client) AddWebhook(ctx context.Context, params *rpcserver.AddWebhookParams) (*db.Webhook, error) {
+	res := new(db.Webhook)
+	if err := c.Call(ctx, "addwebhook", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RemoveWebhook removes a webhook.
+func (c *Client) RemoveWebhook(ctx context.Context, params *rpcserver.WebhookIDParams) (string, error) {
+	return c.callString(ctx, "removewebhook", params)
+}
+
+// ToggleWebhook enables or disables a webhook.
+func (c *Client) ToggleWebhook(ctx context.Context, params *rpcserver.ToggleWebhookParams) (string, error) {
+	return c.callString(ctx, "togglewebhook", params)
+}
+
+// Webhooks returns the registered webhooks.
+func (c *Client) Webhooks(ctx context.Context) ([]*db.Webhook, error) {
+	var res []*db.Webhook
+	return res, c.Call(ctx, "webhooks", nil, &res)
+}
+
+// WebhookDeliveries returns recent webhook deliveries.
+func (c *Client) WebhookDeliveries(ctx context.Context, params *rpcserver.NotificationsParams) ([]*core.WebhookDelivery, error) {
+	var res []*core.WebhookDelivery
+	return res, c.Call(ctx, "webhookdeliveries", params, &res)
+}
+
+//
+// Price alerts
+//
+
+// AddPriceAlert adds a price alert.
+func (c *Client) AddPriceAlert(ctx context.Context, params *rpcserver.AddPriceAlertParams) (*db.PriceAlert, error) {
+	res := new(db.PriceAlert)
+	if err := c.Call(ctx, "addpricealert", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RemovePriceAlert removes a price alert.
+func (c *Client) RemovePriceAlert(ctx context.Context, params *rpcserver.PriceAlertIDParams) (string, error) {
+	return c.callString(ctx, "removepricealert", params)
+}
+
+// PriceAlerts returns the price alerts.
+func (c *Client) PriceAlerts(ctx context.Context) ([]*db.PriceAlert, error) {
+	var res []*db.PriceAlert
+	return res, c.Call(ctx, "pricealerts", nil, &res)
+}
+
+//
+// Address book
+//
+
+// SearchTxHistory searches a wallet's transaction history.
+func (c *Client) SearchTxHistory(ctx context.Context, params *rpcserver.SearchTxHistoryParams) ([]*asset.WalletTransaction, error) {
+	var res []*asset.WalletTransaction
+	return res, c.Call(ctx, "searchtxhistory", params, &res)
+}
+
+// SetLabel sets or removes a label.
+func (c *Client) SetLabel(ctx context.Context, params *rpcserver.SetLabelParams) (string, error) {
+	return c.callString(ctx, "setlabel", params)
+}
+
+// Labels returns the labels.
+func (c *Client) Labels(ctx context.Context, params *rpcserver.LabelsParams) ([]*db.Label, error) {
+	var res []*db.Label
+	return res, c.Call(ctx, "labels", params, &res)
+}
+
+// AddContact adds an address book contact.
+func (c *Client) AddContact(ctx context.Context, params *rpcserver.AddContactParams) (string, error) {
+	return c.callString(ctx, "addcontact", params)
+}
+
+// RemoveContact removes an address book contact.
+func (c *Client) RemoveContact(ctx context.Context, params *rpcserver.RemoveContactParams) (string, error) {
+	return c.callString(ctx, "removecontact", params)
+}
+
+// Contacts returns the address book contacts.
+func (c *Client) Contacts(ctx context.Context, params *rpcserver.LabelsParams) ([]*db.Contact, error) {
+	var res []*db.Contact
+	return res, c.Call(ctx, "contacts", params, &res)
+}
+
+//
+// API tokens
+//
+
+// AddAPIToken issues an API token. The token secret is only returned once.
+func (c *Client) AddAPIToken(ctx context.Context, params *rpcserver.AddAPITokenParams) (*rpcserver.NewAPITokenResult, error) {
+	res := new(rpcserver.NewAPITokenResult)
+	if err := c.Call(ctx, "addapitoken", params, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RevokeAPIToken revokes an API token.
+func (c *Client) RevokeAPIToken(ctx context.Context, params *rpcserver.RevokeAPITokenParams) (string, error) {
+	return c.callString(ctx, "revokeapitoken", params)
+}
+
+// APITokens returns the API tokens.
+func (c *Client) APITokens(ctx context.Context) ([]*rpcserver.APIToken, error) {
+	var res []*rpcserver.APIToken
+	return res, c.Call(ctx, "apitokens", nil, &res)
+}
+
+//
+// Trade history export
+//
+
+// ExportTrades returns the trade history records that pass the filter. The
+// params Format is ignored.
+func (c *Client) ExportTrades(ctx context.Context, params *rpcserver.ExportTradesParams) ([]*core.TradeExportRecord, error) {
+	p := exportTradesParams(params, "json")
+	var res []*core.TradeExportRecord
+	return res, c.Call(ctx, "exporttrades", p, &res)
+}
+
+// ExportTradesCSV returns the trade history records that pass the filter as
+// CSV. The params Format is ignored.
+func (c *Client) ExportTradesCSV(ctx context.Context, params *rpcserver.ExportTradesParams) (string, error) {
+	return c.callString(ctx, "exporttrades", exportTradesParams(params, "csv"))
+}
+
+// exportTradesParams copies the params with the format set.
+func exportTradesParams(params *rpcserver.ExportTradesParams, format string) *rpcserver.ExportTradesParams {
+	var p rpcserver.ExportTradesParams
+	if params != nil {
+		p = *params
+	}
+	p.Format = &format
+	return &p
+}
+
+//
+// Refund kit
+//
+
+// RefundKit returns the emergency refund kit.
+func (c *Client) RefundKit(ctx context.Context) (*core.RefundKit, error) {
+	res := new(core.RefundKit)
+	return res, c.Call(ctx, "refundkit", nil, res)
+}
+
+// ExportRefundKit writes a copy of the refund kit to the path on the host
+// running the RPC server.
+func (c *Client) ExportRefundKit(ctx context.Context, path string) (string, error) {
+	return c.callString(ctx, "exportrefundkit", &rpcserver.ExportRefundKitParams{Path: path})
+}
+
+//
+// Bridge trades
+//
+
+// EstimateBridgeTrade estimates the fees and duration of a bridge trade.
+func (c *Client) EstimateBridgeTrade(ctx context.Context, params *rpcserver.EstimateBridgeTradeParams) (*core.BridgeTradeEstimate, error) {
+	res := new(core.BridgeTradeEstimate)
+	return res, c.Call(ctx, "estimatebridgetrade", params, res)
+}
+
+// StartBridgeTrade starts a bridge trade.
+func (c *Client) StartBridgeTrade(ctx context.Context, params *rpcserver.StartBridgeTradeParams) (*db.BridgeTrade, error) {
+	res := new(db.BridgeTrade)
+	return res, c.Call(ctx, "startbridgetrade", params, res)
+}
+
+// BridgeTrades lists the bridge trades.
+func (c *Client) BridgeTrades(ctx context.Context) ([]*db.BridgeTrade, error) {
+	var res []*db.BridgeTrade
+	return res, c.Call(ctx, "bridgetrades", nil, &res)
+}
+
+// CancelBridgeTrade stops an active bridge trade.
+func (c *Client) CancelBridgeTrade(ctx context.Context, id string) (string, error) {
+	return c.callString(ctx, "cancelbridgetrade", &rpcserver.BridgeTradeIDParams{ID: id})
+}
@@ -0,0 +1,449 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package rpcserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope is a permission that may be granted to an API token. Every route
+// requires exactly one scope. Any token may use routes that require ScopeRead.
+type Scope string
+
+const (
+	// ScopeRead permits routes that only retrieve information.
+	ScopeRead Scope = "read"
+	// ScopeTrade permits placing and canceling orders.
+	ScopeTrade Scope = "trade"
+	// ScopeMM permits starting, stopping and updating market making bots.
+	ScopeMM Scope = "mm"
+	// ScopeSend permits routes that send funds out of the wallets.
+	ScopeSend Scope = "send"
+	// ScopeAdmin permits everything, including wallet and account
+	// configuration, exporting the app seed, and managing API tokens. The
+	// RPC username and password always have the admin scope.
+	ScopeAdmin Scope = "admin"
+)
+
+var knownScopes = []Scope{ScopeRead, ScopeTrade, ScopeMM, ScopeSend, ScopeAdmin}
+
+// apiTokenPrefix is prepended to the API token secrets so that they are
+// recognizable.
+const apiTokenPrefix = "bwt_"
+
+// routeScopes is the scope required for each route. A route that is not
+// listed requires ScopeAdmin.
+var routeScopes = map[string]Scope{
+	// Read-only
+	exchangesRoute:             ScopeRead,
+	helpRoute:                  ScopeRead,
+	myOrdersRoute:              ScopeRead,
+	orderBookRoute:             ScopeRead,
+	getDEXConfRoute:            ScopeRead,
+	bondAssetsRoute:            ScopeRead,
+	versionRoute:               ScopeRead,
+	walletBalanceRoute:         ScopeRead,
+	walletStateRoute:           ScopeRead,
+	walletsRoute:               ScopeRead,
+	batchTxFeeRoute:            ScopeRead,
+	listCoinsRoute:             ScopeRead,
+	walletPeersRoute:           ScopeRead,
+	notificationsRoute:         ScopeRead,
+	mmAvailableBalancesRoute:   ScopeRead,
+	mmStatusRoute:              ScopeRead,
+	mmReportRoute:              ScopeRead,
+	stakeStatusRoute:           ScopeRead,
+	txHistoryRoute:             ScopeRead,
+	walletTxRoute:              ScopeRead,
+	searchTxHistoryRoute:       ScopeRead,
+	validateGaslessRedeemRoute: ScopeRead,
+	checkBridgeApprovalRoute:   ScopeRead,
+	pendingBridgesRoute:        ScopeRead,
+	bridgeHistoryRoute:         ScopeRead,
+	supportedBridgesRoute:      ScopeRead,
+	bridgeFeesAndLimitsRoute:   ScopeRead,
+	paymentMultisigPubkeyRoute: ScopeRead,
+	viewPaymentMultisigRoute:   ScopeRead,
+	webhooksRoute:              ScopeRead,
+	webhookDeliveriesRoute:     ScopeRead,
+	priceAlertsRoute:           ScopeRead,
+	labelsRoute:                ScopeRead,
+	contactsRoute:              ScopeRead,
+	exportTradesRoute:          ScopeRead,
+	estimateBridgeTradeRoute:   ScopeRead,
+	bridgeTradesRoute:          ScopeRead,
+	// Trading
+	tradeRoute:                 ScopeTrade,
+	multiTradeRoute:            ScopeTrade,
+	cancelRoute:                ScopeTrade,
+	gaslessRedeemCalldataRoute: ScopeTrade,
+	submitGaslessRedeemRoute:   ScopeTrade,
+	// Market making
+	startBotRoute:            ScopeMM,
+	stopBotRoute:             ScopeMM,
+	updateRunningBotCfgRoute: ScopeMM,
+	updateRunningBotInvRoute: ScopeMM,
+	pruneMMSnapshotsRoute:    ScopeMM,
+	// Sending funds
+	withdrawRoute:              ScopeSend,
+	sendRoute:                  ScopeSend,
+	sendBatchRoute:             ScopeSend,
+	sendWithCoinsRoute:         ScopeSend,
+	abandonTxRoute:             ScopeSend,
+	withdrawBchSpvRoute:        ScopeSend,
+	purchaseTicketsRoute:       ScopeSend,
+	bridgeRoute:                ScopeSend,
+	approveBridgeContractRoute: ScopeSend,
+	sendFundsToMultisigRoute:   ScopeSend,
+	signMultisigRoute:          ScopeSend,
+	refundPaymentMultisigRoute: ScopeSend,
+	sendPaymentMultisigRoute:   ScopeSend,
+	startBridgeTradeRoute:      ScopeSend,
+	cancelBridgeTradeRoute:     ScopeSend,
+}
+
+// routeScope is the scope required to use the route.
+func routeScope(route string) Scope {
+	if scope, found := routeScopes[route]; found {
+		return scope
+	}
+	return ScopeAdmin
+}
+
+// parseScopes parses and validates the scopes, removing duplicates.
+func parseScopes(strs []string) ([]Scope, error) {
+	if len(strs) == 0 {
+		return nil, errors.New("no scopes specified")
+	}
+	scopes := make([]Scope, 0, len(strs))
+	for _, s := range strs {
+		scope := Scope(strings.ToLower(strings.TrimSpace(s)))
+		if !slices.Contains(knownScopes, scope) {
+			return nil, fmt.Errorf("unknown scope %q", s)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// APIToken is a named credential for the RPC server with a limited set of
+// scopes. The token secret is only revealed when the token is created.
+type APIToken struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Scopes     []Scope  `json:"scopes"`
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+	// CreatedMs and ExpirationMs are unix milliseconds. A zero ExpirationMs
+	// means the token doesn't expire.
+	CreatedMs    int64 `json:"createdMs"`
+	ExpirationMs int64 `json:"expirationMs,omitempty"`
+	LastUsedMs   int64 `json:"lastUsedMs,omitempty"`
+	// SecretHash is the hex-encoded sha256 hash of the token secret.
+	SecretHash string `json:"secretHash,omitempty"`
+}
+
+// NewAPITokenResult is the result of the addapitoken route.
+type NewAPITokenResult struct {
+	*APIToken
+	// Token is the token secret, to be used as a bearer token.
+	Token string `json:"token"`
+}
+
+// expired checks whether the token is expired at the time.
+func (t *APIToken) expired(now time.Time) bool {
+	return t.ExpirationMs != 0 && now.UnixMilli() >= t.ExpirationMs
+}
+
+// hasScope checks whether the token grants the scope.
+func (t *APIToken) hasScope(scope Scope) bool {
+	return scope == ScopeRead || slices.Contains(t.Scopes, scope) || slices.Contains(t.Scopes, ScopeAdmin)
+}
+
+// ipAllowed checks whether the IP is in the token's allowlist. An empty
+// allowlist permits any IP. Entries may be IPs or CIDR ranges.
+func (t *APIToken) ipAllowed(ip net.IP) bool {
+	if len(t.AllowedIPs) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, entry := range t.AllowedIPs {
+		if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		} else if allowed := net.ParseIP(entry); allowed != nil && allowed.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAllowedIPs checks that every entry is an IP or a CIDR range.
+func validateAllowedIPs(entries []string) error {
+	for _, entry := range entries {
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return fmt.Errorf("invalid IP or CIDR range %q", entry)
+		}
+	}
+	return nil
+}
+
+// tokenStore manages the API tokens, which are persisted to a JSON file.
+type tokenStore struct {
+	path string
+
+	mtx    sync.RWMutex
+	tokens map[string]*APIToken
+}
+
+// newTokenStore loads the tokens from the file at path. An empty path creates
+// a store that is not persisted.
+func newTokenStore(path string) (*tokenStore, error) {
+	ts := &tokenStore{
+		path:   path,
+		tokens: make(map[string]*APIToken),
+	}
+	if path == "" {
+		return ts, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ts, nil
+		}
+		return nil, fmt.Errorf("error reading API token file: %w", err)
+	}
+	var tokens []*APIToken
+	if err := json.Unmarshal(b, &tokens); err != nil {
+		return nil, fmt.Errorf("error decoding API token file: %w", err)
+	}
+	for _, t := range tokens {
+		ts.tokens[t.ID] = t
+	}
+	return ts, nil
+}
+
+// save writes the tokens to file. The mutex must be held.
+func (ts *tokenStore) save() error {
+	if ts.path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(ts.sortedTokens(), "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ts.path), 0700); err != nil {
+		return err
+	}
+	tmpPath := ts.path + ".tmp"
+	if err := os.WriteFile(tmpPath, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, ts.path)
+}
+
+// sortedTokens is the tokens sorted by creation time. The mutex must be held.
+func (ts *tokenStore) sortedTokens() []*APIToken {
+	tokens := make([]*APIToken, 0, len(ts.tokens))
+	for _, t := range ts.tokens {
+		tokens = append(tokens, t)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		if tokens[i].CreatedMs == tokens[j].CreatedMs {
+			return tokens[i].ID < tokens[j].ID
+		}
+		return tokens[i].CreatedMs < tokens[j].CreatedMs
+	})
+	return tokens
+}
+
+// add creates a new token. The secret is returned with the token and is not
+// stored.
+func (ts *tokenStore) add(name string, scopes []Scope, allowedIPs []string, lifetime time.Duration) (*NewAPITokenResult, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("no token name")
+	}
+	if err := validateAllowedIPs(allowedIPs); err != nil {
+		return nil, err
+	}
+	if lifetime < 0 {
+		return nil, errors.New("negative token lifetime")
+	}
+	var idB [8]byte
+	var secretB [32]byte
+	if _, err := rand.Read(idB[:]); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(secretB[:]); err != nil {
+		return nil, err
+	}
+	id := hex.EncodeToString(idB[:])
+	// The ID is part of the secret so that the token can be found without
+	// comparing against every stored hash.
+	secret := apiTokenPrefix + id + "_" + hex.EncodeToString(secretB[:])
+	secretHash := sha256.Sum256([]byte(secret))
+	now := time.Now()
+	t := &APIToken{
+		ID:         id,
+		Name:       name,
+		Scopes:     scopes,
+		AllowedIPs: allowedIPs,
+		CreatedMs:  now.UnixMilli(),
+		SecretHash: hex.EncodeToString(secretHash[:]),
+	}
+	if lifetime > 0 {
+		t.ExpirationMs = now.Add(lifetime).UnixMilli()
+	}
+
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	for _, existing := range ts.tokens {
+		if existing.Name == name {
+			return nil, fmt.Errorf("a token named %q already exists", name)
+		}
+	}
+	ts.tokens[id] = t
+	if err := ts.save(); err != nil {
+		delete(ts.tokens, id)
+		return nil, fmt.Errorf("error saving API tokens: %w", err)
+	}
+	tCopy := *t
+	tCopy.SecretHash = ""
+	return &NewAPITokenResult{APIToken: &tCopy, Token: secret}, nil
+}
+
+// revoke deletes the token with the ID or name.
+func (ts *tokenStore) revoke(idOrName string) (*APIToken, error) {
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	t, found := ts.tokens[idOrName]
+	if !found {
+		for _, existing := range ts.tokens {
+			if existing.Name == idOrName {
+				t = existing
+				break
+			}
+		}
+	}
+	if t == nil {
+		return nil, fmt.Errorf("no token %q", idOrName)
+	}
+	delete(ts.tokens, t.ID)
+	if err := ts.save(); err != nil {
+		ts.tokens[t.ID] = t
+		return nil, fmt.Errorf("error saving API tokens: %w", err)
+	}
+	return t, nil
+}
+
+// list is a copy of the tokens, sorted by creation time.
+func (ts *tokenStore) list() []*APIToken {
+	ts.mtx.RLock()
+	defer ts.mtx.RUnlock()
+	tokens := ts.sortedTokens()
+	for i, t := range tokens {
+		tCopy := *t
+		tokens[i] = &tCopy
+	}
+	return tokens
+}
+
+// authenticate finds the token with the secret. It is an error if the token
+// is unknown, expired or not permitted from the IP.
+func (ts *tokenStore) authenticate(secret string, ip net.IP) (*APIToken, error) {
+	if !strings.HasPrefix(secret, apiTokenPrefix) {
+		return nil, errors.New("malformed token")
+	}
+	id, _, found := strings.Cut(strings.TrimPrefix(secret, apiTokenPrefix), "_")
+	if !found {
+		return nil, errors.New("malformed token")
+	}
+	secretHash := sha256.Sum256([]byte(secret))
+
+	ts.mtx.Lock()
+	defer ts.mtx.Unlock()
+	t, found := ts.tokens[id]
+	if !found {
+		return nil, errors.New("unknown token")
+	}
+	storedHash, err := hex.DecodeString(t.SecretHash)
+	if err != nil || subtle.ConstantTimeCompare(storedHash, secretHash[:]) != 1 {
+		return nil, errors.New("unknown token")
+	}
+	now := time.Now()
+	if t.expired(now) {
+		return nil, fmt.Errorf("token %q is expired", t.Name)
+	}
+	if !t.ipAllowed(ip) {
+		return nil, fmt.Errorf("token %q is not permitted from %s", t.Name, ip)
+	}
+	// LastUsedMs is not persisted on every request.
+	t.LastUsedMs = now.UnixMilli()
+	tCopy := *t
+	return &tCopy, nil
+}
+
+// authInfo describes the credentials used for a request.
+type authInfo struct {
+	// token is nil for the RPC username and password.
+	token *APIToken
+	ip    string
+}
+
+// hasScope checks whether the credentials grant the scope.
+func (a *authInfo) hasScope(scope Scope) bool {
+	return a.token == nil || a.token.hasScope(scope)
+}
+
+// String describes the credentials for the audit log.
+func (a *authInfo) String() string {
+	if a.token == nil {
+		return fmt.Sprintf("admin credentials from %s", a.ip)
+	}
+	return fmt.Sprintf("token %q (%s) from %s", a.token.Name, a.token.ID, a.ip)
+}
+
+type authCtxKey struct{}
+
+// requestAuth is the authInfo added to the request context by the auth
+// middleware.
+func requestAuth(ctx context.Context) *authInfo {
+	if a, ok := ctx.Value(authCtxKey{}).(*authInfo); ok {
+		return a
+	}
+	return nil
+}
+
+// remoteIP parses the IP from the request's remote address.
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
@@ -0,0 +1,195 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+//go:build !live
+
+package rpcserver
+
+import (
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRouteScopes(t *testing.T) {
+	for route := range routeScopes {
+		if _, found := routes[route]; !found {
+			t.Fatalf("scope for unknown route %s", route)
+		}
+	}
+	for _, route := range []string{appSeedRoute, initRoute, newWalletRoute, addAPITokenRoute,
+		revokeAPITokenRoute, apiTokensRoute, deployContractRoute, addContactRoute} {
+		if scope := routeScope(route); scope != ScopeAdmin {
+			t.Fatalf("%s requires the %s scope, not admin", route, scope)
+		}
+	}
+	for _, route := range []string{withdrawRoute, sendRoute, sendBatchRoute, bridgeRoute} {
+		if scope := routeScope(route); scope != ScopeSend {
+			t.Fatalf("%s requires the %s scope, not send", route, scope)
+		}
+	}
+	if routeScope(tradeRoute) != ScopeTrade || routeScope(startBotRoute) != ScopeMM || routeScope(walletsRoute) != ScopeRead {
+		t.Fatalf("wrong trade, mm or read scope")
+	}
+
+	trader := &APIToken{Scopes: []Scope{ScopeTrade}}
+	if !trader.hasScope(ScopeRead) || !trader.hasScope(ScopeTrade) || trader.hasScope(ScopeSend) || trader.hasScope(ScopeAdmin) {
+		t.Fatalf("wrong trade token permissions")
+	}
+	admin := &APIToken{Scopes: []Scope{ScopeAdmin}}
+	if !admin.hasScope(ScopeSend) || !admin.hasScope(ScopeMM) {
+		t.Fatalf("wrong admin token permissions")
+	}
+
+	if _, err := parseScopes([]string{"read", "Trade", "trade"}); err != nil {
+		t.Fatalf("parseScopes error: %v", err)
+	}
+	if _, err := parseScopes([]string{"everything"}); err == nil {
+		t.Fatalf("no error for unknown scope")
+	}
+	if _, err := parseScopes(nil); err == nil {
+		t.Fatalf("no error for no scopes")
+	}
+}
+
+func TestTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	ts, err := newTokenStore(path)
+	if err != nil {
+		t.Fatalf("newTokenStore error: %v", err)
+	}
+	localhost := net.ParseIP("127.0.0.1")
+
+	monitor, err := ts.add("monitor", []Scope{ScopeRead}, nil, 0)
+	if err != nil {
+		t.Fatalf("error adding token: %v", err)
+	}
+	if monitor.SecretHash != "" {
+		t.Fatalf("secret hash returned")
+	}
+	if _, err := ts.add("monitor", []Scope{ScopeRead}, nil, 0); err == nil {
+		t.Fatalf("no error for duplicate name")
+	}
+	if _, err := ts.add("bad ip", []Scope{ScopeRead}, []string{"localhost"}, 0); err == nil {
+		t.Fatalf("no error for bad IP")
+	}
+	bot, err := ts.add("bot", []Scope{ScopeTrade}, []string{"10.0.0.0/8", "127.0.0.1"}, time.Hour)
+	if err != nil {
+		t.Fatalf("error adding token: %v", err)
+	}
+	if bot.ExpirationMs == 0 {
+		t.Fatalf("no expiration")
+	}
+
+	// Reload from file.
+	if ts, err = newTokenStore(path); err != nil {
+		t.Fatalf("error reloading tokens: %v", err)
+	}
+	if n := len(ts.list()); n != 2 {
+		t.Fatalf("wanted 2 tokens, got %d", n)
+	}
+
+	tok, err := ts.authenticate(monitor.Token, localhost)
+	if err != nil {
+		t.Fatalf("authenticate error: %v", err)
+	}
+	if tok.Name != "monitor" || tok.LastUsedMs == 0 {
+		t.Fatalf("wrong token authenticated")
+	}
+	if _, err := ts.authenticate(monitor.Token[:len(monitor.Token)-1]+"0", localhost); err == nil {
+		t.Fatalf("no error for wrong secret")
+	}
+	if _, err := ts.authenticate("abc", localhost); err == nil {
+		t.Fatalf("no error for malformed token")
+	}
+
+	// IP allowlist.
+	if _, err := ts.authenticate(bot.Token, net.ParseIP("10.1.2.3")); err != nil {
+		t.Fatalf("error for IP in range: %v", err)
+	}
+	if _, err := ts.authenticate(bot.Token, localhost); err != nil {
+		t.Fatalf("error for allowed IP: %v", err)
+	}
+	if _, err := ts.authenticate(bot.Token, net.ParseIP("192.168.0.2")); err == nil {
+		t.Fatalf("no error for IP not allowed")
+	}
+
+	// Expiry.
+	ts.tokens[bot.ID].ExpirationMs = time.Now().Add(-time.Second).UnixMilli()
+	if _, err := ts.authenticate(bot.Token, localhost); err == nil {
+		t.Fatalf("no error for expired token")
+	}
+
+	// Revoke by name.
+	if _, err := ts.revoke("monitor"); err != nil {
+		t.Fatalf("revoke error: %v", err)
+	}
+	if _, err := ts.authenticate(monitor.Token, localhost); err == nil {
+		t.Fatalf("no error for revoked token")
+	}
+	if _, err := ts.revoke("monitor"); err == nil {
+		t.Fatalf("no error revoking unknown token")
+	}
+	if ts, err = newTokenStore(path); err != nil {
+		t.Fatalf("error reloading tokens: %v", err)
+	}
+	if n := len(ts.list()); n != 1 {
+		t.Fatalf("wanted 1 token after revoke, got %d", n)
+	}
+}
+
+func TestAuthMiddlewareAPIToken(t *testing.T) {
+	s, shutdown := newTServer(t, false, "", "abc")
+	defer shutdown()
+	var gotAuth *authInfo
+	am := s.authMiddleware(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			gotAuth = requestAuth(r.Context())
+			w.WriteHeader(http.StatusOK)
+		}))
+	tok, err := s.tokens.add("bot", []Scope{ScopeTrade}, []string{"127.0.0.1"}, 0)
+	if err != nil {
+		t.Fatalf("error adding token: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name, header, remoteAddr string
+		wantCode                 int
+	}{{
+		name:       "ok",
+		header:     "Bearer " + tok.Token,
+		remoteAddr: "127.0.0.1:5555",
+		wantCode:   http.StatusOK,
+	}, {
+		name:       "wrong IP",
+		header:     "Bearer " + tok.Token,
+		remoteAddr: "127.0.0.2:5555",
+		wantCode:   http.StatusUnauthorized,
+	}, {
+		name:       "bad token",
+		header:     "Bearer bwt_abc_def",
+		remoteAddr: "127.0.0.1:5555",
+		wantCode:   http.StatusUnauthorized,
+	}} {
+		gotAuth = nil
+		r, _ := http.NewRequest("GET", "", nil)
+		r.RemoteAddr = tt.remoteAddr
+		r.Header.Set("Authorization", tt.header)
+		w := &tResponseWriter{}
+		am.ServeHTTP(w, r)
+		if w.code != tt.wantCode {
+			t.Fatalf("%s: wanted code %d, got %d", tt.name, tt.wantCode, w.code)
+		}
+		if tt.wantCode != http.StatusOK {
+			continue
+		}
+		if gotAuth == nil || gotAuth.token == nil || gotAuth.token.ID != tok.ID {
+			t.Fatalf("%s: token not in request context", tt.name)
+		}
+		if gotAuth.hasScope(ScopeSend) || !gotAuth.hasScope(ScopeTrade) {
+			t.Fatalf("%s: wrong scopes", tt.name)
+		}
+	}
+}
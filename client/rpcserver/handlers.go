@@ -6,7 +6,9 @@ package rpcserver
 import (
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -16,6 +18,7 @@ import (
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
@@ -51,9 +54,14 @@ const (
 	walletStateRoute           = "walletstate"
 	walletsRoute               = "wallets"
 	rescanWalletRoute          = "rescanwallet"
+	addCustomTokenRoute        = "addcustomtoken"
 	abandonTxRoute             = "abandontx"
 	withdrawRoute              = "withdraw"
 	sendRoute                  = "send"
+	sendBatchRoute             = "sendbatch"
+	batchTxFeeRoute            = "batchtxfee"
+	listCoinsRoute             = "listcoins"
+	sendWithCoinsRoute         = "sendwithcoins"
 	appSeedRoute               = "appseed"
 	deleteArchivedRecordsRoute = "deletearchivedrecords"
 	walletPeersRoute           = "walletpeers"
@@ -92,18 +100,44 @@ const (
 	sendPaymentMultisigRoute   = "sendpaymentmultisig"
 	mmReportRoute              = "mmreport"
 	pruneMMSnapshotsRoute      = "prunemmsnapshots"
+	addWebhookRoute            = "addwebhook"
+	removeWebhookRoute         = "removewebhook"
+	toggleWebhookRoute         = "togglewebhook"
+	webhooksRoute              = "webhooks"
+	webhookDeliveriesRoute     = "webhookdeliveries"
+	addPriceAlertRoute         = "addpricealert"
+	removePriceAlertRoute      = "removepricealert"
+	priceAlertsRoute           = "pricealerts"
+	searchTxHistoryRoute       = "searchtxhistory"
+	setLabelRoute              = "setlabel"
+	labelsRoute                = "labels"
+	addContactRoute            = "addcontact"
+	removeContactRoute         = "removecontact"
+	contactsRoute              = "contacts"
+	addAPITokenRoute           = "addapitoken"
+	revokeAPITokenRoute        = "revokeapitoken"
+	apiTokensRoute             = "apitokens"
+	exportTradesRoute          = "exporttrades"
+	refundKitRoute             = "refundkit"
+	exportRefundKitRoute       = "exportrefundkit"
+	estimateBridgeTradeRoute   = "estimatebridgetrade"
+	startBridgeTradeRoute      = "startbridgetrade"
+	bridgeTradesRoute          = "bridgetrades"
+	cancelBridgeTradeRoute     = "cancelbridgetrade"
 )
 
 const (
-	initializedStr    = "app initialized"
-	walletCreatedStr  = "%s wallet created and unlocked"
-	walletLockedStr   = "%s wallet locked"
-	walletUnlockedStr = "%s wallet unlocked"
-	canceledOrderStr  = "canceled order %s"
-	logoutStr         = "goodbye"
-	walletStatusStr   = "%s wallet has been %s"
-	setVotePrefsStr   = "vote preferences set"
-	setVSPStr         = "vsp set to %s"
+	initializedStr     = "app initialized"
+	walletCreatedStr   = "%s wallet created and unlocked"
+	walletLockedStr    = "%s wallet locked"
+	walletUnlockedStr  = "%s wallet unlocked"
+	canceledOrderStr   = "canceled order %s"
+	logoutStr          = "goodbye"
+	walletStatusStr    = "%s wallet has been %s"
+	setVotePrefsStr    = "vote preferences set"
+	setVSPStr          = "vsp set to %s"
+	exportRefundKitStr = "refund kit written to %s"
+	canceledBridgeStr  = "canceled bridge trade %s"
 )
 
 // createResponse creates a msgjson response payload.
@@ -159,9 +193,14 @@ var routes = map[string]func(s *RPCServer, msg *msgjson.Message) *msgjson.Respon
 	walletStateRoute:           handleWalletState,
 	walletsRoute:               handleWallets,
 	rescanWalletRoute:          handleRescanWallet,
+	addCustomTokenRoute:        handleAddCustomToken,
 	abandonTxRoute:             handleAbandonTx,
 	withdrawRoute:              handleWithdraw,
 	sendRoute:                  handleSend,
+	sendBatchRoute:             handleSendBatch,
+	batchTxFeeRoute:            handleBatchTxFee,
+	listCoinsRoute:             handleListCoins,
+	sendWithCoinsRoute:         handleSendWithCoins,
 	appSeedRoute:               handleAppSeed,
 	deleteArchivedRecordsRoute: handleDeleteArchivedRecords,
 	walletPeersRoute:           handleWalletPeers,
@@ -200,6 +239,30 @@ var routes = map[string]func(s *RPCServer, msg *msgjson.Message) *msgjson.Respon
 	sendPaymentMultisigRoute:   handleSendPaymentMultisig,
 	mmReportRoute:              handleMMReport,
 	pruneMMSnapshotsRoute:      handlePruneMMSnapshots,
+	addWebhookRoute:            handleAddWebhook,
+	removeWebhookRoute:         handleRemoveWebhook,
+	toggleWebhookRoute:         handleToggleWebhook,
+	webhooksRoute:              handleWebhooks,
+	webhookDeliveriesRoute:     handleWebhookDeliveries,
+	addPriceAlertRoute:         handleAddPriceAlert,
+	removePriceAlertRoute:      handleRemovePriceAlert,
+	priceAlertsRoute:           handlePriceAlerts,
+	searchTxHistoryRoute:       handleSearchTxHistory,
+	setLabelRoute:              handleSetLabel,
+	labelsRoute:                handleLabels,
+	addContactRoute:            handleAddContact,
+	removeContactRoute:         handleRemoveContact,
+	contactsRoute:              handleContacts,
+	addAPITokenRoute:           handleAddAPIToken,
+	revokeAPITokenRoute:        handleRevokeAPIToken,
+	apiTokensRoute:             handleAPITokens,
+	exportTradesRoute:          handleExportTrades,
+	refundKitRoute:             handleRefundKit,
+	exportRefundKitRoute:       handleExportRefundKit,
+	estimateBridgeTradeRoute:   handleEstimateBridgeTrade,
+	startBridgeTradeRoute:      handleStartBridgeTrade,
+	bridgeTradesRoute:          handleBridgeTrades,
+	cancelBridgeTradeRoute:     handleCancelBridgeTrade,
 }
 
 //
@@ -495,6 +558,22 @@ func handleRescanWallet(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePay
 	return createResponse(rescanWalletRoute, "started", nil)
 }
 
+// handleAddCustomToken handles requests to add a user-defined token by
+// contract address. *msgjson.ResponsePayload.Error is empty if successful.
+func handleAddCustomToken(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params AddCustomTokenParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(addCustomTokenRoute, err)
+	}
+	defer params.AppPass.Clear()
+	tokenID, err := s.core.AddCustomToken(params.AppPass, params.AssetID, params.Address)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCCreateWalletError, "unable to add custom token: %v", err)
+		return createResponse(addCustomTokenRoute, nil, resErr)
+	}
+	return createResponse(addCustomTokenRoute, tokenID, nil)
+}
+
 //
 // handlers_trading handlers
 //
@@ -583,7 +662,7 @@ func handleTrade(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
 		resErr := msgjson.NewError(msgjson.RPCTradeError, "unable to trade: %v", err)
 		return createResponse(tradeRoute, nil, resErr)
 	}
-	tradeRes := &tradeResponse{
+	tradeRes := &TradeResponse{
 		OrderID: res.ID.String(),
 		Sig:     res.Sig.String(),
 		Stamp:   res.Stamp,
@@ -598,16 +677,16 @@ func handleMultiTrade(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePaylo
 	}
 	defer params.AppPass.Clear()
 	results := s.core.MultiTrade(params.AppPass, &params.MultiTradeForm)
-	trades := make([]*tradeResponse, 0, len(results))
+	trades := make([]*TradeResponse, 0, len(results))
 	for _, res := range results {
 		if res.Error != nil {
-			trades = append(trades, &tradeResponse{
-				Error: res.Error,
+			trades = append(trades, &TradeResponse{
+				Error: res.Error.Error(),
 			})
 			continue
 		}
 		trade := res.Order
-		trades = append(trades, &tradeResponse{
+		trades = append(trades, &TradeResponse{
 			OrderID: trade.ID.String(),
 			Sig:     trade.Sig.String(),
 			Stamp:   trade.Stamp,
@@ -671,12 +750,12 @@ func handleOrderBook(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayloa
 	return createResponse(orderBookRoute, book, nil)
 }
 
-// parseCoreOrder converts a *core.Order into a *myOrder.
-func parseCoreOrder(co *core.Order, b, q uint32) *myOrder {
+// parseCoreOrder converts a *core.Order into a *MyOrder.
+func parseCoreOrder(co *core.Order, b, q uint32) *MyOrder {
 	// matchesParser parses core.Match slice & calculates how much of the order
 	// has been settled/finalized.
-	parseMatches := func(matches []*core.Match) (ms []*match, settled uint64) {
-		ms = make([]*match, 0, len(matches))
+	parseMatches := func(matches []*core.Match) (ms []*Match, settled uint64) {
+		ms = make([]*Match, 0, len(matches))
 		// coinSafeString gets the Coin's StringID safely.
 		coinSafeString := func(c *core.Coin) string {
 			if c == nil {
@@ -690,7 +769,7 @@ func parseCoreOrder(co *core.Order, b, q uint32) *myOrder {
 				(m.Side == order.Taker && m.Status >= order.MatchComplete) {
 				settled += m.Qty
 			}
-			match := &match{
+			match := &Match{
 				MatchID:  m.MatchID.String(),
 				Status:   m.Status.String(),
 				Revoked:  m.Revoked,
@@ -718,7 +797,7 @@ func parseCoreOrder(co *core.Order, b, q uint32) *myOrder {
 	if co.Status >= order.OrderStatusExecuted {
 		cancelling = false
 	}
-	o := &myOrder{
+	o := &MyOrder{
 		Host:        co.Host,
 		MarketName:  co.MarketID,
 		BaseID:      b,
@@ -751,7 +830,7 @@ func handleMyOrders(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload
 	if err := msg.Unmarshal(&params); err != nil {
 		return usage(myOrdersRoute, err)
 	}
-	var myOrders myOrdersResponse
+	var myOrders MyOrdersResponse
 	filterMkts := params.Base != nil && params.Quote != nil
 	exchanges := s.core.Exchanges()
 	for host, exchange := range exchanges {
@@ -803,7 +882,19 @@ func send(s *RPCServer, msg *msgjson.Message, route string) *msgjson.ResponsePay
 	if route == withdrawRoute {
 		subtract = true
 	}
-	coin, err := s.core.Send(params.AppPass, params.AssetID, params.Value, params.Address, subtract)
+	address := params.Address
+	if params.Contact != "" {
+		if address != "" {
+			resErr := msgjson.NewError(msgjson.RPCArgumentsError, "specify address or contact, not both")
+			return createResponse(route, nil, resErr)
+		}
+		var err error
+		if address, err = s.core.ContactAddress(params.AssetID, params.Contact); err != nil {
+			resErr := msgjson.NewError(msgjson.RPCArgumentsError, "%v", err)
+			return createResponse(route, nil, resErr)
+		}
+	}
+	coin, err := s.core.Send(params.AppPass, params.AssetID, params.Value, address, subtract)
 	if err != nil {
 		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "unable to %s: %v", route, err)
 		return createResponse(route, nil, resErr)
@@ -812,6 +903,105 @@ func send(s *RPCServer, msg *msgjson.Message, route string) *msgjson.ResponsePay
 	return createResponse(route, &res, nil)
 }
 
+// batchRecipients returns the recipients from the params, parsing the CSV
+// data if provided.
+func (s *RPCServer) batchRecipients(assetID uint32, recipients []*asset.BatchRecipient, csv *string) ([]*asset.BatchRecipient, error) {
+	if csv != nil {
+		if len(recipients) > 0 {
+			return nil, errors.New("specify recipients or csv, not both")
+		}
+		return s.core.ParseBatchSendCSV(assetID, *csv)
+	}
+	return recipients, nil
+}
+
+// handleSendBatch handles requests to send to multiple recipients.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleSendBatch(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params SendBatchParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(sendBatchRoute, err)
+	}
+	defer params.AppPass.Clear()
+	if len(params.AppPass) == 0 {
+		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "empty pass")
+		return createResponse(sendBatchRoute, nil, resErr)
+	}
+	recipients, err := s.batchRecipients(params.AssetID, params.Recipients, params.CSV)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCArgumentsError, "invalid recipients: %v", err)
+		return createResponse(sendBatchRoute, nil, resErr)
+	}
+	txIDs, err := s.core.SendBatch(params.AppPass, params.AssetID, recipients)
+	if err != nil {
+		if len(txIDs) > 0 {
+			err = fmt.Errorf("%w (broadcast txs: %s)", err, strings.Join(txIDs, ", "))
+		}
+		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "unable to send batch: %v", err)
+		return createResponse(sendBatchRoute, nil, resErr)
+	}
+	return createResponse(sendBatchRoute, txIDs, nil)
+}
+
+// handleListCoins handles requests to list a wallet's unspent outputs.
+func handleListCoins(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params ListCoinsParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(listCoinsRoute, err)
+	}
+	coins, err := s.core.SpendableCoins(params.AssetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCListCoinsError, "unable to list coins: %v", err)
+		return createResponse(listCoinsRoute, nil, resErr)
+	}
+	return createResponse(listCoinsRoute, coins, nil)
+}
+
+// handleSendWithCoins handles requests to send using specific coins.
+// *msgjson.ResponsePayload.Error is empty if successful.
+func handleSendWithCoins(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params SendWithCoinsParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(sendWithCoinsRoute, err)
+	}
+	defer params.AppPass.Clear()
+	if len(params.AppPass) == 0 {
+		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "empty pass")
+		return createResponse(sendWithCoinsRoute, nil, resErr)
+	}
+	coin, err := s.core.SendWithCoins(params.AppPass, params.AssetID, &asset.CoinControlSend{
+		Coins:         params.Coins,
+		Address:       params.Address,
+		Value:         params.Value,
+		ChangeAddress: params.ChangeAddress,
+	})
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "unable to send: %v", err)
+		return createResponse(sendWithCoinsRoute, nil, resErr)
+	}
+	res := coin.String()
+	return createResponse(sendWithCoinsRoute, &res, nil)
+}
+
+// handleBatchTxFee handles requests to estimate the fees for a batch send.
+func handleBatchTxFee(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params BatchTxFeeParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(batchTxFeeRoute, err)
+	}
+	recipients, err := s.batchRecipients(params.AssetID, params.Recipients, params.CSV)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCArgumentsError, "invalid recipients: %v", err)
+		return createResponse(batchTxFeeRoute, nil, resErr)
+	}
+	fee, err := s.core.EstimateBatchSendTxFee(params.AssetID, recipients)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCFundTransferError, "unable to estimate batch fees: %v", err)
+		return createResponse(batchTxFeeRoute, nil, resErr)
+	}
+	return createResponse(batchTxFeeRoute, fee, nil)
+}
+
 // handleAbandonTx handles requests to abandon an unconfirmed transaction.
 // This marks the transaction and all its descendants as abandoned, allowing
 // the wallet to forget about it. *msgjson.ResponsePayload.Error is empty if
@@ -1041,7 +1231,7 @@ func handleBondAssets(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePaylo
 			return createResponse(bondAssetsRoute, nil, resErr)
 		}
 	}
-	res := &getBondAssetsResponse{
+	res := &BondAssetsResponse{
 		Expiry: exchCfg.BondExpiry,
 		Assets: exchCfg.BondAssets,
 	}
@@ -1173,10 +1363,7 @@ func handleMMAvailableBalances(s *RPCServer, msg *msgjson.Message) *msgjson.Resp
 		return createResponse(mmAvailableBalancesRoute, nil, resErr)
 	}
 
-	res := struct {
-		DEXBalances map[uint32]uint64 `json:"dexBalances"`
-		CEXBalances map[uint32]uint64 `json:"cexBalances"`
-	}{
+	res := &MMAvailableBalancesResponse{
 		DEXBalances: dexBalances,
 		CEXBalances: cexBalances,
 	}
@@ -1983,13 +2170,18 @@ var routeInfos = map[string]routeInfo{
 	},
 	bondOptionsRoute: {
 		paramsType: reflect.TypeFor[core.BondOptionsForm](),
-		summary:    `Change bond options for a DEX.`,
+		summary: `Change bond options for a DEX. With a list of bond assets, each bond renewal
+    uses the assets with the lowest fees that can fund it, preferring earlier
+    assets when fees are similar. The planner's decision is reported in the
+    "bondPlan" of the exchange's "auth" in the exchanges result.`,
 		fieldDescs: map[string]string{
 			"host":         descHost,
 			"targetTier":   "The target trading tier.",
 			"maxBondedAmt": "The maximum amount that may be locked in bonds.",
 			"bondAssetID":  "The asset ID with which to auto-post bonds.",
 			"penaltyComps": "The maximum number of penalties to compensate.",
+			"bondAssetIDs": "JSON array of asset IDs for bond renewals, in order of preference. The first is the primary bond asset. An empty array always uses bondAssetID.",
+			"splitBonds":   "Whether a renewal may be split across assets when no single asset can fund it.",
 		},
 		returns: `Returns: "ok"`,
 	},
@@ -2113,6 +2305,7 @@ var routeInfos = map[string]routeInfo{
       "sig" (string): The DEX's signature of the order information.
       "stamp" (int): The time the order was signed in milliseconds since 00:00:00
         Jan 1 1970.
+      "error" (string): Why the order could not be placed, if it failed.
     }]`,
 	},
 	cancelRoute: {
@@ -2139,6 +2332,19 @@ needed to complete a swap.`,
 		},
 		returns: `Returns:
     string: "started"`,
+	},
+	addCustomTokenRoute: {
+		paramsType: reflect.TypeFor[AddCustomTokenParams](),
+		summary: `Add a user-defined ERC20 token by contract address, and create a wallet for
+    it. The token's name, symbol, and decimals are read from the contract. Custom
+    tokens can be sent and received, but are not traded on any DEX.`,
+		fieldDescs: map[string]string{
+			"appPass": descAppPass,
+			"assetID": "The asset ID of the token's chain, e.g. 60 for Ethereum.",
+			"address": "The token's contract address.",
+		},
+		returns: `Returns:
+    int: The asset ID assigned to the token.`,
 	},
 	abandonTxRoute: {
 		paramsType: reflect.TypeFor[AbandonTxParams](),
@@ -2166,6 +2372,7 @@ supported for DCR wallets.`,
 			"value":    "The amount to withdraw in units of the asset's smallest denomination (e.g. satoshis, atoms, etc.)",
 			"address":  "The address to which withdrawn funds are sent.",
 			"subtract": "Ignored for withdraw (always true). Use 'send' route for exact-amount sends.",
+			"contact":  "The name of an address book contact to withdraw to instead of address.",
 		},
 		returns: `Returns:
     string: "[coin ID]"`,
@@ -2179,6 +2386,72 @@ supported for DCR wallets.`,
 			"value":    "The amount to send in units of the asset's smallest denomination (e.g. satoshis, atoms, etc.)",
 			"address":  "The address to which funds are sent.",
 			"subtract": "Whether to subtract the tx fee from the value.",
+			"contact":  "The name of an address book contact to send to instead of address.",
+		},
+		returns: `Returns:
+    string: "[coin ID]"`,
+	},
+	sendBatchRoute: {
+		paramsType: reflect.TypeFor[SendBatchParams](),
+		summary: `Sends exact values from an exchange wallet to multiple recipients. UTXO-based
+    wallets pay every recipient in a single transaction. Account-based wallets
+    do not batch. They send N sequential transactions for N recipients, and
+    each pays its own network fee. Provide either recipients or csv.`,
+		fieldDescs: map[string]string{
+			"appPass":    descAppPass,
+			"assetID":    descAssetID,
+			"recipients": `A JSON array of recipients, e.g. [{"address":"abc","value":100000000}]. Values are in units of the asset's smallest denomination.`,
+			"csv":        `CSV data with an address and an amount in conventional units (e.g. 1.5) per line. A header line and lines beginning with # are ignored.`,
+		},
+		returns: `Returns:
+    array: The IDs of the broadcast transactions, one for UTXO-based
+      wallets, or one per recipient for account-based wallets.`,
+	},
+	batchTxFeeRoute: {
+		paramsType: reflect.TypeFor[BatchTxFeeParams](),
+		summary:    `Estimates the total network fees for a batch send. See sendbatch.`,
+		fieldDescs: map[string]string{
+			"assetID":    descAssetID,
+			"recipients": `A JSON array of recipients, e.g. [{"address":"abc","value":100000000}]. Values are in units of the asset's smallest denomination.`,
+			"csv":        `CSV data with an address and an amount in conventional units per line.`,
+		},
+		returns: `Returns:
+    int: The estimated fees in units of the asset's smallest denomination.`,
+	},
+	listCoinsRoute: {
+		paramsType: reflect.TypeFor[ListCoinsParams](),
+		summary: `Lists the unspent outputs in a UTXO-based wallet for coin control. Coins
+    locked by active orders or bonds are included with locked set to true.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+		},
+		returns: `Returns:
+    array: The unspent outputs.
+    [
+      {
+        "id" (string): The coin ID.
+        "txID" (string): The transaction ID.
+        "vout" (int): The output index.
+        "address" (string): The address paid by the output.
+        "value" (int): The value in units of the asset's smallest denomination.
+        "confs" (int): The number of confirmations.
+        "label" (string): The wallet's label for the output, if any.
+        "locked" (bool): Whether the coin is locked by an active order or bond.
+      },...
+    ]`,
+	},
+	sendWithCoinsRoute: {
+		paramsType: reflect.TypeFor[SendWithCoinsParams](),
+		summary: `Sends from a UTXO-based wallet, spending exactly the specified coins.
+    Coins locked by active orders cannot be spent. If value is omitted, the
+    full value of the coins less fees is sent with no change output.`,
+		fieldDescs: map[string]string{
+			"appPass":       descAppPass,
+			"assetID":       descAssetID,
+			"coins":         `A JSON array of coin IDs from listcoins, e.g. ["abcd...","ef01..."].`,
+			"address":       "The address to which funds are sent.",
+			"value":         "The amount to send in units of the asset's smallest denomination. Omit to send everything with no change.",
+			"changeAddress": "An address for the change output. Defaults to a new wallet address.",
 		},
 		returns: `Returns:
     string: "[coin ID]"`,
@@ -2664,6 +2937,441 @@ supported for DCR wallets.`,
 		returns: `Returns:
     int: the number of snapshots deleted`,
 	},
+	addWebhookRoute: {
+		paramsType: reflect.TypeFor[AddWebhookParams](),
+		summary: `Add a webhook. Matching notifications are POSTed to the URL as
+    JSON, signed with HMAC-SHA256 in the X-Bisonw-Signature header.`,
+		fieldDescs: map[string]string{
+			"url":       "The http or https URL to deliver notifications to.",
+			"topics":    `A JSON array of notification topics to deliver, e.g. '["MatchErrorCoin","BondRefunded"]'.`,
+			"noteTypes": `A JSON array of notification types to deliver, e.g. '["order","bot"]'. If neither topics nor noteTypes are specified, all notifications of severity success or higher are delivered.`,
+			"secret":    "The HMAC secret. If not specified, one is generated.",
+		},
+		returns: `Returns:
+    obj: The new webhook.
+    {
+      "id" (string): The webhook ID.
+      "url" (string): The webhook URL.
+      "secret" (string): The HMAC secret. It is only shown here, when the
+        webhook is added.
+      "topics" (array): The topic filter.
+      "noteTypes" (array): The note type filter.
+      "disabled" (bool): Whether deliveries are disabled.
+    }`,
+	},
+	removeWebhookRoute: {
+		paramsType: reflect.TypeFor[WebhookIDParams](),
+		summary:    `Remove a webhook.`,
+		fieldDescs: map[string]string{
+			"id": "The webhook ID.",
+		},
+	},
+	toggleWebhookRoute: {
+		paramsType: reflect.TypeFor[ToggleWebhookParams](),
+		summary:    `Enable or disable deliveries to a webhook.`,
+		fieldDescs: map[string]string{
+			"id":      "The webhook ID.",
+			"disable": "True to disable the webhook, false to enable it.",
+		},
+	},
+	webhooksRoute: {
+		summary: `List configured webhooks. Secrets are not shown.`,
+		returns: `Returns:
+    array: An array of webhooks. See addwebhook.`,
+	},
+	webhookDeliveriesRoute: {
+		paramsType: reflect.TypeFor[NotificationsParams](),
+		summary:    `See recent webhook deliveries, newest first.`,
+		fieldDescs: map[string]string{
+			"n": "The number of deliveries to return. If <= 0, all logged deliveries are returned.",
+		},
+		returns: `Returns:
+    array: An array of deliveries.
+    [
+      {
+        "id" (string): The delivery ID, also sent in the X-Bisonw-Delivery header.
+        "webhookID" (string): The webhook ID.
+        "url" (string): The webhook URL.
+        "noteType" (string): The notification type.
+        "topic" (string): The notification topic.
+        "attempts" (int): The number of delivery attempts.
+        "statusCode" (int): The HTTP status code of the last attempt.
+        "error" (string): The error from the last attempt, if any.
+        "delivered" (bool): Whether the delivery succeeded.
+        "stamp" (int): The time of the last attempt, in milliseconds.
+      },...
+    ]`,
+	},
+	addPriceAlertRoute: {
+		paramsType: reflect.TypeFor[AddPriceAlertParams](),
+		summary: `Add a price alert. A PriceAlertTriggered notification is sent when the
+    condition is met.`,
+		fieldDescs: map[string]string{
+			"source":     `The price source. "midgap" for a market's book mid-gap rate, or "fiat" for an asset's fiat rate.`,
+			"condition":  `"above" or "below" to compare the price to threshold, or "change" to compare the percent change over windowSecs to threshold.`,
+			"threshold":  `The conventional rate (midgap), fiat price (fiat), or percent change (change). A negative percent change is a drop.`,
+			"host":       "The DEX host. midgap only.",
+			"baseID":     "The market's base asset BIP ID. midgap only.",
+			"quoteID":    "The market's quote asset BIP ID. midgap only.",
+			"assetID":    "The asset's BIP ID. fiat only.",
+			"windowSecs": "The look-back period in seconds. change only.",
+			"rearm":      "Re-arm the alert after the condition is no longer met. Default false, the alert only triggers once.",
+		},
+		returns: `Returns:
+    obj: The new price alert.
+    {
+      "id" (string): The alert ID.
+      "source" (string): The price source.
+      "host" (string): The DEX host.
+      "baseID" (int): The base asset BIP ID.
+      "quoteID" (int): The quote asset BIP ID.
+      "assetID" (int): The fiat asset BIP ID.
+      "condition" (string): The trigger condition.
+      "threshold" (float): The trigger threshold.
+      "windowSecs" (int): The look-back period for change alerts.
+      "rearm" (bool): Whether the alert re-arms.
+      "triggered" (bool): Whether the alert has triggered and not re-armed.
+      "triggerStamp" (int): The time of the last trigger, in milliseconds.
+      "created" (int): The time the alert was created, in milliseconds.
+    }`,
+	},
+	removePriceAlertRoute: {
+		paramsType: reflect.TypeFor[PriceAlertIDParams](),
+		summary:    `Remove a price alert.`,
+		fieldDescs: map[string]string{
+			"id": "The price alert ID.",
+		},
+	},
+	priceAlertsRoute: {
+		summary: `List price alerts.`,
+		returns: `Returns:
+    array: An array of price alerts. See addpricealert.`,
+	},
+	searchTxHistoryRoute: {
+		paramsType: reflect.TypeFor[SearchTxHistoryParams](),
+		summary: `Search a wallet's transaction history. Transactions match if the
+    transaction ID, label, recipient address, or recipient label or contact
+    name contains the query, ignoring case.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+			"query":   "The search text.",
+			"n":       "The maximum number of transactions to return. If <= 0, all matches are returned.",
+		},
+		returns: `Returns:
+    array: The matching transactions, most recent first. See txhistory.`,
+	},
+	setLabelRoute: {
+		paramsType: reflect.TypeFor[SetLabelParams](),
+		summary: `Label a wallet transaction or address. Labels are returned with
+    transaction history as label and recipientLabel.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+			"kind":    `"tx" to label a transaction, or "address" to label an address.`,
+			"id":      "The transaction ID or address.",
+			"label":   "The label. Omit to remove an existing label.",
+		},
+		returns: `Returns:
+    string: Success message on completion.`,
+	},
+	labelsRoute: {
+		paramsType: reflect.TypeFor[LabelsParams](),
+		summary:    `List an asset's transaction and address labels.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+		},
+		returns: `Returns:
+    array: The labels.
+    [
+      {
+        "assetID" (int): The asset's BIP ID.
+        "kind" (string): "tx" or "address".
+        "id" (string): The transaction ID or address.
+        "label" (string): The label.
+      },...
+    ]`,
+	},
+	addContactRoute: {
+		paramsType: reflect.TypeFor[AddContactParams](),
+		summary: `Add a contact to an asset's address book, replacing any contact with
+    the same name. Contacts can be used by name with send and withdraw.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+			"name":    "The contact name. Names are case-insensitive.",
+			"address": "The contact's address.",
+			"note":    "An optional note.",
+		},
+		returns: `Returns:
+    string: Success message on completion.`,
+	},
+	removeContactRoute: {
+		paramsType: reflect.TypeFor[RemoveContactParams](),
+		summary:    `Remove a contact from an asset's address book.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+			"name":    "The contact name.",
+		},
+		returns: `Returns:
+    string: Success message on completion.`,
+	},
+	contactsRoute: {
+		paramsType: reflect.TypeFor[LabelsParams](),
+		summary:    `List an asset's address book.`,
+		fieldDescs: map[string]string{
+			"assetID": descAssetID,
+		},
+		returns: `Returns:
+    array: The contacts, sorted by name.
+    [
+      {
+        "assetID" (int): The asset's BIP ID.
+        "name" (string): The contact name.
+        "address" (string): The contact's address.
+        "note" (string): The note, if any.
+      },...
+    ]`,
+	},
+	addAPITokenRoute: {
+		paramsType: reflect.TypeFor[AddAPITokenParams](),
+		summary: `Issue a named API token. API tokens are used as Bearer tokens in
+    the Authorization header instead of the RPC username and password. Every
+    token can use read-only routes. The token secret is only shown once.`,
+		fieldDescs: map[string]string{
+			"name":       "A unique name for the token.",
+			"scopes":     `A JSON array of scopes to grant, e.g. '["trade","mm"]'. Scopes are read, trade, mm, send and admin.`,
+			"allowedIPs": `A JSON array of IPs or CIDR ranges from which the token may be used, e.g. '["127.0.0.1","10.0.0.0/8"]'. If empty, any IP is allowed.`,
+			"lifetime":   `How long the token is valid, e.g. "720h". If not specified, the token doesn't expire.`,
+		},
+		returns: `Returns:
+    obj: The new token.
+    {
+      "id" (string): The token ID.
+      "name" (string): The token name.
+      "scopes" (array): The granted scopes.
+      "allowedIPs" (array): The IP allowlist.
+      "createdMs" (int): The creation time, in milliseconds.
+      "expirationMs" (int): The expiration time, in milliseconds. Zero if the token doesn't expire.
+      "token" (string): The token secret.
+    }`,
+	},
+	revokeAPITokenRoute: {
+		paramsType: reflect.TypeFor[RevokeAPITokenParams](),
+		summary:    `Revoke an API token.`,
+		fieldDescs: map[string]string{
+			"token": "The token ID or name.",
+		},
+	},
+	apiTokensRoute: {
+		summary: `List the API tokens. Token secrets are not shown.`,
+		returns: `Returns:
+    array: An array of tokens. See addapitoken. Tokens also have a
+    "lastUsedMs" (int) field, the time the token was last used since the
+    last restart, in milliseconds.`,
+	},
+	exportTradesRoute: {
+		paramsType: reflect.TypeFor[ExportTradesParams](),
+		summary: `Export trade history for accounting, one record per match. Cancel
+    order matches are not included. Fees are the order's fees divided
+    between its matches by quantity. Fiat values use the fiat rates
+    recorded when the order was placed, and are zero if no rates were
+    recorded.`,
+		fieldDescs: map[string]string{
+			"from":    "Only export matches at or after this time, in milliseconds.",
+			"to":      "Only export matches before this time, in milliseconds.",
+			"format":  `"json" (default) or "csv".`,
+			"hosts":   `A JSON array of DEX hosts, e.g. '["dex.decred.org:7232"]'.`,
+			"assets":  "A JSON array of asset BIP IDs. Orders on markets with any of the assets are exported.",
+			"baseID":  "The market's base asset BIP ID. Requires quoteID.",
+			"quoteID": "The market's quote asset BIP ID. Requires baseID.",
+			"orderID": "Only export matches for the order with this hex ID.",
+		},
+		returns: `Returns:
+    string: The CSV, if format is "csv".
+    array: Otherwise, an array of match records, sorted by match time.
+    [
+      {
+        "host" (string): The DEX host.
+        "market" (string): The market name.
+        "baseID" (int): The base asset BIP ID.
+        "baseSymbol" (string): The base asset symbol.
+        "quoteID" (int): The quote asset BIP ID.
+        "quoteSymbol" (string): The quote asset symbol.
+        "orderID" (string): The order ID.
+        "orderType" (string): "limit" or "market".
+        "matchID" (string): The match ID.
+        "side" (string): "buy" or "sell".
+        "role" (string): "maker" or "taker".
+        "status" (string): The match status.
+        "revoked" (bool): Whether the match was revoked.
+        "refunded" (bool): Whether our swap was refunded.
+        "rate" (float): The conventional match rate.
+        "qty" (float): The base asset quantity.
+        "quoteQty" (float): The quote asset quantity.
+        "swapFees" (float): Swap fees, in swapFeeSymbol.
+        "swapFeeSymbol" (string): The swap fee asset.
+        "redeemFees" (float): Redeem fees, in redeemFeeSymbol.
+        "redeemFeeSymbol" (string): The redeem fee asset.
+        "fundingFees" (float): Order funding fees, in fundingFeeSymbol.
+        "fundingFeeSymbol" (string): The funding fee asset.
+        "refundFees" (float): Refund fees, in refundFeeSymbol, if the refund
+          transaction is in the wallet history.
+        "refundFeeSymbol" (string): The refund fee asset.
+        "swapTx" (string): Our swap coin ID.
+        "counterSwapTx" (string): The counterparty's swap coin ID.
+        "redeemTx" (string): Our redeem coin ID.
+        "counterRedeemTx" (string): The counterparty's redeem coin ID.
+        "refundTx" (string): Our refund coin ID.
+        "matchStamp" (int): The match time, in milliseconds.
+        "swapStamp" (int): The time of our swap, in milliseconds.
+        "counterSwapStamp" (int): The time of the counterparty's swap.
+        "redeemStamp" (int): The time of our redeem.
+        "counterRedeemStamp" (int): The time of the counterparty's redeem.
+        "baseFiatRate" (float): The base asset USD rate when the order was placed.
+        "quoteFiatRate" (float): The quote asset USD rate when the order was placed.
+        "fiatValue" (float): The USD value of the match.
+        "feesFiatValue" (float): The USD value of the fees.
+      },...
+    ]`,
+	},
+	refundKitRoute: {
+		summary: `Show the emergency refund kit. The kit lists our swaps that may
+    need to be refunded, with signed refund transactions where the asset
+    supports them. It is kept, encrypted with the app password, so that
+    funds can be recovered with the bwrefund tool if the database or
+    machine is lost. Swaps sent before the kit was kept have no signed
+    refund.`,
+		returns: `Returns:
+    obj: The refund kit.
+    {
+      "path" (string): The refund kit file.
+      "entries" (array): The swaps, sorted by lock time.
+      [
+        {
+          "host" (string): The DEX host.
+          "orderID" (string): The order ID.
+          "matchID" (string): The match ID.
+          "assetID" (int): The swap asset BIP ID.
+          "symbol" (string): The swap asset symbol.
+          "value" (int): The contract value, in atoms.
+          "swapCoin" (string): The swap coin ID.
+          "swapCoinID" (string): The raw swap coin ID.
+          "contract" (string): The contract data.
+          "secretHash" (string): The secret hash.
+          "lockTime" (int): The refund lock time, in seconds.
+          "signedRefund" (string): The signed refund transaction, if any.
+          "details" (string): Supplementary swap details.
+        },...
+      ]
+    }`,
+	},
+	exportRefundKitRoute: {
+		paramsType: reflect.TypeFor[ExportRefundKitParams](),
+		summary: `Write a copy of the emergency refund kit, encrypted with the app
+    password, e.g. to removable media. See refundkit.`,
+		fieldDescs: map[string]string{
+			"path": "The file to write.",
+		},
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(exportRefundKitStr, "[path]") + `"`,
+	},
+	estimateBridgeTradeRoute: {
+		paramsType: reflect.TypeFor[EstimateBridgeTradeParams](),
+		summary: `Estimate the fees and duration of a bridge trade, which bridges
+    funds to another chain and trades them, or trades and bridges the
+    proceeds. Durations are rough estimates.`,
+		fieldDescs: bridgeTradeFieldDescs,
+		returns: `Returns:
+    obj: The estimate.
+    {
+      "qty" (int): The order quantity.
+      "bridgeFees" (obj): The bridge fees, keyed by the BIP ID of the fee asset.
+      "tradeFees" (obj): The worst case trade fees, keyed by fee asset.
+      "fees" (obj): The total fees, keyed by fee asset.
+      "feesFiat" (float): The fiat value of the fees.
+      "missingFiatRates" (bool): Whether any fee asset has no fiat rate.
+      "bridgeMinLimit" (int): The minimum bridge amount, if limited.
+      "bridgeMaxLimit" (int): The maximum bridge amount, if limited.
+      "bridgeSecs" (int): The estimated bridge duration, in seconds.
+      "tradeSecs" (int): The estimated trade duration, in seconds.
+      "totalSecs" (int): The estimated total duration, in seconds.
+      "openEnded" (bool): Whether the order may wait on the book for longer.
+    }`,
+	},
+	startBridgeTradeRoute: {
+		paramsType: reflect.TypeFor[StartBridgeTradeParams](),
+		summary: `Start a bridge trade. The steps run in the background and resume
+    after a restart. Progress is reported with bridgetrade notifications.
+    See estimatebridgetrade.`,
+		fieldDescs: func() map[string]string {
+			descs := map[string]string{"appPass": descAppPass}
+			maps.Copy(descs, bridgeTradeFieldDescs)
+			return descs
+		}(),
+		returns: `Returns:
+    obj: The bridge trade. See bridgetrades.`,
+	},
+	bridgeTradesRoute: {
+		summary: `List the bridge trades, oldest first.`,
+		returns: `Returns:
+    array: The bridge trades.
+    [
+      {
+        "id" (string): The bridge trade ID.
+        "tradeFirst" (bool): Whether the trade is placed before the bridge.
+        "bridgeFromAssetID" (int): The bridged asset.
+        "bridgeToAssetID" (int): The bridge destination asset.
+        "bridgeName" (string): The bridge.
+        "bridgeAmount" (int): The amount bridged.
+        "host", "base", "quote", "sell", "isLimit", "rate", "tifnow",
+          "options", "qty": The order. See trade.
+        "step" (string): The current step. One of bridge, trade or done.
+        "status" (string): One of active, complete, failed or canceled.
+        "bridgeInitiating" (int): When the bridge initiation started, in
+          milliseconds.
+        "bridgeTxID" (string): The bridge initiation transaction ID.
+        "bridgeReceived" (int): The amount received from the bridge.
+        "orderPlacing" (int): When placing the order started, in
+          milliseconds.
+        "orderID" (string): The order ID.
+        "tradeReceived" (int): The amount received from the trade.
+        "attempts" (int): The failed attempts of the current step.
+        "error" (string): The last error.
+        "estimatedFees" (obj): The estimated fees, keyed by fee asset.
+        "estimatedSecs" (int): The estimated duration, in seconds.
+        "created" (int): The creation time, in milliseconds.
+        "updated" (int): The time of the last update, in milliseconds.
+      },...
+    ]`,
+	},
+	cancelBridgeTradeRoute: {
+		paramsType: reflect.TypeFor[BridgeTradeIDParams](),
+		summary: `Stop an active bridge trade. A booked order is not canceled, and an
+    initiated bridge completes.`,
+		fieldDescs: map[string]string{
+			"id": "The bridge trade ID.",
+		},
+		returns: `Returns:
+    string: The message "` + fmt.Sprintf(canceledBridgeStr, "[id]") + `"`,
+	},
+}
+
+// bridgeTradeFieldDescs are the field descriptions of the
+// estimatebridgetrade and startbridgetrade routes.
+var bridgeTradeFieldDescs = map[string]string{
+	"tradeFirst":        "Trade first and bridge the proceeds, instead of trading the bridged funds.",
+	"bridgeFromAssetID": descFromAssetID,
+	"bridgeToAssetID":   descToAssetID,
+	"bridgeName":        descBridgeName,
+	"bridgeAmount":      "The amount to bridge. Ignored if tradeFirst is true.",
+	"host":              "The DEX to trade on.",
+	"isLimit":           "Whether the order is a limit order.",
+	"sell":              "Whether the order is selling.",
+	"base":              descBase,
+	"quote":             descQuote,
+	"qty": `The order quantity. If zero and tradeFirst is false, the quantity
+      is derived from the amount received from the bridge.`,
+	"rate":    "The limit order rate. See trade.",
+	"tifnow":  "Require immediate match. Do not book the order.",
+	"options": "A JSON-encoded string->string mapping of additional trade options.",
 }
 
 // parseJSONTag splits a struct field's json tag into name and options.
@@ -3061,3 +3769,384 @@ func sortRouteInfoKeys() []string {
 	slices.Sort(keys)
 	return keys
 }
+
+func handleAddWebhook(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params AddWebhookParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(addWebhookRoute, err)
+	}
+	form := &core.WebhookForm{
+		URL:       params.URL,
+		Topics:    make([]core.Topic, 0, len(params.Topics)),
+		NoteTypes: params.NoteTypes,
+	}
+	for _, t := range params.Topics {
+		form.Topics = append(form.Topics, core.Topic(t))
+	}
+	if params.Secret != nil {
+		form.Secret = *params.Secret
+	}
+	wh, err := s.core.AddWebhook(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCWebhookError, "error adding webhook: %v", err)
+		return createResponse(addWebhookRoute, nil, resErr)
+	}
+	return createResponse(addWebhookRoute, wh, nil)
+}
+
+func handleRemoveWebhook(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params WebhookIDParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(removeWebhookRoute, err)
+	}
+	if err := s.core.RemoveWebhook(params.ID); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCWebhookError, "error removing webhook: %v", err)
+		return createResponse(removeWebhookRoute, nil, resErr)
+	}
+	return createResponse(removeWebhookRoute, fmt.Sprintf("webhook %s removed", params.ID), nil)
+}
+
+func handleToggleWebhook(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params ToggleWebhookParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(toggleWebhookRoute, err)
+	}
+	if err := s.core.ToggleWebhook(params.ID, params.Disable); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCWebhookError, "error updating webhook: %v", err)
+		return createResponse(toggleWebhookRoute, nil, resErr)
+	}
+	status := "enabled"
+	if params.Disable {
+		status = "disabled"
+	}
+	return createResponse(toggleWebhookRoute, fmt.Sprintf("webhook %s %s", params.ID, status), nil)
+}
+
+// handleWebhooks lists the webhooks. The route only requires ScopeRead, so the
+// secrets are always redacted.
+func handleWebhooks(s *RPCServer, _ *msgjson.Message) *msgjson.ResponsePayload {
+	whs, err := s.core.Webhooks()
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCWebhookError, "error retrieving webhooks: %v", err)
+		return createResponse(webhooksRoute, nil, resErr)
+	}
+	redacted := make([]*db.Webhook, 0, len(whs))
+	for _, wh := range whs {
+		whCopy := *wh
+		whCopy.Secret = ""
+		redacted = append(redacted, &whCopy)
+	}
+	return createResponse(webhooksRoute, redacted, nil)
+}
+
+func handleWebhookDeliveries(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params NotificationsParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(webhookDeliveriesRoute, err)
+	}
+	return createResponse(webhookDeliveriesRoute, s.core.WebhookDeliveries(params.N), nil)
+}
+
+func handleAddPriceAlert(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params AddPriceAlertParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(addPriceAlertRoute, err)
+	}
+	form := &core.PriceAlertForm{
+		Source:    db.PriceAlertSource(params.Source),
+		Condition: db.PriceAlertCondition(params.Condition),
+		Threshold: params.Threshold,
+	}
+	if params.Host != nil {
+		form.Host = *params.Host
+	}
+	if params.BaseID != nil {
+		form.BaseID = *params.BaseID
+	}
+	if params.QuoteID != nil {
+		form.QuoteID = *params.QuoteID
+	}
+	if params.AssetID != nil {
+		form.AssetID = *params.AssetID
+	}
+	if params.WindowSecs != nil {
+		form.WindowSecs = *params.WindowSecs
+	}
+	if params.Rearm != nil {
+		form.Rearm = *params.Rearm
+	}
+	alert, err := s.core.AddPriceAlert(form)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCPriceAlertError, "error adding price alert: %v", err)
+		return createResponse(addPriceAlertRoute, nil, resErr)
+	}
+	return createResponse(addPriceAlertRoute, alert, nil)
+}
+
+func handleRemovePriceAlert(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params PriceAlertIDParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(removePriceAlertRoute, err)
+	}
+	if err := s.core.RemovePriceAlert(params.ID); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCPriceAlertError, "error removing price alert: %v", err)
+		return createResponse(removePriceAlertRoute, nil, resErr)
+	}
+	return createResponse(removePriceAlertRoute, fmt.Sprintf("price alert %s removed", params.ID), nil)
+}
+
+func handlePriceAlerts(s *RPCServer, _ *msgjson.Message) *msgjson.ResponsePayload {
+	return createResponse(priceAlertsRoute, s.core.PriceAlerts(), nil)
+}
+
+func handleSearchTxHistory(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params SearchTxHistoryParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(searchTxHistoryRoute, err)
+	}
+	txs, err := s.core.SearchTxHistory(params.AssetID, params.Query, params.N)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCTxHistoryError, "unable to search tx history: %v", err)
+		return createResponse(searchTxHistoryRoute, nil, resErr)
+	}
+	return createResponse(searchTxHistoryRoute, txs, nil)
+}
+
+func handleSetLabel(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params SetLabelParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(setLabelRoute, err)
+	}
+	if err := s.core.SetLabel(params.AssetID, db.LabelKind(params.Kind), params.ID, params.Label); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLabelError, "error setting label: %v", err)
+		return createResponse(setLabelRoute, nil, resErr)
+	}
+	if params.Label == "" {
+		return createResponse(setLabelRoute, fmt.Sprintf("label for %s removed", params.ID), nil)
+	}
+	return createResponse(setLabelRoute, fmt.Sprintf("label for %s set", params.ID), nil)
+}
+
+func handleLabels(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params LabelsParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(labelsRoute, err)
+	}
+	labels, err := s.core.Labels(params.AssetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLabelError, "error loading labels: %v", err)
+		return createResponse(labelsRoute, nil, resErr)
+	}
+	return createResponse(labelsRoute, labels, nil)
+}
+
+func handleAddContact(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params AddContactParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(addContactRoute, err)
+	}
+	err := s.core.AddContact(&db.Contact{
+		AssetID: params.AssetID,
+		Name:    params.Name,
+		Address: params.Address,
+		Note:    params.Note,
+	})
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLabelError, "error adding contact: %v", err)
+		return createResponse(addContactRoute, nil, resErr)
+	}
+	return createResponse(addContactRoute, fmt.Sprintf("contact %s added", params.Name), nil)
+}
+
+func handleRemoveContact(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params RemoveContactParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(removeContactRoute, err)
+	}
+	if err := s.core.RemoveContact(params.AssetID, params.Name); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLabelError, "error removing contact: %v", err)
+		return createResponse(removeContactRoute, nil, resErr)
+	}
+	return createResponse(removeContactRoute, fmt.Sprintf("contact %s removed", params.Name), nil)
+}
+
+func handleContacts(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params LabelsParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(contactsRoute, err)
+	}
+	contacts, err := s.core.Contacts(params.AssetID)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCLabelError, "error loading contacts: %v", err)
+		return createResponse(contactsRoute, nil, resErr)
+	}
+	return createResponse(contactsRoute, contacts, nil)
+}
+
+func handleAddAPIToken(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params AddAPITokenParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(addAPITokenRoute, err)
+	}
+	scopes, err := parseScopes(params.Scopes)
+	if err != nil {
+		return usage(addAPITokenRoute, err)
+	}
+	var lifetime time.Duration
+	if params.Lifetime != nil {
+		if lifetime, err = time.ParseDuration(*params.Lifetime); err != nil {
+			return usage(addAPITokenRoute, fmt.Errorf("invalid lifetime: %w", err))
+		}
+	}
+	res, err := s.tokens.add(params.Name, scopes, params.AllowedIPs, lifetime)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAPITokenError, "error adding API token: %v", err)
+		return createResponse(addAPITokenRoute, nil, resErr)
+	}
+	s.auditLog.Infof("API token %q (%s) issued with scopes %v", res.Name, res.ID, res.Scopes)
+	return createResponse(addAPITokenRoute, res, nil)
+}
+
+func handleRevokeAPIToken(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params RevokeAPITokenParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(revokeAPITokenRoute, err)
+	}
+	t, err := s.tokens.revoke(params.Token)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCAPITokenError, "error revoking API token: %v", err)
+		return createResponse(revokeAPITokenRoute, nil, resErr)
+	}
+	s.auditLog.Infof("API token %q (%s) revoked", t.Name, t.ID)
+	return createResponse(revokeAPITokenRoute, fmt.Sprintf("API token %s revoked", t.Name), nil)
+}
+
+func handleAPITokens(s *RPCServer, _ *msgjson.Message) *msgjson.ResponsePayload {
+	tokens := s.tokens.list()
+	for _, t := range tokens {
+		t.SecretHash = ""
+	}
+	return createResponse(apiTokensRoute, tokens, nil)
+}
+
+func handleExportTrades(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params ExportTradesParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(exportTradesRoute, err)
+	}
+	format := "json"
+	if params.Format != nil {
+		format = strings.ToLower(*params.Format)
+	}
+	if format != "json" && format != "csv" {
+		return usage(exportTradesRoute, fmt.Errorf("unknown format %q", format))
+	}
+	if (params.BaseID == nil) != (params.QuoteID == nil) {
+		return usage(exportTradesRoute, errors.New("baseID and quoteID must be specified together"))
+	}
+	filter := &core.TradeExportFilter{
+		Hosts:  params.Hosts,
+		Assets: params.Assets,
+	}
+	if params.From != nil {
+		filter.From = *params.From
+	}
+	if params.To != nil {
+		filter.To = *params.To
+	}
+	if params.BaseID != nil {
+		filter.Market = &struct {
+			Base  uint32 `json:"baseID"`
+			Quote uint32 `json:"quoteID"`
+		}{*params.BaseID, *params.QuoteID}
+	}
+	if params.OrderID != nil {
+		oid, err := hex.DecodeString(*params.OrderID)
+		if err != nil {
+			return usage(exportTradesRoute, fmt.Errorf("invalid order ID: %w", err))
+		}
+		filter.OrderID = oid
+	}
+	recs, err := s.core.ExportTrades(filter)
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCExportTradesError, "error exporting trades: %v", err)
+		return createResponse(exportTradesRoute, nil, resErr)
+	}
+	if format == "json" {
+		return createResponse(exportTradesRoute, recs, nil)
+	}
+	var b strings.Builder
+	if err := core.WriteTradesCSV(&b, recs, false); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCExportTradesError, "error writing CSV: %v", err)
+		return createResponse(exportTradesRoute, nil, resErr)
+	}
+	return createResponse(exportTradesRoute, b.String(), nil)
+}
+
+// handleRefundKit handles requests for the refund kit.
+func handleRefundKit(s *RPCServer, _ *msgjson.Message) *msgjson.ResponsePayload {
+	return createResponse(refundKitRoute, s.core.RefundKit(), nil)
+}
+
+// handleExportRefundKit handles requests to export the refund kit.
+func handleExportRefundKit(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params ExportRefundKitParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(exportRefundKitRoute, err)
+	}
+	if params.Path == "" {
+		return usage(exportRefundKitRoute, errors.New("path is required"))
+	}
+	if err := s.core.ExportRefundKit(params.Path); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCRefundKitError, "error exporting refund kit: %v", err)
+		return createResponse(exportRefundKitRoute, nil, resErr)
+	}
+	return createResponse(exportRefundKitRoute, fmt.Sprintf(exportRefundKitStr, params.Path), nil)
+}
+
+// handleEstimateBridgeTrade handles requests to estimate a bridge trade.
+func handleEstimateBridgeTrade(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params EstimateBridgeTradeParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(estimateBridgeTradeRoute, err)
+	}
+	est, err := s.core.EstimateBridgeTrade(params.form())
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBridgeError, "unable to estimate bridge trade: %v", err)
+		return createResponse(estimateBridgeTradeRoute, nil, resErr)
+	}
+	return createResponse(estimateBridgeTradeRoute, est, nil)
+}
+
+// handleStartBridgeTrade handles requests to start a bridge trade.
+func handleStartBridgeTrade(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params StartBridgeTradeParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(startBridgeTradeRoute, err)
+	}
+	defer params.AppPass.Clear()
+	bt, err := s.core.StartBridgeTrade(params.AppPass, params.form())
+	if err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBridgeError, "unable to start bridge trade: %v", err)
+		return createResponse(startBridgeTradeRoute, nil, resErr)
+	}
+	return createResponse(startBridgeTradeRoute, bt, nil)
+}
+
+// handleBridgeTrades handles requests for the bridge trades.
+func handleBridgeTrades(s *RPCServer, _ *msgjson.Message) *msgjson.ResponsePayload {
+	return createResponse(bridgeTradesRoute, s.core.BridgeTrades(), nil)
+}
+
+// handleCancelBridgeTrade handles requests to cancel a bridge trade.
+func handleCancelBridgeTrade(s *RPCServer, msg *msgjson.Message) *msgjson.ResponsePayload {
+	var params BridgeTradeIDParams
+	if err := msg.Unmarshal(&params); err != nil {
+		return usage(cancelBridgeTradeRoute, err)
+	}
+	if err := s.core.CancelBridgeTrade(params.ID); err != nil {
+		resErr := msgjson.NewError(msgjson.RPCBridgeError, "unable to cancel bridge trade: %v", err)
+		return createResponse(cancelBridgeTradeRoute, nil, resErr)
+	}
+	return createResponse(cancelBridgeTradeRoute, fmt.Sprintf(canceledBridgeStr, params.ID), nil)
+}
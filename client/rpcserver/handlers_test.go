@@ -14,6 +14,8 @@ import (
 
 	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
+	"decred.org/dcrdex/client/db"
+	"decred.org/dcrdex/client/refundkit"
 	"decred.org/dcrdex/client/websocket"
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
@@ -896,7 +898,7 @@ func TestHandleTrade(t *testing.T) {
 			msg = makeMsg(t, tradeRoute, test.params)
 		}
 		payload := handleTrade(r, msg)
-		res := new(tradeResponse)
+		res := new(TradeResponse)
 		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
 			t.Fatal(err)
 		}
@@ -1080,7 +1082,7 @@ func TestHandleMyOrders(t *testing.T) {
 			msg = makeMsg(t, myOrdersRoute, test.params)
 		}
 		payload := handleMyOrders(r, msg)
-		res := new(myOrdersResponse)
+		res := new(MyOrdersResponse)
 		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
 			t.Fatal(err)
 		}
@@ -1177,7 +1179,7 @@ func TestParseCoreOrder(t *testing.T) {
 	if err := json.Unmarshal([]byte(co), coreOrder); err != nil {
 		panic(err)
 	}
-	myOrder := new(myOrder)
+	myOrder := new(MyOrder)
 	if err := json.Unmarshal([]byte(mo), myOrder); err != nil {
 		panic(err)
 	}
@@ -2322,3 +2324,607 @@ func TestHandleReconfigureWallet(t *testing.T) {
 		}
 	}
 }
+
+func TestHandleAddWebhook(t *testing.T) {
+	secret := "shh"
+	goodParams := &AddWebhookParams{
+		URL:       "https://example.com/hook",
+		Topics:    []string{"MatchErrorCoin"},
+		NoteTypes: []string{"bot"},
+		Secret:    &secret,
+	}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      goodParams,
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      goodParams,
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCWebhookError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{webhookErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, addWebhookRoute)
+		} else {
+			msg = makeMsg(t, addWebhookRoute, test.params)
+		}
+		payload := handleAddWebhook(r, msg)
+		var res db.Webhook
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 {
+			if res.URL != goodParams.URL || res.Secret != secret || len(res.Topics) != 1 || res.Topics[0] != "MatchErrorCoin" {
+				t.Fatalf("%s: wrong webhook %+v", test.name, res)
+			}
+		}
+	}
+}
+
+func TestHandleRemoveWebhook(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &WebhookIDParams{ID: "abc"},
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &WebhookIDParams{ID: "abc"},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCWebhookError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{webhookErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, removeWebhookRoute)
+		} else {
+			msg = makeMsg(t, removeWebhookRoute, test.params)
+		}
+		payload := handleRemoveWebhook(r, msg)
+		var res string
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestHandleWebhooks(t *testing.T) {
+	wh := &db.Webhook{ID: "abc", URL: "https://example.com/hook", Secret: "shh"}
+	tc := &TCore{webhooks: []*db.Webhook{wh}}
+	r := &RPCServer{core: tc}
+	payload := handleWebhooks(r, makeMsg(t, webhooksRoute, nil))
+	var res []*db.Webhook
+	if err := verifyResponse(payload, &res, -1); err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || res[0].ID != wh.ID || res[0].URL != wh.URL {
+		t.Fatalf("wrong webhooks %+v", res)
+	}
+	if res[0].Secret != "" {
+		t.Fatal("webhook secret not redacted")
+	}
+	if wh.Secret != "shh" {
+		t.Fatal("core webhook modified")
+	}
+
+	tc.webhookErr = errors.New("test error")
+	payload = handleWebhooks(r, makeMsg(t, webhooksRoute, nil))
+	if err := verifyResponse(payload, &res, msgjson.RPCWebhookError); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleAddPriceAlert(t *testing.T) {
+	host := "dex.example.com"
+	baseID, quoteID := uint32(42), uint32(0)
+	goodParams := &AddPriceAlertParams{
+		Source:    "midgap",
+		Condition: "above",
+		Threshold: 0.5,
+		Host:      &host,
+		BaseID:    &baseID,
+		QuoteID:   &quoteID,
+	}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      goodParams,
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      goodParams,
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCPriceAlertError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{priceAlertErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, addPriceAlertRoute)
+		} else {
+			msg = makeMsg(t, addPriceAlertRoute, test.params)
+		}
+		payload := handleAddPriceAlert(r, msg)
+		var res db.PriceAlert
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		form := tc.priceAlertForm
+		if form.Host != host || form.BaseID != baseID || form.QuoteID != quoteID || form.Rearm {
+			t.Fatalf("%s: wrong form %+v", test.name, form)
+		}
+		if res.Source != db.PriceAlertMidGap || res.Condition != db.PriceAlertAbove || res.Threshold != 0.5 {
+			t.Fatalf("%s: wrong price alert %+v", test.name, res)
+		}
+	}
+}
+
+func TestHandleSendBatch(t *testing.T) {
+	csv := "abc,1"
+	recipients := []*asset.BatchRecipient{{Address: "abc", Value: 1e8}, {Address: "def", Value: 2e8}}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantN       int
+		wantErrCode int
+	}{{
+		name:        "ok recipients",
+		params:      &SendBatchParams{AppPass: encode.PassBytes("abc"), AssetID: 42, Recipients: recipients},
+		wantN:       2,
+		wantErrCode: -1,
+	}, {
+		name:        "ok csv",
+		params:      &SendBatchParams{AppPass: encode.PassBytes("abc"), AssetID: 42, CSV: &csv},
+		wantN:       1,
+		wantErrCode: -1,
+	}, {
+		name:        "recipients and csv",
+		params:      &SendBatchParams{AppPass: encode.PassBytes("abc"), AssetID: 42, Recipients: recipients, CSV: &csv},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "empty pass",
+		params:      &SendBatchParams{AssetID: 42, Recipients: recipients},
+		wantErrCode: msgjson.RPCFundTransferError,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &SendBatchParams{AppPass: encode.PassBytes("abc"), AssetID: 42, Recipients: recipients},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCFundTransferError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{batchSendErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, sendBatchRoute)
+		} else {
+			msg = makeMsg(t, sendBatchRoute, test.params)
+		}
+		payload := handleSendBatch(r, msg)
+		var txIDs []string
+		if err := verifyResponse(payload, &txIDs, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (len(txIDs) != 1 || len(tc.batchRecipients) != test.wantN) {
+			t.Fatalf("%s: wrong result", test.name)
+		}
+	}
+}
+
+func TestHandleSendWithCoins(t *testing.T) {
+	coins := []dex.Bytes{{0x01}, {0x02}}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &SendWithCoinsParams{AppPass: encode.PassBytes("abc"), AssetID: 42, Coins: coins, Address: "addr", Value: 1e8},
+		wantErrCode: -1,
+	}, {
+		name:        "empty pass",
+		params:      &SendWithCoinsParams{AssetID: 42, Coins: coins, Address: "addr"},
+		wantErrCode: msgjson.RPCFundTransferError,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &SendWithCoinsParams{AppPass: encode.PassBytes("abc"), AssetID: 42, Coins: coins, Address: "addr"},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCFundTransferError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{coin: tCoin{}, sendErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, sendWithCoinsRoute)
+		} else {
+			msg = makeMsg(t, sendWithCoinsRoute, test.params)
+		}
+		payload := handleSendWithCoins(r, msg)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (len(tc.coinControlSend.Coins) != 2 || tc.coinControlSend.Value != 1e8) {
+			t.Fatalf("%s: send not passed to core", test.name)
+		}
+	}
+}
+
+func TestHandleListCoins(t *testing.T) {
+	tc := &TCore{spendableCoins: []*asset.SpendableCoin{{Value: 1}, {Value: 2, Locked: true}}}
+	r := &RPCServer{core: tc}
+	payload := handleListCoins(r, makeMsg(t, listCoinsRoute, &ListCoinsParams{AssetID: 42}))
+	var coins []*asset.SpendableCoin
+	if err := verifyResponse(payload, &coins, -1); err != nil {
+		t.Fatal(err)
+	}
+	if len(coins) != 2 || !coins[1].Locked {
+		t.Fatalf("wrong coins returned")
+	}
+	tc.sendErr = errors.New("test error")
+	payload = handleListCoins(r, makeMsg(t, listCoinsRoute, &ListCoinsParams{AssetID: 42}))
+	if err := verifyResponse(payload, &coins, msgjson.RPCListCoinsError); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleSendToContact(t *testing.T) {
+	pw := encode.PassBytes("abc")
+	tests := []struct {
+		name        string
+		params      *SendParams
+		wantAddr    string
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &SendParams{AppPass: pw, AssetID: 42, Value: 1e8, Contact: "alice"},
+		wantAddr:    "aliceaddr",
+		wantErrCode: -1,
+	}, {
+		name:        "address and contact",
+		params:      &SendParams{AppPass: pw, AssetID: 42, Value: 1e8, Address: "addr", Contact: "alice"},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "unknown contact",
+		params:      &SendParams{AppPass: pw, AssetID: 42, Value: 1e8, Contact: "bob"},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{
+			coin:     tCoin{},
+			contacts: []*db.Contact{{AssetID: 42, Name: "alice", Address: "aliceaddr"}},
+		}
+		r := &RPCServer{core: tc}
+		msg := makeMsg(t, sendRoute, test.params)
+		payload := handleSend(r, msg)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if tc.sendAddr != test.wantAddr {
+			t.Fatalf("%s: wanted send to %q, got %q", test.name, test.wantAddr, tc.sendAddr)
+		}
+	}
+}
+
+func TestHandleSetLabel(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &SetLabelParams{AssetID: 42, Kind: "tx", ID: "abcd", Label: "rent"},
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &SetLabelParams{AssetID: 42, Kind: "tx", ID: "abcd", Label: "rent"},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCLabelError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{labelErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, setLabelRoute)
+		} else {
+			msg = makeMsg(t, setLabelRoute, test.params)
+		}
+		payload := handleSetLabel(r, msg)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && (tc.label == nil || tc.label.Kind != db.LabelTx || tc.label.Label != "rent") {
+			t.Fatalf("%s: label not passed to core", test.name)
+		}
+	}
+}
+
+func TestHandleAddContact(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &AddContactParams{AssetID: 42, Name: "alice", Address: "addr"},
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &AddContactParams{AssetID: 42, Name: "alice", Address: "addr"},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCLabelError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{labelErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, addContactRoute)
+		} else {
+			msg = makeMsg(t, addContactRoute, test.params)
+		}
+		payload := handleAddContact(r, msg)
+		res := ""
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestHandleExportTrades(t *testing.T) {
+	from, baseID, quoteID := uint64(1000), uint32(42), uint32(0)
+	csvFormat, badFormat := "csv", "xml"
+	badOrderID := "zz"
+	recs := []*core.TradeExportRecord{{Host: "dex.example.com", Market: "dcr_btc", MatchID: dex.Bytes{0x01}, Qty: 1}}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantCSV     bool
+		wantErrCode int
+	}{{
+		name:        "ok json",
+		params:      &ExportTradesParams{From: &from, BaseID: &baseID, QuoteID: &quoteID},
+		wantErrCode: -1,
+	}, {
+		name:        "ok csv",
+		params:      &ExportTradesParams{Format: &csvFormat},
+		wantCSV:     true,
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "unknown format",
+		params:      &ExportTradesParams{Format: &badFormat},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "base without quote",
+		params:      &ExportTradesParams{BaseID: &baseID},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "bad order ID",
+		params:      &ExportTradesParams{OrderID: &badOrderID},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &ExportTradesParams{},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCExportTradesError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{tradeExportRecords: recs, tradeExportErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, exportTradesRoute)
+		} else {
+			msg = makeMsg(t, exportTradesRoute, test.params)
+		}
+		payload := handleExportTrades(r, msg)
+		if test.wantErrCode != -1 || test.wantCSV {
+			var res string
+			if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+				t.Fatalf("%s: %v", test.name, err)
+			}
+			if test.wantCSV && !strings.Contains(res, "dcr_btc") {
+				t.Fatalf("%s: record not in CSV: %s", test.name, res)
+			}
+			continue
+		}
+		var res []*core.TradeExportRecord
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if len(res) != 1 || res[0].Market != "dcr_btc" {
+			t.Fatalf("%s: wrong records %+v", test.name, res)
+		}
+		f := tc.tradeExportFilter
+		if f.From != from || f.Market == nil || f.Market.Base != baseID || f.Market.Quote != quoteID {
+			t.Fatalf("%s: wrong filter %+v", test.name, f)
+		}
+	}
+}
+
+func TestHandleRefundKit(t *testing.T) {
+	kit := &core.RefundKit{
+		Path:    "refundkit.bwrk",
+		Entries: []*refundkit.Entry{{Symbol: "btc", Value: 1e8, SignedRefund: dex.Bytes{0x01}}},
+	}
+	tc := &TCore{refundKit: kit}
+	r := &RPCServer{core: tc}
+	payload := handleRefundKit(r, nil)
+	res := new(core.RefundKit)
+	if err := verifyResponse(payload, res, -1); err != nil {
+		t.Fatal(err)
+	}
+	if res.Path != kit.Path || len(res.Entries) != 1 || res.Entries[0].Value != 1e8 {
+		t.Fatalf("wrong refund kit %+v", res)
+	}
+}
+
+func TestHandleExportRefundKit(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      &ExportRefundKitParams{Path: "/media/usb/refundkit.bwrk"},
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "no path",
+		params:      &ExportRefundKitParams{},
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      &ExportRefundKitParams{Path: "refundkit.bwrk"},
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCRefundKitError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{exportRefundKitErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, exportRefundKitRoute)
+		} else {
+			msg = makeMsg(t, exportRefundKitRoute, test.params)
+		}
+		payload := handleExportRefundKit(r, msg)
+		var res string
+		if err := verifyResponse(payload, &res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode == -1 && tc.refundKitExportPath != test.params.(*ExportRefundKitParams).Path {
+			t.Fatalf("%s: wrong export path %q", test.name, tc.refundKitExportPath)
+		}
+	}
+}
+
+func TestHandleStartBridgeTrade(t *testing.T) {
+	params := &StartBridgeTradeParams{
+		AppPass: encode.PassBytes("abc"),
+		EstimateBridgeTradeParams: EstimateBridgeTradeParams{
+			BridgeFromAssetID: 60,
+			BridgeToAssetID:   966,
+			BridgeName:        "across",
+			BridgeAmount:      1e9,
+			TradeForm: core.TradeForm{
+				Host:  "dex.example.com",
+				Base:  966,
+				Quote: 0,
+				Sell:  true,
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		params      any
+		coreErr     error
+		wantErrCode int
+	}{{
+		name:        "ok",
+		params:      params,
+		wantErrCode: -1,
+	}, {
+		name:        "bad params",
+		params:      nil,
+		wantErrCode: msgjson.RPCArgumentsError,
+	}, {
+		name:        "core error",
+		params:      params,
+		coreErr:     errors.New("test error"),
+		wantErrCode: msgjson.RPCBridgeError,
+	}}
+	for _, test := range tests {
+		tc := &TCore{bridgeTradeErr: test.coreErr}
+		r := &RPCServer{core: tc}
+		var msg *msgjson.Message
+		if test.params == nil {
+			msg = makeBadMsg(t, startBridgeTradeRoute)
+		} else {
+			msg = makeMsg(t, startBridgeTradeRoute, test.params)
+		}
+		payload := handleStartBridgeTrade(r, msg)
+		res := new(db.BridgeTrade)
+		if err := verifyResponse(payload, res, test.wantErrCode); err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if test.wantErrCode != -1 {
+			continue
+		}
+		form := tc.bridgeTradeForm
+		if form.BridgeName != "across" || form.BridgeAmount != 1e9 || form.Trade.Host != "dex.example.com" || !form.Trade.Sell {
+			t.Fatalf("%s: wrong form %+v", test.name, form)
+		}
+		if res.ID != "abc" {
+			t.Fatalf("%s: wrong bridge trade %+v", test.name, res)
+		}
+	}
+}
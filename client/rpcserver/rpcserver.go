@@ -17,6 +17,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -51,6 +52,10 @@ const (
 	// deployment and gas testing (which may test multiple assets
 	// sequentially).
 	rpcDevWriteTimeoutSeconds = 1800
+
+	// NotifyRoute is the websocket route used to relay core notifications to
+	// websocket clients.
+	NotifyRoute = "notify"
 )
 
 var (
@@ -84,6 +89,7 @@ type clientCore interface {
 	Wallets() (walletsStates []*core.WalletState)
 	WalletState(assetID uint32) *core.WalletState
 	RescanWallet(assetID uint32, force bool) error
+	AddCustomToken(appPW []byte, parentID uint32, contractAddr string) (uint32, error)
 	AbandonTransaction(assetID uint32, txID string) error
 	Send(appPass []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error)
 	ExportSeed(pw []byte) (string, error)
@@ -92,6 +98,7 @@ type clientCore interface {
 	AddWalletPeer(assetID uint32, host string) error
 	RemoveWalletPeer(assetID uint32, host string) error
 	Notifications(int) (notes, pokes []*db.Notification, _ error)
+	NotificationFeed() *core.NoteFeed
 	MultiTrade(pw []byte, form *core.MultiTradeForm) []*core.MultiTradeResult
 	TxHistory(assetID uint32, req *asset.TxHistoryRequest) (*asset.TxHistoryResponse, error)
 	WalletTransaction(assetID uint32, txID string) (*asset.WalletTransaction, error)
@@ -123,6 +130,33 @@ type clientCore interface {
 	GaslessRedeemCalldata(appPass []byte, matchIDs []order.MatchID, relayerAddress string) (*core.GaslessRedeemCalldataResult, error)
 	ValidateGaslessRedeem(assetID uint32, contractAddress, calldata string) (*asset.GaslessRedeemValidation, error)
 	SubmitGaslessRedeem(appPass []byte, assetID uint32, contractAddress, calldata string) (string, error)
+	AddWebhook(form *core.WebhookForm) (*db.Webhook, error)
+	RemoveWebhook(id string) error
+	ToggleWebhook(id string, disable bool) error
+	Webhooks() ([]*db.Webhook, error)
+	WebhookDeliveries(n int) []*core.WebhookDelivery
+	AddPriceAlert(form *core.PriceAlertForm) (*db.PriceAlert, error)
+	RemovePriceAlert(id string) error
+	PriceAlerts() []*db.PriceAlert
+	EstimateBridgeTrade(form *core.BridgeTradeForm) (*core.BridgeTradeEstimate, error)
+	StartBridgeTrade(pw []byte, form *core.BridgeTradeForm) (*db.BridgeTrade, error)
+	BridgeTrades() []*db.BridgeTrade
+	CancelBridgeTrade(id string) error
+	SendBatch(appPass []byte, assetID uint32, recipients []*asset.BatchRecipient) ([]string, error)
+	EstimateBatchSendTxFee(assetID uint32, recipients []*asset.BatchRecipient) (uint64, error)
+	ParseBatchSendCSV(assetID uint32, csvData string) ([]*asset.BatchRecipient, error)
+	SpendableCoins(assetID uint32) ([]*asset.SpendableCoin, error)
+	SendWithCoins(appPass []byte, assetID uint32, send *asset.CoinControlSend) (asset.Coin, error)
+	SearchTxHistory(assetID uint32, query string, n int) ([]*asset.WalletTransaction, error)
+	SetLabel(assetID uint32, kind db.LabelKind, id, label string) error
+	Labels(assetID uint32) ([]*db.Label, error)
+	AddContact(contact *db.Contact) error
+	RemoveContact(assetID uint32, name string) error
+	Contacts(assetID uint32) ([]*db.Contact, error)
+	ContactAddress(assetID uint32, name string) (string, error)
+	ExportTrades(filter *core.TradeExportFilter) ([]*core.TradeExportRecord, error)
+	RefundKit() *core.RefundKit
+	ExportRefundKit(path string) error
 }
 
 // RPCServer is a single-client http and websocket server enabling a JSON
@@ -136,6 +170,8 @@ type RPCServer struct {
 	tlsConfig *tls.Config
 	srv       *http.Server
 	authSHA   [32]byte
+	tokens    *tokenStore
+	auditLog  dex.Logger
 	wg        sync.WaitGroup
 	bwVersion *SemVersion
 	ctx       context.Context
@@ -215,7 +251,7 @@ func (s *RPCServer) handleJSON(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Responses not accepted", http.StatusMethodNotAllowed)
 		return
 	}
-	s.parseHTTPRequest(w, req)
+	s.parseHTTPRequest(w, requestAuth(r.Context()), req)
 }
 
 // Config holds variables needed to create a new RPC Server.
@@ -226,6 +262,9 @@ type Config struct {
 	BWVersion                   *SemVersion
 	CertHosts                   []string
 	Dev                         bool
+	// TokensPath is the file in which API tokens are stored. If empty, API
+	// tokens are not persisted across restarts.
+	TokensPath string
 }
 
 // SetLogger sets the logger for the RPCServer package.
@@ -252,6 +291,11 @@ func New(cfg *Config) (*RPCServer, error) {
 			return nil, err
 		}
 	}
+	tokens, err := newTokenStore(cfg.TokensPath)
+	if err != nil {
+		return nil, err
+	}
+
 	keypair, err := tls.LoadX509KeyPair(cfg.Cert, cfg.Key)
 	if err != nil {
 		return nil, err
@@ -283,6 +327,8 @@ func New(cfg *Config) (*RPCServer, error) {
 		srv:       httpServer,
 		addr:      cfg.Addr,
 		tlsConfig: tlsConfig,
+		tokens:    tokens,
+		auditLog:  log.SubLogger("AUDIT"),
 		bwVersion: cfg.BWVersion,
 		wsServer:  websocket.New(cfg.Core, log.SubLogger("WS")),
 		dev:       cfg.Dev,
@@ -339,6 +385,12 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 		s.wsServer.HandleConnect(ctx, w, r)
 	})
 
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.readNotifications(ctx)
+	}()
+
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
@@ -354,9 +406,32 @@ func (s *RPCServer) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	return &s.wg, nil
 }
 
-// handleRequest sends the request to the correct handler function if able.
-func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload {
+// readNotifications reads from the Core notification channel and relays to
+// websocket clients.
+func (s *RPCServer) readNotifications(ctx context.Context) {
+	ch := s.core.NotificationFeed()
+	defer ch.ReturnFeed()
+
+	for {
+		select {
+		case n := <-ch.C:
+			s.wsServer.Notify(NotifyRoute, n)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handleRequest sends the request to the correct handler function if able. The
+// credentials must grant the scope required by the route. Every request is
+// recorded in the audit log.
+func (s *RPCServer) handleRequest(auth *authInfo, req *msgjson.Message) *msgjson.ResponsePayload {
 	payload := new(msgjson.ResponsePayload)
+	if auth == nil {
+		s.auditLog.Warnf("DENIED %q: no credentials", req.Route)
+		payload.Error = msgjson.NewError(msgjson.RPCPermissionDenied, "not authenticated")
+		return payload
+	}
 	if req.Route == "" {
 		log.Debugf("route not specified")
 		payload.Error = msgjson.NewError(msgjson.RPCUnknownRoute, "no route was supplied")
@@ -377,13 +452,25 @@ func (s *RPCServer) handleRequest(req *msgjson.Message) *msgjson.ResponsePayload
 		return payload
 	}
 
-	return h(s, req)
+	if scope := routeScope(req.Route); !auth.hasScope(scope) {
+		s.auditLog.Warnf("DENIED %q for %s: requires the %q scope", req.Route, auth, scope)
+		payload.Error = msgjson.NewError(msgjson.RPCPermissionDenied, "the %q scope is required for %s", scope, req.Route)
+		return payload
+	}
+
+	payload = h(s, req)
+	if payload.Error != nil {
+		s.auditLog.Infof("%q by %s failed with code %d", req.Route, auth, payload.Error.Code)
+	} else {
+		s.auditLog.Infof("%q by %s", req.Route, auth)
+	}
+	return payload
 }
 
 // parseHTTPRequest parses the msgjson message in the request body, creates a
 // response message, and writes it to the http.ResponseWriter.
-func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, req *msgjson.Message) {
-	payload := s.handleRequest(req)
+func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, auth *authInfo, req *msgjson.Message) {
+	payload := s.handleRequest(auth, req)
 	resp, err := msgjson.NewResponse(req.ID, payload.Result, payload.Error)
 	if err != nil {
 		msg := fmt.Sprintf("error encoding response: %v", err)
@@ -394,7 +481,10 @@ func (s *RPCServer) parseHTTPRequest(w http.ResponseWriter, req *msgjson.Message
 	writeJSON(w, resp)
 }
 
-// authMiddleware checks incoming requests for authentication.
+// authMiddleware checks incoming requests for authentication. Requests are
+// authenticated with either the RPC username and password using HTTP Basic
+// authentication, or with an API token as a Bearer token. The credentials are
+// added to the request context.
 func (s *RPCServer) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		fail := func() {
@@ -407,12 +497,23 @@ func (s *RPCServer) authMiddleware(next http.Handler) http.Handler {
 			fail()
 			return
 		}
-		authSHA := sha256.Sum256([]byte(auth[0]))
-		if subtle.ConstantTimeCompare(s.authSHA[:], authSHA[:]) != 1 {
-			fail()
-			return
+		a := &authInfo{ip: r.RemoteAddr}
+		if bearer, isBearer := strings.CutPrefix(auth[0], "Bearer "); isBearer {
+			token, err := s.tokens.authenticate(bearer, remoteIP(r))
+			if err != nil {
+				s.auditLog.Warnf("API token authentication failure from ip %s: %v", r.RemoteAddr, err)
+				fail()
+				return
+			}
+			a.token = token
+		} else {
+			authSHA := sha256.Sum256([]byte(auth[0]))
+			if subtle.ConstantTimeCompare(s.authSHA[:], authSHA[:]) != 1 {
+				fail()
+				return
+			}
 		}
-		log.Debugf("authenticated user with ip: %s", r.RemoteAddr)
-		next.ServeHTTP(w, r)
+		log.Debugf("authenticated %s", a)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), authCtxKey{}, a)))
 	})
 }
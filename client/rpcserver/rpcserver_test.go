@@ -11,7 +11,9 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"testing"
@@ -89,6 +91,30 @@ type TCore struct {
 	gaslessRedeemValidErr    error
 	submitGaslessRedeemTx    string
 	submitGaslessRedeemErr   error
+	webhooks                 []*db.Webhook
+	webhookErr               error
+	webhookDeliveries        []*core.WebhookDelivery
+	priceAlertForm           *core.PriceAlertForm
+	priceAlerts              []*db.PriceAlert
+	priceAlertErr            error
+	batchRecipients          []*asset.BatchRecipient
+	batchSendErr             error
+	spendableCoins           []*asset.SpendableCoin
+	coinControlSend          *asset.CoinControlSend
+	sendAddr                 string
+	label                    *db.Label
+	labelErr                 error
+	contacts                 []*db.Contact
+	tradeExportFilter        *core.TradeExportFilter
+	tradeExportRecords       []*core.TradeExportRecord
+	tradeExportErr           error
+	refundKit                *core.RefundKit
+	refundKitExportPath      string
+	exportRefundKitErr       error
+	bridgeTradeForm          *core.BridgeTradeForm
+	bridgeTradeEstimate      *core.BridgeTradeEstimate
+	bridgeTrades             []*db.BridgeTrade
+	bridgeTradeErr           error
 }
 
 func (c *TCore) Balance(uint32) (uint64, error) {
@@ -150,6 +176,9 @@ func (c *TCore) ToggleWalletStatus(assetID uint32, disable bool) error {
 func (c *TCore) RescanWallet(assetID uint32, force bool) error {
 	return c.rescanWalletErr
 }
+func (c *TCore) AddCustomToken(appPW []byte, parentID uint32, contractAddr string) (uint32, error) {
+	return 1_234_567_890, nil
+}
 func (c *TCore) GetDEXConfig(dexAddr string, certI any) (*core.Exchange, error) {
 	return c.dexExchange, c.getDEXConfigErr
 }
@@ -172,6 +201,7 @@ func (c *TCore) WalletState(assetID uint32) *core.WalletState {
 	return c.walletState
 }
 func (c *TCore) Send(pw []byte, assetID uint32, value uint64, addr string, subtract bool) (asset.Coin, error) {
+	c.sendAddr = addr
 	return c.coin, c.sendErr
 }
 func (c *TCore) ExportSeed(pw []byte) (string, error) {
@@ -198,6 +228,9 @@ func (c *TCore) RemoveWalletPeer(assetID uint32, address string) error {
 func (c *TCore) Notifications(n int) (notes, pokes []*db.Notification, _ error) {
 	return nil, nil, nil
 }
+func (c *TCore) NotificationFeed() *core.NoteFeed {
+	return &core.NoteFeed{C: make(chan core.Notification)}
+}
 func (c *TCore) MultiTrade(appPass []byte, form *core.MultiTradeForm) []*core.MultiTradeResult {
 	return nil
 }
@@ -270,6 +303,117 @@ func (c *TCore) ExportMMSnapshots(host string, base, quote uint32, startEpoch, e
 func (c *TCore) PruneMMSnapshots(host string, base, quote uint32, minEpochIdx uint64) (int, error) {
 	return c.pruneMMSnapshotsResult, c.pruneMMSnapshotsErr
 }
+func (c *TCore) AddWebhook(form *core.WebhookForm) (*db.Webhook, error) {
+	if c.webhookErr != nil {
+		return nil, c.webhookErr
+	}
+	return &db.Webhook{ID: "abc", URL: form.URL, Secret: form.Secret, Topics: form.Topics, NoteTypes: form.NoteTypes}, nil
+}
+func (c *TCore) RemoveWebhook(id string) error {
+	return c.webhookErr
+}
+func (c *TCore) ToggleWebhook(id string, disable bool) error {
+	return c.webhookErr
+}
+func (c *TCore) Webhooks() ([]*db.Webhook, error) {
+	return c.webhooks, c.webhookErr
+}
+func (c *TCore) WebhookDeliveries(n int) []*core.WebhookDelivery {
+	return c.webhookDeliveries
+}
+func (c *TCore) AddPriceAlert(form *core.PriceAlertForm) (*db.PriceAlert, error) {
+	c.priceAlertForm = form
+	if c.priceAlertErr != nil {
+		return nil, c.priceAlertErr
+	}
+	return &db.PriceAlert{ID: "abc", Source: form.Source, Condition: form.Condition, Threshold: form.Threshold}, nil
+}
+func (c *TCore) RemovePriceAlert(id string) error {
+	return c.priceAlertErr
+}
+func (c *TCore) PriceAlerts() []*db.PriceAlert {
+	return c.priceAlerts
+}
+func (c *TCore) SendBatch(appPass []byte, assetID uint32, recipients []*asset.BatchRecipient) ([]string, error) {
+	c.batchRecipients = recipients
+	if c.batchSendErr != nil {
+		return nil, c.batchSendErr
+	}
+	return []string{"abc"}, nil
+}
+func (c *TCore) EstimateBatchSendTxFee(assetID uint32, recipients []*asset.BatchRecipient) (uint64, error) {
+	c.batchRecipients = recipients
+	return 1000, c.batchSendErr
+}
+func (c *TCore) ParseBatchSendCSV(assetID uint32, csvData string) ([]*asset.BatchRecipient, error) {
+	if csvData == "" {
+		return nil, errors.New("no recipients")
+	}
+	return []*asset.BatchRecipient{{Address: "abc", Value: 1}}, nil
+}
+func (c *TCore) SpendableCoins(assetID uint32) ([]*asset.SpendableCoin, error) {
+	return c.spendableCoins, c.sendErr
+}
+func (c *TCore) SendWithCoins(appPass []byte, assetID uint32, send *asset.CoinControlSend) (asset.Coin, error) {
+	c.coinControlSend = send
+	return c.coin, c.sendErr
+}
+func (c *TCore) SearchTxHistory(assetID uint32, query string, n int) ([]*asset.WalletTransaction, error) {
+	return nil, nil
+}
+func (c *TCore) SetLabel(assetID uint32, kind db.LabelKind, id, label string) error {
+	c.label = &db.Label{AssetID: assetID, Kind: kind, ID: id, Label: label}
+	return c.labelErr
+}
+func (c *TCore) Labels(assetID uint32) ([]*db.Label, error) {
+	return nil, c.labelErr
+}
+func (c *TCore) AddContact(contact *db.Contact) error {
+	c.contacts = append(c.contacts, contact)
+	return c.labelErr
+}
+func (c *TCore) RemoveContact(assetID uint32, name string) error {
+	return c.labelErr
+}
+func (c *TCore) Contacts(assetID uint32) ([]*db.Contact, error) {
+	return c.contacts, c.labelErr
+}
+func (c *TCore) ContactAddress(assetID uint32, name string) (string, error) {
+	for _, contact := range c.contacts {
+		if contact.Name == name {
+			return contact.Address, nil
+		}
+	}
+	return "", errors.New("unknown contact")
+}
+func (c *TCore) ExportTrades(filter *core.TradeExportFilter) ([]*core.TradeExportRecord, error) {
+	c.tradeExportFilter = filter
+	return c.tradeExportRecords, c.tradeExportErr
+}
+func (c *TCore) RefundKit() *core.RefundKit {
+	return c.refundKit
+}
+func (c *TCore) ExportRefundKit(path string) error {
+	c.refundKitExportPath = path
+	return c.exportRefundKitErr
+}
+func (c *TCore) EstimateBridgeTrade(form *core.BridgeTradeForm) (*core.BridgeTradeEstimate, error) {
+	c.bridgeTradeForm = form
+	return c.bridgeTradeEstimate, c.bridgeTradeErr
+}
+func (c *TCore) StartBridgeTrade(pw []byte, form *core.BridgeTradeForm) (*db.BridgeTrade, error) {
+	c.bridgeTradeForm = form
+	if c.bridgeTradeErr != nil {
+		return nil, c.bridgeTradeErr
+	}
+	return &db.BridgeTrade{ID: "abc", Status: db.BridgeTradeActive}, nil
+}
+func (c *TCore) BridgeTrades() []*db.BridgeTrade {
+	return c.bridgeTrades
+}
+func (c *TCore) CancelBridgeTrade(id string) error {
+	return c.bridgeTradeErr
+}
 func (c *TCore) AbandonTransaction(assetID uint32, txID string) error {
 	return c.abandonTransactionErr
 }
@@ -467,22 +611,46 @@ func TestParseHTTPRequest(t *testing.T) {
 	msg, _ = msgjson.NewRequest(1, "123", nil)
 	b, _ = json.Marshal(msg)
 	bbuff = bytes.NewBuffer(b)
-	r, _ = http.NewRequest("GET", "", bbuff)
+	r = tAuthRequest(bbuff, tAdminAuth)
 	ensureMsgErr("bad route", msgjson.RPCUnknownRoute)
 
 	// Use real route.
 	msg, _ = msgjson.NewRequest(1, "version", nil)
 	b, _ = json.Marshal(msg)
 	bbuff = bytes.NewBuffer(b)
-	r, _ = http.NewRequest("GET", "", bbuff)
+	r = tAuthRequest(bbuff, tAdminAuth)
 	ensureNoErr("good request")
 
 	// Use real route with args. Since version ignores params, this should succeed.
 	msg, _ = msgjson.NewRequest(1, "version", "something")
 	b, _ = json.Marshal(msg)
 	bbuff = bytes.NewBuffer(b)
-	r, _ = http.NewRequest("GET", "", bbuff)
+	r = tAuthRequest(bbuff, tAdminAuth)
 	ensureNoErr("version ignores params")
+
+	// No credentials.
+	msg, _ = msgjson.NewRequest(1, "version", nil)
+	b, _ = json.Marshal(msg)
+	r, _ = http.NewRequest("GET", "", bytes.NewBuffer(b))
+	ensureMsgErr("no credentials", msgjson.RPCPermissionDenied)
+
+	// A read-only token can use read-only routes, but not others.
+	readOnly := &authInfo{token: &APIToken{Name: "monitor", Scopes: []Scope{ScopeRead}}}
+	r = tAuthRequest(bytes.NewBuffer(b), readOnly)
+	ensureNoErr("read-only token")
+	msg, _ = msgjson.NewRequest(1, "send", nil)
+	b, _ = json.Marshal(msg)
+	r = tAuthRequest(bytes.NewBuffer(b), readOnly)
+	ensureMsgErr("read-only token send", msgjson.RPCPermissionDenied)
+}
+
+var tAdminAuth = &authInfo{ip: "127.0.0.1:1234"}
+
+// tAuthRequest creates a request with the credentials in the context, as the
+// auth middleware would.
+func tAuthRequest(body io.Reader, auth *authInfo) *http.Request {
+	r, _ := http.NewRequest("GET", "", body)
+	return r.WithContext(context.WithValue(r.Context(), authCtxKey{}, auth))
 }
 
 func TestNew(t *testing.T) {
@@ -6,6 +6,7 @@ package rpcserver
 import (
 	"time"
 
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/mm"
 	"decred.org/dcrdex/dex"
@@ -108,23 +109,31 @@ type RescanWalletParams struct {
 	Force   bool   `json:"force,omitempty"`
 }
 
+// AddCustomTokenParams is the parameter type for the addcustomtoken route.
+type AddCustomTokenParams struct {
+	AppPass encode.PassBytes `json:"appPass"`
+	AssetID uint32           `json:"assetID"`
+	Address string           `json:"address"`
+}
+
 //
 // Trading param types
 //
 
-// tradeResponse is used when responding to the trade route.
-type tradeResponse struct {
+// TradeResponse is used when responding to the trade and multitrade routes.
+// Error is only set for a multitrade order that could not be placed.
+type TradeResponse struct {
 	OrderID string `json:"orderID"`
 	Sig     string `json:"sig"`
 	Stamp   uint64 `json:"stamp"`
-	Error   error  `json:"error,omitempty"`
+	Error   string `json:"error,omitempty"`
 }
 
-// myOrdersResponse is used when responding to the myorders route.
-type myOrdersResponse []*myOrder
+// MyOrdersResponse is used when responding to the myorders route.
+type MyOrdersResponse []*MyOrder
 
-// myOrder represents an order when responding to the myorders route.
-type myOrder struct {
+// MyOrder represents an order when responding to the myorders route.
+type MyOrder struct {
 	Host        string   `json:"host"`
 	MarketName  string   `json:"marketName"`
 	BaseID      uint32   `json:"baseID"`
@@ -143,11 +152,11 @@ type myOrder struct {
 	Cancelling  bool     `json:"cancelling,omitempty"`
 	Canceled    bool     `json:"canceled,omitempty"`
 	TimeInForce string   `json:"tif,omitempty"`
-	Matches     []*match `json:"matches,omitempty"`
+	Matches     []*Match `json:"matches,omitempty"`
 }
 
-// match represents a match on an order. An order may have many matches.
-type match struct {
+// Match represents a match on an order. An order may have many matches.
+type Match struct {
 	MatchID       string `json:"matchID"`
 	Status        string `json:"status"`
 	Revoked       bool   `json:"revoked"`
@@ -207,6 +216,37 @@ type SendParams struct {
 	Value    uint64           `json:"value"`
 	Address  string           `json:"address"`
 	Subtract bool             `json:"subtract,omitempty"`
+	Contact  string           `json:"contact,omitempty"`
+}
+
+// SendBatchParams is the parameter type for the sendbatch route.
+type SendBatchParams struct {
+	AppPass    encode.PassBytes        `json:"appPass"`
+	AssetID    uint32                  `json:"assetID"`
+	Recipients []*asset.BatchRecipient `json:"recipients,omitempty"`
+	CSV        *string                 `json:"csv,omitempty"`
+}
+
+// BatchTxFeeParams is the parameter type for the batchtxfee route.
+type BatchTxFeeParams struct {
+	AssetID    uint32                  `json:"assetID"`
+	Recipients []*asset.BatchRecipient `json:"recipients,omitempty"`
+	CSV        *string                 `json:"csv,omitempty"`
+}
+
+// ListCoinsParams is the parameter type for the listcoins route.
+type ListCoinsParams struct {
+	AssetID uint32 `json:"assetID"`
+}
+
+// SendWithCoinsParams is the parameter type for the sendwithcoins route.
+type SendWithCoinsParams struct {
+	AppPass       encode.PassBytes `json:"appPass"`
+	AssetID       uint32           `json:"assetID"`
+	Coins         []dex.Bytes      `json:"coins"`
+	Address       string           `json:"address"`
+	Value         uint64           `json:"value,omitempty"`
+	ChangeAddress string           `json:"changeAddress,omitempty"`
 }
 
 // BchWithdrawParams is the parameter type for the withdrawbchspv route.
@@ -252,6 +292,40 @@ type TxHistoryParams struct {
 	Past    bool    `json:"past,omitempty"`
 }
 
+// SearchTxHistoryParams is the parameter type for the searchtxhistory route.
+type SearchTxHistoryParams struct {
+	AssetID uint32 `json:"assetID"`
+	Query   string `json:"query"`
+	N       int    `json:"n,omitempty"`
+}
+
+// SetLabelParams is the parameter type for the setlabel route.
+type SetLabelParams struct {
+	AssetID uint32 `json:"assetID"`
+	Kind    string `json:"kind"`
+	ID      string `json:"id"`
+	Label   string `json:"label,omitempty"`
+}
+
+// LabelsParams is the parameter type for the labels and contacts routes.
+type LabelsParams struct {
+	AssetID uint32 `json:"assetID"`
+}
+
+// AddContactParams is the parameter type for the addcontact route.
+type AddContactParams struct {
+	AssetID uint32 `json:"assetID"`
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Note    string `json:"note,omitempty"`
+}
+
+// RemoveContactParams is the parameter type for the removecontact route.
+type RemoveContactParams struct {
+	AssetID uint32 `json:"assetID"`
+	Name    string `json:"name"`
+}
+
 // WalletTxParams is the parameter type for the wallettx route.
 type WalletTxParams struct {
 	AssetID uint32 `json:"assetID"`
@@ -313,8 +387,8 @@ type SubmitGaslessRedeemResponse struct {
 // DEX param types
 //
 
-// getBondAssetsResponse is the getbondassets response payload.
-type getBondAssetsResponse struct {
+// BondAssetsResponse is the bondassets response payload.
+type BondAssetsResponse struct {
 	Expiry uint64                     `json:"expiry"`
 	Assets map[string]*core.BondAsset `json:"assets"`
 }
@@ -353,6 +427,12 @@ type MMAvailableBalancesParams struct {
 	CexName    *string `json:"cexName,omitempty"`
 }
 
+// MMAvailableBalancesResponse is the mmavailablebalances response payload.
+type MMAvailableBalancesResponse struct {
+	DEXBalances map[uint32]uint64 `json:"dexBalances"`
+	CEXBalances map[uint32]uint64 `json:"cexBalances"`
+}
+
 // StartBotParams is the parameter type for the startmmbot route.
 type StartBotParams struct {
 	AppPass     encode.PassBytes   `json:"appPass"`
@@ -505,6 +585,106 @@ type PruneMMSnapshotsParams struct {
 	MinEpochIdx uint64 `json:"minEpochIdx"`
 }
 
+// AddWebhookParams is the parameter type for the addwebhook route.
+type AddWebhookParams struct {
+	URL       string   `json:"url"`
+	Topics    []string `json:"topics"`
+	NoteTypes []string `json:"noteTypes"`
+	Secret    *string  `json:"secret,omitempty"`
+}
+
+// WebhookIDParams is the parameter type for the removewebhook route.
+type WebhookIDParams struct {
+	ID string `json:"id"`
+}
+
+// ToggleWebhookParams is the parameter type for the togglewebhook route.
+type ToggleWebhookParams struct {
+	ID      string `json:"id"`
+	Disable bool   `json:"disable"`
+}
+
+// AddPriceAlertParams is the parameter type for the addpricealert route.
+type AddPriceAlertParams struct {
+	Source     string  `json:"source"`
+	Condition  string  `json:"condition"`
+	Threshold  float64 `json:"threshold"`
+	Host       *string `json:"host,omitempty"`
+	BaseID     *uint32 `json:"baseID,omitempty"`
+	QuoteID    *uint32 `json:"quoteID,omitempty"`
+	AssetID    *uint32 `json:"assetID,omitempty"`
+	WindowSecs *uint64 `json:"windowSecs,omitempty"`
+	Rearm      *bool   `json:"rearm,omitempty"`
+}
+
+// PriceAlertIDParams is the parameter type for the removepricealert route.
+type PriceAlertIDParams struct {
+	ID string `json:"id"`
+}
+
+// AddAPITokenParams is the parameter type for the addapitoken route.
+type AddAPITokenParams struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	AllowedIPs []string `json:"allowedIPs,omitempty"`
+	Lifetime   *string  `json:"lifetime,omitempty"`
+}
+
+// RevokeAPITokenParams is the parameter type for the revokeapitoken route.
+type RevokeAPITokenParams struct {
+	Token string `json:"token"`
+}
+
+// ExportTradesParams is the parameter type for the exporttrades route.
+type ExportTradesParams struct {
+	From    *uint64  `json:"from,omitempty"`
+	To      *uint64  `json:"to,omitempty"`
+	Format  *string  `json:"format,omitempty"`
+	Hosts   []string `json:"hosts,omitempty"`
+	Assets  []uint32 `json:"assets,omitempty"`
+	BaseID  *uint32  `json:"baseID,omitempty"`
+	QuoteID *uint32  `json:"quoteID,omitempty"`
+	OrderID *string  `json:"orderID,omitempty"`
+}
+
+// ExportRefundKitParams is the parameter type for the exportrefundkit route.
+type ExportRefundKitParams struct {
+	Path string `json:"path"`
+}
+
+// EstimateBridgeTradeParams is the parameter type for the estimatebridgetrade
+// route. The order fields are those of the trade route.
+type EstimateBridgeTradeParams struct {
+	TradeFirst        bool   `json:"tradeFirst"`
+	BridgeFromAssetID uint32 `json:"bridgeFromAssetID"`
+	BridgeToAssetID   uint32 `json:"bridgeToAssetID"`
+	BridgeName        string `json:"bridgeName"`
+	BridgeAmount      uint64 `json:"bridgeAmount"`
+	core.TradeForm
+}
+
+func (p *EstimateBridgeTradeParams) form() *core.BridgeTradeForm {
+	return &core.BridgeTradeForm{
+		TradeFirst:        p.TradeFirst,
+		BridgeFromAssetID: p.BridgeFromAssetID,
+		BridgeToAssetID:   p.BridgeToAssetID,
+		BridgeName:        p.BridgeName,
+		BridgeAmount:      p.BridgeAmount,
+		Trade:             p.TradeForm,
+	}
+}
+
+// StartBridgeTradeParams is the parameter type for the startbridgetrade route.
+type StartBridgeTradeParams struct {
+	AppPass encode.PassBytes `json:"appPass"`
+	EstimateBridgeTradeParams
+}
+
+// BridgeTradeIDParams is the parameter type for the cancelbridgetrade route.
+type BridgeTradeIDParams struct {
+	ID string `json:"id"`
+}
+
 // DeployContractParams is the parameter type for the deploycontract route.
 type DeployContractParams struct {
 	AppPass      encode.PassBytes `json:"appPass"`
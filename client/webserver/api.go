@@ -13,6 +13,7 @@ import (
 	"net/http"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"decred.org/dcrdex/client/asset"
@@ -1477,7 +1478,19 @@ func (s *WebServer) apiSend(w http.ResponseWriter, r *http.Request) {
 		s.writeAPIError(w, fmt.Errorf("empty password"))
 		return
 	}
-	coin, err := s.core.Send(form.Pass, form.AssetID, form.Value, form.Address, form.Subtract)
+	address := form.Address
+	if form.Contact != "" {
+		if address != "" {
+			s.writeAPIError(w, errors.New("specify address or contact, not both"))
+			return
+		}
+		var err error
+		if address, err = s.core.ContactAddress(form.AssetID, form.Contact); err != nil {
+			s.writeAPIError(w, err)
+			return
+		}
+	}
+	coin, err := s.core.Send(form.Pass, form.AssetID, form.Value, address, form.Subtract)
 	if err != nil {
 		s.writeAPIError(w, fmt.Errorf("send/withdraw error: %w", err))
 		return
@@ -1492,6 +1505,236 @@ func (s *WebServer) apiSend(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, resp)
 }
 
+// batchRecipients returns the form's recipients, parsing the CSV data if
+// provided.
+func (s *WebServer) batchRecipients(form *batchSendForm) ([]*asset.BatchRecipient, error) {
+	if form.CSV != "" {
+		if len(form.Recipients) > 0 {
+			return nil, errors.New("specify recipients or csv, not both")
+		}
+		return s.core.ParseBatchSendCSV(form.AssetID, form.CSV)
+	}
+	return form.Recipients, nil
+}
+
+// apiSendBatch handles the 'sendbatch' API request.
+func (s *WebServer) apiSendBatch(w http.ResponseWriter, r *http.Request) {
+	form := new(batchSendForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		s.writeAPIError(w, fmt.Errorf("empty password"))
+		return
+	}
+	recipients, err := s.batchRecipients(form)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	txIDs, err := s.core.SendBatch(form.Pass, form.AssetID, recipients)
+	if err != nil {
+		if len(txIDs) > 0 {
+			err = fmt.Errorf("%w (broadcast txs: %s)", err, strings.Join(txIDs, ", "))
+		}
+		s.writeAPIError(w, fmt.Errorf("batch send error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool     `json:"ok"`
+		TxIDs []string `json:"txIDs"`
+	}{
+		OK:    true,
+		TxIDs: txIDs,
+	})
+}
+
+// apiBatchTxFee handles the 'batchtxfee' API request.
+func (s *WebServer) apiBatchTxFee(w http.ResponseWriter, r *http.Request) {
+	form := new(batchSendForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	recipients, err := s.batchRecipients(form)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	txFee, err := s.core.EstimateBatchSendTxFee(form.AssetID, recipients)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK         bool                    `json:"ok"`
+		TxFee      uint64                  `json:"txfee"`
+		Recipients []*asset.BatchRecipient `json:"recipients"`
+	}{
+		OK:         true,
+		TxFee:      txFee,
+		Recipients: recipients,
+	})
+}
+
+// apiSetLabel handles the 'setlabel' API request. An empty label removes any
+// existing label.
+func (s *WebServer) apiSetLabel(w http.ResponseWriter, r *http.Request) {
+	form := new(db.Label)
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.SetLabel(form.AssetID, form.Kind, form.ID, form.Label); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error setting label: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiLabels handles the 'labels' API request.
+func (s *WebServer) apiLabels(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	labels, err := s.core.Labels(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK     bool        `json:"ok"`
+		Labels []*db.Label `json:"labels"`
+	}{
+		OK:     true,
+		Labels: labels,
+	})
+}
+
+// apiAddContact handles the 'addcontact' API request.
+func (s *WebServer) apiAddContact(w http.ResponseWriter, r *http.Request) {
+	form := new(db.Contact)
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.AddContact(form); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error adding contact: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiRemoveContact handles the 'removecontact' API request.
+func (s *WebServer) apiRemoveContact(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+		Name    string `json:"name"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	if err := s.core.RemoveContact(form.AssetID, form.Name); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error removing contact: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiContacts handles the 'contacts' API request.
+func (s *WebServer) apiContacts(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	contacts, err := s.core.Contacts(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK       bool          `json:"ok"`
+		Contacts []*db.Contact `json:"contacts"`
+	}{
+		OK:       true,
+		Contacts: contacts,
+	})
+}
+
+// apiSearchTxHistory handles the 'searchtxhistory' API request.
+func (s *WebServer) apiSearchTxHistory(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+		Query   string `json:"query"`
+		N       int    `json:"n"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	txs, err := s.core.SearchTxHistory(form.AssetID, form.Query, form.N)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK  bool                       `json:"ok"`
+		Txs []*asset.WalletTransaction `json:"txs"`
+	}{
+		OK:  true,
+		Txs: txs,
+	})
+}
+
+// apiSpendableCoins handles the 'spendablecoins' API request.
+func (s *WebServer) apiSpendableCoins(w http.ResponseWriter, r *http.Request) {
+	form := &struct {
+		AssetID uint32 `json:"assetID"`
+	}{}
+	if !readPost(w, r, form) {
+		return
+	}
+	coins, err := s.core.SpendableCoins(form.AssetID)
+	if err != nil {
+		s.writeAPIError(w, err)
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool                   `json:"ok"`
+		Coins []*asset.SpendableCoin `json:"coins"`
+	}{
+		OK:    true,
+		Coins: coins,
+	})
+}
+
+// apiSendWithCoins handles the 'sendwithcoins' API request.
+func (s *WebServer) apiSendWithCoins(w http.ResponseWriter, r *http.Request) {
+	form := new(coinControlSendForm)
+	defer form.Pass.Clear()
+	if !readPost(w, r, form) {
+		return
+	}
+	if len(form.Pass) == 0 {
+		s.writeAPIError(w, fmt.Errorf("empty password"))
+		return
+	}
+	coin, err := s.core.SendWithCoins(form.Pass, form.AssetID, &form.CoinControlSend)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("send error: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK   bool   `json:"ok"`
+		Coin string `json:"coin"`
+	}{
+		OK:   true,
+		Coin: coin.String(),
+	})
+}
+
 // apiMaxBuy handles the 'maxbuy' API request.
 func (s *WebServer) apiMaxBuy(w http.ResponseWriter, r *http.Request) {
 	form := &struct {
@@ -2576,3 +2819,82 @@ func (s *WebServer) apiCastVote(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, simpleAck())
 }
+
+// apiPriceAlerts handles the '/pricealerts' API request.
+func (s *WebServer) apiPriceAlerts(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, &struct {
+		OK     bool             `json:"ok"`
+		Alerts []*db.PriceAlert `json:"alerts"`
+	}{
+		OK:     true,
+		Alerts: s.core.PriceAlerts(),
+	})
+}
+
+// apiAddPriceAlert handles the '/addpricealert' API request.
+func (s *WebServer) apiAddPriceAlert(w http.ResponseWriter, r *http.Request) {
+	form := new(core.PriceAlertForm)
+	if !readPost(w, r, form) {
+		return
+	}
+	alert, err := s.core.AddPriceAlert(form)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error adding price alert: %w", err))
+		return
+	}
+	writeJSON(w, &struct {
+		OK    bool           `json:"ok"`
+		Alert *db.PriceAlert `json:"alert"`
+	}{
+		OK:    true,
+		Alert: alert,
+	})
+}
+
+// apiRemovePriceAlert handles the '/removepricealert' API request.
+func (s *WebServer) apiRemovePriceAlert(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID string `json:"id"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+	if err := s.core.RemovePriceAlert(req.ID); err != nil {
+		s.writeAPIError(w, fmt.Errorf("error removing price alert: %w", err))
+		return
+	}
+	writeJSON(w, simpleAck())
+}
+
+// apiExportTrades handles the '/exporttrades' API request. The trade history
+// is returned as JSON, or as a CSV file download if the format is "csv".
+func (s *WebServer) apiExportTrades(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		core.TradeExportFilter
+		Format string `json:"format"`
+	}
+	if !readPost(w, r, &req) {
+		return
+	}
+	recs, err := s.core.ExportTrades(&req.TradeExportFilter)
+	if err != nil {
+		s.writeAPIError(w, fmt.Errorf("error exporting trades: %w", err))
+		return
+	}
+	if req.Format != "csv" {
+		writeJSON(w, &struct {
+			OK     bool                      `json:"ok"`
+			Trades []*core.TradeExportRecord `json:"trades"`
+		}{
+			OK:     true,
+			Trades: recs,
+		})
+		return
+	}
+	w.Header().Set("Content-Disposition", "attachment; filename=trades.csv")
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	if err := core.WriteTradesCSV(w, recs, strings.Contains(r.UserAgent(), "Windows")); err != nil {
+		log.Errorf("error writing trades CSV: %v", err)
+	}
+}
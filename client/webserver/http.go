@@ -35,6 +35,7 @@ const (
 	settingsRoute    = "/settings"
 	ordersRoute      = "/orders"
 	exportOrderRoute = "/orders/export"
+	exportTxsRoute   = "/wallets/txhistory/export"
 	marketMakerRoute = "/mm"
 	mmSettingsRoute  = "/mmsettings"
 	mmArchivesRoute  = "/mmarchives"
@@ -499,6 +500,92 @@ func (s *WebServer) handleExportOrders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleExportTxHistory is the handler for the /wallets/txhistory/export
+// request. The wallet's transaction history is written as CSV, with the user's
+// transaction and address labels.
+func (s *WebServer) handleExportTxHistory(w http.ResponseWriter, r *http.Request) {
+	assetID, err := strconv.ParseUint(r.URL.Query().Get("assetID"), 10, 32)
+	if err != nil {
+		log.Errorf("error parsing asset id for tx history export: %v", err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	ui, err := asset.UnitInfo(uint32(assetID))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	feeUI := ui
+	if tkn := asset.TokenInfo(uint32(assetID)); tkn != nil {
+		if feeUI, err = asset.UnitInfo(tkn.ParentID); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	res, err := s.core.TxHistory(uint32(assetID), &asset.TxHistoryRequest{Past: true})
+	if err != nil {
+		log.Errorf("error retrieving tx history: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-txs.csv", dex.BipIDSymbol(uint32(assetID))))
+	w.Header().Set("Content-Type", "text/csv")
+	w.WriteHeader(http.StatusOK)
+	csvWriter := csv.NewWriter(w)
+	csvWriter.UseCRLF = strings.Contains(r.UserAgent(), "Windows")
+
+	err = csvWriter.Write([]string{
+		"Time",
+		"Type",
+		"Tx ID",
+		"Amount",
+		"Amount Asset",
+		"Fees",
+		"Fees Asset",
+		"Recipient",
+		"Recipient Label",
+		"Label",
+		"Confirmed",
+	})
+	if err != nil {
+		log.Errorf("error writing CSV: %v", err)
+		return
+	}
+
+	for _, tx := range res.Txs {
+		var timestamp, recipient string
+		if tx.Timestamp > 0 {
+			timestamp = time.Unix(int64(tx.Timestamp), 0).Local().Format(time.RFC3339)
+		}
+		if tx.Recipient != nil {
+			recipient = *tx.Recipient
+		}
+		err = csvWriter.Write([]string{
+			timestamp,                         // Time
+			tx.Type.String(),                  // Type
+			tx.ID,                             // Tx ID
+			ui.ConventionalString(tx.Amount),  // Amount
+			ui.Conventional.Unit,              // Amount Asset
+			feeUI.ConventionalString(tx.Fees), // Fees
+			feeUI.Conventional.Unit,           // Fees Asset
+			recipient,                         // Recipient
+			tx.RecipientLabel,                 // Recipient Label
+			tx.Label,                          // Label
+			strconv.FormatBool(tx.Confirmed),  // Confirmed
+		})
+		if err != nil {
+			log.Errorf("error writing CSV: %v", err)
+			return
+		}
+	}
+	csvWriter.Flush()
+	if err = csvWriter.Error(); err != nil {
+		log.Errorf("error writing CSV: %v", err)
+	}
+}
+
 type orderTmplData struct {
 	CommonArguments
 	Order *core.OrderReader
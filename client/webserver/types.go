@@ -4,6 +4,7 @@
 package webserver
 
 import (
+	"decred.org/dcrdex/client/asset"
 	"decred.org/dcrdex/client/core"
 	"decred.org/dcrdex/client/db"
 	"decred.org/dcrdex/dex"
@@ -115,11 +116,30 @@ type cancelForm struct {
 
 // sendForm is sent to initiate either send tx.
 type sendForm struct {
-	AssetID  uint32           `json:"assetID"`
-	Value    uint64           `json:"value"`
-	Address  string           `json:"address"`
-	Subtract bool             `json:"subtract"`
-	Pass     encode.PassBytes `json:"pw"`
+	AssetID  uint32 `json:"assetID"`
+	Value    uint64 `json:"value"`
+	Address  string `json:"address"`
+	Subtract bool   `json:"subtract"`
+	// Contact is the name of an address book contact, and can be used
+	// instead of Address.
+	Contact string           `json:"contact"`
+	Pass    encode.PassBytes `json:"pw"`
+}
+
+// batchSendForm is the form for the '/sendbatch' and '/batchtxfee' API
+// requests. Either Recipients or CSV should be provided.
+type batchSendForm struct {
+	AssetID    uint32                  `json:"assetID"`
+	Recipients []*asset.BatchRecipient `json:"recipients"`
+	CSV        string                  `json:"csv"`
+	Pass       encode.PassBytes        `json:"pw"`
+}
+
+// coinControlSendForm is the form for the '/sendwithcoins' API request.
+type coinControlSendForm struct {
+	AssetID uint32 `json:"assetID"`
+	asset.CoinControlSend
+	Pass encode.PassBytes `json:"pw"`
 }
 
 type accountExportForm struct {
@@ -200,6 +200,22 @@ type clientCore interface {
 	Proposal(assetID uint32, token string) (*pi.Proposal, error)
 	ProposalsInProgress() ([]*pi.MiniProposal, error)
 	CastVote(assetID uint32, pw []byte, token, bit string) error
+	PriceAlerts() []*db.PriceAlert
+	AddPriceAlert(form *core.PriceAlertForm) (*db.PriceAlert, error)
+	RemovePriceAlert(id string) error
+	SendBatch(pw []byte, assetID uint32, recipients []*asset.BatchRecipient) ([]string, error)
+	EstimateBatchSendTxFee(assetID uint32, recipients []*asset.BatchRecipient) (uint64, error)
+	ParseBatchSendCSV(assetID uint32, csvData string) ([]*asset.BatchRecipient, error)
+	SpendableCoins(assetID uint32) ([]*asset.SpendableCoin, error)
+	SendWithCoins(pw []byte, assetID uint32, send *asset.CoinControlSend) (asset.Coin, error)
+	SearchTxHistory(assetID uint32, query string, n int) ([]*asset.WalletTransaction, error)
+	SetLabel(assetID uint32, kind db.LabelKind, id, label string) error
+	Labels(assetID uint32) ([]*db.Label, error)
+	AddContact(contact *db.Contact) error
+	RemoveContact(assetID uint32, name string) error
+	Contacts(assetID uint32) ([]*db.Contact, error)
+	ContactAddress(assetID uint32, name string) (string, error)
+	ExportTrades(filter *core.TradeExportFilter) ([]*core.TradeExportRecord, error)
 }
 
 type MMCore interface {
@@ -537,6 +553,7 @@ func New(cfg *Config) (*WebServer, error) {
 					webAuth.Get(homeRoute, s.handleHome)
 					webAuth.Get(walletsRoute, s.handleWallets)
 					webAuth.Get(walletLogRoute, s.handleWalletLogFile)
+					webAuth.Get(exportTxsRoute, s.handleExportTxHistory)
 					webAuth.With(proposalTokenCtx).Get("/proposal/{token}", s.handleProposal)
 					webAuth.Get(proposalsRoute, s.handleProposals)
 					webAuth.Get("/generatecompanionappqrcode", s.handleGenerateCompanionAppQRCode)
@@ -608,6 +625,16 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/orders", s.apiOrders)
 			apiAuth.Post("/order", s.apiOrder)
 			apiAuth.Post("/send", s.apiSend)
+			apiAuth.Post("/sendbatch", s.apiSendBatch)
+			apiAuth.Post("/batchtxfee", s.apiBatchTxFee)
+			apiAuth.Post("/spendablecoins", s.apiSpendableCoins)
+			apiAuth.Post("/sendwithcoins", s.apiSendWithCoins)
+			apiAuth.Post("/setlabel", s.apiSetLabel)
+			apiAuth.Post("/labels", s.apiLabels)
+			apiAuth.Post("/addcontact", s.apiAddContact)
+			apiAuth.Post("/removecontact", s.apiRemoveContact)
+			apiAuth.Post("/contacts", s.apiContacts)
+			apiAuth.Post("/searchtxhistory", s.apiSearchTxHistory)
 			apiAuth.Post("/maxbuy", s.apiMaxBuy)
 			apiAuth.Post("/maxsell", s.apiMaxSell)
 			apiAuth.Post("/preorder", s.apiPreOrder)
@@ -671,6 +698,10 @@ func New(cfg *Config) (*WebServer, error) {
 			apiAuth.Post("/bridgehistory", s.apiBridgeHistory)
 			apiAuth.Post("/castvote", s.apiCastVote)
 			apiAuth.Post("/unpaircompanionapp", s.apiUnpairCompanionApp)
+			apiAuth.Get("/pricealerts", s.apiPriceAlerts)
+			apiAuth.Post("/addpricealert", s.apiAddPriceAlert)
+			apiAuth.Post("/removepricealert", s.apiRemovePriceAlert)
+			apiAuth.Post("/exporttrades", s.apiExportTrades)
 		})
 	})
 
@@ -370,6 +370,52 @@ func (*TCore) CastVote(assetID uint32, pw []byte, token, bit string) error {
 	return nil
 }
 
+func (*TCore) PriceAlerts() []*db.PriceAlert {
+	return nil
+}
+
+func (*TCore) AddPriceAlert(form *core.PriceAlertForm) (*db.PriceAlert, error) {
+	return &db.PriceAlert{}, nil
+}
+
+func (*TCore) RemovePriceAlert(id string) error {
+	return nil
+}
+
+func (*TCore) SendBatch(pw []byte, assetID uint32, recipients []*asset.BatchRecipient) ([]string, error) {
+	return []string{"abc"}, nil
+}
+
+func (*TCore) EstimateBatchSendTxFee(assetID uint32, recipients []*asset.BatchRecipient) (uint64, error) {
+	return 0, nil
+}
+
+func (*TCore) ParseBatchSendCSV(assetID uint32, csvData string) ([]*asset.BatchRecipient, error) {
+	return nil, nil
+}
+
+func (*TCore) SpendableCoins(assetID uint32) ([]*asset.SpendableCoin, error) {
+	return nil, nil
+}
+
+func (*TCore) SendWithCoins(pw []byte, assetID uint32, send *asset.CoinControlSend) (asset.Coin, error) {
+	return &tCoin{id: []byte{0xb0}}, nil
+}
+func (*TCore) SearchTxHistory(assetID uint32, query string, n int) ([]*asset.WalletTransaction, error) {
+	return nil, nil
+}
+func (*TCore) SetLabel(assetID uint32, kind db.LabelKind, id, label string) error { return nil }
+func (*TCore) Labels(assetID uint32) ([]*db.Label, error)                         { return nil, nil }
+func (*TCore) AddContact(contact *db.Contact) error                               { return nil }
+func (*TCore) RemoveContact(assetID uint32, name string) error                    { return nil }
+func (*TCore) Contacts(assetID uint32) ([]*db.Contact, error)                     { return nil, nil }
+func (*TCore) ContactAddress(assetID uint32, name string) (string, error) {
+	return "", errors.New("unknown contact")
+}
+func (*TCore) ExportTrades(filter *core.TradeExportFilter) ([]*core.TradeExportRecord, error) {
+	return nil, nil
+}
+
 func (*TCore) PoliteiaDetails() (string, bool, int64) {
 	return "", false, 0
 }
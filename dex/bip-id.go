@@ -4,11 +4,54 @@
 package dex
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 )
 
 var symbolBipIDs map[string]uint32
 
+// customSymbols are the symbols of assets that are registered at runtime, e.g.
+// user-defined tokens. They are kept separate from bipIDs so that the static
+// map is never written after init.
+var customSymbols struct {
+	sync.RWMutex
+	ids     map[uint32]string
+	symbols map[string]uint32
+}
+
+// RegisterCustomSymbol registers a symbol for an asset ID that is not in the
+// BIP ID list. An error is returned if the ID or the symbol is already known.
+// Registering the same symbol for the same ID again is not an error.
+func RegisterCustomSymbol(id uint32, symbol string) error {
+	symbol = strings.ToLower(symbol)
+	if sym, found := bipIDs[id]; found {
+		return fmt.Errorf("asset ID %d is already registered as %s", id, sym)
+	}
+	customSymbols.Lock()
+	defer customSymbols.Unlock()
+	if sym, found := customSymbols.ids[id]; found {
+		if sym == symbol {
+			return nil
+		}
+		return fmt.Errorf("asset ID %d is already registered as %s", id, sym)
+	}
+	// symbolBipIDs is populated by init.
+	if _, found := symbolBipIDs[symbol]; found {
+		return fmt.Errorf("symbol %s is already registered", symbol)
+	}
+	if _, found := customSymbols.symbols[symbol]; found {
+		return fmt.Errorf("symbol %s is already registered", symbol)
+	}
+	if customSymbols.ids == nil {
+		customSymbols.ids = make(map[uint32]string)
+		customSymbols.symbols = make(map[string]uint32)
+	}
+	customSymbols.ids[id] = symbol
+	customSymbols.symbols[symbol] = id
+	return nil
+}
+
 // BipSymbolID returns the asset ID associated with a given ticker symbol.
 // While there are a number of duplicate ticker symbols in the BIP ID list
 // (cpc, cmt, xrd, dst, one, ask, ...), those are disambiguated in the bipIDs
@@ -21,13 +64,23 @@ func BipSymbolID(symbol string) (uint32, bool) {
 		}
 	}
 
-	idx, found := symbolBipIDs[symbol]
+	if idx, found := symbolBipIDs[symbol]; found {
+		return idx, true
+	}
+	customSymbols.RLock()
+	defer customSymbols.RUnlock()
+	idx, found := customSymbols.symbols[symbol]
 	return idx, found
 }
 
 // BipIDSymbol returns the BIP ID for a given symbol.
 func BipIDSymbol(id uint32) string {
-	return bipIDs[id]
+	if sym, found := bipIDs[id]; found {
+		return sym
+	}
+	customSymbols.RLock()
+	defer customSymbols.RUnlock()
+	return customSymbols.ids[id]
 }
 
 // TokenSymbol returns the tokens raw symbol if this is compound symbol that
@@ -71,3 +71,35 @@ func TestBipSymbolID(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisterCustomSymbol(t *testing.T) {
+	const id = 1_500_000_000
+	if err := RegisterCustomSymbol(id, "FAKE.eth"); err != nil {
+		t.Fatalf("RegisterCustomSymbol error: %v", err)
+	}
+	if sym := BipIDSymbol(id); sym != "fake.eth" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	if got, found := BipSymbolID("fake.eth"); !found || got != id {
+		t.Fatalf("wrong ID %d, found = %t", got, found)
+	}
+	// Registering again is fine.
+	if err := RegisterCustomSymbol(id, "fake.eth"); err != nil {
+		t.Fatalf("error re-registering: %v", err)
+	}
+	// But not with a different symbol.
+	if err := RegisterCustomSymbol(id, "other.eth"); err == nil {
+		t.Fatal("no error for different symbol")
+	}
+	// Symbols must be unique.
+	if err := RegisterCustomSymbol(id+1, "fake.eth"); err == nil {
+		t.Fatal("no error for duplicate symbol")
+	}
+	if err := RegisterCustomSymbol(id+1, "usdc.eth"); err == nil {
+		t.Fatal("no error for built-in symbol")
+	}
+	// Built-in IDs can't be registered.
+	if err := RegisterCustomSymbol(42, "notdcr"); err == nil {
+		t.Fatal("no error for built-in ID")
+	}
+}
@@ -0,0 +1,116 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package lexi
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// backupMagic starts every lexi backup, so that a restore can recognize a file
+// that isn't one.
+var backupMagic = []byte("lexibackup\x00\x00")
+
+// maxPendingRestoreWrites is the number of pending writes allowed when loading
+// a backup.
+const maxPendingRestoreWrites = 256
+
+// BackupTo writes a consistent snapshot of the database to w. The database
+// remains usable while the backup is written, but writes made after the backup
+// starts are not included. Expired entries are not included.
+func (db *DB) BackupTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(backupMagic); err != nil {
+		return fmt.Errorf("error writing backup header: %w", err)
+	}
+	stream := db.NewStream()
+	stream.LogPrefix = "lexi.Backup"
+	if _, err := stream.Backup(bw, 0); err != nil {
+		return fmt.Errorf("error streaming backup: %w", err)
+	}
+	return bw.Flush()
+}
+
+// BackupFile writes a backup to the file at path. The backup is written to a
+// temporary file first, so an existing file at path is only replaced by a
+// complete backup.
+func (db *DB) BackupFile(path string) (err error) {
+	tmpPath := path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("error creating backup file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+	if err = db.BackupTo(f); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("error syncing backup file: %w", err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("error closing backup file: %w", err)
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Restore creates a new database at cfg.Path from a backup written by BackupTo.
+// There must not already be a database at cfg.Path. The restored database is
+// opened with New.
+func Restore(r io.Reader, cfg *Config) (*DB, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(backupMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || !bytes.Equal(magic, backupMagic) {
+		return nil, errors.New("not a lexi backup")
+	}
+	v4Path, needsUpdate, err := NeedsV1toV4Update(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	if needsUpdate {
+		return nil, fmt.Errorf("a database already exists at %s", cfg.Path)
+	}
+	if entries, err := os.ReadDir(v4Path); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("a database already exists at %s", v4Path)
+	}
+	if err := os.MkdirAll(filepath.Dir(v4Path), 0700); err != nil {
+		return nil, err
+	}
+	opts := badger.DefaultOptions(v4Path).WithLogger(&badgerLoggerWrapper{cfg.Log.SubLogger("BADG")})
+	bdb, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := bdb.Load(br, maxPendingRestoreWrites); err != nil {
+		bdb.Close()
+		os.RemoveAll(v4Path)
+		return nil, fmt.Errorf("error loading backup: %w", err)
+	}
+	if err := bdb.Close(); err != nil {
+		return nil, err
+	}
+	return New(cfg)
+}
+
+// RestoreFile is like Restore, but reads the backup from the file at
+// backupPath.
+func RestoreFile(backupPath string, cfg *Config) (*DB, error) {
+	f, err := os.Open(backupPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Restore(f, cfg)
+}
@@ -8,6 +8,12 @@
 // Usage:
 //
 //	go run ./dex/lexi/cmd/lexidbexplorer /path/to/lexi.db
+//
+// The backup and restore subcommands write an online backup of a database to a
+// file, and create a new database from a backup file.
+//
+//	go run ./dex/lexi/cmd/lexidbexplorer backup /path/to/lexi.db /path/to/backup
+//	go run ./dex/lexi/cmd/lexidbexplorer restore /path/to/backup /path/to/new.db
 package main
 
 import (
@@ -19,12 +25,36 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const usage = `Usage:
+  lexidbexplorer <path-to-lexi-db>
+  lexidbexplorer backup <path-to-lexi-db> <backup-file>
+  lexidbexplorer restore <backup-file> <path-to-new-lexi-db>`
+
 func main() {
 	if len(os.Args) < 2 {
-		fmt.Fprintln(os.Stderr, "Usage: lexidbexplorer <path-to-lexi-db>")
+		fmt.Fprintln(os.Stderr, usage)
 		os.Exit(1)
 	}
 
+	switch os.Args[1] {
+	case "backup", "restore":
+		if len(os.Args) != 4 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		var err error
+		if os.Args[1] == "backup" {
+			err = backup(os.Args[2], os.Args[3])
+		} else {
+			err = restore(os.Args[2], os.Args[3])
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	dbPath := os.Args[1]
 
 	// Check if path exists
@@ -59,3 +89,36 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// backup writes a backup of the database at dbPath to backupPath.
+func backup(dbPath, backupPath string) error {
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return fmt.Errorf("database path does not exist: %s", dbPath)
+	}
+	db, err := lexi.New(&lexi.Config{
+		Path: dbPath,
+		Log:  dex.StdOutLogger("LEXI", dex.LevelOff),
+	})
+	if err != nil {
+		return fmt.Errorf("error opening database: %w", err)
+	}
+	defer db.Close()
+	if err := db.BackupFile(backupPath); err != nil {
+		return err
+	}
+	fmt.Printf("Backed up %s to %s\n", dbPath, backupPath)
+	return nil
+}
+
+// restore creates a new database at dbPath from the backup at backupPath.
+func restore(backupPath, dbPath string) error {
+	db, err := lexi.RestoreFile(backupPath, &lexi.Config{
+		Path: dbPath,
+		Log:  dex.StdOutLogger("LEXI", dex.LevelOff),
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Restored %s to %s\n", backupPath, dbPath)
+	return db.Close()
+}
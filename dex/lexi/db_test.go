@@ -2,6 +2,7 @@ package lexi
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -10,6 +11,7 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
@@ -1120,3 +1122,253 @@ func TestNewWithMigration(t *testing.T) {
 		t.Fatalf("Expected v4 directory to exist at %s", v4Path)
 	}
 }
+
+func TestTableMigrate(t *testing.T) {
+	db, shutdown := newTestDB(t)
+	defer shutdown()
+
+	tbl, err := db.Table("MigrateTest")
+	if err != nil {
+		t.Fatalf("Error creating table: %v", err)
+	}
+	checkVersion := func(exp uint32) {
+		t.Helper()
+		v, err := tbl.Version()
+		if err != nil {
+			t.Fatalf("Version error: %v", err)
+		}
+		if v != exp {
+			t.Fatalf("expected version %d, got %d", exp, v)
+		}
+	}
+	checkVersion(0)
+
+	k := []byte{0x01}
+	var calls int
+	upgradeOne := func(tbl *Table) error {
+		calls++
+		return tbl.Set(k, []byte{0x01})
+	}
+	upgradeTwo := func(tbl *Table) error {
+		calls++
+		return tbl.Set(k, []byte{0x02}, WithReplace())
+	}
+	if err := tbl.Migrate(upgradeOne); err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	checkVersion(1)
+
+	// Applied upgrades are skipped.
+	calls = 0
+	if err := tbl.Migrate(upgradeOne, upgradeTwo); err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 upgrade, got %d", calls)
+	}
+	checkVersion(2)
+	if v, err := tbl.GetRaw(k); err != nil || !bytes.Equal(v, []byte{0x02}) {
+		t.Fatalf("wrong value after upgrade %x, %v", v, err)
+	}
+
+	// A failed upgrade is rolled back, and the version is not advanced.
+	failingUpgrade := func(tbl *Table) error {
+		if err := tbl.Set(k, []byte{0x03}, WithReplace()); err != nil {
+			return err
+		}
+		return errors.New("test error")
+	}
+	if err := tbl.Migrate(upgradeOne, upgradeTwo, failingUpgrade); err == nil {
+		t.Fatal("no error for failed upgrade")
+	}
+	checkVersion(2)
+	if v, err := tbl.GetRaw(k); err != nil || !bytes.Equal(v, []byte{0x02}) {
+		t.Fatalf("failed upgrade not rolled back %x, %v", v, err)
+	}
+
+	// Tables from the future are rejected.
+	if err := tbl.Migrate(upgradeOne); err == nil {
+		t.Fatal("no error for unknown version")
+	}
+
+	// Versions are per-table.
+	otherTbl, err := db.Table("OtherMigrateTest")
+	if err != nil {
+		t.Fatalf("Error creating table: %v", err)
+	}
+	if v, err := otherTbl.Version(); err != nil || v != 0 {
+		t.Fatalf("wrong version for other table %d, %v", v, err)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for entries to expire")
+	}
+	db, shutdown := newTestDB(t)
+	defer shutdown()
+
+	tbl, err := db.Table("TTLTest")
+	if err != nil {
+		t.Fatalf("Error creating table: %v", err)
+	}
+	idx, err := tbl.AddIndex("V", func(k, v KV) ([]byte, error) {
+		return v.([]byte), nil
+	})
+	if err != nil {
+		t.Fatalf("Error adding index: %v", err)
+	}
+
+	kTemp, kPerm, kRenewed := []byte{0x01}, []byte{0x02}, []byte{0x03}
+	if err := tbl.Set(kTemp, []byte{0x01}, WithTTL(time.Second)); err != nil {
+		t.Fatalf("Error setting temporary value: %v", err)
+	}
+	if err := tbl.Set(kPerm, []byte{0x02}); err != nil {
+		t.Fatalf("Error setting permanent value: %v", err)
+	}
+	// Replacing without a TTL makes the entry permanent.
+	if err := tbl.Set(kRenewed, []byte{0x03}, WithTTL(time.Second)); err != nil {
+		t.Fatalf("Error setting renewed value: %v", err)
+	}
+	if err := tbl.Set(kRenewed, []byte{0x03}, WithReplace()); err != nil {
+		t.Fatalf("Error replacing renewed value: %v", err)
+	}
+
+	var expiring int
+	if err := idx.Iterate(nil, func(it *Iter) error {
+		if !it.ExpiresAt().IsZero() {
+			expiring++
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Error iterating index: %v", err)
+	}
+	if expiring != 1 {
+		t.Fatalf("expected 1 expiring entry, got %d", expiring)
+	}
+
+	time.Sleep(time.Second * 2)
+
+	if _, err := tbl.GetRaw(kTemp); !errors.Is(err, ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound for expired entry, got %v", err)
+	}
+	for _, k := range [][]byte{kPerm, kRenewed} {
+		if _, err := tbl.GetRaw(k); err != nil {
+			t.Fatalf("Error getting permanent value %x: %v", k, err)
+		}
+	}
+	var n int
+	if err := idx.Iterate(nil, func(it *Iter) error {
+		n++
+		return nil
+	}); err != nil {
+		t.Fatalf("Error iterating index: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 index entries after expiry, got %d", n)
+	}
+	// The key mappings expired too, so the key can be set again without
+	// WithReplace.
+	if err := db.View(func(txn *badger.Txn) error {
+		_, err := txn.Get(PrefixedKey(keyToIDPrefix, kTemp))
+		return err
+	}); !errors.Is(err, badger.ErrKeyNotFound) {
+		t.Fatalf("expected expired key mapping, got %v", err)
+	}
+	if err := tbl.Set(kTemp, []byte{0x01}); err != nil {
+		t.Fatalf("Error setting expired key again: %v", err)
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	db, shutdown := newTestDB(t)
+	defer shutdown()
+
+	tbl, err := db.Table("BackupTest")
+	if err != nil {
+		t.Fatalf("Error creating table: %v", err)
+	}
+	if _, err := tbl.AddIndex("V", func(k, v KV) ([]byte, error) {
+		return v.([]byte), nil
+	}); err != nil {
+		t.Fatalf("Error adding index: %v", err)
+	}
+	for i := range 10 {
+		if err := tbl.Set([]byte{byte(i)}, []byte{byte(9 - i)}); err != nil {
+			t.Fatalf("Error setting value %d: %v", i, err)
+		}
+	}
+	if err := tbl.Migrate(func(*Table) error { return nil }); err != nil {
+		t.Fatalf("Migrate error: %v", err)
+	}
+
+	tmpDir := t.TempDir()
+	backupPath := filepath.Join(tmpDir, "backup")
+	if err := db.BackupFile(backupPath); err != nil {
+		t.Fatalf("BackupFile error: %v", err)
+	}
+	// Writes after the backup are not included.
+	if err := tbl.Set([]byte{0x10}, []byte{0x10}); err != nil {
+		t.Fatalf("Error setting value after backup: %v", err)
+	}
+
+	cfg := &Config{
+		Path: filepath.Join(tmpDir, "restored.db"),
+		Log:  dex.StdOutLogger("T", dex.LevelInfo),
+	}
+	restored, err := RestoreFile(backupPath, cfg)
+	if err != nil {
+		t.Fatalf("RestoreFile error: %v", err)
+	}
+	defer restored.Close()
+
+	rTbl, err := restored.Table("BackupTest")
+	if err != nil {
+		t.Fatalf("Error getting restored table: %v", err)
+	}
+	rIdx, err := rTbl.AddIndex("V", func(k, v KV) ([]byte, error) {
+		return v.([]byte), nil
+	})
+	if err != nil {
+		t.Fatalf("Error adding index to restored table: %v", err)
+	}
+	var i int
+	if err := rIdx.Iterate(nil, func(it *Iter) error {
+		k, err := it.K()
+		if err != nil {
+			return err
+		}
+		if exp := byte(9 - i); k[0] != exp {
+			return fmt.Errorf("expected key %d, got %d", exp, k[0])
+		}
+		i++
+		return nil
+	}); err != nil {
+		t.Fatalf("Error iterating restored index: %v", err)
+	}
+	if i != 10 {
+		t.Fatalf("expected 10 restored entries, got %d", i)
+	}
+	if v, err := rTbl.Version(); err != nil || v != 1 {
+		t.Fatalf("wrong restored table version %d, %v", v, err)
+	}
+	// New entries don't collide with restored ones.
+	if err := rTbl.Set([]byte{0x10}, []byte{0x10}); err != nil {
+		t.Fatalf("Error setting value in restored db: %v", err)
+	}
+	if v, err := rTbl.GetRaw([]byte{0x00}); err != nil || !bytes.Equal(v, []byte{0x09}) {
+		t.Fatalf("wrong restored value %x, %v", v, err)
+	}
+
+	// Restoring over an existing database is refused.
+	if _, err := RestoreFile(backupPath, cfg); err == nil {
+		t.Fatal("no error restoring over existing database")
+	}
+	// Not a backup.
+	if _, err := Restore(bytes.NewReader([]byte("not a backup")), &Config{
+		Path: filepath.Join(tmpDir, "bad.db"),
+		Log:  dex.StdOutLogger("T", dex.LevelInfo),
+	}); err == nil {
+		t.Fatal("no error restoring garbage")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"time"
 
 	"decred.org/dcrdex/dex"
 	"github.com/dgraph-io/badger/v4"
@@ -117,7 +118,7 @@ func (db *DB) DeleteIndex(tableName, indexName string) error {
 				if err != nil {
 					return fmt.Errorf("error encoding datum after index deletion: %w", err)
 				}
-				if err := txn.Set(iter.Item().Key(), b); err != nil {
+				if err := setEntry(txn, iter.Item().KeyCopy(nil), b, iter.Item().ExpiresAt()); err != nil {
 					return fmt.Errorf("error storing new datum after index deletion: %w", err)
 				}
 				return nil
@@ -154,8 +155,9 @@ func (db *DB) ReIndex(tableName, indexName string, f func(k, v []byte) ([]byte,
 					return fmt.Errorf("error decoding datum: %w", err)
 				}
 
-				tableKey := iter.Item().Key()
+				tableKey := iter.Item().KeyCopy(nil)
 				dbIDB := tableKey[PrefixSize:]
+				expiresAt := iter.Item().ExpiresAt()
 
 				// Get the original key for this datum
 				keyItem, err := txn.Get(PrefixedKey(IdToKeyPrefix, dbIDB))
@@ -186,7 +188,7 @@ func (db *DB) ReIndex(tableName, indexName string, f func(k, v []byte) ([]byte,
 					if err != nil {
 						return fmt.Errorf("error encoding datum after index reindex: %w", err)
 					}
-					if err := txn.Set(tableKey, b); err != nil {
+					if err := setEntry(txn, tableKey, b, expiresAt); err != nil {
 						return fmt.Errorf("error storing new datum after index reindex: %w", err)
 					}
 					return nil
@@ -218,12 +220,12 @@ func (db *DB) ReIndex(tableName, indexName string, f func(k, v []byte) ([]byte,
 				if err != nil {
 					return fmt.Errorf("error encoding datum after index reindex: %w", err)
 				}
-				if err := txn.Set(tableKey, b); err != nil {
+				if err := setEntry(txn, tableKey, b, expiresAt); err != nil {
 					return fmt.Errorf("error storing new datum after reindex: %w", err)
 				}
 
 				// Store the new index entry
-				if err := txn.Set(indexEntry, nil); err != nil {
+				if err := setEntry(txn, indexEntry, nil, expiresAt); err != nil {
 					return fmt.Errorf("error storing index entry for reindex: %w", err)
 				}
 				return nil
@@ -284,7 +286,7 @@ func (idx *Index) checkForIndexConflict(txn *badger.Txn, idxB []byte, updatingDB
 
 // add adds an entry to the index. If this element is not required to be
 // indexed, a nil byte slice is returned and no error is returned.
-func (idx *Index) add(txn *badger.Txn, k, v KV, dbID DBID) ([]byte, error) {
+func (idx *Index) add(txn *badger.Txn, k, v KV, dbID DBID, expiresAt uint64) ([]byte, error) {
 	idxB, err := idx.f(k, v)
 	if err != nil {
 		if errors.Is(err, ErrNotIndexed) {
@@ -300,7 +302,7 @@ func (idx *Index) add(txn *badger.Txn, k, v KV, dbID DBID) ([]byte, error) {
 		}
 	}
 
-	if err := txn.Set(b, nil); err != nil {
+	if err := setEntry(txn, b, nil, expiresAt); err != nil {
 		return nil, fmt.Errorf("error writing index entry: %w", err)
 	}
 	return b, nil
@@ -409,6 +411,16 @@ func (i *Iter) datum() (_ *datum, err error) {
 	return i.d, nil
 }
 
+// ExpiresAt is the time at which the datum expires. The zero time is returned
+// if the datum was stored without a TTL.
+func (i *Iter) ExpiresAt() time.Time {
+	expiresAt := i.item.ExpiresAt()
+	if expiresAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(expiresAt), 0)
+}
+
 // Delete deletes the indexed datum and any associated index entries.
 func (i *Iter) Delete() error {
 	d, err := i.datum()
@@ -35,6 +35,7 @@ var (
 	keyToIDPrefix         = keyPrefix{0x00, 0x03}
 	versionPrefix         = keyPrefix{0x00, 0x05}
 	IdToKeyPrefix         = keyPrefix{0x00, 0x04}
+	tableVersionPrefix    = keyPrefix{0x00, 0x06}
 
 	firstAvailablePrefix = keyPrefix{0x01, 0x00}
 )
@@ -357,6 +357,26 @@ func (db *DB) keyID(txn *badger.Txn, kB []byte, readOnly bool) (dbID DBID, err e
 	return
 }
 
+// setKeyMappings sets the key-to-id and id-to-key mappings. If expiresAt is
+// non-zero, the mappings expire at that unix time.
+func (db *DB) setKeyMappings(txn *badger.Txn, kB []byte, dbID DBID, expiresAt uint64) error {
+	if err := setEntry(txn, PrefixedKey(keyToIDPrefix, kB), dbID[:], expiresAt); err != nil {
+		return fmt.Errorf("error mapping key to ID: %w", err)
+	}
+	if err := setEntry(txn, PrefixedKey(IdToKeyPrefix, dbID[:]), kB, expiresAt); err != nil {
+		return fmt.Errorf("error mapping ID to key: %w", err)
+	}
+	return nil
+}
+
+// setEntry sets the value for the key. If expiresAt is non-zero, the entry
+// expires at that unix time.
+func setEntry(txn *badger.Txn, k, v []byte, expiresAt uint64) error {
+	e := badger.NewEntry(k, v)
+	e.ExpiresAt = expiresAt
+	return txn.SetEntry(e)
+}
+
 // deleteDBID deletes the id-to-key mapping and the key-to-id mapping for the
 // DBID.
 func (db *DB) deleteDBID(txn *badger.Txn, dbID DBID) error {
@@ -0,0 +1,122 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package lexi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+)
+
+// TableUpgrade upgrades a Table's data from one schema version to the next.
+// The upgrade runs in a database transaction that is shared by all calls to
+// Update, Set, and Delete until the upgrade returns. Iterating the table
+// WithUpdate also uses the shared transaction. Other reads don't see the
+// upgrade's own writes.
+type TableUpgrade func(t *Table) error
+
+func tableVersionKey(name string) []byte {
+	return PrefixedKey(tableVersionPrefix, []byte(name))
+}
+
+// Version is the Table's schema version. The version of a Table that has never
+// been migrated is 0.
+func (t *Table) Version() (version uint32, err error) {
+	return version, t.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(tableVersionKey(t.name))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return nil
+			}
+			return err
+		}
+		return item.Value(func(b []byte) error {
+			if len(b) != 4 {
+				return fmt.Errorf("invalid table version length %d", len(b))
+			}
+			version = binary.BigEndian.Uint32(b)
+			return nil
+		})
+	})
+}
+
+// Migrate brings the Table's schema up to date. upgrades[i] upgrades the Table
+// from version i to version i+1, so the current version is len(upgrades).
+// Upgrades that have already been applied are skipped. Each upgrade runs in
+// its own transaction along with the version update, so a failed upgrade
+// leaves the Table at the last good version. Migrate should be called once when
+// the database is opened, after the Table's indexes are added and before the
+// Table is used by any other goroutines.
+func (t *Table) Migrate(upgrades ...TableUpgrade) error {
+	version, err := t.Version()
+	if err != nil {
+		return fmt.Errorf("error getting %s table version: %w", t.name, err)
+	}
+	if int(version) > len(upgrades) {
+		return fmt.Errorf("%s table version %d is newer than the latest known version %d", t.name, version, len(upgrades))
+	}
+	for v := int(version); v < len(upgrades); v++ {
+		if err := t.Upgrade(func() error {
+			if err := upgrades[v](t); err != nil {
+				return err
+			}
+			return t.Update(func(txn *badger.Txn) error {
+				b := make([]byte, 4)
+				binary.BigEndian.PutUint32(b, uint32(v+1))
+				return txn.Set(tableVersionKey(t.name), b)
+			})
+		}); err != nil {
+			return fmt.Errorf("error upgrading %s table to version %d: %w", t.name, v+1, err)
+		}
+		t.log.Infof("Upgraded %s table to version %d", t.name, v+1)
+	}
+	return nil
+}
+
+// TTLUpgrade is a TableUpgrade that sets the TTLs of entries that were stored
+// without one. decode parses a stored value, returning the value to store again
+// and the time at which the entry expires. Entries that have already expired
+// are deleted.
+func TTLUpgrade(decode func(vB []byte) (KV, time.Time, error)) TableUpgrade {
+	return func(t *Table) error {
+		type entry struct {
+			k          []byte
+			v          KV
+			expiration time.Time
+		}
+		var entries []*entry
+		if err := t.Iterate(nil, func(it *Iter) error {
+			k, err := it.K()
+			if err != nil {
+				return fmt.Errorf("error reading key: %w", err)
+			}
+			return it.V(func(vB []byte) error {
+				v, expiration, err := decode(vB)
+				if err != nil {
+					return fmt.Errorf("error decoding value: %w", err)
+				}
+				entries = append(entries, &entry{k: k, v: v, expiration: expiration})
+				return nil
+			})
+		}); err != nil {
+			return err
+		}
+		for _, e := range entries {
+			ttl := time.Until(e.expiration)
+			if ttl <= 0 {
+				if err := t.Delete(e.k); err != nil {
+					return fmt.Errorf("error deleting expired entry: %w", err)
+				}
+				continue
+			}
+			if err := t.Set(e.k, e.v, WithReplace(), WithTTL(ttl)); err != nil {
+				return fmt.Errorf("error setting entry TTL: %w", err)
+			}
+		}
+		return nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/dgraph-io/badger/v4"
 )
@@ -104,9 +105,16 @@ func (t *Table) Get(k KV, thing encoding.BinaryUnmarshaler, options ...GetOption
 // }
 
 func (t *Table) get(txn *badger.Txn, dbID DBID) (d *datum, err error) {
+	d, _, err = t.getWithExpiry(txn, dbID)
+	return
+}
+
+// getWithExpiry is like get, but also returns the unix time at which the entry
+// expires, or zero if it doesn't.
+func (t *Table) getWithExpiry(txn *badger.Txn, dbID DBID) (d *datum, expiresAt uint64, err error) {
 	item, err := txn.Get(PrefixedKey(t.prefix, dbID[:]))
 	if err != nil {
-		return nil, convertError(err)
+		return nil, 0, convertError(err)
 	}
 	err = item.Value(func(dB []byte) error {
 		d, err = decodeDatum(dB)
@@ -115,12 +123,13 @@ func (t *Table) get(txn *badger.Txn, dbID DBID) (d *datum, err error) {
 		}
 		return nil
 	})
-	return
+	return d, item.ExpiresAt(), err
 }
 
 type setOpts struct {
 	replace bool
 	txn     *badger.Txn
+	ttl     time.Duration
 }
 
 // SetOptions is an knob to control how items are inserted into the table with
@@ -141,6 +150,15 @@ func WithTxn(txn *badger.Txn) SetOption {
 	}
 }
 
+// WithTTL sets a time-to-live for the entry. When the TTL expires, the entry,
+// its index entries, and its key mappings are removed from the database
+// together. Replacing the entry without a TTL makes it permanent again.
+func WithTTL(ttl time.Duration) SetOption {
+	return func(opts *setOpts) {
+		opts.ttl = ttl
+	}
+}
+
 // UseDefaultSetOptions sets default options for Set.
 func (t *Table) UseDefaultSetOptions(setOpts ...SetOption) {
 	for i := range setOpts {
@@ -168,6 +186,10 @@ func (t *Table) Set(k, v KV, setOpts ...SetOption) error {
 		setOpts[i](&opts)
 	}
 	d := &datum{v: vB, indexes: make([][]byte, 0, len(t.indexes))}
+	var expiresAt uint64
+	if opts.ttl > 0 {
+		expiresAt = uint64(time.Now().Add(opts.ttl).Unix())
+	}
 
 	updateFunc := func(txn *badger.Txn) error {
 		dbID, err := t.keyID(txn, kB, false)
@@ -175,7 +197,7 @@ func (t *Table) Set(k, v KV, setOpts ...SetOption) error {
 			return convertError(err)
 		}
 		// See if an entry already exists
-		oldDatum, err := t.get(txn, dbID)
+		oldDatum, oldExpiresAt, err := t.getWithExpiry(txn, dbID)
 		if !errors.Is(err, ErrKeyNotFound) {
 			if err != nil {
 				return fmt.Errorf("error looking for existing entry: %w", err)
@@ -191,11 +213,17 @@ func (t *Table) Set(k, v KV, setOpts ...SetOption) error {
 				}
 			}
 		}
+		if expiresAt != 0 || oldExpiresAt != 0 {
+			// The key mappings must expire with the entry, or not at all.
+			if err := t.setKeyMappings(txn, kB, dbID, expiresAt); err != nil {
+				return err
+			}
+		}
 
 		// Add to indexes
 		for _, idx := range t.indexes {
 			var indexEntry []byte
-			if indexEntry, err = idx.add(txn, k, v, dbID); err != nil {
+			if indexEntry, err = idx.add(txn, k, v, dbID, expiresAt); err != nil {
 				// Handle unique index conflicts
 				var indexConflictError uniqueIndexConflictError
 				switch {
@@ -211,7 +239,7 @@ func (t *Table) Set(k, v KV, setOpts ...SetOption) error {
 					}
 
 					// Try again
-					indexEntry, err = idx.add(txn, k, v, dbID)
+					indexEntry, err = idx.add(txn, k, v, dbID, expiresAt)
 					if err != nil {
 						return fmt.Errorf("error adding entry to index after deleting conflicting entry: %w", err)
 					}
@@ -230,7 +258,7 @@ func (t *Table) Set(k, v KV, setOpts ...SetOption) error {
 			return fmt.Errorf("error encoding datum: %w", err)
 		}
 
-		return txn.Set(PrefixedKey(t.prefix, dbID[:]), dB)
+		return setEntry(txn, PrefixedKey(t.prefix, dbID[:]), dB, expiresAt)
 	}
 
 	if opts.txn != nil {
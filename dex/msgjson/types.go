@@ -107,6 +107,14 @@ const (
 	RPCTestContractGasError              // 87
 	RPCReconfigureWalletError            // 88
 	UnknownOrderError                    // 89
+	RPCWebhookError                      // 90
+	RPCPriceAlertError                   // 91
+	RPCListCoinsError                    // 92
+	RPCLabelError                        // 93
+	RPCAPITokenError                     // 94
+	RPCPermissionDenied                  // 95
+	RPCExportTradesError                 // 96
+	RPCRefundKitError                    // 97
 )
 
 // Routes are destinations for a "payload" of data. The type of data being
@@ -21,3 +21,14 @@ func parseABI(abiStr string) *abi.ABI {
 
 var ERC20ABI = parseABI(IERC20MetaData.ABI)
 var ERC20SwapABIV0 = parseABI(v0.ERC20SwapMetaData.ABI)
+
+// erc20MetadataABI is the optional metadata extension of the ERC20 standard.
+// These methods are not in IERC20, but nearly every token implements them.
+const erc20MetadataABI = `[
+	{"inputs":[],"name":"name","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"symbol","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint8","name":"","type":"uint8"}],"stateMutability":"view","type":"function"}
+]`
+
+// ERC20MetadataABI is the ABI of the ERC20 name, symbol, and decimals methods.
+var ERC20MetadataABI = parseABI(erc20MetadataABI)
@@ -0,0 +1,210 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/networks/eth/contracts/bond"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// BondVersion is the version of the bond contract and of the bond
+	// transactions that are created with it.
+	BondVersion = 0
+
+	CreateBondMethodName = "createBond"
+	RefundBondMethodName = "refundBond"
+)
+
+var (
+	// BondContractAddresses are the addresses of the ETHBond contract. There
+	// is no deployment on mainnet or testnet yet. The simnet address is read
+	// from the harness files by MaybeReadSimnetAddrs.
+	BondContractAddresses = map[dex.Network]common.Address{}
+
+	// BondABI is the parsed ABI of the ETHBond contract.
+	BondABI = initBondABI()
+)
+
+func initBondABI() *abi.ABI {
+	bondABI, err := abi.JSON(strings.NewReader(bond.ETHBondABI))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse bond abi: %v", err))
+	}
+	return &bondABI
+}
+
+// BondGases are the gas limits for bond contract transactions.
+type BondGases struct {
+	Create uint64 `json:"create"`
+	Refund uint64 `json:"refund"`
+}
+
+var (
+	// ETHBondGases are the gas limits for ETH bonds.
+	ETHBondGases = &BondGases{
+		Create: 180_000, // 137,648 actual on simulated backend
+		Refund: 61_000,  // 46,420 actual on simulated backend
+	}
+	// TokenBondGases are the gas limits for token bonds, not including the
+	// token approval.
+	TokenBondGases = &BondGases{
+		Create: 250_000, // 192,597 actual on simulated backend
+		Refund: 73_000,  // 55,749 actual on simulated backend
+	}
+)
+
+// Bond is the set of parameters that define a bond. The bond ID is computed
+// from the parameters.
+type Bond struct {
+	AcctID [32]byte
+	Owner  common.Address
+	// Token is the zero address for ETH bonds.
+	Token common.Address
+	// Value is in wei or in the token's EVM units.
+	Value    *big.Int
+	LockTime uint64
+}
+
+// ID computes the bond ID, which matches the contract's bondID method.
+func (b *Bond) ID() [32]byte {
+	return BondID(b.AcctID, b.Owner, b.Token, b.Value, b.LockTime)
+}
+
+// BondID computes the ID of the bond with the specified parameters. This is
+// keccak256(abi.encode(acctID, owner, token, value, lockTime)).
+func BondID(acctID [32]byte, owner, token common.Address, value *big.Int, lockTime uint64) [32]byte {
+	b := make([]byte, 0, 32*5)
+	b = append(b, acctID[:]...)
+	b = append(b, common.LeftPadBytes(owner[:], 32)...)
+	b = append(b, common.LeftPadBytes(token[:], 32)...)
+	b = append(b, common.LeftPadBytes(value.Bytes(), 32)...)
+	b = append(b, common.LeftPadBytes(new(big.Int).SetUint64(lockTime).Bytes(), 32)...)
+	return crypto.Keccak256Hash(b)
+}
+
+// BondRecord is an active bond in the bond contract.
+type BondRecord struct {
+	Bond
+	// BlockNumber is the block in which the bond was created.
+	BlockNumber uint64
+}
+
+// ReadBond reads the bond with the ID from the bond contract. A nil
+// BondRecord is returned if the bond does not exist or has been refunded.
+func ReadBond(ctx context.Context, caller bind.ContractCaller, contractAddr common.Address, id [32]byte) (*BondRecord, error) {
+	c, err := bond.NewETHBondCaller(contractAddr, caller)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.Bonds(&bind.CallOpts{Context: ctx}, id)
+	if err != nil {
+		return nil, err
+	}
+	if b.Owner == (common.Address{}) {
+		return nil, nil
+	}
+	if !b.LockTime.IsUint64() || !b.BlockNumber.IsUint64() {
+		return nil, errors.New("bond lock time or block number out of range")
+	}
+	return &BondRecord{
+		Bond: Bond{
+			AcctID:   b.AcctID,
+			Owner:    b.Owner,
+			Token:    b.Token,
+			Value:    b.Value,
+			LockTime: b.LockTime.Uint64(),
+		},
+		BlockNumber: b.BlockNumber.Uint64(),
+	}, nil
+}
+
+// ParseCreateBondData parses the calldata used to call the createBond method
+// of the bond contract. The returned Bond does not have an owner, which is the
+// sender of the transaction.
+func ParseCreateBondData(calldata []byte) (*Bond, error) {
+	decoded, err := ParseCallData(calldata, BondABI)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse call data: %v", err)
+	}
+	if decoded.Name != CreateBondMethodName {
+		return nil, fmt.Errorf("expected %v function but got %v", CreateBondMethodName, decoded.Name)
+	}
+	args := decoded.inputs
+	const numArgs = 4
+	if len(args) != numArgs {
+		return nil, fmt.Errorf("expected %v input args but got %v", numArgs, len(args))
+	}
+	acctID, ok := args[0].value.([32]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected first arg of type [32]byte but got %T", args[0].value)
+	}
+	token, ok := args[1].value.(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("expected second arg of type common.Address but got %T", args[1].value)
+	}
+	value, ok := args[2].value.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("expected third arg of type *big.Int but got %T", args[2].value)
+	}
+	lockTime, ok := args[3].value.(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("expected fourth arg of type *big.Int but got %T", args[3].value)
+	}
+	if !lockTime.IsUint64() {
+		return nil, errors.New("lock time out of range")
+	}
+	return &Bond{
+		AcctID:   acctID,
+		Token:    token,
+		Value:    value,
+		LockTime: lockTime.Uint64(),
+	}, nil
+}
+
+// ParseRefundBondData parses the calldata used to call the refundBond method
+// of the bond contract, returning the bond ID.
+func ParseRefundBondData(calldata []byte) ([32]byte, error) {
+	var id [32]byte
+	decoded, err := ParseCallData(calldata, BondABI)
+	if err != nil {
+		return id, fmt.Errorf("unable to parse call data: %v", err)
+	}
+	if decoded.Name != RefundBondMethodName {
+		return id, fmt.Errorf("expected %v function but got %v", RefundBondMethodName, decoded.Name)
+	}
+	if len(decoded.inputs) != 1 {
+		return id, fmt.Errorf("expected 1 input arg but got %v", len(decoded.inputs))
+	}
+	id, ok := decoded.inputs[0].value.([32]byte)
+	if !ok {
+		return id, fmt.Errorf("expected first arg of type [32]byte but got %T", decoded.inputs[0].value)
+	}
+	return id, nil
+}
+
+// maybeReadSimnetBondAddr reads the bond contract address from the eth
+// harness files.
+func maybeReadSimnetBondAddr() {
+	usr, err := user.Current()
+	if err != nil {
+		return
+	}
+	addrFile := filepath.Join(usr.HomeDir, "dextest", "eth", "bond_contract_address.txt")
+	if addr := maybeGetContractAddrFromFile(addrFile); addr != (common.Address{}) {
+		BondContractAddresses[dex.Simnet] = addr
+	}
+}
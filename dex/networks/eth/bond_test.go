@@ -0,0 +1,99 @@
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBondID(t *testing.T) {
+	var acctID [32]byte
+	copy(acctID[:], mustParseHex("ebdc4c31b88d0c8f4d644591a8e00e92b607f920ad8050deb7c7469767d9c561"))
+	owner := common.HexToAddress("0x345853e21b1d475582E71cC269124eD5e2dD3422")
+	token := common.HexToAddress("0x2B9E3d7c5cCeA0d2E9E6A3D1b1A3c5F2B4B1e7a9")
+	value := new(big.Int).Mul(big.NewInt(3), big.NewInt(GweiFactor))
+	const lockTime = 1_700_000_000
+
+	bytes32Type, _ := abi.NewType("bytes32", "", nil)
+	addrType, _ := abi.NewType("address", "", nil)
+	uint256Type, _ := abi.NewType("uint256", "", nil)
+	args := abi.Arguments{{Type: bytes32Type}, {Type: addrType}, {Type: addrType}, {Type: uint256Type}, {Type: uint256Type}}
+	encoded, err := args.Pack(acctID, owner, token, value, new(big.Int).SetUint64(lockTime))
+	if err != nil {
+		t.Fatalf("Pack error: %v", err)
+	}
+	want := crypto.Keccak256Hash(encoded)
+
+	b := &Bond{AcctID: acctID, Owner: owner, Token: token, Value: value, LockTime: lockTime}
+	if id := b.ID(); id != want {
+		t.Fatalf("wrong bond ID. wanted %x, got %x", want, id)
+	}
+	b.Token = common.Address{}
+	if b.ID() == want {
+		t.Fatalf("bond ID doesn't commit to the token")
+	}
+}
+
+func TestParseCreateBondData(t *testing.T) {
+	var acctID [32]byte
+	copy(acctID[:], mustParseHex("ebdc4c31b88d0c8f4d644591a8e00e92b607f920ad8050deb7c7469767d9c561"))
+	token := common.HexToAddress("0x2B9E3d7c5cCeA0d2E9E6A3D1b1A3c5F2B4B1e7a9")
+	value := big.NewInt(5e9)
+	const lockTime = 1_700_000_000
+
+	calldata, err := BondABI.Pack(CreateBondMethodName, acctID, token, value, new(big.Int).SetUint64(lockTime))
+	if err != nil {
+		t.Fatalf("failed to pack abi: %v", err)
+	}
+	refundCalldata, err := BondABI.Pack(RefundBondMethodName, acctID)
+	if err != nil {
+		t.Fatalf("failed to pack abi: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		calldata []byte
+		wantErr  bool
+	}{{
+		name:     "ok",
+		calldata: calldata,
+	}, {
+		name:     "unable to parse call data",
+		calldata: calldata[1:],
+		wantErr:  true,
+	}, {
+		name:     "wrong function name",
+		calldata: refundCalldata,
+		wantErr:  true,
+	}}
+
+	for _, test := range tests {
+		b, err := ParseCreateBondData(test.calldata)
+		if test.wantErr {
+			if err == nil {
+				t.Fatalf("expected error for test %q", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unexpected error for test %q: %v", test.name, err)
+		}
+		if b.AcctID != acctID || b.Token != token || b.Value.Cmp(value) != 0 || b.LockTime != lockTime {
+			t.Fatalf("wrong bond parsed for test %q: %+v", test.name, b)
+		}
+	}
+
+	id, err := ParseRefundBondData(refundCalldata)
+	if err != nil {
+		t.Fatalf("ParseRefundBondData error: %v", err)
+	}
+	if id != acctID {
+		t.Fatalf("wrong refunded bond ID %x", id)
+	}
+	if _, err := ParseRefundBondData(calldata); err == nil {
+		t.Fatalf("no error parsing createBond data as refundBond data")
+	}
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: BlueOak-1.0.0
+// pragma should be as specific as possible to allow easier validation.
+pragma solidity = 0.8.18;
+
+interface IERC20 {
+    function transfer(address to, uint256 value) external returns (bool);
+    function transferFrom(address from, address to, uint256 value) external returns (bool);
+    function balanceOf(address account) external view returns (uint256);
+}
+
+// ETHBond is a contract for time-locked fidelity bonds. A bond commits an
+// amount of ETH or an ERC20 token to a DEX account until the lock time, after
+// which only the owner can refund it. The bond ID commits to all of the bond's
+// parameters, so the DEX can locate the bond from the ID and verify that it has
+// not been refunded.
+contract ETHBond {
+    // Bond is the record of an active bond.
+    struct Bond {
+        // acctID is the DEX account ID that the bond commits to.
+        bytes32 acctID;
+        // owner is the account that created the bond and can refund it.
+        address owner;
+        // token is the ERC20 token contract address, or the zero address for
+        // ETH.
+        address token;
+        // value is the bond amount in wei or in the token's units.
+        uint256 value;
+        // lockTime is the unix time after which the bond can be refunded.
+        uint256 lockTime;
+        // blockNumber is the block in which the bond was created.
+        uint256 blockNumber;
+    }
+
+    // bonds are the active bonds, keyed by bond ID. A refunded bond is
+    // deleted.
+    mapping(bytes32 => Bond) public bonds;
+
+    event BondCreated(bytes32 indexed bondID, bytes32 indexed acctID, address indexed owner, address token, uint256 value, uint256 lockTime);
+    event BondRefunded(bytes32 indexed bondID, address indexed owner, address token, uint256 value);
+
+    // bondID computes the ID of the bond with the specified parameters.
+    function bondID(bytes32 acctID, address owner, address token, uint256 value, uint256 lockTime)
+        public pure returns (bytes32)
+    {
+        return keccak256(abi.encode(acctID, owner, token, value, lockTime));
+    }
+
+    // createBond creates a bond for the DEX account. For ETH bonds, token is
+    // the zero address and the transaction value must equal value. For token
+    // bonds, the contract must be approved to transfer value tokens from the
+    // sender, and the contract must receive the full value. Tokens that charge
+    // a fee on transfer are not supported, since the bond could not be
+    // refunded in full.
+    function createBond(bytes32 acctID, address token, uint256 value, uint256 lockTime)
+        external payable returns (bytes32 id)
+    {
+        require(value > 0, "zero value");
+        require(lockTime > block.timestamp, "expired lock time");
+
+        id = bondID(acctID, msg.sender, token, value, lockTime);
+        require(bonds[id].owner == address(0), "bond exists");
+        bonds[id] = Bond(acctID, msg.sender, token, value, lockTime, block.number);
+
+        if (token == address(0)) {
+            require(msg.value == value, "bad value");
+        } else {
+            require(msg.value == 0, "bad value");
+            require(token.code.length > 0, "not a contract");
+            uint256 before = balanceOf(token);
+            safeCall(token, abi.encodeCall(IERC20.transferFrom, (msg.sender, address(this), value)));
+            require(balanceOf(token) - before == value, "transfer fee not supported");
+        }
+
+        emit BondCreated(id, acctID, msg.sender, token, value, lockTime);
+    }
+
+    // refundBond refunds the bond to its owner after the lock time.
+    function refundBond(bytes32 id) external {
+        Bond memory b = bonds[id];
+        require(b.owner == msg.sender, "not owner");
+        require(block.timestamp >= b.lockTime, "locked");
+
+        delete bonds[id];
+
+        if (b.token == address(0)) {
+            (bool ok, ) = payable(msg.sender).call{value: b.value}("");
+            require(ok, "transfer failed");
+        } else {
+            safeCall(b.token, abi.encodeCall(IERC20.transfer, (msg.sender, b.value)));
+        }
+
+        emit BondRefunded(id, msg.sender, b.token, b.value);
+    }
+
+    // balanceOf is the contract's balance of the token.
+    function balanceOf(address token) private view returns (uint256) {
+        (bool ok, bytes memory ret) = token.staticcall(abi.encodeCall(IERC20.balanceOf, (address(this))));
+        require(ok && ret.length >= 32, "balance check failed");
+        return abi.decode(ret, (uint256));
+    }
+
+    // safeCall calls a token transfer method, allowing for tokens that don't
+    // return a value.
+    function safeCall(address token, bytes memory data) private {
+        require(token.code.length > 0, "not a contract");
+        (bool ok, bytes memory ret) = token.call(data);
+        require(ok && (ret.length == 0 || abi.decode(ret, (bool))), "token transfer failed");
+    }
+}
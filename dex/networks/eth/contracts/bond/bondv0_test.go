@@ -0,0 +1,443 @@
+//go:build harness
+
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// These tests require that the eth simnet harness (dex/testing/eth/harness.sh)
+// be running and the unix socket be located at
+// $HOME/dextest/eth/alpha/node/geth.ipc. The harness deploys the bond contract
+// and the test USDC token.
+
+package bond_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/networks/erc20"
+	"decred.org/dcrdex/dex/networks/eth/contracts/bond"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	homeDir       = os.Getenv("HOME")
+	alphaIPCFile  = filepath.Join(homeDir, "dextest", "eth", "alpha", "node", "geth.ipc")
+	harnessCtlDir = filepath.Join(homeDir, "dextest", "eth", "harness-ctl")
+	bondAddrFile  = filepath.Join(homeDir, "dextest", "eth", "bond_contract_address.txt")
+	tokenAddrFile = filepath.Join(homeDir, "dextest", "eth", "test_usdc_contract_address.txt")
+
+	ethValue  = big.NewInt(1e18)
+	tokenBond = big.NewInt(1e6) // 1 USDC
+
+	// The harness blocks are mined every 10 seconds, so the lock times are
+	// short to keep the tests quick.
+	lockDuration = 15 * time.Second
+)
+
+type tHarness struct {
+	ctx      context.Context
+	client   *ethclient.Client
+	chainID  *big.Int
+	keys     []*ecdsa.PrivateKey
+	addrs    []common.Address
+	bond     *bond.ETHBond
+	bondABI  *abi.ABI
+	bondAddr common.Address
+	token    common.Address
+}
+
+var h *tHarness
+
+func TestMain(m *testing.M) {
+	// Run in function so that defers happen before os.Exit is called.
+	run := func() (int, error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var err error
+		if h, err = newHarness(ctx); err != nil {
+			return 1, err
+		}
+		defer h.client.Close()
+		return m.Run(), nil
+	}
+	exitCode, err := run()
+	if err != nil {
+		fmt.Println(err)
+	}
+	os.Exit(exitCode)
+}
+
+func newHarness(ctx context.Context) (*tHarness, error) {
+	client, err := ethclient.DialContext(ctx, alphaIPCFile)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s: %w", alphaIPCFile, err)
+	}
+	h := &tHarness{ctx: ctx, client: client}
+	if h.chainID, err = client.ChainID(ctx); err != nil {
+		return nil, fmt.Errorf("error getting chain ID: %w", err)
+	}
+	if h.bondAddr, err = contractAddrFromFile(bondAddrFile); err != nil {
+		return nil, err
+	}
+	if h.token, err = contractAddrFromFile(tokenAddrFile); err != nil {
+		return nil, err
+	}
+	if h.bondABI, err = bond.ETHBondMetaData.GetAbi(); err != nil {
+		return nil, err
+	}
+	if h.bond, err = bond.NewETHBond(h.bondAddr, client); err != nil {
+		return nil, err
+	}
+
+	// Fund new accounts so that the balances are known.
+	for i := 0; i < 2; i++ {
+		k, err := crypto.GenerateKey()
+		if err != nil {
+			return nil, err
+		}
+		addr := crypto.PubkeyToAddress(k.PublicKey)
+		h.keys = append(h.keys, k)
+		h.addrs = append(h.addrs, addr)
+		for _, exe := range []string{"./sendtoaddress", "./sendUSDC"} {
+			cmd := exec.CommandContext(ctx, exe, addr.String(), "10")
+			cmd.Dir = harnessCtlDir
+			if out, err := cmd.CombinedOutput(); err != nil {
+				return nil, fmt.Errorf("error running %q: %v: %s", cmd, err, out)
+			}
+		}
+	}
+	for _, addr := range h.addrs {
+		if err := h.waitFor(func() (bool, error) {
+			bal, err := h.tokenBalance(addr)
+			return err == nil && bal.Sign() > 0, err
+		}); err != nil {
+			return nil, fmt.Errorf("error funding %s: %w", addr, err)
+		}
+	}
+	return h, nil
+}
+
+func contractAddrFromFile(fileName string) (common.Address, error) {
+	b, err := os.ReadFile(fileName)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("error reading contract address: %w", err)
+	}
+	addrStr := strings.TrimSpace(string(b))
+	if !common.IsHexAddress(addrStr) {
+		return common.Address{}, fmt.Errorf("bad contract address %q in %s", addrStr, fileName)
+	}
+	return common.HexToAddress(addrStr), nil
+}
+
+// waitFor polls until the check passes, waiting for at most a few blocks.
+func (h *tHarness) waitFor(check func() (bool, error)) error {
+	for i := 0; i < 60; i++ {
+		if ok, err := check(); err != nil {
+			return err
+		} else if ok {
+			return nil
+		}
+		select {
+		case <-time.After(time.Second):
+		case <-h.ctx.Done():
+			return h.ctx.Err()
+		}
+	}
+	return fmt.Errorf("timed out")
+}
+
+func (h *tHarness) txOpts(t *testing.T, i int, value *big.Int) *bind.TransactOpts {
+	t.Helper()
+	opts, err := bind.NewKeyedTransactorWithChainID(h.keys[i], h.chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Context = h.ctx
+	opts.Value = value
+	return opts
+}
+
+func (h *tHarness) mustSucceed(t *testing.T, tx *types.Transaction) *types.Receipt {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(h.ctx, time.Minute)
+	defer cancel()
+	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	if err != nil {
+		t.Fatalf("error waiting for %s: %v", tx.Hash(), err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		t.Fatalf("transaction %s failed", tx.Hash())
+	}
+	return receipt
+}
+
+// mustRevert checks that the transaction would revert with the reason.
+// Transactions that would revert fail gas estimation, so they are never sent.
+func mustRevert(t *testing.T, reason string) func(*types.Transaction, error) {
+	return func(_ *types.Transaction, err error) {
+		t.Helper()
+		if err == nil {
+			t.Fatalf("no error for reverting transaction. expected %q", reason)
+		}
+		if !strings.Contains(err.Error(), reason) {
+			t.Fatalf("wrong error %q. expected %q", err, reason)
+		}
+	}
+}
+
+func (h *tHarness) lockTime(t *testing.T, d time.Duration) *big.Int {
+	t.Helper()
+	header, err := h.client.HeaderByNumber(h.ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return new(big.Int).SetUint64(uint64(int64(header.Time) + int64(d/time.Second)))
+}
+
+// waitForLockTime waits for a block at or past the lock time.
+func (h *tHarness) waitForLockTime(t *testing.T, lockTime *big.Int) {
+	t.Helper()
+	if err := h.waitFor(func() (bool, error) {
+		header, err := h.client.HeaderByNumber(h.ctx, nil)
+		if err != nil {
+			return false, err
+		}
+		return header.Time >= lockTime.Uint64(), nil
+	}); err != nil {
+		t.Fatalf("error waiting for lock time: %v", err)
+	}
+}
+
+func (h *tHarness) ethBalance(t *testing.T, addr common.Address) *big.Int {
+	t.Helper()
+	bal, err := h.client.BalanceAt(h.ctx, addr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bal
+}
+
+func (h *tHarness) tokenBalance(addr common.Address) (*big.Int, error) {
+	token, err := erc20.NewIERC20(h.token, h.client)
+	if err != nil {
+		return nil, err
+	}
+	return token.BalanceOf(&bind.CallOpts{Context: h.ctx}, addr)
+}
+
+func (h *tHarness) mustTokenBalance(t *testing.T, addr common.Address) *big.Int {
+	t.Helper()
+	bal, err := h.tokenBalance(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bal
+}
+
+func (h *tHarness) bondID(t *testing.T, acctID [32]byte, owner, token common.Address, value, lockTime *big.Int) [32]byte {
+	t.Helper()
+	// The bond ID is the hash of the ABI-encoded parameters.
+	args := h.bondABI.Methods["bondID"].Inputs
+	b, err := args.Pack(acctID, owner, token, value, lockTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := crypto.Keccak256Hash(b)
+	contractID, err := h.bond.BondID(&bind.CallOpts{Context: h.ctx}, acctID, owner, token, value, lockTime)
+	if err != nil {
+		t.Fatalf("bondID error: %v", err)
+	}
+	if contractID != id {
+		t.Fatalf("contract bond ID %x != %x", contractID, id)
+	}
+	return id
+}
+
+func TestETHBond(t *testing.T) {
+	owner := h.addrs[0]
+	acctID := [32]byte{0x01}
+	lockTime := h.lockTime(t, lockDuration)
+	id := h.bondID(t, acctID, owner, common.Address{}, ethValue, lockTime)
+
+	// Bad values.
+	mustRevert(t, "bad value")(h.bond.CreateBond(h.txOpts(t, 0, nil), acctID, common.Address{}, ethValue, lockTime))
+	mustRevert(t, "bad value")(h.bond.CreateBond(h.txOpts(t, 0, big.NewInt(1)), acctID, common.Address{}, ethValue, lockTime))
+	mustRevert(t, "zero value")(h.bond.CreateBond(h.txOpts(t, 0, nil), acctID, common.Address{}, new(big.Int), lockTime))
+	mustRevert(t, "expired lock time")(h.bond.CreateBond(h.txOpts(t, 0, ethValue), acctID, common.Address{}, ethValue, h.lockTime(t, -time.Second)))
+
+	contractBal := h.ethBalance(t, h.bondAddr)
+	tx, err := h.bond.CreateBond(h.txOpts(t, 0, ethValue), acctID, common.Address{}, ethValue, lockTime)
+	if err != nil {
+		t.Fatalf("createBond error: %v", err)
+	}
+	receipt := h.mustSucceed(t, tx)
+	t.Logf("ETH createBond gas: %d", receipt.GasUsed)
+
+	if len(receipt.Logs) != 1 {
+		t.Fatalf("expected 1 log, got %d", len(receipt.Logs))
+	}
+	created, err := h.bond.ParseBondCreated(*receipt.Logs[0])
+	if err != nil {
+		t.Fatalf("error parsing BondCreated: %v", err)
+	}
+	if created.BondID != id || created.AcctID != acctID || created.Owner != owner ||
+		created.Token != (common.Address{}) || created.Value.Cmp(ethValue) != 0 || created.LockTime.Cmp(lockTime) != 0 {
+		t.Fatalf("wrong BondCreated event %+v", created)
+	}
+
+	b, err := h.bond.Bonds(&bind.CallOpts{Context: h.ctx}, id)
+	if err != nil {
+		t.Fatalf("bonds error: %v", err)
+	}
+	if b.AcctID != acctID || b.Owner != owner || b.Token != (common.Address{}) || b.Value.Cmp(ethValue) != 0 ||
+		b.LockTime.Cmp(lockTime) != 0 || b.BlockNumber.Cmp(receipt.BlockNumber) != 0 {
+		t.Fatalf("wrong bond %+v", b)
+	}
+	expBal := new(big.Int).Add(contractBal, ethValue)
+	if bal := h.ethBalance(t, h.bondAddr); bal.Cmp(expBal) != 0 {
+		t.Fatalf("wrong contract balance. expected %s, got %s", expBal, bal)
+	}
+
+	// Same bond again.
+	mustRevert(t, "bond exists")(h.bond.CreateBond(h.txOpts(t, 0, ethValue), acctID, common.Address{}, ethValue, lockTime))
+	// Still locked.
+	mustRevert(t, "locked")(h.bond.RefundBond(h.txOpts(t, 0, nil), id))
+
+	h.waitForLockTime(t, lockTime)
+
+	// Not the owner.
+	mustRevert(t, "not owner")(h.bond.RefundBond(h.txOpts(t, 1, nil), id))
+
+	balBefore := h.ethBalance(t, owner)
+	if tx, err = h.bond.RefundBond(h.txOpts(t, 0, nil), id); err != nil {
+		t.Fatalf("refundBond error: %v", err)
+	}
+	receipt = h.mustSucceed(t, tx)
+	t.Logf("ETH refundBond gas: %d", receipt.GasUsed)
+	fees := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+	expBal = new(big.Int).Sub(new(big.Int).Add(balBefore, ethValue), fees)
+	if bal := h.ethBalance(t, owner); bal.Cmp(expBal) != 0 {
+		t.Fatalf("wrong balance after refund. expected %s, got %s", expBal, bal)
+	}
+	refunded, err := h.bond.ParseBondRefunded(*receipt.Logs[0])
+	if err != nil {
+		t.Fatalf("error parsing BondRefunded: %v", err)
+	}
+	if refunded.BondID != id || refunded.Owner != owner || refunded.Value.Cmp(ethValue) != 0 {
+		t.Fatalf("wrong BondRefunded event %+v", refunded)
+	}
+	if b, err = h.bond.Bonds(&bind.CallOpts{Context: h.ctx}, id); err != nil {
+		t.Fatalf("bonds error: %v", err)
+	}
+	if b.Owner != (common.Address{}) {
+		t.Fatal("bond not deleted")
+	}
+	// Can't refund twice.
+	mustRevert(t, "not owner")(h.bond.RefundBond(h.txOpts(t, 0, nil), id))
+}
+
+func TestTokenBond(t *testing.T) {
+	owner := h.addrs[0]
+	acctID := [32]byte{0x02}
+	lockTime := h.lockTime(t, lockDuration)
+	id := h.bondID(t, acctID, owner, h.token, tokenBond, lockTime)
+
+	// Not approved.
+	mustRevert(t, "token transfer failed")(h.bond.CreateBond(h.txOpts(t, 0, nil), acctID, h.token, tokenBond, lockTime))
+
+	token, err := erc20.NewIERC20(h.token, h.client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := token.Approve(h.txOpts(t, 0, nil), h.bondAddr, tokenBond)
+	if err != nil {
+		t.Fatalf("approve error: %v", err)
+	}
+	h.mustSucceed(t, tx)
+
+	// No ETH with token bonds.
+	mustRevert(t, "bad value")(h.bond.CreateBond(h.txOpts(t, 0, big.NewInt(1)), acctID, h.token, tokenBond, lockTime))
+	// Tokens must be contracts.
+	mustRevert(t, "not a contract")(h.bond.CreateBond(h.txOpts(t, 0, nil), acctID, h.addrs[1], tokenBond, lockTime))
+
+	ownerBal := h.mustTokenBalance(t, owner)
+	contractBal := h.mustTokenBalance(t, h.bondAddr)
+	if tx, err = h.bond.CreateBond(h.txOpts(t, 0, nil), acctID, h.token, tokenBond, lockTime); err != nil {
+		t.Fatalf("createBond error: %v", err)
+	}
+	receipt := h.mustSucceed(t, tx)
+	t.Logf("token createBond gas: %d", receipt.GasUsed)
+
+	expBal := new(big.Int).Sub(ownerBal, tokenBond)
+	if bal := h.mustTokenBalance(t, owner); bal.Cmp(expBal) != 0 {
+		t.Fatalf("wrong owner token balance %s", bal)
+	}
+	expBal = new(big.Int).Add(contractBal, tokenBond)
+	if bal := h.mustTokenBalance(t, h.bondAddr); bal.Cmp(expBal) != 0 {
+		t.Fatalf("wrong contract token balance %s", bal)
+	}
+	b, err := h.bond.Bonds(&bind.CallOpts{Context: h.ctx}, id)
+	if err != nil {
+		t.Fatalf("bonds error: %v", err)
+	}
+	if b.Owner != owner || b.Token != h.token || b.Value.Cmp(tokenBond) != 0 {
+		t.Fatalf("wrong bond %+v", b)
+	}
+
+	mustRevert(t, "locked")(h.bond.RefundBond(h.txOpts(t, 0, nil), id))
+	h.waitForLockTime(t, lockTime)
+
+	if tx, err = h.bond.RefundBond(h.txOpts(t, 0, nil), id); err != nil {
+		t.Fatalf("refundBond error: %v", err)
+	}
+	receipt = h.mustSucceed(t, tx)
+	t.Logf("token refundBond gas: %d", receipt.GasUsed)
+	if bal := h.mustTokenBalance(t, owner); bal.Cmp(ownerBal) != 0 {
+		t.Fatalf("wrong owner token balance after refund %s", bal)
+	}
+	if bal := h.mustTokenBalance(t, h.bondAddr); bal.Cmp(contractBal) != 0 {
+		t.Fatalf("wrong contract token balance after refund %s", bal)
+	}
+}
+
+// TestFeeTokenBond checks that a bond is rejected if the contract does not
+// receive the full value, as with tokens that charge a fee on transfer.
+func TestFeeTokenBond(t *testing.T) {
+	// The stub token returns true for every call, including balanceOf, so a
+	// transferFrom "succeeds" without changing the contract's balance.
+	// Its init code just returns the runtime code 0x600160005260206000f3.
+	initCode := common.FromHex("69600160005260206000f3600052600a6016f3")
+	opts := h.txOpts(t, 1, nil)
+	nonce, err := h.client.PendingNonceAt(h.ctx, opts.From)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gasPrice, err := h.client.SuggestGasPrice(h.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := opts.Signer(opts.From, types.NewContractCreation(nonce, new(big.Int), 100_000, gasPrice, initCode))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.client.SendTransaction(h.ctx, tx); err != nil {
+		t.Fatalf("error deploying stub token: %v", err)
+	}
+	feeToken := h.mustSucceed(t, tx).ContractAddress
+
+	acctID := [32]byte{0x03}
+	mustRevert(t, "transfer fee not supported")(h.bond.CreateBond(h.txOpts(t, 1, nil), acctID, feeToken, tokenBond, h.lockTime(t, lockDuration)))
+}
@@ -0,0 +1,10 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+//
+// This package also imports go-ethereum code and so carries the burden of
+// go-ethereum's GNU Lesser General Public License.
+
+// Package bond contains pre-generated code for the fidelity bond contract that
+// should not be directly edited. See the parent package for details on how to
+// generate.
+package bond
@@ -0,0 +1,26 @@
+#!/usr/bin/env bash
+#
+# 1. Updates bondv0.go to reflect updated solidity code.
+# 2. Generates the bytecode used to deploy the contract on the simnet harness.
+
+PKG_NAME="bond"
+CONTRACT_NAME="ETHBondV0"
+TYPE_NAME="ETHBond"
+SOLIDITY_FILE="./${CONTRACT_NAME}.sol"
+if [ ! -f ${SOLIDITY_FILE} ]
+then
+    echo "${SOLIDITY_FILE} does not exist" >&2
+    exit 1
+fi
+
+mkdir temp
+
+solc --abi --bin --bin-runtime --overwrite --optimize ${SOLIDITY_FILE} -o ./temp/
+
+abigen --abi ./temp/${TYPE_NAME}.abi --bin ./temp/${TYPE_NAME}.bin --pkg ${PKG_NAME} \
+ --type ${TYPE_NAME} --out ./${PKG_NAME}/bondv0.go
+
+BYTECODE=$(<./temp/${TYPE_NAME}.bin)
+echo "${BYTECODE}" | xxd -r -p > "${PKG_NAME}/contract.bin"
+
+rm -fr temp
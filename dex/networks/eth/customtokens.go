@@ -0,0 +1,87 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"decred.org/dcrdex/dex"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// CustomTokenIDMin is the first asset ID in the range reserved for
+	// user-defined tokens. Custom token IDs are well above any BIP-0044 coin
+	// type or built-in token ID, and below math.MaxInt32, which is reserved.
+	CustomTokenIDMin = 1_000_000_000
+	// CustomTokenIDMax is the last asset ID in the range reserved for
+	// user-defined tokens.
+	CustomTokenIDMax = 1_999_999_999
+
+	// maxCustomTokenConventionalDecimals is the most decimals that a custom
+	// token's conventional unit can have. Tokens with more decimals use an
+	// EVMFactor, the same as the built-in tokens.
+	maxCustomTokenConventionalDecimals = 9
+)
+
+// CustomTokenID generates the asset ID for a user-defined token on the chain
+// with the specified parent asset ID. The ID is derived from the parent ID and
+// the token's contract address, so the same token always gets the same ID.
+func CustomTokenID(parentID uint32, tokenAddr common.Address) uint32 {
+	var b [4 + common.AddressLength]byte
+	binary.BigEndian.PutUint32(b[:4], parentID)
+	copy(b[4:], tokenAddr[:])
+	h := crypto.Keccak256(b[:])
+	const n = CustomTokenIDMax - CustomTokenIDMin + 1
+	return CustomTokenIDMin + binary.BigEndian.Uint32(h[:4])%n
+}
+
+// IsCustomTokenID is true if the asset ID is in the range reserved for
+// user-defined tokens.
+func IsCustomTokenID(assetID uint32) bool {
+	return assetID >= CustomTokenIDMin && assetID <= CustomTokenIDMax
+}
+
+// NewCustomToken creates the Token for a user-defined ERC20 token on the
+// specified network. The conventional unit has the token's decimals, up to 9.
+// Custom tokens can only be used with the version 1 swap contract, which
+// handles all ERC20 tokens identically.
+func NewCustomToken(parentID uint32, net dex.Network, tokenAddr common.Address, name, symbol string, decimals uint8) (*Token, error) {
+	if name == "" || symbol == "" {
+		return nil, fmt.Errorf("token %s has no name or symbol", tokenAddr)
+	}
+	convDecimals := int64(decimals)
+	if convDecimals > maxCustomTokenConventionalDecimals {
+		convDecimals = maxCustomTokenConventionalDecimals
+	}
+	evmFactor := int64(decimals) - convDecimals
+	return &Token{
+		EVMFactor: &evmFactor,
+		Token: &dex.Token{
+			ParentID: parentID,
+			Name:     name,
+			UnitInfo: dex.UnitInfo{
+				AtomicUnit: "atoms",
+				Conventional: dex.Denomination{
+					Unit:             symbol,
+					ConversionFactor: uint64(math.Pow10(int(convDecimals))),
+				},
+				FeeRateDenom: "gas",
+			},
+		},
+		NetTokens: map[dex.Network]*NetToken{
+			net: {
+				Address: tokenAddr,
+				SwapContracts: map[uint32]*SwapContract{
+					1: {
+						Gas: tokenV1Gases,
+					},
+				},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,80 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"math/big"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCustomToken(t *testing.T) {
+	addr := common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+	id := CustomTokenID(EthBipID, addr)
+	if !IsCustomTokenID(id) {
+		t.Fatalf("ID %d not in custom range", id)
+	}
+	if CustomTokenID(EthBipID, addr) != id {
+		t.Fatal("ID not deterministic")
+	}
+	if CustomTokenID(EthBipID+1, addr) == id {
+		t.Fatal("same ID for different chain")
+	}
+	for tokenID := range Tokens {
+		if IsCustomTokenID(tokenID) {
+			t.Fatalf("built-in token %d in custom range", tokenID)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		decimals   uint8
+		convFactor uint64
+		evmAmt     *big.Int
+		atoms      uint64
+	}{{
+		name:       "18 decimals",
+		decimals:   18,
+		convFactor: 1e9,
+		evmAmt:     new(big.Int).Mul(big.NewInt(15), big.NewInt(1e17)), // 1.5 tokens
+		atoms:      1.5e9,
+	}, {
+		name:       "6 decimals",
+		decimals:   6,
+		convFactor: 1e6,
+		evmAmt:     big.NewInt(1_500_000),
+		atoms:      1_500_000,
+	}, {
+		name:       "0 decimals",
+		decimals:   0,
+		convFactor: 1,
+		evmAmt:     big.NewInt(2),
+		atoms:      2,
+	}}
+	for _, tt := range tests {
+		tkn, err := NewCustomToken(EthBipID, dex.Mainnet, addr, "Fake", "FAKE", tt.decimals)
+		if err != nil {
+			t.Fatalf("%s: NewCustomToken error: %v", tt.name, err)
+		}
+		if f := tkn.UnitInfo.Conventional.ConversionFactor; f != tt.convFactor {
+			t.Fatalf("%s: wrong conversion factor %d", tt.name, f)
+		}
+		if atoms := tkn.EVMToAtomic(tt.evmAmt); atoms != tt.atoms {
+			t.Fatalf("%s: wrong atoms %d", tt.name, atoms)
+		}
+		if evmAmt := tkn.AtomicToEVM(tt.atoms); evmAmt.Cmp(tt.evmAmt) != 0 {
+			t.Fatalf("%s: wrong EVM amount %s", tt.name, evmAmt)
+		}
+		nt := tkn.NetTokens[dex.Mainnet]
+		if nt == nil || nt.Address != addr || nt.SwapContracts[1] == nil {
+			t.Fatalf("%s: wrong net token", tt.name)
+		}
+	}
+
+	if _, err := NewCustomToken(EthBipID, dex.Mainnet, addr, "", "FAKE", 18); err == nil {
+		t.Fatal("no error for missing name")
+	}
+}
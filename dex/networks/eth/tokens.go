@@ -432,9 +432,10 @@ var Tokens = map[uint32]*Token{
 
 // MaybeReadSimnetAddrs attempts to read the info files generated by the eth
 // simnet harness to populate swap contract and token addresses in
-// ContractAddresses and Tokens.
+// ContractAddresses, BondContractAddresses, and Tokens.
 func MaybeReadSimnetAddrs() {
 	MaybeReadSimnetAddrsDir("eth", ContractAddresses, MultiBalanceAddresses, Tokens[usdcTokenID].NetTokens[dex.Simnet], Tokens[usdtTokenID].NetTokens[dex.Simnet])
+	maybeReadSimnetBondAddr()
 }
 
 func MaybeReadSimnetAddrsDir(
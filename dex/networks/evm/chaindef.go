@@ -0,0 +1,300 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package evm defines generic EVM-compatible chains from a chain definition
+// file. The client and server instantiate the shared eth wallet and backend
+// for each definition, so that a new chain can be supported without a new
+// asset package.
+package evm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Gas models.
+const (
+	// GasModelLondon is a chain with EIP-1559 fees and no additional fees.
+	GasModelLondon = "london"
+	// GasModelOPStack is an OP Stack L2 with an additional L1 security fee
+	// for posting calldata to Ethereum.
+	GasModelOPStack = "opstack"
+)
+
+const (
+	defaultFinalizeConfs = 10
+	defaultGasFeeLimit   = 200 // gwei
+)
+
+// Compatibility is data used to check that an RPC provider has a compatible
+// API. The transaction, block, address, and token should all exist on the
+// network.
+type Compatibility struct {
+	Addr      common.Address `json:"addr"`
+	TokenAddr common.Address `json:"tokenAddr"`
+	TxHash    common.Hash    `json:"txHash"`
+	BlockHash common.Hash    `json:"blockHash"`
+}
+
+// AcrossBridge is the configuration of the Across bridge for a network.
+type AcrossBridge struct {
+	// SpokePool is the address of the Across spoke pool on the chain.
+	SpokePool common.Address `json:"spokePool"`
+	// WrappedNative is the address of the wrapped native asset, e.g. WETH.
+	WrappedNative common.Address `json:"wrappedNative"`
+	// NativeSymbol is the symbol that Across uses for the native asset,
+	// e.g. WETH for chains that use ETH as the native asset.
+	NativeSymbol string `json:"nativeSymbol"`
+}
+
+// Bridges are the bridges that are available on a network.
+type Bridges struct {
+	Across *AcrossBridge `json:"across,omitempty"`
+}
+
+// NetworkDef is the part of a chain definition that is specific to a network.
+type NetworkDef struct {
+	ChainID int64 `json:"chainID"`
+	// DefaultProviders are the RPC providers that a wallet uses if the user
+	// doesn't specify any.
+	DefaultProviders []string `json:"defaultProviders"`
+	// Contracts are the swap contract addresses by contract version.
+	Contracts map[uint32]common.Address `json:"contracts"`
+	// MultiBalance is the address of the MultiBalance contract, if deployed.
+	MultiBalance common.Address `json:"multiBalance"`
+	// Bond is the address of the ETHBond contract, if deployed. Fidelity
+	// bonds are only supported if the bond contract is deployed.
+	Bond common.Address `json:"bond"`
+	// Compatibility is required by the client.
+	Compatibility *Compatibility `json:"compatibility"`
+	Bridges       *Bridges       `json:"bridges,omitempty"`
+}
+
+// ChainDef is the definition of an EVM-compatible chain.
+type ChainDef struct {
+	// Name is the display name of the chain, e.g. Arbitrum.
+	Name string `json:"name"`
+	// Symbol is the ticker symbol of the chain's native asset. Symbol is only
+	// required if BipID is not already in the BIP ID list, and otherwise must
+	// match the listed symbol.
+	Symbol string `json:"symbol"`
+	BipID  uint32 `json:"bipID"`
+	// GasModel is either GasModelLondon (the default) or GasModelOPStack.
+	GasModel string `json:"gasModel"`
+	// FinalizeConfs is the number of confirmations after which a transaction
+	// is considered final. Default 10.
+	FinalizeConfs uint64 `json:"finalizeConfs"`
+	// MaxTxFeeGwei is the absolute maximum fee that the wallet will pay for a
+	// single transaction. Default 1 ETH.
+	MaxTxFeeGwei uint64 `json:"maxTxFeeGwei"`
+	// GasFeeLimit is the default gas fee limit for a wallet, in gwei / gas.
+	// Default 200.
+	GasFeeLimit uint64 `json:"gasFeeLimit"`
+	// UnitInfo defaults to the ETH units.
+	UnitInfo *dex.UnitInfo `json:"unitInfo,omitempty"`
+	// Gases are the swap contract gas costs by contract version. Versions that
+	// aren't specified use the Ethereum gas costs.
+	Gases map[uint32]*dexeth.Gases `json:"gases,omitempty"`
+	// Networks are the network definitions keyed by network name, i.e.
+	// mainnet, testnet, or simnet.
+	Networks map[string]*NetworkDef `json:"networks"`
+
+	nets map[dex.Network]*NetworkDef
+}
+
+// LoadChainDefs loads the chain definitions from the JSON file at path. The
+// file is a JSON array of chain definitions.
+func LoadChainDefs(path string) ([]*ChainDef, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseChainDefs(b)
+}
+
+// ParseChainDefs parses and validates a JSON array of chain definitions.
+func ParseChainDefs(b []byte) ([]*ChainDef, error) {
+	var defs []*ChainDef
+	if err := json.Unmarshal(b, &defs); err != nil {
+		return nil, fmt.Errorf("error parsing chain definitions: %w", err)
+	}
+	bipIDs := make(map[uint32]bool, len(defs))
+	for i, def := range defs {
+		if err := def.validate(); err != nil {
+			return nil, fmt.Errorf("chain definition %d (%s): %w", i, def.Name, err)
+		}
+		if bipIDs[def.BipID] {
+			return nil, fmt.Errorf("duplicate chain definition for BIP ID %d", def.BipID)
+		}
+		bipIDs[def.BipID] = true
+	}
+	return defs, nil
+}
+
+func (def *ChainDef) validate() error {
+	if def.Name == "" {
+		return errors.New("no name")
+	}
+	if def.BipID == 0 {
+		return errors.New("no BIP ID")
+	}
+	if dexeth.IsCustomTokenID(def.BipID) {
+		return fmt.Errorf("BIP ID %d is reserved for custom tokens", def.BipID)
+	}
+	switch def.GasModel {
+	case "":
+		def.GasModel = GasModelLondon
+	case GasModelLondon, GasModelOPStack:
+	default:
+		return fmt.Errorf("unknown gas model %q", def.GasModel)
+	}
+	if def.FinalizeConfs == 0 {
+		def.FinalizeConfs = defaultFinalizeConfs
+	}
+	if def.MaxTxFeeGwei == 0 {
+		def.MaxTxFeeGwei = dexeth.GweiFactor // 1 ETH
+	}
+	if def.GasFeeLimit == 0 {
+		def.GasFeeLimit = defaultGasFeeLimit
+	}
+	if def.UnitInfo != nil && def.UnitInfo.Conventional.ConversionFactor != dexeth.UnitInfo.Conventional.ConversionFactor {
+		// The shared wallet and backend use gwei as the atomic unit.
+		return errors.New("the conventional unit must be 1e9 atomic units")
+	}
+	if len(def.Networks) == 0 {
+		return errors.New("no networks")
+	}
+	def.nets = make(map[dex.Network]*NetworkDef, len(def.Networks))
+	for netName, nd := range def.Networks {
+		net, err := dex.NetFromString(netName)
+		if err != nil {
+			return err
+		}
+		if nd.ChainID <= 0 {
+			return fmt.Errorf("no chain ID for %s", net)
+		}
+		if len(nd.Contracts) == 0 {
+			return fmt.Errorf("no swap contracts for %s", net)
+		}
+		if _, found := nd.Contracts[1]; !found {
+			// Tokens and the server backend require the v1 contract.
+			return fmt.Errorf("no version 1 swap contract for %s", net)
+		}
+		for ver, addr := range nd.Contracts {
+			if addr == (common.Address{}) {
+				return fmt.Errorf("no version %d swap contract address for %s", ver, net)
+			}
+		}
+		if across := nd.Bridges.across(); across != nil {
+			if across.SpokePool == (common.Address{}) || across.NativeSymbol == "" {
+				return fmt.Errorf("incomplete Across bridge configuration for %s", net)
+			}
+		}
+		def.nets[net] = nd
+	}
+	return nil
+}
+
+func (b *Bridges) across() *AcrossBridge {
+	if b == nil {
+		return nil
+	}
+	return b.Across
+}
+
+// RegisterSymbol registers the chain's symbol if the BIP ID is not in the BIP
+// ID list. It is an error if the chain has a symbol that doesn't match the
+// listed symbol.
+func (def *ChainDef) RegisterSymbol() error {
+	symbol := strings.ToLower(def.Symbol)
+	if listed := dex.BipIDSymbol(def.BipID); listed != "" {
+		if symbol != "" && symbol != listed {
+			return fmt.Errorf("BIP ID %d is listed as %s, not %s", def.BipID, listed, symbol)
+		}
+		return nil
+	}
+	if symbol == "" {
+		return fmt.Errorf("no symbol for unlisted BIP ID %d", def.BipID)
+	}
+	return dex.RegisterCustomSymbol(def.BipID, symbol)
+}
+
+// Network gets the network definition, or nil if the chain isn't defined for
+// the network.
+func (def *ChainDef) Network(net dex.Network) *NetworkDef {
+	return def.nets[net]
+}
+
+// Units gets the chain's UnitInfo.
+func (def *ChainDef) Units() dex.UnitInfo {
+	if def.UnitInfo != nil {
+		return *def.UnitInfo
+	}
+	return dexeth.UnitInfo
+}
+
+// ContractVersions are the sorted swap contract versions that are deployed on
+// any network.
+func (def *ChainDef) ContractVersions() []uint32 {
+	var vers []uint32
+	for _, nd := range def.nets {
+		for ver := range nd.Contracts {
+			if !containsVersion(vers, ver) {
+				vers = append(vers, ver)
+			}
+		}
+	}
+	sort.Slice(vers, func(i, j int) bool { return vers[i] < vers[j] })
+	return vers
+}
+
+func containsVersion(vers []uint32, ver uint32) bool {
+	for _, v := range vers {
+		if v == ver {
+			return true
+		}
+	}
+	return false
+}
+
+// ContractAddresses are the swap contract addresses by version and network.
+func (def *ChainDef) ContractAddresses() map[uint32]map[dex.Network]common.Address {
+	addrs := make(map[uint32]map[dex.Network]common.Address)
+	for net, nd := range def.nets {
+		for ver, addr := range nd.Contracts {
+			if addrs[ver] == nil {
+				addrs[ver] = make(map[dex.Network]common.Address)
+			}
+			addrs[ver][net] = addr
+		}
+	}
+	return addrs
+}
+
+// VersionedGases are the swap contract gas costs for each deployed contract
+// version.
+func (def *ChainDef) VersionedGases() map[uint32]*dexeth.Gases {
+	gases := make(map[uint32]*dexeth.Gases)
+	for _, ver := range def.ContractVersions() {
+		if g := def.Gases[ver]; g != nil {
+			gases[ver] = g
+		} else if g := dexeth.VersionedGases[ver]; g != nil {
+			gases[ver] = g
+		}
+	}
+	return gases
+}
+
+// String is the chain's name and BIP ID.
+func (def *ChainDef) String() string {
+	return def.Name + " (" + strconv.FormatUint(uint64(def.BipID), 10) + ")"
+}
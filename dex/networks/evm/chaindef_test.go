@@ -0,0 +1,172 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const tChainDefs = `[
+  {
+    "name": "Arbitrum",
+    "symbol": "ARB",
+    "bipID": 9001,
+    "gasModel": "london",
+    "finalizeConfs": 20,
+    "gases": {
+      "1": {"swap": 50000, "swapAdd": 30000, "redeem": 40000, "redeemAdd": 20000, "refund": 45000}
+    },
+    "networks": {
+      "mainnet": {
+        "chainID": 42161,
+        "defaultProviders": ["https://arb1.arbitrum.io/rpc"],
+        "contracts": {"1": "0x1111111111111111111111111111111111111111"},
+        "bridges": {
+          "across": {
+            "spokePool": "0xe35e9842fceaCA96570B734083f4a58e8F7C5f2A",
+            "wrappedNative": "0x82aF49447D8a07e3bd95BD0d56f35241523fBab1",
+            "nativeSymbol": "WETH"
+          }
+        }
+      },
+      "simnet": {
+        "chainID": 1337,
+        "contracts": {
+          "0": "0x2222222222222222222222222222222222222222",
+          "1": "0x3333333333333333333333333333333333333333"
+        }
+      }
+    }
+  },
+  {
+    "name": "Base",
+    "bipID": 8453,
+    "gasModel": "opstack",
+    "networks": {
+      "testnet": {
+        "chainID": 84532,
+        "contracts": {"1": "0x4444444444444444444444444444444444444444"}
+      }
+    }
+  }
+]`
+
+func TestLoadChainDefs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evmchains.json")
+	if err := os.WriteFile(path, []byte(tChainDefs), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defs, err := LoadChainDefs(path)
+	if err != nil {
+		t.Fatalf("LoadChainDefs error: %v", err)
+	}
+	if len(defs) != 2 {
+		t.Fatalf("expected 2 definitions, got %d", len(defs))
+	}
+
+	arb, base := defs[0], defs[1]
+	if arb.GasModel != GasModelLondon || base.GasModel != GasModelOPStack {
+		t.Fatalf("wrong gas models %q, %q", arb.GasModel, base.GasModel)
+	}
+	if arb.FinalizeConfs != 20 || base.FinalizeConfs != defaultFinalizeConfs {
+		t.Fatalf("wrong finalize confs %d, %d", arb.FinalizeConfs, base.FinalizeConfs)
+	}
+	if base.MaxTxFeeGwei != dexeth.GweiFactor || base.GasFeeLimit != defaultGasFeeLimit {
+		t.Fatalf("wrong fee defaults %d, %d", base.MaxTxFeeGwei, base.GasFeeLimit)
+	}
+	if arb.Units().Conventional.Unit != dexeth.UnitInfo.Conventional.Unit {
+		t.Fatal("wrong default units")
+	}
+
+	mainnet := arb.Network(dex.Mainnet)
+	if mainnet == nil || mainnet.ChainID != 42161 {
+		t.Fatal("wrong mainnet definition")
+	}
+	if arb.Network(dex.Testnet) != nil {
+		t.Fatal("unexpected testnet definition")
+	}
+	if across := mainnet.Bridges.across(); across == nil || across.NativeSymbol != "WETH" {
+		t.Fatal("wrong Across configuration")
+	}
+
+	if vers := arb.ContractVersions(); len(vers) != 2 || vers[0] != 0 || vers[1] != 1 {
+		t.Fatalf("wrong contract versions %v", vers)
+	}
+	addrs := arb.ContractAddresses()
+	if addrs[1][dex.Simnet] != common.HexToAddress("0x3333333333333333333333333333333333333333") {
+		t.Fatal("wrong simnet v1 contract address")
+	}
+	if _, found := addrs[0][dex.Mainnet]; found {
+		t.Fatal("unexpected mainnet v0 contract address")
+	}
+	gases := arb.VersionedGases()
+	if gases[0] != dexeth.VersionedGases[0] {
+		t.Fatal("v0 gases should be the default")
+	}
+	if gases[1] == nil || gases[1].Swap != 50000 {
+		t.Fatal("v1 gases not overridden")
+	}
+}
+
+func TestParseChainDefsErrors(t *testing.T) {
+	const nets = `"networks": {"mainnet": {"chainID": 10, "contracts": {"1": "0x1111111111111111111111111111111111111111"}}}`
+	for _, tt := range []struct {
+		name string
+		defs string
+		err  string
+	}{
+		{"no name", `[{"bipID": 9001, ` + nets + `}]`, "no name"},
+		{"no BIP ID", `[{"name": "a", ` + nets + `}]`, "no BIP ID"},
+		{"custom token ID", `[{"name": "a", "bipID": 1000000001, ` + nets + `}]`, "reserved"},
+		{"bad gas model", `[{"name": "a", "bipID": 9001, "gasModel": "x", ` + nets + `}]`, "gas model"},
+		{"no networks", `[{"name": "a", "bipID": 9001}]`, "no networks"},
+		{"bad network", `[{"name": "a", "bipID": 9001, "networks": {"x": {"chainID": 10}}}]`, "unknown network"},
+		{"no chain ID", `[{"name": "a", "bipID": 9001, "networks": {"mainnet": {"contracts": {"1": "0x1111111111111111111111111111111111111111"}}}}]`, "no chain ID"},
+		{"no contracts", `[{"name": "a", "bipID": 9001, "networks": {"mainnet": {"chainID": 10}}}]`, "no swap contracts"},
+		{"no v1 contract", `[{"name": "a", "bipID": 9001, "networks": {"mainnet": {"chainID": 10, "contracts": {"0": "0x1111111111111111111111111111111111111111"}}}}]`, "no version 1"},
+		{"incomplete across", `[{"name": "a", "bipID": 9001, "networks": {"mainnet": {"chainID": 10, "contracts": {"1": "0x1111111111111111111111111111111111111111"}, "bridges": {"across": {}}}}}]`, "Across"},
+		{"duplicate", `[{"name": "a", "bipID": 9001, ` + nets + `}, {"name": "b", "bipID": 9001, ` + nets + `}]`, "duplicate"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseChainDefs([]byte(tt.defs))
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Fatalf("wrong error %q, expected %q", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestRegisterSymbol(t *testing.T) {
+	def := &ChainDef{Name: "Fake", BipID: 9002, Symbol: "FAKEVM"}
+	if err := def.RegisterSymbol(); err != nil {
+		t.Fatalf("RegisterSymbol error: %v", err)
+	}
+	if sym := dex.BipIDSymbol(9002); sym != "fakevm" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	// Listed IDs can omit the symbol, but it must match if provided.
+	if err := (&ChainDef{BipID: 8453}).RegisterSymbol(); err != nil {
+		t.Fatalf("error for listed ID: %v", err)
+	}
+	if err := (&ChainDef{BipID: 8453, Symbol: "BASE"}).RegisterSymbol(); err != nil {
+		t.Fatalf("error for matching symbol: %v", err)
+	}
+	if err := (&ChainDef{BipID: 8453, Symbol: "other"}).RegisterSymbol(); err == nil {
+		t.Fatal("no error for mismatched symbol")
+	}
+	// Unlisted IDs need a symbol.
+	if err := (&ChainDef{BipID: 9003}).RegisterSymbol(); err == nil {
+		t.Fatal("no error for missing symbol")
+	}
+}
@@ -11,6 +11,7 @@ ERC20_SWAP_V0=$(fileToHex "../../networks/erc20/contracts/v0/swap_contract.bin")
 TEST_TOKEN=$(fileToHex "../../networks/erc20/contracts/v0/token_contract.bin")
 MULTIBALANCE_BIN=$(fileToHex "../../networks/eth/contracts/multibalance/contract.bin")
 ETH_SWAP_V1=$(fileToHex "../../networks/eth/contracts/v1/contract.bin")
+BOND_BIN=$(fileToHex "../../networks/eth/contracts/bond/contract.bin")
 
 # Ensure we can create the session and that there's not a session already
 # running before we nuke the data directory.
@@ -135,6 +136,9 @@ TEST_USDT_CONTRACT_HASH=$(gethDeploy "deployERC20(\"${TEST_TOKEN}\",6)")
 echo "Deploying MultiBalance contract."
 MULTIBALANCE_CONTRACT_HASH=$(gethDeploy "deploy(\"${MULTIBALANCE_BIN}\")")
 
+echo "Deploying Bond contract."
+BOND_CONTRACT_HASH=$(gethDeploy "deploy(\"${BOND_BIN}\")")
+
 mine_pending_txs() {
   while true
   do
@@ -241,6 +245,12 @@ cat > "${NODES_ROOT}/multibalance_address.txt" <<EOF
 ${MULTIBALANCE_CONTRACT_ADDR}
 EOF
 
+BOND_CONTRACT_ADDR=$("${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/contractAddress.js --exec contractAddress(\"${BOND_CONTRACT_HASH}\")" | sed 's/"//g')
+echo "Bond contract address is ${BOND_CONTRACT_ADDR}. Saving to ${NODES_ROOT}/bond_contract_address.txt"
+cat > "${NODES_ROOT}/bond_contract_address.txt" <<EOF
+${BOND_CONTRACT_ADDR}
+EOF
+
 # Add test tokens.
 "${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/loadTestToken.js --exec airdrop(\"${TEST_USDC_CONTRACT_ADDR}\",4400000000000000000)"
 "${NODES_ROOT}/harness-ctl/alpha" "attach --preload ${NODES_ROOT}/harness-ctl/loadTestToken.js --exec airdrop(\"${TEST_USDT_CONTRACT_ADDR}\",4400000000000000000)"
@@ -0,0 +1,202 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package eth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"decred.org/dcrdex/dex"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/server/asset"
+	srvdex "decred.org/dcrdex/server/dex"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ETHBondBackend is an ETHBackend for a chain with a bond contract. Only
+// backends with a bond contract satisfy the dex.Bonder interface, so bonds
+// can't be enabled for a chain without one.
+type ETHBondBackend struct {
+	*ETHBackend
+}
+
+var _ srvdex.Bonder = (*ETHBondBackend)(nil)
+
+// BondVer returns the latest supported bond version.
+func (be *ETHBondBackend) BondVer() uint16 {
+	return dexeth.BondVersion
+}
+
+// ParseBondTx performs basic validation of a serialized bond transaction. See
+// AssetBackend.parseRawBondTx.
+func (be *ETHBondBackend) ParseBondTx(ver uint16, rawTx []byte) (bondCoinID []byte, amt int64, bondAddr string,
+	bondPubKeyHash []byte, lockTime int64, acct account.AccountID, err error) {
+	return be.parseRawBondTx(ver, rawTx)
+}
+
+// BondCoin locates and validates a bond transaction. See
+// AssetBackend.bondCoin.
+func (be *ETHBondBackend) BondCoin(ctx context.Context, ver uint16, coinID []byte) (amt, lockTime, confs int64, acct account.AccountID, err error) {
+	return be.bondCoin(ctx, ver, coinID)
+}
+
+// TokenBondBackend is a TokenBackend for a token on a chain with a bond
+// contract.
+type TokenBondBackend struct {
+	*TokenBackend
+}
+
+var _ srvdex.Bonder = (*TokenBondBackend)(nil)
+
+// BondVer returns the latest supported bond version.
+func (be *TokenBondBackend) BondVer() uint16 {
+	return dexeth.BondVersion
+}
+
+// ParseBondTx performs basic validation of a serialized bond transaction. See
+// AssetBackend.parseRawBondTx.
+func (be *TokenBondBackend) ParseBondTx(ver uint16, rawTx []byte) (bondCoinID []byte, amt int64, bondAddr string,
+	bondPubKeyHash []byte, lockTime int64, acct account.AccountID, err error) {
+	return be.parseRawBondTx(ver, rawTx)
+}
+
+// BondCoin locates and validates a bond transaction. See
+// AssetBackend.bondCoin.
+func (be *TokenBondBackend) BondCoin(ctx context.Context, ver uint16, coinID []byte) (amt, lockTime, confs int64, acct account.AccountID, err error) {
+	return be.bondCoin(ctx, ver, coinID)
+}
+
+// parseRawBondTx performs basic validation of a serialized bond transaction,
+// which is a signed call to the bond contract's createBond method. The bond
+// coin ID is the transaction hash and the bond address is the bond's owner.
+// There is no bond pubkey hash for EVM bonds.
+func (be *AssetBackend) parseRawBondTx(ver uint16, rawTx []byte) (bondCoinID []byte, amt int64, bondAddr string,
+	bondPubKeyHash []byte, lockTime int64, acct account.AccountID, err error) {
+
+	if ver != dexeth.BondVersion {
+		err = fmt.Errorf("unsupported bond version %d", ver)
+		return
+	}
+	tx := new(types.Transaction)
+	if err = tx.UnmarshalBinary(rawTx); err != nil {
+		err = fmt.Errorf("error decoding bond transaction: %w", err)
+		return
+	}
+	b, err := be.parseBondTx(tx)
+	if err != nil {
+		return
+	}
+	bondCoinID = tx.Hash().Bytes()
+	amt = int64(be.atomize(b.Value))
+	bondAddr = b.Owner.String()
+	lockTime = int64(b.LockTime)
+	copy(acct[:], b.AcctID[:])
+	return
+}
+
+// parseBondTx checks that the transaction creates a bond for this asset in the
+// bond contract, and returns the bond.
+func (be *AssetBackend) parseBondTx(tx *types.Transaction) (*dexeth.Bond, error) {
+	if be.bondContractAddr == (common.Address{}) {
+		return nil, fmt.Errorf("no %s bond contract", be.baseChainName)
+	}
+	if to := tx.To(); to == nil || *to != be.bondContractAddr {
+		return nil, fmt.Errorf("bond transaction is not to the bond contract %s", be.bondContractAddr)
+	}
+	if tx.ChainId().Uint64() != be.evmChainID {
+		return nil, fmt.Errorf("wrong chain ID %d. wanted %d", tx.ChainId(), be.evmChainID)
+	}
+	b, err := dexeth.ParseCreateBondData(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if b.Token != be.tokenAddr {
+		return nil, fmt.Errorf("bond is for token %s, not %s", b.Token, dex.BipIDSymbol(be.assetID))
+	}
+	if b.Value.Sign() <= 0 {
+		return nil, errors.New("zero bond value")
+	}
+	if be.tokenAddr == (common.Address{}) {
+		if tx.Value().Cmp(b.Value) != 0 {
+			return nil, fmt.Errorf("transaction value %s does not match bond value %s", tx.Value(), b.Value)
+		}
+	} else if tx.Value().Sign() != 0 {
+		return nil, errors.New("token bond transaction has value")
+	}
+	b.Owner, err = types.LatestSignerForChainID(tx.ChainId()).Sender(tx)
+	if err != nil {
+		return nil, fmt.Errorf("error recovering bond transaction sender: %w", err)
+	}
+	return b, nil
+}
+
+// bondCoin locates a bond transaction, validates it, and returns the amount,
+// lock time, account ID, and the confirmations of the transaction. It is a
+// CoinNotFoundError if the bond has been refunded. A bond transaction that is
+// not mined yet has zero confirmations.
+func (be *AssetBackend) bondCoin(ctx context.Context, ver uint16, coinID []byte) (amt, lockTime, confs int64, acct account.AccountID, err error) {
+	if ver != dexeth.BondVersion {
+		err = fmt.Errorf("unsupported bond version %d", ver)
+		return
+	}
+	cid, err := dexeth.DecodeCoinID(coinID)
+	if err != nil {
+		return
+	}
+	if cid.IsRelay {
+		err = errors.New("relay coin ID is not a bond")
+		return
+	}
+	tx, isMempool, err := be.node.transaction(ctx, cid.TxHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			err = asset.CoinNotFoundError
+		}
+		return
+	}
+	b, err := be.parseBondTx(tx)
+	if err != nil {
+		return
+	}
+	amt = int64(be.atomize(b.Value))
+	lockTime = int64(b.LockTime)
+	copy(acct[:], b.AcctID[:])
+	if isMempool {
+		return
+	}
+
+	receipt, err := be.node.transactionReceipt(ctx, cid.TxHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			// Mined, but the receipt isn't available yet.
+			err = nil
+		}
+		return
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		err = fmt.Errorf("bond transaction %s failed", cid.TxHash)
+		return
+	}
+	rec, err := be.node.bond(ctx, be.bondContractAddr, b.ID())
+	if err != nil {
+		err = fmt.Errorf("error reading bond: %w", err)
+		return
+	}
+	if rec == nil { // refunded
+		err = asset.CoinNotFoundError
+		return
+	}
+	tip, err := be.node.blockNumber(ctx)
+	if err != nil {
+		return
+	}
+	if bn := receipt.BlockNumber.Uint64(); tip >= bn {
+		confs = int64(tip - bn + 1)
+	}
+	return
+}
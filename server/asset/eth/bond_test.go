@@ -0,0 +1,326 @@
+//go:build !harness
+
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"decred.org/dcrdex/dex/encode"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	"decred.org/dcrdex/server/asset"
+	srvdex "decred.org/dcrdex/server/dex"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestParseBondTx(t *testing.T) {
+	be, _ := tNewBackend(BipID)
+	be.evmChainID = dexeth.SimnetChainID
+	bondAddr := common.BytesToAddress(encode.RandomBytes(20))
+	tokenAddr := common.BytesToAddress(encode.RandomBytes(20))
+
+	privKey, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(privKey.PublicKey)
+	var acctID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	const lockTime = 1_700_000_000
+	const amtGwei = 1e8
+	value := dexeth.GweiToWei(amtGwei)
+
+	type txParams struct {
+		to       common.Address
+		chainID  int64
+		token    common.Address
+		value    *big.Int
+		txValue  *big.Int
+		calldata []byte
+	}
+	makeTx := func(p *txParams) []byte {
+		data := p.calldata
+		if data == nil {
+			var err error
+			data, err = dexeth.BondABI.Pack(dexeth.CreateBondMethodName, acctID, p.token, p.value, big.NewInt(lockTime))
+			if err != nil {
+				t.Fatalf("error packing create bond data: %v", err)
+			}
+		}
+		signer := types.LatestSignerForChainID(big.NewInt(p.chainID))
+		tx, err := types.SignNewTx(privKey, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(p.chainID),
+			To:        &p.to,
+			Value:     p.txValue,
+			Gas:       dexeth.ETHBondGases.Create,
+			GasFeeCap: big.NewInt(100e9),
+			GasTipCap: big.NewInt(2e9),
+			Data:      data,
+		})
+		if err != nil {
+			t.Fatalf("error signing tx: %v", err)
+		}
+		rawTx, _ := tx.MarshalBinary()
+		return rawTx
+	}
+	goodParams := func() *txParams {
+		return &txParams{
+			to:      bondAddr,
+			chainID: dexeth.SimnetChainID,
+			value:   value,
+			txValue: value,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		params    func() *txParams
+		ver       uint16
+		tokenAddr common.Address
+		noBondCtr bool
+		rawTx     []byte
+		wantErr   bool
+	}{{
+		name:   "ok",
+		params: goodParams,
+	}, {
+		name: "ok token",
+		params: func() *txParams {
+			p := goodParams()
+			p.token = tokenAddr
+			p.txValue = nil
+			return p
+		},
+		tokenAddr: tokenAddr,
+	}, {
+		name:    "wrong version",
+		params:  goodParams,
+		ver:     1,
+		wantErr: true,
+	}, {
+		name:      "no bond contract",
+		params:    goodParams,
+		noBondCtr: true,
+		wantErr:   true,
+	}, {
+		name:    "bad tx",
+		rawTx:   encode.RandomBytes(20),
+		wantErr: true,
+	}, {
+		name: "wrong contract",
+		params: func() *txParams {
+			p := goodParams()
+			p.to = tokenAddr
+			return p
+		},
+		wantErr: true,
+	}, {
+		name: "wrong chain ID",
+		params: func() *txParams {
+			p := goodParams()
+			p.chainID = 1
+			return p
+		},
+		wantErr: true,
+	}, {
+		name: "wrong method",
+		params: func() *txParams {
+			p := goodParams()
+			p.calldata, _ = dexeth.BondABI.Pack(dexeth.RefundBondMethodName, acctID)
+			return p
+		},
+		wantErr: true,
+	}, {
+		name: "token bond for ETH backend",
+		params: func() *txParams {
+			p := goodParams()
+			p.token = tokenAddr
+			p.txValue = nil
+			return p
+		},
+		wantErr: true,
+	}, {
+		name: "token bond with value",
+		params: func() *txParams {
+			p := goodParams()
+			p.token = tokenAddr
+			return p
+		},
+		tokenAddr: tokenAddr,
+		wantErr:   true,
+	}, {
+		name: "tx value mismatch",
+		params: func() *txParams {
+			p := goodParams()
+			p.txValue = new(big.Int).Sub(value, big.NewInt(1))
+			return p
+		},
+		wantErr: true,
+	}, {
+		name: "zero value",
+		params: func() *txParams {
+			p := goodParams()
+			p.value = new(big.Int)
+			p.txValue = new(big.Int)
+			return p
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		be.bondContractAddr = bondAddr
+		if tt.noBondCtr {
+			be.bondContractAddr = common.Address{}
+		}
+		be.tokenAddr = tt.tokenAddr
+		rawTx := tt.rawTx
+		if rawTx == nil {
+			rawTx = makeTx(tt.params())
+		}
+		coinID, amt, addr, _, lt, acct, err := be.parseRawBondTx(tt.ver, rawTx)
+		if tt.wantErr {
+			if err == nil {
+				t.Fatalf("%s: no error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		tx := new(types.Transaction)
+		tx.UnmarshalBinary(rawTx)
+		if !bytes32Equal(coinID, tx.Hash()) {
+			t.Fatalf("%s: wrong coin ID %x", tt.name, coinID)
+		}
+		if amt != amtGwei {
+			t.Fatalf("%s: wrong amount %d", tt.name, amt)
+		}
+		if addr != owner.String() {
+			t.Fatalf("%s: wrong bond address %s", tt.name, addr)
+		}
+		if lt != lockTime {
+			t.Fatalf("%s: wrong lock time %d", tt.name, lt)
+		}
+		if acct != acctID {
+			t.Fatalf("%s: wrong account ID %s", tt.name, acct)
+		}
+	}
+}
+
+func bytes32Equal(b []byte, h common.Hash) bool {
+	return len(b) == common.HashLength && common.BytesToHash(b) == h
+}
+
+func TestBondCoin(t *testing.T) {
+	be, node := tNewBackend(BipID)
+	be.evmChainID = dexeth.SimnetChainID
+	be.bondContractAddr = common.BytesToAddress(encode.RandomBytes(20))
+
+	privKey, _ := crypto.GenerateKey()
+	var acctID [32]byte
+	copy(acctID[:], encode.RandomBytes(32))
+	const lockTime = 1_700_000_000
+	value := dexeth.GweiToWei(1e8)
+	data, _ := dexeth.BondABI.Pack(dexeth.CreateBondMethodName, acctID, common.Address{}, value, big.NewInt(lockTime))
+	chainID := big.NewInt(dexeth.SimnetChainID)
+	tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(chainID), &types.DynamicFeeTx{
+		ChainID:   chainID,
+		To:        &be.bondContractAddr,
+		Value:     value,
+		Gas:       dexeth.ETHBondGases.Create,
+		GasFeeCap: big.NewInt(100e9),
+		GasTipCap: big.NewInt(2e9),
+		Data:      data,
+	})
+	if err != nil {
+		t.Fatalf("error signing tx: %v", err)
+	}
+	coinID := tx.Hash().Bytes()
+	rec := &dexeth.BondRecord{BlockNumber: 10}
+
+	tests := []struct {
+		name      string
+		isMempool bool
+		txErr     error
+		receipt   *types.Receipt
+		bondRec   *dexeth.BondRecord
+		bondErr   error
+		wantConfs int64
+		wantErr   error
+	}{{
+		name:      "ok",
+		receipt:   &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)},
+		bondRec:   rec,
+		wantConfs: 3,
+	}, {
+		name:      "mempool",
+		isMempool: true,
+	}, {
+		name:    "not found",
+		txErr:   ethereum.NotFound,
+		wantErr: asset.CoinNotFoundError,
+	}, {
+		name:    "failed tx",
+		receipt: &types.Receipt{Status: types.ReceiptStatusFailed, BlockNumber: big.NewInt(10)},
+		bondRec: rec,
+		wantErr: errors.New(""),
+	}, {
+		name:    "refunded",
+		receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)},
+		wantErr: asset.CoinNotFoundError,
+	}, {
+		name:    "bond error",
+		receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(10)},
+		bondErr: errors.New("test error"),
+		wantErr: errors.New(""),
+	}}
+
+	for _, tt := range tests {
+		node.tx = tx
+		node.txIsMempool = tt.isMempool
+		node.txErr = tt.txErr
+		node.receipt = tt.receipt
+		node.bondRec = tt.bondRec
+		node.bondErr = tt.bondErr
+		node.blkNum = 12
+
+		amt, lt, confs, acct, err := be.bondCoin(tCtx, dexeth.BondVersion, coinID)
+		if tt.wantErr != nil {
+			if err == nil {
+				t.Fatalf("%s: no error", tt.name)
+			}
+			if errors.Is(tt.wantErr, asset.CoinNotFoundError) && !errors.Is(err, asset.CoinNotFoundError) {
+				t.Fatalf("%s: expected CoinNotFoundError, got %v", tt.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if amt != 1e8 || lt != lockTime || acct != acctID {
+			t.Fatalf("%s: wrong bond values %d, %d, %s", tt.name, amt, lt, acct)
+		}
+		if confs != tt.wantConfs {
+			t.Fatalf("%s: wanted %d confs, got %d", tt.name, tt.wantConfs, confs)
+		}
+	}
+}
+
+func TestWithBondContract(t *testing.T) {
+	be, _ := tNewBackend(BipID)
+	eth := &ETHBackend{be}
+	if _, is := eth.WithBondContract(common.Address{}).(srvdex.Bonder); is {
+		t.Fatalf("backend without a bond contract is a Bonder")
+	}
+	if eth.bondContractAddr != (common.Address{}) {
+		t.Fatalf("bond contract set for the zero address")
+	}
+	bondAddr := common.BytesToAddress(encode.RandomBytes(20))
+	if _, is := eth.WithBondContract(bondAddr).(srvdex.Bonder); !is {
+		t.Fatalf("backend with a bond contract is not a Bonder")
+	}
+	if eth.bondContractAddr != bondAddr {
+		t.Fatalf("wrong bond contract address %s", eth.bondContractAddr)
+	}
+}
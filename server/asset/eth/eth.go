@@ -215,7 +215,11 @@ func (d *Driver) Setup(cfg *asset.BackendConfig) (asset.Backend, error) {
 		}
 	}
 
-	return NewEVMBackend(cfg, chainID, dexeth.ContractAddresses, registeredTokens)
+	be, err := NewEVMBackend(cfg, chainID, dexeth.ContractAddresses, registeredTokens)
+	if err != nil {
+		return nil, err
+	}
+	return be.WithBondContract(dexeth.BondContractAddresses[cfg.Net]), nil
 }
 
 type TokenDriver struct {
@@ -249,6 +253,8 @@ type ethFetcher interface {
 	vector(ctx context.Context, assetID uint32, locator []byte) (*dexeth.SwapVector, error)
 	statusAndVector(ctx context.Context, assetID uint32, locator []byte) (*dexeth.SwapStatus, *dexeth.SwapVector, error)
 	accountBalance(ctx context.Context, assetID uint32, addr common.Address) (*big.Int, error)
+	// bond reads an active bond from the bond contract.
+	bond(ctx context.Context, contractAddr common.Address, id [32]byte) (*dexeth.BondRecord, error)
 }
 
 type baseBackend struct {
@@ -262,6 +268,9 @@ type baseBackend struct {
 	baseChainName   string
 	versionedTokens map[uint32]*VersionedToken
 	evmChainID      uint64
+	// bondContractAddr is the address of the bond contract, or the zero
+	// address if bonds are not supported.
+	bondContractAddr common.Address
 
 	// bestHeight is the last best known chain tip height. bestHeight is set
 	// in Connect before the poll loop is started, and only updated in the poll
@@ -450,6 +459,18 @@ func NewEVMBackend(
 	return eth, nil
 }
 
+// WithBondContract sets the address of the bond contract for the chain's
+// fidelity bonds, and returns the backend to use. If addr is the zero address,
+// bonds are not supported and eth is returned. Otherwise, the returned backend
+// is an *ETHBondBackend. WithBondContract must be called before Connect.
+func (eth *ETHBackend) WithBondContract(addr common.Address) asset.Backend {
+	if addr == (common.Address{}) {
+		return eth
+	}
+	eth.bondContractAddr = addr
+	return &ETHBondBackend{eth}
+}
+
 // Connect connects to the node RPC server and initializes some variables.
 func (eth *ETHBackend) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	eth.baseBackend.ctx = ctx
@@ -538,6 +559,9 @@ func (eth *ETHBackend) TokenBackend(assetID uint32, configPath string) (asset.Ba
 		VersionedToken: vToken,
 	}
 	eth.baseBackend.tokens[assetID] = be
+	if eth.bondContractAddr != (common.Address{}) {
+		return &TokenBondBackend{be}, nil
+	}
 	return be, nil
 }
 
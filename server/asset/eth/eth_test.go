@@ -163,6 +163,8 @@ type testNode struct {
 	receipt          *types.Receipt
 	acctBal          *big.Int
 	acctBalErr       error
+	bondRec          *dexeth.BondRecord
+	bondErr          error
 }
 
 func (n *testNode) connect(ctx context.Context) error {
@@ -246,6 +248,10 @@ func (n *testNode) accountBalance(ctx context.Context, assetID uint32, addr comm
 	return n.acctBal, n.acctBalErr
 }
 
+func (n *testNode) bond(ctx context.Context, contractAddr common.Address, id [32]byte) (*dexeth.BondRecord, error) {
+	return n.bondRec, n.bondErr
+}
+
 func tSwap(bn, locktime int64, value uint64, secret [32]byte, state dexeth.SwapStep, participantAddr *common.Address) *dexeth.SwapState {
 	return &dexeth.SwapState{
 		Secret:      secret,
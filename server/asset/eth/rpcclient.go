@@ -622,6 +622,14 @@ func (c *rpcclient) transactionReceipt(ctx context.Context, txHash common.Hash)
 	})
 }
 
+// bond reads an active bond from the bond contract.
+func (c *rpcclient) bond(ctx context.Context, contractAddr common.Address, id [32]byte) (b *dexeth.BondRecord, err error) {
+	return b, c.withClient(func(ec *ethConn) error {
+		b, err = dexeth.ReadBond(ctx, ec.Client, contractAddr, id)
+		return err
+	})
+}
+
 func isNotFoundError(err error) bool {
 	return strings.Contains(err.Error(), "not found")
 }
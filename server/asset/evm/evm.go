@@ -0,0 +1,75 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+// Package evm provides backends for EVM-compatible chains that are defined in
+// a chain definition file rather than in their own asset package. The backends
+// are the shared eth backend, configured from the chain definition.
+package evm
+
+import (
+	"fmt"
+
+	dexevm "decred.org/dcrdex/dex/networks/evm"
+	"decred.org/dcrdex/server/asset"
+	"decred.org/dcrdex/server/asset/eth"
+)
+
+// Driver implements asset.Driver for a defined chain.
+type Driver struct {
+	eth.Driver
+	def *dexevm.ChainDef
+}
+
+var _ asset.Driver = (*Driver)(nil)
+
+// NewDriver is the constructor for a Driver.
+func NewDriver(def *dexevm.ChainDef) *Driver {
+	return &Driver{
+		Driver: eth.Driver{
+			DriverBase: eth.DriverBase{
+				ProtocolVersion: eth.ProtocolVersion(def.BipID),
+				UI:              def.Units(),
+				Nam:             def.Name,
+			},
+		},
+		def: def,
+	}
+}
+
+// Setup creates the backend. Start the backend with its Run method.
+func (d *Driver) Setup(cfg *asset.BackendConfig) (asset.Backend, error) {
+	nd := d.def.Network(cfg.Net)
+	if nd == nil {
+		return nil, fmt.Errorf("%s is not defined for %s", d.def.Name, cfg.Net)
+	}
+	be, err := eth.NewEVMBackend(cfg, uint64(nd.ChainID), d.def.ContractAddresses(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return be.WithBondContract(nd.Bond), nil
+}
+
+// RegisterChains registers the chain symbols and asset drivers for the chain
+// definitions. Chains that already have a driver can't be registered.
+func RegisterChains(defs []*dexevm.ChainDef) error {
+	for _, def := range defs {
+		if _, err := asset.UnitInfo(def.BipID); err == nil {
+			return fmt.Errorf("asset %d is already registered", def.BipID)
+		}
+		if err := def.RegisterSymbol(); err != nil {
+			return fmt.Errorf("error registering %s symbol: %w", def, err)
+		}
+		asset.Register(def.BipID, NewDriver(def))
+	}
+	return nil
+}
+
+// RegisterChainsFile loads the chain definitions from the file and registers
+// them with RegisterChains.
+func RegisterChainsFile(path string) ([]*dexevm.ChainDef, error) {
+	defs, err := dexevm.LoadChainDefs(path)
+	if err != nil {
+		return nil, err
+	}
+	return defs, RegisterChains(defs)
+}
@@ -0,0 +1,68 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package evm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/server/asset"
+	_ "decred.org/dcrdex/server/asset/eth"
+)
+
+const tChainDefs = `[{
+  "name": "Test Chain",
+  "symbol": "TEVM",
+  "bipID": 9101,
+  "networks": {
+    "simnet": {
+      "chainID": 1337,
+      "contracts": {"1": "0x1111111111111111111111111111111111111111"}
+    }
+  }
+}]`
+
+func TestRegisterChainsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evmchains.json")
+	if err := os.WriteFile(path, []byte(tChainDefs), 0600); err != nil {
+		t.Fatal(err)
+	}
+	defs, err := RegisterChainsFile(path)
+	if err != nil {
+		t.Fatalf("RegisterChainsFile error: %v", err)
+	}
+	if len(defs) != 1 {
+		t.Fatalf("expected 1 definition, got %d", len(defs))
+	}
+	if sym := dex.BipIDSymbol(9101); sym != "tevm" {
+		t.Fatalf("wrong symbol %q", sym)
+	}
+	ui, err := asset.UnitInfo(9101)
+	if err != nil {
+		t.Fatalf("asset not registered: %v", err)
+	}
+	if ui.Conventional.ConversionFactor != 1e9 {
+		t.Fatalf("wrong conversion factor %d", ui.Conventional.ConversionFactor)
+	}
+	if v, err := asset.Version(9101); err != nil || v != 1 {
+		t.Fatalf("wrong version %d, err = %v", v, err)
+	}
+
+	// Already registered.
+	if err := RegisterChains(defs); err == nil {
+		t.Fatal("no error for duplicate registration")
+	}
+	// Built-in chains can't be redefined.
+	defs[0].BipID = 60
+	if err := RegisterChains(defs); err == nil {
+		t.Fatal("no error for built-in chain")
+	}
+
+	// The backend can only be set up for defined networks.
+	if _, err := NewDriver(defs[0]).Setup(&asset.BackendConfig{Net: dex.Mainnet}); err == nil {
+		t.Fatal("no error for undefined network")
+	}
+}
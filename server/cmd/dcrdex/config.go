@@ -77,6 +77,7 @@ type dexConf struct {
 	DBPort            uint16
 	ShowPGConfig      bool
 	MarketsConfPath   string
+	EVMChainsPath     string
 	CancelThreshold   float64
 	FreeCancels       bool
 	MaxUserCancels    uint32
@@ -131,6 +132,7 @@ type flagsData struct {
 	HiddenService string   `long:"hiddenservice" description:"A host:port on which the RPC server should listen for incoming hidden service connections. No TLS is used for these connections."`
 
 	MarketsConfPath  string        `long:"marketsconfpath" description:"Path to the markets configuration JSON file."`
+	EVMChainsPath    string        `long:"evmchains" description:"Path to a JSON file with definitions of additional EVM-compatible chains."`
 	BroadcastTimeout time.Duration `long:"bcasttimeout" description:"The broadcast timeout specifies how long clients have to broadcast an expected transaction when it is their turn to act. Matches without the expected action by this time are revoked and the actor is penalized (default: 12 minutes)."`
 	TxWaitExpiration time.Duration `long:"txwaitexpiration" description:"How long the server will search for a client-reported transaction before responding to the client with an error indicating that it was not found. This should ideally be less than half of swaps BroadcastTimeout to allow for more than one retry of the client's request (default: 2 minutes)."`
 	DEXPrivKeyPath   string        `long:"dexprivkeypath" description:"The path to a file containing the DEX private key for message signing."`
@@ -468,6 +470,9 @@ func loadConfig() (*dexConf, *procOpts, error) {
 	if !filepath.IsAbs(cfg.MarketsConfPath) {
 		cfg.MarketsConfPath = filepath.Join(cfg.AppDataDir, cfg.MarketsConfPath)
 	}
+	if cfg.EVMChainsPath != "" && !filepath.IsAbs(cfg.EVMChainsPath) {
+		cfg.EVMChainsPath = filepath.Join(cfg.AppDataDir, cfg.EVMChainsPath)
+	}
 	if !filepath.IsAbs(cfg.DEXPrivKeyPath) {
 		cfg.DEXPrivKeyPath = filepath.Join(cfg.AppDataDir, cfg.DEXPrivKeyPath)
 	}
@@ -561,6 +566,7 @@ func loadConfig() (*dexConf, *procOpts, error) {
 		DBPass:            cfg.PGPass,
 		ShowPGConfig:      cfg.ShowPGConfig,
 		MarketsConfPath:   cfg.MarketsConfPath,
+		EVMChainsPath:     cfg.EVMChainsPath,
 		CancelThreshold:   cfg.CancelThreshold,
 		MaxUserCancels:    cfg.MaxUserCancels,
 		FreeCancels:       cfg.FreeCancels,
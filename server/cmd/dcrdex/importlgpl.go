@@ -13,8 +13,9 @@ import (
 	dexbase "decred.org/dcrdex/dex/networks/base"
 	dexeth "decred.org/dcrdex/dex/networks/eth"
 	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
-	_ "decred.org/dcrdex/server/asset/base"    // register base asset
-	_ "decred.org/dcrdex/server/asset/eth"     // register eth asset
+	_ "decred.org/dcrdex/server/asset/base" // register base asset
+	_ "decred.org/dcrdex/server/asset/eth"  // register eth asset
+	"decred.org/dcrdex/server/asset/evm"
 	_ "decred.org/dcrdex/server/asset/polygon" // register polygon asset
 )
 
@@ -23,3 +24,19 @@ func init() {
 	dexpolygon.MaybeReadSimnetAddrs()
 	dexbase.MaybeReadSimnetAddrs()
 }
+
+// registerEVMChains registers the EVM-compatible chains that are defined in the
+// file at path.
+func registerEVMChains(path string) error {
+	if path == "" {
+		return nil
+	}
+	defs, err := evm.RegisterChainsFile(path)
+	if err != nil {
+		return err
+	}
+	for _, def := range defs {
+		log.Infof("Registered EVM chain %s", def)
+	}
+	return nil
+}
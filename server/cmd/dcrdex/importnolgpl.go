@@ -0,0 +1,17 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+//go:build nolgpl
+
+package main
+
+import "errors"
+
+// registerEVMChains is an error if a chain definition file is specified, since
+// EVM-compatible chains are not supported when built with the nolgpl tag.
+func registerEVMChains(path string) error {
+	if path == "" {
+		return nil
+	}
+	return errors.New("EVM chains are not supported in nolgpl builds")
+}
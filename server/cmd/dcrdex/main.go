@@ -36,6 +36,11 @@ func mainCore(ctx context.Context) error {
 		}
 	}()
 
+	// Defined EVM chains must be registered before the markets are loaded.
+	if err := registerEVMChains(cfg.EVMChainsPath); err != nil {
+		return fmt.Errorf("error registering EVM chains: %w", err)
+	}
+
 	if cfg.ValidateMarkets {
 		return dexsrv.ValidateConfigFile(cfg.MarketsConfPath, cfg.Network, log.SubLogger("V"))
 	}
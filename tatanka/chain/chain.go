@@ -35,8 +35,14 @@ type Chain interface {
 	// Query(context.Context, Query) (Result, error)
 	Connected() bool
 	CheckBond(*tanka.Bond) error
-	// AuditHTLC will be needed if Tatanka nodes are the auditors.
-	// AuditHTLC(*tanka.HTLCAudit) (bool, error)
+}
+
+// HTLCAuditor is an optional interface that should be implemented by backends
+// that can verify swap contracts on behalf of clients. AuditHTLC returns false
+// if the HTLC does not exist or does not satisfy the terms of the audit. An
+// error is only returned if the audit could not be performed.
+type HTLCAuditor interface {
+	AuditHTLC(*tanka.HTLCAudit) (bool, error)
 }
 
 // FeeRater is an optional interface that should be implemented by backends for
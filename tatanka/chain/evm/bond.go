@@ -0,0 +1,134 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package evm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const createBondMethodName = "createBond"
+
+// bondContractABI is the subset of the bond contract ABI needed to verify
+// bonds: the createBond method, and the bonds getter for active bonds.
+const bondContractABI = `[{"inputs":[{"internalType":"bytes32","name":"acctID","type":"bytes32"},{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256","name":"lockTime","type":"uint256"}],"name":"createBond","outputs":[{"internalType":"bytes32","name":"id","type":"bytes32"}],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"bonds","outputs":[{"internalType":"bytes32","name":"acctID","type":"bytes32"},{"internalType":"address","name":"owner","type":"address"},{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"uint256","name":"lockTime","type":"uint256"},{"internalType":"uint256","name":"blockNumber","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+var bondABI = func() *abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(bondContractABI))
+	if err != nil {
+		panic(fmt.Sprintf("failed to parse bond abi: %v", err))
+	}
+	return &parsed
+}()
+
+// evmBond is the set of parameters that define a bond in the bond contract.
+type evmBond struct {
+	AcctID [32]byte
+	Owner  common.Address
+	// Token is the zero address for bonds in the chain's native asset.
+	Token    common.Address
+	Value    *big.Int
+	LockTime uint64
+}
+
+// id computes the bond ID, which is
+// keccak256(abi.encode(acctID, owner, token, value, lockTime)).
+func (b *evmBond) id() [32]byte {
+	buf := make([]byte, 0, 32*5)
+	buf = append(buf, b.AcctID[:]...)
+	buf = append(buf, common.LeftPadBytes(b.Owner[:], 32)...)
+	buf = append(buf, common.LeftPadBytes(b.Token[:], 32)...)
+	buf = append(buf, common.LeftPadBytes(b.Value.Bytes(), 32)...)
+	buf = append(buf, common.LeftPadBytes(new(big.Int).SetUint64(b.LockTime).Bytes(), 32)...)
+	return crypto.Keccak256Hash(buf)
+}
+
+// parseCreateBondData parses the calldata of a createBond call. The returned
+// bond does not have an owner, which is the sender of the transaction.
+func parseCreateBondData(calldata []byte) (*evmBond, error) {
+	if len(calldata) < 4 {
+		return nil, errors.New("calldata too short")
+	}
+	method, err := bondABI.MethodById(calldata[:4])
+	if err != nil {
+		return nil, fmt.Errorf("unknown bond contract method: %w", err)
+	}
+	if method.Name != createBondMethodName {
+		return nil, fmt.Errorf("expected %s method but got %s", createBondMethodName, method.Name)
+	}
+	args, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, fmt.Errorf("unable to unpack %s arguments: %w", method.Name, err)
+	}
+	if len(args) != 4 {
+		return nil, fmt.Errorf("expected 4 arguments but got %d", len(args))
+	}
+	acctID, ok := args[0].([32]byte)
+	if !ok {
+		return nil, fmt.Errorf("expected account ID of type [32]byte but got %T", args[0])
+	}
+	token, ok := args[1].(common.Address)
+	if !ok {
+		return nil, fmt.Errorf("expected token of type common.Address but got %T", args[1])
+	}
+	value, ok := args[2].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("expected value of type *big.Int but got %T", args[2])
+	}
+	lockTime, ok := args[3].(*big.Int)
+	if !ok {
+		return nil, fmt.Errorf("expected lock time of type *big.Int but got %T", args[3])
+	}
+	if !lockTime.IsUint64() {
+		return nil, errors.New("lock time out of range")
+	}
+	return &evmBond{
+		AcctID:   acctID,
+		Token:    token,
+		Value:    value,
+		LockTime: lockTime.Uint64(),
+	}, nil
+}
+
+// readContractBond reads the active bond with the ID from the bond contract.
+// A nil bond is returned if the bond does not exist or has been refunded.
+func readContractBond(ctx context.Context, caller bind.ContractCaller, contractAddr common.Address, id [32]byte) (*evmBond, error) {
+	contract := bind.NewBoundContract(contractAddr, *bondABI, caller, nil, nil)
+	var out []any
+	if err := contract.Call(&bind.CallOpts{Context: ctx}, &out, "bonds", id); err != nil {
+		return nil, err
+	}
+	if len(out) != 6 {
+		return nil, fmt.Errorf("expected 6 bond fields but got %d", len(out))
+	}
+	acctID, ok1 := out[0].([32]byte)
+	owner, ok2 := out[1].(common.Address)
+	token, ok3 := out[2].(common.Address)
+	value, ok4 := out[3].(*big.Int)
+	lockTime, ok5 := out[4].(*big.Int)
+	if !ok1 || !ok2 || !ok3 || !ok4 || !ok5 {
+		return nil, errors.New("unexpected bond field types")
+	}
+	if owner == (common.Address{}) {
+		return nil, nil
+	}
+	if !lockTime.IsUint64() {
+		return nil, errors.New("bond lock time out of range")
+	}
+	return &evmBond{
+		AcctID:   acctID,
+		Owner:    owner,
+		Token:    token,
+		Value:    value,
+		LockTime: lockTime.Uint64(),
+	}, nil
+}
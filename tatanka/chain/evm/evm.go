@@ -0,0 +1,445 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	dexbase "decred.org/dcrdex/dex/networks/base"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	swapv1 "decred.org/dcrdex/dex/networks/eth/contracts/v1"
+	dexpolygon "decred.org/dcrdex/dex/networks/polygon"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/tatanka/chain"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	// contractVersion is the version of the swap contract used for audits.
+	contractVersion = 1
+	feeMonitorTick  = time.Second * 10
+	rpcTimeout      = time.Second * 20
+	// defaultBondConfs is the number of confirmations required for a bond if
+	// not specified in the configuration.
+	defaultBondConfs = 1
+)
+
+// evmNetwork is the static data that differentiates the EVM-compatible chains.
+type evmNetwork struct {
+	assetID       uint32
+	name          string
+	chainIDs      map[dex.Network]int64
+	contractAddrs map[dex.Network]common.Address
+}
+
+var (
+	ethereumNetwork = &evmNetwork{
+		assetID:       dexeth.EthBipID,
+		name:          "Ethereum",
+		chainIDs:      dexeth.ChainIDs,
+		contractAddrs: dexeth.ContractAddresses[contractVersion],
+	}
+	polygonNetwork = &evmNetwork{
+		assetID:       dexpolygon.PolygonBipID,
+		name:          "Polygon",
+		chainIDs:      dexpolygon.ChainIDs,
+		contractAddrs: dexpolygon.ContractAddresses[contractVersion],
+	}
+	baseNetwork = &evmNetwork{
+		assetID:       dexbase.BaseBipID,
+		name:          "Base",
+		chainIDs:      dexbase.ChainIDs,
+		contractAddrs: dexbase.ContractAddresses[contractVersion],
+	}
+)
+
+func init() {
+	chain.RegisterChainConstructor(dexeth.EthBipID, NewEthereum)
+	chain.RegisterChainConstructor(dexpolygon.PolygonBipID, NewPolygon)
+	chain.RegisterChainConstructor(dexbase.BaseBipID, NewBase)
+}
+
+type EVMConfigFile struct {
+	// RPCEndpoint is the websocket or HTTP(S) URL, or the IPC path, of the
+	// node.
+	RPCEndpoint string `json:"rpcEndpoint"`
+	// ContractAddress overrides the network's swap contract address. Required
+	// for simnet.
+	ContractAddress string `json:"contractAddress"`
+	// BondContractAddress is the address of the bond contract. There are no
+	// default bond contracts on any EVM network, so bonds are rejected unless
+	// this is set.
+	BondContractAddress string `json:"bondContractAddress"`
+	// BondConfs is the number of confirmations required before a bond is
+	// accepted. Defaults to 1.
+	BondConfs uint64 `json:"bondConfs"`
+	// BondIncrement is the bond amount, in gwei, required for each tier of
+	// bond strength. If zero, bond amounts are not checked.
+	BondIncrement uint64 `json:"bondIncrement"`
+}
+
+// ethClient is the subset of the RPC API used by evmChain. It is satisfied by
+// *ethclient.Client.
+type ethClient interface {
+	bind.ContractCaller
+	ChainID(ctx context.Context) (*big.Int, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	TransactionByHash(ctx context.Context, txHash common.Hash) (tx *types.Transaction, isPending bool, err error)
+	BlockNumber(ctx context.Context) (uint64, error)
+	Close()
+}
+
+var _ ethClient = (*ethclient.Client)(nil)
+
+var _ chain.HTLCAuditor = (*evmChain)(nil)
+
+// swapContract is the subset of the swap contract API used for audits. It is
+// satisfied by *swapv1.ETHSwapCaller.
+type swapContract interface {
+	Status(opts *bind.CallOpts, token common.Address, v swapv1.ETHSwapVector) (swapv1.ETHSwapStatus, error)
+}
+
+// bondReader reads an active bond from the bond contract. A nil BondRecord
+// is returned if the bond does not exist or has been refunded.
+type bondReader func(ctx context.Context, id [32]byte) (*evmBond, error)
+
+type evmChain struct {
+	*evmNetwork
+	cfg          *EVMConfigFile
+	net          dex.Network
+	log          dex.Logger
+	fees         chan uint64
+	contractAddr common.Address
+	// bondContractAddr is the zero address if bonds are not supported.
+	bondContractAddr common.Address
+
+	ec        ethClient
+	contract  swapContract
+	readBond  bondReader
+	connected atomic.Bool
+}
+
+func NewEthereum(rawConfig json.RawMessage, log dex.Logger, net dex.Network) (chain.Chain, error) {
+	return newEVMChain(ethereumNetwork, rawConfig, log, net)
+}
+
+func NewPolygon(rawConfig json.RawMessage, log dex.Logger, net dex.Network) (chain.Chain, error) {
+	return newEVMChain(polygonNetwork, rawConfig, log, net)
+}
+
+func NewBase(rawConfig json.RawMessage, log dex.Logger, net dex.Network) (chain.Chain, error) {
+	return newEVMChain(baseNetwork, rawConfig, log, net)
+}
+
+func newEVMChain(n *evmNetwork, rawConfig json.RawMessage, log dex.Logger, net dex.Network) (*evmChain, error) {
+	var cfg EVMConfigFile
+	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing configuration: %w", err)
+	}
+	if cfg.RPCEndpoint == "" {
+		return nil, errors.New("no rpcEndpoint specified")
+	}
+	if _, found := n.chainIDs[net]; !found {
+		return nil, fmt.Errorf("no %s chain ID for network %s", n.name, net)
+	}
+	contractAddr := n.contractAddrs[net]
+	if cfg.ContractAddress != "" {
+		if !common.IsHexAddress(cfg.ContractAddress) {
+			return nil, fmt.Errorf("invalid contract address %q", cfg.ContractAddress)
+		}
+		contractAddr = common.HexToAddress(cfg.ContractAddress)
+	}
+	if contractAddr == (common.Address{}) {
+		return nil, fmt.Errorf("no %s v%d swap contract address for network %s", n.name, contractVersion, net)
+	}
+	var bondContractAddr common.Address
+	if cfg.BondContractAddress != "" {
+		if !common.IsHexAddress(cfg.BondContractAddress) {
+			return nil, fmt.Errorf("invalid bond contract address %q", cfg.BondContractAddress)
+		}
+		bondContractAddr = common.HexToAddress(cfg.BondContractAddress)
+	}
+	if cfg.BondConfs == 0 {
+		cfg.BondConfs = defaultBondConfs
+	}
+	return &evmChain{
+		evmNetwork:       n,
+		cfg:              &cfg,
+		net:              net,
+		log:              log,
+		fees:             make(chan uint64, 1),
+		contractAddr:     contractAddr,
+		bondContractAddr: bondContractAddr,
+	}, nil
+}
+
+func (c *evmChain) Connect(ctx context.Context) (_ *sync.WaitGroup, err error) {
+	ec, err := ethclient.DialContext(ctx, c.cfg.RPCEndpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to %s RPC endpoint: %w", c.name, err)
+	}
+	if c.contract, err = swapv1.NewETHSwapCaller(c.contractAddr, ec); err != nil {
+		ec.Close()
+		return nil, fmt.Errorf("error constructing swap contract caller: %w", err)
+	}
+	c.ec = ec
+	c.readBond = func(ctx context.Context, id [32]byte) (*evmBond, error) {
+		return readContractBond(ctx, ec, c.bondContractAddr, id)
+	}
+
+	if err = c.initialize(ctx); err != nil {
+		ec.Close()
+		return nil, err
+	}
+
+	c.connected.Store(true)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer ec.Close()
+		defer c.connected.Store(false)
+		c.monitorFees(ctx)
+	}()
+
+	return &wg, nil
+}
+
+func (c *evmChain) initialize(ctx context.Context) error {
+	chainID, err := c.ec.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving chain ID: %w", err)
+	}
+	if wantChainID := c.chainIDs[c.net]; chainID.Int64() != wantChainID {
+		return fmt.Errorf("wrong %s chain ID %s, expected %d", c.name, chainID, wantChainID)
+	}
+	hdr, err := c.ec.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error getting best header: %w", err)
+	}
+	if hdr.BaseFee == nil {
+		return fmt.Errorf("%s block header does not contain base fee", c.name)
+	}
+	return nil
+}
+
+func (c *evmChain) Connected() bool {
+	return c.connected.Load()
+}
+
+func (c *evmChain) FeeChannel() <-chan uint64 {
+	return c.fees
+}
+
+func (c *evmChain) monitorFees(ctx context.Context) {
+	tick := time.NewTicker(feeMonitorTick)
+	defer tick.Stop()
+	var tip common.Hash
+	for {
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return
+		}
+
+		hdr, err := c.ec.HeaderByNumber(ctx, nil)
+		if err != nil {
+			c.connected.Store(false)
+			c.log.Errorf("%s is not connected: %v", c.name, err)
+			continue
+		}
+		c.connected.Store(true)
+		if hdr.Hash() == tip {
+			continue
+		}
+		tip = hdr.Hash()
+		feeRate, err := c.feeRate(ctx, hdr)
+		if err != nil {
+			c.log.Errorf("Error calculating %s fee rate: %v", c.name, err)
+			continue
+		}
+		select {
+		case c.fees <- feeRate:
+		case <-time.After(time.Second * 5):
+			c.log.Errorf("fee channel is blocking")
+		}
+	}
+}
+
+// feeRate calculates the fee rate in gwei / gas as twice the header's base fee
+// plus the suggested tip.
+func (c *evmChain) feeRate(ctx context.Context, hdr *types.Header) (uint64, error) {
+	if hdr.BaseFee == nil {
+		return 0, errors.New("block header does not contain base fee")
+	}
+	tipCap, err := c.ec.SuggestGasTipCap(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error getting suggested gas tip cap: %w", err)
+	}
+	feeRate := new(big.Int).Add(tipCap, new(big.Int).Mul(hdr.BaseFee, big.NewInt(2)))
+	return dexeth.WeiToGweiSafe(feeRate)
+}
+
+// CheckBond verifies that the bond's coin ID is a mined transaction calling
+// the bond contract's createBond method, and that the bond is still active in
+// the contract, is committed to the bond's peer, has a lock time no earlier
+// than the bond expiration, and is large enough for the bond strength. Token
+// bonds are not supported.
+func (c *evmChain) CheckBond(b *tanka.Bond) error {
+	if b.AssetID != c.assetID {
+		return fmt.Errorf("wrong asset ID %d for %s bond", b.AssetID, c.name)
+	}
+	if b.Strength == 0 {
+		return errors.New("zero-strength bond")
+	}
+	if c.bondContractAddr == (common.Address{}) {
+		return fmt.Errorf("no %s bond contract for network %s", c.name, c.net)
+	}
+	coinID, err := dexeth.DecodeCoinID(b.CoinID)
+	if err != nil {
+		return err
+	}
+	if coinID.IsRelay {
+		return errors.New("relay coin ID is not a bond")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	tx, isPending, err := c.ec.TransactionByHash(ctx, coinID.TxHash)
+	if err != nil {
+		return fmt.Errorf("error retrieving bond transaction %s: %w", coinID.TxHash, err)
+	}
+	if isPending {
+		return fmt.Errorf("bond transaction %s is not mined", coinID.TxHash)
+	}
+	if to := tx.To(); to == nil || *to != c.bondContractAddr {
+		return fmt.Errorf("bond transaction is not to the bond contract %s", c.bondContractAddr)
+	}
+	bond, err := parseCreateBondData(tx.Data())
+	if err != nil {
+		return err
+	}
+	if bond.Token != (common.Address{}) {
+		return fmt.Errorf("token bonds are not supported for %s", c.name)
+	}
+	if tx.Value().Cmp(bond.Value) != 0 {
+		return fmt.Errorf("transaction value %s does not match bond value %s", tx.Value(), bond.Value)
+	}
+	if bond.Owner, err = types.LatestSignerForChainID(tx.ChainId()).Sender(tx); err != nil {
+		return fmt.Errorf("error recovering bond transaction sender: %w", err)
+	}
+
+	receipt, err := c.ec.TransactionReceipt(ctx, coinID.TxHash)
+	if err != nil {
+		return fmt.Errorf("error retrieving receipt for %s: %w", coinID.TxHash, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("bond transaction %s reverted", coinID.TxHash)
+	}
+	tip, err := c.ec.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving best block number: %w", err)
+	}
+	var confs uint64
+	if bn := receipt.BlockNumber.Uint64(); tip >= bn {
+		confs = tip - bn + 1
+	}
+	if confs < c.cfg.BondConfs {
+		return fmt.Errorf("bond has %d confirmations, %d required", confs, c.cfg.BondConfs)
+	}
+
+	rec, err := c.readBond(ctx, bond.id())
+	if err != nil {
+		return fmt.Errorf("error reading bond: %w", err)
+	}
+	if rec == nil {
+		return errors.New("bond is refunded or was not created")
+	}
+	if account.AccountID(rec.AcctID) != account.NewID(b.PeerID[:]) {
+		return errors.New("bond is committed to a different account")
+	}
+	if lockStamp := time.Unix(int64(rec.LockTime), 0); b.Expiration.After(lockStamp) {
+		return fmt.Errorf("bond expiration %s is after the bond lock time %s", b.Expiration, lockStamp)
+	}
+	if c.cfg.BondIncrement > 0 && dexeth.WeiToGwei(rec.Value) < b.Strength*c.cfg.BondIncrement {
+		return fmt.Errorf("bond value %s wei is too low for strength %d", rec.Value, b.Strength)
+	}
+	return nil
+}
+
+// AuditHTLC verifies that the audit's contract locator describes an initiated
+// v1 swap paying at least the required value to the recipient with the
+// specified secret hash and a lock time no earlier than required. The audit's
+// coin ID must be a successful transaction. Token swaps are not supported.
+func (c *evmChain) AuditHTLC(audit *tanka.HTLCAudit) (bool, error) {
+	if audit.AssetID != c.assetID {
+		return false, fmt.Errorf("wrong asset ID %d for %s audit", audit.AssetID, c.name)
+	}
+	coinID, err := dexeth.DecodeCoinID(audit.CoinID)
+	if err != nil {
+		return false, err
+	}
+	vector, err := dexeth.ParseV1Locator(audit.Contract)
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case !common.IsHexAddress(audit.Recipient) || vector.To != common.HexToAddress(audit.Recipient):
+		c.log.Debugf("Audit failed: wrong recipient %s", vector.To)
+		return false, nil
+	case len(audit.SecretHash) != dexeth.SecretHashSize || [32]byte(audit.SecretHash) != vector.SecretHash:
+		c.log.Debugf("Audit failed: wrong secret hash %x", vector.SecretHash)
+		return false, nil
+	case int64(vector.LockTime) < audit.LockTime.Unix():
+		c.log.Debugf("Audit failed: lock time %d is too early", vector.LockTime)
+		return false, nil
+	case vector.Value.Cmp(dexeth.GweiToWei(audit.Value)) < 0:
+		c.log.Debugf("Audit failed: value %s wei is too low", vector.Value)
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	receipt, err := c.ec.TransactionReceipt(ctx, coinID.TxHash)
+	if err != nil {
+		if errors.Is(err, ethereum.NotFound) {
+			c.log.Debugf("Audit failed: transaction %s not mined", coinID.TxHash)
+			return false, nil
+		}
+		return false, fmt.Errorf("error retrieving receipt for %s: %w", coinID.TxHash, err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		c.log.Debugf("Audit failed: transaction %s reverted", coinID.TxHash)
+		return false, nil
+	}
+
+	status, err := c.contract.Status(&bind.CallOpts{Context: ctx}, common.Address{}, dexeth.SwapVectorToAbigen(vector))
+	if err != nil {
+		return false, fmt.Errorf("error retrieving swap status: %w", err)
+	}
+	if step := dexeth.SwapStep(status.Step); step != dexeth.SSInitiated {
+		c.log.Debugf("Audit failed: swap is %s", step)
+		return false, nil
+	}
+	return true, nil
+}
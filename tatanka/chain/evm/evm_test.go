@@ -0,0 +1,457 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	dexeth "decred.org/dcrdex/dex/networks/eth"
+	swapv1 "decred.org/dcrdex/dex/networks/eth/contracts/v1"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	tLogger           = dex.StdOutLogger("T", dex.LevelTrace)
+	tBondContractAddr = common.HexToAddress("0x8d9ca0d6a9b81b0e3f5a3f5c5d1c2b7b0e4a9d21")
+)
+
+type tEthClient struct {
+	chainID    int64
+	baseFee    *big.Int
+	tipCap     *big.Int
+	receipt    *types.Receipt
+	receiptErr error
+	tx         *types.Transaction
+	txPending  bool
+	txErr      error
+	tip        uint64
+	callResult []byte
+}
+
+func (c *tEthClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (c *tEthClient) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return c.callResult, nil
+}
+
+func (c *tEthClient) ChainID(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(c.chainID), nil
+}
+
+func (c *tEthClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(100), BaseFee: c.baseFee}, nil
+}
+
+func (c *tEthClient) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.tipCap, nil
+}
+
+func (c *tEthClient) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	return c.receipt, c.receiptErr
+}
+
+func (c *tEthClient) TransactionByHash(ctx context.Context, txHash common.Hash) (*types.Transaction, bool, error) {
+	return c.tx, c.txPending, c.txErr
+}
+
+func (c *tEthClient) BlockNumber(ctx context.Context) (uint64, error) {
+	return c.tip, nil
+}
+
+func (c *tEthClient) Close() {}
+
+type tSwapContract struct {
+	status    swapv1.ETHSwapStatus
+	statusErr error
+	vector    swapv1.ETHSwapVector
+}
+
+func (c *tSwapContract) Status(opts *bind.CallOpts, token common.Address, v swapv1.ETHSwapVector) (swapv1.ETHSwapStatus, error) {
+	c.vector = v
+	return c.status, c.statusErr
+}
+
+func tEVMChain(t *testing.T) (*evmChain, *tEthClient, *tSwapContract) {
+	t.Helper()
+	rawCfg, _ := json.Marshal(&EVMConfigFile{
+		RPCEndpoint:         "ws://127.0.0.1:38557",
+		ContractAddress:     "0x2f68e723b8989ba1c6a9f03e42f33cb7dc9d606f",
+		BondContractAddress: tBondContractAddr.String(),
+		BondConfs:           2,
+		BondIncrement:       1e8,
+	})
+	c, err := newEVMChain(ethereumNetwork, rawCfg, tLogger, dex.Simnet)
+	if err != nil {
+		t.Fatalf("newEVMChain error: %v", err)
+	}
+	ec := &tEthClient{
+		chainID: dexeth.SimnetChainID,
+		baseFee: dexeth.GweiToWei(10),
+		tipCap:  dexeth.GweiToWei(2),
+		receipt: &types.Receipt{Status: types.ReceiptStatusSuccessful, BlockNumber: big.NewInt(99)},
+		tip:     100,
+	}
+	contract := &tSwapContract{status: swapv1.ETHSwapStatus{Step: uint8(dexeth.SSInitiated), BlockNumber: big.NewInt(99)}}
+	c.ec, c.contract = ec, contract
+	return c, ec, contract
+}
+
+func TestNewEVMChain(t *testing.T) {
+	if _, err := NewEthereum(json.RawMessage(`{}`), tLogger, dex.Mainnet); err == nil {
+		t.Fatalf("no error for missing endpoint")
+	}
+	if _, err := NewEthereum(json.RawMessage(`{"rpcEndpoint":"ws://127.0.0.1:8546"}`), tLogger, dex.Mainnet); err != nil {
+		t.Fatalf("error for mainnet with default contract: %v", err)
+	}
+	if _, err := NewEthereum(json.RawMessage(`{"rpcEndpoint":"ws://127.0.0.1:8546","contractAddress":"abc"}`), tLogger, dex.Mainnet); err == nil {
+		t.Fatalf("no error for bad contract address")
+	}
+}
+
+func TestInitializeAndFeeRate(t *testing.T) {
+	c, ec, _ := tEVMChain(t)
+	ctx := context.Background()
+	if err := c.initialize(ctx); err != nil {
+		t.Fatalf("initialize error: %v", err)
+	}
+	hdr, _ := ec.HeaderByNumber(ctx, nil)
+	feeRate, err := c.feeRate(ctx, hdr)
+	if err != nil {
+		t.Fatalf("feeRate error: %v", err)
+	}
+	if feeRate != 22 { // 2 * 10 base fee + 2 tip
+		t.Fatalf("wrong fee rate %d", feeRate)
+	}
+
+	ec.chainID = dexeth.MainnetChainID
+	if err := c.initialize(ctx); err == nil {
+		t.Fatalf("no error for wrong chain ID")
+	}
+	ec.chainID = dexeth.SimnetChainID
+	ec.baseFee = nil
+	if err := c.initialize(ctx); err == nil {
+		t.Fatalf("no error for missing base fee")
+	}
+}
+
+func TestCheckBond(t *testing.T) {
+	privKey, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(privKey.PublicKey)
+	var peerID tanka.PeerID
+	copy(peerID[:], encode.RandomBytes(len(peerID)))
+	acctID := account.NewID(peerID[:])
+	lockTime := time.Now().Add(time.Hour * 24 * 30).Truncate(time.Second)
+	value := dexeth.GweiToWei(2e8) // strength 2
+
+	signTx := func(to common.Address, txValue *big.Int, calldata []byte) *types.Transaction {
+		tx, err := types.SignNewTx(privKey, types.LatestSignerForChainID(big.NewInt(dexeth.SimnetChainID)), &types.DynamicFeeTx{
+			ChainID:   big.NewInt(dexeth.SimnetChainID),
+			Gas:       180_000,
+			GasFeeCap: dexeth.GweiToWei(20),
+			GasTipCap: dexeth.GweiToWei(2),
+			To:        &to,
+			Value:     txValue,
+			Data:      calldata,
+		})
+		if err != nil {
+			t.Fatalf("error signing tx: %v", err)
+		}
+		return tx
+	}
+	createData, _ := bondABI.Pack(createBondMethodName, [32]byte(acctID), common.Address{}, value, big.NewInt(lockTime.Unix()))
+	tokenData, _ := bondABI.Pack(createBondMethodName, [32]byte(acctID), common.HexToAddress("0x01"), value, big.NewInt(lockTime.Unix()))
+	bondTx := signTx(tBondContractAddr, value, createData)
+	rec := &evmBond{
+		AcctID:   acctID,
+		Owner:    owner,
+		Value:    value,
+		LockTime: uint64(lockTime.Unix()),
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*tanka.Bond, *tEthClient, *evmChain)
+		wantErr bool
+	}{{
+		name: "ok",
+	}, {
+		name: "wrong asset",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			b.AssetID = 966
+		},
+		wantErr: true,
+	}, {
+		name: "zero strength",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			b.Strength = 0
+		},
+		wantErr: true,
+	}, {
+		name: "no bond contract",
+		modify: func(_ *tanka.Bond, _ *tEthClient, c *evmChain) {
+			c.bondContractAddr = common.Address{}
+		},
+		wantErr: true,
+	}, {
+		name: "bad coin ID",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			b.CoinID = encode.RandomBytes(20)
+		},
+		wantErr: true,
+	}, {
+		name: "pending",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.txPending = true
+		},
+		wantErr: true,
+	}, {
+		name: "tx error",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.tx, ec.txErr = nil, ethereum.NotFound
+		},
+		wantErr: true,
+	}, {
+		name: "wrong contract",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.tx = signTx(common.HexToAddress("0x02"), value, createData)
+		},
+		wantErr: true,
+	}, {
+		name: "token bond",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.tx = signTx(tBondContractAddr, new(big.Int), tokenData)
+		},
+		wantErr: true,
+	}, {
+		name: "value mismatch",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.tx = signTx(tBondContractAddr, dexeth.GweiToWei(1), createData)
+		},
+		wantErr: true,
+	}, {
+		name: "reverted",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.receipt.Status = types.ReceiptStatusFailed
+		},
+		wantErr: true,
+	}, {
+		name: "not enough confirmations",
+		modify: func(_ *tanka.Bond, ec *tEthClient, _ *evmChain) {
+			ec.tip = 99
+		},
+		wantErr: true,
+	}, {
+		name: "refunded",
+		modify: func(_ *tanka.Bond, _ *tEthClient, c *evmChain) {
+			c.readBond = func(context.Context, [32]byte) (*evmBond, error) { return nil, nil }
+		},
+		wantErr: true,
+	}, {
+		name: "wrong account",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			copy(b.PeerID[:], encode.RandomBytes(len(b.PeerID)))
+		},
+		wantErr: true,
+	}, {
+		name: "expiration after lock time",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			b.Expiration = lockTime.Add(time.Second)
+		},
+		wantErr: true,
+	}, {
+		name: "strength too high",
+		modify: func(b *tanka.Bond, _ *tEthClient, _ *evmChain) {
+			b.Strength = 3
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		c, ec, _ := tEVMChain(t)
+		ec.tx = bondTx
+		var readID [32]byte
+		c.readBond = func(_ context.Context, id [32]byte) (*evmBond, error) {
+			readID = id
+			return rec, nil
+		}
+		b := &tanka.Bond{
+			PeerID:     peerID,
+			AssetID:    dexeth.EthBipID,
+			CoinID:     bondTx.Hash().Bytes(),
+			Strength:   2,
+			Expiration: lockTime.Add(-time.Hour),
+		}
+		if tt.modify != nil {
+			tt.modify(b, ec, c)
+		}
+		err := c.CheckBond(b)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if !tt.wantErr && readID != rec.id() {
+			t.Fatalf("%s: wrong bond ID read", tt.name)
+		}
+	}
+}
+
+func TestReadContractBond(t *testing.T) {
+	b := &evmBond{
+		Owner:    common.BytesToAddress(encode.RandomBytes(20)),
+		Value:    dexeth.GweiToWei(1e8),
+		LockTime: uint64(time.Now().Unix()),
+	}
+	copy(b.AcctID[:], encode.RandomBytes(32))
+	ec := new(tEthClient)
+	var err error
+	ec.callResult, err = bondABI.Methods["bonds"].Outputs.Pack(b.AcctID, b.Owner, b.Token, b.Value, new(big.Int).SetUint64(b.LockTime), big.NewInt(99))
+	if err != nil {
+		t.Fatalf("error packing bond: %v", err)
+	}
+	rec, err := readContractBond(context.Background(), ec, tBondContractAddr, b.id())
+	if err != nil {
+		t.Fatalf("readContractBond error: %v", err)
+	}
+	if rec == nil || rec.AcctID != b.AcctID || rec.Owner != b.Owner || rec.Value.Cmp(b.Value) != 0 || rec.LockTime != b.LockTime {
+		t.Fatalf("wrong bond read: %+v", rec)
+	}
+
+	// A refunded or unknown bond has a zero owner.
+	ec.callResult, _ = bondABI.Methods["bonds"].Outputs.Pack([32]byte{}, common.Address{}, common.Address{}, new(big.Int), new(big.Int), new(big.Int))
+	if rec, err = readContractBond(context.Background(), ec, tBondContractAddr, b.id()); err != nil || rec != nil {
+		t.Fatalf("expected nil bond, got %+v, %v", rec, err)
+	}
+}
+
+func TestAuditHTLC(t *testing.T) {
+	recipient := common.BytesToAddress(encode.RandomBytes(20))
+	var secretHash [32]byte
+	copy(secretHash[:], encode.RandomBytes(32))
+	lockTime := time.Now().Add(time.Hour * 8).Truncate(time.Second)
+	const value = 5e7 // gwei
+
+	vector := &dexeth.SwapVector{
+		From:       common.BytesToAddress(encode.RandomBytes(20)),
+		To:         recipient,
+		Value:      dexeth.GweiToWei(value),
+		SecretHash: secretHash,
+		LockTime:   uint64(lockTime.Unix()),
+	}
+
+	newAudit := func() *tanka.HTLCAudit {
+		return &tanka.HTLCAudit{
+			AssetID:    dexeth.EthBipID,
+			CoinID:     encode.RandomBytes(32),
+			Contract:   vector.Locator(),
+			Recipient:  recipient.String(),
+			Value:      value,
+			SecretHash: secretHash[:],
+			LockTime:   lockTime,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*tanka.HTLCAudit, *tEthClient, *tSwapContract)
+		wantErr  bool
+		wantPass bool
+	}{{
+		name:     "ok",
+		wantPass: true,
+	}, {
+		name: "wrong recipient",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.Recipient = vector.From.String()
+		},
+	}, {
+		name: "wrong secret hash",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.SecretHash = encode.RandomBytes(32)
+		},
+	}, {
+		name: "lock time too early",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.LockTime = lockTime.Add(time.Hour)
+		},
+	}, {
+		name: "value too low",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.Value = value + 1
+		},
+	}, {
+		name: "tx not mined",
+		modify: func(_ *tanka.HTLCAudit, ec *tEthClient, _ *tSwapContract) {
+			ec.receipt, ec.receiptErr = nil, ethereum.NotFound
+		},
+	}, {
+		name: "tx reverted",
+		modify: func(_ *tanka.HTLCAudit, ec *tEthClient, _ *tSwapContract) {
+			ec.receipt.Status = types.ReceiptStatusFailed
+		},
+	}, {
+		name: "redeemed",
+		modify: func(_ *tanka.HTLCAudit, _ *tEthClient, sc *tSwapContract) {
+			sc.status.Step = uint8(dexeth.SSRedeemed)
+		},
+	}, {
+		name: "receipt error",
+		modify: func(_ *tanka.HTLCAudit, ec *tEthClient, _ *tSwapContract) {
+			ec.receipt, ec.receiptErr = nil, errors.New("test error")
+		},
+		wantErr: true,
+	}, {
+		name: "status error",
+		modify: func(_ *tanka.HTLCAudit, _ *tEthClient, sc *tSwapContract) {
+			sc.statusErr = errors.New("test error")
+		},
+		wantErr: true,
+	}, {
+		name: "bad locator",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.Contract = secretHash[:]
+		},
+		wantErr: true,
+	}, {
+		name: "bad coin ID",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.CoinID = encode.RandomBytes(20)
+		},
+		wantErr: true,
+	}, {
+		name: "wrong asset",
+		modify: func(a *tanka.HTLCAudit, _ *tEthClient, _ *tSwapContract) {
+			a.AssetID = 966
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		c, ec, sc := tEVMChain(t)
+		audit := newAudit()
+		if tt.modify != nil {
+			tt.modify(audit, ec, sc)
+		}
+		ok, err := c.AuditHTLC(audit)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if ok != tt.wantPass {
+			t.Fatalf("%s: wanted pass = %t, got %t", tt.name, tt.wantPass, ok)
+		}
+		if tt.wantPass && sc.vector.Participant != recipient {
+			t.Fatalf("%s: wrong vector passed to contract", tt.name)
+		}
+	}
+}
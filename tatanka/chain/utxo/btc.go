@@ -4,7 +4,10 @@
 package utxo
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,7 +23,9 @@ import (
 	"decred.org/dcrdex/tatanka/tanka"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/btcutil"
+	btcchaincfg "github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	btcwire "github.com/btcsuite/btcd/wire"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/rpcclient/v8"
 )
@@ -39,14 +44,26 @@ type BitcoinConfigFile struct {
 	NodeRelay string `json:"nodeRelay"`
 }
 
-type bitcoinChain struct {
-	cfg  *BitcoinConfigFile
-	net  dex.Network
-	log  dex.Logger
-	fees chan uint64
-	name string
+// btcNode is the subset of the RPC client API used by bitcoinChain. It is
+// satisfied by *rpcclient.Client.
+type btcNode interface {
+	RawRequest(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error)
+	EstimateSmartFee(ctx context.Context, confirmations int64, mode chainjson.EstimateSmartFeeMode) (*chainjson.EstimateSmartFeeResult, error)
+}
+
+var _ btcNode = (*rpcclient.Client)(nil)
 
-	cl        *rpcclient.Client
+var _ chain.HTLCAuditor = (*bitcoinChain)(nil)
+
+type bitcoinChain struct {
+	cfg         *BitcoinConfigFile
+	net         dex.Network
+	chainParams *btcchaincfg.Params
+	log         dex.Logger
+	fees        chan uint64
+	name        string
+
+	cl        btcNode
 	connected atomic.Bool
 }
 
@@ -63,15 +80,33 @@ func NewBitcoin(rawConfig json.RawMessage, log dex.Logger, net dex.Network) (cha
 		return nil, fmt.Errorf("error validating RPC configuration: %v", err)
 	}
 
+	chainParams, err := bitcoinParams(net)
+	if err != nil {
+		return nil, err
+	}
+
 	return &bitcoinChain{
-		cfg:  &cfg,
-		net:  net,
-		log:  log,
-		name: "Bitcoin",
-		fees: make(chan uint64, 1),
+		cfg:         &cfg,
+		net:         net,
+		chainParams: chainParams,
+		log:         log,
+		name:        "Bitcoin",
+		fees:        make(chan uint64, 1),
 	}, nil
 }
 
+func bitcoinParams(net dex.Network) (*btcchaincfg.Params, error) {
+	switch net {
+	case dex.Mainnet:
+		return &btcchaincfg.MainNetParams, nil
+	case dex.Testnet:
+		return &btcchaincfg.TestNet3Params, nil
+	case dex.Simnet:
+		return &btcchaincfg.RegressionNetParams, nil
+	}
+	return nil, fmt.Errorf("unknown network %s", net)
+}
+
 func (c *bitcoinChain) Connect(ctx context.Context) (_ *sync.WaitGroup, err error) {
 	cfg := c.cfg
 	host := cfg.RPCBind
@@ -285,7 +320,33 @@ func (c *bitcoinChain) getBlockVerbose(ctx context.Context, blockHash *chainhash
 
 func (c *bitcoinChain) getRawTransaction(ctx context.Context, txHash *chainhash.Hash) ([]byte, error) {
 	var txB dex.Bytes
-	return txB, c.call(ctx, "getrawtransaction", []any{txHash.String(), false}, &txB)
+	if err := c.call(ctx, "getrawtransaction", []any{txHash.String(), false}, &txB); err != nil {
+		return nil, err
+	}
+	return txB, nil
+}
+
+// transaction fetches and decodes the transaction.
+func (c *bitcoinChain) transaction(ctx context.Context, txHash *chainhash.Hash) (*btcwire.MsgTx, error) {
+	txB, err := c.getRawTransaction(ctx, txHash)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving transaction %s: %w", txHash, err)
+	}
+	msgTx := btcwire.NewMsgTx(btcwire.TxVersion)
+	if err = msgTx.Deserialize(bytes.NewReader(txB)); err != nil {
+		return nil, fmt.Errorf("error deserializing transaction %s: %w", txHash, err)
+	}
+	return msgTx, nil
+}
+
+// isUnspent checks whether the output is unspent, including spends in
+// mempool. gettxout returns null for spent outputs.
+func (c *bitcoinChain) isUnspent(ctx context.Context, txHash *chainhash.Hash, vout uint32) (bool, error) {
+	var txOut *btcjson.GetTxOutResult
+	if err := c.call(ctx, "gettxout", []any{txHash.String(), vout, true}, &txOut); err != nil {
+		return false, fmt.Errorf("gettxout error for %s:%d: %w", txHash, vout, err)
+	}
+	return txOut != nil, nil
 }
 
 func (c *bitcoinChain) call(ctx context.Context, method string, args []any, thing any) error {
@@ -315,11 +376,79 @@ func (c *bitcoinChain) CheckBond(b *tanka.Bond) error {
 	return nil
 }
 
-func (c *bitcoinChain) AuditHTLC(*tanka.HTLCAudit) (bool, error) {
+// AuditHTLC verifies that the audit's coin is an unspent P2SH or P2WSH output
+// paying at least the required value to the audit's swap contract, and that
+// the contract pays to the recipient with the specified secret hash and a lock
+// time no earlier than required.
+func (c *bitcoinChain) AuditHTLC(audit *tanka.HTLCAudit) (bool, error) {
+	if audit.AssetID != BitcoinID {
+		return false, fmt.Errorf("wrong asset ID %d for %s audit", audit.AssetID, c.name)
+	}
+	txHash, vout, err := decodeBTCCoinID(audit.CoinID)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	msgTx, err := c.transaction(ctx, txHash)
+	if err != nil {
+		return false, err
+	}
+	if int(vout) >= len(msgTx.TxOut) {
+		c.log.Debugf("Audit failed: invalid output index %d for tx %s", vout, txHash)
+		return false, nil
+	}
+	if unspent, err := c.isUnspent(ctx, txHash, vout); err != nil {
+		return false, err
+	} else if !unspent {
+		c.log.Debugf("Audit failed: contract output %s:%d is spent", txHash, vout)
+		return false, nil
+	}
 
-	// TODO: Perform the audit
+	txOut := msgTx.TxOut[vout]
+	scriptHash := dexbtc.ExtractScriptHash(txOut.PkScript)
+	segwit := len(scriptHash) == sha256.Size
+	var contractHash []byte
+	if segwit {
+		h := sha256.Sum256(audit.Contract)
+		contractHash = h[:]
+	} else {
+		contractHash = btcutil.Hash160(audit.Contract)
+	}
+	if scriptHash == nil || !bytes.Equal(contractHash, scriptHash) {
+		c.log.Debugf("Audit failed: output %s:%d does not pay to the contract", txHash, vout)
+		return false, nil
+	}
+	_, receiver, lockTime, secretHash, err := dexbtc.ExtractSwapDetails(audit.Contract, segwit, c.chainParams)
+	if err != nil {
+		c.log.Debugf("Audit failed: invalid contract: %v", err)
+		return false, nil
+	}
+	switch {
+	case receiver.String() != audit.Recipient:
+		c.log.Debugf("Audit failed: wrong recipient %s", receiver)
+	case !bytes.Equal(secretHash, audit.SecretHash):
+		c.log.Debugf("Audit failed: wrong secret hash %x", secretHash)
+	case int64(lockTime) < audit.LockTime.Unix():
+		c.log.Debugf("Audit failed: lock time %d is too early", lockTime)
+	case txOut.Value < 0 || uint64(txOut.Value) < audit.Value:
+		c.log.Debugf("Audit failed: value %d is too low", txOut.Value)
+	default:
+		return true, nil
+	}
+	return false, nil
+}
 
-	return true, nil
+// decodeBTCCoinID decodes a Bitcoin coin ID, which is the 32-byte transaction
+// hash followed by the 4-byte big-endian output index.
+func decodeBTCCoinID(coinID []byte) (*chainhash.Hash, uint32, error) {
+	if len(coinID) != chainhash.HashSize+4 {
+		return nil, 0, fmt.Errorf("coin ID wrong length. expected %d, got %d", chainhash.HashSize+4, len(coinID))
+	}
+	var txHash chainhash.Hash
+	copy(txHash[:], coinID[:chainhash.HashSize])
+	return &txHash, binary.BigEndian.Uint32(coinID[chainhash.HashSize:]), nil
 }
 
 // isMethodNotFoundErr will return true if the error indicates that the RPC
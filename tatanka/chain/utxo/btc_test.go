@@ -0,0 +1,215 @@
+package utxo
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	dexbtc "decred.org/dcrdex/dex/networks/btc"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+)
+
+type tBtcNode struct {
+	tx       *wire.MsgTx
+	txErr    error
+	spent    bool
+	txOutErr error
+}
+
+func (n *tBtcNode) RawRequest(ctx context.Context, method string, params []json.RawMessage) (json.RawMessage, error) {
+	switch method {
+	case "getrawtransaction":
+		if n.txErr != nil {
+			return nil, n.txErr
+		}
+		var b bytes.Buffer
+		if err := n.tx.Serialize(&b); err != nil {
+			return nil, err
+		}
+		return json.Marshal(hex.EncodeToString(b.Bytes()))
+	case "gettxout":
+		if n.txOutErr != nil {
+			return nil, n.txOutErr
+		}
+		if n.spent {
+			return json.RawMessage("null"), nil
+		}
+		return json.RawMessage(`{"confirmations":1}`), nil
+	}
+	return nil, errors.New("unknown method " + method)
+}
+
+func (n *tBtcNode) EstimateSmartFee(ctx context.Context, confirmations int64, mode chainjson.EstimateSmartFeeMode) (*chainjson.EstimateSmartFeeResult, error) {
+	return &chainjson.EstimateSmartFeeResult{FeeRate: 0.0001}, nil
+}
+
+func tBitcoinChain(node *tBtcNode) *bitcoinChain {
+	return &bitcoinChain{
+		cfg:         &BitcoinConfigFile{},
+		net:         dex.Simnet,
+		chainParams: &chaincfg.RegressionNetParams,
+		log:         tLogger,
+		fees:        make(chan uint64, 1),
+		name:        "Bitcoin",
+		cl:          node,
+	}
+}
+
+func TestBitcoinAuditHTLC(t *testing.T) {
+	chainParams := &chaincfg.RegressionNetParams
+	for _, segwit := range []bool{true, false} {
+		newAddr := func() btcutil.Address {
+			var addr btcutil.Address
+			var err error
+			if segwit {
+				addr, err = btcutil.NewAddressWitnessPubKeyHash(encode.RandomBytes(20), chainParams)
+			} else {
+				addr, err = btcutil.NewAddressPubKeyHash(encode.RandomBytes(20), chainParams)
+			}
+			if err != nil {
+				t.Fatalf("error creating address: %v", err)
+			}
+			return addr
+		}
+		recipient, sender := newAddr(), newAddr()
+		secretHash := encode.RandomBytes(32)
+		lockTime := time.Now().Add(time.Hour * 8).Truncate(time.Second)
+		const value = 5e7
+
+		contract, err := dexbtc.MakeContract(recipient, sender, secretHash, lockTime.Unix(), segwit, chainParams)
+		if err != nil {
+			t.Fatalf("MakeContract error: %v", err)
+		}
+		var contractAddr btcutil.Address
+		if segwit {
+			h := sha256.Sum256(contract)
+			contractAddr, err = btcutil.NewAddressWitnessScriptHash(h[:], chainParams)
+		} else {
+			contractAddr, err = btcutil.NewAddressScriptHash(contract, chainParams)
+		}
+		if err != nil {
+			t.Fatalf("error creating contract address: %v", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(contractAddr)
+		if err != nil {
+			t.Fatalf("PayToAddrScript error: %v", err)
+		}
+		msgTx := wire.NewMsgTx(wire.TxVersion)
+		msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+		msgTx.AddTxOut(wire.NewTxOut(value, pkScript))
+		txHash := msgTx.TxHash()
+		coinID := func(vout uint32) []byte {
+			return append(txHash[:], encode.Uint32Bytes(vout)...)
+		}
+
+		newAudit := func() *tanka.HTLCAudit {
+			return &tanka.HTLCAudit{
+				AssetID:    BitcoinID,
+				CoinID:     coinID(0),
+				Contract:   contract,
+				Recipient:  recipient.String(),
+				Value:      value,
+				SecretHash: secretHash,
+				LockTime:   lockTime,
+			}
+		}
+
+		tests := []struct {
+			name     string
+			modify   func(*tanka.HTLCAudit, *tBtcNode)
+			wantErr  bool
+			wantPass bool
+		}{{
+			name:     "ok",
+			wantPass: true,
+		}, {
+			name: "wrong recipient",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.Recipient = sender.String()
+			},
+		}, {
+			name: "wrong secret hash",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.SecretHash = encode.RandomBytes(32)
+			},
+		}, {
+			name: "lock time too early",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.LockTime = lockTime.Add(time.Hour)
+			},
+		}, {
+			name: "value too low",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.Value = value + 1
+			},
+		}, {
+			name: "wrong contract",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.Contract = encode.RandomBytes(len(contract))
+			},
+		}, {
+			name: "spent",
+			modify: func(_ *tanka.HTLCAudit, n *tBtcNode) {
+				n.spent = true
+			},
+		}, {
+			name: "bad output index",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.CoinID = coinID(1)
+			},
+		}, {
+			name: "wrong asset",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.AssetID = ChainID
+			},
+			wantErr: true,
+		}, {
+			name: "bad coin ID",
+			modify: func(a *tanka.HTLCAudit, _ *tBtcNode) {
+				a.CoinID = a.CoinID[:32]
+			},
+			wantErr: true,
+		}, {
+			name: "getrawtransaction error",
+			modify: func(_ *tanka.HTLCAudit, n *tBtcNode) {
+				n.txErr = errors.New("test error")
+			},
+			wantErr: true,
+		}, {
+			name: "gettxout error",
+			modify: func(_ *tanka.HTLCAudit, n *tBtcNode) {
+				n.txOutErr = errors.New("test error")
+			},
+			wantErr: true,
+		}}
+
+		for _, tt := range tests {
+			node := &tBtcNode{tx: msgTx}
+			c := tBitcoinChain(node)
+			audit := newAudit()
+			if tt.modify != nil {
+				tt.modify(audit, node)
+			}
+			ok, err := c.AuditHTLC(audit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s (segwit = %t): wanted error = %t, got %v", tt.name, segwit, tt.wantErr, err)
+			}
+			if ok != tt.wantPass {
+				t.Fatalf("%s (segwit = %t): wanted pass = %t, got %t", tt.name, segwit, tt.wantPass, ok)
+			}
+		}
+	}
+}
@@ -4,7 +4,10 @@
 package utxo
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,9 +18,12 @@ import (
 	"time"
 
 	"decred.org/dcrdex/dex"
+	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	"decred.org/dcrdex/server/account"
 	"decred.org/dcrdex/tatanka/chain"
 	"decred.org/dcrdex/tatanka/tanka"
 	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/dcrutil/v4"
 	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
 	"github.com/decred/dcrd/rpcclient/v8"
@@ -26,6 +32,13 @@ import (
 
 const (
 	ChainID = 42
+
+	// defaultBondConfs is the number of confirmations required for a bond if
+	// not specified in the configuration.
+	defaultBondConfs = 1
+	// rpcTimeout is the timeout for RPC requests made while validating bonds
+	// and auditing contracts.
+	rpcTimeout = time.Second * 20
 )
 
 var (
@@ -45,15 +58,38 @@ type DecredConfigFile struct {
 	RPCListen string `json:"rpclisten"`
 	RPCCert   string `json:"rpccert"`
 	NodeRelay string `json:"nodeRelay"`
+	// BondConfs is the number of confirmations required before a bond is
+	// accepted. Defaults to 1.
+	BondConfs int64 `json:"bondConfs"`
+	// BondIncrement is the bond amount, in atoms, required for each tier of
+	// bond strength. If zero, bond amounts are not checked.
+	BondIncrement uint64 `json:"bondIncrement"`
+}
+
+// dcrNode is the subset of the dcrd RPC API used by decredChain. It is
+// satisfied by *rpcclient.Client.
+type dcrNode interface {
+	GetCurrentNet(ctx context.Context) (wire.CurrencyNet, error)
+	Version(ctx context.Context) (map[string]chainjson.VersionResult, error)
+	GetInfo(ctx context.Context) (*chainjson.InfoChainResult, error)
+	GetBestBlockHash(ctx context.Context) (*chainhash.Hash, error)
+	EstimateSmartFee(ctx context.Context, confirmations int64, mode chainjson.EstimateSmartFeeMode) (*chainjson.EstimateSmartFeeResult, error)
+	GetRawTransactionVerbose(ctx context.Context, txHash *chainhash.Hash) (*chainjson.TxRawResult, error)
+	GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uint32, tree int8, mempool bool) (*chainjson.GetTxOutResult, error)
 }
 
+var _ dcrNode = (*rpcclient.Client)(nil)
+
+var _ chain.HTLCAuditor = (*decredChain)(nil)
+
 type decredChain struct {
-	cfg  *DecredConfigFile
-	net  dex.Network
-	log  dex.Logger
-	fees chan uint64
+	cfg         *DecredConfigFile
+	net         dex.Network
+	chainParams *chaincfg.Params
+	log         dex.Logger
+	fees        chan uint64
 
-	cl        *rpcclient.Client
+	cl        dcrNode
 	connected atomic.Bool
 }
 
@@ -62,14 +98,34 @@ func NewDecred(rawConfig json.RawMessage, log dex.Logger, net dex.Network) (chai
 	if err := json.Unmarshal(rawConfig, &cfg); err != nil {
 		return nil, fmt.Errorf("error parsing configuration: %w", err)
 	}
+	if cfg.BondConfs == 0 {
+		cfg.BondConfs = defaultBondConfs
+	}
+	chainParams, err := decredParams(net)
+	if err != nil {
+		return nil, err
+	}
 	return &decredChain{
-		cfg:  &cfg,
-		net:  net,
-		log:  log,
-		fees: make(chan uint64, 1),
+		cfg:         &cfg,
+		net:         net,
+		chainParams: chainParams,
+		log:         log,
+		fees:        make(chan uint64, 1),
 	}, nil
 }
 
+func decredParams(net dex.Network) (*chaincfg.Params, error) {
+	switch net {
+	case dex.Mainnet:
+		return chaincfg.MainNetParams(), nil
+	case dex.Testnet:
+		return chaincfg.TestNet3Params(), nil
+	case dex.Simnet:
+		return chaincfg.SimNetParams(), nil
+	}
+	return nil, fmt.Errorf("unknown network %s", net)
+}
+
 func (c *decredChain) Connect(ctx context.Context) (_ *sync.WaitGroup, err error) {
 	cfg := c.cfg
 	if cfg.NodeRelay == "" {
@@ -243,18 +299,160 @@ func (c *decredChain) monitorFees(ctx context.Context) {
 	}
 }
 
+// CheckBond verifies that the bond's output is an unspent, sufficiently
+// confirmed version 0 bond whose account commitment matches the bond's peer,
+// and that the bond's lock time is not before the claimed expiration.
 func (c *decredChain) CheckBond(b *tanka.Bond) error {
+	if b.AssetID != ChainID {
+		return fmt.Errorf("wrong asset ID %d for Decred bond", b.AssetID)
+	}
+	if b.Strength == 0 {
+		return errors.New("zero-strength bond")
+	}
+	txHash, vout, err := decodeCoinID(b.CoinID)
+	if err != nil {
+		return err
+	}
 
-	// TODO: Validate bond
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	msgTx, confs, err := c.transaction(ctx, txHash)
+	if err != nil {
+		return err
+	}
+	if confs < c.cfg.BondConfs {
+		return fmt.Errorf("bond has %d confirmations, %d required", confs, c.cfg.BondConfs)
+	}
+	if int(vout) >= len(msgTx.TxOut)-1 { // bond output must be followed by the commitment
+		return fmt.Errorf("invalid bond output index %d for tx with %d outputs", vout, len(msgTx.TxOut))
+	}
+	if unspent, err := c.isUnspent(ctx, txHash, vout); err != nil {
+		return err
+	} else if !unspent {
+		return errors.New("bond output is spent")
+	}
 
+	bondOut, commitOut := msgTx.TxOut[vout], msgTx.TxOut[vout+1]
+	scriptHash := dexdcr.ExtractScriptHash(bondOut.Version, bondOut.PkScript)
+	if scriptHash == nil {
+		return errors.New("bond output is not P2SH")
+	}
+	acct, lockTime, pkh, err := dexdcr.ExtractBondCommitDataV0(commitOut.Version, commitOut.PkScript)
+	if err != nil {
+		return fmt.Errorf("invalid bond commitment output: %w", err)
+	}
+	if acct != account.NewID(b.PeerID[:]) {
+		return errors.New("bond is committed to a different account")
+	}
+	bondScript, err := dexdcr.MakeBondScript(0, lockTime, pkh[:])
+	if err != nil {
+		return fmt.Errorf("error reconstructing bond script: %w", err)
+	}
+	if !bytes.Equal(dcrutil.Hash160(bondScript), scriptHash) {
+		return errors.New("bond script hash mismatch")
+	}
+	if lockStamp := time.Unix(int64(lockTime), 0); b.Expiration.After(lockStamp) {
+		return fmt.Errorf("bond expiration %s is after the bond lock time %s", b.Expiration, lockStamp)
+	}
+	if c.cfg.BondIncrement > 0 && uint64(bondOut.Value) < b.Strength*c.cfg.BondIncrement {
+		return fmt.Errorf("bond value %d is too low for strength %d", bondOut.Value, b.Strength)
+	}
 	return nil
 }
 
-func (c *decredChain) AuditHTLC(*tanka.HTLCAudit) (bool, error) {
+// AuditHTLC verifies that the audit's coin is an unspent P2SH output paying at
+// least the required value to the audit's swap contract, and that the
+// contract pays to the recipient with the specified secret hash and a lock
+// time no earlier than required.
+func (c *decredChain) AuditHTLC(audit *tanka.HTLCAudit) (bool, error) {
+	if audit.AssetID != ChainID {
+		return false, fmt.Errorf("wrong asset ID %d for Decred audit", audit.AssetID)
+	}
+	txHash, vout, err := decodeCoinID(audit.CoinID)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+	msgTx, _, err := c.transaction(ctx, txHash)
+	if err != nil {
+		return false, err
+	}
+	if int(vout) >= len(msgTx.TxOut) {
+		c.log.Debugf("Audit failed: invalid output index %d for tx %s", vout, txHash)
+		return false, nil
+	}
+	if unspent, err := c.isUnspent(ctx, txHash, vout); err != nil {
+		return false, err
+	} else if !unspent {
+		c.log.Debugf("Audit failed: contract output %s:%d is spent", txHash, vout)
+		return false, nil
+	}
+
+	txOut := msgTx.TxOut[vout]
+	scriptHash := dexdcr.ExtractScriptHash(txOut.Version, txOut.PkScript)
+	if scriptHash == nil || !bytes.Equal(dcrutil.Hash160(audit.Contract), scriptHash) {
+		c.log.Debugf("Audit failed: output %s:%d does not pay to the contract", txHash, vout)
+		return false, nil
+	}
+	_, receiver, lockTime, secretHash, err := dexdcr.ExtractSwapDetails(audit.Contract, c.chainParams)
+	if err != nil {
+		c.log.Debugf("Audit failed: invalid contract: %v", err)
+		return false, nil
+	}
+	switch {
+	case receiver.String() != audit.Recipient:
+		c.log.Debugf("Audit failed: wrong recipient %s", receiver)
+	case !bytes.Equal(secretHash, audit.SecretHash):
+		c.log.Debugf("Audit failed: wrong secret hash %x", secretHash)
+	case int64(lockTime) < audit.LockTime.Unix():
+		c.log.Debugf("Audit failed: lock time %d is too early", lockTime)
+	case uint64(txOut.Value) < audit.Value:
+		c.log.Debugf("Audit failed: value %d is too low", txOut.Value)
+	default:
+		return true, nil
+	}
+	return false, nil
+}
+
+// transaction fetches and decodes the transaction, also returning the number
+// of confirmations.
+func (c *decredChain) transaction(ctx context.Context, txHash *chainhash.Hash) (*wire.MsgTx, int64, error) {
+	verboseTx, err := c.cl.GetRawTransactionVerbose(ctx, txHash)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error retrieving transaction %s: %w", txHash, err)
+	}
+	txB, err := hex.DecodeString(verboseTx.Hex)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error decoding transaction %s: %w", txHash, err)
+	}
+	msgTx := wire.NewMsgTx()
+	if err = msgTx.Deserialize(bytes.NewReader(txB)); err != nil {
+		return nil, 0, fmt.Errorf("error deserializing transaction %s: %w", txHash, err)
+	}
+	return msgTx, verboseTx.Confirmations, nil
+}
 
-	// TODO: Perform the audit
+// isUnspent checks whether the regular tree output is unspent, including
+// spends in mempool.
+func (c *decredChain) isUnspent(ctx context.Context, txHash *chainhash.Hash, vout uint32) (bool, error) {
+	txOut, err := c.cl.GetTxOut(ctx, txHash, vout, wire.TxTreeRegular, true)
+	if err != nil {
+		return false, fmt.Errorf("gettxout error for %s:%d: %w", txHash, vout, err)
+	}
+	return txOut != nil, nil
+}
 
-	return true, nil
+// decodeCoinID decodes a Decred coin ID, which is the 32-byte transaction hash
+// followed by the 4-byte big-endian output index.
+func decodeCoinID(coinID []byte) (*chainhash.Hash, uint32, error) {
+	if len(coinID) != chainhash.HashSize+4 {
+		return nil, 0, fmt.Errorf("coin ID wrong length. expected %d, got %d", chainhash.HashSize+4, len(coinID))
+	}
+	var txHash chainhash.Hash
+	copy(txHash[:], coinID[:chainhash.HashSize])
+	return &txHash, binary.BigEndian.Uint32(coinID[chainhash.HashSize:]), nil
 }
 
 // connectNodeRPC attempts to create a new websocket connection to a dcrd node
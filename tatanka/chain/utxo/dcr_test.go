@@ -0,0 +1,359 @@
+package utxo
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	dexdcr "decred.org/dcrdex/dex/networks/dcr"
+	"decred.org/dcrdex/server/account"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	chainjson "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/txscript/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+)
+
+var tLogger = dex.StdOutLogger("T", dex.LevelTrace)
+
+type tDcrNode struct {
+	tx       *chainjson.TxRawResult
+	txErr    error
+	spent    bool
+	txOutErr error
+}
+
+func (n *tDcrNode) GetCurrentNet(ctx context.Context) (wire.CurrencyNet, error) {
+	return wire.SimNet, nil
+}
+
+func (n *tDcrNode) Version(ctx context.Context) (map[string]chainjson.VersionResult, error) {
+	return nil, nil
+}
+
+func (n *tDcrNode) GetInfo(ctx context.Context) (*chainjson.InfoChainResult, error) {
+	return &chainjson.InfoChainResult{TxIndex: true}, nil
+}
+
+func (n *tDcrNode) GetBestBlockHash(ctx context.Context) (*chainhash.Hash, error) {
+	return &chainhash.Hash{}, nil
+}
+
+func (n *tDcrNode) EstimateSmartFee(ctx context.Context, confirmations int64, mode chainjson.EstimateSmartFeeMode) (*chainjson.EstimateSmartFeeResult, error) {
+	return &chainjson.EstimateSmartFeeResult{FeeRate: 0.0001}, nil
+}
+
+func (n *tDcrNode) GetRawTransactionVerbose(ctx context.Context, txHash *chainhash.Hash) (*chainjson.TxRawResult, error) {
+	return n.tx, n.txErr
+}
+
+func (n *tDcrNode) GetTxOut(ctx context.Context, txHash *chainhash.Hash, index uint32, tree int8, mempool bool) (*chainjson.GetTxOutResult, error) {
+	if n.spent || n.txOutErr != nil {
+		return nil, n.txOutErr
+	}
+	return &chainjson.GetTxOutResult{}, nil
+}
+
+func tDecredChain(node *tDcrNode) *decredChain {
+	return &decredChain{
+		cfg:         &DecredConfigFile{BondConfs: 1},
+		net:         dex.Simnet,
+		chainParams: chaincfg.SimNetParams(),
+		log:         tLogger,
+		fees:        make(chan uint64, 1),
+		cl:          node,
+	}
+}
+
+func tTxRawResult(t *testing.T, msgTx *wire.MsgTx, confs int64) *chainjson.TxRawResult {
+	t.Helper()
+	txB, err := msgTx.Bytes()
+	if err != nil {
+		t.Fatalf("error serializing tx: %v", err)
+	}
+	return &chainjson.TxRawResult{
+		Hex:           hex.EncodeToString(txB),
+		Txid:          msgTx.TxHash().String(),
+		Confirmations: confs,
+	}
+}
+
+func tP2SHScript(t *testing.T, script []byte) []byte {
+	t.Helper()
+	addr, err := stdaddr.NewAddressScriptHashV0(script, chaincfg.SimNetParams())
+	if err != nil {
+		t.Fatalf("error creating p2sh address: %v", err)
+	}
+	_, pkScript := addr.PaymentScript()
+	return pkScript
+}
+
+func tNewTx(outs ...*wire.TxOut) *wire.MsgTx {
+	msgTx := wire.NewMsgTx()
+	msgTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0, wire.TxTreeRegular), 2e8, nil))
+	for _, out := range outs {
+		msgTx.AddTxOut(out)
+	}
+	return msgTx
+}
+
+func tCoinID(msgTx *wire.MsgTx, vout uint32) []byte {
+	txHash := msgTx.TxHash()
+	return append(txHash[:], encode.Uint32Bytes(vout)...)
+}
+
+func TestCheckBond(t *testing.T) {
+	var peerID tanka.PeerID
+	copy(peerID[:], encode.RandomBytes(tanka.PeerIDLength))
+	pkh := encode.RandomBytes(20)
+	lockTime := uint32(time.Now().Add(time.Hour * 24 * 30).Unix())
+	const bondValue = 1e8
+
+	bondScript, err := dexdcr.MakeBondScript(0, lockTime, pkh)
+	if err != nil {
+		t.Fatalf("MakeBondScript error: %v", err)
+	}
+	makeBondTx := func(acct account.AccountID) *wire.MsgTx {
+		pushData := make([]byte, 0, dexdcr.BondPushDataSize)
+		pushData = append(pushData, 0, 0) // version 0
+		pushData = append(pushData, acct[:]...)
+		pushData = binary.BigEndian.AppendUint32(pushData, lockTime)
+		pushData = append(pushData, pkh...)
+		commitScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_RETURN).AddData(pushData).Script()
+		if err != nil {
+			t.Fatalf("error building commitment script: %v", err)
+		}
+		return tNewTx(wire.NewTxOut(bondValue, tP2SHScript(t, bondScript)), wire.NewTxOut(0, commitScript))
+	}
+
+	goodTx := makeBondTx(account.NewID(peerID[:]))
+	wrongAcctTx := makeBondTx(account.NewID(encode.RandomBytes(tanka.PeerIDLength)))
+
+	newBond := func(msgTx *wire.MsgTx) *tanka.Bond {
+		return &tanka.Bond{
+			PeerID:     peerID,
+			AssetID:    ChainID,
+			CoinID:     tCoinID(msgTx, 0),
+			Strength:   1,
+			Expiration: time.Unix(int64(lockTime), 0).Add(-time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		tx      *wire.MsgTx
+		confs   int64
+		modify  func(*tanka.Bond, *tDcrNode, *decredChain)
+		wantErr bool
+	}{{
+		name:  "ok",
+		tx:    goodTx,
+		confs: 1,
+	}, {
+		name:    "unconfirmed",
+		tx:      goodTx,
+		wantErr: true,
+	}, {
+		name:    "wrong account",
+		tx:      wrongAcctTx,
+		confs:   1,
+		wantErr: true,
+	}, {
+		name:  "spent",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(_ *tanka.Bond, n *tDcrNode, _ *decredChain) {
+			n.spent = true
+		},
+		wantErr: true,
+	}, {
+		name:  "expiration after lock time",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, _ *decredChain) {
+			b.Expiration = time.Unix(int64(lockTime), 0).Add(time.Hour)
+		},
+		wantErr: true,
+	}, {
+		name:  "insufficient value",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, c *decredChain) {
+			c.cfg.BondIncrement = bondValue
+			b.Strength = 2
+		},
+		wantErr: true,
+	}, {
+		name:  "sufficient value",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, c *decredChain) {
+			c.cfg.BondIncrement = bondValue / 2
+			b.Strength = 2
+		},
+	}, {
+		name:  "commitment output index",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, _ *decredChain) {
+			b.CoinID = tCoinID(goodTx, 1)
+		},
+		wantErr: true,
+	}, {
+		name:  "bad coin ID",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, _ *decredChain) {
+			b.CoinID = b.CoinID[:32]
+		},
+		wantErr: true,
+	}, {
+		name:  "zero strength",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(b *tanka.Bond, _ *tDcrNode, _ *decredChain) {
+			b.Strength = 0
+		},
+		wantErr: true,
+	}, {
+		name:  "rpc error",
+		tx:    goodTx,
+		confs: 1,
+		modify: func(_ *tanka.Bond, n *tDcrNode, _ *decredChain) {
+			n.txErr = errors.New("test error")
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		node := &tDcrNode{tx: tTxRawResult(t, tt.tx, tt.confs)}
+		c := tDecredChain(node)
+		bond := newBond(tt.tx)
+		if tt.modify != nil {
+			tt.modify(bond, node, c)
+		}
+		err := c.CheckBond(bond)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestAuditHTLC(t *testing.T) {
+	chainParams := chaincfg.SimNetParams()
+	newAddr := func() stdaddr.Address {
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(encode.RandomBytes(20), chainParams)
+		if err != nil {
+			t.Fatalf("error creating address: %v", err)
+		}
+		return addr
+	}
+	recipient, sender := newAddr(), newAddr()
+	secretHash := encode.RandomBytes(32)
+	lockTime := time.Now().Add(time.Hour * 8).Truncate(time.Second)
+	const value = 5e7
+
+	contract, err := dexdcr.MakeContract(recipient.String(), sender.String(), secretHash, lockTime.Unix(), chainParams)
+	if err != nil {
+		t.Fatalf("MakeContract error: %v", err)
+	}
+	msgTx := tNewTx(wire.NewTxOut(value, tP2SHScript(t, contract)))
+
+	newAudit := func() *tanka.HTLCAudit {
+		return &tanka.HTLCAudit{
+			AssetID:    ChainID,
+			CoinID:     tCoinID(msgTx, 0),
+			Contract:   contract,
+			Recipient:  recipient.String(),
+			Value:      value,
+			SecretHash: secretHash,
+			LockTime:   lockTime,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(*tanka.HTLCAudit, *tDcrNode)
+		wantErr  bool
+		wantPass bool
+	}{{
+		name:     "ok",
+		wantPass: true,
+	}, {
+		name: "earlier required lock time",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.LockTime = lockTime.Add(-time.Hour)
+		},
+		wantPass: true,
+	}, {
+		name: "wrong recipient",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.Recipient = sender.String()
+		},
+	}, {
+		name: "wrong secret hash",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.SecretHash = encode.RandomBytes(32)
+		},
+	}, {
+		name: "lock time too early",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.LockTime = lockTime.Add(time.Hour)
+		},
+	}, {
+		name: "value too low",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.Value = value + 1
+		},
+	}, {
+		name: "wrong contract",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.Contract = encode.RandomBytes(len(contract))
+		},
+	}, {
+		name: "spent",
+		modify: func(_ *tanka.HTLCAudit, n *tDcrNode) {
+			n.spent = true
+		},
+	}, {
+		name: "bad output index",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.CoinID = tCoinID(msgTx, 1)
+		},
+	}, {
+		name: "wrong asset",
+		modify: func(a *tanka.HTLCAudit, _ *tDcrNode) {
+			a.AssetID = 0
+		},
+		wantErr: true,
+	}, {
+		name: "gettxout error",
+		modify: func(_ *tanka.HTLCAudit, n *tDcrNode) {
+			n.txOutErr = errors.New("test error")
+		},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		node := &tDcrNode{tx: tTxRawResult(t, msgTx, 1)}
+		c := tDecredChain(node)
+		audit := newAudit()
+		if tt.modify != nil {
+			tt.modify(audit, node)
+		}
+		ok, err := c.AuditHTLC(audit)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: wanted error = %t, got %v", tt.name, tt.wantErr, err)
+		}
+		if ok != tt.wantPass {
+			t.Fatalf("%s: wanted pass = %t, got %t", tt.name, tt.wantPass, ok)
+		}
+	}
+}
@@ -893,6 +893,19 @@ func (c *MeshConn) RequestMesh(msg *msgjson.Message, thing any, opts ...RequestO
 	return nil
 }
 
+// NotifyMesh sends a notification to the Mesh.
+func (c *MeshConn) NotifyMesh(msg *msgjson.Message) error {
+	c.nodesMtx.RLock()
+	primaryNode := c.primaryNode
+	c.nodesMtx.RUnlock()
+	if primaryNode == nil {
+		return errors.New("not connected to any tatanka nodes")
+	}
+
+	mj.SignMessage(c.priv, msg)
+	return primaryNode.Send(msg)
+}
+
 func (c *MeshConn) requestTT(tt *tatankaNode, msg *msgjson.Message, thing any, timeout time.Duration, checkSig ...bool) (err error) {
 	errChan := make(chan error)
 	if err := tt.Request(msg, func(msg *msgjson.Message) {
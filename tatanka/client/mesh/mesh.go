@@ -18,6 +18,7 @@ import (
 	"decred.org/dcrdex/dex/lexi"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/tatanka/client/conn"
+	"decred.org/dcrdex/tatanka/client/orderbook"
 	"decred.org/dcrdex/tatanka/mj"
 	"decred.org/dcrdex/tatanka/tanka"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -29,12 +30,15 @@ type Config struct {
 	PrivateKey *secp256k1.PrivateKey
 	Logger     dex.Logger
 	EntryNode  *TatankaCredentials
+	Net        dex.Network
 }
 
 // Mesh is a manager for operations on the Tatanka Mesh Network.
 type Mesh struct {
+	ctx    context.Context
 	priv   *secp256k1.PrivateKey
 	peerID tanka.PeerID
+	net    dex.Network
 
 	// cfg      *Config
 	log       dex.Logger
@@ -46,6 +50,20 @@ type Mesh struct {
 	db        *lexi.DB
 	dbCM      *dex.ConnectionMaster
 	bondTable *lexi.Table
+	// pendingBondTable stores bonds that have been broadcast but not yet
+	// accepted by the mesh, keyed on bond ID.
+	pendingBondTable *lexi.Table
+	// swapTable stores swap state, keyed on match ID.
+	swapTable *lexi.Table
+	// outcomeTable stores the tally of swap outcomes, keyed on peer ID.
+	outcomeTable *lexi.Table
+	outcomesMtx  sync.Mutex
+
+	walletsMtx sync.RWMutex
+	wallets    map[uint32]*swapWallet
+
+	swapsMtx sync.RWMutex
+	swaps    map[tanka.ID32]*swap
 
 	marketsMtx sync.RWMutex
 	markets    map[string]*market
@@ -67,14 +85,18 @@ func New(cfg *Config) (*Mesh, error) {
 	}
 
 	mesh := &Mesh{
-		priv:      cfg.PrivateKey,
-		peerID:    peerID,
-		log:       cfg.Logger,
-		dataDir:   cfg.DataDir,
-		entryNode: cfg.EntryNode,
-		payloads:  make(chan any, 128),
-		markets:   make(map[string]*market),
-		fiatRates: make(map[string]*fiatrates.FiatRateInfo),
+		priv:             cfg.PrivateKey,
+		peerID:           peerID,
+		net:              cfg.Net,
+		log:              cfg.Logger,
+		dataDir:          cfg.DataDir,
+		entryNode:        cfg.EntryNode,
+		payloads:         make(chan any, 128),
+		markets:          make(map[string]*market),
+		fiatRates:        make(map[string]*fiatrates.FiatRateInfo),
+		feeRateEstimates: make(map[uint32]*feerates.Estimate),
+		wallets:          make(map[uint32]*swapWallet),
+		swaps:            make(map[tanka.ID32]*swap),
 	}
 
 	if err := mesh.initializeDB(); err != nil {
@@ -87,6 +109,7 @@ func New(cfg *Config) (*Mesh, error) {
 // Connect initializes the Mesh.
 func (m *Mesh) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 	var wg sync.WaitGroup
+	m.ctx = ctx
 
 	dbCM := dex.NewConnectionMaster(m.db)
 	if err := dbCM.ConnectOnce(ctx); err != nil {
@@ -112,6 +135,22 @@ func (m *Mesh) Connect(ctx context.Context) (*sync.WaitGroup, error) {
 
 	m.conn = &meshConn{mesh, meshCM}
 
+	if err := m.loadSwaps(); err != nil {
+		return nil, fmt.Errorf("error loading active swaps: %w", err)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.runSwaps(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.runMarkets(ctx)
+	}()
+
 	wg.Add(1)
 	go func() {
 		<-dbCM.Done()
@@ -137,7 +176,19 @@ func (m *Mesh) initializeDB() error {
 	}
 	m.db = db
 
-	m.bondTable, err = db.Table("bond")
+	if m.bondTable, err = db.Table("bond"); err != nil {
+		return err
+	}
+	if err = m.bondTable.Migrate(bondTTLUpgrade); err != nil {
+		return err
+	}
+	if m.pendingBondTable, err = db.Table("pendingbond"); err != nil {
+		return err
+	}
+	if m.swapTable, err = db.Table("swap"); err != nil {
+		return err
+	}
+	m.outcomeTable, err = db.Table("outcome")
 	return err
 }
 
@@ -179,6 +230,14 @@ func (m *Mesh) handlePeerRequest(peerID tanka.PeerID, route string, payload json
 	case mj.RouteNegotiate:
 		// TODO: Reputation check.
 		m.handleNegotiate(peerID, payload, respond)
+	case mj.RouteSwapAddress:
+		m.handleSwapAddress(peerID, payload, respond)
+	case mj.RouteSwapContract:
+		m.handleSwapContract(peerID, payload, respond)
+	case mj.RouteSwapRedeem:
+		m.handleSwapRedeem(peerID, payload, respond)
+	case mj.RouteOrderSnapshot:
+		m.handleOrderSnapshot(peerID, payload, respond)
 	default:
 		m.log.Debugf("Received a peer request for an unknown route %q", route)
 	}
@@ -212,21 +271,79 @@ func (m *Mesh) SubscribeToFiatRates() error {
 	return m.conn.RequestMesh(req, &ok)
 }
 
-// PostBond stores the bond in the database and sends it to the mesh.
+// PostBond sends the bond to the mesh. Once the mesh accepts the bond, it is
+// stored as an active bond and removed from the pending bonds.
 func (m *Mesh) PostBond(bond *tanka.Bond) error {
+	req := mj.MustRequest(mj.RoutePostBond, []*tanka.Bond{bond})
+	var res bool
+	if err := m.conn.RequestMesh(req, &res); err != nil {
+		return err
+	}
 	k := bond.ID()
-	if err := m.bondTable.Set(k[:], lexi.JSON(bond)); err != nil {
+	if err := m.bondTable.Set(k[:], lexi.JSON(bond), lexi.WithReplace(), lexi.WithTTL(time.Until(bond.Expiration))); err != nil {
 		return fmt.Errorf("error storing bond in DB: %w", err)
 	}
-	req := mj.MustRequest(mj.RoutePostBond, []*tanka.Bond{bond})
-	var res bool
-	return m.conn.RequestMesh(req, &res)
+	return m.RemovePendingBond(bond)
+}
+
+// AuditHTLC asks the mesh to audit a swap contract. This is for assets whose
+// wallets cannot audit the counterparty's contract themselves. The result is
+// true only if the Tatanka node's chain backend found an HTLC satisfying the
+// terms of the audit.
+func (m *Mesh) AuditHTLC(audit *tanka.HTLCAudit) (bool, error) {
+	req := mj.MustRequest(mj.RouteAuditHTLC, audit)
+	var ok bool
+	if err := m.conn.RequestMesh(req, &ok); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+// AddPendingBond stores a bond that will be posted with PostBond when it is
+// confirmed, so that posting can resume after a restart. Like active bonds,
+// pending bonds are deleted from the database when they expire.
+func (m *Mesh) AddPendingBond(bond *tanka.Bond) error {
+	k := bond.ID()
+	if err := m.pendingBondTable.Set(k[:], lexi.JSON(bond), lexi.WithReplace(), lexi.WithTTL(time.Until(bond.Expiration))); err != nil {
+		return fmt.Errorf("error storing pending bond in DB: %w", err)
+	}
+	return nil
 }
 
-// ActiveBonds retrieves the active bonds from the database.
+// RemovePendingBond deletes the pending bond. It is not an error if the bond
+// is not pending.
+func (m *Mesh) RemovePendingBond(bond *tanka.Bond) error {
+	k := bond.ID()
+	if err := m.pendingBondTable.Delete(k[:]); err != nil && !errors.Is(err, lexi.ErrKeyNotFound) {
+		return fmt.Errorf("error deleting pending bond from DB: %w", err)
+	}
+	return nil
+}
+
+// PendingBonds retrieves the bonds that have not yet been accepted by the
+// mesh.
+func (m *Mesh) PendingBonds() ([]*tanka.Bond, error) {
+	return readBonds(m.pendingBondTable)
+}
+
+// ActiveBonds retrieves the bonds that have been accepted by the mesh.
 func (m *Mesh) ActiveBonds() ([]*tanka.Bond, error) {
+	return readBonds(m.bondTable)
+}
+
+// bondTTLUpgrade is the version 1 upgrade of the bond table, which sets the
+// TTLs of bonds stored without one.
+var bondTTLUpgrade = lexi.TTLUpgrade(func(vB []byte) (lexi.KV, time.Time, error) {
+	var bond tanka.Bond
+	if err := json.Unmarshal(vB, &bond); err != nil {
+		return nil, time.Time{}, err
+	}
+	return lexi.JSON(&bond), bond.Expiration, nil
+})
+
+func readBonds(tbl *lexi.Table) ([]*tanka.Bond, error) {
 	bonds := make([]*tanka.Bond, 0, 1)
-	return bonds, m.bondTable.Iterate(nil, func(it *lexi.Iter) error {
+	return bonds, tbl.Iterate(nil, func(it *lexi.Iter) error {
 		var bond tanka.Bond
 		if err := it.V(func(vB []byte) error {
 			return json.Unmarshal(vB, &bond)
@@ -238,6 +355,8 @@ func (m *Mesh) ActiveBonds() ([]*tanka.Bond, error) {
 	})
 }
 
+// SubscribeMarket subscribes to the market's order broadcasts. It is not an
+// error to subscribe to a market more than once.
 func (m *Mesh) SubscribeMarket(baseID, quoteID uint32) error {
 	mktName, err := dex.MarketName(baseID, quoteID)
 	if err != nil {
@@ -247,17 +366,23 @@ func (m *Mesh) SubscribeMarket(baseID, quoteID uint32) error {
 	m.marketsMtx.Lock()
 	defer m.marketsMtx.Unlock()
 
+	if _, found := m.markets[mktName]; found {
+		return nil
+	}
+
 	if err = m.conn.Subscribe(mj.TopicMarket, tanka.Subject(mktName)); err != nil {
 		return fmt.Errorf("error subscribing to market: %w", err)
 	}
 
 	m.markets[mktName] = &market{
-		log:     m.log.SubLogger(mktName),
-		peerID:  m.peerID,
-		baseID:  baseID,
-		quoteID: quoteID,
-		conn:    m.conn,
-		ords:    make(map[tanka.ID40]*order),
+		log:       m.log.SubLogger(mktName),
+		peerID:    m.peerID,
+		baseID:    baseID,
+		quoteID:   quoteID,
+		conn:      m.conn,
+		ords:      make(map[tanka.ID40]*order),
+		book:      orderbook.New(),
+		beginSwap: m.beginSwap,
 	}
 
 	return nil
@@ -279,7 +404,7 @@ func (m *Mesh) handleBroadcast(payload json.RawMessage) {
 
 func (m *Mesh) handleNegotiate(peerID tanka.PeerID, payload json.RawMessage, respond func(any, mj.TankagramError)) {
 	var match *tanka.Match
-	if err := json.Unmarshal(payload, match); err != nil {
+	if err := json.Unmarshal(payload, &match); err != nil {
 		m.log.Debugf("handleNegotiate: unable to unmarshal match from peer %v: %v", peerID, err)
 		respond(false, mj.TEEBadRequest)
 		return
@@ -298,7 +423,18 @@ func (m *Mesh) handleNegotiate(peerID tanka.PeerID, payload json.RawMessage, res
 		respond(false, mj.TEEPeerError)
 		return
 	}
-	respond(market.handleNegotiate(match), mj.TEErrNone)
+	if match.From != peerID {
+		m.log.Debugf("handleNegotiate: peer %v sent a match from %v", peerID, match.From)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	ord := market.handleNegotiate(match)
+	if ord == nil {
+		respond(false, mj.TEErrNone)
+		return
+	}
+	m.beginSwap(ord.ID(), ord, match, true)
+	respond(true, mj.TEErrNone)
 }
 
 func (m *Mesh) handleRates(payload json.RawMessage) {
@@ -0,0 +1,61 @@
+package mesh
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+func TestPendingBonds(t *testing.T) {
+	priv, _ := genKeyPair()
+	m, err := New(&Config{
+		DataDir:    t.TempDir(),
+		PrivateKey: priv,
+		Logger:     tLogger,
+		Net:        dex.Simnet,
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer m.db.Close()
+
+	bond := &tanka.Bond{
+		PeerID:     m.ID(),
+		AssetID:    42,
+		CoinID:     encode.RandomBytes(36),
+		Strength:   1,
+		Expiration: time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+	// Adding the bond again replaces it.
+	for i := 0; i < 2; i++ {
+		if err := m.AddPendingBond(bond); err != nil {
+			t.Fatalf("AddPendingBond error: %v", err)
+		}
+	}
+	bonds, err := m.PendingBonds()
+	if err != nil {
+		t.Fatalf("PendingBonds error: %v", err)
+	}
+	if len(bonds) != 1 || bonds[0].ID() != bond.ID() {
+		t.Fatalf("wrong pending bonds %+v", bonds)
+	}
+	// Pending bonds are not active.
+	if bonds, err = m.ActiveBonds(); err != nil {
+		t.Fatalf("ActiveBonds error: %v", err)
+	} else if len(bonds) != 0 {
+		t.Fatalf("pending bond counted as active")
+	}
+	for i := 0; i < 2; i++ {
+		if err := m.RemovePendingBond(bond); err != nil {
+			t.Fatalf("RemovePendingBond error: %v", err)
+		}
+	}
+	if bonds, err = m.PendingBonds(); err != nil {
+		t.Fatalf("PendingBonds error: %v", err)
+	} else if len(bonds) != 0 {
+		t.Fatalf("pending bond not removed")
+	}
+}
@@ -0,0 +1,1039 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/lexi"
+	dexorder "decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/tatanka/mj"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+const (
+	// defaultSwapConf is the number of confirmations required on the
+	// counterparty's swap contract if not specified when adding the wallet.
+	defaultSwapConf = 1
+	// failurePenalty is how many successful swaps a counterparty failure
+	// cancels out when calculating a peer's score.
+	failurePenalty = 5
+)
+
+// swapTick is how often active swaps are checked for progress.
+var swapTick = time.Second * 5
+
+// SwapWallet is the subset of asset.Wallet needed to execute swaps.
+type SwapWallet interface {
+	Info() *asset.WalletInfo
+	FundOrder(*asset.Order) (coins asset.Coins, redeemScripts []dex.Bytes, fees uint64, err error)
+	ReturnCoins(asset.Coins) error
+	Swap(ctx context.Context, swaps *asset.Swaps) (receipts []asset.Receipt, changeCoin asset.Coin, feesPaid uint64, err error)
+	Redeem(ctx context.Context, redeems *asset.RedeemForm) (ins []dex.Bytes, out asset.Coin, feesPaid uint64, err error)
+	AuditContract(coinID, contract, txData dex.Bytes, rebroadcast bool) (*asset.AuditInfo, error)
+	ContractLockTimeExpired(ctx context.Context, contract dex.Bytes) (bool, time.Time, error)
+	FindRedemption(ctx context.Context, coinID, contract dex.Bytes) (redemptionCoin, secret dex.Bytes, err error)
+	Refund(ctx context.Context, coinID, contract dex.Bytes, feeRate uint64) (dex.Bytes, error)
+	RedemptionAddress() (string, error)
+	SwapConfirmations(ctx context.Context, coinID dex.Bytes, contract dex.Bytes, matchTime time.Time) (confs uint32, spent bool, err error)
+}
+
+var _ SwapWallet = asset.Wallet(nil)
+
+type swapWallet struct {
+	SwapWallet
+	swapConf uint32
+}
+
+// version is the newest asset version supported by the wallet.
+func (w *swapWallet) version() (ver uint32) {
+	for _, v := range w.Info().SupportedVersions {
+		if v > ver {
+			ver = v
+		}
+	}
+	return
+}
+
+// AddWallet adds the wallet used to execute swaps for the asset. swapConf is
+// the number of confirmations required on the counterparty's swap contract
+// before we act on it. If zero, a default of 1 is used.
+func (m *Mesh) AddWallet(assetID uint32, w SwapWallet, swapConf uint32) {
+	if swapConf == 0 {
+		swapConf = defaultSwapConf
+	}
+	m.walletsMtx.Lock()
+	m.wallets[assetID] = &swapWallet{SwapWallet: w, swapConf: swapConf}
+	m.walletsMtx.Unlock()
+}
+
+func (m *Mesh) wallet(assetID uint32) *swapWallet {
+	m.walletsMtx.RLock()
+	defer m.walletsMtx.RUnlock()
+	return m.wallets[assetID]
+}
+
+// swapOutcome is the result of a swap as it pertains to the counterparty's
+// reputation.
+type swapOutcome uint8
+
+const (
+	outcomePending swapOutcome = iota
+	// outcomeSuccess means the swap completed.
+	outcomeSuccess
+	// outcomeFailure means the counterparty failed to perform their part of
+	// the swap.
+	outcomeFailure
+	// outcomeNeutral means the swap did not complete, but the counterparty is
+	// not known to be at fault.
+	outcomeNeutral
+)
+
+// swapState is the persisted state of a match's swap. The maker initiates.
+type swapState struct {
+	Match *tanka.Match `json:"match"`
+	// OrderID is the ID of our order.
+	OrderID      tanka.ID40   `json:"orderID"`
+	Counterparty tanka.PeerID `json:"counterparty"`
+	Maker        bool         `json:"maker"`
+	Rate         uint64       `json:"rate"`
+	SendAsset    uint32       `json:"sendAsset"`
+	SendQty      uint64       `json:"sendQty"`
+	RecvAsset    uint32       `json:"recvAsset"`
+	RecvQty      uint64       `json:"recvQty"`
+	// Status follows the progress of the swap as in dex/order, regardless of
+	// our role. A refunded or revoked swap retains the status it had.
+	Status     dexorder.MatchStatus `json:"status"`
+	Refunded   bool                 `json:"refunded"`
+	Revoked    bool                 `json:"revoked"`
+	Outcome    swapOutcome          `json:"outcome"`
+	Reported   bool                 `json:"reported"`
+	SecretHash dex.Bytes            `json:"secretHash"`
+	// Secret is generated by the maker, and learned by the taker when the
+	// maker redeems.
+	Secret dex.Bytes `json:"secret,omitempty"`
+
+	OurAddr      string    `json:"ourAddr"`
+	AddrSent     bool      `json:"addrSent"`
+	OurCoinID    dex.Bytes `json:"ourCoinID,omitempty"`
+	OurContract  dex.Bytes `json:"ourContract,omitempty"`
+	ContractSent bool      `json:"contractSent"`
+	RedeemCoinID dex.Bytes `json:"redeemCoinID,omitempty"`
+	RedeemSent   bool      `json:"redeemSent"`
+	RefundCoinID dex.Bytes `json:"refundCoinID,omitempty"`
+
+	TheirAddr     string    `json:"theirAddr"`
+	TheirCoinID   dex.Bytes `json:"theirCoinID,omitempty"`
+	TheirContract dex.Bytes `json:"theirContract,omitempty"`
+}
+
+// lockTime is the lock time of the maker's or taker's contract.
+func (s *swapState) lockTime(net dex.Network, maker bool) time.Time {
+	if maker {
+		return s.Match.Stamp.Add(dex.LockTimeMaker(net))
+	}
+	return s.Match.Stamp.Add(dex.LockTimeTaker(net))
+}
+
+// finished is true if the swap requires no more on-chain action.
+func (s *swapState) finished() bool {
+	return s.Status == dexorder.MatchComplete || s.Refunded || s.Revoked
+}
+
+// done is true if the swap is finished and the outcome is reported.
+func (s *swapState) done() bool {
+	return s.finished() && s.Outcome != outcomePending && (s.Reported || s.Outcome == outcomeNeutral)
+}
+
+// swap is an active swap.
+type swap struct {
+	// procMtx guards processing and rerun. Only one goroutine processes the
+	// swap at a time. rerun is set if processing is requested while the swap
+	// is being processed, and the processing goroutine then runs again.
+	procMtx    sync.Mutex
+	processing bool
+	rerun      bool
+
+	mtx sync.Mutex
+	swapState
+	// audit is the audit of the counterparty's contract. It is not persisted
+	// and is repeated after a restart.
+	audit *asset.AuditInfo
+	// searching is true while we are searching for the maker's redemption of
+	// our contract.
+	searching bool
+	// emitted is the last SwapUpdate emitted for the swap.
+	emitted *SwapUpdate
+}
+
+// SwapUpdate is emitted when a swap begins and whenever its state changes.
+type SwapUpdate struct {
+	MatchID tanka.ID32
+	// OrderID is the ID of our order.
+	OrderID      tanka.ID40
+	Counterparty tanka.PeerID
+	Maker        bool
+	BaseID       uint32
+	QuoteID      uint32
+	Qty          uint64
+	Rate         uint64
+	Stamp        time.Time
+	Status       dexorder.MatchStatus
+	Refunded     bool
+	Revoked      bool
+	// CounterAddr is the counterparty's redemption address.
+	CounterAddr       string
+	SwapCoinID        dex.Bytes
+	Contract          dex.Bytes
+	CounterSwapCoinID dex.Bytes
+	CounterContract   dex.Bytes
+	RedeemCoinID      dex.Bytes
+	RefundCoinID      dex.Bytes
+	SecretHash        dex.Bytes
+	Secret            dex.Bytes
+}
+
+// changed is true if any of the fields that change during a swap differ.
+func (u *SwapUpdate) changed(prev *SwapUpdate) bool {
+	return prev == nil || u.Status != prev.Status || u.Refunded != prev.Refunded ||
+		u.Revoked != prev.Revoked || u.CounterAddr != prev.CounterAddr ||
+		!bytes.Equal(u.SwapCoinID, prev.SwapCoinID) ||
+		!bytes.Equal(u.CounterSwapCoinID, prev.CounterSwapCoinID) ||
+		!bytes.Equal(u.RedeemCoinID, prev.RedeemCoinID) ||
+		!bytes.Equal(u.RefundCoinID, prev.RefundCoinID) ||
+		!bytes.Equal(u.SecretHash, prev.SecretHash) ||
+		!bytes.Equal(u.Secret, prev.Secret)
+}
+
+// update generates a SwapUpdate for the current state.
+func (s *swapState) update() *SwapUpdate {
+	return &SwapUpdate{
+		MatchID:           s.Match.ID(),
+		OrderID:           s.OrderID,
+		Counterparty:      s.Counterparty,
+		Maker:             s.Maker,
+		BaseID:            s.Match.BaseID,
+		QuoteID:           s.Match.QuoteID,
+		Qty:               s.Match.Qty,
+		Rate:              s.Rate,
+		Stamp:             s.Match.Stamp,
+		Status:            s.Status,
+		Refunded:          s.Refunded,
+		Revoked:           s.Revoked,
+		CounterAddr:       s.TheirAddr,
+		SwapCoinID:        s.OurCoinID,
+		Contract:          s.OurContract,
+		CounterSwapCoinID: s.TheirCoinID,
+		CounterContract:   s.TheirContract,
+		RedeemCoinID:      s.RedeemCoinID,
+		RefundCoinID:      s.RefundCoinID,
+		SecretHash:        s.SecretHash,
+		Secret:            s.Secret,
+	}
+}
+
+// peerOutcomes is a tally of swap outcomes with a peer.
+type peerOutcomes struct {
+	Successes uint32 `json:"successes"`
+	Failures  uint32 `json:"failures"`
+}
+
+// score is the score reported for the peer.
+func (o *peerOutcomes) score() int8 {
+	s := int64(o.Successes) - int64(o.Failures)*failurePenalty
+	switch {
+	case s > math.MaxInt8:
+		return math.MaxInt8
+	case s < math.MinInt8:
+		return math.MinInt8
+	}
+	return int8(s)
+}
+
+// beginSwap begins the swap for a negotiated match. makerOrd is the order that
+// the match is against, and oid is the ID of our order.
+func (m *Mesh) beginSwap(oid tanka.ID40, makerOrd *tanka.Order, match *tanka.Match, maker bool) {
+	mid := match.ID()
+	m.swapsMtx.Lock()
+	if _, exists := m.swaps[mid]; exists {
+		m.swapsMtx.Unlock()
+		return
+	}
+	s := &swap{swapState: swapState{
+		Match:        match,
+		OrderID:      oid,
+		Counterparty: match.From,
+		Maker:        maker,
+		Rate:         makerOrd.Rate,
+		Status:       dexorder.NewlyMatched,
+	}}
+	if !maker {
+		s.Counterparty = makerOrd.From
+	}
+	baseQty, quoteQty := match.Qty, calc.BaseToQuote(makerOrd.Rate, match.Qty)
+	// The maker sells if their order is a sell order.
+	if makerOrd.Sell == maker {
+		s.SendAsset, s.SendQty, s.RecvAsset, s.RecvQty = match.BaseID, baseQty, match.QuoteID, quoteQty
+	} else {
+		s.SendAsset, s.SendQty, s.RecvAsset, s.RecvQty = match.QuoteID, quoteQty, match.BaseID, baseQty
+	}
+	if maker {
+		s.Secret = encode.RandomBytes(32)
+		secretHash := sha256.Sum256(s.Secret)
+		s.SecretHash = secretHash[:]
+	}
+	if err := m.saveSwap(s); err != nil {
+		m.swapsMtx.Unlock()
+		m.log.Errorf("Error storing new swap for match %s: %v", mid, err)
+		return
+	}
+	m.swaps[mid] = s
+	m.swapsMtx.Unlock()
+
+	m.log.Infof("Beginning swap for match %s with %s as %s", mid, s.Counterparty, roleName(maker))
+
+	go m.processSwap(s)
+}
+
+func roleName(maker bool) string {
+	if maker {
+		return "maker"
+	}
+	return "taker"
+}
+
+// saveSwap stores the swap in the database. The swap's mtx must be held, or
+// the swap must not yet be shared.
+func (m *Mesh) saveSwap(s *swap) error {
+	mid := s.Match.ID()
+	return m.swapTable.Set(mid[:], lexi.JSON(&s.swapState), lexi.WithReplace())
+}
+
+// loadSwaps loads the unfinished swaps from the database.
+func (m *Mesh) loadSwaps() error {
+	m.swapsMtx.Lock()
+	defer m.swapsMtx.Unlock()
+	return m.swapTable.Iterate(nil, func(it *lexi.Iter) error {
+		s := new(swap)
+		if err := it.V(func(vB []byte) error {
+			return json.Unmarshal(vB, &s.swapState)
+		}); err != nil {
+			return err
+		}
+		if s.done() {
+			return nil
+		}
+		m.swaps[s.Match.ID()] = s
+		return nil
+	})
+}
+
+// activeSwap returns the active swap for the match, if the peer is the
+// counterparty.
+func (m *Mesh) activeSwap(peerID tanka.PeerID, mid tanka.ID32) *swap {
+	m.swapsMtx.RLock()
+	s, found := m.swaps[mid]
+	m.swapsMtx.RUnlock()
+	if !found || s.Counterparty != peerID {
+		return nil
+	}
+	return s
+}
+
+// runSwaps processes the active swaps periodically until the context is
+// canceled.
+func (m *Mesh) runSwaps(ctx context.Context) {
+	tick := time.NewTicker(swapTick)
+	defer tick.Stop()
+	for {
+		m.swapsMtx.RLock()
+		swaps := make([]*swap, 0, len(m.swaps))
+		for _, s := range m.swaps {
+			swaps = append(swaps, s)
+		}
+		m.swapsMtx.RUnlock()
+		for _, s := range swaps {
+			go m.processSwap(s)
+		}
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processSwap advances the swap as far as possible, and sends any pending
+// messages to the counterparty.
+func (m *Mesh) processSwap(s *swap) {
+	s.procMtx.Lock()
+	if s.processing {
+		s.rerun = true
+		s.procMtx.Unlock()
+		return
+	}
+	s.processing = true
+	s.procMtx.Unlock()
+	var done bool
+	for {
+		s.mtx.Lock()
+		m.advanceSwap(s)
+		s.mtx.Unlock()
+		m.sendSwapMessages(s)
+		s.mtx.Lock()
+		if u := s.update(); u.changed(s.emitted) {
+			s.emitted = u
+			m.emit(u)
+		}
+		done = s.done()
+		s.mtx.Unlock()
+		// Clearing processing under the same lock that a new request checks
+		// ensures that a request is either handled here or starts its own run.
+		s.procMtx.Lock()
+		if !s.rerun {
+			s.processing = false
+			s.procMtx.Unlock()
+			break
+		}
+		s.rerun = false
+		s.procMtx.Unlock()
+	}
+	if done {
+		mid := s.Match.ID()
+		m.swapsMtx.Lock()
+		delete(m.swaps, mid)
+		m.swapsMtx.Unlock()
+		m.log.Infof("Swap for match %s is done", mid)
+	}
+}
+
+// advanceSwap performs any on-chain actions that the swap's state allows. The
+// swap's mtx must be held.
+func (m *Mesh) advanceSwap(s *swap) {
+	if s.finished() {
+		return
+	}
+	mid := s.Match.ID()
+	sendWallet, recvWallet := m.wallet(s.SendAsset), m.wallet(s.RecvAsset)
+	if sendWallet == nil || recvWallet == nil {
+		m.log.Warnf("Cannot process swap for match %s. Missing %s or %s wallet",
+			mid, dex.BipIDSymbol(s.SendAsset), dex.BipIDSymbol(s.RecvAsset))
+		return
+	}
+
+	save := func() {
+		if err := m.saveSwap(s); err != nil {
+			m.log.Errorf("Error storing swap for match %s: %v", mid, err)
+		}
+	}
+
+	if s.OurAddr == "" {
+		addr, err := recvWallet.RedemptionAddress()
+		if err != nil {
+			m.log.Errorf("Error getting %s redemption address for match %s: %v", dex.BipIDSymbol(s.RecvAsset), mid, err)
+			return
+		}
+		s.OurAddr = addr
+		save()
+	}
+
+	if s.Maker {
+		m.advanceMakerSwap(s, sendWallet, recvWallet)
+	} else {
+		m.advanceTakerSwap(s, sendWallet, recvWallet)
+	}
+	save()
+}
+
+// advanceMakerSwap advances the swap when we are the maker. The swap's mtx
+// must be held.
+func (m *Mesh) advanceMakerSwap(s *swap, sendWallet, recvWallet *swapWallet) {
+	mid := s.Match.ID()
+	if s.Status == dexorder.MakerRedeemed {
+		// The taker can find our redemption on-chain if they never receive
+		// it from us.
+		if time.Now().After(s.lockTime(m.net, false)) {
+			s.Status = dexorder.MatchComplete
+			m.setOutcome(s, outcomeSuccess)
+		}
+		return
+	}
+	if s.Status == dexorder.NewlyMatched {
+		if s.TheirAddr == "" {
+			if time.Now().After(s.lockTime(m.net, false)) {
+				m.log.Infof("Revoking match %s. Taker never sent an address", mid)
+				s.Revoked = true
+				m.setOutcome(s, outcomeFailure)
+			}
+			return
+		}
+		if err := m.swap(s, sendWallet, s.lockTime(m.net, true)); err != nil {
+			m.log.Errorf("Error broadcasting swap for match %s: %v", mid, err)
+			return
+		}
+		s.Status = dexorder.MakerSwapCast
+	}
+
+	if s.Status == dexorder.MakerSwapCast && s.TheirCoinID != nil {
+		ok, err := m.auditCounterparty(s, recvWallet)
+		if err != nil {
+			m.log.Errorf("Error auditing taker's contract for match %s: %v", mid, err)
+		} else if !ok {
+			// We'll refund when our contract expires.
+			s.TheirCoinID, s.TheirContract = nil, nil
+			m.setOutcome(s, outcomeFailure)
+		} else if m.counterpartyConfirmed(s, recvWallet) {
+			s.Status = dexorder.TakerSwapCast
+		}
+	}
+
+	if s.Status == dexorder.TakerSwapCast {
+		if err := m.redeem(s, recvWallet); err != nil {
+			m.log.Errorf("Error redeeming taker's contract for match %s: %v", mid, err)
+		} else {
+			s.Status = dexorder.MakerRedeemed
+			return
+		}
+	}
+
+	if s.Status == dexorder.MakerSwapCast || s.Status == dexorder.TakerSwapCast {
+		m.refundIfExpired(s, sendWallet)
+	}
+}
+
+// advanceTakerSwap advances the swap when we are the taker. The swap's mtx
+// must be held.
+func (m *Mesh) advanceTakerSwap(s *swap, sendWallet, recvWallet *swapWallet) {
+	mid := s.Match.ID()
+	takerLockTime := s.lockTime(m.net, false)
+	if s.Status == dexorder.NewlyMatched {
+		if s.TheirCoinID == nil {
+			if time.Now().After(takerLockTime) {
+				m.log.Infof("Revoking match %s. Maker never sent a contract", mid)
+				s.Revoked = true
+				m.setOutcome(s, outcomeFailure)
+			}
+			return
+		}
+		ok, err := m.auditCounterparty(s, recvWallet)
+		if err != nil {
+			m.log.Errorf("Error auditing maker's contract for match %s: %v", mid, err)
+			return
+		}
+		if !ok {
+			m.log.Infof("Revoking match %s. Maker's contract failed audit", mid)
+			s.Revoked = true
+			m.setOutcome(s, outcomeFailure)
+			return
+		}
+		s.Status = dexorder.MakerSwapCast
+	}
+
+	if s.Status == dexorder.MakerSwapCast {
+		if time.Now().After(takerLockTime) {
+			m.log.Infof("Revoking match %s. Too late to swap", mid)
+			s.Revoked = true
+			m.setOutcome(s, outcomeNeutral)
+			return
+		}
+		if !m.counterpartyConfirmed(s, recvWallet) {
+			return
+		}
+		if err := m.swap(s, sendWallet, takerLockTime); err != nil {
+			m.log.Errorf("Error broadcasting swap for match %s: %v", mid, err)
+			return
+		}
+		s.Status = dexorder.TakerSwapCast
+	}
+
+	if s.Status == dexorder.TakerSwapCast {
+		if s.Secret == nil {
+			m.findRedemption(s, sendWallet)
+			m.refundIfExpired(s, sendWallet)
+			return
+		}
+		s.Status = dexorder.MakerRedeemed
+	}
+
+	if s.Status == dexorder.MakerRedeemed {
+		if err := m.redeem(s, recvWallet); err != nil {
+			m.log.Errorf("Error redeeming maker's contract for match %s: %v", mid, err)
+			return
+		}
+		s.Status = dexorder.MatchComplete
+		m.setOutcome(s, outcomeSuccess)
+		m.log.Infof("Swap for match %s complete", mid)
+	}
+}
+
+// feeRate is the fee rate to use for the asset's transactions.
+func (m *Mesh) feeRate(assetID uint32, w *swapWallet) uint64 {
+	if feeRate := m.FeeRateEstimate(assetID); feeRate > 0 {
+		return feeRate
+	}
+	if rater, is := w.SwapWallet.(asset.FeeRater); is {
+		return rater.FeeRate()
+	}
+	return 0
+}
+
+// swap funds and broadcasts our swap contract. The swap's mtx must be held.
+func (m *Mesh) swap(s *swap, w *swapWallet, lockTime time.Time) error {
+	feeRate := m.feeRate(s.SendAsset, w)
+	ver := w.version()
+	coins, _, _, err := w.FundOrder(&asset.Order{
+		AssetVersion:  ver,
+		Value:         s.SendQty,
+		MaxSwapCount:  1,
+		MaxFeeRate:    feeRate,
+		FeeSuggestion: feeRate,
+		RedeemAssetID: s.RecvAsset,
+	})
+	if err != nil {
+		return fmt.Errorf("error funding swap: %w", err)
+	}
+	receipts, _, _, err := w.Swap(m.ctx, &asset.Swaps{
+		AssetVersion: ver,
+		Inputs:       coins,
+		Contracts: []*asset.Contract{{
+			Address:    s.TheirAddr,
+			Value:      s.SendQty,
+			SecretHash: s.SecretHash,
+			LockTime:   uint64(lockTime.Unix()),
+		}},
+		FeeRate: feeRate,
+	})
+	if err != nil {
+		if err := w.ReturnCoins(coins); err != nil {
+			m.log.Errorf("Error returning coins for match %s: %v", s.Match.ID(), err)
+		}
+		return err
+	}
+	if len(receipts) != 1 {
+		// Nothing was recorded for the swap, so return the coins rather than
+		// leaving them locked.
+		if err := w.ReturnCoins(coins); err != nil {
+			m.log.Errorf("Error returning coins for match %s: %v", s.Match.ID(), err)
+		}
+		return fmt.Errorf("expected 1 swap receipt, got %d", len(receipts))
+	}
+	s.OurCoinID = receipts[0].Coin().ID()
+	s.OurContract = receipts[0].Contract()
+	m.log.Infof("Broadcast %s swap for match %s, coin %s", dex.BipIDSymbol(s.SendAsset), s.Match.ID(), receipts[0].Coin())
+	return nil
+}
+
+// auditCounterparty audits the counterparty's contract. An error is returned
+// if the audit could not be performed, e.g. the transaction is not yet found.
+// The swap's mtx must be held.
+func (m *Mesh) auditCounterparty(s *swap, w *swapWallet) (bool, error) {
+	if s.audit != nil {
+		return true, nil
+	}
+	ai, err := w.AuditContract(s.TheirCoinID, s.TheirContract, nil, true)
+	if err != nil {
+		return false, err
+	}
+	mid := s.Match.ID()
+	lockTime := s.lockTime(m.net, !s.Maker)
+	switch {
+	case ai.Recipient != s.OurAddr:
+		m.log.Errorf("Audit failed for match %s: wrong recipient %s", mid, ai.Recipient)
+		return false, nil
+	case ai.Coin.Value() < s.RecvQty:
+		m.log.Errorf("Audit failed for match %s: value %d < %d", mid, ai.Coin.Value(), s.RecvQty)
+		return false, nil
+	case !bytes.Equal(ai.SecretHash, s.SecretHash):
+		m.log.Errorf("Audit failed for match %s: wrong secret hash %x", mid, ai.SecretHash)
+		return false, nil
+	case ai.Expiration.Unix() < lockTime.Unix():
+		m.log.Errorf("Audit failed for match %s: lock time %s is earlier than %s", mid, ai.Expiration, lockTime)
+		return false, nil
+	}
+	s.audit = ai
+	return true, nil
+}
+
+// counterpartyConfirmed checks whether the counterparty's audited contract has
+// the required number of confirmations. The swap's mtx must be held.
+func (m *Mesh) counterpartyConfirmed(s *swap, w *swapWallet) bool {
+	if _, err := m.auditCounterparty(s, w); err != nil || s.audit == nil {
+		return false
+	}
+	confs, _, err := w.SwapConfirmations(m.ctx, s.TheirCoinID, s.audit.Contract, s.Match.Stamp)
+	if err != nil {
+		m.log.Errorf("Error checking confirmations of counterparty's contract for match %s: %v", s.Match.ID(), err)
+		return false
+	}
+	return confs >= w.swapConf
+}
+
+// redeem redeems the counterparty's contract. The swap's mtx must be held.
+func (m *Mesh) redeem(s *swap, w *swapWallet) error {
+	if _, err := m.auditCounterparty(s, w); err != nil || s.audit == nil {
+		return fmt.Errorf("counterparty contract not audited: %v", err)
+	}
+	_, out, _, err := w.Redeem(m.ctx, &asset.RedeemForm{
+		Redemptions: []*asset.Redemption{{
+			Spends: s.audit,
+			Secret: s.Secret,
+		}},
+		FeeSuggestion: m.feeRate(s.RecvAsset, w),
+	})
+	if err != nil {
+		return err
+	}
+	s.RedeemCoinID = out.ID()
+	m.log.Infof("Redeemed %s swap for match %s, coin %s", dex.BipIDSymbol(s.RecvAsset), s.Match.ID(), out)
+	return nil
+}
+
+// refundIfExpired refunds our contract if the lock time has expired and the
+// contract is unspent. The swap's mtx must be held.
+func (m *Mesh) refundIfExpired(s *swap, w *swapWallet) {
+	mid := s.Match.ID()
+	expired, _, err := w.ContractLockTimeExpired(m.ctx, s.OurContract)
+	if err != nil {
+		m.log.Errorf("Error checking lock time of our contract for match %s: %v", mid, err)
+		return
+	}
+	if !expired {
+		return
+	}
+	_, spent, err := w.SwapConfirmations(m.ctx, s.OurCoinID, s.OurContract, s.Match.Stamp)
+	if err != nil {
+		m.log.Errorf("Error checking our contract for match %s: %v", mid, err)
+		return
+	}
+	if spent {
+		// The counterparty redeemed. As taker, we'll find the secret.
+		return
+	}
+	refundCoin, err := w.Refund(m.ctx, s.OurCoinID, s.OurContract, m.feeRate(s.SendAsset, w))
+	if err != nil {
+		m.log.Errorf("Error refunding our contract for match %s: %v", mid, err)
+		return
+	}
+	s.RefundCoinID = refundCoin
+	s.Refunded = true
+	// A maker that audited the taker's contract but could not redeem it
+	// has nobody to blame.
+	if s.Maker && s.Status == dexorder.TakerSwapCast {
+		m.setOutcome(s, outcomeNeutral)
+	} else {
+		m.setOutcome(s, outcomeFailure)
+	}
+	m.log.Infof("Refunded %s swap for match %s", dex.BipIDSymbol(s.SendAsset), mid)
+}
+
+// findRedemption searches for the maker's redemption of our contract in the
+// background. The swap's mtx must be held.
+func (m *Mesh) findRedemption(s *swap, w *swapWallet) {
+	if s.searching {
+		return
+	}
+	s.searching = true
+	coinID, contract := s.OurCoinID, s.OurContract
+	go func() {
+		_, secret, err := w.FindRedemption(m.ctx, coinID, contract)
+		s.mtx.Lock()
+		s.searching = false
+		found := err == nil && m.setSecret(s, secret)
+		s.mtx.Unlock()
+		if err != nil {
+			if !errors.Is(err, context.Canceled) {
+				m.log.Errorf("Error searching for redemption of our contract for match %s: %v", s.Match.ID(), err)
+			}
+			return
+		}
+		if found {
+			m.processSwap(s)
+		}
+	}()
+}
+
+// setSecret sets the secret learned from the maker if it matches the secret
+// hash. The swap's mtx must be held.
+func (m *Mesh) setSecret(s *swap, secret dex.Bytes) bool {
+	if s.Secret != nil {
+		return true
+	}
+	secretHash := sha256.Sum256(secret)
+	if !bytes.Equal(secretHash[:], s.SecretHash) {
+		m.log.Errorf("Received invalid secret for match %s", s.Match.ID())
+		return false
+	}
+	s.Secret = secret
+	if err := m.saveSwap(s); err != nil {
+		m.log.Errorf("Error storing secret for match %s: %v", s.Match.ID(), err)
+	}
+	return true
+}
+
+// setOutcome sets the outcome of the swap and records it in the tally of the
+// counterparty's outcomes. Only the first outcome is recorded. The swap's mtx
+// must be held.
+func (m *Mesh) setOutcome(s *swap, outcome swapOutcome) {
+	if s.Outcome != outcomePending {
+		return
+	}
+	s.Outcome = outcome
+	if outcome == outcomeNeutral {
+		return
+	}
+	m.outcomesMtx.Lock()
+	defer m.outcomesMtx.Unlock()
+	tally, err := m.peerOutcomes(s.Counterparty)
+	if err != nil {
+		m.log.Errorf("Error loading outcomes for peer %s: %v", s.Counterparty, err)
+		return
+	}
+	if outcome == outcomeSuccess {
+		tally.Successes++
+	} else {
+		tally.Failures++
+	}
+	if err := m.outcomeTable.Set(s.Counterparty[:], lexi.JSON(tally), lexi.WithReplace()); err != nil {
+		m.log.Errorf("Error storing outcomes for peer %s: %v", s.Counterparty, err)
+	}
+}
+
+// peerOutcomes loads the tally of swap outcomes with the peer.
+func (m *Mesh) peerOutcomes(peerID tanka.PeerID) (*peerOutcomes, error) {
+	tally := new(peerOutcomes)
+	if err := m.outcomeTable.Get(peerID[:], lexi.JSON(tally)); err != nil && !errors.Is(err, lexi.ErrKeyNotFound) {
+		return nil, err
+	}
+	return tally, nil
+}
+
+// swapMessage is a message to be sent to the counterparty.
+type swapMessage struct {
+	route   string
+	payload any
+	// sent updates the swap state after the message is acknowledged.
+	sent func()
+}
+
+// sendSwapMessages sends the messages that the swap's state calls for, and
+// reports the outcome to the mesh.
+func (m *Mesh) sendSwapMessages(s *swap) {
+	mid := s.Match.ID()
+	var msgs []*swapMessage
+	s.mtx.Lock()
+	if s.OurAddr != "" && !s.AddrSent && !s.finished() {
+		msgs = append(msgs, &swapMessage{
+			route:   mj.RouteSwapAddress,
+			payload: &mj.SwapAddress{MatchID: mid, Address: s.OurAddr},
+			sent:    func() { s.AddrSent = true },
+		})
+	}
+	if s.OurCoinID != nil && !s.ContractSent && !s.Refunded {
+		msgs = append(msgs, &swapMessage{
+			route: mj.RouteSwapContract,
+			payload: &mj.SwapContract{
+				MatchID:    mid,
+				CoinID:     s.OurCoinID,
+				Contract:   s.OurContract,
+				SecretHash: s.SecretHash,
+			},
+			sent: func() { s.ContractSent = true },
+		})
+	}
+	if s.Maker && s.RedeemCoinID != nil && !s.RedeemSent {
+		msgs = append(msgs, &swapMessage{
+			route:   mj.RouteSwapRedeem,
+			payload: &mj.SwapRedeem{MatchID: mid, CoinID: s.RedeemCoinID, Secret: s.Secret},
+			sent: func() {
+				s.RedeemSent = true
+				s.Status = dexorder.MatchComplete
+				m.setOutcome(s, outcomeSuccess)
+				m.log.Infof("Swap for match %s complete", mid)
+			},
+		})
+	}
+	reportOutcome := !s.Reported && (s.Outcome == outcomeSuccess || s.Outcome == outcomeFailure)
+	s.mtx.Unlock()
+
+	for _, msg := range msgs {
+		if err := m.requestSwapPeer(s.Counterparty, msg.route, msg.payload); err != nil {
+			m.log.Errorf("Error sending %s for match %s to %s: %v", msg.route, mid, s.Counterparty, err)
+			break
+		}
+		s.mtx.Lock()
+		msg.sent()
+		if err := m.saveSwap(s); err != nil {
+			m.log.Errorf("Error storing swap for match %s: %v", mid, err)
+		}
+		reportOutcome = !s.Reported && (s.Outcome == outcomeSuccess || s.Outcome == outcomeFailure)
+		s.mtx.Unlock()
+	}
+
+	if !reportOutcome {
+		return
+	}
+	m.outcomesMtx.Lock()
+	tally, err := m.peerOutcomes(s.Counterparty)
+	m.outcomesMtx.Unlock()
+	if err != nil {
+		m.log.Errorf("Error loading outcomes for peer %s: %v", s.Counterparty, err)
+		return
+	}
+	report := &tanka.ScoreReport{
+		Scorer: m.peerID,
+		Scored: s.Counterparty,
+		Score:  tally.score(),
+		Stamp:  time.Now(),
+	}
+	report.Sign(m.priv)
+	note := mj.MustNotification(mj.RouteSetScore, &mj.ScoreReport{
+		PeerID: report.Scored,
+		Score:  report.Score,
+		Stamp:  report.Stamp,
+		Sig:    report.Sig,
+	})
+	if err := m.conn.NotifyMesh(note); err != nil {
+		m.log.Errorf("Error reporting score for peer %s: %v", s.Counterparty, err)
+		return
+	}
+	s.mtx.Lock()
+	s.Reported = true
+	if err := m.saveSwap(s); err != nil {
+		m.log.Errorf("Error storing swap for match %s: %v", mid, err)
+	}
+	s.mtx.Unlock()
+}
+
+// requestSwapPeer sends a swap message to the counterparty, reestablishing
+// the encrypted connection if necessary.
+func (m *Mesh) requestSwapPeer(peerID tanka.PeerID, route string, payload any) error {
+	var ok bool
+	err := m.conn.RequestPeer(peerID, mj.MustRequest(route, payload), &ok)
+	if err != nil {
+		if err := m.conn.ConnectPeer(peerID); err != nil {
+			return err
+		}
+		err = m.conn.RequestPeer(peerID, mj.MustRequest(route, payload), &ok)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("counterparty rejected message")
+	}
+	return nil
+}
+
+func (m *Mesh) handleSwapAddress(peerID tanka.PeerID, payload json.RawMessage, respond func(any, mj.TankagramError)) {
+	var msg mj.SwapAddress
+	if err := json.Unmarshal(payload, &msg); err != nil || msg.Address == "" {
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s := m.activeSwap(peerID, msg.MatchID)
+	if s == nil {
+		m.log.Debugf("Received swap address for unknown match %s from %s", msg.MatchID, peerID)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	if s.TheirAddr != "" && s.TheirAddr != msg.Address {
+		s.mtx.Unlock()
+		m.log.Errorf("Peer %s attempted to change their address for match %s", peerID, msg.MatchID)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s.TheirAddr = msg.Address
+	err := m.saveSwap(s)
+	s.mtx.Unlock()
+	if err != nil {
+		m.log.Errorf("Error storing swap for match %s: %v", msg.MatchID, err)
+		respond(false, mj.TEEPeerError)
+		return
+	}
+	respond(true, mj.TEErrNone)
+	go m.processSwap(s)
+}
+
+func (m *Mesh) handleSwapContract(peerID tanka.PeerID, payload json.RawMessage, respond func(any, mj.TankagramError)) {
+	var msg mj.SwapContract
+	if err := json.Unmarshal(payload, &msg); err != nil || len(msg.CoinID) == 0 || len(msg.Contract) == 0 {
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s := m.activeSwap(peerID, msg.MatchID)
+	if s == nil {
+		m.log.Debugf("Received swap contract for unknown match %s from %s", msg.MatchID, peerID)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	switch {
+	case s.finished():
+		s.mtx.Unlock()
+		respond(false, mj.TEEBadRequest)
+		return
+	case s.TheirCoinID != nil:
+		// Already have it.
+		s.mtx.Unlock()
+		respond(true, mj.TEErrNone)
+		return
+	case s.Maker && (s.Status != dexorder.MakerSwapCast || !bytes.Equal(msg.SecretHash, s.SecretHash)):
+		s.mtx.Unlock()
+		respond(false, mj.TEEBadRequest)
+		return
+	case !s.Maker:
+		if len(msg.SecretHash) != sha256.Size {
+			s.mtx.Unlock()
+			respond(false, mj.TEEBadRequest)
+			return
+		}
+		s.SecretHash = msg.SecretHash
+	}
+	s.TheirCoinID, s.TheirContract = msg.CoinID, msg.Contract
+	err := m.saveSwap(s)
+	s.mtx.Unlock()
+	if err != nil {
+		m.log.Errorf("Error storing swap for match %s: %v", msg.MatchID, err)
+		respond(false, mj.TEEPeerError)
+		return
+	}
+	respond(true, mj.TEErrNone)
+	go m.processSwap(s)
+}
+
+func (m *Mesh) handleSwapRedeem(peerID tanka.PeerID, payload json.RawMessage, respond func(any, mj.TankagramError)) {
+	var msg mj.SwapRedeem
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s := m.activeSwap(peerID, msg.MatchID)
+	if s == nil || s.Maker {
+		m.log.Debugf("Received unexpected redemption for match %s from %s", msg.MatchID, peerID)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	s.mtx.Lock()
+	ok := s.SecretHash != nil && m.setSecret(s, msg.Secret)
+	s.mtx.Unlock()
+	if !ok {
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	respond(true, mj.TEErrNone)
+	go m.processSwap(s)
+}
@@ -0,0 +1,560 @@
+package mesh
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/client/asset"
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/fiatrates"
+	"decred.org/dcrdex/dex/lexi"
+	dexorder "decred.org/dcrdex/dex/order"
+	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/tatanka"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+var tLogger = dex.StdOutLogger("T", dex.LevelInfo)
+
+type tCoin struct {
+	id    dex.Bytes
+	value uint64
+}
+
+func (c *tCoin) ID() dex.Bytes  { return c.id }
+func (c *tCoin) String() string { return c.id.String() }
+func (c *tCoin) Value() uint64  { return c.value }
+func (c *tCoin) TxID() string   { return c.id.String() }
+func newTCoin(v uint64) *tCoin  { return &tCoin{id: encode.RandomBytes(32), value: v} }
+
+type tReceipt struct {
+	coin       *tCoin
+	expiration time.Time
+}
+
+func (r *tReceipt) Expiration() time.Time   { return r.expiration }
+func (r *tReceipt) Coin() asset.Coin        { return r.coin }
+func (r *tReceipt) Contract() dex.Bytes     { return r.coin.id }
+func (r *tReceipt) String() string          { return r.coin.String() }
+func (r *tReceipt) SignedRefund() dex.Bytes { return nil }
+
+type tContract struct {
+	recipient  string
+	value      uint64
+	secretHash dex.Bytes
+	lockTime   time.Time
+	secret     dex.Bytes
+	refunded   bool
+}
+
+// tChain is a simulated blockchain shared by the wallets of both clients. The
+// contract data is the contract's coin ID.
+type tChain struct {
+	mtx       sync.Mutex
+	contracts map[string]*tContract
+}
+
+func newTChain() *tChain {
+	return &tChain{contracts: make(map[string]*tContract)}
+}
+
+func (c *tChain) contract(coinID dex.Bytes) (*tContract, error) {
+	ct, found := c.contracts[hex.EncodeToString(coinID)]
+	if !found {
+		return nil, asset.CoinNotFoundError
+	}
+	return ct, nil
+}
+
+type tWallet struct {
+	chain *tChain
+	addr  string
+	// extraReceipts are added to the receipts returned by Swap.
+	extraReceipts int
+	returned      atomic.Int32
+}
+
+var _ SwapWallet = (*tWallet)(nil)
+
+func newTWallet(chain *tChain) *tWallet {
+	return &tWallet{chain: chain, addr: hex.EncodeToString(encode.RandomBytes(20))}
+}
+
+func (w *tWallet) Info() *asset.WalletInfo {
+	return &asset.WalletInfo{SupportedVersions: []uint32{0}}
+}
+
+func (w *tWallet) FundOrder(ord *asset.Order) (asset.Coins, []dex.Bytes, uint64, error) {
+	return asset.Coins{newTCoin(ord.Value)}, []dex.Bytes{nil}, 0, nil
+}
+
+func (w *tWallet) ReturnCoins(asset.Coins) error {
+	w.returned.Add(1)
+	return nil
+}
+
+func (w *tWallet) Swap(_ context.Context, swaps *asset.Swaps) ([]asset.Receipt, asset.Coin, uint64, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	receipts := make([]asset.Receipt, 0, len(swaps.Contracts))
+	for _, c := range swaps.Contracts {
+		coin := newTCoin(c.Value)
+		lockTime := time.Unix(int64(c.LockTime), 0)
+		w.chain.contracts[hex.EncodeToString(coin.id)] = &tContract{
+			recipient:  c.Address,
+			value:      c.Value,
+			secretHash: c.SecretHash,
+			lockTime:   lockTime,
+		}
+		receipts = append(receipts, &tReceipt{coin: coin, expiration: lockTime})
+	}
+	for i := 0; i < w.extraReceipts; i++ {
+		receipts = append(receipts, &tReceipt{coin: newTCoin(0)})
+	}
+	return receipts, nil, 0, nil
+}
+
+func (w *tWallet) Redeem(_ context.Context, form *asset.RedeemForm) ([]dex.Bytes, asset.Coin, uint64, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	ins := make([]dex.Bytes, 0, len(form.Redemptions))
+	for _, r := range form.Redemptions {
+		ct, err := w.chain.contract(r.Spends.Coin.ID())
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		secretHash := sha256.Sum256(r.Secret)
+		switch {
+		case ct.recipient != w.addr:
+			return nil, nil, 0, errors.New("not our contract")
+		case !bytes.Equal(secretHash[:], ct.secretHash):
+			return nil, nil, 0, errors.New("wrong secret")
+		case ct.secret != nil || ct.refunded:
+			return nil, nil, 0, errors.New("already spent")
+		}
+		ct.secret = r.Secret
+		ins = append(ins, r.Spends.Coin.ID())
+	}
+	return ins, newTCoin(0), 0, nil
+}
+
+func (w *tWallet) AuditContract(coinID, contract, _ dex.Bytes, _ bool) (*asset.AuditInfo, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	ct, err := w.chain.contract(coinID)
+	if err != nil {
+		return nil, err
+	}
+	return &asset.AuditInfo{
+		Recipient:  ct.recipient,
+		Expiration: ct.lockTime,
+		Coin:       &tCoin{id: coinID, value: ct.value},
+		Contract:   contract,
+		SecretHash: ct.secretHash,
+	}, nil
+}
+
+func (w *tWallet) ContractLockTimeExpired(_ context.Context, contract dex.Bytes) (bool, time.Time, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	ct, err := w.chain.contract(contract)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	return time.Now().After(ct.lockTime), ct.lockTime, nil
+}
+
+func (w *tWallet) FindRedemption(ctx context.Context, coinID, _ dex.Bytes) (dex.Bytes, dex.Bytes, error) {
+	for {
+		w.chain.mtx.Lock()
+		ct, err := w.chain.contract(coinID)
+		var secret dex.Bytes
+		if err == nil {
+			secret = ct.secret
+		}
+		w.chain.mtx.Unlock()
+		if err != nil {
+			return nil, nil, err
+		}
+		if secret != nil {
+			return encode.RandomBytes(32), secret, nil
+		}
+		select {
+		case <-time.After(time.Millisecond * 50):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+}
+
+func (w *tWallet) Refund(_ context.Context, coinID, _ dex.Bytes, _ uint64) (dex.Bytes, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	ct, err := w.chain.contract(coinID)
+	if err != nil {
+		return nil, err
+	}
+	if ct.secret != nil || ct.refunded {
+		return nil, errors.New("already spent")
+	}
+	ct.refunded = true
+	return encode.RandomBytes(32), nil
+}
+
+func (w *tWallet) RedemptionAddress() (string, error) {
+	return w.addr, nil
+}
+
+func (w *tWallet) SwapConfirmations(_ context.Context, coinID, _ dex.Bytes, _ time.Time) (uint32, bool, error) {
+	w.chain.mtx.Lock()
+	defer w.chain.mtx.Unlock()
+	ct, err := w.chain.contract(coinID)
+	if err != nil {
+		return 0, false, err
+	}
+	return 1, ct.secret != nil || ct.refunded, nil
+}
+
+func genKeyPair() (*secp256k1.PrivateKey, tanka.PeerID) {
+	priv, _ := secp256k1.GeneratePrivateKey()
+	var peerID tanka.PeerID
+	copy(peerID[:], priv.PubKey().SerializeCompressed())
+	return priv, peerID
+}
+
+// runTatanka runs a simnet tatanka node with no chains.
+func runTatanka(t *testing.T, ctx context.Context) *TatankaCredentials {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen error: %v", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	dir := t.TempDir()
+	priv, peerID := genKeyPair()
+	if err := os.WriteFile(filepath.Join(dir, "priv.key"), priv.Serialize(), 0600); err != nil {
+		t.Fatalf("error writing key file: %v", err)
+	}
+	cfgPath := filepath.Join(dir, "config.json")
+	rawCfg, _ := json.Marshal(&tatanka.ConfigFile{})
+	if err := os.WriteFile(cfgPath, rawCfg, 0600); err != nil {
+		t.Fatalf("error writing config file: %v", err)
+	}
+
+	tt, err := tatanka.New(&tatanka.Config{
+		Net:     dex.Simnet,
+		DataDir: dir,
+		Logger:  tLogger.SubLogger("SRV"),
+		RPC: comms.RPCConfig{
+			ListenAddrs: []string{addr},
+			NoTLS:       true,
+		},
+		ChainConfig: cfgPath,
+		MaxClients:  10,
+		FiatOracleConfig: fiatrates.Config{
+			DisabledFiatSources: "cryptocompare,binance,coinpaprika,messari,kucoin",
+		},
+	})
+	if err != nil {
+		t.Fatalf("error creating tatanka node: %v", err)
+	}
+	if err := dex.NewConnectionMaster(tt).ConnectOnce(ctx); err != nil {
+		t.Fatalf("error starting tatanka node: %v", err)
+	}
+	return &TatankaCredentials{PeerID: peerID, Addr: addr, NoTLS: true}
+}
+
+func newTestMesh(t *testing.T, ctx context.Context, name string, entryNode *TatankaCredentials, chains map[uint32]*tChain) (*Mesh, map[uint32]*tWallet) {
+	t.Helper()
+	priv, _ := genKeyPair()
+	m, err := New(&Config{
+		DataDir:    t.TempDir(),
+		PrivateKey: priv,
+		Logger:     tLogger.SubLogger(name),
+		EntryNode:  entryNode,
+		Net:        dex.Simnet,
+	})
+	if err != nil {
+		t.Fatalf("error creating %s mesh: %v", name, err)
+	}
+	wallets := make(map[uint32]*tWallet, len(chains))
+	for assetID, chain := range chains {
+		w := newTWallet(chain)
+		wallets[assetID] = w
+		m.AddWallet(assetID, w, 1)
+	}
+	if _, err := m.Connect(ctx); err != nil {
+		t.Fatalf("error connecting %s mesh: %v", name, err)
+	}
+	return m, wallets
+}
+
+func (m *Mesh) storedSwaps(t *testing.T) []*swapState {
+	t.Helper()
+	var swaps []*swapState
+	if err := m.swapTable.Iterate(nil, func(it *lexi.Iter) error {
+		return it.V(func(vB []byte) error {
+			var s swapState
+			if err := json.Unmarshal(vB, &s); err != nil {
+				return err
+			}
+			swaps = append(swaps, &s)
+			return nil
+		})
+	}); err != nil {
+		t.Fatalf("error loading swaps: %v", err)
+	}
+	return swaps
+}
+
+func waitFor(t *testing.T, what string, timeout time.Duration, f func() bool) {
+	t.Helper()
+	deadline := time.After(timeout)
+	for !f() {
+		select {
+		case <-time.After(time.Millisecond * 50):
+		case <-deadline:
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestSwap(t *testing.T) {
+	defer func(d time.Duration) { swapTick = d }(swapTick)
+	swapTick = time.Millisecond * 100
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const baseID, quoteID = 42, 0
+	entryNode := runTatanka(t, ctx)
+	chains := map[uint32]*tChain{baseID: newTChain(), quoteID: newTChain()}
+	maker, makerWallets := newTestMesh(t, ctx, "MAKER", entryNode, chains)
+	taker, takerWallets := newTestMesh(t, ctx, "TAKER", entryNode, chains)
+
+	mktName, _ := dex.MarketName(baseID, quoteID)
+	for _, m := range []*Mesh{maker, taker} {
+		if err := m.SubscribeMarket(baseID, quoteID); err != nil {
+			t.Fatalf("SubscribeMarket error: %v", err)
+		}
+	}
+	marketOf := func(m *Mesh) *market {
+		m.marketsMtx.RLock()
+		defer m.marketsMtx.RUnlock()
+		return m.markets[mktName]
+	}
+	takerMkt := marketOf(taker)
+
+	const qty, lotSize, rate = 1 << 26, 1 << 20, 2 * calc.RateEncodingFactor
+	makerOrd := &tanka.Order{
+		From:    maker.ID(),
+		BaseID:  baseID,
+		QuoteID: quoteID,
+		Sell:    true,
+		Qty:     qty,
+		Rate:    rate,
+		LotSize: lotSize,
+		Nonce:   1,
+		Stamp:   time.Now(),
+	}
+	if err := maker.PlaceOrder(makerOrd); err != nil {
+		t.Fatalf("PlaceOrder error: %v", err)
+	}
+	waitFor(t, "order broadcast", time.Second*10, func() bool {
+		return takerMkt.book.Order(makerOrd.ID()) != nil
+	})
+
+	takerOrd := &tanka.Order{
+		From:    taker.ID(),
+		BaseID:  baseID,
+		QuoteID: quoteID,
+		Qty:     qty,
+		Rate:    rate,
+		LotSize: lotSize,
+		Nonce:   1,
+		Stamp:   time.Now(),
+	}
+	if err := taker.PlaceOrder(takerOrd); err != nil {
+		t.Fatalf("PlaceOrder error: %v", err)
+	}
+
+	complete := func(m *Mesh) func() bool {
+		return func() bool {
+			swaps := m.storedSwaps(t)
+			return len(swaps) == 1 && swaps[0].Status == dexorder.MatchComplete && swaps[0].Reported
+		}
+	}
+	waitFor(t, "maker swap", time.Second*30, complete(maker))
+	waitFor(t, "taker swap", time.Second*30, complete(taker))
+
+	makerSwap, takerSwap := maker.storedSwaps(t)[0], taker.storedSwaps(t)[0]
+	if makerSwap.Match.ID() != takerSwap.Match.ID() {
+		t.Fatalf("match ID mismatch")
+	}
+	if !makerSwap.Maker || takerSwap.Maker {
+		t.Fatalf("wrong roles")
+	}
+	if makerSwap.SendAsset != baseID || makerSwap.SendQty != qty || makerSwap.RecvQty != calc.BaseToQuote(rate, qty) {
+		t.Fatalf("wrong maker swap amounts: %+v", makerSwap)
+	}
+	if !bytes.Equal(makerSwap.Secret, takerSwap.Secret) {
+		t.Fatalf("taker didn't learn the secret")
+	}
+
+	// Each party redeemed the other's contract.
+	redeemedBy := func(chain *tChain, coinID dex.Bytes, w *tWallet) {
+		t.Helper()
+		ct, err := chain.contract(coinID)
+		if err != nil {
+			t.Fatalf("contract not found: %v", err)
+		}
+		if ct.recipient != w.addr || ct.secret == nil {
+			t.Fatalf("contract not redeemed by the counterparty")
+		}
+	}
+	redeemedBy(chains[baseID], makerSwap.OurCoinID, takerWallets[baseID])
+	redeemedBy(chains[quoteID], takerSwap.OurCoinID, makerWallets[quoteID])
+
+	// The taker is updated on the completed swap for their order.
+	var completeUpdate *SwapUpdate
+	waitFor(t, "taker swap update", time.Second*10, func() bool {
+		for len(taker.payloads) > 0 {
+			if u, is := (<-taker.payloads).(*SwapUpdate); is && u.Status == dexorder.MatchComplete {
+				completeUpdate = u
+			}
+		}
+		return completeUpdate != nil
+	})
+	if completeUpdate.OrderID != takerOrd.ID() || completeUpdate.RedeemCoinID == nil {
+		t.Fatalf("wrong taker swap update %+v", completeUpdate)
+	}
+
+	for _, m := range []*Mesh{maker, taker} {
+		counterparty := maker.ID()
+		if m == maker {
+			counterparty = taker.ID()
+		}
+		tally, err := m.peerOutcomes(counterparty)
+		if err != nil {
+			t.Fatalf("peerOutcomes error: %v", err)
+		}
+		if tally.Successes != 1 || tally.Failures != 0 {
+			t.Fatalf("wrong outcomes %+v", tally)
+		}
+	}
+}
+
+func TestSwapReturnsCoins(t *testing.T) {
+	m := &Mesh{log: tLogger, net: dex.Simnet}
+	w := newTWallet(newTChain())
+	var oid tanka.ID40
+	copy(oid[:], encode.RandomBytes(40))
+	s := &swap{swapState: swapState{
+		Match:      &tanka.Match{OrderID: oid, Qty: 1, Stamp: time.Now()},
+		SendAsset:  42,
+		SendQty:    1e8,
+		TheirAddr:  "addr",
+		SecretHash: encode.RandomBytes(32),
+	}}
+	lockTime := time.Now().Add(time.Hour)
+
+	w.extraReceipts = 1
+	if err := m.swap(s, &swapWallet{SwapWallet: w}, lockTime); err == nil {
+		t.Fatal("no error for wrong number of receipts")
+	}
+	if w.returned.Load() != 1 {
+		t.Fatal("coins not returned for wrong number of receipts")
+	}
+	if s.OurCoinID != nil {
+		t.Fatal("swap coin recorded for wrong number of receipts")
+	}
+
+	w.extraReceipts = 0
+	if err := m.swap(s, &swapWallet{SwapWallet: w}, lockTime); err != nil {
+		t.Fatalf("swap error: %v", err)
+	}
+	if w.returned.Load() != 1 {
+		t.Fatal("coins returned for a successful swap")
+	}
+	if s.OurCoinID == nil {
+		t.Fatal("swap coin not recorded")
+	}
+}
+
+func TestLoadSwaps(t *testing.T) {
+	priv, _ := genKeyPair()
+	m, err := New(&Config{
+		DataDir:    t.TempDir(),
+		PrivateKey: priv,
+		Logger:     tLogger,
+		Net:        dex.Simnet,
+	})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+	defer m.db.Close()
+
+	newSwap := func(nonce uint64, status dexorder.MatchStatus, outcome swapOutcome, reported bool) *swap {
+		var oid tanka.ID40
+		copy(oid[:], encode.RandomBytes(40))
+		return &swap{swapState: swapState{
+			Match:    &tanka.Match{OrderID: oid, Qty: nonce, Stamp: time.Now()},
+			Status:   status,
+			Outcome:  outcome,
+			Reported: reported,
+		}}
+	}
+	active := newSwap(1, dexorder.MakerSwapCast, outcomePending, false)
+	unreported := newSwap(2, dexorder.MatchComplete, outcomeSuccess, false)
+	done := newSwap(3, dexorder.MatchComplete, outcomeSuccess, true)
+	for _, s := range []*swap{active, unreported, done} {
+		if err := m.saveSwap(s); err != nil {
+			t.Fatalf("saveSwap error: %v", err)
+		}
+	}
+	if err := m.loadSwaps(); err != nil {
+		t.Fatalf("loadSwaps error: %v", err)
+	}
+	if len(m.swaps) != 2 {
+		t.Fatalf("expected 2 active swaps, got %d", len(m.swaps))
+	}
+	for _, s := range []*swap{active, unreported} {
+		if m.swaps[s.Match.ID()] == nil {
+			t.Fatalf("swap with status %s not loaded", s.Status)
+		}
+	}
+}
+
+func TestPeerOutcomesScore(t *testing.T) {
+	for _, tt := range []struct {
+		successes, failures uint32
+		score               int8
+	}{
+		{0, 0, 0},
+		{3, 0, 3},
+		{3, 1, 3 - failurePenalty},
+		{500, 0, 127},
+		{0, 100, -128},
+	} {
+		o := &peerOutcomes{Successes: tt.successes, Failures: tt.failures}
+		if score := o.score(); score != tt.score {
+			t.Fatalf("%d successes, %d failures: wanted score %d, got %d", tt.successes, tt.failures, tt.score, score)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package mesh
 
 import (
+	"context"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -13,6 +16,7 @@ import (
 	"decred.org/dcrdex/tatanka/client/trade"
 	"decred.org/dcrdex/tatanka/mj"
 	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
 type order struct {
@@ -24,8 +28,14 @@ type order struct {
 	matchesMtx sync.RWMutex
 	matches    map[tanka.ID32]*tanka.Match
 	remain     uint64
+	// announced is the remaining quantity last broadcast to the market.
+	announced uint64
 }
 
+// orderRefreshInterval is how often our live orders are refreshed and expired
+// orders are pruned from the books.
+var orderRefreshInterval = tanka.OrderTTL / 4
+
 type market struct {
 	log     dex.Logger
 	peerID  tanka.PeerID
@@ -39,58 +49,59 @@ type market struct {
 
 	// book is known market orders minus ours.
 	book *orderbook.Book
+
+	// beginSwap is called when a match is negotiated. makerOrd is the order
+	// that the match is against, and oid is the ID of our order.
+	beginSwap func(oid tanka.ID40, makerOrd *tanka.Order, match *tanka.Match, maker bool)
 }
 
-func (m *market) addOwnOrder(ord *tanka.Order) {
+// addOwnOrder adds our order to the market and attempts to match remain of
+// its quantity with orders on the book.
+func (m *market) addOwnOrder(ord *tanka.Order, remain uint64) {
 	oid := ord.ID()
 	m.ordsMtx.RLock()
-	if _, exists := m.ords[oid]; exists {
+	_, exists := m.ords[oid]
+	m.ordsMtx.RUnlock()
+	if exists {
 		// ignore it then
 		return
 	}
-	m.ordsMtx.RUnlock()
 	o := &order{
 		Order:   ord,
 		oid:     oid,
 		matches: make(map[tanka.ID32]*tanka.Match),
 	}
 	desire := &trade.DesiredTrade{
-		Qty:  ord.Qty,
+		Qty:  remain,
 		Rate: ord.Rate,
 		Sell: ord.Sell,
 	}
 	matches, _ := trade.MatchBook(desire, m.feeParams(), m.book.Find)
 	// TODO: Do asyncronously.
-	o.remain = ord.Qty
+	o.remain = remain
+	o.announced = remain
 	for _, match := range matches {
 		now := time.Now()
 		matchThem := &tanka.Match{
 			From:    m.peerID,
-			OrderID: ord.ID(),
+			OrderID: match.Order.ID(),
 			Qty:     match.Qty,
 			BaseID:  m.baseID,
 			QuoteID: m.quoteID,
 			Stamp:   now,
 		}
-		ok, err := m.negotiate(ord.From, matchThem)
+		ok, err := m.negotiate(match.Order.From, matchThem)
 		if err != nil {
-			m.log.Errorf("unable to negotiate match with peer %s: %v", ord.From, err)
+			m.log.Errorf("unable to negotiate match with peer %s: %v", match.Order.From, err)
 			continue
 		}
 		if !ok {
 			continue
 		}
 		// They agree.
-		matchUs := &tanka.Match{
-			From:    match.Order.From,
-			OrderID: match.Order.ID(),
-			Qty:     match.Qty,
-			BaseID:  m.baseID,
-			QuoteID: m.quoteID,
-			Stamp:   now,
-		}
-		o.matches[matchUs.ID()] = matchUs
+		o.matches[matchThem.ID()] = matchThem
 		o.remain -= match.Qty
+		m.beginSwap(oid, match.Order, matchThem, false)
 	}
 	// TODO: Retry with orders from the book if some were not accepted but
 	// more compatible orders exist. We need a way to mark tried orders
@@ -100,6 +111,18 @@ func (m *market) addOwnOrder(ord *tanka.Order) {
 	m.ordsMtx.Unlock()
 }
 
+// removeOwnOrder removes our order from the market. Negotiated matches are
+// unaffected.
+func (m *market) removeOwnOrder(oid tanka.ID40) bool {
+	m.ordsMtx.Lock()
+	defer m.ordsMtx.Unlock()
+	if _, found := m.ords[oid]; !found {
+		return false
+	}
+	delete(m.ords, oid)
+	return true
+}
+
 // TODO: Correct these.
 func (m *market) feeParams() *trade.FeeParameters {
 	return &trade.FeeParameters{
@@ -109,8 +132,34 @@ func (m *market) feeParams() *trade.FeeParameters {
 	}
 }
 
+// checkOrder checks that an order received from a peer belongs on the market's
+// book.
+func (m *market) checkOrder(ord *tanka.Order, from tanka.PeerID) error {
+	if ord.From != from {
+		return fmt.Errorf("order from %s received from %s", ord.From, from)
+	}
+	if ord.BaseID != m.baseID || ord.QuoteID != m.quoteID {
+		return fmt.Errorf("order for wrong market %d-%d", ord.BaseID, ord.QuoteID)
+	}
+	if ord.LotSize == 0 {
+		return errors.New("zero lot size")
+	}
+	if err := ord.Valid(); err != nil {
+		return err
+	}
+	if time.Since(ord.Stamp) > tanka.OrderTTL {
+		return errors.New("order expired")
+	}
+	if time.Until(ord.Stamp) > tanka.EpochLength {
+		return errors.New("order stamped in the future")
+	}
+	return nil
+}
+
 func (m *market) addOrder(ord *tanka.Order) {
-	// TODO: Validate order.
+	if ord.From == m.peerID {
+		return
+	}
 	m.book.Add(ord)
 	check := func(o *order) {
 		o.matchesMtx.Lock()
@@ -141,7 +190,7 @@ func (m *market) addOrder(ord *tanka.Order) {
 		now := time.Now()
 		matchThem := &tanka.Match{
 			From:    m.peerID,
-			OrderID: o.Order.ID(),
+			OrderID: ord.ID(),
 			Qty:     qty,
 			BaseID:  m.baseID,
 			QuoteID: m.quoteID,
@@ -156,15 +205,8 @@ func (m *market) addOrder(ord *tanka.Order) {
 			return
 		}
 		// They agree.
-		matchUs := &tanka.Match{
-			From:    ord.From,
-			OrderID: ord.ID(),
-			Qty:     qty,
-			BaseID:  m.baseID,
-			QuoteID: m.quoteID,
-			Stamp:   now,
-		}
-		o.matches[matchUs.ID()] = matchUs
+		o.matches[matchThem.ID()] = matchThem
+		m.beginSwap(o.oid, ord, matchThem, false)
 	}
 	m.ordsMtx.RLock()
 	defer m.ordsMtx.RUnlock()
@@ -190,7 +232,9 @@ func (m *market) negotiate(to tanka.PeerID, match *tanka.Match) (bool, error) {
 	return ok, m.conn.RequestPeer(to, msg, &ok)
 }
 
-func (m *market) handleNegotiate(match *tanka.Match) bool {
+// handleNegotiate handles a match proposal against one of our orders. If the
+// match is accepted, the order is returned.
+func (m *market) handleNegotiate(match *tanka.Match) *tanka.Order {
 	mid := match.ID()
 	m.ordsMtx.RLock()
 	ord, found := m.ords[match.OrderID]
@@ -198,22 +242,208 @@ func (m *market) handleNegotiate(match *tanka.Match) bool {
 	// Order is finished or never existed.
 	if !found {
 		m.log.Debugf("ignoring match proposal for unknown order %s", match.OrderID)
-		return false
+		return nil
 	}
 	ord.matchesMtx.Lock()
 	defer ord.matchesMtx.Unlock()
 	// We already confirmed this match.
 	if ord.matches[mid] != nil {
-		return true
+		return ord.Order
 	}
 	// We no longer have the quantity necessary.
 	// TODO: negotiate a lower quantity.
 	if ord.remain < match.Qty {
-		return false
+		return nil
 	}
 	ord.matches[mid] = match
 	ord.remain -= match.Qty
-	return true
+	return ord.Order
+}
+
+// snapshot is a signed list of our live orders.
+func (m *market) snapshot(priv *secp256k1.PrivateKey) *tanka.OrderSnapshot {
+	stamp := time.Now()
+	snap := &tanka.OrderSnapshot{
+		From:    m.peerID,
+		BaseID:  m.baseID,
+		QuoteID: m.quoteID,
+		Stamp:   stamp,
+	}
+	m.ordsMtx.RLock()
+	for _, o := range m.ords {
+		o.matchesMtx.RLock()
+		remain := o.remain
+		o.matchesMtx.RUnlock()
+		if remain == 0 {
+			continue
+		}
+		ord := *o.Order
+		ord.Qty = remain
+		ord.Stamp = stamp
+		snap.Orders = append(snap.Orders, &ord)
+	}
+	m.ordsMtx.RUnlock()
+	snap.Sign(priv)
+	return snap
+}
+
+// applySnapshot adds the orders from a peer's verified snapshot to the book.
+// Orders already on the book are updated if the snapshot is newer. Orders
+// from the peer that are on the book but not in a newer snapshot are no longer
+// live and are removed. The number of orders added or updated is returned.
+func (m *market) applySnapshot(snap *tanka.OrderSnapshot) (n int) {
+	live := make(map[tanka.ID40]bool, len(snap.Orders))
+	for _, ord := range snap.Orders {
+		if err := m.checkOrder(ord, snap.From); err != nil {
+			m.log.Debugf("ignoring order in snapshot from %s: %v", snap.From, err)
+			continue
+		}
+		oid := ord.ID()
+		live[oid] = true
+		if existing := m.book.Order(oid); existing != nil {
+			if !ord.Stamp.After(existing.Stamp) {
+				continue
+			}
+			if err := m.book.Update(&tanka.OrderUpdate{
+				From:  ord.From,
+				Nonce: ord.Nonce,
+				Qty:   ord.Qty,
+				Stamp: ord.Stamp,
+			}); err != nil {
+				// Removed since we checked.
+				continue
+			}
+		} else {
+			m.addOrder(ord)
+		}
+		n++
+	}
+	var gone []tanka.ID40
+	m.book.Find(&orderbook.Filter{
+		Check: func(ord *tanka.Order) bool {
+			if ord.From == snap.From && !live[ord.ID()] && ord.Stamp.Before(snap.Stamp) {
+				gone = append(gone, ord.ID())
+			}
+			return false
+		},
+	})
+	for _, oid := range gone {
+		m.book.Delete(oid)
+	}
+	return n
+}
+
+// refresh prunes expired orders from the book and returns updates for our
+// orders that should be broadcast to keep them on our peers' books.
+func (m *market) refresh() []*tanka.OrderUpdate {
+	now := time.Now()
+	if n := m.book.Prune(now.Add(-tanka.OrderTTL)); n > 0 {
+		m.log.Debugf("pruned %d expired orders", n)
+	}
+	var updates []*tanka.OrderUpdate
+	m.ordsMtx.RLock()
+	defer m.ordsMtx.RUnlock()
+	for _, o := range m.ords {
+		o.matchesMtx.Lock()
+		if o.remain > 0 || o.announced > 0 {
+			updates = append(updates, &tanka.OrderUpdate{
+				From:  m.peerID,
+				Nonce: o.Nonce,
+				Qty:   o.remain,
+				Stamp: now,
+			})
+			o.announced = o.remain
+		}
+		o.matchesMtx.Unlock()
+	}
+	return updates
+}
+
+// sendOrderSnapshot sends a snapshot of our live orders to a new market
+// subscriber.
+func (m *Mesh) sendOrderSnapshot(mkt *market, to tanka.PeerID) {
+	snap := mkt.snapshot(m.priv)
+	if len(snap.Orders) == 0 {
+		return
+	}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		m.log.Errorf("error marshaling order snapshot: %v", err)
+		return
+	}
+	msg := &msgjson.Message{
+		Route:   mj.RouteOrderSnapshot,
+		Payload: b,
+	}
+	if err := m.conn.ConnectPeer(to); err != nil {
+		m.log.Errorf("error connecting to new subscriber %s: %v", to, err)
+		return
+	}
+	var ok bool
+	if err := m.conn.RequestPeer(to, msg, &ok); err != nil {
+		m.log.Errorf("error sending order snapshot to %s: %v", to, err)
+	}
+}
+
+func (m *Mesh) handleOrderSnapshot(peerID tanka.PeerID, payload json.RawMessage, respond func(any, mj.TankagramError)) {
+	var snap tanka.OrderSnapshot
+	if err := json.Unmarshal(payload, &snap); err != nil {
+		m.log.Debugf("handleOrderSnapshot: unable to unmarshal snapshot from peer %v: %v", peerID, err)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	if snap.From != peerID {
+		m.log.Debugf("handleOrderSnapshot: peer %v sent a snapshot from %v", peerID, snap.From)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	if err := snap.Verify(); err != nil {
+		m.log.Debugf("handleOrderSnapshot: bad snapshot signature from peer %v: %v", peerID, err)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	if time.Since(snap.Stamp) > tanka.OrderTTL || time.Until(snap.Stamp) > tanka.EpochLength {
+		m.log.Debugf("handleOrderSnapshot: snapshot from peer %v has a bad stamp %s", peerID, snap.Stamp)
+		respond(false, mj.TEEBadRequest)
+		return
+	}
+	mkt, err := m.market(snap.BaseID, snap.QuoteID)
+	if err != nil {
+		m.log.Debugf("handleOrderSnapshot: %v", err)
+		respond(false, mj.TEEPeerError)
+		return
+	}
+	respond(true, mj.TEErrNone)
+	if n := mkt.applySnapshot(&snap); n > 0 {
+		m.log.Debugf("Synced %d orders from peer %s", n, peerID)
+	}
+}
+
+// runMarkets refreshes our orders and prunes expired orders from the books
+// periodically until the context is canceled.
+func (m *Mesh) runMarkets(ctx context.Context) {
+	tick := time.NewTicker(orderRefreshInterval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-tick.C:
+		case <-ctx.Done():
+			return
+		}
+		m.marketsMtx.RLock()
+		mkts := make(map[string]*market, len(m.markets))
+		for mktName, mkt := range m.markets {
+			mkts[mktName] = mkt
+		}
+		m.marketsMtx.RUnlock()
+		for mktName, mkt := range mkts {
+			for _, u := range mkt.refresh() {
+				if err := m.Broadcast(mj.TopicMarket, tanka.Subject(mktName), mj.MessageTypeOrderUpdate, u); err != nil {
+					m.log.Errorf("error broadcasting order update: %v", err)
+				}
+			}
+		}
+	}
 }
 
 func (m *Mesh) handleMarketBroadcast(bcast *mj.Broadcast) {
@@ -239,21 +469,160 @@ func (m *Mesh) handleMarketBroadcast(bcast *mj.Broadcast) {
 			m.log.Errorf("error unmarshaling new order: %v", err)
 			return
 		}
+		if err := mkt.checkOrder(&ord, bcast.PeerID); err != nil {
+			m.log.Debugf("ignoring new order from %s: %v", bcast.PeerID, err)
+			return
+		}
 		mkt.addOrder(&ord)
+	case mj.MessageTypeOrderUpdate:
+		var ou tanka.OrderUpdate
+		if err := json.Unmarshal(bcast.Payload, &ou); err != nil {
+			m.log.Errorf("error unmarshaling order update: %v", err)
+			return
+		}
+		if ou.From != bcast.PeerID {
+			m.log.Debugf("ignoring update for order from %s broadcast by %s", ou.From, bcast.PeerID)
+			return
+		}
+		if err := mkt.book.Update(&ou); err != nil {
+			// We don't have the order. It was probably placed before we
+			// subscribed, and the owner didn't send a snapshot.
+			m.log.Tracef("order update for unknown order: %v", err)
+		}
 	case mj.MessageTypeNewSubscriber:
 		var ns mj.NewSubscriber
 		if err := json.Unmarshal(bcast.Payload, &ns); err != nil {
 			m.log.Errorf("error decoding new_subscriber payload: %v", err)
+			return
 		}
-		// c.emit(&NewMarketSubscriber{
-		// 	MarketName: mktName,
-		// 	PeerID:     bcast.PeerID,
-		// })
+		if ns.PeerID == m.peerID || ns.PeerID != bcast.PeerID {
+			return
+		}
+		// Let them know about our orders.
+		go m.sendOrderSnapshot(mkt, ns.PeerID)
 	default:
 		m.log.Errorf("received broadcast on %s -> %s with unknown message type %s", bcast.Topic, bcast.Subject)
 	}
 }
 
+func (m *Mesh) market(baseID, quoteID uint32) (*market, error) {
+	mktName, err := dex.MarketName(baseID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+	mkt, found := m.markets[mktName]
+	if !found {
+		return nil, fmt.Errorf("not subscribed to market %s", mktName)
+	}
+	return mkt, nil
+}
+
+// Markets lists the markets to which we are subscribed.
+func (m *Mesh) Markets() []*tanka.MarketParameters {
+	m.marketsMtx.RLock()
+	defer m.marketsMtx.RUnlock()
+	mkts := make([]*tanka.MarketParameters, 0, len(m.markets))
+	for _, mkt := range m.markets {
+		mkts = append(mkts, &tanka.MarketParameters{BaseID: mkt.baseID, QuoteID: mkt.quoteID})
+	}
+	return mkts
+}
+
+// LotSize is the lot size for our orders at the rate on a subscribed market.
+// It is the smallest lot size that keeps our fee exposure within the market's
+// fee parameters.
+func (m *Mesh) LotSize(baseID, quoteID uint32, rate uint64) (uint64, error) {
+	mkt, err := m.market(baseID, quoteID)
+	if err != nil {
+		return 0, err
+	}
+	lotSize := trade.MinimumLotSize(rate, mkt.feeParams())
+	if lotSize == 0 {
+		return 0, fmt.Errorf("no lot size for rate %d", rate)
+	}
+	return lotSize, nil
+}
+
+// Book returns the orders on a subscribed market's book, not including our
+// own.
+func (m *Mesh) Book(baseID, quoteID uint32) ([]*tanka.Order, error) {
+	mkt, err := m.market(baseID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	var ords []*tanka.Order
+	mkt.book.Find(&orderbook.Filter{
+		Check: func(ord *tanka.Order) bool {
+			ords = append(ords, ord)
+			return false
+		},
+	})
+	return ords, nil
+}
+
+// PlaceOrder matches our order with compatible orders on the book and
+// broadcasts it to the market. The market must be subscribed.
+func (m *Mesh) PlaceOrder(ord *tanka.Order) error {
+	return m.placeOrder(ord, ord.Qty)
+}
+
+// ResumeOrder is like PlaceOrder, but for an order that was placed before and
+// has already had filled of its quantity matched.
+func (m *Mesh) ResumeOrder(ord *tanka.Order, filled uint64) error {
+	if filled >= ord.Qty {
+		return fmt.Errorf("order %s is already filled", ord.ID())
+	}
+	return m.placeOrder(ord, ord.Qty-filled)
+}
+
+func (m *Mesh) placeOrder(ord *tanka.Order, remain uint64) error {
+	if ord.From != m.peerID {
+		return fmt.Errorf("order is from %s, not us", ord.From)
+	}
+	if err := ord.Valid(); err != nil {
+		return err
+	}
+	mkt, err := m.market(ord.BaseID, ord.QuoteID)
+	if err != nil {
+		return err
+	}
+	mkt.addOwnOrder(ord, remain)
+	// Peers drop orders stamped more than tanka.OrderTTL ago, so a resumed
+	// order is broadcast with the current stamp and its remaining quantity.
+	bcastOrd := *ord
+	bcastOrd.Qty = remain
+	bcastOrd.Stamp = time.Now()
+	mktName, _ := dex.MarketName(ord.BaseID, ord.QuoteID)
+	if err := m.Broadcast(mj.TopicMarket, tanka.Subject(mktName), mj.MessageTypeNewOrder, &bcastOrd); err != nil {
+		return fmt.Errorf("error broadcasting order: %w", err)
+	}
+	return nil
+}
+
+// CancelOrder stops matching our order. Swaps for matches that were already
+// negotiated are unaffected.
+func (m *Mesh) CancelOrder(baseID, quoteID uint32, oid tanka.ID40) error {
+	mkt, err := m.market(baseID, quoteID)
+	if err != nil {
+		return err
+	}
+	if !mkt.removeOwnOrder(oid) {
+		return fmt.Errorf("order %s not found", oid)
+	}
+	mktName, _ := dex.MarketName(baseID, quoteID)
+	u := &tanka.OrderUpdate{
+		From:  m.peerID,
+		Nonce: binary.BigEndian.Uint64(oid[32:]),
+		Stamp: time.Now(),
+	}
+	if err := m.Broadcast(mj.TopicMarket, tanka.Subject(mktName), mj.MessageTypeOrderUpdate, u); err != nil {
+		m.log.Errorf("error broadcasting canceled order %s: %v", oid, err)
+	}
+	return nil
+}
+
 func (m *Mesh) FiatRate(assetID uint32) float64 {
 	m.fiatRatesMtx.RLock()
 	defer m.fiatRatesMtx.RUnlock()
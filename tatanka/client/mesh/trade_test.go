@@ -0,0 +1,142 @@
+package mesh
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/calc"
+	"decred.org/dcrdex/tatanka/client/orderbook"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+func TestOrderSnapshot(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const baseID, quoteID = 42, 0
+	entryNode := runTatanka(t, ctx)
+	maker, _ := newTestMesh(t, ctx, "MAKER", entryNode, nil)
+	if err := maker.SubscribeMarket(baseID, quoteID); err != nil {
+		t.Fatalf("SubscribeMarket error: %v", err)
+	}
+
+	const lotSize, rate = 1 << 20, 2 * calc.RateEncodingFactor
+	makerOrd := &tanka.Order{
+		From:    maker.ID(),
+		BaseID:  baseID,
+		QuoteID: quoteID,
+		Sell:    true,
+		Qty:     lotSize * 4,
+		Rate:    rate,
+		LotSize: lotSize,
+		Nonce:   1,
+		Stamp:   time.Now(),
+	}
+	if err := maker.PlaceOrder(makerOrd); err != nil {
+		t.Fatalf("PlaceOrder error: %v", err)
+	}
+
+	// A subscriber that joins after the order was broadcast gets it from the
+	// maker's snapshot.
+	late, _ := newTestMesh(t, ctx, "LATE", entryNode, nil)
+	if err := late.SubscribeMarket(baseID, quoteID); err != nil {
+		t.Fatalf("SubscribeMarket error: %v", err)
+	}
+	lateMkt, err := late.market(baseID, quoteID)
+	if err != nil {
+		t.Fatalf("market error: %v", err)
+	}
+	waitFor(t, "order snapshot", time.Second*10, func() bool {
+		return lateMkt.book.Order(makerOrd.ID()) != nil
+	})
+
+	// Canceling the order removes it from the late subscriber's book.
+	if err := maker.CancelOrder(baseID, quoteID, makerOrd.ID()); err != nil {
+		t.Fatalf("CancelOrder error: %v", err)
+	}
+	waitFor(t, "order cancellation", time.Second*10, func() bool {
+		return lateMkt.book.Order(makerOrd.ID()) == nil
+	})
+}
+
+func TestApplySnapshot(t *testing.T) {
+	priv, peerID := genKeyPair()
+	mkt := &market{
+		log:     tLogger,
+		baseID:  42,
+		quoteID: 0,
+		ords:    make(map[tanka.ID40]*order),
+		book:    orderbook.New(),
+	}
+	now := time.Now()
+	newOrd := func(nonce uint64, stamp time.Time) *tanka.Order {
+		return &tanka.Order{
+			From:    peerID,
+			BaseID:  42,
+			QuoteID: 0,
+			Qty:     8,
+			Rate:    1,
+			LotSize: 2,
+			Nonce:   nonce,
+			Stamp:   stamp,
+		}
+	}
+	gone := newOrd(1, now.Add(-time.Minute))
+	known := newOrd(2, now.Add(-time.Minute))
+	mkt.book.Add(gone)
+	mkt.book.Add(known)
+
+	updated := newOrd(2, now)
+	updated.Qty = 4
+	expired := newOrd(3, now.Add(-tanka.OrderTTL-time.Minute))
+	wrongMkt := newOrd(4, now)
+	wrongMkt.QuoteID = 60
+	added := newOrd(5, now)
+	snap := &tanka.OrderSnapshot{
+		From:    peerID,
+		BaseID:  42,
+		QuoteID: 0,
+		Orders:  []*tanka.Order{updated, expired, wrongMkt, added},
+		Stamp:   now,
+	}
+	snap.Sign(priv)
+	if err := snap.Verify(); err != nil {
+		t.Fatalf("Verify error: %v", err)
+	}
+	snap.Orders[0].Qty = 6
+	if err := snap.Verify(); err == nil {
+		t.Fatal("tampered snapshot verified")
+	}
+	snap.Orders[0].Qty = 4
+
+	if n := mkt.applySnapshot(snap); n != 2 {
+		t.Fatalf("expected 2 orders synced, got %d", n)
+	}
+	if mkt.book.Order(gone.ID()) != nil {
+		t.Fatal("order missing from snapshot not removed")
+	}
+	if ord := mkt.book.Order(known.ID()); ord == nil || ord.Qty != 4 {
+		t.Fatal("known order not updated")
+	}
+	for _, ord := range []*tanka.Order{expired, wrongMkt} {
+		if mkt.book.Order(ord.ID()) != nil {
+			t.Fatalf("bad order %d added", ord.Nonce)
+		}
+	}
+	if mkt.book.Order(added.ID()) == nil {
+		t.Fatal("new order not added")
+	}
+
+	// Applying the same snapshot again changes nothing, and a later update
+	// for an order takes precedence over an older snapshot.
+	if err := mkt.book.Update(&tanka.OrderUpdate{From: peerID, Nonce: 5, Qty: 2, Stamp: now.Add(time.Second)}); err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if n := mkt.applySnapshot(snap); n != 0 {
+		t.Fatalf("expected 0 orders synced, got %d", n)
+	}
+	if ord := mkt.book.Order(added.ID()); ord.Qty != 2 {
+		t.Fatalf("order update overwritten by older snapshot")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"decred.org/dcrdex/tatanka/tanka"
 )
@@ -19,6 +20,7 @@ type Booker interface {
 	Add(*tanka.Order)
 	Update(ou *tanka.OrderUpdate) error
 	Delete(id tanka.ID40)
+	Prune(cutoff time.Time) int
 }
 
 // Filter is used when searching for orders.
@@ -132,7 +134,8 @@ func (ob *Book) Add(o *tanka.Order) {
 	ob.addOrderAndSort(o)
 }
 
-// Update updates an order.
+// Update updates an order. An update stamped before the order's current stamp
+// is ignored. An update with zero quantity removes the order.
 func (ob *Book) Update(ou *tanka.OrderUpdate) error {
 	ob.mtx.Lock()
 	defer ob.mtx.Unlock()
@@ -141,6 +144,14 @@ func (ob *Book) Update(ou *tanka.OrderUpdate) error {
 	if !has {
 		return fmt.Errorf("order %x not found", id)
 	}
+	if ou.Stamp.Before(o.Stamp) {
+		return nil
+	}
+	if ou.Qty == 0 {
+		delete(ob.book, id)
+		ob.deleteSortedOrder(o)
+		return nil
+	}
 	o.Qty = ou.Qty
 	o.Stamp = ou.Stamp
 	return nil
@@ -156,3 +167,19 @@ func (ob *Book) Delete(id tanka.ID40) {
 		ob.deleteSortedOrder(o)
 	}
 }
+
+// Prune deletes orders that were stamped before the cutoff, returning the
+// number of orders deleted.
+func (ob *Book) Prune(cutoff time.Time) int {
+	ob.mtx.Lock()
+	defer ob.mtx.Unlock()
+	var n int
+	for id, o := range ob.book {
+		if o.Stamp.Before(cutoff) {
+			delete(ob.book, id)
+			ob.deleteSortedOrder(o)
+			n++
+		}
+	}
+	return n
+}
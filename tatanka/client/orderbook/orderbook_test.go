@@ -190,9 +190,11 @@ func TestUpdate(t *testing.T) {
 	o := ords[0]
 	updateTime := mustParseTime("Sun, 28 Dec 2025 12:00:00 UTC")
 	tests := []struct {
-		name    string
-		update  *tanka.OrderUpdate
-		wantErr bool
+		name      string
+		update    *tanka.OrderUpdate
+		wantQty   uint64
+		wantStamp time.Time
+		wantErr   bool
 	}{{
 		name: "ok",
 		update: &tanka.OrderUpdate{
@@ -201,6 +203,18 @@ func TestUpdate(t *testing.T) {
 			Qty:   7,
 			Stamp: updateTime,
 		},
+		wantQty:   7,
+		wantStamp: updateTime,
+	}, {
+		name: "stale update ignored",
+		update: &tanka.OrderUpdate{
+			From:  o.From,
+			Nonce: o.Nonce,
+			Qty:   5,
+			Stamp: updateTime.Add(-time.Minute),
+		},
+		wantQty:   7,
+		wantStamp: updateTime,
 	}, {
 		name: "order does not exist",
 		update: &tanka.OrderUpdate{
@@ -224,14 +238,25 @@ func TestUpdate(t *testing.T) {
 				t.Fatalf("unexpected error %v", err)
 			}
 			ord := ob.Order(test.update.ID())
-			if ord.Qty != test.update.Qty {
-				t.Fatalf("expected qty %d but got %d", test.update.Qty, ord.Qty)
+			if ord.Qty != test.wantQty {
+				t.Fatalf("expected qty %d but got %d", test.wantQty, ord.Qty)
 			}
-			if ord.Stamp != test.update.Stamp {
-				t.Fatalf("expected stamp %s but got %s", test.update.Stamp, ord.Stamp)
+			if ord.Stamp != test.wantStamp {
+				t.Fatalf("expected stamp %s but got %s", test.wantStamp, ord.Stamp)
 			}
 		})
 	}
+
+	// A zero quantity update removes the order.
+	if err := ob.Update(&tanka.OrderUpdate{From: o.From, Nonce: o.Nonce, Stamp: updateTime}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ob.Order(o.ID()) != nil {
+		t.Fatal("order not removed")
+	}
+	if len(ob.buys) != 1 {
+		t.Fatalf("wanted 1 buy but got %d", len(ob.buys))
+	}
 }
 
 func TestDeleteOrder(t *testing.T) {
@@ -260,3 +285,25 @@ func TestDeleteOrder(t *testing.T) {
 		t.Fatalf("wanted 1 but got %d orders", len(oids))
 	}
 }
+
+func TestPrune(t *testing.T) {
+	ob := New()
+	ords := testOrders()
+	for _, o := range ords {
+		ob.Add(o)
+	}
+	ords[1].Stamp = ords[1].Stamp.Add(time.Hour)
+	ords[2].Stamp = ords[2].Stamp.Add(time.Hour)
+	if n := ob.Prune(ords[0].Stamp.Add(time.Minute)); n != 2 {
+		t.Fatalf("wanted 2 pruned but got %d", n)
+	}
+	if len(ob.buys) != 1 || ob.buys[0] != ords[1] {
+		t.Fatal("wrong buys after prune")
+	}
+	if len(ob.sells) != 1 || ob.sells[0] != ords[2] {
+		t.Fatal("wrong sells after prune")
+	}
+	if n := ob.Prune(ords[0].Stamp); n != 0 {
+		t.Fatalf("wanted 0 pruned but got %d", n)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/dex/utils"
+	"decred.org/dcrdex/tatanka/chain"
 	"decred.org/dcrdex/tatanka/mj"
 	"decred.org/dcrdex/tatanka/tanka"
 	"decred.org/dcrdex/tatanka/tcp"
@@ -123,6 +124,10 @@ func (t *Tatanka) handleClientConnect(cl tanka.Sender, msg *msgjson.Message) *ms
 		return msgjson.NewError(mj.ErrBadRequest, "error unmarshaling client connection configuration from %q: %v", cl.PeerID(), err)
 	}
 
+	if until, banned := t.rateLimiter.banned(conn.ID, time.Now()); banned {
+		return msgjson.NewError(mj.ErrBanned, "banned for flooding until %s", until.UTC().Format(time.RFC3339))
+	}
+
 	p, err := t.db.Peer(conn.ID)
 	if err != nil {
 		return msgjson.NewError(mj.ErrInternal, "error getting peer info for peer %q: %v", conn.ID, err)
@@ -253,6 +258,7 @@ func (t *Tatanka) handlePostBond(cl tanka.Sender, msg *msgjson.Message) *msgjson
 		}
 	}
 
+	newBonds := make([]*tanka.Bond, 0, len(bonds))
 	for _, b := range bonds {
 		if b == nil {
 			t.log.Errorf("Bond-posting client %s sent a nil bond", peerID)
@@ -282,12 +288,19 @@ func (t *Tatanka) handlePostBond(cl tanka.Sender, msg *msgjson.Message) *msgjson
 			return msgjson.NewError(mj.ErrBadRequest, "failed validation")
 		}
 
+		if existing, err := t.db.Bond(b.CoinID); err == nil && existing.PeerID == b.PeerID {
+			continue // already known
+		}
+
 		if err := t.db.StoreBond(b); err != nil {
 			t.log.Errorf("Error storing bond for client %s in db: %v", peerID, err)
 			return msgjson.NewError(mj.ErrInternal, "internal error")
 		}
+		newBonds = append(newBonds, b)
 	}
 
+	t.shareBonds(newBonds, nil)
+
 	liveBonds, err := t.db.GetBonds(peerID)
 	if err != nil {
 		t.log.Errorf("Error retrieving bonds for client %s in db: %v", peerID, err)
@@ -643,6 +656,24 @@ func (t *Tatanka) handleBroadcast(c *client, msg *msgjson.Message) *msgjson.Erro
 		return msgjson.NewError(mj.ErrBadRequest, "too old")
 	}
 
+	// Unsubscribing is not subject to the quota.
+	switch bcast.MessageType {
+	case mj.MessageTypeUnsubTopic, mj.MessageTypeUnsubSubject:
+	default:
+		switch t.rateLimiter.broadcast(p.ID, bcast.Topic, c.tier(), time.Now()) {
+		case quotaViolation:
+			t.log.Warnf("Client %s exceeded their broadcast quota for topic %q", p.ID, bcast.Topic)
+			fallthrough
+		case quotaExceeded:
+			return msgjson.NewError(mj.ErrRateLimit, "broadcast quota exceeded for topic %q", bcast.Topic)
+		case quotaBanned:
+			t.log.Warnf("Disconnecting client %s banned for flooding topic %q", p.ID, bcast.Topic)
+			t.send(p, mj.MustResponse(msg.ID, nil, msgjson.NewError(mj.ErrBanned, "banned for flooding")))
+			p.Disconnect()
+			return nil
+		}
+	}
+
 	// Relay to remote tatankas first.
 	t.relayBroadcast(bcast, p.ID)
 
@@ -782,38 +813,52 @@ func (t *Tatanka) handleTankagram(c *client, msg *msgjson.Message) *msgjson.Erro
 func (t *Tatanka) handleSetScore(c *client, msg *msgjson.Message) {
 	scorer := c.peer.ID
 	var score *mj.ScoreReport
-	if err := msg.Unmarshal(&score); err != nil {
+	if err := msg.Unmarshal(&score); err != nil || score == nil {
 		t.log.Errorf("error unmarshaling set_score from %s: %v", scorer, err)
 		return
 	}
-	if err := t.db.SetScore(score.PeerID, scorer, score.Score, time.Now()); err != nil {
-		t.log.Errorf("error adding score from %s for %s to db: %v", scorer, score.PeerID, err)
-	}
-	rep, err := t.db.Reputation(score.PeerID)
-	if err != nil {
-		t.log.Errorf("error getting reputation after score update from %s for %s: %v", score.PeerID, scorer, err)
+	if d := time.Since(score.Stamp); d > tanka.EpochLength || d < -tanka.EpochLength {
+		t.log.Errorf("set_score from %s has a bad stamp %s", scorer, score.Stamp)
 		return
 	}
-	t.clientMtx.RLock()
-	c, found := t.clients[score.PeerID]
-	t.clientMtx.RUnlock()
-	if found {
-		c.mtx.Lock()
-		c.Reputation = rep
-		c.mtx.Unlock()
+	report := &tanka.ScoreReport{
+		Scorer: scorer,
+		Scored: score.PeerID,
+		Score:  score.Score,
+		Stamp:  score.Stamp,
+		Sig:    score.Sig,
 	}
+	if err := report.Verify(); err != nil {
+		t.log.Errorf("bad score report signature from %s: %v", scorer, err)
+		return
+	}
+	t.acceptScoreReport(report, nil)
+}
 
-	note := mj.MustNotification(mj.RouteShareScore, &mj.SharedScore{
-		Scorer:     scorer,
-		Scored:     score.PeerID,
-		Score:      score.Score,
-		Reputation: rep,
-	})
-	for _, tt := range t.tatankaNodes() {
-		if err := t.send(tt, note); err != nil {
-			t.log.Errorf("error notifying %s of new score: %v", tt.ID, err)
-		}
+// handleAuditHTLC audits a swap contract on behalf of the client, for clients
+// whose wallets cannot audit the counterparty's contract themselves. The
+// result is true only if the chain backend found an HTLC satisfying the terms
+// of the audit.
+func (t *Tatanka) handleAuditHTLC(c *client, msg *msgjson.Message) *msgjson.Error {
+	var audit *tanka.HTLCAudit
+	if err := msg.Unmarshal(&audit); err != nil || audit == nil {
+		t.log.Errorf("error unmarshaling audit_htlc from %s: %v", c.ID, err)
+		return msgjson.NewError(mj.ErrBadRequest, "bad request")
 	}
+	t.chainMtx.RLock()
+	ch := t.chains[audit.AssetID]
+	t.chainMtx.RUnlock()
+	auditor, is := ch.(chain.HTLCAuditor)
+	if !is {
+		return msgjson.NewError(mj.ErrBadRequest, "audits not supported for asset %d", audit.AssetID)
+	}
+	ok, err := auditor.AuditHTLC(audit)
+	if err != nil {
+		t.log.Errorf("Error auditing %s HTLC %s for %s: %v", dex.BipIDSymbol(audit.AssetID), audit.CoinID, c.ID, err)
+		return msgjson.NewError(mj.ErrInternal, "audit failed")
+	}
+	t.sendResult(c, msg.ID, ok)
+	return nil
 }
 
 const ErrNoPath = dex.ErrorKind("no path")
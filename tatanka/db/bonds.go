@@ -49,10 +49,23 @@ func (bond *dbBond) UnmarshalBinary(b []byte) error {
 	return nil
 }
 
+// StoreBond stores the bond. The bond is deleted from the database when it
+// expires. Bonds that are already expired are stored without a TTL, and are
+// removed by pruneOldBonds.
 func (d *DB) StoreBond(newBond *tanka.Bond) error {
-	return d.bonds.Set(newBond.CoinID[:], &dbBond{newBond}, lexi.WithReplace())
+	return d.bonds.Set(newBond.CoinID[:], &dbBond{newBond}, lexi.WithReplace(), lexi.WithTTL(time.Until(newBond.Expiration)))
 }
 
+// bondsTTLUpgrade is the version 1 upgrade of the bonds table, which sets the
+// TTLs of bonds stored without one.
+var bondsTTLUpgrade = lexi.TTLUpgrade(func(vB []byte) (lexi.KV, time.Time, error) {
+	var bond dbBond
+	if err := bond.UnmarshalBinary(vB); err != nil {
+		return nil, time.Time{}, err
+	}
+	return &bond, bond.Expiration, nil
+})
+
 func (d *DB) GetBonds(peerID tanka.PeerID) ([]*tanka.Bond, error) {
 	var bonds []*tanka.Bond
 	now := time.Now()
@@ -71,3 +84,31 @@ func (d *DB) GetBonds(peerID tanka.PeerID) ([]*tanka.Bond, error) {
 		})
 	})
 }
+
+// Bond retrieves the stored bond with the coin ID. If the bond is not found,
+// lexi.ErrKeyNotFound is returned.
+func (d *DB) Bond(coinID []byte) (*tanka.Bond, error) {
+	var bond dbBond
+	if err := d.bonds.Get(coinID, &bond); err != nil {
+		return nil, err
+	}
+	return bond.Bond, nil
+}
+
+// LiveBonds retrieves all unexpired bonds.
+func (d *DB) LiveBonds() ([]*tanka.Bond, error) {
+	var bonds []*tanka.Bond
+	now := time.Now()
+	return bonds, d.bonds.Iterate(nil, func(it *lexi.Iter) error {
+		return it.V(func(vB []byte) error {
+			var bond dbBond
+			if err := bond.UnmarshalBinary(vB); err != nil {
+				return fmt.Errorf("error unmarshaling bond: %w", err)
+			}
+			if bond.Expiration.After(now) {
+				bonds = append(bonds, bond.Bond)
+			}
+			return nil
+		})
+	})
+}
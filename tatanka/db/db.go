@@ -32,6 +32,7 @@ type DB struct {
 	bonds        *lexi.Table
 	bonderIdx    *lexi.Index
 	bondStampIdx *lexi.Index
+	gossip       *lexi.Table
 }
 
 func New(dir string, log dex.Logger) (*DB, error) {
@@ -120,6 +121,14 @@ func New(dir string, log dex.Logger) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error initializing bond stamp index: %w", err)
 	}
+	if err := bondsTable.Migrate(bondsTTLUpgrade); err != nil {
+		return nil, fmt.Errorf("error migrating bonds table: %w", err)
+	}
+	// Gossip sync stamps. Keyed on tatanka peer ID.
+	gossipTable, err := db.Table("gossip")
+	if err != nil {
+		return nil, fmt.Errorf("error initializing gossip table: %w", err)
+	}
 	return &DB{
 		DB:           db,
 		scores:       scoreTable,
@@ -128,6 +137,7 @@ func New(dir string, log dex.Logger) (*DB, error) {
 		bonds:        bondsTable,
 		bonderIdx:    bonderIdx,
 		bondStampIdx: bondStampIdx,
+		gossip:       gossipTable,
 	}, nil
 }
 
@@ -2,6 +2,7 @@ package db
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"testing"
 	"time"
@@ -116,6 +117,76 @@ func TestPruneBonds(t *testing.T) {
 	}
 }
 
+func TestBondsTTLUpgrade(t *testing.T) {
+	db, shutdown := tNewDB()
+	defer shutdown()
+
+	// Store bonds without TTLs, as before the upgrade.
+	peer := tanka.PeerID{0x01}
+	live, expired := newBond(peer, 1), newBond(peer, 2, -time.Second)
+	for _, b := range []*tanka.Bond{live, expired} {
+		if err := db.bonds.Set(b.CoinID, &dbBond{b}); err != nil {
+			t.Fatalf("Set error: %v", err)
+		}
+	}
+	expirations := func() map[string]time.Time {
+		t.Helper()
+		exps := make(map[string]time.Time)
+		if err := db.bonds.Iterate(nil, func(it *lexi.Iter) error {
+			k, err := it.K()
+			if err != nil {
+				return err
+			}
+			exps[string(k)] = it.ExpiresAt()
+			return nil
+		}); err != nil {
+			t.Fatalf("Iterate error: %v", err)
+		}
+		return exps
+	}
+	exps := expirations()
+	if len(exps) != 2 {
+		t.Fatalf("expected 2 bonds before upgrade, got %d", len(exps))
+	}
+	for k, exp := range exps {
+		if !exp.IsZero() {
+			t.Fatalf("bond %x has a TTL before upgrade", k)
+		}
+	}
+
+	if err := bondsTTLUpgrade(db.bonds); err != nil {
+		t.Fatalf("bondsTTLUpgrade error: %v", err)
+	}
+	exps = expirations()
+	if len(exps) != 1 {
+		t.Fatalf("expected 1 bond after upgrade, got %d", len(exps))
+	}
+	if _, err := db.Bond(expired.CoinID); !errors.Is(err, lexi.ErrKeyNotFound) {
+		t.Fatalf("expired bond not deleted by upgrade: %v", err)
+	}
+	exp, found := exps[string(live.CoinID)]
+	if !found {
+		t.Fatal("live bond not found after upgrade")
+	}
+	if d := exp.Sub(live.Expiration); d < -time.Second || d > time.Second {
+		t.Fatalf("live bond expires at %v, wanted %v", exp, live.Expiration)
+	}
+	// The bonder index entry must expire with the bond.
+	var n int
+	if err := db.bonderIdx.Iterate(peer[:], func(it *lexi.Iter) error {
+		n++
+		if it.ExpiresAt().IsZero() {
+			t.Fatal("bonder index entry has no TTL after upgrade")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("bonderIdx.Iterate error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 bonder index entry, got %d", n)
+	}
+}
+
 func TestReputation(t *testing.T) {
 	db, shutdown := tNewDB()
 	defer shutdown()
@@ -151,4 +222,96 @@ func TestReputation(t *testing.T) {
 	if n != tanka.MaxReputationEntries {
 		t.Fatalf("Wrong number of remaining entries. Expected %d, got %d", tanka.MaxReputationEntries, n)
 	}
+
+	// Negative scores reduce the aggregate score.
+	scored = tanka.PeerID{0x01}
+	for i, score := range []int8{-5, 2} {
+		if err := db.SetScore(scored, tanka.PeerID{byte(i + 1)}, score, time.Now()); err != nil {
+			t.Fatalf("SetScore(%d) error: %v", score, err)
+		}
+	}
+	if rep, err = db.Reputation(scored); err != nil {
+		t.Fatalf("Reputation error: %v", err)
+	}
+	if rep.Score != -3 {
+		t.Fatalf("Wrong aggregate score with negative entry. Expected -3, got %d", rep.Score)
+	}
+}
+
+func TestScoreReports(t *testing.T) {
+	db, shutdown := tNewDB()
+	defer shutdown()
+
+	scorer, scored := tanka.PeerID{0x01}, tanka.PeerID{0x02}
+	stamp := time.Now().Truncate(time.Millisecond)
+	r := &tanka.ScoreReport{
+		Scorer: scorer,
+		Scored: scored,
+		Score:  5,
+		Stamp:  stamp,
+		Sig:    []byte{0x03},
+	}
+	checkStore := func(expStored bool) {
+		t.Helper()
+		stored, err := db.StoreScoreReport(r)
+		if err != nil {
+			t.Fatalf("StoreScoreReport error: %v", err)
+		}
+		if stored != expStored {
+			t.Fatalf("Expected stored = %t, got %t", expStored, stored)
+		}
+	}
+	checkStore(true)
+	// Duplicates are not stored.
+	checkStore(false)
+	// Neither are older reports.
+	r.Score, r.Stamp = -5, stamp.Add(-time.Second)
+	checkStore(false)
+	if rep, _ := db.Reputation(scored); rep.Score != 5 {
+		t.Fatalf("Older report replaced the newer one. Score = %d", rep.Score)
+	}
+	// Newer reports replace the stored report.
+	r.Stamp = stamp.Add(time.Second)
+	checkStore(true)
+	if rep, _ := db.Reputation(scored); rep.Score != -5 || rep.Depth != 1 {
+		t.Fatalf("Wrong reputation after newer report. Score = %d, Depth = %d", rep.Score, rep.Depth)
+	}
+
+	// Unsigned scores are not shared.
+	db.SetScore(scored, tanka.PeerID{0x04}, 1, time.Now())
+	reports, err := db.ScoreReports(time.Time{})
+	if err != nil {
+		t.Fatalf("ScoreReports error: %v", err)
+	}
+	if len(reports) != 1 {
+		t.Fatalf("Expected 1 report, got %d", len(reports))
+	}
+	reR := reports[0]
+	if reR.Scorer != scorer || reR.Scored != scored || reR.Score != -5 || !reR.Stamp.Equal(r.Stamp) || !bytes.Equal(reR.Sig, r.Sig) {
+		t.Fatalf("Wrong report retrieved: %+v", reR)
+	}
+	if reports, _ = db.ScoreReports(r.Stamp); len(reports) != 0 {
+		t.Fatalf("Expected no reports after the stamp, got %d", len(reports))
+	}
+}
+
+func TestGossipSync(t *testing.T) {
+	db, shutdown := tNewDB()
+	defer shutdown()
+
+	peer := tanka.PeerID{0x01}
+	stamp, err := db.GossipSync(peer)
+	if err != nil {
+		t.Fatalf("GossipSync error: %v", err)
+	}
+	if !stamp.IsZero() {
+		t.Fatalf("Expected zero stamp for unknown node, got %s", stamp)
+	}
+	now := time.Now().Truncate(time.Millisecond)
+	if err := db.SetGossipSync(peer, now); err != nil {
+		t.Fatalf("SetGossipSync error: %v", err)
+	}
+	if stamp, _ = db.GossipSync(peer); !stamp.Equal(now) {
+		t.Fatalf("Wrong stamp. %s != %s", stamp, now)
+	}
 }
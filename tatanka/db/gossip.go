@@ -0,0 +1,34 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package db
+
+import (
+	"errors"
+	"time"
+
+	"decred.org/dcrdex/dex/encode"
+	"decred.org/dcrdex/dex/lexi"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+// SetGossipSync stores the time that we last caught up with the tatanka node.
+func (d *DB) SetGossipSync(peerID tanka.PeerID, stamp time.Time) error {
+	return d.gossip.Set(peerID[:], encode.Uint64Bytes(uint64(stamp.UnixMilli())), lexi.WithReplace())
+}
+
+// GossipSync retrieves the time that we last caught up with the tatanka node.
+// The zero time is returned if we've never synced with the node.
+func (d *DB) GossipSync(peerID tanka.PeerID) (time.Time, error) {
+	b, err := d.gossip.GetRaw(peerID[:])
+	if err != nil {
+		if errors.Is(err, lexi.ErrKeyNotFound) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, err
+	}
+	if len(b) != 8 {
+		return time.Time{}, errors.New("invalid gossip sync stamp")
+	}
+	return time.UnixMilli(int64(encode.BytesToUint64(b))), nil
+}
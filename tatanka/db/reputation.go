@@ -4,11 +4,14 @@
 package db
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/lexi"
 	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/dgraph-io/badger/v4"
 )
 
 type dbScore struct {
@@ -16,21 +19,115 @@ type dbScore struct {
 	scored tanka.PeerID
 	score  int8
 	stamp  time.Time
+	sig    []byte
 }
 
+// MarshalBinary encodes the score, followed by the stamp and the scorer's
+// signature. Scores stored before signed reports were shared are only the
+// single score byte.
 func (s *dbScore) MarshalBinary() ([]byte, error) {
-	return []byte{byte(s.score)}, nil
+	b := make([]byte, 0, 1+8+len(s.sig))
+	b = append(b, byte(s.score))
+	b = append(b, encode.Uint64Bytes(uint64(s.stamp.UnixMilli()))...)
+	return append(b, s.sig...), nil
+}
+
+func (s *dbScore) UnmarshalBinary(b []byte) error {
+	if len(b) == 0 {
+		return errors.New("empty score")
+	}
+	s.score = int8(b[0])
+	if len(b) == 1 {
+		return nil
+	}
+	if len(b) < 9 {
+		return fmt.Errorf("invalid score length %d", len(b))
+	}
+	s.stamp = time.UnixMilli(int64(encode.BytesToUint64(b[1:9])))
+	s.sig = encode.CopySlice(b[9:])
+	return nil
+}
+
+func scoreKey(scored, scorer tanka.PeerID) []byte {
+	return append(scored[:], scorer[:]...)
 }
 
 func (d *DB) SetScore(scored, scorer tanka.PeerID, score int8, stamp time.Time) error {
-	k := append(scored[:], scorer[:]...)
 	s := &dbScore{
 		scorer: scorer,
 		scored: scored,
 		score:  score,
 		stamp:  stamp,
 	}
-	return d.scores.Set(k, s, lexi.WithReplace())
+	return d.scores.Set(scoreKey(scored, scorer), s, lexi.WithReplace())
+}
+
+// StoreScoreReport stores the signed score report if it is newer than the
+// stored report from the same scorer for the same peer. The returned bool is
+// false if the report was a duplicate or was older than the stored report. The
+// signature should be verified by the caller.
+func (d *DB) StoreScoreReport(r *tanka.ScoreReport) (stored bool, err error) {
+	k := scoreKey(r.Scored, r.Scorer)
+	return stored, d.Update(func(txn *badger.Txn) error {
+		b, err := d.scores.GetRaw(k, lexi.WithGetTxn(txn))
+		switch {
+		case err == nil:
+			var s dbScore
+			if err := s.UnmarshalBinary(b); err != nil {
+				return err
+			}
+			if !r.Stamp.Truncate(time.Millisecond).After(s.stamp) {
+				return nil
+			}
+		case !errors.Is(err, lexi.ErrKeyNotFound):
+			return err
+		}
+		s := &dbScore{
+			scorer: r.Scorer,
+			scored: r.Scored,
+			score:  r.Score,
+			stamp:  r.Stamp,
+			sig:    r.Sig,
+		}
+		if err := d.scores.Set(k, s, lexi.WithReplace(), lexi.WithTxn(txn)); err != nil {
+			return err
+		}
+		stored = true
+		return nil
+	})
+}
+
+// ScoreReports retrieves the signed score reports stamped after the specified
+// time.
+func (d *DB) ScoreReports(since time.Time) ([]*tanka.ScoreReport, error) {
+	var reports []*tanka.ScoreReport
+	return reports, d.scores.Iterate(nil, func(it *lexi.Iter) error {
+		var s dbScore
+		if err := it.V(func(vB []byte) error {
+			return s.UnmarshalBinary(vB)
+		}); err != nil {
+			return err
+		}
+		if len(s.sig) == 0 || !s.stamp.After(since) {
+			return nil
+		}
+		k, err := it.K()
+		if err != nil {
+			return err
+		}
+		if len(k) != tanka.PeerIDLength*2 {
+			return fmt.Errorf("invalid score key length %d", len(k))
+		}
+		r := &tanka.ScoreReport{
+			Score: s.score,
+			Stamp: s.stamp,
+			Sig:   s.sig,
+		}
+		copy(r.Scored[:], k[:tanka.PeerIDLength])
+		copy(r.Scorer[:], k[tanka.PeerIDLength:])
+		reports = append(reports, r)
+		return nil
+	})
 }
 
 func (d *DB) Reputation(scored tanka.PeerID) (*tanka.Reputation, error) {
@@ -41,10 +138,10 @@ func (d *DB) Reputation(scored tanka.PeerID) (*tanka.Reputation, error) {
 			return it.Delete()
 		}
 		if err := it.V(func(vB []byte) error {
-			if len(vB) != 1 {
-				return fmt.Errorf("score not a single byte. length = %d", len(vB))
+			if len(vB) == 0 {
+				return errors.New("empty score")
 			}
-			agg.Score += int64(vB[0])
+			agg.Score += int64(int8(vB[0]))
 			return nil
 		}); err != nil {
 			return err
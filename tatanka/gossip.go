@@ -0,0 +1,248 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package tatanka
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"decred.org/dcrdex/dex/lexi"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/tatanka/mj"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+// Bonds and score reports are gossiped between tatanka nodes so that a
+// client's tier is the same regardless of which node they connect to. New
+// bonds and reports are forwarded to all connected tatanka nodes except the
+// one they were received from. Items that are already known are not
+// forwarded, which ends the gossip. A node that was offline catches up by
+// requesting a mj.GossipSync when the connection to another node is
+// established.
+//
+// Conflict rules:
+//  - A score report must be signed by the scorer. For a scorer-scored pair,
+//    the report with the latest stamp wins. Reports from the future are
+//    ignored.
+//  - A bond is keyed on its coin ID and the first bond stored for a coin ID
+//    wins. If the node has a backend for the bond's chain, the bond must also
+//    pass validation, so a bond that commits to a different peer can't be
+//    stored. Bonds for chains the node doesn't support are accepted on the
+//    authority of the whitelisted node that shared them.
+
+// gossipSyncOverlap is subtracted from the stamp of the last sync when
+// catching up. A report can reach the remote node well after it was stamped, so
+// some reports that we've already seen are requested again.
+const gossipSyncOverlap = time.Hour
+
+// acceptScoreReport stores a verified score report and, if the report is new,
+// updates the scored client's reputation and shares the report with the other
+// tatanka nodes. from is nil if the report was received from a local client.
+func (t *Tatanka) acceptScoreReport(r *tanka.ScoreReport, from *remoteTatanka) bool {
+	if time.Until(r.Stamp) > tanka.EpochLength {
+		t.log.Errorf("Ignoring score report from %s for %s stamped in the future", r.Scorer, r.Scored)
+		return false
+	}
+	stored, err := t.db.StoreScoreReport(r)
+	if err != nil {
+		t.log.Errorf("error storing score from %s for %s: %v", r.Scorer, r.Scored, err)
+		return false
+	}
+	if !stored {
+		return false
+	}
+	rep, err := t.db.Reputation(r.Scored)
+	if err != nil {
+		t.log.Errorf("error getting reputation after score update from %s for %s: %v", r.Scorer, r.Scored, err)
+		return true
+	}
+	if c := t.clientNode(r.Scored); c != nil {
+		c.mtx.Lock()
+		c.Reputation = rep
+		c.mtx.Unlock()
+	}
+	t.gossip(mj.MustNotification(mj.RouteShareScore, &mj.SharedScore{
+		Report:     r,
+		Reputation: rep,
+	}), from)
+	return true
+}
+
+// acceptBond validates and stores a bond shared by a tatanka node. The
+// returned bool is false if the bond was already known.
+func (t *Tatanka) acceptBond(b *tanka.Bond) (bool, error) {
+	if b == nil || len(b.CoinID) == 0 {
+		return false, errors.New("no coin ID")
+	}
+	if b.Expiration.Before(time.Now()) {
+		return false, nil
+	}
+	existing, err := t.db.Bond(b.CoinID)
+	switch {
+	case err == nil:
+		if existing.PeerID != b.PeerID {
+			return false, fmt.Errorf("bond %s for %s conflicts with stored bond for %s", b.CoinID, b.PeerID, existing.PeerID)
+		}
+		return false, nil
+	case !errors.Is(err, lexi.ErrKeyNotFound):
+		return false, fmt.Errorf("error retrieving bond: %w", err)
+	}
+	t.chainMtx.RLock()
+	ch := t.chains[b.AssetID]
+	t.chainMtx.RUnlock()
+	if ch != nil {
+		if err := ch.CheckBond(b); err != nil {
+			return false, fmt.Errorf("bond %s didn't pass validation for chain %d: %w", b.CoinID, b.AssetID, err)
+		}
+	}
+	if err := t.db.StoreBond(b); err != nil {
+		return false, fmt.Errorf("error storing bond: %w", err)
+	}
+	return true, nil
+}
+
+// acceptBonds stores the bonds shared by a tatanka node, updates the bonds of
+// any locally connected clients, and returns the bonds that were new.
+func (t *Tatanka) acceptBonds(bonds []*tanka.Bond, from *remoteTatanka) []*tanka.Bond {
+	var newBonds []*tanka.Bond
+	for _, b := range bonds {
+		stored, err := t.acceptBond(b)
+		if err != nil {
+			t.log.Errorf("Rejected bond shared by %s: %v", from.ID, err)
+			continue
+		}
+		if stored {
+			newBonds = append(newBonds, b)
+		}
+	}
+	t.refreshClientBonds(newBonds)
+	return newBonds
+}
+
+// refreshClientBonds updates the bonds of any locally connected clients that
+// the bonds are for.
+func (t *Tatanka) refreshClientBonds(bonds []*tanka.Bond) {
+	peers := make(map[tanka.PeerID]struct{})
+	for _, b := range bonds {
+		peers[b.PeerID] = struct{}{}
+	}
+	for peerID := range peers {
+		c := t.clientNode(peerID)
+		if c == nil {
+			continue
+		}
+		liveBonds, err := t.db.GetBonds(peerID)
+		if err != nil {
+			t.log.Errorf("Error retrieving bonds for client %s in db: %v", peerID, err)
+			continue
+		}
+		c.updateBonds(liveBonds)
+	}
+}
+
+// shareBonds shares new bonds with the tatanka nodes.
+func (t *Tatanka) shareBonds(bonds []*tanka.Bond, from *remoteTatanka) {
+	if len(bonds) == 0 {
+		return
+	}
+	t.gossip(mj.MustNotification(mj.RouteShareBonds, bonds), from)
+}
+
+// gossip sends the notification to all tatanka nodes except the one it was
+// received from.
+func (t *Tatanka) gossip(note *msgjson.Message, from *remoteTatanka) {
+	for _, tt := range t.tatankaNodes() {
+		if from != nil && tt.ID == from.ID {
+			continue
+		}
+		if err := t.send(tt, note); err != nil {
+			t.log.Errorf("error sending %s to %s: %v", note.Route, tt.ID, err)
+		}
+	}
+}
+
+// handleShareBonds handles bonds shared by a remote tatanka node.
+func (t *Tatanka) handleShareBonds(tt *remoteTatanka, msg *msgjson.Message) {
+	var bonds []*tanka.Bond
+	if err := msg.Unmarshal(&bonds); err != nil {
+		t.log.Errorf("error unmarshaling shared bonds from %s: %v", tt.ID, err)
+		return
+	}
+	t.shareBonds(t.acceptBonds(bonds, tt), tt)
+}
+
+// handleGossipSync responds to a remote tatanka node's request for the bonds
+// and score reports that it may have missed.
+func (t *Tatanka) handleGossipSync(tt *remoteTatanka, msg *msgjson.Message) *msgjson.Error {
+	var req mj.GossipSyncRequest
+	if err := msg.Unmarshal(&req); err != nil {
+		t.log.Errorf("error unmarshaling gossip sync request from %s: %v", tt.ID, err)
+		return msgjson.NewError(mj.ErrBadRequest, "bad request")
+	}
+	stamp := time.Now()
+	bonds, err := t.db.LiveBonds()
+	if err != nil {
+		t.log.Errorf("error retrieving bonds for gossip sync: %v", err)
+		return msgjson.NewError(mj.ErrInternal, "internal error")
+	}
+	scores, err := t.db.ScoreReports(req.Since)
+	if err != nil {
+		t.log.Errorf("error retrieving score reports for gossip sync: %v", err)
+		return msgjson.NewError(mj.ErrInternal, "internal error")
+	}
+	t.sendResult(tt, msg.ID, &mj.GossipSync{
+		Bonds:  bonds,
+		Scores: scores,
+		Stamp:  stamp,
+	})
+	return nil
+}
+
+// syncGossip catches up on the bonds and score reports known to the tatanka
+// node. Anything new is shared with the other tatanka nodes.
+func (t *Tatanka) syncGossip(tt *remoteTatanka) {
+	since, err := t.db.GossipSync(tt.ID)
+	if err != nil {
+		t.log.Errorf("error retrieving gossip sync stamp for %s: %v", tt.ID, err)
+		return
+	}
+	errC := make(chan error, 1)
+	var sync mj.GossipSync
+	if !since.IsZero() {
+		since = since.Add(-gossipSyncOverlap)
+	}
+	req := mj.MustRequest(mj.RouteGossipSync, &mj.GossipSyncRequest{Since: since})
+	if err := t.request(tt, req, func(msg *msgjson.Message) {
+		errC <- msg.UnmarshalResult(&sync)
+	}); err != nil {
+		t.log.Errorf("error requesting gossip sync from %s: %v", tt.ID, err)
+		return
+	}
+	select {
+	case err = <-errC:
+	case <-time.After(time.Minute):
+		err = errors.New("timed out")
+	}
+	if err != nil {
+		t.log.Errorf("error syncing gossip with %s: %v", tt.ID, err)
+		return
+	}
+
+	t.shareBonds(t.acceptBonds(sync.Bonds, tt), tt)
+	var nScores int
+	for _, r := range sync.Scores {
+		if err := r.Verify(); err != nil {
+			t.log.Errorf("bad score report signature from %s synced from %s: %v", r.Scorer, tt.ID, err)
+			continue
+		}
+		if t.acceptScoreReport(r, tt) {
+			nScores++
+		}
+	}
+	if err := t.db.SetGossipSync(tt.ID, sync.Stamp); err != nil {
+		t.log.Errorf("error storing gossip sync stamp for %s: %v", tt.ID, err)
+	}
+	t.log.Debugf("Synced %d bonds and %d new score reports from %s", len(sync.Bonds), nScores, tt.ID)
+}
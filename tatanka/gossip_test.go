@@ -0,0 +1,235 @@
+package tatanka
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/tatanka/chain"
+	"decred.org/dcrdex/tatanka/db"
+	"decred.org/dcrdex/tatanka/mj"
+	"decred.org/dcrdex/tatanka/tanka"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// tLink is a tanka.Sender that delivers messages to another in-process
+// Tatanka node. The link is held by one node and represents the other.
+type tLink struct {
+	to   *Tatanka
+	id   tanka.PeerID // to.id
+	back *tLink
+
+	mtx          sync.Mutex
+	respHandlers map[uint64]func(*msgjson.Message)
+}
+
+func (l *tLink) deliver(msg *msgjson.Message) error {
+	// Encode and decode, like the real thing, so the receiver doesn't share
+	// the message with the sender.
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return l.SendRaw(b)
+}
+
+func (l *tLink) Send(msg *msgjson.Message) error {
+	return l.deliver(msg)
+}
+
+func (l *tLink) SendRaw(b []byte) error {
+	msg, err := msgjson.DecodeMessage(b)
+	if err != nil {
+		return err
+	}
+	if msg.Type == msgjson.Response {
+		// Responses go to the handler registered on our counterpart's link.
+		l.back.mtx.Lock()
+		f := l.back.respHandlers[msg.ID]
+		delete(l.back.respHandlers, msg.ID)
+		l.back.mtx.Unlock()
+		if f != nil {
+			go f(msg)
+		}
+		return nil
+	}
+	go (&tcpCore{l.to}).HandleMessage(l.back, msg)
+	return nil
+}
+
+func (l *tLink) Request(msg *msgjson.Message, respHandler func(*msgjson.Message)) error {
+	l.mtx.Lock()
+	l.respHandlers[msg.ID] = respHandler
+	l.mtx.Unlock()
+	return l.deliver(msg)
+}
+
+func (l *tLink) RequestRaw(msgID uint64, b []byte, respHandler func(*msgjson.Message)) error {
+	l.mtx.Lock()
+	l.respHandlers[msgID] = respHandler
+	l.mtx.Unlock()
+	return l.SendRaw(b)
+}
+
+func (l *tLink) SetPeerID(tanka.PeerID) {}
+
+func (l *tLink) PeerID() tanka.PeerID {
+	return l.id
+}
+
+func (l *tLink) Disconnect() {}
+
+func tNewDBTatanka(t *testing.T) *Tatanka {
+	t.Helper()
+	tt := tNewTatanka()
+	tt.wg = new(sync.WaitGroup)
+	var err error
+	if tt.db, err = db.New(t.TempDir(), dex.StdOutLogger("DB", dex.LevelInfo)); err != nil {
+		t.Fatalf("db.New error: %v", err)
+	}
+	tt.chains = make(map[uint32]chain.Chain)
+	tt.prepareHandlers()
+	return tt
+}
+
+// tLinkTatankas connects two in-process nodes, returning their views of each
+// other.
+func tLinkTatankas(a, b *Tatanka) (bAtA, aAtB *remoteTatanka) {
+	ab := &tLink{to: b, id: b.id, respHandlers: make(map[uint64]func(*msgjson.Message))}
+	ba := &tLink{to: a, id: a.id, respHandlers: make(map[uint64]func(*msgjson.Message))}
+	ab.back, ba.back = ba, ab
+	newRemote := func(n *Tatanka, s tanka.Sender) *remoteTatanka {
+		tt := &remoteTatanka{peer: &peer{
+			Peer:   &tanka.Peer{ID: n.id, PubKey: n.priv.PubKey(), Reputation: new(tanka.Reputation)},
+			Sender: s,
+			rrs:    make(map[tanka.PeerID]*tanka.Reputation),
+		}}
+		tt.cfg.Store(n.generateConfig(0))
+		return tt
+	}
+	bAtA, aAtB = newRemote(b, ab), newRemote(a, ba)
+	a.tatankasMtx.Lock()
+	a.tatankas[b.id] = bAtA
+	a.tatankasMtx.Unlock()
+	b.tatankasMtx.Lock()
+	b.tatankas[a.id] = aAtB
+	b.tatankasMtx.Unlock()
+	return
+}
+
+func tWaitFor(t *testing.T, desc string, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		if f() {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Fatalf("timed out waiting for %s", desc)
+}
+
+func TestGossip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("in-process nodes with databases")
+	}
+	// a <=> b <=> c. a and c are not connected, so anything reaching c from a
+	// was forwarded by b.
+	a, b, c := tNewDBTatanka(t), tNewDBTatanka(t), tNewDBTatanka(t)
+	tLinkTatankas(a, b)
+	tLinkTatankas(b, c)
+
+	scorerPriv, _ := secp256k1.GeneratePrivateKey()
+	var scorer, scored tanka.PeerID
+	copy(scorer[:], scorerPriv.PubKey().SerializeCompressed())
+	scored[0] = 0x02
+
+	reputation := func(n *Tatanka) *tanka.Reputation {
+		rep, err := n.db.Reputation(scored)
+		if err != nil {
+			t.Fatalf("Reputation error: %v", err)
+		}
+		return rep
+	}
+
+	// A score reported to a reaches c.
+	r := &tanka.ScoreReport{Scorer: scorer, Scored: scored, Score: 10, Stamp: time.Now()}
+	r.Sign(scorerPriv)
+	if !a.acceptScoreReport(r, nil) {
+		t.Fatal("score report not accepted")
+	}
+	tWaitFor(t, "score to reach c", func() bool { return reputation(c).Score == 10 })
+
+	// A tampered report is rejected.
+	bad := *r
+	bad.Score, bad.Stamp = -10, r.Stamp.Add(time.Second)
+	if err := bad.Verify(); err == nil {
+		t.Fatal("tampered report verified")
+	}
+	ttB := c.tatankaNode(b.id)
+	c.handleShareScore(ttB, mj.MustNotification(mj.RouteShareScore, &mj.SharedScore{Report: &bad}))
+	if reputation(c).Score != 10 {
+		t.Fatal("tampered report accepted")
+	}
+
+	// A newer report replaces the old one everywhere, and an older one is
+	// ignored.
+	r2 := &tanka.ScoreReport{Scorer: scorer, Scored: scored, Score: -3, Stamp: r.Stamp.Add(time.Second)}
+	r2.Sign(scorerPriv)
+	if !c.acceptScoreReport(r2, nil) {
+		t.Fatal("newer score report not accepted")
+	}
+	tWaitFor(t, "newer score to reach a", func() bool { return reputation(a).Score == -3 })
+	if a.acceptScoreReport(r, nil) {
+		t.Fatal("older score report accepted")
+	}
+	if rep := reputation(a); rep.Depth != 1 {
+		t.Fatalf("expected 1 score, got %d", rep.Depth)
+	}
+
+	// Bonds posted at a reach c.
+	bonder := tanka.PeerID{0x03}
+	bond := &tanka.Bond{
+		PeerID:     bonder,
+		AssetID:    42,
+		CoinID:     []byte{0x01},
+		Strength:   1,
+		Expiration: time.Now().Add(time.Hour),
+	}
+	if err := a.db.StoreBond(bond); err != nil {
+		t.Fatalf("StoreBond error: %v", err)
+	}
+	a.shareBonds([]*tanka.Bond{bond}, nil)
+	tWaitFor(t, "bond to reach c", func() bool {
+		bonds, _ := c.db.GetBonds(bonder)
+		return len(bonds) == 1
+	})
+
+	// A conflicting bond for the same coin is rejected.
+	conflict := *bond
+	conflict.PeerID = tanka.PeerID{0x04}
+	if _, err := c.acceptBond(&conflict); err == nil {
+		t.Fatal("conflicting bond accepted")
+	}
+	// A known bond is not new.
+	if stored, err := c.acceptBond(bond); err != nil || stored {
+		t.Fatalf("known bond stored = %t, err = %v", stored, err)
+	}
+
+	// A node that was offline catches up when it connects.
+	d := tNewDBTatanka(t)
+	aAtD, _ := tLinkTatankas(d, a)
+	d.syncGossip(aAtD)
+	if rep, _ := d.db.Reputation(scored); rep.Score != -3 || rep.Depth != 1 {
+		t.Fatalf("wrong reputation after catch-up: %+v", rep)
+	}
+	if bonds, _ := d.db.GetBonds(bonder); len(bonds) != 1 {
+		t.Fatalf("expected 1 bond after catch-up, got %d", len(bonds))
+	}
+	if stamp, _ := d.db.GossipSync(a.id); stamp.IsZero() {
+		t.Fatal("gossip sync stamp not stored")
+	}
+}
@@ -27,6 +27,7 @@ const (
 	ErrFailedRelay
 	ErrUnknownSender
 	ErrCapacity
+	ErrRateLimit
 )
 
 const (
@@ -39,6 +40,8 @@ const (
 	RouteRelayTankagram   = "relay_tankagram"
 	RoutePathInquiry      = "path_inquiry"
 	RouteShareScore       = "share_score"
+	RouteShareBonds       = "share_bonds"
+	RouteGossipSync       = "gossip_sync"
 
 	// tatanka <=> client
 	RouteConnect             = "connect"
@@ -49,11 +52,16 @@ const (
 	RouteRates               = "rates"
 	RouteSetScore            = "set_score"
 	RouteFeeRateEstimate     = "fee_rate_estimate"
+	RouteAuditHTLC           = "audit_htlc"
 
 	// client1 <=> tatankanode <=> client2
 	RouteTankagram     = "tankagram"
 	RouteEncryptionKey = "encryption_key"
 	RouteNegotiate     = "negotiate"
+	RouteSwapAddress   = "swap_address"
+	RouteSwapContract  = "swap_contract"
+	RouteSwapRedeem    = "swap_redeem"
+	RouteOrderSnapshot = "order_snapshot"
 	RouteBroadcast     = "broadcast"
 	RouteNewSubscriber = "new_subscriber"
 
@@ -72,6 +80,7 @@ type BroadcastMessageType string
 const (
 	MessageTypeTrollBox      BroadcastMessageType = "troll_box"
 	MessageTypeNewOrder      BroadcastMessageType = "new_order"
+	MessageTypeOrderUpdate   BroadcastMessageType = "order_update"
 	MessageTypeNewSubscriber BroadcastMessageType = "new_subscriber"
 	MessageTypeUnsubTopic    BroadcastMessageType = "unsub_topic"
 	MessageTypeUnsubSubject  BroadcastMessageType = "unsub_subject"
@@ -219,15 +228,60 @@ type NewSubscriber struct {
 type ScoreReport struct {
 	PeerID tanka.PeerID `json:"peerID"`
 	Score  int8         `json:"score"`
+	// Stamp and Sig are the tanka.ScoreReport stamp and signature, which
+	// allow the report to be shared with other tatanka nodes.
+	Stamp time.Time `json:"stamp"`
+	Sig   dex.Bytes `json:"sig"`
 }
 
-// SharedScore is a scorer-scored tuple shared between tatanka nodes, along
+// SwapAddress is sent to the counterparty of a match to communicate the
+// address that our counterparty's swap contract should pay.
+type SwapAddress struct {
+	MatchID tanka.ID32 `json:"matchID"`
+	Address string     `json:"address"`
+}
+
+// SwapContract is sent to the counterparty of a match after our swap
+// contract is broadcast.
+type SwapContract struct {
+	MatchID  tanka.ID32 `json:"matchID"`
+	CoinID   dex.Bytes  `json:"coinID"`
+	Contract dex.Bytes  `json:"contract"`
+	TxData   dex.Bytes  `json:"txData,omitempty"`
+	// SecretHash is the initiator's secret hash. The participant must use
+	// the same secret hash for their contract.
+	SecretHash dex.Bytes `json:"secretHash"`
+}
+
+// SwapRedeem is sent to the counterparty of a match after we redeem their
+// swap contract.
+type SwapRedeem struct {
+	MatchID tanka.ID32 `json:"matchID"`
+	CoinID  dex.Bytes  `json:"coinID"`
+	Secret  dex.Bytes  `json:"secret"`
+}
+
+// SharedScore is a signed score report shared between tatanka nodes, along
 // with the sending tatanka's new view of the scored peer's reputation.
 type SharedScore struct {
-	Scorer     tanka.PeerID      `json:"scorer"`
-	Scored     tanka.PeerID      `json:"scored"`
-	Score      int8              `json:"score"`
-	Reputation *tanka.Reputation `json:"rep"`
+	Report     *tanka.ScoreReport `json:"report"`
+	Reputation *tanka.Reputation  `json:"rep"`
+}
+
+// GossipSyncRequest is a tatanka node's request for the bonds and score
+// reports that it may have missed while not connected to the receiving node.
+type GossipSyncRequest struct {
+	// Since is the Stamp of the last GossipSync received from the node. Only
+	// score reports stamped after Since are returned.
+	Since time.Time `json:"since"`
+}
+
+// GossipSync is the response to a GossipSyncRequest.
+type GossipSync struct {
+	// Bonds are all live bonds known to the responding node.
+	Bonds  []*tanka.Bond        `json:"bonds"`
+	Scores []*tanka.ScoreReport `json:"scores"`
+	Stamp  time.Time            `json:"stamp"`
 }
 
 func MustRequest(route string, payload any) *msgjson.Message {
@@ -27,6 +27,17 @@ func (p *peer) banned() bool {
 	return tier <= 0
 }
 
+// tier is the peer's bond tier adjusted for reputation.
+func (p *peer) tier() int64 {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	rep := p.Reputation
+	if rep == nil {
+		rep = new(tanka.Reputation)
+	}
+	return calcTier(rep, p.BondTier())
+}
+
 // bondTier is the peer's current bonded tier.
 func (p *peer) bondTier() uint64 {
 	p.mtx.RLock()
@@ -0,0 +1,200 @@
+// This code is available on the terms of the project LICENSE.md file,
+// also available online at https://blueoakcouncil.org/license/1.0.0.
+
+package tatanka
+
+import (
+	"sync"
+	"time"
+
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+const (
+	defaultQuotaInterval   = time.Minute
+	defaultBaseQuota       = 30
+	defaultQuotaPerTier    = 30
+	defaultMaxViolations   = 3
+	defaultViolationWindow = time.Minute * 10
+	defaultBanDuration     = time.Minute * 30
+)
+
+// RateLimitConfig is the configuration of the broadcast quotas for locally
+// connected clients. Each client can broadcast a limited number of messages to
+// each topic per interval. The quota grows with the client's tier, which is
+// their bond tier adjusted for reputation. Broadcasts over the quota are
+// dropped. A client that exceeds a quota in MaxViolations intervals within the
+// violation window is banned for a while. Zero values are replaced with
+// defaults.
+type RateLimitConfig struct {
+	// IntervalSecs is the length of the quota interval in seconds.
+	IntervalSecs uint64 `json:"intervalSecs"`
+	// BaseQuota is the number of broadcasts per topic per interval allowed for
+	// a client with a tier of zero or less.
+	BaseQuota uint64 `json:"baseQuota"`
+	// QuotaPerTier is the number of additional broadcasts per topic per
+	// interval allowed for each tier.
+	QuotaPerTier uint64 `json:"quotaPerTier"`
+	// MaxViolations is the number of intervals in which a client can exceed a
+	// quota within the violation window before they are banned.
+	MaxViolations int `json:"maxViolations"`
+	// ViolationWindowSecs is how long a violation counts against a client.
+	ViolationWindowSecs uint64 `json:"violationWindowSecs"`
+	// BanSecs is the length of a ban in seconds.
+	BanSecs uint64 `json:"banSecs"`
+}
+
+// quotaResult is the outcome of a client's broadcast with respect to their
+// quota.
+type quotaResult int
+
+const (
+	// quotaOK means the broadcast is within the quota.
+	quotaOK quotaResult = iota
+	// quotaViolation means the broadcast is the first over the quota for the
+	// topic in this interval.
+	quotaViolation
+	// quotaExceeded means the broadcast is over the quota, and the violation
+	// was already recorded.
+	quotaExceeded
+	// quotaBanned means the client is banned, either already or as a result of
+	// this broadcast.
+	quotaBanned
+)
+
+// topicQuota tracks a client's broadcasts to a topic in the current interval.
+type topicQuota struct {
+	start    time.Time
+	count    uint64
+	violated bool
+}
+
+type clientQuotas struct {
+	topics      map[tanka.Topic]*topicQuota
+	violations  []time.Time
+	bannedUntil time.Time
+	lastSeen    time.Time
+}
+
+// rateLimiter enforces the broadcast quotas. Quotas and bans are tracked by
+// peer ID, so they persist through reconnects.
+type rateLimiter struct {
+	interval        time.Duration
+	baseQuota       uint64
+	quotaPerTier    uint64
+	maxViolations   int
+	violationWindow time.Duration
+	banDuration     time.Duration
+
+	mtx     sync.Mutex
+	clients map[tanka.PeerID]*clientQuotas
+}
+
+func newRateLimiter(cfg *RateLimitConfig) *rateLimiter {
+	if cfg == nil {
+		cfg = new(RateLimitConfig)
+	}
+	secs := func(s uint64, def time.Duration) time.Duration {
+		if s == 0 {
+			return def
+		}
+		return time.Duration(s) * time.Second
+	}
+	r := &rateLimiter{
+		interval:        secs(cfg.IntervalSecs, defaultQuotaInterval),
+		baseQuota:       cfg.BaseQuota,
+		quotaPerTier:    cfg.QuotaPerTier,
+		maxViolations:   cfg.MaxViolations,
+		violationWindow: secs(cfg.ViolationWindowSecs, defaultViolationWindow),
+		banDuration:     secs(cfg.BanSecs, defaultBanDuration),
+		clients:         make(map[tanka.PeerID]*clientQuotas),
+	}
+	if r.baseQuota == 0 {
+		r.baseQuota = defaultBaseQuota
+	}
+	if r.quotaPerTier == 0 {
+		r.quotaPerTier = defaultQuotaPerTier
+	}
+	if r.maxViolations == 0 {
+		r.maxViolations = defaultMaxViolations
+	}
+	return r
+}
+
+// quota is the number of broadcasts per topic per interval allowed for a
+// client with the specified tier.
+func (r *rateLimiter) quota(tier int64) uint64 {
+	if tier <= 0 {
+		return r.baseQuota
+	}
+	return r.baseQuota + uint64(tier)*r.quotaPerTier
+}
+
+// broadcast records a broadcast from the client to the topic. Any result other
+// than quotaOK means the broadcast should be dropped.
+func (r *rateLimiter) broadcast(peerID tanka.PeerID, topic tanka.Topic, tier int64, now time.Time) quotaResult {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	cq, found := r.clients[peerID]
+	if !found {
+		cq = &clientQuotas{topics: make(map[tanka.Topic]*topicQuota)}
+		r.clients[peerID] = cq
+	}
+	cq.lastSeen = now
+	if now.Before(cq.bannedUntil) {
+		return quotaBanned
+	}
+	q, found := cq.topics[topic]
+	if !found || now.Sub(q.start) >= r.interval {
+		q = &topicQuota{start: now}
+		cq.topics[topic] = q
+	}
+	if q.count < r.quota(tier) {
+		q.count++
+		return quotaOK
+	}
+	if q.violated {
+		return quotaExceeded
+	}
+	// First violation for this topic in this interval.
+	q.violated = true
+	cutoff := now.Add(-r.violationWindow)
+	violations := cq.violations[:0]
+	for _, stamp := range cq.violations {
+		if stamp.After(cutoff) {
+			violations = append(violations, stamp)
+		}
+	}
+	cq.violations = append(violations, now)
+	if len(cq.violations) < r.maxViolations {
+		return quotaViolation
+	}
+	cq.bannedUntil = now.Add(r.banDuration)
+	cq.violations = nil
+	cq.topics = make(map[tanka.Topic]*topicQuota)
+	return quotaBanned
+}
+
+// banned returns the end of the client's ban, if they are banned.
+func (r *rateLimiter) banned(peerID tanka.PeerID, now time.Time) (time.Time, bool) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	cq, found := r.clients[peerID]
+	if !found || !now.Before(cq.bannedUntil) {
+		return time.Time{}, false
+	}
+	return cq.bannedUntil, true
+}
+
+// prune forgets clients that have no active ban and haven't broadcast for
+// longer than the violation window.
+func (r *rateLimiter) prune(now time.Time) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for peerID, cq := range r.clients {
+		if now.Before(cq.bannedUntil) || now.Sub(cq.lastSeen) < r.violationWindow {
+			continue
+		}
+		delete(r.clients, peerID)
+	}
+}
@@ -0,0 +1,165 @@
+package tatanka
+
+import (
+	"testing"
+	"time"
+
+	"decred.org/dcrdex/dex/msgjson"
+	"decred.org/dcrdex/tatanka/mj"
+	"decred.org/dcrdex/tatanka/tanka"
+)
+
+func TestRateLimiter(t *testing.T) {
+	r := newRateLimiter(&RateLimitConfig{
+		IntervalSecs:        60,
+		BaseQuota:           2,
+		QuotaPerTier:        3,
+		MaxViolations:       2,
+		ViolationWindowSecs: 600,
+		BanSecs:             1800,
+	})
+	if q := r.quota(-1); q != 2 {
+		t.Fatalf("wrong quota for negative tier %d", q)
+	}
+	if q := r.quota(2); q != 8 {
+		t.Fatalf("wrong quota for tier 2 %d", q)
+	}
+
+	peerID := tanka.PeerID{0x01}
+	now := time.Now()
+	check := func(topic tanka.Topic, tier int64, stamp time.Time, exp quotaResult) {
+		t.Helper()
+		if res := r.broadcast(peerID, topic, tier, stamp); res != exp {
+			t.Fatalf("expected result %d, got %d", exp, res)
+		}
+	}
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaViolation)
+	check("a", 0, now, quotaExceeded)
+	// Other topics have their own quota.
+	check("b", 0, now, quotaOK)
+	// A higher tier gets a bigger quota.
+	check("a", 1, now, quotaOK)
+	// A new interval resets the quota.
+	now = now.Add(time.Minute)
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaOK)
+	// The second violation in the window results in a ban.
+	check("a", 0, now, quotaBanned)
+	if _, banned := r.banned(peerID, now); !banned {
+		t.Fatal("not banned")
+	}
+	check("b", 0, now, quotaBanned)
+	// Bans are not pruned.
+	r.prune(now.Add(time.Hour - time.Second))
+	if _, banned := r.banned(peerID, now.Add(time.Minute*29)); !banned {
+		t.Fatal("ban pruned")
+	}
+	// Bans expire.
+	now = now.Add(time.Minute * 30)
+	if _, banned := r.banned(peerID, now); banned {
+		t.Fatal("ban didn't expire")
+	}
+	check("a", 0, now, quotaOK)
+	// Violations outside of the window are forgotten.
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaViolation)
+	now = now.Add(time.Minute * 11)
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaOK)
+	check("a", 0, now, quotaViolation)
+	// Idle clients are pruned.
+	r.prune(now.Add(time.Minute * 10))
+	if len(r.clients) != 0 {
+		t.Fatal("client not pruned")
+	}
+}
+
+func TestBroadcastFlood(t *testing.T) {
+	srv := tNewTatanka()
+	srv.rateLimiter = newRateLimiter(&RateLimitConfig{
+		BaseQuota:     5,
+		QuotaPerTier:  5,
+		MaxViolations: 2,
+	})
+
+	flooder, flooderSender := tNewClient(1)
+	bonded, _ := tNewClient(2)
+	bonded.Bonds = []*tanka.Bond{{PeerID: bonded.ID, Strength: 1, Expiration: time.Now().Add(time.Hour)}}
+	sub, subSender := tNewClient(3)
+	for _, c := range []*client{flooder, bonded, sub} {
+		srv.clients[c.ID] = c
+	}
+	const topicA, topicB tanka.Topic = "a", "b"
+	for _, topic := range []tanka.Topic{topicA, topicB} {
+		srv.topics[topic] = &Topic{
+			subjects:    make(map[tanka.Subject]map[tanka.PeerID]struct{}),
+			subscribers: map[tanka.PeerID]struct{}{sub.ID: {}},
+		}
+	}
+
+	broadcast := func(c *client, topic tanka.Topic) *msgjson.Error {
+		return srv.handleBroadcast(c, mj.MustRequest(mj.RouteBroadcast, &mj.Broadcast{
+			PeerID:      c.ID,
+			Topic:       topic,
+			MessageType: mj.MessageTypeTrollBox,
+			Stamp:       time.Now(),
+		}))
+	}
+	relayed := func() (n int) {
+		for msg := subSender.received(); msg != nil; msg = subSender.received() {
+			n++
+		}
+		return n
+	}
+
+	var dropped int
+	for i := 0; i < 20; i++ {
+		if msgErr := broadcast(flooder, topicA); msgErr != nil {
+			if msgErr.Code != mj.ErrRateLimit {
+				t.Fatalf("wrong error code %d", msgErr.Code)
+			}
+			dropped++
+		}
+	}
+	if dropped != 15 {
+		t.Fatalf("expected 15 dropped broadcasts, got %d", dropped)
+	}
+	if n := relayed(); n != 5 {
+		t.Fatalf("expected 5 relayed broadcasts, got %d", n)
+	}
+
+	// The bonded client has a bigger quota.
+	for i := 0; i < 20; i++ {
+		broadcast(bonded, topicA)
+	}
+	if n := relayed(); n != 10 {
+		t.Fatalf("expected 10 relayed broadcasts from bonded client, got %d", n)
+	}
+
+	// Flooding another topic is a second violation, and the flooder is banned.
+	for i := 0; i < 6; i++ {
+		broadcast(flooder, topicB)
+	}
+	if !flooderSender.disconnected {
+		t.Fatal("flooder not disconnected")
+	}
+	var banMsg *msgjson.Message
+	for msg := flooderSender.received(); msg != nil; msg = flooderSender.received() {
+		banMsg = msg
+	}
+	if resp, err := banMsg.Response(); err != nil || resp.Error == nil || resp.Error.Code != mj.ErrBanned {
+		t.Fatalf("expected banned error response, got %+v, %v", resp, err)
+	}
+	if n := relayed(); n != 5 {
+		t.Fatalf("expected 5 relayed broadcasts to topic b, got %d", n)
+	}
+	if _, banned := srv.rateLimiter.banned(flooder.ID, time.Now()); !banned {
+		t.Fatal("flooder not banned")
+	}
+	// Banned clients can't reconnect.
+	if msgErr := srv.handleClientConnect(flooderSender, mj.MustRequest(mj.RouteConnect, &mj.Connect{ID: flooder.ID})); msgErr == nil || msgErr.Code != mj.ErrBanned {
+		t.Fatalf("banned client not rejected on connect: %v", msgErr)
+	}
+}
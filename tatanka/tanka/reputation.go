@@ -4,11 +4,15 @@
 package tanka
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"decred.org/dcrdex/dex"
 	"decred.org/dcrdex/dex/encode"
 	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 )
 
 const (
@@ -24,6 +28,52 @@ type Reputation struct {
 	Depth uint64
 }
 
+// ScoreReport is a client's score for a peer. The report is signed by the
+// scorer so that it can be shared between tatanka nodes. For any scorer-scored
+// pair, only the report with the latest stamp is retained.
+type ScoreReport struct {
+	Scorer PeerID    `json:"scorer"`
+	Scored PeerID    `json:"scored"`
+	Score  int8      `json:"score"`
+	Stamp  time.Time `json:"stamp"`
+	Sig    dex.Bytes `json:"sig"`
+}
+
+func (r *ScoreReport) digest() [32]byte {
+	b := make([]byte, 0, PeerIDLength*2+1+8)
+	b = append(b, r.Scorer[:]...)
+	b = append(b, r.Scored[:]...)
+	b = append(b, byte(r.Score))
+	b = append(b, encode.Uint64Bytes(uint64(r.Stamp.UnixMilli()))...)
+	return blake256.Sum256(b)
+}
+
+// Sign signs the report with the scorer's private key.
+func (r *ScoreReport) Sign(priv *secp256k1.PrivateKey) {
+	h := r.digest()
+	r.Sig = ecdsa.Sign(priv, h[:]).Serialize()
+}
+
+// Verify checks that the report was signed by the scorer.
+func (r *ScoreReport) Verify() error {
+	if len(r.Sig) == 0 {
+		return errors.New("no signature")
+	}
+	pubKey, err := r.Scorer.PublicKey()
+	if err != nil {
+		return fmt.Errorf("bad scorer ID: %w", err)
+	}
+	sig, err := ecdsa.ParseDERSignature(r.Sig)
+	if err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+	h := r.digest()
+	if !sig.Verify(h[:], pubKey) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
 type Bond struct {
 	PeerID     PeerID    `json:"peerID"`
 	AssetID    uint32    `json:"assetID"`
@@ -43,4 +93,19 @@ func (bond *Bond) ID() ID32 {
 
 }
 
-type HTLCAudit struct{}
+// HTLCAudit describes the terms that a swap contract must satisfy.
+type HTLCAudit struct {
+	AssetID uint32 `json:"assetID"`
+	// CoinID is the ID of the HTLC output, or for account-based assets, the
+	// ID of the transaction that initiated the swap.
+	CoinID dex.Bytes `json:"coinID"`
+	// Contract is the asset-specific contract data. For UTXO-based assets,
+	// this is the swap contract script. For EVM assets, this is the contract
+	// locator.
+	Contract   dex.Bytes `json:"contract"`
+	Recipient  string    `json:"recipient"`
+	Value      uint64    `json:"value"`
+	SecretHash dex.Bytes `json:"secretHash"`
+	// LockTime is the earliest acceptable refund time of the contract.
+	LockTime time.Time `json:"lockTime"`
+}
@@ -10,9 +10,18 @@ import (
 	"fmt"
 	"time"
 
+	"decred.org/dcrdex/dex"
+	"decred.org/dcrdex/dex/encode"
 	"github.com/decred/dcrd/crypto/blake256"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
 )
 
+// OrderTTL is how long an order stays on the book after it was placed or last
+// refreshed. Order owners refresh their live orders with an OrderUpdate well
+// before the TTL expires.
+const OrderTTL = time.Minute * 10
+
 type Order struct {
 	From    PeerID `json:"from"`
 	BaseID  uint32 `json:"baseID"`
@@ -112,3 +121,66 @@ func (ou *OrderUpdate) ID() ID40 {
 	binary.BigEndian.PutUint64(b[32:], ou.Nonce)
 	return b
 }
+
+// OrderSnapshot is a peer's list of its own live orders on a market. A peer
+// sends a snapshot to a new market subscriber, who would otherwise only see
+// orders placed after they subscribed. The snapshot is signed by the peer.
+// The Qty of each order is the quantity still available, and the Stamp is the
+// time the order was last refreshed.
+type OrderSnapshot struct {
+	From    PeerID    `json:"from"`
+	BaseID  uint32    `json:"baseID"`
+	QuoteID uint32    `json:"quoteID"`
+	Orders  []*Order  `json:"orders"`
+	Stamp   time.Time `json:"stamp"`
+	Sig     dex.Bytes `json:"sig"`
+}
+
+func (s *OrderSnapshot) digest() [32]byte {
+	const ordLen = 40 + 1 + 8 + 8 + 8 + 8
+	b := make([]byte, 0, PeerIDLength+4+4+8+len(s.Orders)*ordLen)
+	b = append(b, s.From[:]...)
+	b = append(b, encode.Uint32Bytes(s.BaseID)...)
+	b = append(b, encode.Uint32Bytes(s.QuoteID)...)
+	b = append(b, encode.Uint64Bytes(uint64(s.Stamp.UnixMilli()))...)
+	for _, ord := range s.Orders {
+		oid := ord.ID()
+		b = append(b, oid[:]...)
+		var sell byte
+		if ord.Sell {
+			sell = 1
+		}
+		b = append(b, sell)
+		b = append(b, encode.Uint64Bytes(ord.Qty)...)
+		b = append(b, encode.Uint64Bytes(ord.Rate)...)
+		b = append(b, encode.Uint64Bytes(ord.LotSize)...)
+		b = append(b, encode.Uint64Bytes(uint64(ord.Stamp.UnixMilli()))...)
+	}
+	return blake256.Sum256(b)
+}
+
+// Sign signs the snapshot with the peer's private key.
+func (s *OrderSnapshot) Sign(priv *secp256k1.PrivateKey) {
+	h := s.digest()
+	s.Sig = ecdsa.Sign(priv, h[:]).Serialize()
+}
+
+// Verify checks that the snapshot was signed by the peer.
+func (s *OrderSnapshot) Verify() error {
+	if len(s.Sig) == 0 {
+		return errors.New("no signature")
+	}
+	pubKey, err := s.From.PublicKey()
+	if err != nil {
+		return fmt.Errorf("bad peer ID: %w", err)
+	}
+	sig, err := ecdsa.ParseDERSignature(s.Sig)
+	if err != nil {
+		return fmt.Errorf("error parsing signature: %w", err)
+	}
+	h := s.digest()
+	if !sig.Verify(h[:], pubKey) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
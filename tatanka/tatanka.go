@@ -120,6 +120,8 @@ type Tatanka struct {
 	relayMtx     sync.Mutex
 	recentRelays map[[32]byte]time.Time
 
+	rateLimiter *rateLimiter
+
 	clientMtx sync.RWMutex
 	clients   map[tanka.PeerID]*client
 	topics    map[tanka.Topic]*Topic
@@ -230,6 +232,7 @@ func New(cfg *Config) (*Tatanka, error) {
 		remoteClients:   make(map[tanka.PeerID]map[tanka.PeerID]struct{}),
 		topics:          make(map[tanka.Topic]*Topic),
 		recentRelays:    make(map[[32]byte]time.Time),
+		rateLimiter:     newRateLimiter(chainCfg.RateLimits),
 		clientJobs:      make(chan *clientJob, 128),
 		clientHandlers:  make(map[string]any),
 		tatankaHandlers: make(map[string]any),
@@ -302,6 +305,8 @@ func (t *Tatanka) prepareHandlers() {
 		mj.RouteRelayTankagram:   t.handleRelayedTankagram,
 		mj.RoutePathInquiry:      t.handlePathInquiry,
 		mj.RouteShareScore:       t.handleShareScore,
+		mj.RouteShareBonds:       t.handleShareBonds,
+		mj.RouteGossipSync:       t.handleGossipSync,
 	} {
 		registerTatankaHandler(route, handler)
 	}
@@ -321,6 +326,7 @@ func (t *Tatanka) prepareHandlers() {
 		mj.RouteBroadcast: t.handleBroadcast,
 		mj.RouteTankagram: t.handleTankagram,
 		mj.RouteSetScore:  t.handleSetScore,
+		mj.RouteAuditHTLC: t.handleAuditHTLC,
 	} {
 		registerClientHandler(route, handler)
 	}
@@ -395,7 +401,8 @@ func (t *Tatanka) Connect(ctx context.Context) (_ *sync.WaitGroup, err error) {
 		wg.Done()
 	}()
 
-	// Start a ticker to clean up the recent relays map.
+	// Start a ticker to clean up the recent relays map and the broadcast
+	// quotas.
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -410,6 +417,7 @@ func (t *Tatanka) Connect(ctx context.Context) (_ *sync.WaitGroup, err error) {
 					}
 				}
 				t.relayMtx.Unlock()
+				t.rateLimiter.prune(time.Now())
 			case <-ctx.Done():
 				return
 			}
@@ -540,7 +548,17 @@ func (t *Tatanka) runWhitelistLoop(ctx context.Context) {
 
 			cfgMsg := mj.MustRequest(mj.RouteTatankaConnect, t.generateConfig(bondTier))
 			if err := t.request(cl, cfgMsg, func(msg *msgjson.Message) {
-				// Nothing to do. The only non-error result is payload = true.
+				// The only non-error result is payload = true.
+				var ok bool
+				if err := msg.UnmarshalResult(&ok); err != nil || !ok {
+					t.log.Errorf("Boot node %s did not accept connection: %v", n.peerID, err)
+					return
+				}
+				t.wg.Add(1)
+				go func() {
+					defer t.wg.Done()
+					t.syncGossip(tt)
+				}()
 			}); err != nil {
 				t.log.Errorf("Error sending connect message: %w", err)
 				cl.Disconnect()
@@ -655,6 +673,9 @@ type ChainConfig struct {
 // ConfigFile represents the JSON Tatanka configuration file.
 type ConfigFile struct {
 	Chains []ChainConfig `json:"chains"`
+	// RateLimits configures the client broadcast quotas. Defaults are used if
+	// not specified.
+	RateLimits *RateLimitConfig `json:"rateLimits,omitempty"`
 }
 
 func loadConfig(configPath string) (*ConfigFile, error) {
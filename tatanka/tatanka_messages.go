@@ -90,6 +90,12 @@ func (t *Tatanka) handleInboundTatankaConnect(cl tanka.Sender, msg *msgjson.Mess
 
 	t.sendResult(cl, msg.ID, true)
 
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		t.syncGossip(tt)
+	}()
+
 	return nil
 }
 
@@ -284,30 +290,23 @@ func (t *Tatanka) handleRelayBroadcast(tt *remoteTatanka, msg *msgjson.Message)
 	}
 }
 
+// handleShareScore handles a score report shared by a remote tatanka node.
 func (t *Tatanka) handleShareScore(tt *remoteTatanka, msg *msgjson.Message) {
 	var ss mj.SharedScore
-	if err := msg.Unmarshal(&ss); err != nil {
-		t.log.Errorf("error unmarshaling shared score: %v", err)
-		return
-	}
-	if err := t.db.SetScore(ss.Scored, ss.Scorer, ss.Score, time.Now()); err != nil {
+	if err := msg.Unmarshal(&ss); err != nil || ss.Report == nil {
+		t.log.Errorf("error unmarshaling shared score from %s: %v", tt.ID, err)
 		return
 	}
-	t.clientMtx.RLock()
-	c, found := t.clients[ss.Scored]
-	t.clientMtx.RUnlock()
-	if !found {
+	if err := ss.Report.Verify(); err != nil {
+		t.log.Errorf("bad score report signature from %s shared by %s: %v", ss.Report.Scorer, tt.ID, err)
 		return
 	}
-
-	rep, err := t.db.Reputation(ss.Scored)
-	if err != nil {
-		t.log.Errorf("error updating reputation after shared score update: %v", err)
-		return
+	if ss.Reputation != nil {
+		if c := t.clientNode(ss.Report.Scored); c != nil {
+			c.mtx.Lock()
+			c.rrs[tt.ID] = ss.Reputation
+			c.mtx.Unlock()
+		}
 	}
-
-	c.mtx.Lock()
-	c.Reputation = rep
-	c.rrs[tt.ID] = ss.Reputation
-	c.mtx.Unlock()
+	t.acceptScoreReport(ss.Report, tt)
 }
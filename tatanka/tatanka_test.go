@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"decred.org/dcrdex/dex/encode"
 	"decred.org/dcrdex/dex/msgjson"
 	"decred.org/dcrdex/server/comms"
+	"decred.org/dcrdex/tatanka/chain"
 	"decred.org/dcrdex/tatanka/mj"
 	"decred.org/dcrdex/tatanka/tanka"
 	"github.com/decred/dcrd/dcrec/secp256k1/v4"
@@ -96,6 +98,7 @@ func tNewTatanka() *Tatanka {
 		remoteClients:   make(map[tanka.PeerID]map[tanka.PeerID]struct{}),
 		topics:          make(map[tanka.Topic]*Topic),
 		recentRelays:    make(map[[32]byte]time.Time),
+		rateLimiter:     newRateLimiter(nil),
 		clientJobs:      make(chan *clientJob, 128),
 		clientHandlers:  make(map[string]any),
 		tatankaHandlers: make(map[string]any),
@@ -223,3 +226,64 @@ func TestPrepareHandlers(t *testing.T) {
 	// Incorrect call signatures will cause a panic in prepareHandlers.
 	tNewTatanka().prepareHandlers()
 }
+
+type tChain struct{}
+
+func (c *tChain) Connect(context.Context) (*sync.WaitGroup, error) { return &sync.WaitGroup{}, nil }
+func (c *tChain) Connected() bool                                  { return true }
+func (c *tChain) CheckBond(*tanka.Bond) error                      { return nil }
+
+type tAuditorChain struct {
+	tChain
+	auditOK  bool
+	auditErr error
+}
+
+func (c *tAuditorChain) AuditHTLC(*tanka.HTLCAudit) (bool, error) {
+	return c.auditOK, c.auditErr
+}
+
+func TestHandleAuditHTLC(t *testing.T) {
+	tt := tNewTatanka()
+	auditor := &tAuditorChain{auditOK: true}
+	tt.chains = map[uint32]chain.Chain{
+		42: auditor,
+		0:  &tChain{},
+	}
+	c, s := tNewClient(1)
+
+	audit := func(assetID uint32) (bool, *msgjson.Error) {
+		t.Helper()
+		msgErr := tt.handleAuditHTLC(c, mj.MustRequest(mj.RouteAuditHTLC, &tanka.HTLCAudit{AssetID: assetID}))
+		if msgErr != nil {
+			return false, msgErr
+		}
+		resp := s.received()
+		if resp == nil {
+			t.Fatalf("no response sent")
+		}
+		var ok bool
+		if err := resp.UnmarshalResult(&ok); err != nil {
+			t.Fatalf("error unmarshaling result: %v", err)
+		}
+		return ok, nil
+	}
+
+	if ok, msgErr := audit(42); msgErr != nil || !ok {
+		t.Fatalf("expected passing audit, got %t, %v", ok, msgErr)
+	}
+	auditor.auditOK = false
+	if ok, msgErr := audit(42); msgErr != nil || ok {
+		t.Fatalf("expected failing audit, got %t, %v", ok, msgErr)
+	}
+	auditor.auditErr = errors.New("test error")
+	if _, msgErr := audit(42); msgErr == nil || msgErr.Code != mj.ErrInternal {
+		t.Fatalf("expected internal error, got %v", msgErr)
+	}
+	// Chains without an auditor, and unknown chains.
+	for _, assetID := range []uint32{0, 60} {
+		if _, msgErr := audit(assetID); msgErr == nil || msgErr.Code != mj.ErrBadRequest {
+			t.Fatalf("expected bad request for asset %d, got %v", assetID, msgErr)
+		}
+	}
+}